@@ -11,12 +11,14 @@ import (
 
 func ConnectPostgres(env *Env) *pgxpool.Pool {
 	dbpool, err := pgxpool.New(context.Background(), env.PostgresDSN)
-	log.Println("Using DSN:", env.PostgresDSN)
-
 	if err != nil {
 		log.Fatalf("Unable to connect to database: %v\n", err)
 	}
 
+	// The DSN carries the password, so only say where we connect.
+	conn := dbpool.Config().ConnConfig
+	log.Printf("Connecting to database %q on %s\n", conn.Database, conn.Host)
+
 	// Test the connection
 	if err := dbpool.Ping(context.Background()); err != nil {
 		log.Fatalf("Unable to ping database: %v\n", err)
@@ -22,9 +22,68 @@ type Env struct {
 	MetaWhatsAppAccessToken      string `mapstructure:"META_WHATSAPP_ACCESS_TOKEN"`
 	MetaWhatsAppPhoneNumberID    string `mapstructure:"META_WHATSAPP_PHONE_NUMBER_ID"`
 	MetaWhatsAppBusinessAccountID string `mapstructure:"META_WHATSAPP_BUSINESS_ACCOUNT_ID"`
+	// MetaWhatsAppAppSecret signs webhook deliveries (X-Hub-Signature-256) and
+	// MetaWhatsAppVerifyToken answers the subscription challenge; the webhook
+	// is only mounted when both are set.
+	MetaWhatsAppAppSecret   string `mapstructure:"META_WHATSAPP_APP_SECRET"`
+	MetaWhatsAppVerifyToken string `mapstructure:"META_WHATSAPP_VERIFY_TOKEN"`
+	// MetaWhatsAppGraphURL overrides the Graph API base URL.
+	MetaWhatsAppGraphURL string `mapstructure:"META_WHATSAPP_GRAPH_URL"`
 
 	RedisURL string `mapstructure:"REDIS_URL"`
 
+	// SMSProvider is "http" (gateway), "file" or "log" (local stand-ins);
+	// empty disables SMS.
+	SMSProvider      string `mapstructure:"SMS_PROVIDER"`
+	SMSGatewayURL    string `mapstructure:"SMS_GATEWAY_URL"`
+	SMSGatewayAPIKey string `mapstructure:"SMS_GATEWAY_API_KEY"`
+	SMSSenderID      string `mapstructure:"SMS_SENDER_ID"`
+	SMSOutboxFile    string `mapstructure:"SMS_OUTBOX_FILE"`
+
+	// NotificationFallbackOrder is a comma separated channel list, e.g.
+	// "whatsapp,telegram,sms".
+	NotificationFallbackOrder string `mapstructure:"NOTIFICATION_FALLBACK_ORDER"`
+
+	// DHIS2MappingFile maps classifications, age groups, sexes and
+	// facilities to the DHIS2 instance's UIDs; without it there is no DHIS2
+	// reporting. DHIS2URL enables pushing, and DHIS2PushDay (1-28) pushes
+	// the previous month automatically from that day of the month.
+	DHIS2MappingFile string `mapstructure:"DHIS2_MAPPING_FILE"`
+	DHIS2URL         string `mapstructure:"DHIS2_URL"`
+	DHIS2Username    string `mapstructure:"DHIS2_USERNAME"`
+	DHIS2Password    string `mapstructure:"DHIS2_PASSWORD"`
+	DHIS2PushDay     int    `mapstructure:"DHIS2_PUSH_DAY"`
+
+	// SurveillanceSignals is a comma separated list of classification names
+	// to watch for outbreaks; a classification matches when its name
+	// contains one, so MEASLES covers every measles classification. It
+	// defaults to DYSENTERY, MEASLES and SEVERE DEHYDRATION.
+	// SurveillanceMethod is the EARS method, c1, c2 (default) or c3, and a
+	// SurveillanceThreshold of 0 uses the method's usual threshold. Days
+	// with fewer than SurveillanceMinCases cases (default 3) never alert.
+	// Alerts go to the comma separated SurveillanceFocalPhones.
+	SurveillanceSignals     string  `mapstructure:"SURVEILLANCE_SIGNALS"`
+	SurveillanceMethod      string  `mapstructure:"SURVEILLANCE_METHOD"`
+	SurveillanceThreshold   float64 `mapstructure:"SURVEILLANCE_THRESHOLD"`
+	SurveillanceMinCases    int     `mapstructure:"SURVEILLANCE_MIN_CASES"`
+	SurveillanceFocalPhones string  `mapstructure:"SURVEILLANCE_FOCAL_PHONES"`
+
+	// ReviewCriteria is a comma separated list of what sends an assessment
+	// to supervisor review: emergency_classification,
+	// discordant_offline_result and trainee_clinician. It defaults to all.
+	ReviewCriteria string `mapstructure:"REVIEW_CRITERIA"`
+
+	// GrowthReferenceDir loads the WHO Anthro LMS reference files
+	// (weianthro.txt, lenanthro.txt, wflanthro.txt, wfhanthro.txt and
+	// acanthro.txt) from a directory instead of those built into the binary.
+	// Every file must be present.
+	GrowthReferenceDir string `mapstructure:"GROWTH_REFERENCE_DIR"`
+
+	// PDFFontPath is a TrueType font, e.g. NotoSansEthiopic-Regular.ttf,
+	// embedded in printouts for advice in Ge'ez script. Without it such
+	// advice is left off the printout.
+	PDFFontPath string `mapstructure:"PDF_FONT_PATH"`
+
 }
 
 func NewEnv() *Env {
@@ -44,4 +103,4 @@ func NewEnv() *Env {
 	}
 
 	return &env
-}
\ No newline at end of file
+}
\ No newline at end of file
@@ -2,6 +2,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
@@ -48,11 +49,15 @@ func (ac *AssessmentController) CreateAssessment(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		errorCode := "internal_error"
 
-		switch err {
-		case domain.ErrPatientNotFound:
+		switch {
+		case errors.Is(err, domain.ErrPatientNotFound):
 			statusCode = http.StatusNotFound
 			errorCode = "not_found"
-		case domain.ErrInvalidWeight, domain.ErrInvalidAgeForAssessment:
+		case errors.Is(err, domain.ErrInvalidWeight),
+			errors.Is(err, domain.ErrInvalidAgeForAssessment),
+			errors.Is(err, domain.ErrInvalidLength),
+			errors.Is(err, domain.ErrInvalidHeadCircumference),
+			errors.Is(err, domain.ErrInvalidMeasurementPosition):
 			statusCode = http.StatusBadRequest
 			errorCode = "validation_error"
 		}
@@ -219,12 +224,44 @@ func (ac *AssessmentController) UpdateAssessment(c *gin.Context) {
 
 	// Update fields
 	// Add your update logic here based on request
+	if request.LengthCm != nil {
+		assessment.LengthCm = request.LengthCm
+	}
+	if request.HeadCircumferenceCm != nil {
+		assessment.HeadCircumferenceCm = request.HeadCircumferenceCm
+	}
+	if request.MeasurementPosition != "" {
+		assessment.MeasurementPosition = request.MeasurementPosition
+	}
+	if request.Temperature != 0 {
+		temperature := request.Temperature
+		assessment.Temperature = &temperature
+	}
+	if request.RespiratoryRate != nil {
+		assessment.RespiratoryRate = request.RespiratoryRate
+	}
+	if request.OxygenSaturation != nil {
+		assessment.OxygenSaturation = request.OxygenSaturation
+	}
+	if request.HbLevel != nil {
+		assessment.HbLevel = request.HbLevel
+	}
 
 	if err := ac.AssessmentUsecase.UpdateAssessment(c.Request.Context(), assessment); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrInvalidLength) ||
+			errors.Is(err, domain.ErrInvalidHeadCircumference) ||
+			errors.Is(err, domain.ErrInvalidMeasurementPosition) {
+			statusCode = http.StatusBadRequest
+			errorCode = "validation_error"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
 			Error:   "Failed to update assessment",
 			Message: err.Error(),
-			Code:    "internal_error",
+			Code:    errorCode,
 		})
 		return
 	}
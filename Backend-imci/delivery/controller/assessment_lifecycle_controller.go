@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AssessmentLifecycleController struct {
+	LifecycleUsecase domain.AssessmentLifecycleUsecase
+}
+
+func NewAssessmentLifecycleController(lifecycleUsecase domain.AssessmentLifecycleUsecase) *AssessmentLifecycleController {
+	return &AssessmentLifecycleController{
+		LifecycleUsecase: lifecycleUsecase,
+	}
+}
+
+func (lc *AssessmentLifecycleController) GetLifecycle(c *gin.Context) {
+	assessmentID, mpID, role, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	lifecycle, err := lc.LifecycleUsecase.GetLifecycle(c.Request.Context(), assessmentID, mpID, role)
+	if err != nil {
+		respondLifecycleError(c, "Failed to get assessment lifecycle", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lifecycle": lifecycle,
+	})
+}
+
+func (lc *AssessmentLifecycleController) Complete(c *gin.Context) {
+	assessmentID, mpID, role, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	lifecycle, err := lc.LifecycleUsecase.Complete(c.Request.Context(), assessmentID, mpID, role)
+	if err != nil {
+		respondLifecycleError(c, "Failed to complete assessment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Assessment completed successfully",
+		"lifecycle": lifecycle,
+	})
+}
+
+func (lc *AssessmentLifecycleController) Cancel(c *gin.Context) {
+	assessmentID, mpID, role, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CancelAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	lifecycle, err := lc.LifecycleUsecase.Cancel(c.Request.Context(), assessmentID, mpID, role, req.Reason)
+	if err != nil {
+		respondLifecycleError(c, "Failed to cancel assessment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Assessment cancelled successfully",
+		"lifecycle": lifecycle,
+	})
+}
+
+func lifecycleRequestContext(c *gin.Context) (uuid.UUID, uuid.UUID, string, bool) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return assessmentID, mpID, roleStr, true
+}
+
+func respondLifecycleError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	errorCode := "internal_error"
+
+	var incomplete *domain.IncompleteTreesError
+	switch {
+	case errors.Is(err, domain.ErrAssessmentNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "not_found"
+	case errors.Is(err, domain.ErrCancellationReasonRequired):
+		statusCode = http.StatusBadRequest
+		errorCode = "validation_error"
+	case errors.Is(err, domain.ErrTransitionNotPermitted):
+		statusCode = http.StatusForbidden
+		errorCode = "transition_not_permitted"
+	case errors.Is(err, domain.ErrIllegalTransition):
+		statusCode = http.StatusConflict
+		errorCode = "illegal_transition"
+	case errors.Is(err, domain.ErrAssessmentStatusChanged):
+		statusCode = http.StatusConflict
+		errorCode = "status_changed"
+	case errors.As(err, &incomplete):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         message,
+			"message":       err.Error(),
+			"code":          "mandatory_trees_incomplete",
+			"missing_trees": incomplete.Missing,
+		})
+		return
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    errorCode,
+	})
+}
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type AssessmentReviewController struct {
+	ReviewUsecase domain.AssessmentReviewUsecase
+}
+
+func NewAssessmentReviewController(reviewUsecase domain.AssessmentReviewUsecase) *AssessmentReviewController {
+	return &AssessmentReviewController{
+		ReviewUsecase: reviewUsecase,
+	}
+}
+
+// GetReview shows the review status of an assessment to its clinician and
+// to the supervisors who may review it.
+func (rc *AssessmentReviewController) GetReview(c *gin.Context) {
+	assessmentID, mpID, role, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	review, err := rc.ReviewUsecase.GetReview(c.Request.Context(), assessmentID, mpID, role)
+	if err != nil {
+		respondReviewError(c, "Failed to get assessment review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"review": review,
+	})
+}
+
+// ReportOfflineResult takes the classifications an offline device reached
+// so they can be compared with the server's once the assessment syncs.
+func (rc *AssessmentReviewController) ReportOfflineResult(c *gin.Context) {
+	assessmentID, mpID, _, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.OfflineResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	review, err := rc.ReviewUsecase.ReportOfflineResult(c.Request.Context(), assessmentID, mpID, req.Classifications)
+	if err != nil {
+		respondReviewError(c, "Failed to record offline result", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Offline result recorded successfully",
+		"review":  review,
+	})
+}
+
+func (rc *AssessmentReviewController) ListQueue(c *gin.Context) {
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	reviews, err := rc.ReviewUsecase.ListQueue(c.Request.Context(), mpID, roleStr, domain.ReviewStatus(c.Query("status")), limit)
+	if err != nil {
+		respondReviewError(c, "Failed to list review queue", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+	})
+}
+
+func (rc *AssessmentReviewController) Approve(c *gin.Context) {
+	assessmentID, mpID, role, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ApproveReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request",
+				Message: err.Error(),
+				Code:    "validation_error",
+			})
+			return
+		}
+	}
+
+	review, err := rc.ReviewUsecase.Approve(c.Request.Context(), assessmentID, mpID, role, req.Comment)
+	if err != nil {
+		respondReviewError(c, "Failed to approve assessment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Assessment approved successfully",
+		"review":  review,
+	})
+}
+
+func (rc *AssessmentReviewController) Amend(c *gin.Context) {
+	assessmentID, mpID, role, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.AmendReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	review, err := rc.ReviewUsecase.Amend(c.Request.Context(), assessmentID, mpID, role, &req)
+	if err != nil {
+		respondReviewError(c, "Failed to amend assessment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Assessment amended successfully",
+		"review":  review,
+	})
+}
+
+func (rc *AssessmentReviewController) Comment(c *gin.Context) {
+	assessmentID, mpID, role, ok := lifecycleRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ReviewCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	review, err := rc.ReviewUsecase.Comment(c.Request.Context(), assessmentID, mpID, role, req.Comment)
+	if err != nil {
+		respondReviewError(c, "Failed to comment on assessment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Review comment recorded successfully",
+		"review":  review,
+	})
+}
+
+func (rc *AssessmentReviewController) SetTrainee(c *gin.Context) {
+	mpID, ok := parseCounselingParam(c, "id", "medical professional")
+	if !ok {
+		return
+	}
+
+	var req domain.SetTraineeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	if err := rc.ReviewUsecase.SetTrainee(c.Request.Context(), mpID, req.IsTrainee); err != nil {
+		respondReviewError(c, "Failed to update trainee status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Trainee status updated successfully",
+		"is_trainee": req.IsTrainee,
+	})
+}
+
+func respondReviewError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	errorCode := "internal_error"
+
+	switch {
+	case errors.Is(err, domain.ErrAssessmentNotFound),
+		errors.Is(err, domain.ErrReviewNotFound),
+		errors.Is(err, domain.ErrUserNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "not_found"
+	case errors.Is(err, domain.ErrReviewCommentRequired),
+		errors.Is(err, domain.ErrEmptyAmendment):
+		statusCode = http.StatusBadRequest
+		errorCode = "validation_error"
+	case errors.Is(err, domain.ErrSelfReview):
+		statusCode = http.StatusForbidden
+		errorCode = "self_review"
+	case errors.Is(err, domain.ErrNoFacility):
+		statusCode = http.StatusForbidden
+		errorCode = "no_facility"
+	case errors.Is(err, domain.ErrReviewClosed):
+		statusCode = http.StatusConflict
+		errorCode = "review_closed"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    errorCode,
+	})
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditController struct {
+	AuditUsecase domain.AuditUsecase
+}
+
+func NewAuditController(auditUsecase domain.AuditUsecase) *AuditController {
+	return &AuditController{
+		AuditUsecase: auditUsecase,
+	}
+}
+
+// List returns audit entries, newest first. from and to are whole UTC days,
+// to inclusive; before_seq pages to older entries.
+func (ac *AuditController) List(c *gin.Context) {
+	filter := domain.AuditFilter{
+		EntityType: domain.AuditEntity(c.Query("entity_type")),
+		Action:     domain.AuditAction(c.Query("action")),
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter.BeforeSeq, _ = strconv.ParseInt(c.Query("before_seq"), 10, 64)
+
+	for param, target := range map[string]**uuid.UUID{"entity_id": &filter.EntityID, "actor_id": &filter.ActorID} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid " + param,
+				Message: param + " must be a valid UUID",
+				Code:    "validation_error",
+			})
+			return
+		}
+		*target = &id
+	}
+
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: param + " must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return
+		}
+		if param == "to" {
+			// Include the whole of the last day.
+			day = day.AddDate(0, 0, 1)
+		}
+		*target = &day
+	}
+
+	entries, err := ac.AuditUsecase.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to list audit log",
+			Message: err.Error(),
+			Code:    "internal_error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+	})
+}
+
+// Verify re-hashes the audit chain and reports the first tampered entry.
+func (ac *AuditController) Verify(c *gin.Context) {
+	result, err := ac.AuditUsecase.Verify(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to verify audit log",
+			Message: err.Error(),
+			Code:    "internal_error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"verification": result,
+	})
+}
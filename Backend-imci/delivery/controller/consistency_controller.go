@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type ConsistencyController struct {
+	ConsistencyUsecase domain.ConsistencyUsecase
+}
+
+func NewConsistencyController(consistencyUsecase domain.ConsistencyUsecase) *ConsistencyController {
+	return &ConsistencyController{
+		ConsistencyUsecase: consistencyUsecase,
+	}
+}
+
+// Findings lists the contradictions and escalations found across the trees
+// of an assessment.
+func (cc *ConsistencyController) Findings(c *gin.Context) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+
+	findings, err := cc.ConsistencyUsecase.Findings(c.Request.Context(), assessmentID, mpID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+		if errors.Is(err, domain.ErrAssessmentNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to list consistency findings",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"findings": findings,
+	})
+}
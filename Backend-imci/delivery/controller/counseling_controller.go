@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Completion reports cover the last 30 days unless from/to are given.
+const defaultCompletionWindow = 30 * 24 * time.Hour
+
+type CounselingController struct {
+	CounselingUsecase domain.CounselingUsecase
+}
+
+func NewCounselingController(counselingUsecase domain.CounselingUsecase) *CounselingController {
+	return &CounselingController{
+		CounselingUsecase: counselingUsecase,
+	}
+}
+
+func (cc *CounselingController) GetSession(c *gin.Context) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+
+	session, err := cc.CounselingUsecase.GetSession(c.Request.Context(), assessmentID, mpID)
+	if err != nil {
+		respondCounselingError(c, "Failed to get counseling session", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"counseling": session,
+	})
+}
+
+func (cc *CounselingController) ConfirmItem(c *gin.Context) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return
+	}
+	itemID, ok := parseCounselingParam(c, "itemId", "counseling item")
+	if !ok {
+		return
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ConfirmCounselingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	item, err := cc.CounselingUsecase.ConfirmItem(c.Request.Context(), assessmentID, itemID, &req, mpID)
+	if err != nil {
+		respondCounselingError(c, "Failed to confirm counseling item", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Caregiver understanding recorded successfully",
+		"item":    item,
+	})
+}
+
+func (cc *CounselingController) RecordQuestion(c *gin.Context) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return
+	}
+	itemID, ok := parseCounselingParam(c, "itemId", "counseling item")
+	if !ok {
+		return
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CaregiverQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	item, err := cc.CounselingUsecase.RecordQuestion(c.Request.Context(), assessmentID, itemID, &req, mpID)
+	if err != nil {
+		respondCounselingError(c, "Failed to record caregiver question", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Caregiver question recorded successfully",
+		"item":    item,
+	})
+}
+
+// GetMyCompletion reports counselling completion for the signed-in
+// clinician.
+func (cc *CounselingController) GetMyCompletion(c *gin.Context) {
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+	cc.completionReport(c, &mpID)
+}
+
+// GetCompletion reports counselling completion for every clinician.
+func (cc *CounselingController) GetCompletion(c *gin.Context) {
+	cc.completionReport(c, nil)
+}
+
+func (cc *CounselingController) completionReport(c *gin.Context, mpID *uuid.UUID) {
+	to := time.Now()
+	from := to.Add(-defaultCompletionWindow)
+
+	var err error
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: "from must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: "to must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return
+		}
+		// Include the whole of the last day.
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid date range",
+			Message: "from must be before to",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	report, err := cc.CounselingUsecase.CompletionReport(c.Request.Context(), from, to, mpID)
+	if err != nil {
+		respondCounselingError(c, "Failed to get counseling completion", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":       from,
+		"to":         to,
+		"clinicians": report,
+	})
+}
+
+func parseCounselingParam(c *gin.Context, param, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid " + name + " ID",
+			Message: "ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func counselingProfessionalID(c *gin.Context) (uuid.UUID, bool) {
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return uuid.Nil, false
+	}
+	return medicalProfessionalID.(uuid.UUID), true
+}
+
+func respondCounselingError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	errorCode := "internal_error"
+
+	switch {
+	case errors.Is(err, domain.ErrAssessmentNotFound), errors.Is(err, domain.ErrCounselingNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "not_found"
+	case errors.Is(err, domain.ErrCounselingNotConfirmable):
+		statusCode = http.StatusBadRequest
+		errorCode = "validation_error"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    errorCode,
+	})
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/dhis2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DHIS2Controller struct {
+	DHIS2Usecase domain.DHIS2Usecase
+}
+
+func NewDHIS2Controller(dhis2Usecase domain.DHIS2Usecase) *DHIS2Controller {
+	return &DHIS2Controller{
+		DHIS2Usecase: dhis2Usecase,
+	}
+}
+
+// GetReport previews the aggregation and the dataValueSets payload for the
+// period query parameter (YYYYMM, YYYYQn or YYYY).
+func (dc *DHIS2Controller) GetReport(c *gin.Context) {
+	report, err := dc.DHIS2Usecase.BuildReport(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		respondDHIS2Error(c, "Failed to build DHIS2 report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report": report,
+	})
+}
+
+// GetDataValueSet returns only the payload, ready to import into DHIS2.
+func (dc *DHIS2Controller) GetDataValueSet(c *gin.Context) {
+	report, err := dc.DHIS2Usecase.BuildReport(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		respondDHIS2Error(c, "Failed to build DHIS2 report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report.DataValueSet)
+}
+
+func (dc *DHIS2Controller) Push(c *gin.Context) {
+	var req domain.PushDHIS2Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	var triggeredBy *uuid.UUID
+	if id, ok := c.Get("medical_professional_id"); ok {
+		mpID := id.(uuid.UUID)
+		triggeredBy = &mpID
+	}
+
+	export, err := dc.DHIS2Usecase.Push(c.Request.Context(), req.Period, triggeredBy)
+	if err != nil {
+		respondDHIS2Error(c, "Failed to push to DHIS2", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Data values pushed to DHIS2",
+		"export":  export,
+	})
+}
+
+func (dc *DHIS2Controller) ListExports(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	exports, err := dc.DHIS2Usecase.ListExports(c.Request.Context(), limit)
+	if err != nil {
+		respondDHIS2Error(c, "Failed to list DHIS2 exports", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exports": exports,
+	})
+}
+
+func respondDHIS2Error(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	errorCode := "internal_error"
+
+	switch {
+	case errors.Is(err, dhis2.ErrInvalidPeriod):
+		statusCode = http.StatusBadRequest
+		errorCode = "validation_error"
+	case errors.Is(err, domain.ErrDHIS2PushFailed):
+		// The failed attempt is kept in the export log.
+		statusCode = http.StatusBadGateway
+		errorCode = "dhis2_push_failed"
+	case errors.Is(err, domain.ErrDHIS2NotConfigured):
+		statusCode = http.StatusServiceUnavailable
+		errorCode = "not_configured"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    errorCode,
+	})
+}
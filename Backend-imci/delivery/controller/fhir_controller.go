@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/fhir"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FHIR endpoints answer with OperationOutcome resources instead of
+// ErrorResponse, as FHIR clients expect.
+type FHIRController struct {
+	FHIRExportUsecase domain.FHIRExportUsecase
+}
+
+func NewFHIRController(fhirExportUsecase domain.FHIRExportUsecase) *FHIRController {
+	return &FHIRController{
+		FHIRExportUsecase: fhirExportUsecase,
+	}
+}
+
+type operationOutcome struct {
+	ResourceType string                  `json:"resourceType"`
+	Issue        []operationOutcomeIssue `json:"issue"`
+}
+
+type operationOutcomeIssue struct {
+	Severity    string `json:"severity"`
+	Code        string `json:"code"`
+	Diagnostics string `json:"diagnostics"`
+}
+
+func (fc *FHIRController) PatientEverything(c *gin.Context) {
+	fc.everything(c, fc.FHIRExportUsecase.PatientEverything)
+}
+
+func (fc *FHIRController) EncounterEverything(c *gin.Context) {
+	fc.everything(c, fc.FHIRExportUsecase.EncounterEverything)
+}
+
+type everythingFunc func(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID, baseURL string) ([]byte, error)
+
+func (fc *FHIRController) everything(c *gin.Context, export everythingFunc) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondOperationOutcome(c, http.StatusBadRequest, "invalid", "Resource ID must be a valid UUID")
+		return
+	}
+
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		respondOperationOutcome(c, http.StatusUnauthorized, "login", "Medical professional ID not found")
+		return
+	}
+
+	bundle, err := export(c.Request.Context(), id, medicalProfessionalID.(uuid.UUID), fhirBaseURL(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrPatientNotFound) || errors.Is(err, domain.ErrAssessmentNotFound) {
+			respondOperationOutcome(c, http.StatusNotFound, "not-found", err.Error())
+			return
+		}
+		respondOperationOutcome(c, http.StatusInternalServerError, "exception", err.Error())
+		return
+	}
+
+	c.Data(http.StatusOK, fhir.ContentType, bundle)
+}
+
+// fhirBaseURL is the absolute URL of the FHIR endpoint the request came in
+// on, honouring the proxy's scheme.
+func fhirBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := c.GetHeader("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	path := c.FullPath()
+	if i := strings.Index(path, "/fhir/"); i >= 0 {
+		path = path[:i+len("/fhir")]
+	}
+	return scheme + "://" + c.Request.Host + path
+}
+
+func respondOperationOutcome(c *gin.Context, status int, code, diagnostics string) {
+	body, _ := json.Marshal(operationOutcome{
+		ResourceType: "OperationOutcome",
+		Issue:        []operationOutcomeIssue{{Severity: "error", Code: code, Diagnostics: diagnostics}},
+	})
+	c.Data(status, fhir.ContentType, body)
+}
@@ -0,0 +1,116 @@
+// delivery/controller/growth_controller.go
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GrowthController struct {
+	GrowthUsecase domain.GrowthUsecase
+}
+
+func NewGrowthController(growthUsecase domain.GrowthUsecase) *GrowthController {
+	return &GrowthController{
+		GrowthUsecase: growthUsecase,
+	}
+}
+
+func (gc *GrowthController) GetAssessmentGrowth(c *gin.Context) {
+	assessmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid assessment ID",
+			Message: "Assessment ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	mpID := medicalProfessionalID.(uuid.UUID)
+
+	point, err := gc.GrowthUsecase.ComputeForAssessment(c.Request.Context(), assessmentID, mpID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		switch err {
+		case domain.ErrAssessmentNotFound, domain.ErrPatientNotFound:
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		case domain.ErrGrowthNotComputable:
+			statusCode = http.StatusUnprocessableEntity
+			errorCode = "growth_not_computable"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to compute growth z-scores",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"growth": point,
+	})
+}
+
+func (gc *GrowthController) GetPatientGrowthHistory(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	mpID := medicalProfessionalID.(uuid.UUID)
+
+	history, err := gc.GrowthUsecase.GetPatientGrowthHistory(c.Request.Context(), patientID, mpID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if err == domain.ErrPatientNotFound {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to get growth history",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"growth_history": history,
+	})
+}
@@ -0,0 +1,212 @@
+// delivery/controller/immunization_controller.go
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImmunizationController struct {
+	ImmunizationUsecase domain.ImmunizationUsecase
+}
+
+func NewImmunizationController(immunizationUsecase domain.ImmunizationUsecase) *ImmunizationController {
+	return &ImmunizationController{
+		ImmunizationUsecase: immunizationUsecase,
+	}
+}
+
+func (ic *ImmunizationController) RecordDose(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	mpID := medicalProfessionalID.(uuid.UUID)
+
+	var req domain.RecordImmunizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	record, err := ic.ImmunizationUsecase.RecordDose(c.Request.Context(), patientID, &req, mpID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		switch {
+		case errors.Is(err, domain.ErrPatientNotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		case errors.Is(err, domain.ErrUnknownVaccine), errors.Is(err, domain.ErrInvalidImmunizationDate):
+			statusCode = http.StatusBadRequest
+			errorCode = "validation_error"
+		case errors.Is(err, domain.ErrVaccineAlreadyRecorded):
+			statusCode = http.StatusConflict
+			errorCode = "already_recorded"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to record immunization",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Immunization recorded successfully",
+		"immunization": record,
+	})
+}
+
+func (ic *ImmunizationController) GetRecords(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	records, err := ic.ImmunizationUsecase.GetRecords(c.Request.Context(), patientID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrPatientNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to get immunizations",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"immunizations": records,
+	})
+}
+
+func (ic *ImmunizationController) DeleteRecord(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	recordID, err := uuid.Parse(c.Param("recordId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid immunization ID",
+			Message: "Immunization ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	if err := ic.ImmunizationUsecase.DeleteRecord(c.Request.Context(), patientID, recordID); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrImmunizationNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to delete immunization",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Immunization deleted successfully",
+	})
+}
+
+// GetSchedule returns the EPI schedule for a patient. The optional as_of
+// query parameter (YYYY-MM-DD) evaluates the schedule on another day.
+func (ic *ImmunizationController) GetSchedule(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	asOf := time.Now()
+	if raw := c.Query("as_of"); raw != "" {
+		asOf, err = time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: "as_of must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return
+		}
+	}
+
+	schedule, err := ic.ImmunizationUsecase.GetSchedule(c.Request.Context(), patientID, asOf)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrPatientNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to compute immunization schedule",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedule": schedule,
+	})
+}
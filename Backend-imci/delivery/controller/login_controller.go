@@ -2,10 +2,14 @@
 package controller
 
 import (
+    "errors"
+    "math"
     "net/http"
+    "strconv"
 
     "github.com/Afomiat/Digital-IMCI/domain"
     "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
 )
 
 type LoginController struct {
@@ -26,8 +30,14 @@ func (lc *LoginController) Login(c *gin.Context) {
         return
     }
 
-    response, err := lc.LoginUsecase.Login(c.Request.Context(), &request)
+    response, err := lc.LoginUsecase.Login(c.Request.Context(), &request, sessionMetadata(c))
     if err != nil {
+        var locked *domain.LoginLockedError
+        if errors.As(err, &locked) {
+            c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+            c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+            return
+        }
         c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
         return
     }
@@ -47,7 +57,7 @@ func (lc *LoginController) RefreshToken(c *gin.Context) {
         return
     }
 
-    response, err := lc.LoginUsecase.RefreshToken(c.Request.Context(), request.RefreshToken)
+    response, err := lc.LoginUsecase.RefreshToken(c.Request.Context(), request.RefreshToken, sessionMetadata(c))
     if err != nil {
         c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
         return
@@ -57,4 +67,166 @@ func (lc *LoginController) RefreshToken(c *gin.Context) {
         "message": "Token refreshed successfully",
         "data":    response,
     })
-}
\ No newline at end of file
+}
+func sessionMetadata(c *gin.Context) domain.SessionMetadata {
+	return domain.SessionMetadata{
+		Device:    c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// currentSession returns the IDs of the authenticated user and the session
+// their access token belongs to.
+func currentSession(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	sessionID, _ := c.Get("session_id")
+	sid, _ := sessionID.(uuid.UUID)
+
+	return medicalProfessionalID.(uuid.UUID), sid, true
+}
+
+func (lc *LoginController) ListSessions(c *gin.Context) {
+	mpID, sessionID, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	sessions, err := lc.LoginUsecase.ListSessions(c.Request.Context(), mpID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to list sessions",
+			Message: err.Error(),
+			Code:    "internal_error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+	})
+}
+
+func (lc *LoginController) RevokeSession(c *gin.Context) {
+	mpID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid session ID",
+			Message: "Session ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	if err := lc.LoginUsecase.RevokeSession(c.Request.Context(), mpID, sessionID); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to revoke session",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked successfully",
+	})
+}
+
+// RevokeOtherSessions signs the user out of every session except the one
+// making the request.
+func (lc *LoginController) RevokeOtherSessions(c *gin.Context) {
+	mpID, sessionID, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	if err := lc.LoginUsecase.RevokeOtherSessions(c.Request.Context(), mpID, sessionID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to revoke sessions",
+			Message: err.Error(),
+			Code:    "internal_error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Other sessions revoked successfully",
+	})
+}
+
+// UnlockLogin lets an admin clear a phone number's failed-login lockout.
+func (lc *LoginController) UnlockLogin(c *gin.Context) {
+	adminID, _, ok := currentSession(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	if err := lc.LoginUsecase.UnlockLogin(c.Request.Context(), c.Param("phone"), adminID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Failed to unlock login",
+			Message: err.Error(),
+			Code:    "unlock_failed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login unlocked successfully",
+	})
+}
+
+// ListLoginAudit returns recent failed logins, lockouts and unlocks,
+// optionally filtered by the phone query parameter.
+func (lc *LoginController) ListLoginAudit(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	entries, err := lc.LoginUsecase.ListLoginAudit(c.Request.Context(), c.Query("phone"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Failed to list login audit",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+	})
+}
@@ -0,0 +1,90 @@
+// delivery/controller/notification_controller.go
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationController struct {
+	NotificationUsecase domain.NotificationUsecase
+}
+
+func NewNotificationController(notificationUsecase domain.NotificationUsecase) *NotificationController {
+	return &NotificationController{
+		NotificationUsecase: notificationUsecase,
+	}
+}
+
+func (nc *NotificationController) ListChannels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"channels": nc.NotificationUsecase.AvailableChannels(),
+	})
+}
+
+func (nc *NotificationController) SetPreferredChannel(c *gin.Context) {
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	var req domain.SetNotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	if err := nc.NotificationUsecase.SetPreferredChannel(c.Request.Context(), medicalProfessionalID.(uuid.UUID), req.Channel, req.Language); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrUnknownNotificationChannel) {
+			statusCode = http.StatusBadRequest
+			errorCode = "validation_error"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to update notification channel",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification channel updated successfully",
+		"channel": req.Channel,
+	})
+}
+
+func (nc *NotificationController) ListDeliveries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	deliveries, err := nc.NotificationUsecase.ListDeliveries(c.Request.Context(), c.Query("phone"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Failed to list notification deliveries",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+	})
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OutbreakController struct {
+	OutbreakUsecase domain.OutbreakUsecase
+}
+
+func NewOutbreakController(outbreakUsecase domain.OutbreakUsecase) *OutbreakController {
+	return &OutbreakController{
+		OutbreakUsecase: outbreakUsecase,
+	}
+}
+
+// ListAlerts lists alerts, newest first, optionally filtered by status.
+func (oc *OutbreakController) ListAlerts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	alerts, err := oc.OutbreakUsecase.ListAlerts(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		respondOutbreakError(c, "Failed to list outbreak alerts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+	})
+}
+
+func (oc *OutbreakController) Acknowledge(c *gin.Context) {
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid alert ID",
+			Message: "ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	if err := oc.OutbreakUsecase.Acknowledge(c.Request.Context(), alertID, medicalProfessionalID.(uuid.UUID)); err != nil {
+		respondOutbreakError(c, "Failed to acknowledge outbreak alert", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Outbreak alert acknowledged",
+	})
+}
+
+// Detect runs detection for the date query parameter (YYYY-MM-DD), today by
+// default. Alerts already raised for that day are not sent again.
+func (oc *OutbreakController) Detect(c *gin.Context) {
+	day := time.Now().UTC()
+	if raw := c.Query("date"); raw != "" {
+		var err error
+		if day, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: "date must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return
+		}
+	}
+
+	alerts, err := oc.OutbreakUsecase.Detect(c.Request.Context(), day)
+	if err != nil {
+		respondOutbreakError(c, "Failed to run outbreak detection", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":   day.Format("2006-01-02"),
+		"alerts": alerts,
+	})
+}
+
+func respondOutbreakError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	errorCode := "internal_error"
+
+	if errors.Is(err, domain.ErrOutbreakAlertNotFound) {
+		statusCode = http.StatusNotFound
+		errorCode = "not_found"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    errorCode,
+	})
+}
@@ -28,14 +28,15 @@ func (c *PasswordResetController) ForgotPassword(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.PasswordResetUsecase.InitiatePasswordReset(ctx, req.Phone, req.UseWhatsApp); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	channel := req.Channel
+	if channel == "" && req.UseWhatsApp {
+		channel = domain.ChannelWhatsApp
 	}
 
-	method := "whatsapp"
-	if !req.UseWhatsApp {
-		method = "telegram"
+	method, err := c.PasswordResetUsecase.InitiatePasswordReset(ctx, req.Phone, channel)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
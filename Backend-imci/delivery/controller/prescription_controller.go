@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type PrescriptionController struct {
+	PrescriptionUsecase domain.PrescriptionUsecase
+}
+
+func NewPrescriptionController(prescriptionUsecase domain.PrescriptionUsecase) *PrescriptionController {
+	return &PrescriptionController{
+		PrescriptionUsecase: prescriptionUsecase,
+	}
+}
+
+// GetPrescription returns the prescription stored when the assessment was
+// completed or last consolidated.
+func (pc *PrescriptionController) GetPrescription(c *gin.Context) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+
+	prescription, err := pc.PrescriptionUsecase.Get(c.Request.Context(), assessmentID, mpID)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to get prescription", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prescription)
+}
+
+// Consolidate regenerates the prescription from the current treatment plans.
+func (pc *PrescriptionController) Consolidate(c *gin.Context) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+
+	prescription, err := pc.PrescriptionUsecase.Consolidate(c.Request.Context(), assessmentID, mpID)
+	if err != nil {
+		respondPrescriptionError(c, "Failed to consolidate prescription", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prescription)
+}
+
+func respondPrescriptionError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	errorCode := "internal_error"
+
+	switch {
+	case errors.Is(err, domain.ErrAssessmentNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "not_found"
+	case errors.Is(err, domain.ErrPrescriptionNotFound):
+		statusCode = http.StatusNotFound
+		errorCode = "prescription_not_found"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    errorCode,
+	})
+}
@@ -1,11 +1,12 @@
 package controller
 
 import (
-	"fmt"
-	"log"
+	"errors"
 	"net/http"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,20 +20,16 @@ import (
 type SignupController struct {
 	SignupUsecase      domain.SignupUsecase
 	TelegramController *TelegramController
-	WhatsAppController *WhatsAppController
 }
 
-func NewSignupController(signupUsecase domain.SignupUsecase, telegramController *TelegramController, whatsappController *WhatsAppController) *SignupController {
+func NewSignupController(signupUsecase domain.SignupUsecase, telegramController *TelegramController) *SignupController {
 	return &SignupController{
 		SignupUsecase:      signupUsecase,
 		TelegramController: telegramController,
-		WhatsAppController: whatsappController,
 	}
 }
 
 func (sc *SignupController) Signup(ctx *gin.Context) {
-	log.Println("1. Signup endpoint called")
-
 	var form domain.SignupForm
 
 	if err := ctx.ShouldBindJSON(&form); err != nil {
@@ -40,7 +37,8 @@ func (sc *SignupController) Signup(ctx *gin.Context) {
 		return
 	}
 
-	log.Println("2. Form parsed for phone:", form.Phone)
+	channel := domain.ResolveChannel(form.Channel, form.UseWhatsApp)
+	logger.Info("signup requested", "phone", form.Phone, "channel", channel)
 
 	existingProfessional, _ := sc.SignupUsecase.GetMedicalProfessionalByPhone(ctx.Request.Context(), form.Phone)
 	if existingProfessional != nil {
@@ -48,21 +46,39 @@ func (sc *SignupController) Signup(ctx *gin.Context) {
 		return
 	}
 
-	if form.UseWhatsApp {
-		if sc.WhatsAppController == nil {
-			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "WhatsApp signup is not available"})
-			return
-		}
-		sc.WhatsAppController.HandleSignup(ctx, &form)
+	// A new user has no linked Telegram chat to push to, so Telegram signups
+	// go through the bot: the user shares their contact and the bot replies
+	// with the pending code.
+	if channel == domain.ChannelTelegram && sc.TelegramController != nil && sc.TelegramController.TelegramService != nil {
+		sc.TelegramController.HandleSignup(ctx, &form)
 		return
 	}
 
-	if sc.TelegramController == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Telegram signup is not available"})
+	sc.sendSignupOTP(ctx, &form)
+}
+
+func (sc *SignupController) sendSignupOTP(ctx *gin.Context, form *domain.SignupForm) {
+	otp, err := sc.SignupUsecase.SendSignupOTP(ctx.Request.Context(), form)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrNoNotificationChannel) {
+			status = http.StatusServiceUnavailable
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	sc.TelegramController.HandleSignup(ctx, &form)
+	phoneForDisplay := userutil.FormatPhoneE164(form.Phone)
+	if phoneForDisplay == "" {
+		phoneForDisplay = form.Phone
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "OTP sent via " + otp.Channel,
+		"method":  otp.Channel,
+		"phone":   phoneForDisplay,
+		"expires": otp.ExpiresAt,
+	})
 }
 func (sc *SignupController) Verify(ctx *gin.Context) {
 	var otp domain.VerifyOtp
@@ -71,21 +87,7 @@ func (sc *SignupController) Verify(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	OtpResponse, err := sc.SignupUsecase.VerifyOtp(ctx.Request.Context(), &otp)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	user := domain.SignupForm{
-		FullName:     OtpResponse.FullName,
-		Phone:        OtpResponse.Phone,
-		Password:     OtpResponse.Password,
-		Role:         OtpResponse.Role,
-		FacilityName: OtpResponse.FacilityName,
-	}
-	fmt.Printf("**********Verified OTP for phone %s, full name: %s\n", OtpResponse.Role, OtpResponse.FacilityName)
-	professionalID, err := sc.SignupUsecase.RegisterMedicalProfessional(ctx.Request.Context(), &user)
+	professionalID, err := sc.SignupUsecase.CompleteSignup(ctx.Request.Context(), &otp)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Registration failed: " + err.Error()})
 		return
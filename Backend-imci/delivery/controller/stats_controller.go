@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Dashboard statistics cover the last 30 days unless from/to are given.
+const defaultStatsDays = 30
+
+type StatsController struct {
+	StatsUsecase domain.StatsUsecase
+}
+
+func NewStatsController(statsUsecase domain.StatsUsecase) *StatsController {
+	return &StatsController{
+		StatsUsecase: statsUsecase,
+	}
+}
+
+// GetStats reports on the clinician's facility. Admins may pass ?facility=
+// to pick one, and see every facility without it.
+func (sc *StatsController) GetStats(c *gin.Context) {
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+
+	from, to, ok := parseStatsRange(c)
+	if !ok {
+		return
+	}
+
+	stats, err := sc.StatsUsecase.GetFacilityStats(
+		c.Request.Context(),
+		medicalProfessionalID.(uuid.UUID),
+		roleStr == string(domain.AdminRole),
+		c.Query("facility"),
+		from,
+		to,
+	)
+	if err != nil {
+		respondStatsError(c, "Failed to get statistics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// ListFacilities lists the facilities an admin can pick on the dashboard.
+func (sc *StatsController) ListFacilities(c *gin.Context) {
+	from, to, ok := parseStatsRange(c)
+	if !ok {
+		return
+	}
+
+	facilities, err := sc.StatsUsecase.ListFacilities(c.Request.Context(), from, to)
+	if err != nil {
+		respondStatsError(c, "Failed to list facilities", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":       from,
+		"to":         to,
+		"facilities": facilities,
+	})
+}
+
+// parseStatsRange reads from/to as whole UTC days, to inclusive, and
+// returns them as [from, to).
+func parseStatsRange(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -defaultStatsDays)
+
+	var err error
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: "from must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: "to must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		// Include the whole of the last day.
+		to = to.AddDate(0, 0, 1)
+	}
+
+	return from, to, true
+}
+
+func respondStatsError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	errorCode := "internal_error"
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidStatsRange):
+		statusCode = http.StatusBadRequest
+		errorCode = "validation_error"
+	case errors.Is(err, domain.ErrNoFacility):
+		statusCode = http.StatusUnprocessableEntity
+		errorCode = "no_facility"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    errorCode,
+	})
+}
@@ -0,0 +1,209 @@
+// delivery/controller/supplement_controller.go
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SupplementController struct {
+	SupplementUsecase domain.SupplementUsecase
+}
+
+func NewSupplementController(supplementUsecase domain.SupplementUsecase) *SupplementController {
+	return &SupplementController{
+		SupplementUsecase: supplementUsecase,
+	}
+}
+
+func (ic *SupplementController) RecordDose(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	mpID := medicalProfessionalID.(uuid.UUID)
+
+	var req domain.RecordSupplementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	record, err := ic.SupplementUsecase.RecordDose(c.Request.Context(), patientID, &req, mpID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		switch {
+		case errors.Is(err, domain.ErrPatientNotFound):
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		case errors.Is(err, domain.ErrUnknownSupplement), errors.Is(err, domain.ErrInvalidSupplementDate):
+			statusCode = http.StatusBadRequest
+			errorCode = "validation_error"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to record supplement dose",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Supplement dose recorded successfully",
+		"supplement": record,
+	})
+}
+
+func (ic *SupplementController) GetRecords(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	records, err := ic.SupplementUsecase.GetRecords(c.Request.Context(), patientID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrPatientNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to get supplement doses",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"supplements": records,
+	})
+}
+
+func (ic *SupplementController) DeleteRecord(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	recordID, err := uuid.Parse(c.Param("recordId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid supplement ID",
+			Message: "Supplement ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	if err := ic.SupplementUsecase.DeleteRecord(c.Request.Context(), patientID, recordID); err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrSupplementNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to delete supplement dose",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Supplement dose deleted successfully",
+	})
+}
+
+// GetStatus returns Vitamin A and deworming eligibility for a patient. The
+// optional as_of query parameter (YYYY-MM-DD) evaluates it on another day.
+func (ic *SupplementController) GetStatus(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid patient ID",
+			Message: "Patient ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	asOf := time.Now()
+	if raw := c.Query("as_of"); raw != "" {
+		asOf, err = time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid date",
+				Message: "as_of must be in YYYY-MM-DD format",
+				Code:    "validation_error",
+			})
+			return
+		}
+	}
+
+	status, err := ic.SupplementUsecase.GetStatus(c.Request.Context(), patientID, asOf)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrPatientNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to compute supplement status",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+	})
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type VisitSummaryController struct {
+	VisitSummaryUsecase domain.VisitSummaryUsecase
+}
+
+func NewVisitSummaryController(visitSummaryUsecase domain.VisitSummaryUsecase) *VisitSummaryController {
+	return &VisitSummaryController{
+		VisitSummaryUsecase: visitSummaryUsecase,
+	}
+}
+
+// DownloadPDF returns the printable visit summary and mother's card.
+func (vc *VisitSummaryController) DownloadPDF(c *gin.Context) {
+	assessmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid assessment ID",
+			Message: "Assessment ID must be a valid UUID",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	medicalProfessionalID, exists := c.Get("medical_professional_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Medical professional ID not found",
+			Code:    "unauthorized",
+		})
+		return
+	}
+
+	document, err := vc.VisitSummaryUsecase.RenderPDF(c.Request.Context(), assessmentID, medicalProfessionalID.(uuid.UUID))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+
+		if errors.Is(err, domain.ErrAssessmentNotFound) || errors.Is(err, domain.ErrPatientNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to generate visit summary",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="visit-summary-%s.pdf"`, assessmentID))
+	c.Data(http.StatusOK, "application/pdf", document)
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type VitalAlertController struct {
+	VitalAlertUsecase domain.VitalAlertUsecase
+}
+
+func NewVitalAlertController(vitalAlertUsecase domain.VitalAlertUsecase) *VitalAlertController {
+	return &VitalAlertController{
+		VitalAlertUsecase: vitalAlertUsecase,
+	}
+}
+
+// History lists every alert an assessment has raised, including ones whose
+// vitals have since been corrected.
+func (vc *VitalAlertController) History(c *gin.Context) {
+	assessmentID, ok := parseCounselingParam(c, "id", "assessment")
+	if !ok {
+		return
+	}
+	mpID, ok := counselingProfessionalID(c)
+	if !ok {
+		return
+	}
+
+	alerts, err := vc.VitalAlertUsecase.History(c.Request.Context(), assessmentID, mpID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorCode := "internal_error"
+		if errors.Is(err, domain.ErrAssessmentNotFound) {
+			statusCode = http.StatusNotFound
+			errorCode = "not_found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error:   "Failed to list vital alerts",
+			Message: err.Error(),
+			Code:    errorCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+	})
+}
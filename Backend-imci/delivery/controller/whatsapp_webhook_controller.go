@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody caps the payload we are willing to read; Meta batches at
+// most a few kilobytes per delivery.
+const maxWebhookBody = 1 << 20
+
+type WhatsAppWebhookController struct {
+	WebhookUsecase domain.WhatsAppWebhookUsecase
+	AppSecret      string
+	VerifyToken    string
+}
+
+func NewWhatsAppWebhookController(webhookUsecase domain.WhatsAppWebhookUsecase, appSecret, verifyToken string) *WhatsAppWebhookController {
+	return &WhatsAppWebhookController{
+		WebhookUsecase: webhookUsecase,
+		AppSecret:      appSecret,
+		VerifyToken:    verifyToken,
+	}
+}
+
+// Verify answers the subscription handshake Meta performs when the webhook
+// URL is configured.
+func (wc *WhatsAppWebhookController) Verify(c *gin.Context) {
+	mode := c.Query("hub.mode")
+	token := c.Query("hub.verify_token")
+
+	if mode != "subscribe" || subtle.ConstantTimeCompare([]byte(token), []byte(wc.VerifyToken)) != 1 {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Webhook verification failed",
+			Code:    "forbidden",
+		})
+		return
+	}
+
+	c.String(http.StatusOK, c.Query("hub.challenge"))
+}
+
+// Receive accepts a webhook delivery. Meta retries anything that is not
+// acknowledged quickly, so the event is processed after responding; the
+// usecase drops redelivered messages and handles each sender's one at a
+// time.
+func (wc *WhatsAppWebhookController) Receive(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: "Failed to read request body",
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	if !service.VerifyWhatsAppSignature(wc.AppSecret, body, c.GetHeader("X-Hub-Signature-256")) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: domain.ErrInvalidWebhookSignature.Error(),
+			Code:    "invalid_signature",
+		})
+		return
+	}
+
+	event, err := service.ParseWhatsAppWebhook(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    "validation_error",
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+
+	go func() {
+		if err := wc.WebhookUsecase.HandleEvent(context.Background(), event); err != nil {
+			logger.Error("failed to handle whatsapp webhook", "error", err)
+		}
+	}()
+}
@@ -15,12 +15,14 @@ import (
 type AuthMiddleware struct {
 	env           *config.Env
 	blacklistRepo domain.TokenBlacklistRepository
+	sessions      domain.SessionChecker
 }
 
-func NewAuthMiddleware(env *config.Env, blacklistRepo domain.TokenBlacklistRepository) *AuthMiddleware {
+func NewAuthMiddleware(env *config.Env, blacklistRepo domain.TokenBlacklistRepository, sessions domain.SessionChecker) *AuthMiddleware {
 	return &AuthMiddleware{
 		env:           env,
 		blacklistRepo: blacklistRepo,
+		sessions:      sessions,
 	}
 }
 
@@ -85,10 +87,34 @@ func (am *AuthMiddleware) Handler() gin.HandlerFunc {
 			return
 		}
 
+		// Every access token belongs to a login session; once the session is
+		// revoked its access tokens stop working too.
+		sid, _ := claims["sid"].(string)
+		sessionID, err := uuid.Parse(sid)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid session in token"})
+			c.Abort()
+			return
+		}
+		if am.sessions != nil {
+			active, err := am.sessions.IsActive(c.Request.Context(), sessionID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+				c.Abort()
+				return
+			}
+			if !active {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+				c.Abort()
+				return
+			}
+		}
+
 		c.Set("medical_professional_id", medicalProfessionalID)
 		c.Set("phone", claims["phone"])
 		c.Set("role", claims["role"])
 		c.Set("token", tokenString)
+		c.Set("session_id", sessionID)
 
 		c.Next()
 	}
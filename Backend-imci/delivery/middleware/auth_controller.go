@@ -7,20 +7,23 @@ import (
 
 	"github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthController struct {
 	blacklistRepo domain.TokenBlacklistRepository
+	sessionRepo   domain.RefreshSessionRepository
 }
 
-func NewAuthController(blacklistRepo domain.TokenBlacklistRepository) *AuthController {
+func NewAuthController(blacklistRepo domain.TokenBlacklistRepository, sessionRepo domain.RefreshSessionRepository) *AuthController {
 	return &AuthController{
 		blacklistRepo: blacklistRepo,
+		sessionRepo:   sessionRepo,
 	}
 }
 
 func (ac *AuthController) Logout(ctx *gin.Context) {
-	userID, exists := ctx.Get("userID")
+	userID, exists := ctx.Get("medical_professional_id")
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -53,6 +56,19 @@ func (ac *AuthController) Logout(ctx *gin.Context) {
 		}
 	}
 
+	// End the refresh session too, so the refresh token cannot mint new
+	// access tokens after logout.
+	if sessionID, ok := ctx.Get("session_id"); ok && ac.sessionRepo != nil {
+		err := ac.sessionRepo.Revoke(ctx.Request.Context(), sessionID.(uuid.UUID), userID.(uuid.UUID), "logout")
+		if err != nil && err != domain.ErrSessionNotFound {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to end session",
+				"details": "Please try again or contact support if the issue persists",
+			})
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"message":      "Logged out successfully",
 		"details":      "Your session has been terminated and access token invalidated.",
@@ -0,0 +1,39 @@
+// middleware/request_meta.go
+package middleware
+
+import (
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestMeta puts who made the request, and from where, on the request
+// context for the audit log. It must run after the auth middleware.
+func RequestMeta() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Header("X-Request-ID", requestID)
+
+		meta := domain.RequestMeta{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			RequestID: requestID,
+		}
+		if id, ok := c.Get("medical_professional_id"); ok {
+			if actorID, ok := id.(uuid.UUID); ok {
+				meta.ActorID = &actorID
+			}
+		}
+		if role, ok := c.Get("role"); ok {
+			meta.ActorRole, _ = role.(string)
+		}
+
+		c.Request = c.Request.WithContext(domain.WithRequestMeta(c.Request.Context(), meta))
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+// middleware/role.go
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole only lets through users whose token carries one of roles. It
+// must run after the auth middleware.
+func RequireRole(roles ...domain.MedicalProfessionalRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		roleStr, _ := role.(string)
+
+		for _, allowed := range roles {
+			if roleStr == string(allowed) {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+	}
+}
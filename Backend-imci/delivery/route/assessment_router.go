@@ -3,15 +3,25 @@ package route
 
 import (
 	"log"
+	"os"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/growth"
+	"github.com/Afomiat/Digital-IMCI/internal/pdf"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/prescribing"
+	"github.com/Afomiat/Digital-IMCI/internal/vitals"
 	"github.com/Afomiat/Digital-IMCI/repository"
 	"github.com/Afomiat/Digital-IMCI/usecase"
 	younginfantcontroller "github.com/Afomiat/Digital-IMCI/ruleengine/controller"
 	childcontroller "github.com/Afomiat/Digital-IMCI/ruleengine/controller"
+	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
 	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
 	younginfantusecase "github.com/Afomiat/Digital-IMCI/ruleengine/usecase"
 	childusecase "github.com/Afomiat/Digital-IMCI/ruleengine/usecase"
 	"github.com/gin-gonic/gin"
@@ -23,7 +33,10 @@ func NewAssessmentRouter(
 	timeout time.Duration,
 	db *pgxpool.Pool,
 	group *gin.RouterGroup,
-) {
+	chatSessions domain.ChatSessionStore,
+	notifier domain.NotificationDispatcher,
+	auditUsecase domain.AuditUsecase,
+) domain.ChatAssessmentUsecase {
 	assessmentRepo := repository.NewAssessmentRepo(db)
 	patientRepo := repository.NewPatientRepo(db)
 	medicalProfessionalAnswerRepo := repository.NewMedicalProfessionalAnswerRepo(db)
@@ -31,14 +44,91 @@ func NewAssessmentRouter(
 	classificationRepo := repository.NewClassificationRepo(db)
 	treatmentPlanRepo := repository.NewTreatmentPlanRepo(db)
 	counselingRepo := repository.NewCounselingRepo(db)
+	immunizationRepo := repository.NewImmunizationRepo(db)
+	supplementRepo := repository.NewSupplementRepo(db)
 
-	assessmentUsecase := usecase.NewAssessmentUsecase(assessmentRepo, patientRepo, timeout)
+	vitalAlertUsecase := usecase.NewVitalAlertUsecase(
+		repository.NewVitalAlertRepo(db),
+		assessmentRepo,
+		classificationRepo,
+		patientRepo,
+		repository.NewMedicalProfessionalRepo(db),
+		notifier,
+		auditUsecase,
+		vitals.DefaultThresholds,
+		timeout,
+	)
+	consistencyRepo := repository.NewConsistencyRepo(db)
+	consistencyUsecase := usecase.NewConsistencyUsecase(consistencyRepo, assessmentRepo, timeout)
+	assessmentUsecase := usecase.NewAssessmentUsecase(assessmentRepo, patientRepo, vitalAlertUsecase, auditUsecase, timeout)
+	growthUsecase := usecase.NewGrowthUsecase(assessmentRepo, patientRepo, loadGrowthReference(env), timeout)
+	immunizationUsecase := usecase.NewImmunizationUsecase(immunizationRepo, patientRepo, timeout)
+	supplementUsecase := usecase.NewSupplementUsecase(supplementRepo, patientRepo, timeout)
+	counselingUsecase := usecase.NewCounselingUsecase(assessmentRepo, classificationRepo, counselingRepo, timeout)
+	answerProviders := []domain.TreeAnswerProvider{growthUsecase, immunizationUsecase, supplementUsecase}
+	lifecycleRepo := repository.NewAssessmentLifecycleRepo(db)
+	prescriptionUsecase := usecase.NewPrescriptionUsecase(
+		repository.NewPrescriptionRepo(db),
+		assessmentRepo,
+		classificationRepo,
+		treatmentPlanRepo,
+		auditUsecase,
+		prescribing.DefaultDrugTable,
+		timeout,
+	)
+	lifecycleUsecase := usecase.NewAssessmentLifecycleUsecase(lifecycleRepo, prescriptionUsecase, timeout)
+	reviewUsecase := usecase.NewAssessmentReviewUsecase(
+		repository.NewAssessmentReviewRepo(db),
+		lifecycleRepo,
+		repository.NewMedicalProfessionalRepo(db),
+		medicalProfessionalAnswerRepo,
+		classificationRepo,
+		treatmentPlanRepo,
+		counselingRepo,
+		prescriptionUsecase,
+		reviewCriteria(env.ReviewCriteria),
+		timeout,
+	)
+
+	telegramService := newTelegramService(env, db)
+	visitSummaryUsecase := usecase.NewVisitSummaryUsecase(
+		assessmentRepo,
+		patientRepo,
+		repository.NewMedicalProfessionalRepo(db),
+		classificationRepo,
+		treatmentPlanRepo,
+		counselingRepo,
+		loadPDFFont(env),
+		timeout,
+	)
+	companionUsecase := usecase.NewClinicalCompanionUsecase(
+		repository.NewTelegramRepository(db),
+		telegramService,
+		repository.NewMedicalProfessionalRepo(db),
+		patientRepo,
+		assessmentRepo,
+		repository.NewFollowUpRepo(db),
+		visitSummaryUsecase,
+		timeout,
+	)
+	classificationNotifier := domain.ClassificationNotifiers{companionUsecase, reviewUsecase}
+	if telegramService != nil {
+		telegramService.AttachCompanion(companionUsecase)
+		if err := telegramService.StartPolling(); err != nil {
+			logger.Warn("Telegram companion commands unavailable", "error", err)
+		}
+	}
 	
+	translator, err := locale.LoadTranslator()
+	if err != nil {
+		logger.Warn("translation catalogues not loaded, serving questions in English", "error", err)
+	}
+
 	var youngInfantController *younginfantcontroller.YoungInfantRuleEngineController
 	var youngInfantUsecase *younginfantusecase.YoungInfantRuleEngineUsecase
 	var childController *childcontroller.ChildRuleEngineController
 	var childUsecase *childusecase.ChildRuleEngineUsecase
-		youngInfantEngine, err := engine.NewYoungInfantRuleEngine()
+	youngInfantEngine, err := engine.NewYoungInfantRuleEngine()
 	if err != nil {
 		log.Printf("⚠️  Young infant rule engine initialization failed: %v", err)
 	} else {
@@ -46,14 +136,21 @@ func NewAssessmentRouter(
 		youngInfantUsecase = younginfantusecase.NewYoungInfantRuleEngineUsecase(
 			youngInfantEngine,
 			assessmentRepo,
+			consistencyRepo,
+			lifecycleUsecase,
+			vitalAlertUsecase,
 			medicalProfessionalAnswerRepo,
 			clinicalFindingsRepo,
 			classificationRepo,
 			treatmentPlanRepo,
 			counselingRepo,
+			answerProviders,
+			classificationNotifier,
+			auditUsecase,
+			translator,
 			timeout,
 		)
-		youngInfantController = younginfantcontroller.NewYoungInfantRuleEngineController(youngInfantUsecase)
+		youngInfantController = younginfantcontroller.NewYoungInfantRuleEngineController(youngInfantUsecase, translator)
 		log.Printf("✅ Young infant rule engine use case initialized successfully")
 	}
 
@@ -65,18 +162,66 @@ func NewAssessmentRouter(
 		childUsecase = childusecase.NewChildRuleEngineUsecase(
 			childEngine,
 			assessmentRepo,
+			lifecycleUsecase,
+			vitalAlertUsecase,
+			consistencyRepo,
 			medicalProfessionalAnswerRepo,
 			clinicalFindingsRepo,
 			classificationRepo,
 			treatmentPlanRepo,
 			counselingRepo,
+			answerProviders,
+			immunizationUsecase,
+			supplementUsecase,
+			classificationNotifier,
+			auditUsecase,
+			translator,
 			timeout,
 		)
-		childController = childcontroller.NewChildRuleEngineController(childUsecase)
+		childController = childcontroller.NewChildRuleEngineController(childUsecase, translator)
 		log.Printf("✅ Child rule engine use case initialized successfully")
 	}
 
+	var youngInfantRunner, childRunner childusecase.FlowRunner
+	if youngInfantUsecase != nil {
+		youngInfantRunner = youngInfantUsecase
+	}
+	if childUsecase != nil {
+		childRunner = childUsecase
+	}
+	chatAssessmentUsecase := childusecase.NewChatAssessmentUsecase(
+		assessmentUsecase,
+		assessmentRepo,
+		patientRepo,
+		chatSessions,
+		youngInfantRunner,
+		childRunner,
+		timeout,
+	)
+	if telegramService != nil {
+		telegramService.AttachChatAssessment(chatAssessmentUsecase)
+	}
+
+	treeSources := make(map[ruleenginedomain.AgeGroup]childcontroller.TreeSource)
+	if youngInfantUsecase != nil {
+		treeSources[ruleenginedomain.AgeGroupYoungInfant] = youngInfantEngine
+	}
+	if childUsecase != nil {
+		treeSources[ruleenginedomain.AgeGroupChild] = childEngine
+	}
+	localizationController := childcontroller.NewLocalizationController(translator, treeSources)
+
 	assessmentController := controller.NewAssessmentController(assessmentUsecase)
+	growthController := controller.NewGrowthController(growthUsecase)
+	immunizationController := controller.NewImmunizationController(immunizationUsecase)
+	supplementController := controller.NewSupplementController(supplementUsecase)
+	counselingController := controller.NewCounselingController(counselingUsecase)
+	visitSummaryController := controller.NewVisitSummaryController(visitSummaryUsecase)
+	lifecycleController := controller.NewAssessmentLifecycleController(lifecycleUsecase)
+	reviewController := controller.NewAssessmentReviewController(reviewUsecase)
+	vitalAlertController := controller.NewVitalAlertController(vitalAlertUsecase)
+	consistencyController := controller.NewConsistencyController(consistencyUsecase)
+	prescriptionController := controller.NewPrescriptionController(prescriptionUsecase)
 
 	assessmentGroup := group.Group("/assessments")
 	{
@@ -85,8 +230,101 @@ func NewAssessmentRouter(
 		assessmentGroup.GET("", assessmentController.ListAssessments) 
 		assessmentGroup.PUT("/:id", assessmentController.UpdateAssessment) 
 		assessmentGroup.DELETE("/:id", assessmentController.DeleteAssessment) 
+		assessmentGroup.GET("/:id/lifecycle", lifecycleController.GetLifecycle)
+		assessmentGroup.POST("/:id/complete", lifecycleController.Complete)
+		assessmentGroup.POST("/:id/cancel", lifecycleController.Cancel)
+		assessmentGroup.GET("/:id/review", reviewController.GetReview)
+		assessmentGroup.POST("/:id/offline-result", reviewController.ReportOfflineResult)
+		assessmentGroup.GET("/:id/vital-alerts", vitalAlertController.History)
+		assessmentGroup.GET("/:id/consistency", consistencyController.Findings)
+		assessmentGroup.GET("/:id/prescription", prescriptionController.GetPrescription)
+		assessmentGroup.POST("/:id/prescription", prescriptionController.Consolidate)
+		assessmentGroup.GET("/:id/growth", growthController.GetAssessmentGrowth)
+		assessmentGroup.GET("/:id/summary.pdf", visitSummaryController.DownloadPDF)
+		assessmentGroup.GET("/:id/counseling", counselingController.GetSession)
+		assessmentGroup.POST("/:id/counseling/:itemId/confirm", counselingController.ConfirmItem)
+		assessmentGroup.POST("/:id/counseling/:itemId/questions", counselingController.RecordQuestion)
 		
-		NewYoungInfantTreeRoutes(assessmentGroup, youngInfantUsecase, youngInfantController)
-		NewChildTreeRoutes(assessmentGroup, childUsecase, childController)
+		NewYoungInfantTreeRoutes(assessmentGroup, youngInfantUsecase, youngInfantController, translator)
+		NewChildTreeRoutes(assessmentGroup, childUsecase, childController, translator)
+	}
+
+	group.GET("/localization/languages", localizationController.ListLanguages)
+	group.GET("/admin/localization/coverage", middleware.RequireRole(domain.AdminRole), localizationController.Coverage)
+	group.GET("/counseling/completion", counselingController.GetMyCompletion)
+	group.GET("/admin/counseling/completion", middleware.RequireRole(domain.AdminRole), counselingController.GetCompletion)
+
+	reviewGroup := group.Group("/reviews", middleware.RequireRole(domain.SupervisorRole, domain.AdminRole))
+	{
+		reviewGroup.GET("", reviewController.ListQueue)
+		reviewGroup.POST("/:id/approve", reviewController.Approve)
+		reviewGroup.POST("/:id/amend", reviewController.Amend)
+		reviewGroup.POST("/:id/comment", reviewController.Comment)
+	}
+	group.PUT("/admin/medical-professionals/:id/trainee", middleware.RequireRole(domain.AdminRole), reviewController.SetTrainee)
+
+	group.GET("/patients/:id/growth", growthController.GetPatientGrowthHistory)
+	group.GET("/patients/:id/immunizations", immunizationController.GetRecords)
+	group.POST("/patients/:id/immunizations", immunizationController.RecordDose)
+	group.DELETE("/patients/:id/immunizations/:recordId", immunizationController.DeleteRecord)
+	group.GET("/patients/:id/immunizations/schedule", immunizationController.GetSchedule)
+	group.GET("/patients/:id/supplements", supplementController.GetRecords)
+	group.POST("/patients/:id/supplements", supplementController.RecordDose)
+	group.DELETE("/patients/:id/supplements/:recordId", supplementController.DeleteRecord)
+	group.GET("/patients/:id/supplements/status", supplementController.GetStatus)
+
+	return chatAssessmentUsecase
+}
+
+// loadPDFFont loads the font printouts use for Ge'ez script. Without it
+// advice in Amharic or Tigrinya is left off the printout.
+func loadPDFFont(env *config.Env) *pdf.Font {
+	if env.PDFFontPath == "" {
+		logger.Warn("Ge'ez script disabled on printouts", "reason", "PDF_FONT_PATH not set")
+		return nil
+	}
+	font, err := pdf.LoadFont(env.PDFFontPath)
+	if err != nil {
+		logger.Warn("Ge'ez script disabled on printouts", "error", err)
+		return nil
+	}
+	return font
+}
+
+// loadGrowthReference loads the WHO growth reference tables, from
+// GROWTH_REFERENCE_DIR when set and otherwise those built into the binary.
+// Without them growth z-scores are left out and the nutrition questions are
+// asked as usual.
+func loadGrowthReference(env *config.Env) *growth.Reference {
+	var reference *growth.Reference
+	var err error
+	if env.GrowthReferenceDir != "" {
+		reference, err = growth.LoadReference(os.DirFS(env.GrowthReferenceDir))
+	} else {
+		reference, err = growth.WHOReference()
+	}
+	if err != nil {
+		logger.Error("growth z-scores disabled", "error", err)
+		return nil
+	}
+	return reference
+}
+
+// reviewCriteria parses REVIEW_CRITERIA, skipping unknown criteria. An empty
+// setting enables every criterion.
+func reviewCriteria(raw string) []domain.ReviewReason {
+	var criteria []domain.ReviewReason
+	for _, item := range splitList(raw) {
+		reason := domain.ReviewReason(item)
+		known := false
+		for _, r := range domain.ReviewReasons {
+			known = known || r == reason
+		}
+		if !known {
+			logger.Warn("ignoring unknown review criterion", "criterion", item)
+			continue
+		}
+		criteria = append(criteria, reason)
 	}
-}
\ No newline at end of file
+	return criteria
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func NewAuditRouter(group *gin.RouterGroup, auditUsecase domain.AuditUsecase) {
+	auditController := controller.NewAuditController(auditUsecase)
+
+	adminGroup := group.Group("/admin/audit", middleware.RequireRole(domain.AdminRole))
+	{
+		adminGroup.GET("", auditController.List)
+		adminGroup.GET("/verify", auditController.Verify)
+	}
+}
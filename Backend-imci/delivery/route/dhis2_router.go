@@ -0,0 +1,73 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/config"
+	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/dhis2"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/repository"
+	"github.com/Afomiat/Digital-IMCI/service"
+	"github.com/Afomiat/Digital-IMCI/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func NewDHIS2Router(
+	env *config.Env,
+	timeout time.Duration,
+	db *pgxpool.Pool,
+	group *gin.RouterGroup,
+) {
+	var mapping *dhis2.Mapping
+	if env.DHIS2MappingFile != "" {
+		var err error
+		if mapping, err = dhis2.LoadMapping(env.DHIS2MappingFile); err != nil {
+			logger.Warn("DHIS2 reporting disabled", "error", err)
+		}
+	}
+
+	var client domain.DHIS2Client
+	if env.DHIS2URL != "" {
+		client = service.NewDHIS2Client(env.DHIS2URL, env.DHIS2Username, env.DHIS2Password)
+	}
+
+	dhis2Usecase := usecase.NewDHIS2Usecase(repository.NewDHIS2Repo(db), client, mapping, env.DHIS2PushDay, timeout)
+	dhis2Controller := controller.NewDHIS2Controller(dhis2Usecase)
+
+	if env.DHIS2PushDay > 0 {
+		go pushDHIS2Reports(dhis2Usecase, dhis2PushCheckInterval)
+	}
+
+	adminGroup := group.Group("/admin/dhis2", middleware.RequireRole(domain.AdminRole))
+	{
+		adminGroup.GET("/report", dhis2Controller.GetReport)
+		adminGroup.GET("/dataValueSets", dhis2Controller.GetDataValueSet)
+		adminGroup.POST("/push", dhis2Controller.Push)
+		adminGroup.GET("/exports", dhis2Controller.ListExports)
+	}
+}
+
+// dhis2PushCheckInterval is how often the monthly DHIS2 push is checked for.
+// A failed push is retried on the next check.
+const dhis2PushCheckInterval = time.Hour
+
+func pushDHIS2Reports(dhis2Usecase domain.DHIS2Usecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		export, err := dhis2Usecase.PushDue(context.Background(), time.Now())
+		if err != nil {
+			logger.Error("scheduled DHIS2 push failed", "error", err)
+			continue
+		}
+		if export != nil {
+			logger.Info("pushed monthly report to DHIS2", "period", export.Period, "data_values", export.DataValues, "status", export.Status)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package route
+
+import (
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/config"
+	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/repository"
+	"github.com/Afomiat/Digital-IMCI/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func NewFHIRRouter(
+	env *config.Env,
+	timeout time.Duration,
+	db *pgxpool.Pool,
+	group *gin.RouterGroup,
+) {
+	fhirExportUsecase := usecase.NewFHIRExportUsecase(
+		repository.NewAssessmentRepo(db),
+		repository.NewPatientRepo(db),
+		repository.NewClassificationRepo(db),
+		repository.NewTreatmentPlanRepo(db),
+		timeout,
+	)
+	fhirController := controller.NewFHIRController(fhirExportUsecase)
+
+	fhirGroup := group.Group("/fhir")
+	{
+		fhirGroup.GET("/Patient/:id/$everything", fhirController.PatientEverything)
+		fhirGroup.GET("/Encounter/:id/$everything", fhirController.EncounterEverything)
+	}
+}
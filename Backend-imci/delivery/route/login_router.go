@@ -1,27 +1,29 @@
 package route
 
 import (
-	"time"
-
-	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
 	"github.com/Afomiat/Digital-IMCI/domain"
-	"github.com/Afomiat/Digital-IMCI/usecase"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewLoginRouter(
-	env *config.Env,
-	timeout time.Duration,
-	db *pgxpool.Pool,
-	group *gin.RouterGroup,
-	medicalProfessionalRepo domain.MedicalProfessionalRepository,
-) {
-	// Login doesn't need OTP, Telegram, or WhatsApp services
-	loginUsecase := usecase.NewLoginUsecase(medicalProfessionalRepo, timeout, env)
+// NewLoginRouter and NewSessionRouter share one LoginUsecase, so the public
+// login routes and the protected session routes see the same sessions.
+func NewLoginRouter(group *gin.RouterGroup, loginUsecase domain.LoginUsecase) {
 	loginController := controller.NewLoginController(loginUsecase)
 
 	group.POST("/login", loginController.Login)
 	group.POST("/refresh-token", loginController.RefreshToken)
-}
\ No newline at end of file
+}
+
+func NewSessionRouter(group *gin.RouterGroup, loginUsecase domain.LoginUsecase) {
+	loginController := controller.NewLoginController(loginUsecase)
+
+	group.GET("/auth/sessions", loginController.ListSessions)
+	group.DELETE("/auth/sessions", loginController.RevokeOtherSessions)
+	group.DELETE("/auth/sessions/:id", loginController.RevokeSession)
+
+	adminGroup := group.Group("/admin", middleware.RequireRole(domain.AdminRole))
+	adminGroup.POST("/logins/:phone/unlock", loginController.UnlockLogin)
+	adminGroup.GET("/logins/audit", loginController.ListLoginAudit)
+}
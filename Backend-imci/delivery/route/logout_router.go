@@ -11,8 +11,9 @@ func NewLogoutRouter(
 	env *config.Env,
 	Group *gin.RouterGroup,
 	blacklistRepo domain.TokenBlacklistRepository,
+	sessionRepo domain.RefreshSessionRepository,
 ) {
-	authController := middleware.NewAuthController(blacklistRepo)
+	authController := middleware.NewAuthController(blacklistRepo, sessionRepo)
 	
 	Group.POST("/logout", authController.Logout)
 }
\ No newline at end of file
@@ -0,0 +1,100 @@
+package route
+
+import (
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/config"
+	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/repository"
+	"github.com/Afomiat/Digital-IMCI/service"
+	"github.com/Afomiat/Digital-IMCI/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTelegramService returns the shared bot, or nil when Telegram is not
+// configured or the bot cannot be created.
+func newTelegramService(env *config.Env, db *pgxpool.Pool) domain.TelegramService {
+	if env.TelegramBotToken == "" {
+		return nil
+	}
+
+	telegramSvc, err := service.GetTelegramService(
+		env.TelegramBotToken,
+		repository.NewTelegramRepository(db),
+		repository.NewOtpRepository(db),
+	)
+	if err != nil {
+		logger.Warn("Telegram service not available", "error", err)
+		return nil
+	}
+	return telegramSvc
+}
+
+// newWhatsAppService returns the Cloud API client, or nil when WhatsApp is
+// not configured.
+func newWhatsAppService(env *config.Env) domain.WhatsAppService {
+	if env.MetaWhatsAppAccessToken == "" || env.MetaWhatsAppPhoneNumberID == "" {
+		return nil
+	}
+	return service.NewMetaWhatsAppService(
+		env.MetaWhatsAppAccessToken,
+		env.MetaWhatsAppPhoneNumberID,
+		env.MetaWhatsAppGraphURL,
+		service.DefaultWhatsAppTemplates(),
+	)
+}
+
+// newNotificationDispatcher registers every configured OTP channel.
+func newNotificationDispatcher(env *config.Env, db *pgxpool.Pool) domain.NotificationDispatcher {
+	telegramRepo := repository.NewTelegramRepository(db)
+	telegramService := newTelegramService(env, db)
+
+	whatsappService := newWhatsAppService(env)
+
+	var smsChannel domain.NotificationChannel
+	switch env.SMSProvider {
+	case service.SMSProviderHTTP:
+		if env.SMSGatewayURL == "" {
+			logger.Warn("SMS disabled", "reason", "SMS_PROVIDER=http but SMS_GATEWAY_URL is empty")
+		} else {
+			smsChannel = service.NewHTTPSMSChannel(env.SMSGatewayURL, env.SMSGatewayAPIKey, env.SMSSenderID)
+		}
+	case service.SMSProviderFile:
+		smsChannel = service.NewStubSMSChannel(env.SMSOutboxFile)
+		logger.Info("SMS stub enabled", "outbox", env.SMSOutboxFile)
+	case service.SMSProviderLog:
+		smsChannel = service.NewStubSMSChannel("")
+		logger.Info("SMS stub enabled", "outbox", "log")
+	}
+
+	return service.NewNotificationRegistry(
+		repository.NewNotificationDeliveryRepo(db),
+		service.ParseChannelOrder(env.NotificationFallbackOrder),
+		service.NewTelegramChannel(telegramService, telegramRepo),
+		service.NewWhatsAppChannel(whatsappService),
+		smsChannel,
+	)
+}
+
+func NewNotificationRouter(
+	env *config.Env,
+	timeout time.Duration,
+	db *pgxpool.Pool,
+	group *gin.RouterGroup,
+	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	notifier domain.NotificationDispatcher,
+) {
+	deliveryRepo := repository.NewNotificationDeliveryRepo(db)
+	notificationUsecase := usecase.NewNotificationUsecase(medicalProfessionalRepo, deliveryRepo, notifier, timeout)
+	notificationController := controller.NewNotificationController(notificationUsecase)
+
+	group.GET("/notifications/channels", notificationController.ListChannels)
+	group.PUT("/notifications/channel", notificationController.SetPreferredChannel)
+
+	adminGroup := group.Group("/admin", middleware.RequireRole(domain.AdminRole))
+	adminGroup.GET("/notifications/deliveries", notificationController.ListDeliveries)
+}
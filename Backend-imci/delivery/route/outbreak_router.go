@@ -0,0 +1,87 @@
+package route
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/config"
+	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/surveillance"
+	"github.com/Afomiat/Digital-IMCI/repository"
+	"github.com/Afomiat/Digital-IMCI/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func NewOutbreakRouter(
+	env *config.Env,
+	timeout time.Duration,
+	db *pgxpool.Pool,
+	group *gin.RouterGroup,
+	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	notifier domain.NotificationDispatcher,
+) {
+	settings := usecase.OutbreakSettings{
+		Signals:     splitList(env.SurveillanceSignals),
+		Threshold:   env.SurveillanceThreshold,
+		MinCases:    env.SurveillanceMinCases,
+		FocalPhones: splitList(env.SurveillanceFocalPhones),
+	}
+	if env.SurveillanceMethod != "" {
+		method, err := surveillance.ParseMethod(env.SurveillanceMethod)
+		if err != nil {
+			logger.Warn("ignoring SURVEILLANCE_METHOD", "error", err)
+		}
+		settings.Method = method
+	}
+	if len(settings.FocalPhones) == 0 {
+		logger.Warn("SURVEILLANCE_FOCAL_PHONES is empty; outbreak alerts will only be stored")
+	}
+
+	outbreakUsecase := usecase.NewOutbreakUsecase(repository.NewOutbreakRepo(db), medicalProfessionalRepo, notifier, settings, timeout)
+	outbreakController := controller.NewOutbreakController(outbreakUsecase)
+
+	go detectOutbreaks(outbreakUsecase, outbreakCheckInterval)
+
+	adminGroup := group.Group("/admin/outbreaks", middleware.RequireRole(domain.AdminRole))
+	{
+		adminGroup.GET("/alerts", outbreakController.ListAlerts)
+		adminGroup.POST("/alerts/:id/acknowledge", outbreakController.Acknowledge)
+		adminGroup.POST("/detect", outbreakController.Detect)
+	}
+}
+
+// outbreakCheckInterval is how often today's counts are tested. Each alert
+// is raised once per facility, signal and day.
+const outbreakCheckInterval = time.Hour
+
+func detectOutbreaks(outbreakUsecase domain.OutbreakUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		alerts, err := outbreakUsecase.Detect(context.Background(), time.Now().UTC())
+		if err != nil {
+			logger.Error("outbreak detection failed", "error", err)
+			continue
+		}
+		if len(alerts) > 0 {
+			logger.Info("outbreak detection raised alerts", "alerts", len(alerts))
+		}
+	}
+}
+
+// splitList splits a comma separated setting, dropping empty entries.
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,14 +1,12 @@
 package route
 
 import (
-	"log"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/delivery/controller"
 	"github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/Afomiat/Digital-IMCI/repository"
-	"github.com/Afomiat/Digital-IMCI/service"
 	"github.com/Afomiat/Digital-IMCI/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,39 +18,14 @@ func NewPasswordResetRouter(
 	db *pgxpool.Pool,
 	group *gin.RouterGroup,
 	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	notifier domain.NotificationDispatcher,
 ) {
 	passwordResetRepo := repository.NewPasswordResetRepository(db)
-	otpRepo := repository.NewOtpRepository(db)
-	telegramRepo := repository.NewTelegramRepository(db)
-	
-	var telegramService domain.TelegramService
-	if env.TelegramBotToken != "" {
-		telegramSvc, err := service.NewTelegramBotService(
-			env.TelegramBotToken, 
-			telegramRepo, 
-			otpRepo,
-		)
-		if err != nil {
-			log.Printf("Warning: Telegram service not available for password reset: %v", err)
-		} else {
-			telegramService = telegramSvc
-		}
-	}
-
-	var whatsappService domain.WhatsAppService
-	if env.MetaWhatsAppAccessToken != "" && env.MetaWhatsAppPhoneNumberID != "" {
-		whatsappService = service.NewMetaWhatsAppService(
-			env.MetaWhatsAppAccessToken,
-			env.MetaWhatsAppPhoneNumberID,
-		)
-	}
 
 	passwordResetUsecase := usecase.NewPasswordResetUsecase(
 		medicalProfessionalRepo,
 		passwordResetRepo,
-		telegramRepo,
-		telegramService,
-		whatsappService,
+		notifier,
 		timeout,
 		env,
 	)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/Afomiat/Digital-IMCI/usecase"
 	"github.com/Afomiat/Digital-IMCI/repository"
 	"github.com/gin-gonic/gin"
@@ -16,9 +17,10 @@ func NewPatientRouter(
 	timeout time.Duration,
 	db *pgxpool.Pool,
 	group *gin.RouterGroup,
+	auditor domain.AuditRecorder,
 ) {
 	patientRepo := repository.NewPatientRepo(db)
-	patientUsecase := usecase.NewPatientUsecase(patientRepo, timeout)
+	patientUsecase := usecase.NewPatientUsecase(patientRepo, auditor, timeout)
 	patientController := controller.NewPatientController(patientUsecase)
 
 	patientGroup := group.Group("/patients")
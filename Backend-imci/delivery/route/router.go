@@ -8,8 +8,10 @@ import (
 	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
 	"github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/Afomiat/Digital-IMCI/repository"
+	"github.com/Afomiat/Digital-IMCI/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 func Setup(
@@ -20,28 +22,48 @@ func Setup(
 ) {
 	medicalProfessionalRepo := repository.NewMedicalProfessionalRepo(db)
 	
+	// The Redis client lives for the whole process; closing it when Setup
+	// returns would break every request that checks the blacklist.
 	var blacklistRepo domain.TokenBlacklistRepository
+	var attemptStore domain.LoginAttemptStore
+	chatSessions := repository.NewMemoryChatSessionStore()
+	var redisClient *redis.Client
 	if env.RedisURL != "" {
 		redisRepo, err := repository.NewRedisTokenBlacklist(env.RedisURL)
 		if err != nil {
 			log.Printf("Warning: Redis blacklist not available: %v", err)
 		} else {
 			blacklistRepo = redisRepo
-			defer redisRepo.Close()
+			redisClient = redisRepo.Client()
+			attemptStore = repository.NewRedisLoginAttemptStore(redisRepo.Client())
+			chatSessions = repository.NewRedisChatSessionStore(redisRepo.Client())
 		}
 	}
 
-	authMiddleware := middleware.NewAuthMiddleware(env, blacklistRepo).Handler()
+	// Sessions are revoked through the cache so the auth middleware sees it.
+	sessionRepo := repository.NewCachedRefreshSessionRepo(repository.NewRefreshSessionRepo(db), redisClient)
+	authMiddleware := middleware.NewAuthMiddleware(env, blacklistRepo, sessionRepo).Handler()
+	notifier := newNotificationDispatcher(env, db)
 	
 	public := r.Group("/api/v1")
 	protected := r.Group("/api/v1")
-	protected.Use(authMiddleware)
+	protected.Use(authMiddleware, middleware.RequestMeta())
+	auditUsecase := usecase.NewAuditUsecase(repository.NewAuditRepo(db), timeout)
+	loginUsecase := usecase.NewLoginUsecase(medicalProfessionalRepo, sessionRepo, attemptStore, repository.NewLoginAuditRepo(db), timeout, env)
 	
-	NewSignUpRouter(env, timeout, db, public, medicalProfessionalRepo)
-	NewLoginRouter(env, timeout, db, public, medicalProfessionalRepo)
-	NewPasswordResetRouter(env, timeout, db, public, medicalProfessionalRepo)
-	NewPatientRouter(env, timeout, db, protected)
-	NewLogoutRouter(env, protected, blacklistRepo)
-	NewAssessmentRouter(env, timeout, db, protected)
+	NewSignUpRouter(env, timeout, db, public, medicalProfessionalRepo, notifier)
+	NewLoginRouter(public, loginUsecase)
+	NewPasswordResetRouter(env, timeout, db, public, medicalProfessionalRepo, notifier)
+	NewPatientRouter(env, timeout, db, protected, auditUsecase)
+	NewLogoutRouter(env, protected, blacklistRepo, sessionRepo)
+	NewSessionRouter(protected, loginUsecase)
+	chatAssessment := NewAssessmentRouter(env, timeout, db, protected, chatSessions, notifier, auditUsecase)
+	NewNotificationRouter(env, timeout, db, protected, medicalProfessionalRepo, notifier)
+	NewFHIRRouter(env, timeout, db, protected)
+	NewDHIS2Router(env, timeout, db, protected)
+	NewStatsRouter(env, timeout, db, protected, medicalProfessionalRepo)
+	NewOutbreakRouter(env, timeout, db, protected, medicalProfessionalRepo, notifier)
+	NewAuditRouter(protected, auditUsecase)
+	NewWhatsAppWebhookRouter(env, timeout, db, public, medicalProfessionalRepo, chatAssessment)
 
 }
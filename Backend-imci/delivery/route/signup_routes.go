@@ -1,12 +1,14 @@
 package route
 
 import (
+	"context"
 	"log"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/delivery/controller"
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
 	"github.com/Afomiat/Digital-IMCI/repository"
 	"github.com/Afomiat/Digital-IMCI/service"
 	"github.com/Afomiat/Digital-IMCI/usecase"
@@ -20,6 +22,7 @@ func NewSignUpRouter(
 	db *pgxpool.Pool,
 	group *gin.RouterGroup,
 	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	notifier domain.NotificationDispatcher,
 ) {
 	otpRepo := repository.NewOtpRepository(db)
 	telegramRepo := repository.NewTelegramRepository(db)
@@ -39,29 +42,39 @@ func NewSignUpRouter(
 		}
 	}
 
-	var whatsappService domain.WhatsAppService
-	if env.MetaWhatsAppAccessToken != "" && env.MetaWhatsAppPhoneNumberID != "" {
-		whatsappService = service.NewMetaWhatsAppService(
-			env.MetaWhatsAppAccessToken,
-			env.MetaWhatsAppPhoneNumberID,
-		)
-		log.Println("Meta WhatsApp Service initialized successfully for signup")
-	}
-
 	signupUsecase := usecase.NewSignupUsecase(
 		medicalProfessionalRepo,
 		otpRepo,
-		telegramService,
-		whatsappService,
+		notifier,
 		timeout,
 		env,
 	)
 
+	go purgeExpiredSignups(signupUsecase, pendingSignupPurgeInterval)
+
 	telegramController := controller.NewTelegramController(signupUsecase, telegramService)
-	whatsappController := controller.NewWhatsAppController(signupUsecase)
-	signController := controller.NewSignupController(signupUsecase, telegramController, whatsappController)
+	signController := controller.NewSignupController(signupUsecase, telegramController)
 
 	group.POST("/signup", signController.Signup)
 	group.POST("/verify", signController.Verify)
 	group.GET("/telegram/start-link", telegramController.GetStartLink)
 }
+
+// pendingSignupPurgeInterval is how often unverified signups are deleted.
+const pendingSignupPurgeInterval = 10 * time.Minute
+
+func purgeExpiredSignups(signupUsecase domain.SignupUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := signupUsecase.PurgeExpiredSignups(context.Background())
+		if err != nil {
+			logger.Error("failed to purge expired signups", "error", err)
+			continue
+		}
+		if purged > 0 {
+			logger.Info("purged expired signups", "count", purged)
+		}
+	}
+}
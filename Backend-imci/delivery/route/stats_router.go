@@ -0,0 +1,28 @@
+package route
+
+import (
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/config"
+	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/delivery/middleware"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/repository"
+	"github.com/Afomiat/Digital-IMCI/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func NewStatsRouter(
+	env *config.Env,
+	timeout time.Duration,
+	db *pgxpool.Pool,
+	group *gin.RouterGroup,
+	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+) {
+	statsUsecase := usecase.NewStatsUsecase(repository.NewStatsRepo(db), medicalProfessionalRepo, timeout)
+	statsController := controller.NewStatsController(statsUsecase)
+
+	group.GET("/stats", statsController.GetStats)
+	group.GET("/admin/stats/facilities", middleware.RequireRole(domain.AdminRole), statsController.ListFacilities)
+}
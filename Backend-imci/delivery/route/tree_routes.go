@@ -7,6 +7,7 @@ import (
 
 	childcontroller "github.com/Afomiat/Digital-IMCI/ruleengine/controller"
 	younginfantcontroller "github.com/Afomiat/Digital-IMCI/ruleengine/controller"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
 	childusecase "github.com/Afomiat/Digital-IMCI/ruleengine/usecase"
 	younginfantusecase "github.com/Afomiat/Digital-IMCI/ruleengine/usecase"
 	"github.com/gin-gonic/gin"
@@ -16,9 +17,10 @@ func NewYoungInfantTreeRoutes(
 	assessmentGroup *gin.RouterGroup,
 	youngInfantUsecase *younginfantusecase.YoungInfantRuleEngineUsecase,
 	youngInfantController *younginfantcontroller.YoungInfantRuleEngineController,
+	translator *locale.Translator,
 ) {
 	if youngInfantController != nil && youngInfantUsecase != nil {
-		setupYoungInfantTreeRoutes(assessmentGroup, youngInfantUsecase, youngInfantController)
+		setupYoungInfantTreeRoutes(assessmentGroup, youngInfantUsecase, youngInfantController, translator)
 	} else {
 		setupYoungInfantTreeRoutesUnavailable(assessmentGroup)
 	}
@@ -28,9 +30,10 @@ func NewChildTreeRoutes(
 	assessmentGroup *gin.RouterGroup,
 	childUsecase *childusecase.ChildRuleEngineUsecase,
 	childController *childcontroller.ChildRuleEngineController,
+	translator *locale.Translator,
 ) {
 	if childController != nil && childUsecase != nil {
-		setupChildTreeRoutes(assessmentGroup, childUsecase, childController)
+		setupChildTreeRoutes(assessmentGroup, childUsecase, childController, translator)
 	} else {
 		setupChildTreeRoutesUnavailable(assessmentGroup)
 	}
@@ -40,6 +43,7 @@ func setupYoungInfantTreeRoutes(
 	assessmentGroup *gin.RouterGroup,
 	youngInfantUsecase *younginfantusecase.YoungInfantRuleEngineUsecase,
 	youngInfantController *younginfantcontroller.YoungInfantRuleEngineController,
+	translator *locale.Translator,
 ) {
 	youngInfantGroup := assessmentGroup.Group("/young-infant")
 	{
@@ -101,39 +105,39 @@ func setupYoungInfantTreeRoutes(
 		youngInfantGroup.POST("/batch-process", youngInfantController.ProcessBatchAssessment)
 
 		youngInfantGroup.GET("/tree/diarrhea", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "diarrhea_check")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "diarrhea_check")
 		})
 
 		youngInfantGroup.GET("/tree/jaundice", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "jaundice_check")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "jaundice_check")
 		})
 
 		youngInfantGroup.GET("/tree/birth_asphyxia", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "birth_asphyxia_check")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "birth_asphyxia_check")
 		})
 
 		youngInfantGroup.GET("/tree/very_severe_disease", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "very_severe_disease_check")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "very_severe_disease_check")
 		})
 
 		youngInfantGroup.GET("/tree/feeding_problem", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "feeding_problem_underweight_check")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "feeding_problem_underweight_check")
 		})
 
 		youngInfantGroup.GET("/tree/replacement_feeding", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "replacement_feeding_check")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "replacement_feeding_check")
 		})
 
 		youngInfantGroup.GET("/tree/hiv", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "hiv_status_assessment")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "hiv_status_assessment")
 		})
 
 		youngInfantGroup.GET("/tree/gestation", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "gestation_classification")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "gestation_classification")
 		})
 
 		youngInfantGroup.GET("/tree/developmental", func(c *gin.Context) {
-			getYoungInfantTreeHandler(c, youngInfantUsecase, "developmental_assessment")
+			getYoungInfantTreeHandler(c, youngInfantUsecase, translator, "developmental_assessment")
 		})
 
 		youngInfantGroup.POST("/:id/start-flow", youngInfantController.StartAssessmentFlow)
@@ -145,6 +149,7 @@ func setupChildTreeRoutes(
 	assessmentGroup *gin.RouterGroup,
 	childUsecase *childusecase.ChildRuleEngineUsecase,
 	childController *childcontroller.ChildRuleEngineController,
+	translator *locale.Translator,
 ) {
 	childGroup := assessmentGroup.Group("/child")
 	{
@@ -224,42 +229,42 @@ func setupChildTreeRoutes(
 		log.Printf("🔧 /batch-process endpoint called")
 
 		childGroup.GET("/tree/general_danger_signs", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "child_general_danger_signs")
+			getChildTreeHandler(c, childUsecase, translator, "child_general_danger_signs")
 		})
 
 		childGroup.GET("/tree/cough_difficult_breathing", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "child_cough_difficult_breathing")
+			getChildTreeHandler(c, childUsecase, translator, "child_cough_difficult_breathing")
 		})
 		childGroup.GET("/tree/diarrhea", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, " ")
+			getChildTreeHandler(c, childUsecase, translator, " ")
 		})
 		childGroup.GET("/tree/fever", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "child_fever")
+			getChildTreeHandler(c, childUsecase, translator, "child_fever")
 		})
 		childGroup.GET("/tree/ear_problem", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "child_ear_problem")
+			getChildTreeHandler(c, childUsecase, translator, "child_ear_problem")
 		})
 		childGroup.GET("/tree/anemia", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "child_anemia_check")
+			getChildTreeHandler(c, childUsecase, translator, "child_anemia_check")
 		})
 		childGroup.GET("/tree/acute_malnutrition", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "acute_malnutrition")
+			getChildTreeHandler(c, childUsecase, translator, "acute_malnutrition")
 		})
 		childGroup.GET("/tree/feeding_assessment", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "feeding_assessment")
+			getChildTreeHandler(c, childUsecase, translator, "feeding_assessment")
 		})
 		childGroup.GET("/tree/hiv_assessment", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "hiv_assessment")
+			getChildTreeHandler(c, childUsecase, translator, "hiv_assessment")
 
 		})
 		childGroup.GET("/tree/tb_assessment", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "tb_assessment")
+			getChildTreeHandler(c, childUsecase, translator, "tb_assessment")
 		})
 		childGroup.GET("/tree/developmental_assessment", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "developmental_assessment")
+			getChildTreeHandler(c, childUsecase, translator, "developmental_assessment")
 		})
 		childGroup.GET("/tree/immunization_vitamin", func(c *gin.Context) {
-			getChildTreeHandler(c, childUsecase, "immunization_vitamin_status")
+			getChildTreeHandler(c, childUsecase, translator, "immunization_vitamin_status")
 		})
 
 		childGroup.POST("/:id/start-flow", childController.StartAssessmentFlow)
@@ -315,8 +320,9 @@ func setupChildTreeRoutesUnavailable(assessmentGroup *gin.RouterGroup) {
 	childGroup.POST("/:id/answer", unavailableHandler)
 }
 
-func getYoungInfantTreeHandler(c *gin.Context, youngInfantUsecase *younginfantusecase.YoungInfantRuleEngineUsecase, treeID string) {
-	tree, err := youngInfantUsecase.GetAssessmentTree(treeID)
+func getYoungInfantTreeHandler(c *gin.Context, youngInfantUsecase *younginfantusecase.YoungInfantRuleEngineUsecase, translator *locale.Translator, treeID string) {
+	language := younginfantcontroller.RequestLanguage(c, translator, "")
+	tree, err := youngInfantUsecase.GetTreeQuestions(treeID, language)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get assessment tree",
@@ -328,11 +334,13 @@ func getYoungInfantTreeHandler(c *gin.Context, youngInfantUsecase *younginfantus
 	c.JSON(http.StatusOK, gin.H{
 		"tree":      tree,
 		"age_group": "young_infant",
+		"language":  language,
 	})
 }
 
-func getChildTreeHandler(c *gin.Context, childUsecase *childusecase.ChildRuleEngineUsecase, treeID string) {
-	tree, err := childUsecase.GetAssessmentTree(treeID)
+func getChildTreeHandler(c *gin.Context, childUsecase *childusecase.ChildRuleEngineUsecase, translator *locale.Translator, treeID string) {
+	language := childcontroller.RequestLanguage(c, translator, "")
+	tree, err := childUsecase.GetTreeQuestions(treeID, language)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get assessment tree",
@@ -344,5 +352,6 @@ func getChildTreeHandler(c *gin.Context, childUsecase *childusecase.ChildRuleEng
 	c.JSON(http.StatusOK, gin.H{
 		"tree":      tree,
 		"age_group": "child",
+		"language":  language,
 	})
 }
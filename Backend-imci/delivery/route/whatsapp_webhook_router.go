@@ -0,0 +1,47 @@
+package route
+
+import (
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/config"
+	"github.com/Afomiat/Digital-IMCI/delivery/controller"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/repository"
+	"github.com/Afomiat/Digital-IMCI/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewWhatsAppWebhookRouter mounts the Cloud API webhook. It is public, so it
+// is only enabled when requests can be authenticated with the app secret.
+func NewWhatsAppWebhookRouter(
+	env *config.Env,
+	timeout time.Duration,
+	db *pgxpool.Pool,
+	group *gin.RouterGroup,
+	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	chat domain.ChatAssessmentUsecase,
+) {
+	whatsappService := newWhatsAppService(env)
+	if whatsappService == nil || env.MetaWhatsAppAppSecret == "" || env.MetaWhatsAppVerifyToken == "" {
+		logger.Warn("WhatsApp webhook disabled", "reason", "set the access token, phone number ID, app secret and verify token to enable it")
+		return
+	}
+
+	webhookUsecase := usecase.NewWhatsAppWebhookUsecase(
+		repository.NewWhatsAppStatusRepo(db),
+		medicalProfessionalRepo,
+		chat,
+		whatsappService,
+		timeout,
+	)
+	webhookController := controller.NewWhatsAppWebhookController(
+		webhookUsecase,
+		env.MetaWhatsAppAppSecret,
+		env.MetaWhatsAppVerifyToken,
+	)
+
+	group.GET("/webhooks/whatsapp", webhookController.Verify)
+	group.POST("/webhooks/whatsapp", webhookController.Receive)
+}
@@ -5,7 +5,7 @@ import (
 )
 
 type PasswordResetUsecase interface {
-	InitiatePasswordReset(ctx context.Context, phone string, useWhatsApp bool) error
+	InitiatePasswordReset(ctx context.Context, phone string, channel string) (string, error)
 	VerifyPasswordResetOTP(ctx context.Context, phone, otpCode string) (bool, error)
 	ResetPassword(ctx context.Context, phone, newPassword string) error
 }
@@ -13,6 +13,9 @@ var (
 	ErrInvalidWeight           = errors.New("invalid weight")
 	ErrInvalidAgeForAssessment = errors.New("patient age outside IMCI range (0-59 months)")
 	ErrMedicalProfessionalAnswerNotFound = errors.New("medical professional answer not found")
+	ErrInvalidLength           = errors.New("invalid length/height")
+	ErrInvalidHeadCircumference = errors.New("invalid head circumference")
+	ErrInvalidMeasurementPosition = errors.New("measurement position must be lying or standing")
 )
 
 type AssessmentType string
@@ -30,6 +33,14 @@ const (
 	StatusCancelled  AssessmentStatus = "cancelled"
 )
 
+// MeasurementPosition records whether length/height was measured with the
+// child lying down (length) or standing (height).
+type MeasurementPosition string
+const (
+	PositionLying    MeasurementPosition = "lying"
+	PositionStanding MeasurementPosition = "standing"
+)
+
 type JSONB map[string]interface{}
 
 type Assessment struct {
@@ -43,6 +54,9 @@ type Assessment struct {
 	MainSymptoms          JSONB             `json:"main_symptoms"`
 	MUAC                  *float64          `json:"muac,omitempty"`
 	RespiratoryRate       *int              `json:"respiratory_rate,omitempty"`
+	LengthCm              *float64          `json:"length_cm,omitempty"`
+	HeadCircumferenceCm   *float64          `json:"head_circumference_cm,omitempty"`
+	MeasurementPosition   MeasurementPosition `json:"measurement_position,omitempty"`
 	AgeMonths             int               `json:"age_months"`
 	GuidelineVersion      string            `json:"guideline_version"`
 	StartTime             time.Time         `json:"start_time"`
@@ -67,6 +81,8 @@ type Assessment struct {
 	ClinicalFindings    *ClinicalFindings    `json:"clinical_findings,omitempty"`
 	Classification      *Classification      `json:"classification,omitempty"`
 	MedicalProfessionalAnswer *MedicalProfessionalAnswer `json:"medical_professional_answer,omitempty"`
+	// VitalAlerts are the danger thresholds the current vitals breach.
+	VitalAlerts []*VitalAlert `json:"vital_alerts,omitempty"`
 }
 
 // NEW: Medical Professional Answer represents our session
@@ -201,6 +217,8 @@ type Classification struct {
 	IsCriticalIllness    bool      `json:"is_critical_illness"`
 	RequiresUrgentReferral bool    `json:"requires_urgent_referral"`
 	TreatmentPriority    int       `json:"treatment_priority"`
+	// FollowUpDate is derived from the outcome's follow-up advice.
+	FollowUpDate         *time.Time `json:"follow_up_date,omitempty"`
 	CreatedAt            time.Time `json:"created_at"`
 }
 
@@ -228,6 +246,11 @@ type CreateAssessmentRequest struct {
 	MainSymptoms    []string  `json:"main_symptoms,omitempty"`
 	MUAC            *float64  `json:"muac,omitempty"`
 	RespiratoryRate *int      `json:"respiratory_rate,omitempty"`
+	OxygenSaturation *int     `json:"oxygen_saturation,omitempty" binding:"omitempty,min=30,max=100"`
+	HbLevel         *float64  `json:"hb_level,omitempty" binding:"omitempty,min=1,max=25"`
+	LengthCm            *float64            `json:"length_cm,omitempty"`
+	HeadCircumferenceCm *float64            `json:"head_circumference_cm,omitempty"`
+	MeasurementPosition MeasurementPosition `json:"measurement_position,omitempty"`
 	IsOffline       bool      `json:"is_offline"`
 }
 
@@ -267,6 +290,12 @@ type UpdateAssessmentRequest struct {
     Notes       string  `json:"notes,omitempty"`
     Weight      float64 `json:"weight,omitempty"`
     Temperature float64 `json:"temperature,omitempty"`
+    RespiratoryRate  *int     `json:"respiratory_rate,omitempty"`
+    OxygenSaturation *int     `json:"oxygen_saturation,omitempty" binding:"omitempty,min=30,max=100"`
+    HbLevel          *float64 `json:"hb_level,omitempty" binding:"omitempty,min=1,max=25"`
+    LengthCm            *float64            `json:"length_cm,omitempty"`
+    HeadCircumferenceCm *float64            `json:"head_circumference_cm,omitempty"`
+    MeasurementPosition MeasurementPosition `json:"measurement_position,omitempty"`
     // Add other fields as necessary
 }
 // NEW: Repository interfaces for your tables
@@ -286,6 +315,9 @@ type ClinicalFindingsRepository interface {
 type ClassificationRepository interface {
 	Create(ctx context.Context, classification *Classification) error
 	GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*Classification, error)
+	// ListByAssessmentID returns every classification of an assessment, most
+	// urgent first.
+	ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*Classification, error)
 	Upsert(ctx context.Context, classification *Classification) error
 }
 
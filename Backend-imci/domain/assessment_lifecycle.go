@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrIllegalTransition          = errors.New("illegal assessment status transition")
+	ErrTransitionNotPermitted     = errors.New("not permitted to make this status transition")
+	ErrAssessmentStatusChanged    = errors.New("assessment status changed in the meantime")
+	ErrCancellationReasonRequired = errors.New("a reason is required to cancel an assessment")
+	ErrMandatoryTreesIncomplete   = errors.New("mandatory assessment trees are not finished")
+)
+
+// TransitionActor is who triggers a status transition. The rule engines act
+// as the system; clinicians act on their own assessments.
+type TransitionActor string
+
+const (
+	ActorSystem    TransitionActor = "system"
+	ActorClinician TransitionActor = "clinician"
+	ActorAdmin     TransitionActor = "admin"
+)
+
+// assessmentTransitions lists, for each status, the statuses it may move to
+// and who may move it there. Completed and cancelled are final.
+var assessmentTransitions = map[AssessmentStatus]map[AssessmentStatus][]TransitionActor{
+	StatusDraft: {
+		StatusInProgress: {ActorSystem, ActorClinician},
+		StatusCancelled:  {ActorClinician, ActorAdmin},
+	},
+	StatusInProgress: {
+		StatusClassified: {ActorSystem},
+		StatusCompleted:  {ActorClinician},
+		StatusCancelled:  {ActorClinician, ActorAdmin},
+	},
+	StatusClassified: {
+		StatusCompleted: {ActorClinician},
+		StatusCancelled: {ActorClinician, ActorAdmin},
+	},
+}
+
+// assessmentStatusOrder orders AllowedTransitions.
+var assessmentStatusOrder = []AssessmentStatus{StatusDraft, StatusInProgress, StatusClassified, StatusCompleted, StatusCancelled}
+
+// TransitionError is a transition the state machine refuses. It unwraps to
+// ErrIllegalTransition or ErrTransitionNotPermitted.
+type TransitionError struct {
+	From  AssessmentStatus
+	To    AssessmentStatus
+	Actor TransitionActor
+	Err   error
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%v: %s -> %s by %s", e.Err, e.From, e.To, e.Actor)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
+// IncompleteTreesError lists the mandatory trees still to be finished. It
+// unwraps to ErrMandatoryTreesIncomplete.
+type IncompleteTreesError struct {
+	Missing []string
+}
+
+func (e *IncompleteTreesError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrMandatoryTreesIncomplete, strings.Join(e.Missing, ", "))
+}
+
+func (e *IncompleteTreesError) Unwrap() error {
+	return ErrMandatoryTreesIncomplete
+}
+
+// CheckTransition returns a *TransitionError unless actor may move an
+// assessment from from to to.
+func CheckTransition(from, to AssessmentStatus, actor TransitionActor) error {
+	actors, ok := assessmentTransitions[from][to]
+	if !ok {
+		return &TransitionError{From: from, To: to, Actor: actor, Err: ErrIllegalTransition}
+	}
+	for _, allowed := range actors {
+		if allowed == actor {
+			return nil
+		}
+	}
+	return &TransitionError{From: from, To: to, Actor: actor, Err: ErrTransitionNotPermitted}
+}
+
+// AllowedTransitions lists the statuses actor may move an assessment in
+// from to.
+func AllowedTransitions(from AssessmentStatus, actor TransitionActor) []AssessmentStatus {
+	allowed := []AssessmentStatus{}
+	for _, to := range assessmentStatusOrder {
+		if CheckTransition(from, to, actor) == nil {
+			allowed = append(allowed, to)
+		}
+	}
+	return allowed
+}
+
+// MandatoryTrees must be finished before an assessment can be completed:
+// the general danger signs and main symptoms of the IMNCI chart booklet,
+// then nutrition and anaemia for children and feeding for young infants.
+var MandatoryTrees = map[AssessmentType][]string{
+	TypeChild: {
+		"child_general_danger_signs",
+		"child_cough_difficult_breathing",
+		"child_diarrhea",
+		"child_fever",
+		"child_ear_problem",
+		"acute_malnutrition",
+		"child_anemia_check",
+	},
+	TypeYoungInfant: {
+		"very_severe_disease_check",
+		"jaundice_check",
+		"diarrhea_check",
+		"feeding_problem_underweight_check",
+	},
+}
+
+// AssessmentStatusChange is one entry of an assessment's status history.
+type AssessmentStatusChange struct {
+	ID           uuid.UUID        `json:"id"`
+	AssessmentID uuid.UUID        `json:"assessment_id"`
+	FromStatus   AssessmentStatus `json:"from_status"`
+	ToStatus     AssessmentStatus `json:"to_status"`
+	Actor        TransitionActor  `json:"actor"`
+	ChangedBy    *uuid.UUID       `json:"changed_by,omitempty"`
+	Reason       string           `json:"reason,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+type CancelAssessmentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AssessmentLifecycle is an assessment's status with what may happen next.
+type AssessmentLifecycle struct {
+	AssessmentID       uuid.UUID                 `json:"assessment_id"`
+	Status             AssessmentStatus          `json:"status"`
+	EndTime            *time.Time                `json:"end_time,omitempty"`
+	AllowedTransitions []AssessmentStatus        `json:"allowed_transitions"`
+	CompletedTrees     []string                  `json:"completed_trees"`
+	MissingTrees       []string                  `json:"missing_trees"`
+	History            []*AssessmentStatusChange `json:"history"`
+}
+
+type AssessmentLifecycleRepository interface {
+	// GetAssessment loads the identity and lifecycle fields of an assessment
+	// without the owner check.
+	GetAssessment(ctx context.Context, id uuid.UUID) (*Assessment, error)
+	// Transition moves the assessment to change.ToStatus if it is still in
+	// change.FromStatus, sets its end time when endTime is given and records
+	// change, all in one transaction. It returns ErrAssessmentStatusChanged
+	// when the status moved on in the meantime.
+	Transition(ctx context.Context, change *AssessmentStatusChange, endTime *time.Time) error
+	ListHistory(ctx context.Context, assessmentID uuid.UUID) ([]*AssessmentStatusChange, error)
+	MarkTreeCompleted(ctx context.Context, assessmentID uuid.UUID, treeID string, at time.Time) error
+	CompletedTrees(ctx context.Context, assessmentID uuid.UUID) ([]string, error)
+}
+
+type AssessmentLifecycleUsecase interface {
+	// FlowStarted moves a draft assessment to in progress when one of its
+	// trees is started, and refuses closed assessments.
+	FlowStarted(ctx context.Context, assessment *Assessment) error
+	// FlowFinished records that treeID was finished and, when it produced a
+	// classification, moves the assessment to classified.
+	FlowFinished(ctx context.Context, assessment *Assessment, treeID string, classified bool) error
+	// Complete requires every mandatory tree to be finished, finalises the
+	// visit's prescription and sets EndTime.
+	Complete(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string) (*AssessmentLifecycle, error)
+	Cancel(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string, reason string) (*AssessmentLifecycle, error)
+	GetLifecycle(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string) (*AssessmentLifecycle, error)
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReviewNotFound        = errors.New("assessment review not found")
+	ErrReviewClosed          = errors.New("assessment review is already signed off")
+	ErrSelfReview            = errors.New("supervisors cannot review their own assessments")
+	ErrEmptyAmendment        = errors.New("amendment does not change any answer or classification")
+	ErrReviewCommentRequired = errors.New("a comment is required")
+)
+
+type ReviewStatus string
+
+const (
+	// ReviewNotRequired is reported for assessments that never met the
+	// review criteria; no review is stored for them.
+	ReviewNotRequired ReviewStatus = "not_required"
+	ReviewPending     ReviewStatus = "pending"
+	ReviewApproved    ReviewStatus = "approved"
+	ReviewAmended     ReviewStatus = "amended"
+)
+
+// ReviewReason is a criterion that sends an assessment to supervisor review.
+type ReviewReason string
+
+const (
+	ReviewReasonEmergency         ReviewReason = "emergency_classification"
+	ReviewReasonDiscordantOffline ReviewReason = "discordant_offline_result"
+	ReviewReasonTrainee           ReviewReason = "trainee_clinician"
+)
+
+// ReviewReasons lists every review criterion, all enabled by default.
+var ReviewReasons = []ReviewReason{ReviewReasonEmergency, ReviewReasonDiscordantOffline, ReviewReasonTrainee}
+
+type ReviewActionType string
+
+const (
+	ReviewActionApprove ReviewActionType = "approve"
+	ReviewActionAmend   ReviewActionType = "amend"
+	ReviewActionComment ReviewActionType = "comment"
+)
+
+// ReviewedClassification is a classification as a supervisor amends it.
+// TreatmentPlans, when set, replaces the classification's treatments.
+type ReviewedClassification struct {
+	Disease        string              `json:"disease" binding:"required"`
+	Color          string              `json:"color" binding:"required"`
+	Details        string              `json:"details,omitempty"`
+	TreatmentPlans []ReviewedTreatment `json:"treatment_plans,omitempty" binding:"omitempty,dive"`
+}
+
+// ReviewedTreatment is a treatment a supervisor prescribes for an amended
+// classification.
+type ReviewedTreatment struct {
+	DrugName            string `json:"drug_name" binding:"required"`
+	Dosage              string `json:"dosage,omitempty"`
+	Frequency           string `json:"frequency,omitempty"`
+	Duration            string `json:"duration,omitempty"`
+	AdministrationRoute string `json:"administration_route,omitempty"`
+	IsPreReferral       bool   `json:"is_pre_referral,omitempty"`
+	Instructions        string `json:"instructions,omitempty"`
+}
+
+// AnswerChange is one answer a supervisor amended. Before is nil for answers
+// the clinician never gave.
+type AnswerChange struct {
+	NodeID string      `json:"node_id"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ClassificationChange is a classification a supervisor added, removed or
+// changed.
+type ClassificationChange struct {
+	Disease string                  `json:"disease"`
+	Change  string                  `json:"change"`
+	Before  *ReviewedClassification `json:"before,omitempty"`
+	After   *ReviewedClassification `json:"after,omitempty"`
+}
+
+// ReviewDiff is what an amendment changed against the clinician's record.
+type ReviewDiff struct {
+	Answers         []AnswerChange         `json:"answers"`
+	Classifications []ClassificationChange `json:"classifications"`
+}
+
+type ReviewAction struct {
+	ID         uuid.UUID        `json:"id"`
+	ReviewID   uuid.UUID        `json:"review_id"`
+	ReviewerID *uuid.UUID       `json:"reviewer_id,omitempty"`
+	Action     ReviewActionType `json:"action"`
+	Comment    string           `json:"comment,omitempty"`
+	Diff       *ReviewDiff      `json:"diff,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+type AssessmentReview struct {
+	ID                    uuid.UUID       `json:"id"`
+	AssessmentID          uuid.UUID       `json:"assessment_id"`
+	PatientID             uuid.UUID       `json:"patient_id"`
+	MedicalProfessionalID uuid.UUID       `json:"medical_professional_id"`
+	ClinicianName         string          `json:"clinician_name,omitempty"`
+	Facility              string          `json:"facility,omitempty"`
+	Reasons               []ReviewReason  `json:"reasons"`
+	Status                ReviewStatus    `json:"status"`
+	ReviewedBy            *uuid.UUID      `json:"reviewed_by,omitempty"`
+	ReviewedAt            *time.Time      `json:"reviewed_at,omitempty"`
+	CreatedAt             time.Time       `json:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+	Actions               []*ReviewAction `json:"actions,omitempty"`
+}
+
+// ReviewDetail is an assessment's review status with the clinician's
+// answers and classifications it covers.
+type ReviewDetail struct {
+	AssessmentID    uuid.UUID         `json:"assessment_id"`
+	Status          ReviewStatus      `json:"status"`
+	Review          *AssessmentReview `json:"review,omitempty"`
+	Answers         JSONB             `json:"answers"`
+	Classifications []*Classification `json:"classifications"`
+}
+
+type OfflineResultRequest struct {
+	Classifications []string `json:"classifications" binding:"required"`
+}
+
+type ReviewCommentRequest struct {
+	Comment string `json:"comment" binding:"required"`
+}
+
+type ApproveReviewRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// ReviewAmendment is what an amend action writes to the clinician's record.
+type ReviewAmendment struct {
+	AssessmentID uuid.UUID
+	// Answers replaces the stored answers; nil leaves them as they are.
+	Answers JSONB
+	Removed []uuid.UUID
+	Added   []*Classification
+	// Changed classifications lose the counselling given for them, which
+	// no longer matches, and get that of Counselings.
+	Changed []*Classification
+	// Plans replaces the treatment plans of each classification it has.
+	Plans map[uuid.UUID][]*TreatmentPlan
+	// Counselings is the advice for the changed and added classifications.
+	Counselings []*Counseling
+	// Audit records each removed, added and changed classification; it is
+	// written with the amendment.
+	Audit []*AuditEntry
+}
+
+// AmendReviewRequest overrides the given answers and, when Classifications
+// is set, replaces the classifications as a whole.
+type AmendReviewRequest struct {
+	Comment         string                   `json:"comment" binding:"required"`
+	Answers         JSONB                    `json:"answers,omitempty"`
+	Classifications []ReviewedClassification `json:"classifications,omitempty" binding:"omitempty,dive"`
+}
+
+type SetTraineeRequest struct {
+	IsTrainee bool `json:"is_trainee"`
+}
+
+type AssessmentReviewRepository interface {
+	// GetByAssessmentID returns the review with the clinician's name and
+	// facility, or ErrReviewNotFound.
+	GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*AssessmentReview, error)
+	// Save creates the review of an assessment or updates its reasons and
+	// status.
+	Save(ctx context.Context, review *AssessmentReview) error
+	// ListQueue returns reviews in status, oldest first; an empty facility
+	// covers every facility.
+	ListQueue(ctx context.Context, status ReviewStatus, facility string, limit int) ([]*AssessmentReview, error)
+	// RecordAction stores action and, unless status is empty, signs the
+	// review off with status. It returns ErrReviewClosed when the review was
+	// signed off in the meantime.
+	RecordAction(ctx context.Context, action *ReviewAction, status ReviewStatus) error
+	// Amend records action like RecordAction and, in the same transaction,
+	// applies amendment to the assessment's answers, classifications and
+	// treatment plans.
+	Amend(ctx context.Context, action *ReviewAction, status ReviewStatus, amendment *ReviewAmendment) error
+	ListActions(ctx context.Context, reviewID uuid.UUID) ([]*ReviewAction, error)
+	SaveOfflineResult(ctx context.Context, assessmentID uuid.UUID, classifications []string, at time.Time) error
+	// GetOfflineResult returns nil when the assessment never reported an
+	// offline result.
+	GetOfflineResult(ctx context.Context, assessmentID uuid.UUID) ([]string, error)
+	SetTrainee(ctx context.Context, medicalProfessionalID uuid.UUID, isTrainee bool) error
+}
+
+type AssessmentReviewUsecase interface {
+	// NotifyClassification re-evaluates the review criteria whenever the
+	// rule engines save a classification.
+	ClassificationNotifier
+	// Evaluate queues the assessment for review when it meets the enabled
+	// criteria. It returns nil when no review is needed.
+	Evaluate(ctx context.Context, assessment *Assessment) (*AssessmentReview, error)
+	ReportOfflineResult(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, classifications []string) (*ReviewDetail, error)
+	GetReview(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string) (*ReviewDetail, error)
+	ListQueue(ctx context.Context, medicalProfessionalID uuid.UUID, role string, status ReviewStatus, limit int) ([]*AssessmentReview, error)
+	Approve(ctx context.Context, assessmentID, reviewerID uuid.UUID, role string, comment string) (*ReviewDetail, error)
+	Amend(ctx context.Context, assessmentID, reviewerID uuid.UUID, role string, req *AmendReviewRequest) (*ReviewDetail, error)
+	Comment(ctx context.Context, assessmentID, reviewerID uuid.UUID, role string, comment string) (*ReviewDetail, error)
+	SetTrainee(ctx context.Context, medicalProfessionalID uuid.UUID, isTrainee bool) error
+}
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditView   AuditAction = "view"
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+type AuditEntity string
+
+const (
+	AuditPatient        AuditEntity = "patient"
+	AuditAssessment     AuditEntity = "assessment"
+	AuditClassification AuditEntity = "classification"
+	AuditPrescription   AuditEntity = "prescription"
+	AuditTreatmentPlan  AuditEntity = "treatment_plan"
+	AuditCounseling     AuditEntity = "counseling"
+)
+
+// RequestMeta describes the request behind an audited change. The HTTP
+// middleware puts it on the request context; changes made outside a request,
+// such as by the chat bots, are recorded without it.
+type RequestMeta struct {
+	ActorID   *uuid.UUID
+	ActorRole string
+	IP        string
+	UserAgent string
+	Method    string
+	Path      string
+	RequestID string
+}
+
+type requestMetaKey struct{}
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+func RequestMetaFrom(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+// AuditEntry is one record of the append-only audit log. Before and After
+// hold the entity as JSON text exactly as hashed.
+type AuditEntry struct {
+	Seq        int64           `json:"seq"`
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"`
+	ActorRole  string          `json:"actor_role,omitempty"`
+	Action     AuditAction     `json:"action"`
+	EntityType AuditEntity     `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	UserAgent  string          `json:"user_agent,omitempty"`
+	Method     string          `json:"method,omitempty"`
+	Path       string          `json:"path,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+// NewAuditEntry describes a change of entityID by the actor of the request on
+// ctx. Before and after are recorded as JSON; nil leaves them empty.
+func NewAuditEntry(ctx context.Context, action AuditAction, entityType AuditEntity, entityID uuid.UUID, before, after interface{}) (*AuditEntry, error) {
+	meta := RequestMetaFrom(ctx)
+	entry := &AuditEntry{
+		ID:         uuid.New(),
+		ActorID:    meta.ActorID,
+		ActorRole:  meta.ActorRole,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		IP:         meta.IP,
+		UserAgent:  meta.UserAgent,
+		Method:     meta.Method,
+		Path:       meta.Path,
+		RequestID:  meta.RequestID,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	var err error
+	if entry.Before, err = auditSnapshot(before); err != nil {
+		return nil, err
+	}
+	if entry.After, err = auditSnapshot(after); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// auditSnapshot renders an entity as the JSON kept in the log.
+func auditSnapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit snapshot: %w", err)
+	}
+	return raw, nil
+}
+
+// AuditGenesisHash is the PrevHash of the first entry.
+const AuditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// AuditHash chains entry to the entry before it: the SHA-256 of the previous
+// hash and every recorded field, so changing, removing or reordering any
+// entry breaks every hash after it. CreatedAt is hashed at the microsecond
+// precision the database keeps.
+func AuditHash(prevHash string, entry *AuditEntry) string {
+	actorID := ""
+	if entry.ActorID != nil {
+		actorID = entry.ActorID.String()
+	}
+
+	fields := []string{
+		prevHash,
+		entry.ID.String(),
+		actorID,
+		entry.ActorRole,
+		string(entry.Action),
+		string(entry.EntityType),
+		entry.EntityID.String(),
+		string(entry.Before),
+		string(entry.After),
+		entry.IP,
+		entry.UserAgent,
+		entry.Method,
+		entry.Path,
+		entry.RequestID,
+		entry.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	}
+
+	// Length prefixes keep the boundary between fields unambiguous.
+	hash := sha256.New()
+	for _, field := range fields {
+		fmt.Fprintf(hash, "%d:%s", len(field), field)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type AuditFilter struct {
+	EntityType AuditEntity
+	EntityID   *uuid.UUID
+	ActorID    *uuid.UUID
+	Action     AuditAction
+	From       *time.Time
+	To         *time.Time
+	// BeforeSeq pages backwards from the newest entries.
+	BeforeSeq int64
+	Limit     int
+}
+
+// AuditVerification is the result of re-hashing the audit chain. BrokenAt is
+// the first entry whose hash or link does not match. The chain cannot show
+// entries cut off its end, so HeadHash should be kept somewhere else and
+// compared on the next check.
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	HeadSeq  int64  `json:"head_seq"`
+	HeadHash string `json:"head_hash"`
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type AuditRepository interface {
+	// Append links entry to the newest entry, sets its PrevHash, Hash and
+	// Seq and stores it. Appends are serialised so the chain never forks.
+	Append(ctx context.Context, entry *AuditEntry) error
+	// List returns entries matching filter, newest first.
+	List(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error)
+	// Chain returns up to limit entries after afterSeq, oldest first.
+	Chain(ctx context.Context, afterSeq int64, limit int) ([]*AuditEntry, error)
+}
+
+// AuditRecorder records access to and changes of clinical data. A change
+// whose entry cannot be written returns the error, so the log never silently
+// misses it.
+type AuditRecorder interface {
+	Record(ctx context.Context, action AuditAction, entityType AuditEntity, entityID uuid.UUID, before, after interface{}) error
+}
+
+type AuditUsecase interface {
+	AuditRecorder
+	List(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error)
+	Verify(ctx context.Context) (*AuditVerification, error)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrChatSessionNotFound = errors.New("no assessment is running in this chat")
+	ErrChatUnavailable     = errors.New("chat assessments are not available")
+)
+
+// ChatButton is a choice offered with a chat reply. The ID is sent back as
+// the next message when the button is pressed.
+type ChatButton struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// ChatReply is one plain-text bot message. Each transport decides how to
+// render the buttons: Telegram uses an inline keyboard, WhatsApp reply
+// buttons or a list, and anything else a numbered list.
+type ChatReply struct {
+	Text    string       `json:"text"`
+	Buttons []ChatButton `json:"buttons,omitempty"`
+}
+
+// ChatMessenger delivers replies over one chat transport.
+type ChatMessenger interface {
+	SendChatReply(ctx context.Context, recipient string, reply *ChatReply) error
+}
+
+// ChatSession is where a conversation is in the assessment.
+type ChatSession struct {
+	Step                  string         `json:"step"`
+	MedicalProfessionalID uuid.UUID      `json:"medical_professional_id"`
+	PatientID             uuid.UUID      `json:"patient_id,omitempty"`
+	AssessmentID          uuid.UUID      `json:"assessment_id,omitempty"`
+	AssessmentType        AssessmentType `json:"assessment_type,omitempty"`
+	TreeID                string         `json:"tree_id,omitempty"`
+	NodeID                string         `json:"node_id,omitempty"`
+	// Choices are the button IDs last offered, so a numeric reply can pick
+	// one on transports without buttons.
+	Choices   []string  `json:"choices,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ChatSessionStore interface {
+	// Get returns ErrChatSessionNotFound when the conversation has no session.
+	Get(ctx context.Context, conversationID string) (*ChatSession, error)
+	Save(ctx context.Context, conversationID string, session *ChatSession, ttl time.Duration) error
+	Delete(ctx context.Context, conversationID string) error
+}
+
+// ChatAssessmentUsecase runs an IMCI assessment as a conversation.
+// conversationID identifies the chat on its transport, e.g. "telegram:42".
+type ChatAssessmentUsecase interface {
+	// HandleMessage returns ErrChatSessionNotFound for messages that are
+	// neither a chat command nor part of a running assessment.
+	HandleMessage(ctx context.Context, conversationID string, mp *MedicalProfessional, text string) ([]*ChatReply, error)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrTelegramNotLinked = errors.New("telegram chat is not linked to a medical professional")
+
+// FollowUp is a classification whose follow-up visit falls in a given window.
+type FollowUp struct {
+	AssessmentID uuid.UUID `json:"assessment_id"`
+	PatientID    uuid.UUID `json:"patient_id"`
+	PatientName  string    `json:"patient_name"`
+	Disease      string    `json:"disease"`
+	Color        string    `json:"color"`
+	FollowUpDate time.Time `json:"follow_up_date"`
+}
+
+type FollowUpRepository interface {
+	// ListDue returns the follow-ups of a medical professional's assessments
+	// due on or after from and before to.
+	ListDue(ctx context.Context, medicalProfessionalID uuid.UUID, from, to time.Time) ([]*FollowUp, error)
+}
+
+// PatientVisits is a patient together with the assessments the requesting
+// medical professional performed.
+type PatientVisits struct {
+	Patient     *Patient      `json:"patient"`
+	Assessments []*Assessment `json:"assessments"`
+}
+
+// ClassificationNotifier is told about every classification saved by the
+// rule engines.
+type ClassificationNotifier interface {
+	NotifyClassification(ctx context.Context, assessment *Assessment, classification *Classification)
+}
+
+// ClassificationNotifiers tells each of its notifiers in turn.
+type ClassificationNotifiers []ClassificationNotifier
+
+func (n ClassificationNotifiers) NotifyClassification(ctx context.Context, assessment *Assessment, classification *Classification) {
+	for _, notifier := range n {
+		if notifier != nil {
+			notifier.NotifyClassification(ctx, assessment, classification)
+		}
+	}
+}
+
+// ClinicalCompanionUsecase backs the chat bot commands clinicians use once
+// their chat is linked to their account.
+type ClinicalCompanionUsecase interface {
+	ClassificationNotifier
+	Authenticate(ctx context.Context, chatID int64) (*MedicalProfessional, error)
+	DueFollowUps(ctx context.Context, medicalProfessionalID uuid.UUID, day time.Time) ([]*FollowUp, error)
+	FindPatients(ctx context.Context, medicalProfessionalID uuid.UUID, name string) ([]*PatientVisits, error)
+	VisitSummaryPDF(ctx context.Context, medicalProfessionalID uuid.UUID, assessmentID uuid.UUID) ([]byte, error)
+}
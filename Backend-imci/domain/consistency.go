@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TreeAnswers is the snapshot of one completed tree's answers. The flow
+// answers of an assessment only hold the latest tree, so every tree is kept
+// here for checks across the visit.
+type TreeAnswers struct {
+	AssessmentID uuid.UUID `json:"assessment_id"`
+	TreeID       string    `json:"tree_id"`
+	Answers      JSONB     `json:"answers"`
+	CompletedAt  time.Time `json:"completed_at"`
+}
+
+type ConsistencyKind string
+
+const (
+	// ConsistencyContradiction is a sign answered differently by two trees.
+	// It is recorded for the clinician to resolve; nothing is changed.
+	ConsistencyContradiction ConsistencyKind = "contradiction"
+	// ConsistencyEscalation is a classification upgraded because a danger
+	// sign was found elsewhere in the visit.
+	ConsistencyEscalation ConsistencyKind = "escalation"
+)
+
+// ConsistencyFinding is a contradiction or escalation found across the trees
+// of a visit.
+type ConsistencyFinding struct {
+	ID               uuid.UUID       `json:"id"`
+	AssessmentID     uuid.UUID       `json:"assessment_id"`
+	Kind             ConsistencyKind `json:"kind"`
+	Code             string          `json:"code"`
+	TreeIDs          []string        `json:"tree_ids"`
+	Detail           string          `json:"detail"`
+	ClassificationID *uuid.UUID      `json:"classification_id,omitempty"`
+	FromDisease      string          `json:"from_disease,omitempty"`
+	ToDisease        string          `json:"to_disease,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+type ConsistencyRepository interface {
+	// SaveTreeAnswers stores a tree's answers, replacing an earlier snapshot
+	// of the same tree.
+	SaveTreeAnswers(ctx context.Context, snapshot *TreeAnswers) error
+	ListTreeAnswers(ctx context.Context, assessmentID uuid.UUID) ([]*TreeAnswers, error)
+	// SaveFinding stores a finding unless one with the same code was already
+	// recorded for the assessment and classification. It reports whether the
+	// finding is new.
+	SaveFinding(ctx context.Context, finding *ConsistencyFinding) (bool, error)
+	ListFindings(ctx context.Context, assessmentID uuid.UUID) ([]*ConsistencyFinding, error)
+	// ReplaceCare saves an escalated classification and replaces the
+	// treatment plans and counselling saved for it with plans and
+	// counselings, in one transaction with audit. Each deleted plan and
+	// counselling item is audited too, as a change by the actor on ctx.
+	ReplaceCare(ctx context.Context, classification *Classification, plans []*TreatmentPlan, counselings []*Counseling, audit []*AuditEntry) error
+}
+
+type ConsistencyUsecase interface {
+	Findings(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) ([]*ConsistencyFinding, error)
+}
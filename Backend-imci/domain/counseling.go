@@ -3,23 +3,122 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrCounselingNotFound       = errors.New("counseling item not found")
+	ErrCounselingNotConfirmable = errors.New("only structured counseling items can be confirmed")
+)
+
+// Advice types. mother_advice and follow_up_schedule are the free-text rows
+// written when a tree is classified; the others are the structured items
+// the clinician goes through with the caregiver one by one.
+const (
+	AdviceMotherAdvice     = "mother_advice"
+	AdviceFollowUpSchedule = "follow_up_schedule"
+	AdviceHomeFluids       = "home_fluids"
+	AdviceFeeding          = "feeding"
+	AdviceWhenToReturn     = "when_to_return"
+	AdviceReferral         = "referral"
+)
+
+// Answers to "did the caregiver understand?", stored in
+// UnderstoodByCaregiver.
+const (
+	UnderstoodYes       = "yes"
+	UnderstoodPartially = "partially"
+	UnderstoodNo        = "no"
+)
+
 type Counseling struct {
 	ID                    uuid.UUID  `json:"id"`
 	AssessmentID          uuid.UUID  `json:"assessment_id"`
+	ClassificationID      *uuid.UUID `json:"classification_id,omitempty"`
 	AdviceType            string     `json:"advice_type"`
 	Details               string     `json:"details"`
 	Language              string     `json:"language"`
 	UnderstoodByCaregiver *string    `json:"understood_by_caregiver,omitempty"`
-	QuestionsAsked        *string    `json:"questions_asked,omitempty"`
-	CreatedAt             time.Time  `json:"created_at"`
+	// QuestionsAsked holds the caregiver's questions, one per line.
+	QuestionsAsked *string    `json:"questions_asked,omitempty"`
+	UnderstoodAt   *time.Time `json:"understood_at,omitempty"`
+	CounseledBy    *uuid.UUID `json:"counseled_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IsStructured reports whether the item is one the caregiver confirms.
+func (c *Counseling) IsStructured() bool {
+	switch c.AdviceType {
+	case AdviceHomeFluids, AdviceFeeding, AdviceWhenToReturn, AdviceReferral:
+		return true
+	}
+	return false
+}
+
+// Understood reports whether the caregiver confirmed the item fully.
+func (c *Counseling) Understood() bool {
+	return c.UnderstoodByCaregiver != nil && *c.UnderstoodByCaregiver == UnderstoodYes
+}
+
+// CounselingCard groups the structured items of one classification.
+type CounselingCard struct {
+	ClassificationID uuid.UUID     `json:"classification_id"`
+	Disease          string        `json:"disease"`
+	Color            string        `json:"color"`
+	Items            []*Counseling `json:"items"`
+	Complete         bool          `json:"complete"`
+}
+
+// CounselingSession is everything to go through with the caregiver after an
+// assessment. Notes holds the free-text advice rows.
+type CounselingSession struct {
+	AssessmentID uuid.UUID         `json:"assessment_id"`
+	Cards        []*CounselingCard `json:"cards"`
+	Notes        []*Counseling     `json:"notes"`
+	Total        int               `json:"total"`
+	Understood   int               `json:"understood"`
+	Complete     bool              `json:"complete"`
+}
+
+type ConfirmCounselingRequest struct {
+	Understood string `json:"understood" binding:"required,oneof=yes partially no"`
+}
+
+type CaregiverQuestionRequest struct {
+	Question string `json:"question" binding:"required,max=1000"`
+}
+
+// CounselingCompletion summarises one clinician's counselling over a period.
+type CounselingCompletion struct {
+	MedicalProfessionalID uuid.UUID `json:"medical_professional_id"`
+	FullName              string    `json:"full_name"`
+	Assessments           int       `json:"assessments"`
+	CompletedAssessments  int       `json:"completed_assessments"`
+	Items                 int       `json:"items"`
+	UnderstoodItems       int       `json:"understood_items"`
+	ItemsWithQuestions    int       `json:"items_with_questions"`
+	CompletionRate        float64   `json:"completion_rate"`
 }
 
 type CounselingRepository interface {
 	Create(ctx context.Context, counseling *Counseling) error
 	GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*Counseling, error)
+	GetByID(ctx context.Context, id uuid.UUID, assessmentID uuid.UUID) (*Counseling, error)
+	ConfirmUnderstanding(ctx context.Context, counseling *Counseling) error
+	AddQuestion(ctx context.Context, id uuid.UUID, assessmentID uuid.UUID, question string) error
+	// CompletionReport covers the assessments created in [from, to). When
+	// medicalProfessionalID is set only that clinician is reported.
+	CompletionReport(ctx context.Context, from, to time.Time, medicalProfessionalID *uuid.UUID) ([]*CounselingCompletion, error)
+}
+
+type CounselingUsecase interface {
+	// GetSession returns the counselling items of an assessment, creating
+	// the structured items for classifications that do not have them yet.
+	GetSession(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) (*CounselingSession, error)
+	ConfirmItem(ctx context.Context, assessmentID, itemID uuid.UUID, req *ConfirmCounselingRequest, medicalProfessionalID uuid.UUID) (*Counseling, error)
+	RecordQuestion(ctx context.Context, assessmentID, itemID uuid.UUID, req *CaregiverQuestionRequest, medicalProfessionalID uuid.UUID) (*Counseling, error)
+	CompletionReport(ctx context.Context, from, to time.Time, medicalProfessionalID *uuid.UUID) ([]*CounselingCompletion, error)
 }
\ No newline at end of file
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDHIS2NotConfigured = errors.New("DHIS2 reporting is not configured")
+	ErrDHIS2PushFailed    = errors.New("DHIS2 rejected the data")
+)
+
+// DHIS2 export outcomes.
+const (
+	DHIS2ExportSuccess = "success"
+	DHIS2ExportWarning = "warning"
+	DHIS2ExportFailed  = "failed"
+)
+
+// IndicatorCount is the number of classifications with one name, for one age
+// group (assessment type), sex and facility.
+type IndicatorCount struct {
+	Classification string `json:"classification"`
+	AgeGroup       string `json:"age_group"`
+	Sex            string `json:"sex"`
+	Facility       string `json:"facility"`
+	Count          int    `json:"count"`
+}
+
+// DHIS2DataValue and DHIS2DataValueSet follow the DHIS2 Web API
+// dataValueSets payload, hence the camelCase names.
+type DHIS2DataValue struct {
+	DataElement          string `json:"dataElement"`
+	Period               string `json:"period"`
+	OrgUnit              string `json:"orgUnit"`
+	CategoryOptionCombo  string `json:"categoryOptionCombo"`
+	AttributeOptionCombo string `json:"attributeOptionCombo,omitempty"`
+	Value                string `json:"value"`
+}
+
+type DHIS2DataValueSet struct {
+	DataSet      string           `json:"dataSet,omitempty"`
+	CompleteDate string           `json:"completeDate,omitempty"`
+	DataValues   []DHIS2DataValue `json:"dataValues"`
+}
+
+// UnmappedIndicator is a count left out of the payload because the mapping
+// has no UID for it.
+type UnmappedIndicator struct {
+	IndicatorCount
+	Reason string `json:"reason"`
+}
+
+// DHIS2Report is the aggregation for a period and the payload built from it.
+type DHIS2Report struct {
+	Period       string               `json:"period"`
+	From         time.Time            `json:"from"`
+	To           time.Time            `json:"to"`
+	Counts       []*IndicatorCount    `json:"counts"`
+	DataValueSet *DHIS2DataValueSet   `json:"data_value_set"`
+	Unmapped     []*UnmappedIndicator `json:"unmapped"`
+}
+
+type DHIS2ImportSummary struct {
+	Status    string   `json:"status"`
+	Imported  int      `json:"imported"`
+	Updated   int      `json:"updated"`
+	Ignored   int      `json:"ignored"`
+	Deleted   int      `json:"deleted"`
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// DHIS2Export records one push of a period's data values.
+type DHIS2Export struct {
+	ID          uuid.UUID  `json:"id"`
+	Period      string     `json:"period"`
+	Status      string     `json:"status"`
+	DataValues  int        `json:"data_values"`
+	Imported    int        `json:"imported"`
+	Updated     int        `json:"updated"`
+	Ignored     int        `json:"ignored"`
+	Deleted     int        `json:"deleted"`
+	Error       string     `json:"error,omitempty"`
+	TriggeredBy *uuid.UUID `json:"triggered_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type PushDHIS2Request struct {
+	Period string `json:"period" binding:"required"`
+}
+
+type DHIS2Repository interface {
+	// CountClassifications aggregates classifications of assessments started
+	// in [from, to), leaving out cancelled assessments.
+	CountClassifications(ctx context.Context, from, to time.Time) ([]*IndicatorCount, error)
+	RecordExport(ctx context.Context, export *DHIS2Export) error
+	ListExports(ctx context.Context, limit int) ([]*DHIS2Export, error)
+	HasSuccessfulExport(ctx context.Context, period string) (bool, error)
+}
+
+// DHIS2Client posts data value sets to a DHIS2 instance.
+type DHIS2Client interface {
+	PushDataValueSet(ctx context.Context, set *DHIS2DataValueSet) (*DHIS2ImportSummary, error)
+}
+
+type DHIS2Usecase interface {
+	BuildReport(ctx context.Context, period string) (*DHIS2Report, error)
+	Push(ctx context.Context, period string, triggeredBy *uuid.UUID) (*DHIS2Export, error)
+	// PushDue pushes the previous month once the configured day of the month
+	// has been reached. It returns nil when nothing is due.
+	PushDue(ctx context.Context, now time.Time) (*DHIS2Export, error)
+	ListExports(ctx context.Context, limit int) ([]*DHIS2Export, error)
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// FHIRExportUsecase serialises IMCI records as FHIR R4 bundles for the
+// regional health information exchange. baseURL makes each entry's fullUrl
+// absolute.
+type FHIRExportUsecase interface {
+	// PatientEverything returns the patient with all of their assessments.
+	PatientEverything(ctx context.Context, patientID uuid.UUID, medicalProfessionalID uuid.UUID, baseURL string) ([]byte, error)
+	// EncounterEverything returns one assessment with its patient.
+	EncounterEverything(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID, baseURL string) ([]byte, error)
+}
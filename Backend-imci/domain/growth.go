@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGrowthNotComputable = errors.New("growth z-scores cannot be computed for this assessment")
+)
+
+// GrowthZScores holds the WHO z-scores computed for one assessment. A nil
+// value means the indicator could not be computed (missing measurement, sex
+// unknown or age outside the WHO reference range).
+type GrowthZScores struct {
+	WeightForAge             *float64 `json:"weight_for_age,omitempty"`
+	LengthHeightForAge       *float64 `json:"length_height_for_age,omitempty"`
+	WeightForLengthHeight    *float64 `json:"weight_for_length_height,omitempty"`
+	WeightForLengthIndicator string   `json:"weight_for_length_indicator,omitempty"`
+	MUACForAge               *float64 `json:"muac_for_age,omitempty"`
+}
+
+// GrowthPoint is one entry on a patient's growth curve.
+type GrowthPoint struct {
+	AssessmentID        uuid.UUID           `json:"assessment_id"`
+	MeasuredAt          time.Time           `json:"measured_at"`
+	AgeDays             int                 `json:"age_days"`
+	AgeMonths           float64             `json:"age_months"`
+	WeightKg            float64             `json:"weight_kg"`
+	MUAC                *float64            `json:"muac,omitempty"`
+	LengthCm            *float64            `json:"length_cm,omitempty"`
+	HeadCircumferenceCm *float64            `json:"head_circumference_cm,omitempty"`
+	MeasurementPosition MeasurementPosition `json:"measurement_position,omitempty"`
+	ZScores             GrowthZScores       `json:"z_scores"`
+}
+
+type GrowthHistory struct {
+	PatientID uuid.UUID      `json:"patient_id"`
+	Gender    Gender         `json:"gender"`
+	Points    []*GrowthPoint `json:"points"`
+}
+
+// TreeAnswerProvider supplies answers for assessment tree nodes that can be
+// derived from data already recorded, so the clinician is not asked again.
+type TreeAnswerProvider interface {
+	ProvideAnswers(ctx context.Context, assessment *Assessment, treeID string) (map[string]interface{}, error)
+}
+
+type GrowthUsecase interface {
+	TreeAnswerProvider
+	ComputeForAssessment(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) (*GrowthPoint, error)
+	GetPatientGrowthHistory(ctx context.Context, patientID uuid.UUID, medicalProfessionalID uuid.UUID) (*GrowthHistory, error)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownVaccine          = errors.New("unknown vaccine")
+	ErrImmunizationNotFound    = errors.New("immunization record not found")
+	ErrVaccineAlreadyRecorded  = errors.New("this vaccine dose is already recorded for the patient")
+	ErrInvalidImmunizationDate = errors.New("dose date must be between the patient's date of birth and today")
+)
+
+// ImmunizationRecord is one vaccine dose given to a patient.
+type ImmunizationRecord struct {
+	ID         uuid.UUID  `json:"id"`
+	PatientID  uuid.UUID  `json:"patient_id"`
+	Vaccine    string     `json:"vaccine"`
+	DoseDate   time.Time  `json:"dose_date"`
+	LotNumber  string     `json:"lot_number,omitempty"`
+	Facility   string     `json:"facility,omitempty"`
+	RecordedBy *uuid.UUID `json:"recorded_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type RecordImmunizationRequest struct {
+	Vaccine   string    `json:"vaccine" binding:"required"`
+	DoseDate  time.Time `json:"dose_date" binding:"required"`
+	LotNumber string    `json:"lot_number,omitempty"`
+	Facility  string    `json:"facility,omitempty"`
+}
+
+// VaccineDue is the computed EPI status of one scheduled dose.
+type VaccineDue struct {
+	Vaccine             string     `json:"vaccine"`
+	Antigen             string     `json:"antigen"`
+	Status              string     `json:"status"`
+	DueDate             time.Time  `json:"due_date"`
+	GivenOn             *time.Time `json:"given_on,omitempty"`
+	Dosage              string     `json:"dosage"`
+	AdministrationRoute string     `json:"administration_route"`
+	Site                string     `json:"site"`
+}
+
+type ImmunizationSchedule struct {
+	PatientID uuid.UUID     `json:"patient_id"`
+	AsOf      time.Time     `json:"as_of"`
+	DueToday  []*VaccineDue `json:"due_today"`
+	Next      []*VaccineDue `json:"next"`
+	Doses     []*VaccineDue `json:"doses"`
+}
+
+type ImmunizationRepository interface {
+	Create(ctx context.Context, record *ImmunizationRecord) error
+	GetByPatientID(ctx context.Context, patientID uuid.UUID) ([]*ImmunizationRecord, error)
+	Delete(ctx context.Context, id uuid.UUID, patientID uuid.UUID) error
+}
+
+type ImmunizationUsecase interface {
+	TreeAnswerProvider
+	RecordDose(ctx context.Context, patientID uuid.UUID, req *RecordImmunizationRequest, medicalProfessionalID uuid.UUID) (*ImmunizationRecord, error)
+	GetRecords(ctx context.Context, patientID uuid.UUID) ([]*ImmunizationRecord, error)
+	DeleteRecord(ctx context.Context, patientID uuid.UUID, recordID uuid.UUID) error
+	GetSchedule(ctx context.Context, patientID uuid.UUID, asOf time.Time) (*ImmunizationSchedule, error)
+}
@@ -0,0 +1,67 @@
+// domain/login_attempt.go
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+// LoginLockedError is returned while a phone number or IP address is
+// throttled after failed logins.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s; try again in %s", ErrLoginLocked.Error(), e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+// Login attempt scopes.
+const (
+	LoginScopePhone = "phone"
+	LoginScopeIP    = "ip"
+)
+
+// LoginAttemptStore counts failed logins and holds temporary blocks, keyed by
+// scope (phone or IP) and identifier.
+type LoginAttemptStore interface {
+	IncrementFailures(ctx context.Context, scope, id string, window time.Duration) (int64, error)
+	Block(ctx context.Context, scope, id string, duration time.Duration) error
+	BlockedFor(ctx context.Context, scope, id string) (time.Duration, error)
+	Reset(ctx context.Context, scope, id string) error
+}
+
+// Login audit events.
+const (
+	LoginEventUnknownPhone  = "unknown_phone"
+	LoginEventWrongPassword = "wrong_password"
+	LoginEventThrottled     = "throttled"
+	LoginEventLockedOut     = "locked_out"
+	LoginEventUnlocked      = "unlocked"
+)
+
+type LoginAuditEntry struct {
+	ID                    uuid.UUID  `json:"id"`
+	Phone                 string     `json:"phone"`
+	MedicalProfessionalID *uuid.UUID `json:"medical_professional_id,omitempty"`
+	IPAddress             string     `json:"ip_address"`
+	Device                string     `json:"device"`
+	Event                 string     `json:"event"`
+	PerformedBy           *uuid.UUID `json:"performed_by,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type LoginAuditRepository interface {
+	Record(ctx context.Context, entry *LoginAuditEntry) error
+	List(ctx context.Context, phone string, limit int) ([]*LoginAuditEntry, error)
+}
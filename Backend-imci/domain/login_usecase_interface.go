@@ -1,9 +1,18 @@
 // domain/interfaces/auth_usecase.go
 package domain
 
-import "context"
+import (
+    "context"
+
+    "github.com/google/uuid"
+)
 
 type LoginUsecase interface {
-    Login(ctx context.Context, request *LoginRequest) (*LoginResponse, error)
-    RefreshToken(ctx context.Context, refreshToken string) (*LoginResponse, error)
+    Login(ctx context.Context, request *LoginRequest, meta SessionMetadata) (*LoginResponse, error)
+    RefreshToken(ctx context.Context, refreshToken string, meta SessionMetadata) (*LoginResponse, error)
+    ListSessions(ctx context.Context, medicalProfessionalID uuid.UUID, currentSessionID uuid.UUID) ([]*RefreshSession, error)
+    RevokeSession(ctx context.Context, medicalProfessionalID uuid.UUID, sessionID uuid.UUID) error
+    RevokeOtherSessions(ctx context.Context, medicalProfessionalID uuid.UUID, currentSessionID uuid.UUID) error
+    UnlockLogin(ctx context.Context, phone string, adminID uuid.UUID) error
+    ListLoginAudit(ctx context.Context, phone string, limit int) ([]*LoginAuditEntry, error)
 }
\ No newline at end of file
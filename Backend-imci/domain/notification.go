@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownNotificationChannel = errors.New("unknown notification channel")
+	// ErrRecipientUnreachable means the channel works but cannot reach this
+	// phone number, e.g. a Telegram account that was never linked.
+	ErrRecipientUnreachable  = errors.New("recipient is not reachable on this channel")
+	ErrNoNotificationChannel = errors.New("no notification channel could deliver the message")
+)
+
+// Notification channel names. They double as the per-user preference stored
+// on the medical professional.
+const (
+	ChannelTelegram = "telegram"
+	ChannelWhatsApp = "whatsapp"
+	ChannelSMS      = "sms"
+)
+
+type NotificationPurpose string
+
+const (
+	PurposeSignupOTP        NotificationPurpose = "signup_otp"
+	PurposePasswordResetOTP NotificationPurpose = "password_reset_otp"
+	PurposeOutbreakAlert    NotificationPurpose = "outbreak_alert"
+	PurposeClinicalAlert    NotificationPurpose = "clinical_alert"
+)
+
+// IsAlert reports whether the notification carries a message in Text rather
+// than a one-time code.
+func (p NotificationPurpose) IsAlert() bool {
+	return p == PurposeOutbreakAlert || p == PurposeClinicalAlert
+}
+
+// OTPNotification is a one-time code to deliver to a phone number. Alerts
+// carry their message in Text instead of a code. Language is the
+// recipient's preferred language for channels that send templates; empty
+// means English.
+type OTPNotification struct {
+	Phone     string
+	Code      string
+	Text      string
+	Language  string
+	Purpose   NotificationPurpose
+	ExpiresAt time.Time
+}
+
+// NotificationChannel delivers one-time codes over a single transport.
+type NotificationChannel interface {
+	Name() string
+	// Send returns ErrRecipientUnreachable when the phone number cannot be
+	// reached on this channel, so the dispatcher can fall back quietly.
+	Send(ctx context.Context, n *OTPNotification) error
+}
+
+// NotificationDispatcher delivers a notification on the preferred channel
+// and falls back through the configured order, logging every attempt.
+type NotificationDispatcher interface {
+	// Deliver returns the name of the channel that delivered the message.
+	Deliver(ctx context.Context, n *OTPNotification, preferred string) (string, error)
+	Channels() []string
+	Has(channel string) bool
+}
+
+// Delivery statuses.
+const (
+	DeliverySent        = "sent"
+	DeliveryFailed      = "failed"
+	DeliveryUnreachable = "unreachable"
+)
+
+// NotificationDelivery is one attempt to deliver a notification.
+type NotificationDelivery struct {
+	ID        uuid.UUID           `json:"id"`
+	Phone     string              `json:"phone"`
+	Channel   string              `json:"channel"`
+	Purpose   NotificationPurpose `json:"purpose"`
+	Status    string              `json:"status"`
+	Error     string              `json:"error,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+type NotificationDeliveryRepository interface {
+	Record(ctx context.Context, delivery *NotificationDelivery) error
+	List(ctx context.Context, phone string, limit int) ([]*NotificationDelivery, error)
+}
+
+type SetNotificationChannelRequest struct {
+	Channel string `json:"channel" binding:"required"`
+	// Language, when given, also sets the language of templated messages.
+	Language string `json:"language,omitempty"`
+}
+
+type NotificationUsecase interface {
+	AvailableChannels() []string
+	SetPreferredChannel(ctx context.Context, medicalProfessionalID uuid.UUID, channel, language string) error
+	ListDeliveries(ctx context.Context, phone string, limit int) ([]*NotificationDelivery, error)
+}
+
+// ResolveChannel maps a requested channel onto a channel name, honouring the
+// legacy use_whatsapp flag when no channel was given.
+func ResolveChannel(channel string, useWhatsApp bool) string {
+	if channel != "" {
+		return channel
+	}
+	if useWhatsApp {
+		return ChannelWhatsApp
+	}
+	return ChannelTelegram
+}
@@ -3,14 +3,23 @@ package domain
 
 import "time"
 
+// OTP is a pending signup: the registration details held until the phone
+// number is verified. Only a bcrypt hash of the password is kept, and rows
+// are purged once they expire.
 type OTP struct {
 	ID        string    `json:"id"`
 	Phone     string    `json:"phone"`
-	Code      string    `json:"code"`
+	Code      string    `json:"-"`
 	Role      string    `json:"role,omitempty"`
 	FacilityName string    `json:"facility_name,omitempty"`
 	FullName  string    `json:"full_name" `
-	Password  string    `json:"password"`
+	PasswordHash string    `json:"-"`
+	UseWhatsApp  bool      `json:"use_whatsapp"`
+	// Channel is the OTP channel chosen at signup, kept as the account's
+	// preference once verified.
+	Channel      string    `json:"channel"`
+	// Language is the language chosen at signup for templated messages.
+	Language     string    `json:"language,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
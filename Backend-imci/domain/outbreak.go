@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrOutbreakAlertNotFound = errors.New("outbreak alert not found")
+
+// Outbreak alert statuses.
+const (
+	OutbreakAlertOpen         = "open"
+	OutbreakAlertAcknowledged = "acknowledged"
+)
+
+// DailySignalCount is how many classifications matching a watched signal
+// were given at one facility on one day.
+type DailySignalCount struct {
+	Facility string
+	Signal   string
+	Date     time.Time
+	Count    int
+}
+
+// OutbreakAlert is a day on which a signal rose above its baseline at a
+// facility.
+type OutbreakAlert struct {
+	ID             uuid.UUID  `json:"id"`
+	Facility       string     `json:"facility"`
+	Signal         string     `json:"signal"`
+	AlertDate      time.Time  `json:"alert_date"`
+	Method         string     `json:"method"`
+	Observed       int        `json:"observed"`
+	Baseline       float64    `json:"baseline"`
+	Statistic      float64    `json:"statistic"`
+	Threshold      float64    `json:"threshold"`
+	Status         string     `json:"status"`
+	Notified       int        `json:"notified"`
+	AcknowledgedBy *uuid.UUID `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type OutbreakRepository interface {
+	// DailyCounts counts the classifications of assessments started in
+	// [from, to) whose name contains one of signals, per facility and day.
+	// Days without cases are left out.
+	DailyCounts(ctx context.Context, signals []string, from, to time.Time) ([]*DailySignalCount, error)
+	// CreateAlert stores alert unless the facility already has one for the
+	// signal, day and method, and reports whether it was stored.
+	CreateAlert(ctx context.Context, alert *OutbreakAlert) (bool, error)
+	SetNotified(ctx context.Context, id uuid.UUID, notified int) error
+	ListAlerts(ctx context.Context, status string, limit int) ([]*OutbreakAlert, error)
+	Acknowledge(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID, at time.Time) error
+}
+
+type OutbreakUsecase interface {
+	// Detect tests day at every facility, stores new alerts and sends them
+	// to the district focal persons. It returns the new alerts.
+	Detect(ctx context.Context, day time.Time) ([]*OutbreakAlert, error)
+	ListAlerts(ctx context.Context, status string, limit int) ([]*OutbreakAlert, error)
+	Acknowledge(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID) error
+}
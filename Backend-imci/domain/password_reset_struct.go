@@ -19,6 +19,8 @@ type PasswordResetRequest struct {
 type ForgotPasswordRequest struct {
 	Phone       string `json:"phone" binding:"required"`
 	UseWhatsApp bool   `json:"use_whatsapp"`
+	// Channel overrides the account's preferred OTP channel.
+	Channel     string `json:"channel"`
 }
 
 type VerifyResetOTPRequest struct {
@@ -12,6 +12,7 @@ type PatientRepository interface {
 	Create(ctx context.Context, patient *Patient) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Patient, error)
 	GetAll(ctx context.Context, page, perPage int) ([]*Patient, int, error)
+	SearchByName(ctx context.Context, name string, limit int) ([]*Patient, error)
 	Update(ctx context.Context, patient *Patient) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPrescriptionNotFound = errors.New("prescription not found")
+
+// PrescriptionItem is one line of a visit's prescription: the treatment plan
+// it prescribes and the plans of the same drug, route and pre-referral flag
+// it stands for.
+type PrescriptionItem struct {
+	ID                  uuid.UUID   `json:"id"`
+	Position            int         `json:"position"`
+	DrugKey             string      `json:"drug_key,omitempty"`
+	DrugName            string      `json:"drug_name"`
+	Dosage              string      `json:"dosage"`
+	Frequency           string      `json:"frequency"`
+	Duration            string      `json:"duration"`
+	AdministrationRoute string      `json:"administration_route"`
+	IsPreReferral       bool        `json:"is_pre_referral"`
+	Instructions        string      `json:"instructions,omitempty"`
+	TreatmentPriority   int         `json:"treatment_priority"`
+	TreatmentPlanIDs    []uuid.UUID `json:"treatment_plan_ids"`
+	ClassificationIDs   []uuid.UUID `json:"classification_ids"`
+}
+
+type PrescriptionWarningKind string
+
+const (
+	WarningInteraction      PrescriptionWarningKind = "interaction"
+	WarningDuplicateClass   PrescriptionWarningKind = "duplicate_class"
+	WarningContraindication PrescriptionWarningKind = "contraindication"
+	// WarningRegimenConflict flags an item merged from plans that differ in
+	// dosage, frequency or duration; only the first plan's is prescribed.
+	WarningRegimenConflict PrescriptionWarningKind = "regimen_conflict"
+)
+
+type PrescriptionWarning struct {
+	Kind    PrescriptionWarningKind `json:"kind"`
+	Drugs   []string                `json:"drugs"`
+	Message string                  `json:"message"`
+}
+
+// Prescription is the visit's final prescription: its treatment plans
+// merged per drug, route and pre-referral flag and ordered by urgency.
+type Prescription struct {
+	ID           uuid.UUID             `json:"id"`
+	AssessmentID uuid.UUID             `json:"assessment_id"`
+	Items        []*PrescriptionItem   `json:"items"`
+	Warnings     []PrescriptionWarning `json:"warnings"`
+	GeneratedBy  *uuid.UUID            `json:"generated_by,omitempty"`
+	GeneratedAt  time.Time             `json:"generated_at"`
+}
+
+type PrescriptionRepository interface {
+	// Save stores the prescription of an assessment, replacing an earlier
+	// one.
+	Save(ctx context.Context, prescription *Prescription) error
+	GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*Prescription, error)
+}
+
+// PrescriptionFinalizer consolidates and stores a visit's prescription when
+// the assessment is completed.
+type PrescriptionFinalizer interface {
+	Finalize(ctx context.Context, assessment *Assessment, generatedBy uuid.UUID) (*Prescription, error)
+}
+
+type PrescriptionUsecase interface {
+	PrescriptionFinalizer
+	// Consolidate regenerates and stores the prescription of an assessment
+	// the clinician owns.
+	Consolidate(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) (*Prescription, error)
+	Get(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) (*Prescription, error)
+}
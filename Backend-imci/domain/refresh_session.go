@@ -0,0 +1,57 @@
+// domain/refresh_session.go
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used; session revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// RefreshSession is one logged-in device. Every refresh rotates the token
+// stored against the session; presenting an older token of the same session
+// means it was copied, and the whole session is revoked.
+type RefreshSession struct {
+	ID                    uuid.UUID  `json:"id"`
+	MedicalProfessionalID uuid.UUID  `json:"medical_professional_id"`
+	TokenHash             string     `json:"-"`
+	Device                string     `json:"device"`
+	IPAddress             string     `json:"ip_address"`
+	CreatedAt             time.Time  `json:"created_at"`
+	LastUsedAt            time.Time  `json:"last_used_at"`
+	ExpiresAt             time.Time  `json:"expires_at"`
+	RevokedAt             *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason         string     `json:"revoked_reason,omitempty"`
+	Current               bool       `json:"current"`
+}
+
+// SessionMetadata describes the client a session was opened or refreshed from.
+type SessionMetadata struct {
+	Device    string
+	IPAddress string
+}
+
+type RefreshSessionRepository interface {
+	Create(ctx context.Context, session *RefreshSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*RefreshSession, error)
+	// Rotate swaps the session's token hash only if it still holds oldHash,
+	// returning ErrRefreshTokenReused otherwise.
+	Rotate(ctx context.Context, id uuid.UUID, oldHash, newHash string, meta SessionMetadata, expiresAt time.Time) error
+	ListActive(ctx context.Context, medicalProfessionalID uuid.UUID) ([]*RefreshSession, error)
+	Revoke(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID, reason string) error
+	RevokeAll(ctx context.Context, medicalProfessionalID uuid.UUID, except *uuid.UUID, reason string) error
+}
+
+// SessionChecker tells the auth middleware whether the session an access
+// token was issued for is still active, so revoking a session signs its
+// device out before the access token expires.
+type SessionChecker interface {
+	IsActive(ctx context.Context, id uuid.UUID) (bool, error)
+}
@@ -13,12 +13,14 @@ type MedicalProfessionalRepository interface {
 	Update(ctx context.Context, professional *MedicalProfessional) error
 	Delete(ctx context.Context, id uuid.UUID) error                              
 	GetAll(ctx context.Context) ([]*MedicalProfessional, error)
+	GetByFacilityAndRole(ctx context.Context, facility string, role string) ([]*MedicalProfessional, error)
 }
 
 type OtpRepository interface {
 	GetOtpByPhone(ctx context.Context, phone string) (*OTP, error)
 	SaveOTP(ctx context.Context, otp *OTP) error
 	DeleteOTP(ctx context.Context, phone string) error
+	PurgeExpired(ctx context.Context) (int64, error)
 }
 
 
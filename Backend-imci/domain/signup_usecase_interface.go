@@ -10,8 +10,12 @@ import (
 type SignupUsecase interface {
 	GetMedicalProfessionalByPhone(ctx context.Context, phone string) (*MedicalProfessional, error)
 	PrepareSignupOTP(ctx context.Context, form *SignupForm) (*OTP, error)
-	SendWhatsAppOTP(ctx context.Context, form *SignupForm) (*OTP, error)
+	SendSignupOTP(ctx context.Context, form *SignupForm) (*OTP, error)
 	GetOtpByPhone(ctx context.Context, phone string) (*OTP, error)
 	VerifyOtp(ctx context.Context, otp *VerifyOtp) (*OTP, error)
 	RegisterMedicalProfessional(ctx context.Context, form *SignupForm) (uuid.UUID, error)
+	// CompleteSignup verifies the OTP and creates the account from the
+	// pending signup, so the password never leaves the server again.
+	CompleteSignup(ctx context.Context, otp *VerifyOtp) (uuid.UUID, error)
+	PurgeExpiredSignups(ctx context.Context) (int64, error)
 }
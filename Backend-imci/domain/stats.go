@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidStatsRange = errors.New("invalid statistics date range")
+	ErrNoFacility        = errors.New("medical professional has no facility")
+)
+
+// StatsFilter selects the assessments started in [From, To) by clinicians
+// of one facility; an empty Facility covers every facility.
+type StatsFilter struct {
+	Facility string
+	From     time.Time
+	To       time.Time
+}
+
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+type ColorCount struct {
+	Color string `json:"color"`
+	Count int    `json:"count"`
+}
+
+// ClassificationCount is how often a classification was given to one age
+// group (assessment type).
+type ClassificationCount struct {
+	Classification string `json:"classification"`
+	Color          string `json:"color"`
+	AgeGroup       string `json:"age_group,omitempty"`
+	Count          int    `json:"count"`
+}
+
+type AgeGroupConditions struct {
+	AgeGroup   string                 `json:"age_group"`
+	Conditions []*ClassificationCount `json:"conditions"`
+}
+
+// ReferralStats covers assessments with an urgent-referral classification.
+// An emergency counts as referred once a pre-referral treatment is recorded
+// or the caregiver has confirmed the referral advice.
+type ReferralStats struct {
+	Emergencies int     `json:"emergencies"`
+	Referred    int     `json:"referred"`
+	Rate        float64 `json:"rate"`
+}
+
+// FollowUpCompliance covers assessments whose follow-up window has closed.
+// A follow-up is attended when the child is seen again from the day before
+// the follow-up date until GraceDays after it.
+type FollowUpCompliance struct {
+	Due       int     `json:"due"`
+	Attended  int     `json:"attended"`
+	Rate      float64 `json:"rate"`
+	GraceDays int     `json:"grace_days"`
+}
+
+// AssessmentDuration is measured from StartTime to EndTime over finished
+// assessments.
+type AssessmentDuration struct {
+	Assessments    int      `json:"assessments"`
+	AverageMinutes *float64 `json:"average_minutes,omitempty"`
+	MedianMinutes  *float64 `json:"median_minutes,omitempty"`
+}
+
+type FacilityStats struct {
+	Facility                   string                 `json:"facility,omitempty"`
+	From                       time.Time              `json:"from"`
+	To                         time.Time              `json:"to"`
+	TotalAssessments           int                    `json:"total_assessments"`
+	AssessmentsPerDay          []*DailyCount          `json:"assessments_per_day"`
+	ClassificationsByColor     []*ColorCount          `json:"classifications_by_color"`
+	ClassificationDistribution []*ClassificationCount `json:"classification_distribution"`
+	Referrals                  *ReferralStats         `json:"referrals"`
+	FollowUp                   *FollowUpCompliance    `json:"follow_up"`
+	Duration                   *AssessmentDuration    `json:"duration"`
+	TopConditions              []*AgeGroupConditions  `json:"top_conditions"`
+}
+
+type FacilitySummary struct {
+	Facility    string `json:"facility"`
+	Clinicians  int    `json:"clinicians"`
+	Assessments int    `json:"assessments"`
+}
+
+type StatsRepository interface {
+	AssessmentsPerDay(ctx context.Context, filter StatsFilter) ([]*DailyCount, error)
+	// ClassificationCounts groups classifications by name, colour and age
+	// group, most frequent first.
+	ClassificationCounts(ctx context.Context, filter StatsFilter) ([]*ClassificationCount, error)
+	Referrals(ctx context.Context, filter StatsFilter) (*ReferralStats, error)
+	FollowUpCompliance(ctx context.Context, filter StatsFilter, now time.Time, graceDays int) (*FollowUpCompliance, error)
+	AssessmentDuration(ctx context.Context, filter StatsFilter) (*AssessmentDuration, error)
+	ListFacilities(ctx context.Context, from, to time.Time) ([]*FacilitySummary, error)
+}
+
+type StatsUsecase interface {
+	// GetFacilityStats reports on the clinician's own facility; admins may
+	// choose another facility, or every facility with an empty name.
+	GetFacilityStats(ctx context.Context, medicalProfessionalID uuid.UUID, isAdmin bool, facility string, from, to time.Time) (*FacilityStats, error)
+	ListFacilities(ctx context.Context, from, to time.Time) ([]*FacilitySummary, error)
+}
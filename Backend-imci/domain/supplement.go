@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownSupplement     = errors.New("unknown supplement")
+	ErrSupplementNotFound    = errors.New("supplement record not found")
+	ErrInvalidSupplementDate = errors.New("dose date must be between the patient's date of birth and today")
+)
+
+// SupplementRecord is one Vitamin A or deworming dose given to a patient.
+type SupplementRecord struct {
+	ID         uuid.UUID  `json:"id"`
+	PatientID  uuid.UUID  `json:"patient_id"`
+	Supplement string     `json:"supplement"`
+	Dose       string     `json:"dose"`
+	DoseDate   time.Time  `json:"dose_date"`
+	Facility   string     `json:"facility,omitempty"`
+	RecordedBy *uuid.UUID `json:"recorded_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// RecordSupplementRequest records a supplement dose. When Dose is empty the
+// recommended dose for the child's age is stored.
+type RecordSupplementRequest struct {
+	Supplement string    `json:"supplement" binding:"required,oneof=vitamin_a deworming"`
+	Dose       string    `json:"dose,omitempty"`
+	DoseDate   time.Time `json:"dose_date" binding:"required"`
+	Facility   string    `json:"facility,omitempty"`
+}
+
+type SupplementEligibility struct {
+	Supplement          string     `json:"supplement"`
+	Eligible            bool       `json:"eligible"`
+	Due                 bool       `json:"due"`
+	LastDoseDate        *time.Time `json:"last_dose_date,omitempty"`
+	NextDueDate         *time.Time `json:"next_due_date,omitempty"`
+	Drug                string     `json:"drug"`
+	RecommendedDose     string     `json:"recommended_dose"`
+	AdministrationRoute string     `json:"administration_route"`
+}
+
+type SupplementStatus struct {
+	PatientID uuid.UUID              `json:"patient_id"`
+	AsOf      time.Time              `json:"as_of"`
+	VitaminA  *SupplementEligibility `json:"vitamin_a"`
+	Deworming *SupplementEligibility `json:"deworming"`
+}
+
+type SupplementRepository interface {
+	Create(ctx context.Context, record *SupplementRecord) error
+	GetByPatientID(ctx context.Context, patientID uuid.UUID) ([]*SupplementRecord, error)
+	Delete(ctx context.Context, id uuid.UUID, patientID uuid.UUID) error
+}
+
+type SupplementUsecase interface {
+	TreeAnswerProvider
+	RecordDose(ctx context.Context, patientID uuid.UUID, req *RecordSupplementRequest, medicalProfessionalID uuid.UUID) (*SupplementRecord, error)
+	GetRecords(ctx context.Context, patientID uuid.UUID) ([]*SupplementRecord, error)
+	DeleteRecord(ctx context.Context, patientID uuid.UUID, recordID uuid.UUID) error
+	GetStatus(ctx context.Context, patientID uuid.UUID, asOf time.Time) (*SupplementStatus, error)
+}
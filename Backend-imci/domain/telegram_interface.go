@@ -9,6 +9,13 @@ type TelegramService interface {
 	StartPolling() error
 	StopPolling()
 	IsRunning() bool
+	SendMessage(ctx context.Context, chatID int64, text string) error
+	// AttachCompanion enables the clinician commands (/followups, /patient,
+	// /summary) for linked chats.
+	AttachCompanion(companion ClinicalCompanionUsecase)
+	// AttachChatAssessment enables /assess for linked chats.
+	AttachChatAssessment(chat ChatAssessmentUsecase)
+	ChatMessenger
 
 }
 
@@ -16,4 +23,6 @@ type TelegramRepository interface {
 	SaveChatID(ctx context.Context, username string, chatID int64, phone string) error
     GetChatIDByUsername(ctx context.Context, username string) (int64, error)
     GetUsernameByPhone(ctx context.Context, phone string) (string, error)
-}
\ No newline at end of file
+	GetPhoneByChatID(ctx context.Context, chatID int64) (string, error)
+	GetChatIDByPhone(ctx context.Context, phone string) (int64, error)
+}
\ No newline at end of file
@@ -15,7 +15,15 @@ type MedicalProfessional struct {
 	Role         string    `json:"role"`
 	TelegramUsername string    `json:"telegram_username"` 
 	UseWhatsApp   bool      `json:"use_whatsapp"`
+	// NotificationChannel is the preferred OTP channel; empty falls back to
+	// UseWhatsApp.
+	NotificationChannel string `json:"notification_channel,omitempty"`
+	// NotificationLanguage is the language templated messages are sent in,
+	// e.g. "am"; empty means English.
+	NotificationLanguage string `json:"notification_language,omitempty"`
 	FacilityName    string    `json:"facility_name,omitempty" db:"facility_name"`
+	// IsTrainee sends the clinician's assessments to supervisor review.
+	IsTrainee    bool      `json:"is_trainee"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -33,6 +41,7 @@ const (
 	NurseRole     MedicalProfessionalRole = "nurse"
 	TechnicianRole MedicalProfessionalRole = "technician"
 	AdminRole     MedicalProfessionalRole = "admin"
+	SupervisorRole MedicalProfessionalRole = "supervisor"
 )
 
 type SignupForm struct {
@@ -41,6 +50,8 @@ type SignupForm struct {
 	Password string `json:"password" binding:"required"`
 	Role     string `json:"role" binding:"required"`
 	UseWhatsApp   bool   `json:"use_whatsapp"` 
+	Channel       string `json:"channel,omitempty"`
+	Language      string `json:"language,omitempty"`
 	FacilityName    string    `json:"facility_name,omitempty" db:"facility_name"`
 
 	
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VisitSummary gathers everything handed to the caregiver after a visit.
+// Language is the caregiver's language, the one mother advice was given in.
+type VisitSummary struct {
+	Assessment      *Assessment       `json:"assessment"`
+	Patient         *Patient          `json:"patient"`
+	Clinician       string            `json:"clinician,omitempty"`
+	Facility        string            `json:"facility,omitempty"`
+	Classifications []*Classification `json:"classifications"`
+	TreatmentPlans  []*TreatmentPlan  `json:"treatment_plans"`
+	Counseling      []*Counseling     `json:"counseling"`
+	FollowUpDate    *time.Time        `json:"follow_up_date,omitempty"`
+	Language        string            `json:"language"`
+	GeneratedAt     time.Time         `json:"generated_at"`
+}
+
+type VisitSummaryUsecase interface {
+	GetVisitSummary(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) (*VisitSummary, error)
+	RenderPDF(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) ([]byte, error)
+}
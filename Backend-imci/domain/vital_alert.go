@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VitalSign string
+
+const (
+	VitalOxygenSaturation VitalSign = "oxygen_saturation"
+	VitalTemperature      VitalSign = "temperature"
+	VitalRespiratoryRate  VitalSign = "respiratory_rate"
+	VitalHemoglobin       VitalSign = "hb_level"
+)
+
+type VitalAlertSeverity string
+
+const (
+	// VitalEmergency marks the assessment as a critical illness needing
+	// urgent referral and notifies the clinician and facility supervisors.
+	VitalEmergency VitalAlertSeverity = "emergency"
+	VitalWarning   VitalAlertSeverity = "warning"
+)
+
+// VitalAlert is a vital that breached a threshold.
+type VitalAlert struct {
+	ID           uuid.UUID          `json:"id"`
+	AssessmentID uuid.UUID          `json:"assessment_id"`
+	Code         string             `json:"code"`
+	Sign         VitalSign          `json:"sign"`
+	Value        float64            `json:"value"`
+	Threshold    float64            `json:"threshold"`
+	Severity     VitalAlertSeverity `json:"severity"`
+	Message      string             `json:"message"`
+	NotifiedAt   *time.Time         `json:"notified_at,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type VitalAlertRepository interface {
+	// Save stores the alert of an assessment, updating the value of one
+	// already raised with the same code. It reports whether the alert is new.
+	Save(ctx context.Context, alert *VitalAlert) (bool, error)
+	MarkNotified(ctx context.Context, id uuid.UUID, at time.Time) error
+	// DeleteExcept deletes the alerts of an assessment whose code is not in
+	// codes, those its vitals no longer raise.
+	DeleteExcept(ctx context.Context, assessmentID uuid.UUID, codes []string) error
+	ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*VitalAlert, error)
+}
+
+type VitalAlertUsecase interface {
+	// Check returns the alerts the assessment's vitals raise without storing
+	// anything.
+	Check(assessment *Assessment) []*VitalAlert
+	// Evaluate records vitals measured in answers on the assessment, sets
+	// its critical illness flags for emergencies and stores the alerts,
+	// deleting those the vitals no longer raise.
+	// Emergencies not yet notified are sent in the background, outside the
+	// request. answers may be nil.
+	Evaluate(ctx context.Context, assessment *Assessment, answers map[string]interface{}) ([]*VitalAlert, error)
+	History(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) ([]*VitalAlert, error)
+}
@@ -3,7 +3,13 @@ package domain
 import "context"
 
 type WhatsAppService interface {
-    SendOTP(ctx context.Context, phoneNumber, code string) error
+	// SendOTP sends the OTP template in language, or in English when the
+	// template has no translation in it.
+    SendOTP(ctx context.Context, phoneNumber, code, language string) error
+	// SendTemplate sends an approved message template, in the requested
+	// language when one is registered and in English otherwise.
+	SendTemplate(ctx context.Context, phoneNumber string, msg *WhatsAppTemplateMessage) error
+	ChatMessenger
 
 }
 
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrUnknownWhatsAppTemplate = errors.New("unknown WhatsApp template")
+)
+
+type WhatsAppTemplateKind string
+
+const (
+	TemplateOTP   WhatsAppTemplateKind = "otp"
+	TemplateAlert WhatsAppTemplateKind = "alert"
+)
+
+// WhatsAppTemplateMessage is a template to send with its body parameters in
+// template order.
+type WhatsAppTemplateMessage struct {
+	Kind     WhatsAppTemplateKind
+	Language string
+	Params   []string
+}
+
+// WhatsAppInboundMessage is a message a user sent to the business number.
+// For button and list replies Text holds the title and ReplyID the ID.
+type WhatsAppInboundMessage struct {
+	ID          string    `json:"id"`
+	From        string    `json:"from"`
+	ProfileName string    `json:"profile_name,omitempty"`
+	Type        string    `json:"type"`
+	Text        string    `json:"text,omitempty"`
+	ReplyID     string    `json:"reply_id,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// WhatsAppStatusUpdate reports the delivery state of a message we sent:
+// sent, delivered, read or failed.
+type WhatsAppStatusUpdate struct {
+	MessageID   string    `json:"message_id"`
+	RecipientID string    `json:"recipient_id"`
+	Status      string    `json:"status"`
+	ErrorCode   int       `json:"error_code,omitempty"`
+	ErrorTitle  string    `json:"error_title,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// WhatsAppWebhookEvent is everything one webhook delivery carried.
+type WhatsAppWebhookEvent struct {
+	Messages []*WhatsAppInboundMessage
+	Statuses []*WhatsAppStatusUpdate
+}
+
+type WhatsAppStatusRepository interface {
+	// Record stores a status update; repeated deliveries of the same update
+	// are ignored.
+	Record(ctx context.Context, status *WhatsAppStatusUpdate) error
+	// ClaimInbound records an incoming message and reports whether it is
+	// new. A redelivered message returns false and must not be answered
+	// again.
+	ClaimInbound(ctx context.Context, msg *WhatsAppInboundMessage) (bool, error)
+}
+
+type WhatsAppWebhookUsecase interface {
+	HandleEvent(ctx context.Context, event *WhatsAppWebhookEvent) error
+}
@@ -0,0 +1,271 @@
+// Package consistency compares the danger signs recorded by the trees of a
+// visit and finds the contradictions and escalations between them.
+package consistency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+// GeneralDangerSign is the sign of the summary questions that ask whether
+// the child has any general danger sign.
+const GeneralDangerSign = "GENERAL_DANGER_SIGN"
+
+// DangerSignTree is the tree that asks for every general danger sign in turn.
+const DangerSignTree = "child_general_danger_signs"
+
+// Question is a tree question that records a danger sign. The sign is
+// present when the answer equals Present.
+type Question struct {
+	Sign    string
+	Label   string
+	TreeID  string
+	NodeID  string
+	Present string
+}
+
+// Escalation upgrades a classification of TreeID listed in From to the
+// tree's Outcome when the visit has a danger sign.
+type Escalation struct {
+	Code    string
+	TreeID  string
+	From    []string
+	Outcome string
+	To      string
+}
+
+// Rules are the questions compared across the trees of a visit and the
+// escalations a danger sign calls for.
+type Rules struct {
+	Questions   []Question
+	Escalations []Escalation
+}
+
+// Escalation returns the escalation with code, or nil.
+func (r Rules) Escalation(code string) *Escalation {
+	for i := range r.Escalations {
+		if r.Escalations[i].Code == code {
+			return &r.Escalations[i]
+		}
+	}
+	return nil
+}
+
+// Child holds the child questions that record a general danger sign, with
+// the summary questions under GeneralDangerSign, and the IMCI rules that any
+// general danger sign makes pneumonia, fever and dehydration severe. NO
+// DEHYDRATION is left alone: a danger sign alone is not a sign of
+// dehydration.
+var Child = Rules{
+	Questions: []Question{
+		{Sign: "UNABLE_TO_DRINK", Label: "not able to drink or breastfeed", TreeID: DangerSignTree, NodeID: "unable_to_drink_breastfeed", Present: "no"},
+		{Sign: "VOMITS_EVERYTHING", Label: "vomits everything", TreeID: DangerSignTree, NodeID: "vomits_everything", Present: "yes"},
+		{Sign: "CONVULSIONS", Label: "convulsions in this illness", TreeID: DangerSignTree, NodeID: "convulsions_history", Present: "yes"},
+		{Sign: "LETHARGIC_UNCONSCIOUS", Label: "lethargic or unconscious", TreeID: DangerSignTree, NodeID: "lethargic_unconscious", Present: "yes"},
+		{Sign: "CONVULSING_NOW", Label: "convulsing now", TreeID: DangerSignTree, NodeID: "convulsing_now", Present: "yes"},
+		{Sign: "LETHARGIC_UNCONSCIOUS", Label: "lethargic or unconscious", TreeID: "child_diarrhea", NodeID: "lethargic_unconscious", Present: "yes"},
+		{Sign: "UNABLE_TO_DRINK", Label: "not able to drink or breastfeed", TreeID: "child_diarrhea", NodeID: "drinking_ability", Present: "no"},
+		{Sign: GeneralDangerSign, Label: "any general danger sign", TreeID: "child_cough_difficult_breathing", NodeID: "general_danger_signs", Present: "yes"},
+	},
+	Escalations: []Escalation{
+		{
+			Code:    "DANGER_SIGN_SEVERE_PNEUMONIA",
+			TreeID:  "child_cough_difficult_breathing",
+			From:    []string{"PNEUMONIA", "COUGH OR COLD"},
+			Outcome: "SEVERE_PNEUMONIA_OR_VERY_SEVERE_DISEASE",
+			To:      "SEVERE PNEUMONIA OR VERY SEVERE DISEASE",
+		},
+		{
+			Code:    "DANGER_SIGN_VERY_SEVERE_FEBRILE_DISEASE",
+			TreeID:  "child_fever",
+			From:    []string{"MALARIA", "FEVER: NO MALARIA"},
+			Outcome: "VERY_SEVERE_FEBRILE_DISEASE",
+			To:      "VERY SEVERE FEBRILE DISEASE",
+		},
+		{
+			Code:    "DANGER_SIGN_SEVERE_DEHYDRATION",
+			TreeID:  "child_diarrhea",
+			From:    []string{"SOME DEHYDRATION"},
+			Outcome: "SEVERE_DEHYDRATION",
+			To:      "SEVERE DEHYDRATION",
+		},
+	},
+}
+
+// YoungInfant holds the young infant signs asked by more than one tree. The
+// young infant chart has no rule that upgrades one box's classification from
+// a sign found in another, so only contradictions are reported.
+var YoungInfant = Rules{
+	Questions: []Question{
+		{Sign: "MOVES_ONLY_WHEN_STIMULATED", Label: "moves only when stimulated", TreeID: "very_severe_disease_check", NodeID: "check_movements", Present: "moves_only_when_stimulated"},
+		{Sign: "MOVES_ONLY_WHEN_STIMULATED", Label: "moves only when stimulated", TreeID: "diarrhea_check", NodeID: "movement_condition", Present: "moves_only_when_stimulated"},
+		{Sign: "DIFFICULTY_FEEDING", Label: "difficulty feeding", TreeID: "very_severe_disease_check", NodeID: "difficulty_feeding", Present: "yes"},
+		{Sign: "DIFFICULTY_FEEDING", Label: "difficulty feeding", TreeID: "feeding_problem_underweight_check", NodeID: "feeding_difficulty", Present: "yes"},
+		{Sign: "DIFFICULTY_FEEDING", Label: "difficulty feeding", TreeID: "replacement_feeding_check", NodeID: "feeding_difficulty_non_bf", Present: "yes"},
+	},
+}
+
+type observation struct {
+	question Question
+	present  bool
+}
+
+// Check compares the signs recorded by every tree of a visit, answers
+// keyed by tree. A sign answered present in one tree and absent in another
+// is a contradiction, as is a summary question that disagrees with the
+// danger sign tree. Any sign present escalates the matching
+// classifications; the escalation's code names the rule applied.
+func Check(rules Rules, answers map[string]map[string]interface{}, classifications []*domain.Classification) []*domain.ConsistencyFinding {
+	findings := []*domain.ConsistencyFinding{}
+
+	bySign := map[string][]observation{}
+	signs := []string{}
+	anyPresent := false
+	for _, q := range rules.Questions {
+		value, answered := answers[q.TreeID][q.NodeID].(string)
+		if !answered {
+			continue
+		}
+		present := value == q.Present
+		anyPresent = anyPresent || present
+		if _, seen := bySign[q.Sign]; !seen {
+			signs = append(signs, q.Sign)
+		}
+		bySign[q.Sign] = append(bySign[q.Sign], observation{question: q, present: present})
+	}
+
+	for _, sign := range signs {
+		observations := bySign[sign]
+		if sign == GeneralDangerSign {
+			observations = append(observations, summaryObservations(rules.Questions, bySign, answers)...)
+		}
+		if finding := contradiction(sign, observations); finding != nil {
+			findings = append(findings, finding)
+		}
+	}
+
+	if !anyPresent {
+		return findings
+	}
+	for _, c := range classifications {
+		for _, rule := range rules.Escalations {
+			if !containsDisease(rule.From, c.Disease) {
+				continue
+			}
+			id := c.ID
+			findings = append(findings, &domain.ConsistencyFinding{
+				AssessmentID:     c.AssessmentID,
+				Kind:             domain.ConsistencyEscalation,
+				Code:             rule.Code,
+				TreeIDs:          []string{rule.TreeID},
+				Detail:           fmt.Sprintf("%s upgraded to %s: a general danger sign was found in this visit", c.Disease, rule.To),
+				ClassificationID: &id,
+				FromDisease:      c.Disease,
+				ToDisease:        rule.To,
+			})
+			break
+		}
+	}
+
+	return findings
+}
+
+// summaryObservations stands in for a summary question in the trees that
+// record danger signs one by one: present if any sign was found there, and
+// absent from the danger sign tree once every sign has been answered absent.
+func summaryObservations(questions []Question, bySign map[string][]observation, answers map[string]map[string]interface{}) []observation {
+	presentIn := map[string]bool{}
+	for sign, observations := range bySign {
+		if sign == GeneralDangerSign {
+			continue
+		}
+		for _, o := range observations {
+			if o.present {
+				presentIn[o.question.TreeID] = true
+			}
+		}
+	}
+
+	observations := []observation{}
+	trees := make([]string, 0, len(presentIn))
+	for treeID := range presentIn {
+		trees = append(trees, treeID)
+	}
+	sort.Strings(trees)
+	for _, treeID := range trees {
+		observations = append(observations, observation{
+			question: Question{Sign: GeneralDangerSign, Label: "any general danger sign", TreeID: treeID},
+			present:  true,
+		})
+	}
+
+	if presentIn[DangerSignTree] {
+		return observations
+	}
+	asked := 0
+	for _, q := range questions {
+		if q.TreeID != DangerSignTree {
+			continue
+		}
+		if _, answered := answers[q.TreeID][q.NodeID].(string); !answered {
+			return observations
+		}
+		asked++
+	}
+	if asked > 0 {
+		observations = append(observations, observation{
+			question: Question{Sign: GeneralDangerSign, Label: "any general danger sign", TreeID: DangerSignTree},
+			present:  false,
+		})
+	}
+	return observations
+}
+
+func contradiction(sign string, observations []observation) *domain.ConsistencyFinding {
+	var present, absent []string
+	label := ""
+	for _, o := range observations {
+		label = o.question.Label
+		if o.present {
+			present = appendTree(present, o.question.TreeID)
+		} else {
+			absent = appendTree(absent, o.question.TreeID)
+		}
+	}
+	if len(present) == 0 || len(absent) == 0 {
+		return nil
+	}
+
+	trees := append([]string{}, present...)
+	for _, treeID := range absent {
+		trees = appendTree(trees, treeID)
+	}
+	return &domain.ConsistencyFinding{
+		Kind:    domain.ConsistencyContradiction,
+		Code:    "CONFLICTING_" + sign,
+		TreeIDs: trees,
+		Detail: fmt.Sprintf("%s: yes in %s, no in %s",
+			label, strings.Join(present, ", "), strings.Join(absent, ", ")),
+	}
+}
+
+func appendTree(trees []string, treeID string) []string {
+	for _, t := range trees {
+		if t == treeID {
+			return trees
+		}
+	}
+	return append(trees, treeID)
+}
+
+func containsDisease(diseases []string, disease string) bool {
+	for _, d := range diseases {
+		if strings.EqualFold(d, disease) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package consistency
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+var noDangerSignAnswers = map[string]interface{}{
+	"unable_to_drink_breastfeed": "yes",
+	"vomits_everything":          "no",
+	"convulsions_history":        "no",
+	"lethargic_unconscious":      "no",
+	"convulsing_now":             "no",
+}
+
+func findingCodes(findings []*domain.ConsistencyFinding) []string {
+	codes := []string{}
+	for _, f := range findings {
+		codes = append(codes, f.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func TestCheck(t *testing.T) {
+	assessmentID := uuid.New()
+	pneumonia := &domain.Classification{ID: uuid.New(), AssessmentID: assessmentID, Disease: "PNEUMONIA"}
+	malaria := &domain.Classification{ID: uuid.New(), AssessmentID: assessmentID, Disease: "MALARIA"}
+	noDehydration := &domain.Classification{ID: uuid.New(), AssessmentID: assessmentID, Disease: "NO DEHYDRATION"}
+	localInfection := &domain.Classification{ID: uuid.New(), AssessmentID: assessmentID, Disease: "LOCAL BACTERIAL INFECTION"}
+
+	tests := []struct {
+		name            string
+		rules           Rules
+		answers         map[string]map[string]interface{}
+		classifications []*domain.Classification
+		want            []string
+	}{
+		{
+			name:  "consistent visit without danger signs",
+			rules: Child,
+			answers: map[string]map[string]interface{}{
+				DangerSignTree:                    noDangerSignAnswers,
+				"child_cough_difficult_breathing": {"general_danger_signs": "no", "fast_breathing": "yes"},
+				"child_diarrhea":                  {"lethargic_unconscious": "no", "drinking_ability": "yes"},
+			},
+			classifications: []*domain.Classification{pneumonia, malaria},
+			want:            []string{},
+		},
+		{
+			name:  "lethargic in diarrhoea but not in danger signs",
+			rules: Child,
+			answers: map[string]map[string]interface{}{
+				DangerSignTree:   noDangerSignAnswers,
+				"child_diarrhea": {"lethargic_unconscious": "yes", "drinking_ability": "yes"},
+			},
+			classifications: []*domain.Classification{pneumonia, malaria, noDehydration},
+			want: []string{
+				"CONFLICTING_LETHARGIC_UNCONSCIOUS",
+				"DANGER_SIGN_SEVERE_PNEUMONIA",
+				"DANGER_SIGN_VERY_SEVERE_FEBRILE_DISEASE",
+			},
+		},
+		{
+			name:  "summary question disagrees with danger sign tree",
+			rules: Child,
+			answers: map[string]map[string]interface{}{
+				DangerSignTree:                    noDangerSignAnswers,
+				"child_cough_difficult_breathing": {"general_danger_signs": "yes"},
+			},
+			classifications: []*domain.Classification{malaria},
+			want: []string{
+				"CONFLICTING_GENERAL_DANGER_SIGN",
+				"DANGER_SIGN_VERY_SEVERE_FEBRILE_DISEASE",
+			},
+		},
+		{
+			name:  "summary question misses a sign found elsewhere",
+			rules: Child,
+			answers: map[string]map[string]interface{}{
+				DangerSignTree:                    {"unable_to_drink_breastfeed": "yes", "vomits_everything": "yes"},
+				"child_cough_difficult_breathing": {"general_danger_signs": "no"},
+			},
+			want: []string{"CONFLICTING_GENERAL_DANGER_SIGN"},
+		},
+		{
+			name:  "young infant movement recorded differently",
+			rules: YoungInfant,
+			answers: map[string]map[string]interface{}{
+				"very_severe_disease_check": {"difficulty_feeding": "no", "check_movements": "moves_on_own"},
+				"diarrhea_check":            {"movement_condition": "moves_only_when_stimulated"},
+			},
+			classifications: []*domain.Classification{localInfection},
+			want:            []string{"CONFLICTING_MOVES_ONLY_WHEN_STIMULATED"},
+		},
+		{
+			name:  "young infant feeding difficulty recorded differently",
+			rules: YoungInfant,
+			answers: map[string]map[string]interface{}{
+				"very_severe_disease_check":         {"difficulty_feeding": "no", "check_movements": "moves_on_own"},
+				"diarrhea_check":                    {"movement_condition": "restless_irritable"},
+				"feeding_problem_underweight_check": {"feeding_difficulty": "yes"},
+			},
+			want: []string{"CONFLICTING_DIFFICULTY_FEEDING"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findingCodes(Check(tt.rules, tt.answers, tt.classifications))
+			sort.Strings(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRulesEscalation(t *testing.T) {
+	if rule := Child.Escalation("DANGER_SIGN_SEVERE_DEHYDRATION"); rule == nil || rule.Outcome != "SEVERE_DEHYDRATION" {
+		t.Errorf("escalation = %+v, want SEVERE_DEHYDRATION", rule)
+	}
+	if rule := YoungInfant.Escalation("DANGER_SIGN_SEVERE_DEHYDRATION"); rule != nil {
+		t.Errorf("young infant escalation = %+v, want none", rule)
+	}
+}
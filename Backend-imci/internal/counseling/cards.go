@@ -0,0 +1,179 @@
+// Package counseling builds the caregiver counselling items for an IMCI
+// classification, following the "Counsel the mother" charts: extra fluids,
+// feeding, and when to return immediately, or the referral advice for a
+// severe (pink) classification.
+package counseling
+
+import "strings"
+
+// Item types, in the order the clinician goes through them.
+const (
+	HomeFluids   = "home_fluids"
+	Feeding      = "feeding"
+	WhenToReturn = "when_to_return"
+	Referral     = "referral"
+)
+
+// Types lists every structured item type.
+var Types = []string{HomeFluids, Feeding, WhenToReturn, Referral}
+
+// Item is one piece of advice to go through with the caregiver. Key names
+// the wording, e.g. "home_fluids.diarrhoea", so catalogues can translate it.
+// General items carry the advice every home-treated classification gets
+// rather than advice for the illness found.
+type Item struct {
+	Type    string
+	Key     string
+	Text    string
+	General bool
+}
+
+// Classification codes by the advice they need. The rule trees write codes
+// both with spaces and with underscores.
+var (
+	diarrhoeaCodes = codes(
+		"NO DEHYDRATION", "SOME DEHYDRATION", "SEVERE DEHYDRATION",
+		"PERSISTENT DIARRHOEA", "SEVERE PERSISTENT DIARRHOEA", "SEVERE PERSISTENT DIARRHEA",
+		"DYSENTERY",
+	)
+	respiratoryCodes = codes(
+		"COUGH OR COLD", "PNEUMONIA", "SEVERE PNEUMONIA OR VERY SEVERE DISEASE",
+	)
+	feedingCodes = codes(
+		"FEEDING PROBLEM", "FEEDING PROBLEM OR UNDERWEIGHT",
+		"MODERATE ACUTE MALNUTRITION", "UNCOMPLICATED SEVERE ACUTE MALNUTRITION",
+		"ANEMIA", "ANAEMIA", "LOW BIRTH WEIGHT AND/OR PRETERM",
+	)
+	// negativeCodes found nothing to counsel on.
+	negativeCodes = codes(
+		"NO DIARRHEA", "NO COUGH OR DIFFICULT BREATHING",
+		"NO FEVER", "NO EAR INFECTION", "NO ANEMIA", "NO ANAEMIA", "NO ACUTE MALNUTRITION",
+		"NO FEEDING PROBLEM", "NO FEEDING PROBLEM AND NOT UNDERWEIGHT",
+		"NO GENERAL DANGER SIGNS", "NO JAUNDICE", "NO TB INFECTION",
+		"NO DEVELOPMENTAL DELAY", "NO BIRTH ASPHYXIA", "NO COUGH DIFFICULT BREATHING",
+		"SEVERE INFECTION UNLIKELY", "HIV INFECTION UNLIKELY",
+		"NORMAL BIRTH WEIGHT AND/OR TERM", "IMMUNIZATION AND SUPPLEMENTS UP TO DATE",
+	)
+)
+
+func codes(list ...string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, code := range list {
+		set[code] = true
+	}
+	return set
+}
+
+// normalize turns a classification code into the form of the lists above.
+func normalize(disease string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(strings.ToUpper(disease), "_", " ")), " ")
+}
+
+type illness struct {
+	diarrhoea   bool
+	respiratory bool
+	feeding     bool
+}
+
+func classify(disease string) illness {
+	code := normalize(disease)
+	return illness{
+		diarrhoea:   diarrhoeaCodes[code],
+		respiratory: respiratoryCodes[code],
+		feeding:     feedingCodes[code],
+	}
+}
+
+// Cards returns the counselling items for one classification. Severe
+// classifications only get the referral advice; everything else is treated
+// at home. Negative classifications, such as NO ANEMIA, get none.
+func Cards(disease, color string, youngInfant bool) []Item {
+	if negativeCodes[normalize(disease)] {
+		return nil
+	}
+	if strings.EqualFold(color, "pink") {
+		return []Item{item(Referral, false)(referralText(youngInfant))}
+	}
+
+	ill := classify(disease)
+	return []Item{
+		item(HomeFluids, !ill.diarrhoea)(fluidsText(ill, youngInfant)),
+		item(Feeding, !ill.feeding)(feedingText(ill, youngInfant)),
+		item(WhenToReturn, youngInfant || !(ill.respiratory || ill.diarrhoea))(returnText(ill, youngInfant)),
+	}
+}
+
+// VisitCards returns the items of one classification of a visit whose
+// earlier classifications already got the item types in given. General
+// advice is given once per visit, so a general item whose type the visit
+// already has is left out. given is updated with the types returned.
+func VisitCards(disease, color string, youngInfant bool, given map[string]bool) []Item {
+	var items []Item
+	for _, it := range Cards(disease, color, youngInfant) {
+		if it.General && given[it.Type] {
+			continue
+		}
+		given[it.Type] = true
+		items = append(items, it)
+	}
+	return items
+}
+
+// item builds an item of itemType from a wording variant and its text.
+func item(itemType string, general bool) func(variant, text string) Item {
+	return func(variant, text string) Item {
+		return Item{Type: itemType, Key: itemType + "." + variant, Text: text, General: general}
+	}
+}
+
+func referralText(youngInfant bool) (string, string) {
+	if youngInfant {
+		return "young_infant", "Take the infant to the hospital now. Keep the infant warm, skin-to-skin if possible, and keep breastfeeding on the way."
+	}
+	return "child", "Take the child to the hospital now. Keep the child warm on the way and keep breastfeeding or giving sips of fluid if the child can drink."
+}
+
+func fluidsText(ill illness, youngInfant bool) (string, string) {
+	switch {
+	case youngInfant && ill.diarrhoea:
+		return "young_infant_diarrhoea", "Breastfeed frequently and for longer at each feed. Give ORS solution or clean water in addition to breast milk after each loose stool until the diarrhoea stops."
+	case youngInfant:
+		return "young_infant", "Breastfeed more often and for longer at each feed. Do not give other foods or fluids."
+	case ill.diarrhoea:
+		return "diarrhoea", "Give extra fluid: breastfeed frequently and give ORS solution, soup, rice water or clean water. Give 50-100 ml after each loose stool under 2 years, 100-200 ml from 2 years, until the diarrhoea stops."
+	default:
+		return "general", "Breastfeed more frequently and for longer at each feed, and offer more fluids such as soup, rice water or clean water."
+	}
+}
+
+func feedingText(ill illness, youngInfant bool) (string, string) {
+	switch {
+	case youngInfant && ill.feeding:
+		return "young_infant_feeding_problem", "Hold the infant in a good position and check the attachment as shown. Breastfeed at least 8 times in 24 hours and come back for the feeding follow-up."
+	case youngInfant:
+		return "young_infant", "Breastfeed as often as the infant wants, day and night, at least 8 times in 24 hours. Give only breast milk."
+	case ill.feeding:
+		return "feeding_problem", "Follow the feeding advice given for the child's age and the feeding problem found, and come back for the feeding follow-up."
+	default:
+		return "general", "Continue feeding during the illness: give the usual foods in small, frequent meals, and one extra meal a day for two weeks after the illness."
+	}
+}
+
+func returnText(ill illness, youngInfant bool) (string, string) {
+	if youngInfant {
+		return "young_infant", "Return immediately if the infant is breastfeeding or drinking poorly, becomes sicker, develops a fever or feels cold to touch, has fast or difficult breathing, or has blood in the stool."
+	}
+
+	variant := "child"
+	signs := []string{"is not able to drink or breastfeed", "becomes sicker", "develops a fever"}
+	if ill.respiratory {
+		variant += "_respiratory"
+		signs = append(signs, "has fast or difficult breathing")
+	}
+	if ill.diarrhoea {
+		variant += "_diarrhoea"
+		signs = append(signs, "has blood in the stool", "is drinking poorly")
+	}
+	last := len(signs) - 1
+	return variant, "Return immediately if the child " + strings.Join(signs[:last], ", ") + ", or " + signs[last] + "."
+}
@@ -0,0 +1,84 @@
+package counseling
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCards(t *testing.T) {
+	items := Cards("SEVERE DEHYDRATION", "pink", false)
+	if len(items) != 1 || items[0].Type != Referral {
+		t.Fatalf("severe classification got %+v", items)
+	}
+
+	items = Cards("SOME DEHYDRATION", "yellow", false)
+	if items[0].Key != "home_fluids.diarrhoea" || items[2].Key != "when_to_return.child_diarrhoea" {
+		t.Errorf("unexpected wording keys %q and %q", items[0].Key, items[2].Key)
+	}
+	if len(items) != 3 || items[0].Type != HomeFluids || items[1].Type != Feeding || items[2].Type != WhenToReturn {
+		t.Fatalf("unexpected items %+v", items)
+	}
+	if !strings.Contains(items[0].Text, "ORS") {
+		t.Errorf("diarrhoea fluids advice should mention ORS: %q", items[0].Text)
+	}
+	if !strings.Contains(items[2].Text, "blood in the stool") || strings.Contains(items[2].Text, "breathing") {
+		t.Errorf("unexpected return signs: %q", items[2].Text)
+	}
+
+	items = Cards("PNEUMONIA", "yellow", false)
+	if !strings.Contains(items[2].Text, "fast or difficult breathing") {
+		t.Errorf("pneumonia return signs missing breathing: %q", items[2].Text)
+	}
+	if strings.Contains(items[0].Text, "ORS") {
+		t.Errorf("non-diarrhoea fluids advice mentions ORS: %q", items[0].Text)
+	}
+
+	items = Cards("FEEDING PROBLEM", "yellow", true)
+	if !strings.Contains(items[1].Text, "attachment") || !strings.Contains(items[0].Text, "Do not give other") {
+		t.Errorf("unexpected young infant advice %+v", items)
+	}
+}
+
+func TestCardsMatchExactCodes(t *testing.T) {
+	// Codes are matched whole, so a negative classification naming an
+	// illness gets no advice for it.
+	for _, disease := range []string{"NO FEEDING PROBLEM", "NO ACUTE MALNUTRITION", "NO ANEMIA", "NO_COUGH_DIFFICULT_BREATHING", "NO COUGH OR DIFFICULT BREATHING"} {
+		if items := Cards(disease, "green", false); len(items) != 0 {
+			t.Errorf("%s got %+v, want no cards", disease, items)
+		}
+	}
+
+	items := Cards("SOME_DEHYDRATION", "yellow", false)
+	if len(items) != 3 || items[0].Key != "home_fluids.diarrhoea" || items[0].General {
+		t.Errorf("underscored code got %+v", items)
+	}
+	items = Cards("FEVER", "yellow", false)
+	if len(items) != 3 || !items[0].General || !items[1].General || !items[2].General {
+		t.Errorf("fever should only get the general advice, got %+v", items)
+	}
+}
+
+func TestVisitCardsGiveGeneralAdviceOnce(t *testing.T) {
+	given := map[string]bool{}
+
+	first := VisitCards("PNEUMONIA", "yellow", false, given)
+	if len(first) != 3 {
+		t.Fatalf("first classification got %+v, want all three cards", first)
+	}
+
+	// Fever only has the general advice, which the visit already has.
+	if items := VisitCards("FEVER", "yellow", false, given); len(items) != 0 {
+		t.Errorf("fever got %+v, want no repeated general advice", items)
+	}
+
+	// Diarrhoea still gets its own fluids and return advice.
+	items := VisitCards("SOME DEHYDRATION", "yellow", false, given)
+	if len(items) != 2 || items[0].Key != "home_fluids.diarrhoea" || items[1].Key != "when_to_return.child_diarrhoea" {
+		t.Errorf("dehydration got %+v, want only its specific advice", items)
+	}
+
+	// Referral advice is never held back.
+	if items := VisitCards("SEVERE DEHYDRATION", "pink", false, given); len(items) != 1 || items[0].Type != Referral {
+		t.Errorf("severe classification got %+v", items)
+	}
+}
@@ -0,0 +1,137 @@
+package dhis2
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	cases := []struct {
+		period     string
+		start, end string
+	}{
+		{"202603", "2026-03-01", "2026-04-01"},
+		{"202612", "2026-12-01", "2027-01-01"},
+		{"2026Q2", "2026-04-01", "2026-07-01"},
+		{"2026", "2026-01-01", "2027-01-01"},
+	}
+	for _, tc := range cases {
+		start, end, err := Gregorian.ParsePeriod(tc.period)
+		if err != nil {
+			t.Fatalf("ParsePeriod(%q): %v", tc.period, err)
+		}
+		if got := start.Format("2006-01-02"); got != tc.start {
+			t.Errorf("%s start = %s, want %s", tc.period, got, tc.start)
+		}
+		if got := end.Format("2006-01-02"); got != tc.end {
+			t.Errorf("%s end = %s, want %s", tc.period, got, tc.end)
+		}
+	}
+
+	for _, bad := range []string{"", "202613", "2026Q5", "2026-03", "26"} {
+		if _, _, err := Gregorian.ParsePeriod(bad); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("ParsePeriod(%q) error = %v, want ErrInvalidPeriod", bad, err)
+		}
+	}
+}
+
+func TestPreviousMonth(t *testing.T) {
+	if got := Gregorian.PreviousMonth(time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)); got != "202512" {
+		t.Errorf("PreviousMonth = %s, want 202512", got)
+	}
+	if got := Gregorian.PreviousMonth(time.Date(2026, 3, 31, 10, 0, 0, 0, time.UTC)); got != "202602" {
+		t.Errorf("PreviousMonth = %s, want 202602", got)
+	}
+}
+
+// Meskerem 1 fell on 12 September 2023 (2016) and 11 September 2024 (2017),
+// after the leap year 2015 with a six-day Pagume.
+func TestEthiopianPeriods(t *testing.T) {
+	cases := []struct {
+		period     string
+		start, end string
+	}{
+		{"201601", "2023-09-12", "2023-10-12"},
+		{"201607", "2024-03-10", "2024-04-09"},
+		{"201612", "2024-08-07", "2024-09-11"},
+		{"2016Q1", "2023-09-12", "2023-12-11"},
+		{"2016Q4", "2024-06-08", "2024-09-11"},
+		{"2016", "2023-09-12", "2024-09-11"},
+		{"2015", "2022-09-11", "2023-09-12"},
+	}
+	for _, tc := range cases {
+		start, end, err := Ethiopian.ParsePeriod(tc.period)
+		if err != nil {
+			t.Fatalf("ParsePeriod(%q): %v", tc.period, err)
+		}
+		if got := start.Format("2006-01-02"); got != tc.start {
+			t.Errorf("%s start = %s, want %s", tc.period, got, tc.start)
+		}
+		if got := end.Format("2006-01-02"); got != tc.end {
+			t.Errorf("%s end = %s, want %s", tc.period, got, tc.end)
+		}
+	}
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d.Add(15 * time.Hour)
+	}
+	if got := Ethiopian.Monthly(date("2024-03-10")); got != "201607" {
+		t.Errorf("Monthly = %s, want 201607", got)
+	}
+	// Pagume is reported with Nehase.
+	if got := Ethiopian.Monthly(date("2024-09-10")); got != "201612" {
+		t.Errorf("Monthly in Pagume = %s, want 201612", got)
+	}
+	if got := Ethiopian.PreviousMonth(date("2023-09-12")); got != "201512" {
+		t.Errorf("PreviousMonth = %s, want 201512", got)
+	}
+	if got := Ethiopian.Day(date("2024-03-14")); got != 5 {
+		t.Errorf("Day = %d, want 5", got)
+	}
+
+	if _, err := ParseCalendar("julian"); !errors.Is(err, ErrUnknownCalendar) {
+		t.Errorf("ParseCalendar error = %v, want ErrUnknownCalendar", err)
+	}
+}
+
+func TestMappingLookups(t *testing.T) {
+	m, err := ParseMapping([]byte(`{
+		"dataElements": {"Severe  pneumonia or very severe disease": "de1"},
+		"categoryOptionCombos": {"child.male": "coc1", "young_infant": "coc2", "default": "coc0"},
+		"orgUnits": {"Adama HC": "ou1", "default": "ou0"}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseMapping: %v", err)
+	}
+
+	if uid, ok := m.DataElement("SEVERE PNEUMONIA OR VERY SEVERE DISEASE"); !ok || uid != "de1" {
+		t.Errorf("DataElement = %q, %v", uid, ok)
+	}
+	if _, ok := m.DataElement("PNEUMONIA"); ok {
+		t.Error("unmapped classification should not match")
+	}
+
+	combos := map[[2]string]string{
+		{"child", "male"}:          "coc1",
+		{"young_infant", "female"}: "coc2",
+		{"child", "female"}:        "coc0",
+	}
+	for in, want := range combos {
+		if got, _ := m.CategoryOptionCombo(in[0], in[1]); got != want {
+			t.Errorf("CategoryOptionCombo(%s, %s) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+
+	if got, _ := m.OrgUnit("Adama HC"); got != "ou1" {
+		t.Errorf("OrgUnit(Adama HC) = %q", got)
+	}
+	if got, _ := m.OrgUnit(""); got != "ou0" {
+		t.Errorf("OrgUnit fallback = %q", got)
+	}
+
+	if _, err := ParseMapping([]byte(`{"dataElements": {}}`)); !errors.Is(err, ErrInvalidMapping) {
+		t.Errorf("empty mapping error = %v, want ErrInvalidMapping", err)
+	}
+}
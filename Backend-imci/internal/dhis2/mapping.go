@@ -0,0 +1,110 @@
+// Package dhis2 maps IMCI indicator counts onto a DHIS2 instance's metadata
+// (data elements, category option combos and organisation units) and parses
+// DHIS2 period identifiers. The mapping is configuration: every instance has
+// its own UIDs.
+package dhis2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrInvalidMapping = errors.New("invalid DHIS2 mapping")
+
+// Fallback key for category option combos and organisation units.
+const Default = "default"
+
+// Mapping is loaded from a JSON file:
+//
+//	{
+//	  "dataSet": "qNtxTrp56wV",
+//	  "calendar": "ethiopian",
+//	  "attributeOptionCombo": "HllvX50cXC0",
+//	  "dataElements": {"PNEUMONIA": "fbfJHSPpUQD"},
+//	  "categoryOptionCombos": {"child.male": "...", "child.female": "...", "default": "..."},
+//	  "orgUnits": {"Bishoftu Health Center": "DiszpKrYNg8"}
+//	}
+//
+// Data elements are keyed by classification name; several classifications
+// may share one data element, and their counts are summed. Category option
+// combos are keyed "<age group>.<sex>", then "<age group>", then "default".
+// Organisation units are keyed by facility name, then "default". Calendar
+// is the instance's calendar, "gregorian" (the default) or "ethiopian".
+type Mapping struct {
+	DataSet              string            `json:"dataSet"`
+	Calendar             Calendar          `json:"calendar"`
+	AttributeOptionCombo string            `json:"attributeOptionCombo"`
+	DataElements         map[string]string `json:"dataElements"`
+	CategoryOptionCombos map[string]string `json:"categoryOptionCombos"`
+	OrgUnits             map[string]string `json:"orgUnits"`
+}
+
+// LoadMapping reads and checks a mapping file.
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DHIS2 mapping: %w", err)
+	}
+	return ParseMapping(data)
+}
+
+func ParseMapping(data []byte) (*Mapping, error) {
+	var m Mapping
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMapping, err)
+	}
+	if len(m.DataElements) == 0 {
+		return nil, fmt.Errorf("%w: no data elements", ErrInvalidMapping)
+	}
+	if len(m.CategoryOptionCombos) == 0 {
+		return nil, fmt.Errorf("%w: no category option combos", ErrInvalidMapping)
+	}
+	if len(m.OrgUnits) == 0 {
+		return nil, fmt.Errorf("%w: no organisation units", ErrInvalidMapping)
+	}
+	calendar, err := ParseCalendar(string(m.Calendar))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMapping, err)
+	}
+	m.Calendar = calendar
+
+	// Classification names are matched case-insensitively.
+	elements := make(map[string]string, len(m.DataElements))
+	for name, uid := range m.DataElements {
+		elements[normalize(name)] = uid
+	}
+	m.DataElements = elements
+	return &m, nil
+}
+
+// DataElement returns the data element for a classification.
+func (m *Mapping) DataElement(classification string) (string, bool) {
+	uid, ok := m.DataElements[normalize(classification)]
+	return uid, ok
+}
+
+// CategoryOptionCombo returns the disaggregation for an age group and sex.
+func (m *Mapping) CategoryOptionCombo(ageGroup, sex string) (string, bool) {
+	for _, key := range []string{ageGroup + "." + sex, ageGroup, Default} {
+		if uid, ok := m.CategoryOptionCombos[key]; ok {
+			return uid, true
+		}
+	}
+	return "", false
+}
+
+// OrgUnit returns the organisation unit for a facility.
+func (m *Mapping) OrgUnit(facility string) (string, bool) {
+	if uid, ok := m.OrgUnits[facility]; ok && facility != "" {
+		return uid, true
+	}
+	uid, ok := m.OrgUnits[Default]
+	return uid, ok
+}
+
+func normalize(name string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(name), " "))
+}
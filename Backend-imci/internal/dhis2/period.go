@@ -0,0 +1,138 @@
+package dhis2
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidPeriod   = errors.New("invalid DHIS2 period")
+	ErrUnknownCalendar = errors.New("unknown DHIS2 calendar")
+)
+
+var (
+	monthlyPattern   = regexp.MustCompile(`^(\d{4})(0[1-9]|1[0-2])$`)
+	quarterlyPattern = regexp.MustCompile(`^(\d{4})Q([1-4])$`)
+	yearlyPattern    = regexp.MustCompile(`^(\d{4})$`)
+)
+
+// Calendar is the calendar a DHIS2 instance's periods are in. Ethiopia's
+// national instance uses the Ethiopian calendar, so "201607" there is
+// Megabit 2016 rather than July 2016.
+type Calendar string
+
+const (
+	Gregorian Calendar = "gregorian"
+	// Ethiopian has twelve 30-day months and Pagume, a 13th month of five
+	// or six days. DHIS2 has no period for Pagume; its days are reported
+	// with Nehase, month 12.
+	Ethiopian Calendar = "ethiopian"
+)
+
+// ParseCalendar reads a calendar name; empty is Gregorian.
+func ParseCalendar(name string) (Calendar, error) {
+	switch Calendar(name) {
+	case "", Gregorian, "iso8601":
+		return Gregorian, nil
+	case Ethiopian:
+		return Ethiopian, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownCalendar, name)
+	}
+}
+
+// ParsePeriod returns the half-open [start, end) range of a monthly
+// (202603), quarterly (2026Q1) or yearly (2026) DHIS2 period, in UTC.
+func (c Calendar) ParsePeriod(period string) (time.Time, time.Time, error) {
+	var year, month, months int
+	switch {
+	case monthlyPattern.MatchString(period):
+		m := monthlyPattern.FindStringSubmatch(period)
+		year, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+		months = 1
+	case quarterlyPattern.MatchString(period):
+		m := quarterlyPattern.FindStringSubmatch(period)
+		year, _ = strconv.Atoi(m[1])
+		quarter, _ := strconv.Atoi(m[2])
+		month, months = (quarter-1)*3+1, 3
+	case yearlyPattern.MatchString(period):
+		year, _ = strconv.Atoi(period)
+		month, months = 1, 12
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %q (use YYYYMM, YYYYQn or YYYY)", ErrInvalidPeriod, period)
+	}
+
+	if c == Ethiopian {
+		end := fromEthiopian(year, month+months, 1)
+		if month+months > 12 {
+			end = fromEthiopian(year+1, 1, 1)
+		}
+		return fromEthiopian(year, month, 1), end, nil
+	}
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, months, 0), nil
+}
+
+// Monthly returns the monthly period containing t.
+func (c Calendar) Monthly(t time.Time) string {
+	if c == Ethiopian {
+		year, month, _ := toEthiopian(t)
+		return fmt.Sprintf("%04d%02d", year, month)
+	}
+	return t.Format("200601")
+}
+
+// PreviousMonth returns the monthly period before the one containing t,
+// the period a facility reports on at the start of each month.
+func (c Calendar) PreviousMonth(t time.Time) string {
+	if c == Ethiopian {
+		year, month, _ := toEthiopian(t)
+		if month == 1 {
+			return fmt.Sprintf("%04d12", year-1)
+		}
+		return fmt.Sprintf("%04d%02d", year, month-1)
+	}
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return c.Monthly(firstOfMonth.AddDate(0, -1, 0))
+}
+
+// Day returns the day of the month of t.
+func (c Calendar) Day(t time.Time) int {
+	if c == Ethiopian {
+		_, _, day := toEthiopian(t)
+		return day
+	}
+	return t.Day()
+}
+
+// Julian day numbers of the Ethiopian epoch (1 Meskerem 1) and of
+// 1 January 2000.
+const (
+	ethiopianEpoch = 1724221
+	jdn2000        = 2451545
+)
+
+// fromEthiopian returns midnight UTC of an Ethiopian date.
+func fromEthiopian(year, month, day int) time.Time {
+	jdn := ethiopianEpoch - 1 + 365*(year-1) + year/4 + 30*(month-1) + day
+	return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, jdn-jdn2000)
+}
+
+// toEthiopian returns the Ethiopian date of t's calendar day, with Pagume
+// folded into month 12.
+func toEthiopian(t time.Time) (year, month, day int) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	jdn := jdn2000 + int(midnight.Sub(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).Hours()/24)
+
+	year = (4*(jdn-ethiopianEpoch) + 1463) / 1461
+	dayOfYear := jdn - (ethiopianEpoch - 1 + 365*(year-1) + year/4)
+	month, day = (dayOfYear-1)/30+1, (dayOfYear-1)%30+1
+	if month == 13 {
+		month, day = 12, 30+day
+	}
+	return year, month, day
+}
@@ -0,0 +1,112 @@
+// Package dosing holds the IMCI chart booklet dose of the oral drugs the
+// treatment plans prescribe, by the child's weight or, when the weight is
+// not known, age. Drugs are keyed like prescribing.DefaultDrugTable. Injectable
+// pre-referral drugs and drugs whose dose depends on the indication, such as
+// ORS and cotrimoxazole, are not covered and keep the plan's own dosage.
+package dosing
+
+// youngInfantMonths is the age the young infant chart stops at.
+const youngInfantMonths = 2
+
+// band is one row of a dosing table. A row matches on weight when it has a
+// weight range and the weight is known, and on age otherwise. Ranges include
+// their lower bound only.
+type band struct {
+	minKg, maxKg         float64
+	minMonths, maxMonths int
+	dose                 string
+}
+
+func (b band) matches(weightKg float64, ageMonths int) bool {
+	if b.maxKg > 0 && weightKg > 0 {
+		return weightKg >= b.minKg && weightKg < b.maxKg
+	}
+	if b.maxMonths > 0 {
+		return ageMonths >= b.minMonths && ageMonths < b.maxMonths
+	}
+	return false
+}
+
+type table struct {
+	youngInfant []band
+	child       []band
+}
+
+var tables = map[string]table{
+	// Dispersible 250 mg tablets, twice daily.
+	"amoxicillin": {
+		youngInfant: []band{
+			{minKg: 1.5, maxKg: 2.5, dose: "1/2 tablet (125 mg)"},
+			{minKg: 2.5, maxKg: 4, dose: "1 tablet (250 mg)"},
+			{minKg: 4, maxKg: 6, dose: "1 1/2 tablets (375 mg)"},
+		},
+		child: []band{
+			{minKg: 4, maxKg: 10, minMonths: 2, maxMonths: 12, dose: "2 tablets (500 mg)"},
+			{minKg: 10, maxKg: 14, minMonths: 12, maxMonths: 36, dose: "3 tablets (750 mg)"},
+			{minKg: 14, maxKg: 20, minMonths: 36, maxMonths: 60, dose: "4 tablets (1000 mg)"},
+		},
+	},
+	// 100 mg tablets, every 6 hours.
+	"paracetamol": {
+		child: []band{
+			{minKg: 4, maxKg: 14, minMonths: 2, maxMonths: 36, dose: "1 tablet (100 mg)"},
+			{minKg: 14, maxKg: 20, minMonths: 36, maxMonths: 60, dose: "1 1/2 tablets (150 mg)"},
+		},
+	},
+	// 20 mg tablets, once daily for 10 days.
+	"zinc": {
+		youngInfant: []band{
+			{maxMonths: 2, dose: "1/2 tablet (10 mg)"},
+		},
+		child: []band{
+			{minMonths: 2, maxMonths: 6, dose: "1/2 tablet (10 mg)"},
+			{minMonths: 6, maxMonths: 60, dose: "1 tablet (20 mg)"},
+		},
+	},
+	// Single dose.
+	"vitamin_a": {
+		youngInfant: []band{
+			{maxMonths: 2, dose: "50,000 IU"},
+		},
+		child: []band{
+			{minMonths: 2, maxMonths: 6, dose: "50,000 IU"},
+			{minMonths: 6, maxMonths: 12, dose: "100,000 IU"},
+			{minMonths: 12, maxMonths: 60, dose: "200,000 IU"},
+		},
+	},
+	// 20/120 mg tablets, twice daily for 3 days.
+	"artemether_lumefantrine": {
+		child: []band{
+			{minKg: 5, maxKg: 15, minMonths: 2, maxMonths: 36, dose: "1 tablet (20/120 mg)"},
+			{minKg: 15, maxKg: 25, minMonths: 36, maxMonths: 60, dose: "2 tablets (40/240 mg)"},
+		},
+	},
+	// Single dose.
+	"mebendazole_albendazole": {
+		child: []band{
+			{minMonths: 12, maxMonths: 24, dose: "albendazole 200 mg or mebendazole 500 mg"},
+			{minMonths: 24, maxMonths: 60, dose: "albendazole 400 mg or mebendazole 500 mg"},
+		},
+	},
+}
+
+// For returns the dose of one administration of drug for a child of
+// weightKg (0 when not measured) and ageMonths. It reports false when the
+// drug has no table or the child is outside it, e.g. below the lowest
+// weight, in which case the drug is not given by the chart.
+func For(drug string, weightKg float64, ageMonths int) (string, bool) {
+	t, ok := tables[drug]
+	if !ok {
+		return "", false
+	}
+	bands := t.child
+	if ageMonths < youngInfantMonths {
+		bands = t.youngInfant
+	}
+	for _, b := range bands {
+		if b.matches(weightKg, ageMonths) {
+			return b.dose, true
+		}
+	}
+	return "", false
+}
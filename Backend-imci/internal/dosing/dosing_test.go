@@ -0,0 +1,39 @@
+package dosing
+
+import "testing"
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		drug      string
+		weightKg  float64
+		ageMonths int
+		want      string
+		wantOK    bool
+	}{
+		{"amoxicillin by weight", "amoxicillin", 9.9, 30, "2 tablets (500 mg)", true},
+		{"amoxicillin band lower bound", "amoxicillin", 10, 8, "3 tablets (750 mg)", true},
+		{"amoxicillin by age without weight", "amoxicillin", 0, 40, "4 tablets (1000 mg)", true},
+		{"amoxicillin young infant", "amoxicillin", 3.2, 1, "1 tablet (250 mg)", true},
+		{"amoxicillin young infant without weight", "amoxicillin", 0, 1, "", false},
+		{"amoxicillin above the chart", "amoxicillin", 21, 58, "", false},
+		{"paracetamol", "paracetamol", 15, 40, "1 1/2 tablets (150 mg)", true},
+		{"zinc under 6 months", "zinc", 6, 4, "1/2 tablet (10 mg)", true},
+		{"zinc from 6 months", "zinc", 6, 6, "1 tablet (20 mg)", true},
+		{"vitamin A", "vitamin_a", 8, 9, "100,000 IU", true},
+		{"artemether-lumefantrine below 5 kg", "artemether_lumefantrine", 4.5, 3, "", false},
+		{"artemether-lumefantrine", "artemether_lumefantrine", 16, 30, "2 tablets (40/240 mg)", true},
+		{"deworming under 1 year", "mebendazole_albendazole", 8, 10, "", false},
+		{"deworming from 2 years", "mebendazole_albendazole", 12, 24, "albendazole 400 mg or mebendazole 500 mg", true},
+		{"no table", "ceftriaxone", 10, 12, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := For(tt.drug, tt.weightKg, tt.ageMonths)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("expected %q %v, got %q %v", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
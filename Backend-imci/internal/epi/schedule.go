@@ -0,0 +1,205 @@
+// Package epi implements the national Expanded Programme on Immunization
+// schedule: which vaccines a child should have had by a given date, which are
+// due or overdue, and when the next dose falls. It also covers the Vitamin A
+// and deworming supplements delivered alongside routine immunization.
+package epi
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrUnknownVaccine = errors.New("unknown vaccine")
+
+type Vaccine string
+
+const (
+	BCG      Vaccine = "BCG"
+	OPV0     Vaccine = "OPV0"
+	OPV1     Vaccine = "OPV1"
+	OPV2     Vaccine = "OPV2"
+	OPV3     Vaccine = "OPV3"
+	Penta1   Vaccine = "Penta1"
+	Penta2   Vaccine = "Penta2"
+	Penta3   Vaccine = "Penta3"
+	PCV1     Vaccine = "PCV1"
+	PCV2     Vaccine = "PCV2"
+	PCV3     Vaccine = "PCV3"
+	Rota1    Vaccine = "Rota1"
+	Rota2    Vaccine = "Rota2"
+	IPV      Vaccine = "IPV"
+	Measles1 Vaccine = "Measles1"
+	Measles2 Vaccine = "Measles2"
+)
+
+type Status string
+
+const (
+	StatusGiven    Status = "given"
+	StatusDue      Status = "due"
+	StatusOverdue  Status = "overdue"
+	StatusUpcoming Status = "upcoming"
+	// StatusMissed means the child is past the maximum age for the dose and
+	// it should no longer be given.
+	StatusMissed Status = "missed"
+)
+
+// overdueGrace is how long after the due date a dose still counts as due
+// rather than overdue.
+const overdueGrace = 28 * 24 * time.Hour
+
+// minDoseInterval is the minimum spacing between doses of the same series.
+const minDoseInterval = 28 * 24 * time.Hour
+
+// Dose describes one scheduled vaccine dose.
+type Dose struct {
+	Vaccine    Vaccine
+	Antigen    string
+	MinAgeDays int
+	MaxAgeDays int
+	// Previous is the earlier dose of the same series, if any.
+	Previous Vaccine
+	Dosage   string
+	Route    string
+	Site     string
+}
+
+// Schedule is the national EPI schedule in the order doses are given.
+var Schedule = []Dose{
+	{Vaccine: BCG, Antigen: "BCG", MinAgeDays: 0, MaxAgeDays: 365, Dosage: "0.05 ml", Route: "Intradermal", Site: "Right upper arm"},
+	{Vaccine: OPV0, Antigen: "Polio (oral)", MinAgeDays: 0, MaxAgeDays: 14, Dosage: "2 drops", Route: "Oral", Site: "Mouth"},
+	{Vaccine: OPV1, Antigen: "Polio (oral)", MinAgeDays: 42, MaxAgeDays: 1826, Dosage: "2 drops", Route: "Oral", Site: "Mouth"},
+	{Vaccine: Penta1, Antigen: "DPT-HepB-Hib", MinAgeDays: 42, MaxAgeDays: 1826, Dosage: "0.5 ml", Route: "Intramuscular", Site: "Left anterolateral thigh"},
+	{Vaccine: PCV1, Antigen: "Pneumococcal conjugate", MinAgeDays: 42, MaxAgeDays: 730, Dosage: "0.5 ml", Route: "Intramuscular", Site: "Right anterolateral thigh"},
+	{Vaccine: Rota1, Antigen: "Rotavirus", MinAgeDays: 42, MaxAgeDays: 730, Dosage: "1.5 ml", Route: "Oral", Site: "Mouth"},
+	{Vaccine: OPV2, Antigen: "Polio (oral)", MinAgeDays: 70, MaxAgeDays: 1826, Previous: OPV1, Dosage: "2 drops", Route: "Oral", Site: "Mouth"},
+	{Vaccine: Penta2, Antigen: "DPT-HepB-Hib", MinAgeDays: 70, MaxAgeDays: 1826, Previous: Penta1, Dosage: "0.5 ml", Route: "Intramuscular", Site: "Left anterolateral thigh"},
+	{Vaccine: PCV2, Antigen: "Pneumococcal conjugate", MinAgeDays: 70, MaxAgeDays: 730, Previous: PCV1, Dosage: "0.5 ml", Route: "Intramuscular", Site: "Right anterolateral thigh"},
+	{Vaccine: Rota2, Antigen: "Rotavirus", MinAgeDays: 70, MaxAgeDays: 730, Previous: Rota1, Dosage: "1.5 ml", Route: "Oral", Site: "Mouth"},
+	{Vaccine: OPV3, Antigen: "Polio (oral)", MinAgeDays: 98, MaxAgeDays: 1826, Previous: OPV2, Dosage: "2 drops", Route: "Oral", Site: "Mouth"},
+	{Vaccine: Penta3, Antigen: "DPT-HepB-Hib", MinAgeDays: 98, MaxAgeDays: 1826, Previous: Penta2, Dosage: "0.5 ml", Route: "Intramuscular", Site: "Left anterolateral thigh"},
+	{Vaccine: PCV3, Antigen: "Pneumococcal conjugate", MinAgeDays: 98, MaxAgeDays: 730, Previous: PCV2, Dosage: "0.5 ml", Route: "Intramuscular", Site: "Right anterolateral thigh"},
+	{Vaccine: IPV, Antigen: "Polio (inactivated)", MinAgeDays: 98, MaxAgeDays: 1826, Dosage: "0.5 ml", Route: "Intramuscular", Site: "Right anterolateral thigh"},
+	{Vaccine: Measles1, Antigen: "Measles", MinAgeDays: 274, MaxAgeDays: 1826, Dosage: "0.5 ml", Route: "Subcutaneous", Site: "Left upper arm"},
+	{Vaccine: Measles2, Antigen: "Measles", MinAgeDays: 456, MaxAgeDays: 1826, Previous: Measles1, Dosage: "0.5 ml", Route: "Subcutaneous", Site: "Left upper arm"},
+}
+
+// Lookup returns the schedule entry for a vaccine.
+func Lookup(v Vaccine) (Dose, error) {
+	for _, d := range Schedule {
+		if d.Vaccine == v {
+			return d, nil
+		}
+	}
+	return Dose{}, ErrUnknownVaccine
+}
+
+// DoseStatus is the computed state of one scheduled dose for a child.
+type DoseStatus struct {
+	Dose
+	Status  Status
+	DueDate time.Time
+	GivenOn *time.Time
+}
+
+// Evaluate computes the status of every scheduled dose for a child born on
+// dob with the given doses already recorded, as of asOf. A dose whose
+// previous dose has not been given yet becomes due no earlier than the
+// minimum interval after that previous dose would be given.
+func Evaluate(dob time.Time, given map[Vaccine]time.Time, asOf time.Time) []DoseStatus {
+	dob = truncateDay(dob)
+	asOf = truncateDay(asOf)
+
+	statuses := make([]DoseStatus, 0, len(Schedule))
+	byVaccine := make(map[Vaccine]DoseStatus, len(Schedule))
+
+	for _, dose := range Schedule {
+		ds := DoseStatus{Dose: dose}
+		ds.DueDate = dob.AddDate(0, 0, dose.MinAgeDays)
+
+		if dose.Previous != "" {
+			if prev, ok := byVaccine[dose.Previous]; ok {
+				prevDate := prev.DueDate
+				if prev.GivenOn != nil {
+					prevDate = *prev.GivenOn
+				} else if prev.Status == StatusDue || prev.Status == StatusOverdue {
+					// The previous dose would be given today.
+					prevDate = asOf
+				}
+				if earliest := prevDate.Add(minDoseInterval); earliest.After(ds.DueDate) {
+					ds.DueDate = earliest
+				}
+			}
+		}
+
+		if givenOn, ok := given[dose.Vaccine]; ok {
+			g := truncateDay(givenOn)
+			ds.GivenOn = &g
+			ds.Status = StatusGiven
+		} else {
+			ds.Status = statusFor(ds.DueDate, dob.AddDate(0, 0, dose.MaxAgeDays), asOf)
+		}
+
+		byVaccine[dose.Vaccine] = ds
+		statuses = append(statuses, ds)
+	}
+
+	return statuses
+}
+
+func statusFor(dueDate, lastDate, asOf time.Time) Status {
+	switch {
+	case asOf.After(lastDate) || dueDate.After(lastDate):
+		return StatusMissed
+	case dueDate.After(asOf):
+		return StatusUpcoming
+	case asOf.Sub(dueDate) > overdueGrace:
+		return StatusOverdue
+	default:
+		return StatusDue
+	}
+}
+
+// DueToday returns the doses that should be given at a visit on asOf.
+func DueToday(statuses []DoseStatus) []DoseStatus {
+	var due []DoseStatus
+	for _, s := range statuses {
+		if s.Status == StatusDue || s.Status == StatusOverdue {
+			due = append(due, s)
+		}
+	}
+	return due
+}
+
+// NextDue returns the upcoming doses that share the earliest due date, or
+// nil when nothing else is scheduled.
+func NextDue(statuses []DoseStatus) []DoseStatus {
+	var upcoming []DoseStatus
+	for _, s := range statuses {
+		if s.Status == StatusUpcoming {
+			upcoming = append(upcoming, s)
+		}
+	}
+	if len(upcoming) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].DueDate.Before(upcoming[j].DueDate)
+	})
+
+	first := upcoming[0].DueDate
+	var next []DoseStatus
+	for _, s := range upcoming {
+		if s.DueDate.Equal(first) {
+			next = append(next, s)
+		}
+	}
+	return next
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
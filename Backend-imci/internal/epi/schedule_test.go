@@ -0,0 +1,81 @@
+package epi
+
+import (
+	"testing"
+	"time"
+)
+
+func statusOf(statuses []DoseStatus, v Vaccine) DoseStatus {
+	for _, s := range statuses {
+		if s.Vaccine == v {
+			return s
+		}
+	}
+	return DoseStatus{}
+}
+
+func TestEvaluateNewborn(t *testing.T) {
+	dob := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	statuses := Evaluate(dob, nil, dob.AddDate(0, 0, 3))
+
+	for _, v := range []Vaccine{BCG, OPV0} {
+		if got := statusOf(statuses, v).Status; got != StatusDue {
+			t.Errorf("%s: expected due, got %s", v, got)
+		}
+	}
+	if got := statusOf(statuses, Penta1).Status; got != StatusUpcoming {
+		t.Errorf("Penta1: expected upcoming, got %s", got)
+	}
+
+	next := NextDue(statuses)
+	if len(next) == 0 || !next[0].DueDate.Equal(dob.AddDate(0, 0, 42)) {
+		t.Fatalf("expected next doses at 6 weeks, got %+v", next)
+	}
+}
+
+func TestEvaluateCatchUp(t *testing.T) {
+	dob := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	given := map[Vaccine]time.Time{
+		BCG:    dob,
+		OPV0:   dob,
+		OPV1:   dob.AddDate(0, 0, 42),
+		Penta1: dob.AddDate(0, 0, 42),
+		PCV1:   dob.AddDate(0, 0, 42),
+		Rota1:  dob.AddDate(0, 0, 42),
+	}
+	asOf := dob.AddDate(0, 5, 0)
+	statuses := Evaluate(dob, given, asOf)
+
+	if got := statusOf(statuses, Penta2).Status; got != StatusOverdue {
+		t.Errorf("Penta2: expected overdue, got %s", got)
+	}
+
+	// Penta3 cannot be given the same day as Penta2.
+	penta3 := statusOf(statuses, Penta3)
+	if penta3.Status != StatusUpcoming {
+		t.Errorf("Penta3: expected upcoming, got %s", penta3.Status)
+	}
+	if want := asOf.Add(minDoseInterval); !penta3.DueDate.Equal(want) {
+		t.Errorf("Penta3: expected due %s, got %s", want, penta3.DueDate)
+	}
+
+	due := DueToday(statuses)
+	want := map[Vaccine]bool{OPV2: true, Penta2: true, PCV2: true, Rota2: true, IPV: true}
+	if len(due) != len(want) {
+		t.Fatalf("expected %d doses due today, got %d: %+v", len(want), len(due), due)
+	}
+	for _, d := range due {
+		if !want[d.Vaccine] {
+			t.Errorf("unexpected dose due today: %s", d.Vaccine)
+		}
+	}
+}
+
+func TestEvaluateMissedAfterMaxAge(t *testing.T) {
+	dob := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	statuses := Evaluate(dob, nil, dob.AddDate(0, 1, 0))
+
+	if got := statusOf(statuses, OPV0).Status; got != StatusMissed {
+		t.Errorf("OPV0: expected missed after two weeks, got %s", got)
+	}
+}
@@ -0,0 +1,92 @@
+package epi
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrUnknownSupplement = errors.New("unknown supplement")
+
+// Supplement is a preventive supplement given alongside the immunization
+// schedule.
+type Supplement string
+
+const (
+	VitaminA  Supplement = "vitamin_a"
+	Deworming Supplement = "deworming"
+)
+
+// supplementIntervalMonths is the spacing between routine supplement doses.
+const supplementIntervalMonths = 6
+
+// SupplementEligibility is the computed state of a supplement for a child.
+type SupplementEligibility struct {
+	Supplement Supplement
+	// Eligible is false when the child is outside the age band for the
+	// supplement.
+	Eligible bool
+	// Due is true when the child is eligible and has had no dose in the last
+	// six months.
+	Due         bool
+	LastDose    *time.Time
+	NextDueDate *time.Time
+	Drug        string
+	Dosage      string
+	Route       string
+}
+
+// EvaluateSupplement works out whether a supplement is due for a child born
+// on dob whose most recent dose (if any) was given on lastDose.
+//
+// Vitamin A is given every six months from 6 to 59 months: 100,000 IU under
+// 12 months and 200,000 IU after. Deworming is given every six months from
+// 24 to 59 months.
+func EvaluateSupplement(s Supplement, dob time.Time, lastDose *time.Time, asOf time.Time) (SupplementEligibility, error) {
+	dob = truncateDay(dob)
+	asOf = truncateDay(asOf)
+
+	e := SupplementEligibility{Supplement: s, Route: "Oral"}
+	if lastDose != nil {
+		l := truncateDay(*lastDose)
+		e.LastDose = &l
+	}
+
+	var startMonths int
+	switch s {
+	case VitaminA:
+		startMonths = 6
+		e.Drug = "Vitamin A"
+		e.Dosage = "200,000 IU"
+		if asOf.Before(dob.AddDate(0, 12, 0)) {
+			e.Dosage = "100,000 IU"
+		}
+	case Deworming:
+		startMonths = 24
+		e.Drug = "Albendazole"
+		e.Dosage = "400 mg"
+	default:
+		return SupplementEligibility{}, ErrUnknownSupplement
+	}
+
+	firstDue := dob.AddDate(0, startMonths, 0)
+	lastEligible := dob.AddDate(0, 60, 0)
+
+	nextDue := firstDue
+	if e.LastDose != nil {
+		if afterLast := e.LastDose.AddDate(0, supplementIntervalMonths, 0); afterLast.After(nextDue) {
+			nextDue = afterLast
+		}
+	}
+
+	if !asOf.Before(lastEligible) {
+		return e, nil
+	}
+	if nextDue.Before(lastEligible) {
+		e.NextDueDate = &nextDue
+	}
+
+	e.Eligible = !asOf.Before(firstDue)
+	e.Due = e.Eligible && !nextDue.After(asOf)
+
+	return e, nil
+}
@@ -0,0 +1,48 @@
+package epi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateSupplement(t *testing.T) {
+	dob := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := dob.AddDate(0, 28, 0)
+
+	tests := []struct {
+		name         string
+		supplement   Supplement
+		lastDose     *time.Time
+		asOf         time.Time
+		wantEligible bool
+		wantDue      bool
+		wantDosage   string
+	}{
+		{"vitamin A before 6 months", VitaminA, nil, dob.AddDate(0, 4, 0), false, false, "100,000 IU"},
+		{"vitamin A infant never given", VitaminA, nil, dob.AddDate(0, 7, 0), true, true, "100,000 IU"},
+		{"vitamin A given recently", VitaminA, &recent, dob.AddDate(0, 30, 0), true, false, "200,000 IU"},
+		{"vitamin A given over 6 months ago", VitaminA, &recent, dob.AddDate(0, 35, 0), true, true, "200,000 IU"},
+		{"deworming before 2 years", Deworming, nil, dob.AddDate(0, 20, 0), false, false, "400 mg"},
+		{"deworming never given", Deworming, nil, dob.AddDate(0, 26, 0), true, true, "400 mg"},
+		{"deworming after 5 years", Deworming, nil, dob.AddDate(0, 61, 0), false, false, "400 mg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateSupplement(tt.supplement, dob, tt.lastDose, tt.asOf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Eligible != tt.wantEligible || got.Due != tt.wantDue {
+				t.Errorf("eligible=%v due=%v, want eligible=%v due=%v", got.Eligible, got.Due, tt.wantEligible, tt.wantDue)
+			}
+			if got.Dosage != tt.wantDosage {
+				t.Errorf("dosage=%q, want %q", got.Dosage, tt.wantDosage)
+			}
+		})
+	}
+
+	if _, err := EvaluateSupplement("zinc", dob, nil, dob); err != ErrUnknownSupplement {
+		t.Errorf("expected ErrUnknownSupplement, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package fhir
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnsupportedResource = errors.New("unsupported FHIR resource type")
+	ErrInvalidBundle       = errors.New("invalid FHIR bundle")
+)
+
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	ID           string        `json:"id,omitempty"`
+	Meta         *Meta         `json:"meta,omitempty"`
+	Type         string        `json:"type"`
+	Timestamp    string        `json:"timestamp,omitempty"`
+	Total        *int          `json:"total,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+type BundleEntry struct {
+	FullURL  string        `json:"fullUrl,omitempty"`
+	Resource Resource      `json:"resource"`
+	Search   *BundleSearch `json:"search,omitempty"`
+}
+
+type BundleSearch struct {
+	Mode string `json:"mode"`
+}
+
+// NewSearchset returns an empty searchset bundle, the shape $everything
+// responds with.
+func NewSearchset(id, timestamp string) *Bundle {
+	return &Bundle{
+		ResourceType: "Bundle",
+		ID:           id,
+		Meta:         &Meta{LastUpdated: timestamp},
+		Type:         "searchset",
+		Timestamp:    timestamp,
+	}
+}
+
+// Add appends resources as matches; the base URL, when set, makes the full
+// URL absolute.
+func (b *Bundle) Add(base string, resources ...Resource) {
+	for _, r := range resources {
+		fullURL := r.FHIRType() + "/" + r.ResourceID()
+		if base != "" {
+			fullURL = strings.TrimRight(base, "/") + "/" + fullURL
+		}
+		b.Entry = append(b.Entry, BundleEntry{
+			FullURL:  fullURL,
+			Resource: r,
+			Search:   &BundleSearch{Mode: "match"},
+		})
+	}
+	total := len(b.Entry)
+	b.Total = &total
+}
+
+// Resources returns the entries of type T in bundle order.
+func Resources[T Resource](b *Bundle) []T {
+	var out []T
+	for _, e := range b.Entry {
+		if r, ok := e.Resource.(T); ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (e *BundleEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		FullURL  string          `json:"fullUrl"`
+		Resource json.RawMessage `json:"resource"`
+		Search   *BundleSearch   `json:"search"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var head struct {
+		ResourceType string `json:"resourceType"`
+	}
+	if err := json.Unmarshal(raw.Resource, &head); err != nil {
+		return err
+	}
+
+	var r Resource
+	switch head.ResourceType {
+	case "Patient":
+		r = &Patient{}
+	case "Encounter":
+		r = &Encounter{}
+	case "Observation":
+		r = &Observation{}
+	case "Condition":
+		r = &Condition{}
+	case "MedicationRequest":
+		r = &MedicationRequest{}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedResource, head.ResourceType)
+	}
+	if err := json.Unmarshal(raw.Resource, r); err != nil {
+		return err
+	}
+
+	e.FullURL, e.Resource, e.Search = raw.FullURL, r, raw.Search
+	return nil
+}
+
+// Validate checks the bundle against the rules the exchange enforces:
+// required elements are present, resource IDs are unique and every
+// reference between resources resolves inside the bundle. Practitioner
+// references point at the facility registry and are not checked.
+func Validate(b *Bundle) error {
+	if b.ResourceType != "Bundle" || b.Type == "" {
+		return fmt.Errorf("%w: resourceType and type are required", ErrInvalidBundle)
+	}
+
+	ids := make(map[string]bool)
+	for i, e := range b.Entry {
+		if e.Resource == nil || e.Resource.ResourceID() == "" {
+			return fmt.Errorf("%w: entry %d has no resource id", ErrInvalidBundle, i)
+		}
+		key := e.Resource.FHIRType() + "/" + e.Resource.ResourceID()
+		if ids[key] {
+			return fmt.Errorf("%w: duplicate resource %s", ErrInvalidBundle, key)
+		}
+		ids[key] = true
+	}
+
+	var problems []string
+	check := func(key, field string, ref *Reference) {
+		switch {
+		case ref == nil || ref.Reference == "":
+			problems = append(problems, key+": "+field+" is required")
+		case !ids[ref.Reference] && !strings.HasPrefix(ref.Reference, "Practitioner/"):
+			problems = append(problems, key+": "+field+" "+ref.Reference+" does not resolve")
+		}
+	}
+	required := func(key, field, value string) {
+		if value == "" {
+			problems = append(problems, key+": "+field+" is required")
+		}
+	}
+
+	for _, e := range b.Entry {
+		key := e.Resource.FHIRType() + "/" + e.Resource.ResourceID()
+		switch r := e.Resource.(type) {
+		case *Encounter:
+			required(key, "status", r.Status)
+			required(key, "class", r.Class.Code)
+			check(key, "subject", r.Subject)
+			for _, p := range r.Participant {
+				check(key, "participant.individual", p.Individual)
+			}
+			for _, d := range r.Diagnosis {
+				check(key, "diagnosis.condition", &d.Condition)
+			}
+		case *Observation:
+			required(key, "status", r.Status)
+			if len(r.Code.Coding) == 0 {
+				problems = append(problems, key+": code is required")
+			}
+			check(key, "subject", r.Subject)
+			check(key, "encounter", r.Encounter)
+		case *Condition:
+			check(key, "subject", &r.Subject)
+			check(key, "encounter", r.Encounter)
+		case *MedicationRequest:
+			required(key, "status", r.Status)
+			required(key, "intent", r.Intent)
+			if r.MedicationCodeableConcept == nil {
+				problems = append(problems, key+": medication is required")
+			}
+			check(key, "subject", &r.Subject)
+			check(key, "encounter", r.Encounter)
+			for _, reason := range r.ReasonReference {
+				check(key, "reasonReference", &reason)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidBundle, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package fhir
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	patient := &Patient{ResourceType: "Patient", ID: "p1"}
+	encounter := &Encounter{
+		ResourceType: "Encounter",
+		ID:           "e1",
+		Status:       "finished",
+		Class:        Coding{System: ActCode, Code: "AMB"},
+		Subject:      Ref(patient),
+		Participant:  []EncounterParticipant{{Individual: &Reference{Reference: "Practitioner/x"}}},
+	}
+
+	b := NewSearchset("b1", "2026-03-02T09:00:00Z")
+	b.Add("https://imci.example/fhir/", patient, encounter)
+	if err := Validate(b); err != nil {
+		t.Fatalf("valid bundle rejected: %v", err)
+	}
+	if b.Entry[1].FullURL != "https://imci.example/fhir/Encounter/e1" {
+		t.Errorf("fullUrl = %q", b.Entry[1].FullURL)
+	}
+
+	b.Add("", &Condition{ResourceType: "Condition", ID: "c1", Subject: *Ref(patient), Encounter: &Reference{Reference: "Encounter/missing"}})
+	err := Validate(b)
+	if !errors.Is(err, ErrInvalidBundle) || !strings.Contains(err.Error(), "Encounter/missing does not resolve") {
+		t.Fatalf("expected a dangling reference error, got %v", err)
+	}
+
+	b.Add("", &Patient{ResourceType: "Patient", ID: "p1"})
+	if err := Validate(b); err == nil || !strings.Contains(err.Error(), "duplicate resource Patient/p1") {
+		t.Fatalf("expected a duplicate resource error, got %v", err)
+	}
+}
+
+func TestBundleEntryUnmarshal(t *testing.T) {
+	var b Bundle
+	data := `{"resourceType":"Bundle","type":"searchset","entry":[{"resource":{"resourceType":"Condition","id":"c1","subject":{"reference":"Patient/p1"}}}]}`
+	if err := json.Unmarshal([]byte(data), &b); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if c, ok := b.Entry[0].Resource.(*Condition); !ok || c.Subject.Reference != "Patient/p1" {
+		t.Fatalf("expected a Condition, got %#v", b.Entry[0].Resource)
+	}
+
+	data = `{"resourceType":"Bundle","type":"searchset","entry":[{"resource":{"resourceType":"Procedure","id":"x"}}]}`
+	if err := json.Unmarshal([]byte(data), &b); !errors.Is(err, ErrUnsupportedResource) {
+		t.Fatalf("expected ErrUnsupportedResource, got %v", err)
+	}
+}
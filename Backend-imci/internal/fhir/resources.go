@@ -0,0 +1,202 @@
+// Package fhir models the subset of HL7 FHIR R4 used to exchange IMCI data
+// with health information exchanges: Patient, Encounter, Observation,
+// Condition and MedicationRequest, collected in a Bundle.
+package fhir
+
+// Code systems and units shared by the mappings.
+const (
+	LOINC        = "http://loinc.org"
+	SNOMED       = "http://snomed.info/sct"
+	UCUM         = "http://unitsofmeasure.org"
+	ActCode      = "http://terminology.hl7.org/CodeSystem/v3-ActCode"
+	ObsCategory  = "http://terminology.hl7.org/CodeSystem/observation-category"
+	CondClinical = "http://terminology.hl7.org/CodeSystem/condition-clinical"
+	CondCategory = "http://terminology.hl7.org/CodeSystem/condition-category"
+)
+
+const (
+	ContentType = "application/fhir+json"
+	// DateLayout formats FHIR date values; dateTime and instant values use
+	// time.RFC3339Nano.
+	DateLayout = "2006-01-02"
+)
+
+// Resource is implemented by every resource that can appear in a Bundle.
+type Resource interface {
+	FHIRType() string
+	ResourceID() string
+}
+
+type Meta struct {
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Code returns the code from system, or "" when there is none.
+func (c *CodeableConcept) Code(system string) string {
+	if c == nil {
+		return ""
+	}
+	for _, coding := range c.Coding {
+		if coding.System == system {
+			return coding.Code
+		}
+	}
+	return ""
+}
+
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+type Reference struct {
+	Reference string `json:"reference,omitempty"`
+	Display   string `json:"display,omitempty"`
+}
+
+type Period struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit,omitempty"`
+	System string  `json:"system,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+type Annotation struct {
+	Text string `json:"text"`
+}
+
+// Extension carries IMCI data that has no home in the base resources.
+type Extension struct {
+	URL          string `json:"url"`
+	ValueBoolean *bool  `json:"valueBoolean,omitempty"`
+	ValueString  string `json:"valueString,omitempty"`
+	ValueCode    string `json:"valueCode,omitempty"`
+	ValueInteger *int   `json:"valueInteger,omitempty"`
+}
+
+type HumanName struct {
+	Text string `json:"text,omitempty"`
+}
+
+type Patient struct {
+	ResourceType string       `json:"resourceType"`
+	ID           string       `json:"id"`
+	Meta         *Meta        `json:"meta,omitempty"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
+	Name         []HumanName  `json:"name,omitempty"`
+	Gender       string       `json:"gender,omitempty"`
+	BirthDate    string       `json:"birthDate,omitempty"`
+}
+
+type EncounterParticipant struct {
+	Individual *Reference `json:"individual,omitempty"`
+}
+
+type EncounterDiagnosis struct {
+	Condition Reference `json:"condition"`
+	Rank      int       `json:"rank,omitempty"`
+}
+
+type Encounter struct {
+	ResourceType string                 `json:"resourceType"`
+	ID           string                 `json:"id"`
+	Meta         *Meta                  `json:"meta,omitempty"`
+	Extension    []Extension            `json:"extension,omitempty"`
+	Status       string                 `json:"status"`
+	Class        Coding                 `json:"class"`
+	Type         []CodeableConcept      `json:"type,omitempty"`
+	Subject      *Reference             `json:"subject,omitempty"`
+	Participant  []EncounterParticipant `json:"participant,omitempty"`
+	Period       *Period                `json:"period,omitempty"`
+	Diagnosis    []EncounterDiagnosis   `json:"diagnosis,omitempty"`
+}
+
+type Observation struct {
+	ResourceType      string            `json:"resourceType"`
+	ID                string            `json:"id"`
+	Status            string            `json:"status"`
+	Category          []CodeableConcept `json:"category,omitempty"`
+	Code              CodeableConcept   `json:"code"`
+	Subject           *Reference        `json:"subject,omitempty"`
+	Encounter         *Reference        `json:"encounter,omitempty"`
+	EffectiveDateTime string            `json:"effectiveDateTime,omitempty"`
+	ValueQuantity     *Quantity         `json:"valueQuantity,omitempty"`
+}
+
+type Condition struct {
+	ResourceType   string            `json:"resourceType"`
+	ID             string            `json:"id"`
+	Extension      []Extension       `json:"extension,omitempty"`
+	ClinicalStatus *CodeableConcept  `json:"clinicalStatus,omitempty"`
+	Category       []CodeableConcept `json:"category,omitempty"`
+	Severity       *CodeableConcept  `json:"severity,omitempty"`
+	Code           *CodeableConcept  `json:"code,omitempty"`
+	Subject        Reference         `json:"subject"`
+	Encounter      *Reference        `json:"encounter,omitempty"`
+	RecordedDate   string            `json:"recordedDate,omitempty"`
+	Note           []Annotation      `json:"note,omitempty"`
+}
+
+type Timing struct {
+	Repeat *TimingRepeat    `json:"repeat,omitempty"`
+	Code   *CodeableConcept `json:"code,omitempty"`
+}
+
+type TimingRepeat struct {
+	BoundsDuration *Quantity `json:"boundsDuration,omitempty"`
+}
+
+type Dosage struct {
+	Text                  string            `json:"text,omitempty"`
+	AdditionalInstruction []CodeableConcept `json:"additionalInstruction,omitempty"`
+	PatientInstruction    string            `json:"patientInstruction,omitempty"`
+	Timing                *Timing           `json:"timing,omitempty"`
+	Route                 *CodeableConcept  `json:"route,omitempty"`
+}
+
+type MedicationRequest struct {
+	ResourceType              string           `json:"resourceType"`
+	ID                        string           `json:"id"`
+	Status                    string           `json:"status"`
+	Intent                    string           `json:"intent"`
+	Priority                  string           `json:"priority,omitempty"`
+	MedicationCodeableConcept *CodeableConcept `json:"medicationCodeableConcept,omitempty"`
+	Subject                   Reference        `json:"subject"`
+	Encounter                 *Reference       `json:"encounter,omitempty"`
+	AuthoredOn                string           `json:"authoredOn,omitempty"`
+	ReasonReference           []Reference      `json:"reasonReference,omitempty"`
+	DosageInstruction         []Dosage         `json:"dosageInstruction,omitempty"`
+}
+
+func (r *Patient) FHIRType() string           { return "Patient" }
+func (r *Encounter) FHIRType() string         { return "Encounter" }
+func (r *Observation) FHIRType() string       { return "Observation" }
+func (r *Condition) FHIRType() string         { return "Condition" }
+func (r *MedicationRequest) FHIRType() string { return "MedicationRequest" }
+
+func (r *Patient) ResourceID() string           { return r.ID }
+func (r *Encounter) ResourceID() string         { return r.ID }
+func (r *Observation) ResourceID() string       { return r.ID }
+func (r *Condition) ResourceID() string         { return r.ID }
+func (r *MedicationRequest) ResourceID() string { return r.ID }
+
+// Ref builds a relative reference to a resource.
+func Ref(r Resource) *Reference {
+	return &Reference{Reference: r.FHIRType() + "/" + r.ResourceID()}
+}
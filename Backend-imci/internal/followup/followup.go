@@ -0,0 +1,42 @@
+// Package followup turns the free-text follow-up advice attached to IMCI
+// outcomes ("Follow-up in 5 days", "Follow-up after 2 days of antibiotics")
+// into a concrete follow-up date.
+package followup
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var daysPattern = regexp.MustCompile(`(?i)\b(?:in|after)\s+(\d{1,3})\s+days?\b`)
+
+// Days returns the earliest follow-up interval, in days, mentioned in the
+// advice, and false when none of the lines names one.
+func Days(advice []string) (int, bool) {
+	best, found := 0, false
+	for _, line := range advice {
+		for _, m := range daysPattern.FindAllStringSubmatch(line, -1) {
+			n, err := strconv.Atoi(m[1])
+			if err != nil || n <= 0 {
+				continue
+			}
+			if !found || n < best {
+				best, found = n, true
+			}
+		}
+	}
+	return best, found
+}
+
+// Date returns the follow-up day counted from the visit, or nil when the
+// advice does not name an interval.
+func Date(visit time.Time, advice []string) *time.Time {
+	days, ok := Days(advice)
+	if !ok {
+		return nil
+	}
+	y, m, d := visit.Date()
+	date := time.Date(y, m, d+days, 0, 0, 0, 0, time.UTC)
+	return &date
+}
@@ -0,0 +1,39 @@
+package followup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDays(t *testing.T) {
+	cases := []struct {
+		advice []string
+		want   int
+		ok     bool
+	}{
+		{[]string{"Follow-up in 5 days if not improving"}, 5, true},
+		{[]string{"Follow-up after 2 days of Ampicillin", "Follow up in 30 days"}, 2, true},
+		{[]string{"Follow-up underweight in 14 days"}, 14, true},
+		{[]string{"Follow-up visits at age 6-24 hrs, 3 days, 7 days & 6 weeks"}, 0, false},
+		{[]string{"Continue follow-up as scheduled"}, 0, false},
+		{nil, 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := Days(tc.advice)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("Days(%q) = %d, %v; want %d, %v", tc.advice, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	visit := time.Date(2024, 1, 30, 15, 4, 0, 0, time.UTC)
+	got := Date(visit, []string{"Follow-up in 2 days"})
+	want := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if got == nil || !got.Equal(want) {
+		t.Fatalf("Date = %v, want %v", got, want)
+	}
+	if Date(visit, []string{"Follow-up as needed"}) != nil {
+		t.Fatal("expected no date without an interval")
+	}
+}
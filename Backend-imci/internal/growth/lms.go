@@ -0,0 +1,217 @@
+// Package growth computes WHO Child Growth Standards z-scores from the
+// WHO Anthro LMS reference tables loaded by LoadReference.
+package growth
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	ErrUnknownSex         = errors.New("growth reference requires male or female sex")
+	ErrOutOfRange         = errors.New("measurement outside WHO reference range")
+	ErrInvalidMeasure     = errors.New("measurement must be greater than zero")
+	ErrUnknownIndicator   = errors.New("unknown growth indicator")
+	ErrReferenceNotLoaded = errors.New("growth reference table not loaded")
+)
+
+type Sex string
+
+const (
+	Male   Sex = "male"
+	Female Sex = "female"
+)
+
+type Indicator string
+
+const (
+	WeightForAge       Indicator = "weight_for_age"
+	LengthHeightForAge Indicator = "length_height_for_age"
+	WeightForLength    Indicator = "weight_for_length"
+	WeightForHeight    Indicator = "weight_for_height"
+	MUACForAge         Indicator = "muac_for_age"
+)
+
+// Position is how a child was measured. WHO length tables assume the child
+// is lying down (recumbent) and height tables assume standing.
+type Position string
+
+const (
+	Lying    Position = "lying"
+	Standing Position = "standing"
+)
+
+// lyingStandingDiff is the WHO adjustment (cm) between recumbent length and
+// standing height for the same child.
+const lyingStandingDiff = 0.7
+
+// daysPerMonth is the WHO average month length used to convert ages.
+const daysPerMonth = 30.4375
+
+// LMS holds the Box-Cox power (L), median (M) and coefficient of variation
+// (S) for one reference row. X is the age in days or the length/height in
+// centimetres depending on the table.
+type LMS struct {
+	X float64
+	L float64
+	M float64
+	S float64
+}
+
+type table []LMS
+
+// lookup returns the LMS parameters at x. Age tables have a row for every
+// day; a length or height between two 0.1 cm rows is linearly interpolated,
+// as WHO Anthro does.
+func (t table) lookup(x float64) (LMS, error) {
+	if len(t) == 0 || x < t[0].X || x > t[len(t)-1].X {
+		return LMS{}, ErrOutOfRange
+	}
+
+	i := sort.Search(len(t), func(i int) bool { return t[i].X >= x })
+	if t[i].X == x {
+		return t[i], nil
+	}
+
+	lo, hi := t[i-1], t[i]
+	f := (x - lo.X) / (hi.X - lo.X)
+	return LMS{
+		X: x,
+		L: lo.L + f*(hi.L-lo.L),
+		M: lo.M + f*(hi.M-lo.M),
+		S: lo.S + f*(hi.S-lo.S),
+	}, nil
+}
+
+// Z returns the z-score of measurement y for these LMS parameters.
+func (p LMS) Z(y float64) float64 {
+	if p.L == 0 {
+		return math.Log(y/p.M) / p.S
+	}
+	return (math.Pow(y/p.M, p.L) - 1) / (p.L * p.S)
+}
+
+// valueAt returns the measurement that corresponds to z-score z.
+func (p LMS) valueAt(z float64) float64 {
+	if p.L == 0 {
+		return p.M * math.Exp(p.S*z)
+	}
+	return p.M * math.Pow(1+p.L*p.S*z, 1/p.L)
+}
+
+// restrictedZ applies the WHO adjustment for weight and arm circumference
+// indicators beyond ±3 SD, where the LMS curve is stretched and would
+// otherwise exaggerate extreme values.
+func (p LMS) restrictedZ(y float64) float64 {
+	z := p.Z(y)
+	switch {
+	case z > 3:
+		sd3 := p.valueAt(3)
+		sd23 := sd3 - p.valueAt(2)
+		return 3 + (y-sd3)/sd23
+	case z < -3:
+		sd3 := p.valueAt(-3)
+		sd23 := p.valueAt(-2) - sd3
+		return -3 + (y-sd3)/sd23
+	default:
+		return z
+	}
+}
+
+func (r *Reference) table(indicator Indicator, sex Sex) (table, error) {
+	if sex != Male && sex != Female {
+		return nil, ErrUnknownSex
+	}
+	if _, ok := referenceFiles[indicator]; !ok {
+		return nil, ErrUnknownIndicator
+	}
+	if r == nil || len(r.tables[indicator][sex]) == 0 {
+		return nil, ErrReferenceNotLoaded
+	}
+	return r.tables[indicator][sex], nil
+}
+
+// AgeInMonths converts an age in days to WHO months.
+func AgeInMonths(ageDays int) float64 {
+	return float64(ageDays) / daysPerMonth
+}
+
+// WeightForAgeZ returns the weight-for-age z-score.
+func (r *Reference) WeightForAgeZ(sex Sex, ageDays int, weightKg float64) (float64, error) {
+	return r.ageBasedZ(WeightForAge, sex, ageDays, weightKg, true)
+}
+
+// MUACForAgeZ returns the MUAC-for-age z-score. The reference starts at 3
+// months (91 days) of age.
+func (r *Reference) MUACForAgeZ(sex Sex, ageDays int, muacCm float64) (float64, error) {
+	return r.ageBasedZ(MUACForAge, sex, ageDays, muacCm, true)
+}
+
+// LengthHeightForAgeZ returns the length/height-for-age z-score. Children
+// under 24 months are compared against length and older children against
+// height, so a measurement taken in the other position is corrected by 0.7 cm.
+func (r *Reference) LengthHeightForAgeZ(sex Sex, ageDays int, lengthCm float64, position Position) (float64, error) {
+	months := AgeInMonths(ageDays)
+	adjusted := lengthCm
+	if months < 24 && position == Standing {
+		adjusted += lyingStandingDiff
+	} else if months >= 24 && position == Lying {
+		adjusted -= lyingStandingDiff
+	}
+	return r.ageBasedZ(LengthHeightForAge, sex, ageDays, adjusted, false)
+}
+
+// WeightForLengthHeightZ returns the weight-for-length z-score for children
+// under 24 months and weight-for-height for older children, correcting the
+// measured length or height for position first.
+func (r *Reference) WeightForLengthHeightZ(sex Sex, ageDays int, weightKg, lengthCm float64, position Position) (float64, Indicator, error) {
+	if weightKg <= 0 || lengthCm <= 0 {
+		return 0, "", ErrInvalidMeasure
+	}
+
+	indicator := WeightForLength
+	adjusted := lengthCm
+	if AgeInMonths(ageDays) >= 24 {
+		indicator = WeightForHeight
+		if position == Lying {
+			adjusted -= lyingStandingDiff
+		}
+	} else if position == Standing {
+		adjusted += lyingStandingDiff
+	}
+
+	t, err := r.table(indicator, sex)
+	if err != nil {
+		return 0, "", err
+	}
+	p, err := t.lookup(adjusted)
+	if err != nil {
+		return 0, "", err
+	}
+	return round2(p.restrictedZ(weightKg)), indicator, nil
+}
+
+func (r *Reference) ageBasedZ(indicator Indicator, sex Sex, ageDays int, y float64, restricted bool) (float64, error) {
+	if y <= 0 {
+		return 0, ErrInvalidMeasure
+	}
+
+	t, err := r.table(indicator, sex)
+	if err != nil {
+		return 0, err
+	}
+	p, err := t.lookup(float64(ageDays))
+	if err != nil {
+		return 0, err
+	}
+
+	if restricted {
+		return round2(p.restrictedZ(y)), nil
+	}
+	return round2(p.Z(y)), nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,228 @@
+package growth
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testdata holds rows copied from the WHO Anthro reference files. The
+// published SD values below are from the WHO Child Growth Standards
+// z-score tables for the same rows.
+
+// testMUACRows stands in for acanthro.txt so that the reference loads. The
+// values are made up, so no test checks MUAC z-scores against them.
+const testMUACRows = "sex\tage\tl\tm\ts\n1\t91\t1\t13.5\t0.08\n2\t91\t1\t13.2\t0.08\n"
+
+// testReferenceFS returns the testdata files plus the stand-in MUAC table.
+func testReferenceFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+	files := fstest.MapFS{"acanthro.txt": {Data: []byte(testMUACRows)}}
+	entries, err := fs.ReadDir(os.DirFS("testdata"), ".")
+	if err != nil {
+		t.Fatalf("failed to list testdata: %v", err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile("testdata/" + entry.Name())
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", entry.Name(), err)
+		}
+		files[entry.Name()] = &fstest.MapFile{Data: data}
+	}
+	return files
+}
+
+func loadTestReference(t *testing.T) *Reference {
+	t.Helper()
+	r, err := LoadReference(testReferenceFS(t))
+	if err != nil {
+		t.Fatalf("failed to load reference: %v", err)
+	}
+	return r
+}
+
+func TestPublishedSDValues(t *testing.T) {
+	r := loadTestReference(t)
+
+	tests := []struct {
+		name      string
+		indicator Indicator
+		sex       Sex
+		x         float64
+		// sd holds the published -3 to +3 SD values.
+		sd [7]float64
+	}{
+		{"weight-for-age boys at birth", WeightForAge, Male, 0, [7]float64{2.1, 2.5, 2.9, 3.3, 3.9, 4.4, 5.0}},
+		{"weight-for-age girls at birth", WeightForAge, Female, 0, [7]float64{2.0, 2.4, 2.8, 3.2, 3.7, 4.2, 4.8}},
+		{"length-for-age boys at birth", LengthHeightForAge, Male, 0, [7]float64{44.2, 46.1, 48.0, 49.9, 51.8, 53.7, 55.6}},
+		{"length-for-age girls at birth", LengthHeightForAge, Female, 0, [7]float64{43.6, 45.4, 47.3, 49.1, 51.0, 52.9, 54.7}},
+		{"weight-for-length boys at 45 cm", WeightForLength, Male, 45, [7]float64{1.9, 2.0, 2.2, 2.4, 2.7, 3.0, 3.3}},
+		{"weight-for-length girls at 45 cm", WeightForLength, Female, 45, [7]float64{1.9, 2.1, 2.3, 2.5, 2.7, 3.0, 3.3}},
+		{"weight-for-height boys at 65 cm", WeightForHeight, Male, 65, [7]float64{5.9, 6.3, 6.9, 7.4, 8.1, 8.8, 9.6}},
+		{"weight-for-height girls at 65 cm", WeightForHeight, Female, 65, [7]float64{5.6, 6.1, 6.6, 7.2, 7.9, 8.7, 9.7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl, err := r.table(tt.indicator, tt.sex)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			p, err := tbl.lookup(tt.x)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i, want := range tt.sd {
+				z := float64(i - 3)
+				if got := math.Round(p.valueAt(z)*10) / 10; got != want {
+					t.Errorf("%+v SD = %v, want %v", z, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestZScoresAtPublishedValues(t *testing.T) {
+	r := loadTestReference(t)
+
+	z, err := r.WeightForAgeZ(Male, 0, 3.3464)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if z != 0 {
+		t.Errorf("expected z=0 at the median, got %v", z)
+	}
+
+	// 2.5 kg is the published -2 SD weight of a newborn boy.
+	z, err = r.WeightForAgeZ(Male, 0, 2.5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if z < -2.1 || z > -1.9 {
+		t.Errorf("expected z around -2, got %v", z)
+	}
+
+	z, indicator, err := r.WeightForLengthHeightZ(Male, 20, 2.441, 45, Lying)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if indicator != WeightForLength {
+		t.Errorf("expected %s, got %s", WeightForLength, indicator)
+	}
+	if z != 0 {
+		t.Errorf("expected z=0 at the median, got %v", z)
+	}
+}
+
+func TestWeightForHeightForOlderChildren(t *testing.T) {
+	r := loadTestReference(t)
+
+	// A 3 year old measured lying is corrected to standing height.
+	z, indicator, err := r.WeightForLengthHeightZ(Female, 1100, 7.2402, 65.7, Lying)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if indicator != WeightForHeight {
+		t.Errorf("expected %s, got %s", WeightForHeight, indicator)
+	}
+	if z != 0 {
+		t.Errorf("expected z=0 at the median, got %v", z)
+	}
+}
+
+func TestLengthHeightForAgePositionCorrection(t *testing.T) {
+	r := loadTestReference(t)
+
+	lying, err := r.LengthHeightForAgeZ(Male, 0, 49.8842, Lying)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	standing, err := r.LengthHeightForAgeZ(Male, 0, 49.1842, Standing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lying != 0 || standing != 0 {
+		t.Errorf("expected standing measurement to be corrected, got %v and %v", lying, standing)
+	}
+}
+
+func TestLookupInterpolatesLength(t *testing.T) {
+	tbl := table{{X: 45.0, L: -0.35, M: 2.4, S: 0.09}, {X: 45.1, L: -0.35, M: 2.5, S: 0.09}}
+	p, err := tbl.lookup(45.05)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(p.M-2.45) > 1e-9 {
+		t.Errorf("expected interpolated median 2.45, got %v", p.M)
+	}
+	if _, err := tbl.lookup(45.2); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange beyond the table, got %v", err)
+	}
+}
+
+func TestRestrictedZBeyondThreeSD(t *testing.T) {
+	p := LMS{L: -0.3521, M: 8.638, S: 0.0799}
+	sd3 := p.valueAt(-3)
+	sd2 := p.valueAt(-2)
+	y := sd3 - (sd2 - sd3)
+	if got := round2(p.restrictedZ(y)); got != -4 {
+		t.Errorf("expected restricted z of -4, got %v", got)
+	}
+}
+
+func TestReadReferenceTable(t *testing.T) {
+	tables, err := readReferenceTable(strings.NewReader("sex\tage\tl\tm\ts\tloh\n1\t1\t1\t50.0\t0.04\tL\n1\t0\t1\t49.9\t0.04\tL\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tables[Male]) != 2 || tables[Male][0].X != 0 {
+		t.Errorf("expected two rows sorted by age, got %+v", tables[Male])
+	}
+
+	if _, err := readReferenceTable(strings.NewReader("3\t0\t1\t49.9\t0.04\n")); err == nil {
+		t.Error("expected an error for an unknown sex code")
+	}
+	if _, err := readReferenceTable(strings.NewReader("1\t0\t1\t0\t0.04\n")); err == nil {
+		t.Error("expected an error for a zero median")
+	}
+}
+
+func TestLoadReferenceRequiresEveryTable(t *testing.T) {
+	files := testReferenceFS(t)
+	delete(files, "acanthro.txt")
+	if _, err := LoadReference(files); err == nil || !strings.Contains(err.Error(), "acanthro.txt") {
+		t.Errorf("expected an error naming the missing MUAC table, got %v", err)
+	}
+
+	files = testReferenceFS(t)
+	files["acanthro.txt"] = &fstest.MapFile{Data: []byte("1\t91\t1\t13.5\t0.08\n")}
+	if _, err := LoadReference(files); err == nil {
+		t.Error("expected an error for a table without girls' rows")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	r := loadTestReference(t)
+
+	if _, err := r.MUACForAgeZ(Male, 30, 11); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange before the MUAC table starts, got %v", err)
+	}
+	if _, err := r.WeightForAgeZ(Male, 1, 3.4); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange outside the table, got %v", err)
+	}
+	if _, err := r.WeightForAgeZ("unknown", 0, 5); !errors.Is(err, ErrUnknownSex) {
+		t.Errorf("expected ErrUnknownSex, got %v", err)
+	}
+	if _, err := r.WeightForAgeZ(Female, 0, 0); !errors.Is(err, ErrInvalidMeasure) {
+		t.Errorf("expected ErrInvalidMeasure, got %v", err)
+	}
+
+	var none *Reference
+	if _, err := none.WeightForAgeZ(Male, 0, 3.3); !errors.Is(err, ErrReferenceNotLoaded) {
+		t.Errorf("expected ErrReferenceNotLoaded without a reference, got %v", err)
+	}
+}
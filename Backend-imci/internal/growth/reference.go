@@ -0,0 +1,122 @@
+package growth
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// referenceFiles names the WHO Anthro reference file of each indicator, as
+// published with the WHO Anthro software and igrowup macros. The age files
+// have a row per day from birth to 1856 days (MUAC from 91 days) and the
+// length and height files a row per 0.1 cm.
+var referenceFiles = map[Indicator]string{
+	WeightForAge:       "weianthro.txt",
+	LengthHeightForAge: "lenanthro.txt",
+	WeightForLength:    "wflanthro.txt",
+	WeightForHeight:    "wfhanthro.txt",
+	MUACForAge:         "acanthro.txt",
+}
+
+// whoFiles holds the WHO Anthro reference files built into the binary; see
+// who/README.md.
+//
+//go:embed who
+var whoFiles embed.FS
+
+// Reference holds the WHO LMS tables of every indicator.
+type Reference struct {
+	tables map[Indicator]map[Sex]table
+}
+
+// WHOReference loads the reference files built into the binary.
+func WHOReference() (*Reference, error) {
+	fsys, err := fs.Sub(whoFiles, "who")
+	if err != nil {
+		return nil, err
+	}
+	return LoadReference(fsys)
+}
+
+// LoadReference reads the WHO Anthro reference files from fsys. Every
+// indicator needs its table for both sexes, so a missing or incomplete file
+// is an error rather than a silently absent z-score.
+func LoadReference(fsys fs.FS) (*Reference, error) {
+	r := &Reference{tables: map[Indicator]map[Sex]table{}}
+	for indicator, name := range referenceFiles {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open growth reference %s: %w", name, err)
+		}
+		tables, err := readReferenceTable(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read growth reference %s: %w", name, err)
+		}
+		if len(tables[Male]) == 0 || len(tables[Female]) == 0 {
+			return nil, fmt.Errorf("growth reference %s must have rows for both sexes", name)
+		}
+		r.tables[indicator] = tables
+	}
+	return r, nil
+}
+
+// readReferenceTable parses a tab separated WHO Anthro file: sex (1 male,
+// 2 female), age in days or length in cm, then l, m and s. A header line and
+// any further columns such as the measurement position are ignored.
+func readReferenceTable(r io.Reader) (map[Sex]table, error) {
+	tables := map[Sex]table{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		sexCode, err := strconv.Atoi(fields[0])
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: invalid sex %q", line, fields[0])
+		}
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("line %d: expected sex, x, l, m and s", line)
+		}
+
+		var sex Sex
+		switch sexCode {
+		case 1:
+			sex = Male
+		case 2:
+			sex = Female
+		default:
+			return nil, fmt.Errorf("line %d: invalid sex %d", line, sexCode)
+		}
+
+		var values [4]float64
+		for i := range values {
+			if values[i], err = strconv.ParseFloat(fields[i+1], 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", line, fields[i+1])
+			}
+		}
+		if values[2] <= 0 || values[3] <= 0 {
+			return nil, fmt.Errorf("line %d: m and s must be greater than zero", line)
+		}
+		tables[sex] = append(tables[sex], LMS{X: values[0], L: values[1], M: values[2], S: values[3]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, t := range tables {
+		sort.Slice(t, func(i, j int) bool { return t[i].X < t[j].X })
+	}
+	return tables, nil
+}
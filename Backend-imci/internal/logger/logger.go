@@ -0,0 +1,89 @@
+// Package logger provides the application's structured logger. Attributes
+// whose keys name credentials are redacted and phone numbers are masked, so
+// callers can log request fields without leaking secrets.
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// secretKeys are attribute keys whose values are never written out.
+var secretKeys = map[string]bool{
+	"password":      true,
+	"password_hash": true,
+	"hash":          true,
+	"secret":        true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+	"otp":           true,
+	"otp_code":      true,
+	"code":          true,
+}
+
+var std = New(os.Stdout, slog.LevelInfo)
+
+// New returns a JSON logger writing to w that redacts secret attributes.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: Redact,
+	}))
+}
+
+// Redact is a slog ReplaceAttr function that hides credential values and
+// masks phone numbers down to their last four digits.
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+
+	switch {
+	case isSecretKey(key):
+		return slog.String(a.Key, redacted)
+	case key == "phone" || strings.HasSuffix(key, "_phone"):
+		return slog.String(a.Key, MaskPhone(a.Value.String()))
+	}
+
+	return a
+}
+
+func isSecretKey(key string) bool {
+	if secretKeys[key] {
+		return true
+	}
+	for _, suffix := range []string{"_password", "_token", "_secret"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskPhone keeps only the last four digits of a phone number.
+func MaskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return strings.Repeat("*", len(phone))
+	}
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
+
+// L returns the application logger.
+func L() *slog.Logger {
+	return std
+}
+
+// SetDefault replaces the application logger, e.g. to change the level or
+// capture output in tests.
+func SetDefault(l *slog.Logger) {
+	std = l
+}
+
+func Debug(msg string, args ...any) { std.Debug(msg, args...) }
+func Info(msg string, args ...any)  { std.Info(msg, args...) }
+func Warn(msg string, args ...any)  { std.Warn(msg, args...) }
+func Error(msg string, args ...any) { std.Error(msg, args...) }
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactsSecretsAndMasksPhones(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, slog.LevelDebug)
+
+	l.Info("login attempt",
+		"phone", "251911223344",
+		"password", "hunter2",
+		"password_hash", "$2a$10$abcdefghijklmnop",
+		"refresh_token", "eyJhbGciOi",
+		"code", "123456",
+		slog.Group("request", "telegram_token", "bot-token", "user", "nurse"),
+	)
+
+	out := buf.String()
+	for _, leaked := range []string{"hunter2", "$2a$10$abcdefghijklmnop", "eyJhbGciOi", "123456", "bot-token", "251911223344"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("log output leaked %q: %s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"********3344", "nurse", "login attempt"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("log output missing %q: %s", kept, out)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+// Package pdf writes simple text documents as PDF without external
+// dependencies. It supports the two standard Helvetica fonts, an embedded
+// TrueType font for other scripts, word wrapping, coloured banners and
+// automatic page breaks, which is all the printable summaries need.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size and margins in points.
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	margin       = 50.0
+	contentWidth = pageWidth - 2*margin
+)
+
+type font struct {
+	resource string
+	widths   *[95]int
+}
+
+var (
+	regular = font{resource: "F1", widths: &helveticaWidths}
+	bold    = font{resource: "F2", widths: &helveticaBoldWidths}
+)
+
+// embeddedResource names the embedded font in the page resources.
+const embeddedResource = "F3"
+
+// Document is a PDF under construction. The zero value is not usable; call
+// New or NewWithFont.
+type Document struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+
+	embedded *Font
+	// used maps the embedded glyphs shown to their characters.
+	used map[uint16]rune
+}
+
+func New() *Document {
+	d := &Document{}
+	d.newPage()
+	return d
+}
+
+// NewWithFont returns a document that sets the characters outside Latin-1
+// in f. The font is only embedded when the document uses it.
+func NewWithFont(f *Font) *Document {
+	d := New()
+	if f != nil {
+		d.embedded = f
+		d.used = make(map[uint16]rune)
+	}
+	return d
+}
+
+func (d *Document) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pageHeight - margin
+}
+
+// ensure starts a new page when fewer than h points are left.
+func (d *Document) ensure(h float64) {
+	if d.y-h < margin {
+		d.newPage()
+	}
+}
+
+// Title writes a large bold line.
+func (d *Document) Title(text string) {
+	d.write(bold, 18, 0, text)
+	d.Space(6)
+}
+
+// Heading writes a bold section heading.
+func (d *Document) Heading(text string) {
+	d.Space(8)
+	d.write(bold, 13, 0, text)
+	d.Space(2)
+}
+
+// Text writes a wrapped paragraph.
+func (d *Document) Text(text string) {
+	d.write(regular, 10.5, 0, text)
+}
+
+// Bold writes a wrapped paragraph in bold.
+func (d *Document) Bold(text string) {
+	d.write(bold, 10.5, 0, text)
+}
+
+// Bullet writes an indented, wrapped list item.
+func (d *Document) Bullet(text string) {
+	d.write(regular, 10.5, 12, "- "+text)
+}
+
+// Field writes "label: value" on one wrapped line, skipping empty values.
+func (d *Document) Field(label, value string) {
+	if value == "" {
+		return
+	}
+	d.write(regular, 10.5, 0, label+": "+value)
+}
+
+// Color is an RGB fill colour with components between 0 and 1.
+type Color struct {
+	R, G, B float64
+}
+
+// Banner writes bold text on a full-width coloured block, used to mark
+// classifications by severity.
+func (d *Document) Banner(text string, fill Color) {
+	const size, padding = 11.0, 5.0
+	leading := size * 1.35
+	lines := d.wrap(bold, size, contentWidth-2*padding, text)
+	h := float64(len(lines))*leading + 2*padding
+
+	d.Space(4)
+	d.ensure(h)
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f 0 g\n",
+		fill.R, fill.G, fill.B, margin, d.y-h, contentWidth, h)
+
+	y := d.y - padding
+	for _, line := range lines {
+		y -= leading
+		d.show(bold, size, margin+padding, y+size*0.3, line)
+	}
+	d.y -= h
+	d.Space(2)
+}
+
+// Supports reports whether the document's fonts can show every character
+// of text. Anything else is printed as '?'.
+func (d *Document) Supports(text string) bool {
+	for _, r := range text {
+		if r > 0xFF && !d.isEmbedded(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmbedded reports whether r is set in the embedded font.
+func (d *Document) isEmbedded(r rune) bool {
+	return r > 0xFF && d.embedded != nil && d.embedded.Has(r)
+}
+
+// Space adds vertical space.
+func (d *Document) Space(h float64) {
+	d.y -= h
+}
+
+func (d *Document) write(f font, size, indent float64, text string) {
+	leading := size * 1.35
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range d.wrap(f, size, contentWidth-indent, paragraph) {
+			d.ensure(leading)
+			d.y -= leading
+			d.show(f, size, margin+indent, d.y, line)
+		}
+	}
+}
+
+// show writes one line at x, y, switching to the embedded font for the
+// characters the standard fonts cannot show. The embedded font has no bold
+// face, so bold text in it is stroked as well as filled.
+func (d *Document) show(f font, size, x, y float64, text string) {
+	fmt.Fprintf(d.page, "BT %.2f %.2f Td", x, y)
+	for _, run := range d.runs(text) {
+		if !run.embedded {
+			fmt.Fprintf(d.page, " /%s %.1f Tf (%s) Tj", f.resource, size, escape(run.text))
+			continue
+		}
+		if f == bold {
+			fmt.Fprintf(d.page, " 2 Tr %.2f w", size*0.03)
+		}
+		fmt.Fprintf(d.page, " /%s %.1f Tf <%s> Tj", embeddedResource, size, d.encode(run.text))
+		if f == bold {
+			d.page.WriteString(" 0 Tr")
+		}
+	}
+	d.page.WriteString(" ET\n")
+}
+
+type run struct {
+	text     string
+	embedded bool
+}
+
+// runs splits text into runs of the standard and the embedded font.
+func (d *Document) runs(text string) []run {
+	var runs []run
+	for _, r := range text {
+		embedded := d.isEmbedded(r)
+		if n := len(runs); n > 0 && runs[n-1].embedded == embedded {
+			runs[n-1].text += string(r)
+			continue
+		}
+		runs = append(runs, run{text: string(r), embedded: embedded})
+	}
+	return runs
+}
+
+// encode writes text as the hex glyph ids of the embedded font.
+func (d *Document) encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		glyph := d.embedded.glyphs[r]
+		d.used[glyph] = r
+		fmt.Fprintf(&b, "%04X", glyph)
+	}
+	return b.String()
+}
+
+// Bytes serialises the document.
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are the catalog, page tree and fonts; each page then takes
+	// two objects: the page and its content stream. The embedded font, when
+	// used, follows the pages.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	fonts := "/F1 3 0 R /F2 4 0 R"
+	embeddedAt := 5 + 2*len(d.pages)
+	if len(d.used) > 0 {
+		fonts += fmt.Sprintf(" /%s %d 0 R", embeddedResource, embeddedAt)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fonts, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+	if len(d.used) > 0 {
+		d.writeEmbeddedFont(obj, embeddedAt)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// wrap splits text into lines no wider than width.
+func (d *Document) wrap(f font, size, width float64, text string) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if d.textWidth(f, size, line+" "+word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
+
+func (d *Document) textWidth(f font, size float64, text string) float64 {
+	total := 0
+	for _, r := range text {
+		if d.isEmbedded(r) {
+			total += d.embedded.width(d.embedded.glyphs[r])
+		} else if r >= 32 && r <= 126 {
+			total += f.widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// escape encodes text for a PDF string literal in WinAnsiEncoding. Characters
+// outside Latin-1 cannot be shown with the standard fonts and become '?'.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteByte(' ')
+		case r < 32:
+			continue
+		case r < 128:
+			b.WriteRune(r)
+		case r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// writeEmbeddedFont writes the embedded font as a Type0 font with
+// Identity-H encoding, whose character codes are glyph ids, starting at
+// object number first. The whole font file is embedded.
+func (d *Document) writeEmbeddedFont(obj func(string), first int) {
+	f := d.embedded
+	glyphs := make([]int, 0, len(d.used))
+	for glyph := range d.used {
+		glyphs = append(glyphs, int(glyph))
+	}
+	sort.Ints(glyphs)
+
+	var widths, toUnicode strings.Builder
+	for i, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, f.width(uint16(glyph)))
+		if i%100 == 0 {
+			if i > 0 {
+				toUnicode.WriteString("endbfchar\n")
+			}
+			fmt.Fprintf(&toUnicode, "%d beginbfchar\n", min(100, len(glyphs)-i))
+		}
+		fmt.Fprintf(&toUnicode, "<%04X> <", glyph)
+		for _, unit := range utf16.Encode([]rune{d.used[uint16(glyph)]}) {
+			fmt.Fprintf(&toUnicode, "%04X", unit)
+		}
+		toUnicode.WriteString(">\n")
+	}
+	toUnicode.WriteString("endbfchar\n")
+
+	cmap := "/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n" +
+		toUnicode.String() +
+		"endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n"
+
+	obj(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", f.name, first+1, first+4))
+	obj(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>", f.name, first+2, widths.String()))
+	obj(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.name, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+		f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), first+3))
+	obj(fmt.Sprintf("<< /Length %d /Length1 %d >>\nstream\n%s\nendstream", len(f.data), len(f.data), f.data))
+	obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(cmap), cmap))
+}
@@ -0,0 +1,80 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocumentStructure(t *testing.T) {
+	d := New()
+	d.Title("Visit summary")
+	d.Field("Name", "Abebe (Kebede)")
+	for i := 0; i < 120; i++ {
+		d.Bullet(fmt.Sprintf("Line %d of a long list that forces another page", i))
+	}
+	out := d.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	if !bytes.Contains(out, []byte(`(Name: Abebe \(Kebede\)) Tj`)) {
+		t.Fatal("parentheses were not escaped")
+	}
+	if count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(out); count == nil || string(count[1]) == "1" {
+		t.Fatal("expected the long list to span several pages")
+	}
+
+	// Every xref offset must point at the start of its object.
+	xrefAt := bytes.LastIndex(out, []byte("startxref\n"))
+	start, err := strconv.Atoi(strings.TrimSpace(strings.Split(string(out[xrefAt+len("startxref\n"):]), "\n")[0]))
+	if err != nil {
+		t.Fatalf("startxref: %v", err)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out[start:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q", i+1, out[off:off+10])
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	d := New()
+	lines := d.wrap(regular, 10, 100, "Give paracetamol every six hours until the fever is gone")
+	if len(lines) < 2 {
+		t.Fatalf("expected wrapping, got %q", lines)
+	}
+	for _, l := range lines {
+		if d.textWidth(regular, 10, l) > 100 {
+			t.Fatalf("line %q exceeds width", l)
+		}
+	}
+}
+
+func TestBanner(t *testing.T) {
+	d := New()
+	d.Banner("SEVERE PNEUMONIA OR VERY SEVERE DISEASE", Color{R: 0.98, G: 0.78, B: 0.84})
+	out := d.Bytes()
+
+	if !bytes.Contains(out, []byte("0.980 0.780 0.840 rg")) {
+		t.Fatal("banner fill colour missing")
+	}
+	if !bytes.Contains(out, []byte("(SEVERE PNEUMONIA OR VERY SEVERE DISEASE) Tj")) {
+		t.Fatal("banner text missing")
+	}
+}
+
+func TestSupports(t *testing.T) {
+	d := New()
+	if !d.Supports("Café, 38.5 °C") {
+		t.Error("Latin-1 text should be supported")
+	}
+	if d.Supports("ፈሳሽ ይስጡ") {
+		t.Error("Ge'ez text should not be supported without an embedded font")
+	}
+}
@@ -0,0 +1,267 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+var ErrUnsupportedFont = errors.New("unsupported font: only TrueType outlines can be embedded")
+
+// Font is a TrueType font embedded in documents for the characters the
+// standard fonts cannot show, such as the Ge'ez script of Amharic and
+// Tigrinya.
+type Font struct {
+	data       []byte
+	name       string
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	glyphs     map[rune]uint16
+	advances   []uint16
+}
+
+// LoadFont reads a TrueType (.ttf) font file, e.g. Noto Sans Ethiopic.
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	return ParseFont(data)
+}
+
+// ParseFont reads the metrics and character map of a TrueType font.
+func ParseFont(data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, ErrUnsupportedFont
+	}
+	if version := binary.BigEndian.Uint32(data); version != 0x00010000 && version != 0x74727565 {
+		return nil, ErrUnsupportedFont
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + 16*i
+		if record+16 > len(data) {
+			return nil, fmt.Errorf("font table directory is truncated")
+		}
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("font table %q is out of bounds", data[record:record+4])
+		}
+		tables[string(data[record:record+4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap", "glyf"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("font has no %s table: %w", tag, ErrUnsupportedFont)
+		}
+	}
+
+	head, hhea, hmtx := tables["head"], tables["hhea"], tables["hmtx"]
+	if len(head) < 54 || len(hhea) < 36 {
+		return nil, fmt.Errorf("font head or hhea table is truncated")
+	}
+	f := &Font{
+		data:       data,
+		name:       fontName(tables["name"]),
+		unitsPerEm: int(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int(int16(binary.BigEndian.Uint16(hhea[4:]))),
+		descent:    int(int16(binary.BigEndian.Uint16(hhea[6:]))),
+	}
+	if f.unitsPerEm == 0 {
+		return nil, fmt.Errorf("font has no units per em")
+	}
+	for i := range f.bbox {
+		f.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+
+	metrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if len(hmtx) < 4*metrics {
+		return nil, fmt.Errorf("font hmtx table is truncated")
+	}
+	f.advances = make([]uint16, metrics)
+	for i := range f.advances {
+		f.advances[i] = binary.BigEndian.Uint16(hmtx[4*i:])
+	}
+
+	glyphs, err := readCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	f.glyphs = glyphs
+	return f, nil
+}
+
+// Has reports whether the font has a glyph for r.
+func (f *Font) Has(r rune) bool {
+	return f.glyphs[r] != 0
+}
+
+// width is the advance of glyph in thousandths of the font size.
+func (f *Font) width(glyph uint16) int {
+	if len(f.advances) == 0 {
+		return 0
+	}
+	advance := f.advances[len(f.advances)-1]
+	if int(glyph) < len(f.advances) {
+		advance = f.advances[glyph]
+	}
+	return int(advance) * 1000 / f.unitsPerEm
+}
+
+func (f *Font) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// readCmap reads the Unicode character map, preferring the full repertoire
+// (format 12) over the Basic Multilingual Plane one (format 4).
+func readCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, fmt.Errorf("font cmap table is truncated")
+	}
+	var bmp, full []byte
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < count; i++ {
+		record := 4 + 8*i
+		if record+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+2 > len(cmap) || !(platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			bmp = cmap[offset:]
+		case 12:
+			full = cmap[offset:]
+		}
+	}
+
+	switch {
+	case full != nil:
+		return readCmap12(full)
+	case bmp != nil:
+		return readCmap4(bmp)
+	default:
+		return nil, fmt.Errorf("font has no Unicode character map: %w", ErrUnsupportedFont)
+	}
+}
+
+func readCmap4(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 14 {
+		return nil, fmt.Errorf("font cmap subtable is truncated")
+	}
+	segments := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	ends := 14
+	starts := ends + 2*segments + 2
+	deltas := starts + 2*segments
+	rangeOffsets := deltas + 2*segments
+	if rangeOffsets+2*segments > len(sub) {
+		return nil, fmt.Errorf("font cmap subtable is truncated")
+	}
+
+	glyphs := make(map[rune]uint16)
+	for i := 0; i < segments; i++ {
+		end := int(binary.BigEndian.Uint16(sub[ends+2*i:]))
+		start := int(binary.BigEndian.Uint16(sub[starts+2*i:]))
+		delta := binary.BigEndian.Uint16(sub[deltas+2*i:])
+		rangeOffset := int(binary.BigEndian.Uint16(sub[rangeOffsets+2*i:]))
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			glyph := uint16(c) + delta
+			if rangeOffset != 0 {
+				at := rangeOffsets + 2*i + rangeOffset + 2*(c-start)
+				if at+2 > len(sub) {
+					continue
+				}
+				glyph = binary.BigEndian.Uint16(sub[at:])
+				if glyph != 0 {
+					glyph += delta
+				}
+			}
+			if glyph != 0 {
+				glyphs[rune(c)] = glyph
+			}
+		}
+	}
+	return glyphs, nil
+}
+
+func readCmap12(sub []byte) (map[rune]uint16, error) {
+	if len(sub) < 16 {
+		return nil, fmt.Errorf("font cmap subtable is truncated")
+	}
+	groups := int(binary.BigEndian.Uint32(sub[12:]))
+	if 16+12*groups > len(sub) {
+		return nil, fmt.Errorf("font cmap subtable is truncated")
+	}
+
+	glyphs := make(map[rune]uint16)
+	for i := 0; i < groups; i++ {
+		group := sub[16+12*i:]
+		start := binary.BigEndian.Uint32(group)
+		end := binary.BigEndian.Uint32(group[4:])
+		glyph := binary.BigEndian.Uint32(group[8:])
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			if g := glyph + c - start; g != 0 && g <= 0xFFFF {
+				glyphs[rune(c)] = uint16(g)
+			}
+		}
+	}
+	return glyphs, nil
+}
+
+// fontName is the PostScript name of the font, used as its PDF base font.
+func fontName(name []byte) string {
+	const fallback = "EmbeddedFont"
+	if len(name) < 6 {
+		return fallback
+	}
+	count := int(binary.BigEndian.Uint16(name[2:]))
+	storage := int(binary.BigEndian.Uint16(name[4:]))
+	for i := 0; i < count; i++ {
+		record := 6 + 12*i
+		if record+12 > len(name) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(name[record:])
+		nameID := binary.BigEndian.Uint16(name[record+6:])
+		length := int(binary.BigEndian.Uint16(name[record+8:]))
+		offset := storage + int(binary.BigEndian.Uint16(name[record+10:]))
+		if nameID != 6 || offset+length > len(name) {
+			continue
+		}
+		raw := name[offset : offset+length]
+		var s string
+		switch platform {
+		case 1:
+			s = string(raw)
+		case 0, 3:
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			s = string(utf16.Decode(units))
+		default:
+			continue
+		}
+		s = strings.Map(func(r rune) rune {
+			if r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+				return r
+			}
+			return -1
+		}, s)
+		if s != "" {
+			return s
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,141 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testFont builds a minimal TrueType font mapping "ሀ" (U+1200) to "ሆ"
+// (U+1206) to glyphs 1-7, each 600 units wide on a 1000 unit em.
+func testFont(t *testing.T) []byte {
+	t.Helper()
+	be := binary.BigEndian
+
+	head := make([]byte, 54)
+	be.PutUint16(head[18:], 1000)
+	for i, v := range []int16{-100, -250, 1100, 950} {
+		be.PutUint16(head[36+2*i:], uint16(v))
+	}
+
+	hhea := make([]byte, 36)
+	be.PutUint16(hhea[4:], 950)
+	be.PutUint16(hhea[6:], uint16(0xFFFF-250+1))
+	be.PutUint16(hhea[34:], 8)
+
+	hmtx := make([]byte, 4*8)
+	for i := 0; i < 8; i++ {
+		be.PutUint16(hmtx[4*i:], 600)
+	}
+
+	// A format 4 subtable with the Ge'ez segment and the closing 0xFFFF one.
+	sub := make([]byte, 32)
+	be.PutUint16(sub, 4)
+	be.PutUint16(sub[2:], uint16(len(sub)))
+	be.PutUint16(sub[6:], 4)
+	be.PutUint16(sub[14:], 0x1206)
+	be.PutUint16(sub[16:], 0xFFFF)
+	be.PutUint16(sub[20:], 0x1200)
+	be.PutUint16(sub[22:], 0xFFFF)
+	be.PutUint16(sub[24:], 0x10000+1-0x1200)
+	be.PutUint16(sub[26:], 1)
+	cmap := make([]byte, 12, 12+len(sub))
+	be.PutUint16(cmap[2:], 1)
+	be.PutUint16(cmap[4:], 3)
+	be.PutUint16(cmap[6:], 1)
+	be.PutUint32(cmap[8:], 12)
+	cmap = append(cmap, sub...)
+
+	name := make([]byte, 18)
+	be.PutUint16(name[2:], 1)
+	be.PutUint16(name[4:], 18)
+	be.PutUint16(name[6:], 1)
+	be.PutUint16(name[12:], 6)
+	be.PutUint16(name[14:], uint16(len("Test Ethiopic")))
+	name = append(name, "Test Ethiopic"...)
+
+	tables := map[string][]byte{"head": head, "hhea": hhea, "hmtx": hmtx, "cmap": cmap, "glyf": {0, 0}, "name": name}
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	out := make([]byte, 12+16*len(tags))
+	be.PutUint32(out, 0x00010000)
+	be.PutUint16(out[4:], uint16(len(tags)))
+	for i, tag := range tags {
+		record := out[12+16*i:]
+		copy(record, tag)
+		be.PutUint32(record[8:], uint32(len(out)))
+		be.PutUint32(record[12:], uint32(len(tables[tag])))
+		out = append(out, tables[tag]...)
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+	return out
+}
+
+func TestParseFont(t *testing.T) {
+	f, err := ParseFont(testFont(t))
+	if err != nil {
+		t.Fatalf("ParseFont: %v", err)
+	}
+	if f.name != "TestEthiopic" {
+		t.Errorf("expected name TestEthiopic, got %q", f.name)
+	}
+	if !f.Has('ሀ') || !f.Has('ሆ') || f.Has('ሇ') || f.Has('A') {
+		t.Error("unexpected character map")
+	}
+	if f.glyphs['ሆ'] != 7 || f.width(7) != 600 {
+		t.Errorf("expected glyph 7 of width 600, got %d of %d", f.glyphs['ሆ'], f.width(f.glyphs['ሆ']))
+	}
+
+	if _, err := ParseFont([]byte("OTTO\x00\x00\x00\x00\x00\x00\x00\x00")); !errors.Is(err, ErrUnsupportedFont) {
+		t.Errorf("expected ErrUnsupportedFont for CFF outlines, got %v", err)
+	}
+}
+
+func TestEmbeddedFont(t *testing.T) {
+	f, err := ParseFont(testFont(t))
+	if err != nil {
+		t.Fatalf("ParseFont: %v", err)
+	}
+
+	d := NewWithFont(f)
+	if !d.Supports("Give ሀሁ") || d.Supports("ሇ") {
+		t.Error("expected support for exactly the characters of the font")
+	}
+	d.Text("Give ሀሁ now")
+	d.Bold("ሆ")
+	out := d.Bytes()
+
+	for _, want := range []string{
+		"/F1 10.5 Tf (Give ) Tj /F3 10.5 Tf <00010002> Tj /F1 10.5 Tf ( now) Tj",
+		"2 Tr 0.32 w /F3 10.5 Tf <0007> Tj 0 Tr",
+		"/Subtype /Type0 /BaseFont /TestEthiopic /Encoding /Identity-H",
+		"/W [1 [600] 2 [600] 7 [600] ]",
+		"/FontFile2 ",
+		"<0001> <1200>",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("document is missing %q", want)
+		}
+	}
+	if !bytes.Contains(out, testFont(t)) {
+		t.Error("font file not embedded")
+	}
+
+	english := NewWithFont(f)
+	english.Text("Give fluids")
+	if bytes.Contains(english.Bytes(), []byte("/F3")) {
+		t.Error("font embedded in a document that does not use it")
+	}
+	if strings.Contains(string(New().Bytes()), "Type0") {
+		t.Error("standard document should not have an embedded font")
+	}
+}
@@ -0,0 +1,356 @@
+// Package prescribing consolidates the treatment plans of a visit into its
+// final prescription and checks it against the drug table.
+package prescribing
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+// Drug is an entry of the drug table. A treatment plan is the drug when its
+// name contains one of Names as whole words; entries are matched in order,
+// so combinations come before their parts.
+type Drug struct {
+	Key   string
+	Names []string
+	Class string
+}
+
+// DrugInteraction warns when both drugs are prescribed together.
+type DrugInteraction struct {
+	Drugs   [2]string
+	Message string
+}
+
+// DrugContraindication warns when a drug is prescribed to a child younger
+// than MinAgeMonths, lighter than MinWeightKg or with one of Classifications.
+type DrugContraindication struct {
+	Drug            string
+	MinAgeMonths    int
+	MinWeightKg     float64
+	Classifications []string
+	Message         string
+}
+
+type DrugTable struct {
+	Drugs             []Drug
+	Interactions      []DrugInteraction
+	Contraindications []DrugContraindication
+}
+
+// DefaultDrugTable covers the drugs the IMNCI treatment plans prescribe.
+var DefaultDrugTable = DrugTable{
+	Drugs: []Drug{
+		{Key: "ampicillin_gentamicin", Names: []string{"ampicillin and gentamicin"}, Class: "penicillin"},
+		{Key: "amoxicillin", Names: []string{"amoxicillin"}, Class: "penicillin"},
+		{Key: "ampicillin", Names: []string{"ampicillin"}, Class: "penicillin"},
+		{Key: "gentamicin", Names: []string{"gentamicin"}, Class: "aminoglycoside"},
+		{Key: "ceftriaxone", Names: []string{"ceftriaxone"}, Class: "cephalosporin"},
+		{Key: "cotrimoxazole", Names: []string{"cotrimoxazole"}},
+		{Key: "ciprofloxacin", Names: []string{"ciprofloxacin"}},
+		{Key: "artemether_lumefantrine", Names: []string{"artemether lumefantrine", "artemisinin lumefantrine"}, Class: "antimalarial"},
+		{Key: "artesunate", Names: []string{"artesunate"}, Class: "antimalarial"},
+		{Key: "primaquine", Names: []string{"primaquine"}},
+		{Key: "paracetamol", Names: []string{"paracetamol"}},
+		{Key: "zinc", Names: []string{"zinc"}},
+		{Key: "iron", Names: []string{"iron"}},
+		{Key: "vitamin_a", Names: []string{"vitamin a"}},
+		{Key: "vitamin_k", Names: []string{"vitamin k"}},
+		{Key: "ors", Names: []string{"ors"}},
+		{Key: "mebendazole_albendazole", Names: []string{"mebendazole", "albendazole"}},
+		{Key: "bronchodilator", Names: []string{"bronchodilator"}},
+		{Key: "tetracycline_eye_ointment", Names: []string{"tetracycline eye ointment"}},
+		{Key: "nystatin", Names: []string{"nystatin"}},
+		{Key: "gentian_violet", Names: []string{"gentian violet"}},
+	},
+	Interactions: []DrugInteraction{
+		{Drugs: [2]string{"ciprofloxacin", "zinc"}, Message: "Zinc reduces the absorption of ciprofloxacin: give ciprofloxacin 2 hours before or 6 hours after zinc"},
+		{Drugs: [2]string{"ciprofloxacin", "iron"}, Message: "Iron reduces the absorption of ciprofloxacin: give ciprofloxacin 2 hours before or 6 hours after iron"},
+	},
+	Contraindications: []DrugContraindication{
+		{Drug: "primaquine", MinAgeMonths: 6, Message: "Primaquine is not given to infants under 6 months"},
+		{Drug: "artemether_lumefantrine", MinWeightKg: 5, Message: "Artemether-lumefantrine is not given below 5 kg: treat as very severe febrile disease"},
+		{Drug: "iron", Classifications: []string{"SEVERE ACUTE MALNUTRITION", "SEVERE MALNUTRITION"}, Message: "Do not give iron with severe acute malnutrition: RUTF already contains iron"},
+		{Drug: "cotrimoxazole", Classifications: []string{"SEVERE JAUNDICE"}, Message: "Cotrimoxazole is not given with severe jaundice"},
+	},
+}
+
+// MatchDrug returns the table entry a treatment plan's drug name is, if any.
+func (t DrugTable) MatchDrug(name string) (Drug, bool) {
+	normalized := " " + normalizeDrugName(name) + " "
+	for _, drug := range t.Drugs {
+		for _, n := range drug.Names {
+			if strings.Contains(normalized, " "+n+" ") {
+				return drug, true
+			}
+		}
+	}
+	return Drug{}, false
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+func normalizeDrugName(name string) string {
+	return strings.TrimSpace(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), " "))
+}
+
+// Consolidate merges the treatment plans of a visit. Plans of the same
+// drug, route and pre-referral flag become one item that is the whole plan
+// of the most severe classification prescribing it, or of the longest course
+// among equally severe ones: the dose, frequency and duration of different
+// plans are never mixed. Other plans are kept unless they repeat one another
+// exactly. Items are ordered pre-referral first, then by the treatment
+// priority of their classifications, and checked against the table for
+// interactions, duplicate drug classes and contraindications.
+func Consolidate(table DrugTable, assessment *domain.Assessment, plans []*domain.TreatmentPlan, classifications []*domain.Classification) *domain.Prescription {
+	priority := map[uuid.UUID]int{}
+	diseases := []string{}
+	for _, c := range classifications {
+		priority[c.ID] = c.TreatmentPriority
+		diseases = append(diseases, strings.ToUpper(c.Disease))
+	}
+	planPriority := func(p *domain.TreatmentPlan) int {
+		if value, ok := priority[p.ClassificationID]; ok && value > 0 {
+			return value
+		}
+		return 3
+	}
+
+	// The first plan of an item in this order is the one it prescribes.
+	ordered := append([]*domain.TreatmentPlan{}, plans...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].IsPreReferral != ordered[j].IsPreReferral {
+			return ordered[i].IsPreReferral
+		}
+		if pi, pj := planPriority(ordered[i]), planPriority(ordered[j]); pi != pj {
+			return pi < pj
+		}
+		return durationDays(ordered[i].Duration) > durationDays(ordered[j].Duration)
+	})
+
+	items := []*domain.PrescriptionItem{}
+	byKey := map[string]*domain.PrescriptionItem{}
+	// overridden holds the regimens each item was merged over.
+	overridden := map[*domain.PrescriptionItem][]string{}
+	for _, plan := range ordered {
+		drug, known := table.MatchDrug(plan.DrugName)
+		key := drug.Key
+		if !known {
+			key = "plan:" + strings.Join([]string{
+				normalizeDrugName(plan.DrugName), plan.Dosage, plan.Frequency, plan.Duration, plan.Instructions,
+			}, "|")
+		}
+		key += "|" + normalizeRoute(plan.AdministrationRoute) + "|" + strconv.FormatBool(plan.IsPreReferral)
+
+		item, exists := byKey[key]
+		if !exists {
+			item = &domain.PrescriptionItem{
+				ID:                  uuid.New(),
+				DrugKey:             drug.Key,
+				DrugName:            plan.DrugName,
+				Dosage:              plan.Dosage,
+				Frequency:           plan.Frequency,
+				Duration:            plan.Duration,
+				AdministrationRoute: plan.AdministrationRoute,
+				IsPreReferral:       plan.IsPreReferral,
+				Instructions:        plan.Instructions,
+				TreatmentPriority:   planPriority(plan),
+				TreatmentPlanIDs:    []uuid.UUID{},
+				ClassificationIDs:   []uuid.UUID{},
+			}
+			byKey[key] = item
+			items = append(items, item)
+		} else {
+			if item.AdministrationRoute == "" {
+				item.AdministrationRoute = plan.AdministrationRoute
+			}
+			if regimen := regimenOf(plan.Dosage, plan.Frequency, plan.Duration); !sameRegimen(regimen, regimenOf(item.Dosage, item.Frequency, item.Duration)) && !containsRegimen(overridden[item], regimen) {
+				overridden[item] = append(overridden[item], regimen)
+			}
+		}
+
+		item.TreatmentPlanIDs = append(item.TreatmentPlanIDs, plan.ID)
+		if !containsID(item.ClassificationIDs, plan.ClassificationID) {
+			item.ClassificationIDs = append(item.ClassificationIDs, plan.ClassificationID)
+		}
+	}
+
+	for i, item := range items {
+		item.Position = i + 1
+	}
+
+	return &domain.Prescription{
+		AssessmentID: assessment.ID,
+		Items:        items,
+		Warnings:     append(prescriptionWarnings(table, assessment, items, diseases), regimenWarnings(items, overridden)...),
+	}
+}
+
+// normalizeRoute treats a plan without a route as oral, the route of every
+// IMNCI home treatment.
+func normalizeRoute(route string) string {
+	route = strings.ToLower(strings.TrimSpace(route))
+	if route == "" {
+		return "oral"
+	}
+	return route
+}
+
+func regimenOf(dosage, frequency, duration string) string {
+	return strings.Join([]string{dosage, frequency, duration}, ", ")
+}
+
+func sameRegimen(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+func containsRegimen(regimens []string, regimen string) bool {
+	for _, r := range regimens {
+		if sameRegimen(r, regimen) {
+			return true
+		}
+	}
+	return false
+}
+
+// regimenWarnings flags the items whose classifications asked for a
+// different dosage, frequency or duration than the one prescribed.
+func regimenWarnings(items []*domain.PrescriptionItem, overridden map[*domain.PrescriptionItem][]string) []domain.PrescriptionWarning {
+	warnings := []domain.PrescriptionWarning{}
+	for _, item := range items {
+		regimens := overridden[item]
+		if len(regimens) == 0 {
+			continue
+		}
+		warnings = append(warnings, domain.PrescriptionWarning{
+			Kind:    domain.WarningRegimenConflict,
+			Drugs:   []string{item.DrugKey},
+			Message: fmt.Sprintf("%s is prescribed as %s, but another classification asked for %s: check the regimen", item.DrugName, regimenOf(item.Dosage, item.Frequency, item.Duration), strings.Join(regimens, "; ")),
+		})
+	}
+	return warnings
+}
+
+var durationNumber = regexp.MustCompile(`(\d+)\s*(day|week|month)`)
+
+// durationDays reads "5 days", "10-14 days" or "3 months" as days. A single
+// dose is 0 and an open course such as "Until fever resolves" or "Ongoing"
+// outlasts any fixed one.
+func durationDays(duration string) int {
+	d := strings.ToLower(strings.TrimSpace(duration))
+	if strings.HasPrefix(d, "until") || d == "ongoing" || d == "lifelong" {
+		return math.MaxInt32
+	}
+	longest := 0
+	for _, match := range durationNumber.FindAllStringSubmatch(d, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		switch match[2] {
+		case "week":
+			n *= 7
+		case "month":
+			n *= 30
+		}
+		if n > longest {
+			longest = n
+		}
+	}
+	return longest
+}
+
+func prescriptionWarnings(table DrugTable, assessment *domain.Assessment, items []*domain.PrescriptionItem, diseases []string) []domain.PrescriptionWarning {
+	warnings := []domain.PrescriptionWarning{}
+
+	prescribed := map[string]*domain.PrescriptionItem{}
+	byClass := map[string][]string{}
+	classes := []string{}
+	for _, item := range items {
+		if item.DrugKey == "" {
+			continue
+		}
+		prescribed[item.DrugKey] = item
+	}
+	for _, drug := range table.Drugs {
+		if _, ok := prescribed[drug.Key]; !ok || drug.Class == "" {
+			continue
+		}
+		if _, seen := byClass[drug.Class]; !seen {
+			classes = append(classes, drug.Class)
+		}
+		byClass[drug.Class] = append(byClass[drug.Class], drug.Key)
+	}
+
+	for _, interaction := range table.Interactions {
+		if prescribed[interaction.Drugs[0]] != nil && prescribed[interaction.Drugs[1]] != nil {
+			warnings = append(warnings, domain.PrescriptionWarning{
+				Kind:    domain.WarningInteraction,
+				Drugs:   []string{interaction.Drugs[0], interaction.Drugs[1]},
+				Message: interaction.Message,
+			})
+		}
+	}
+
+	for _, class := range classes {
+		keys := byClass[class]
+		if len(keys) < 2 {
+			continue
+		}
+		names := make([]string, 0, len(keys))
+		for _, key := range keys {
+			names = append(names, prescribed[key].DrugName)
+		}
+		warnings = append(warnings, domain.PrescriptionWarning{
+			Kind:    domain.WarningDuplicateClass,
+			Drugs:   keys,
+			Message: fmt.Sprintf("%s are in the same class (%s): check that more than one is needed", strings.Join(names, ", "), class),
+		})
+	}
+
+	for _, rule := range table.Contraindications {
+		if prescribed[rule.Drug] == nil || !contraindicated(rule, assessment, diseases) {
+			continue
+		}
+		warnings = append(warnings, domain.PrescriptionWarning{
+			Kind:    domain.WarningContraindication,
+			Drugs:   []string{rule.Drug},
+			Message: rule.Message,
+		})
+	}
+
+	return warnings
+}
+
+func contraindicated(rule DrugContraindication, assessment *domain.Assessment, diseases []string) bool {
+	if rule.MinAgeMonths > 0 && assessment.AgeMonths < rule.MinAgeMonths {
+		return true
+	}
+	if rule.MinWeightKg > 0 && assessment.WeightKg > 0 && assessment.WeightKg < rule.MinWeightKg {
+		return true
+	}
+	for _, disease := range diseases {
+		for _, c := range rule.Classifications {
+			if strings.Contains(disease, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package prescribing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+func plan(classificationID uuid.UUID, drug, dosage, frequency, duration string, preReferral bool, instructions string) *domain.TreatmentPlan {
+	return &domain.TreatmentPlan{
+		ID:                  uuid.New(),
+		ClassificationID:    classificationID,
+		DrugName:            drug,
+		Dosage:              dosage,
+		Frequency:           frequency,
+		Duration:            duration,
+		AdministrationRoute: "Oral",
+		IsPreReferral:       preReferral,
+		Instructions:        instructions,
+	}
+}
+
+func itemsOf(p *domain.Prescription, key string) []*domain.PrescriptionItem {
+	items := []*domain.PrescriptionItem{}
+	for _, item := range p.Items {
+		if item.DrugKey == key {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func itemByKey(p *domain.Prescription, key string) *domain.PrescriptionItem {
+	if items := itemsOf(p, key); len(items) == 1 {
+		return items[0]
+	}
+	return nil
+}
+
+func hasWarning(p *domain.Prescription, kind domain.PrescriptionWarningKind, drug string) bool {
+	for _, w := range p.Warnings {
+		if w.Kind != kind {
+			continue
+		}
+		for _, d := range w.Drugs {
+			if d == drug {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestConsolidateMergesAndOrders(t *testing.T) {
+	assessment := &domain.Assessment{ID: uuid.New(), AgeMonths: 24, WeightKg: 11}
+	severe := &domain.Classification{ID: uuid.New(), Disease: "VERY SEVERE DISEASE", TreatmentPriority: 1}
+	pneumonia := &domain.Classification{ID: uuid.New(), Disease: "PNEUMONIA", TreatmentPriority: 2}
+	ear := &domain.Classification{ID: uuid.New(), Disease: "ACUTE EAR INFECTION", TreatmentPriority: 2}
+	dysentery := &domain.Classification{ID: uuid.New(), Disease: "DYSENTERY", TreatmentPriority: 2}
+	feeding := &domain.Classification{ID: uuid.New(), Disease: "FEEDING PROBLEM", TreatmentPriority: 3}
+
+	plans := []*domain.TreatmentPlan{
+		plan(feeding.ID, "N/A", "N/A", "N/A", "N/A", false, "Follow-up of feeding problem in 5 days"),
+		plan(pneumonia.ID, "Amoxicillin", "Based on weight", "Twice daily", "5 days", false, "Give for 5 days"),
+		plan(ear.ID, "Amoxicillin", "Based on weight", "Twice daily", "7 days", false, "Dry the ear by wicking"),
+		plan(pneumonia.ID, "Paracetamol", "Based on weight", "As needed", "Until fever resolves", false, ""),
+		plan(ear.ID, "Paracetamol", "Based on weight", "Once daily", "3 days", false, ""),
+		plan(dysentery.ID, "Zinc sulfate", "10mg daily", "Once daily", "10 days", false, ""),
+		plan(dysentery.ID, "Ciprofloxacin", "Based on weight", "Twice daily", "3 days", false, ""),
+		plan(feeding.ID, "Zinc sulfate", "10-20mg daily", "Once daily", "14 days", false, ""),
+		plan(feeding.ID, "N/A", "N/A", "N/A", "N/A", false, "Praise the mother"),
+		plan(severe.ID, "First dose of IV/IM Ampicillin and Gentamicin", "Based on weight", "Stat", "Single dose", true, "Give before referral"),
+		plan(severe.ID, "Urgent referral", "N/A", "Immediate", "N/A", false, "Refer to hospital"),
+		plan(pneumonia.ID, "Urgent referral", "N/A", "Immediate", "N/A", false, "Refer to hospital"),
+	}
+	classifications := []*domain.Classification{severe, pneumonia, ear, dysentery, feeding}
+
+	p := Consolidate(DefaultDrugTable, assessment, plans, classifications)
+
+	if got := len(p.Items); got != 8 {
+		t.Fatalf("items = %d, want 8: %+v", got, p.Items)
+	}
+	first := p.Items[0]
+	if first.DrugKey != "ampicillin_gentamicin" || !first.IsPreReferral || first.Position != 1 {
+		t.Errorf("first item = %+v, want pre-referral ampicillin and gentamicin", first)
+	}
+	if p.Items[1].DrugName != "Urgent referral" || p.Items[1].TreatmentPriority != 1 || len(p.Items[1].TreatmentPlanIDs) != 2 {
+		t.Errorf("second item = %+v, want one urgent referral from both classifications", p.Items[1])
+	}
+	for i := 1; i < len(p.Items); i++ {
+		if p.Items[i].TreatmentPriority < p.Items[i-1].TreatmentPriority && !p.Items[i-1].IsPreReferral {
+			t.Errorf("item %d (priority %d) after item with priority %d", i+1, p.Items[i].TreatmentPriority, p.Items[i-1].TreatmentPriority)
+		}
+	}
+
+	// Equally severe classifications: the longer course is prescribed as a
+	// whole, instructions included.
+	amoxicillin := itemByKey(p, "amoxicillin")
+	if amoxicillin == nil || amoxicillin.Duration != "7 days" || amoxicillin.Instructions != "Dry the ear by wicking" || len(amoxicillin.ClassificationIDs) != 2 {
+		t.Errorf("amoxicillin = %+v, want the ear infection plan for both classifications", amoxicillin)
+	}
+	paracetamol := itemByKey(p, "paracetamol")
+	if paracetamol == nil || paracetamol.Duration != "Until fever resolves" || paracetamol.Frequency != "As needed" {
+		t.Errorf("paracetamol = %+v, want the open pneumonia course as needed", paracetamol)
+	}
+	// The more severe classification's plan wins over a higher dose.
+	zinc := itemByKey(p, "zinc")
+	if zinc == nil || zinc.Dosage != "10mg daily" || zinc.Duration != "10 days" || len(zinc.TreatmentPlanIDs) != 2 {
+		t.Errorf("zinc = %+v, want the dysentery plan of 10mg for 10 days", zinc)
+	}
+
+	if !hasWarning(p, domain.WarningInteraction, "ciprofloxacin") {
+		t.Errorf("warnings = %+v, want ciprofloxacin and zinc interaction", p.Warnings)
+	}
+	if !hasWarning(p, domain.WarningDuplicateClass, "amoxicillin") {
+		t.Errorf("warnings = %+v, want amoxicillin and ampicillin duplicate class", p.Warnings)
+	}
+	if hasWarning(p, domain.WarningContraindication, "zinc") {
+		t.Errorf("unexpected contraindication: %+v", p.Warnings)
+	}
+	// Merging over a different regimen is flagged for the clinician.
+	for _, key := range []string{"amoxicillin", "paracetamol", "zinc"} {
+		if !hasWarning(p, domain.WarningRegimenConflict, key) {
+			t.Errorf("warnings = %+v, want a regimen conflict for %s", p.Warnings, key)
+		}
+	}
+	if hasWarning(p, domain.WarningRegimenConflict, "ciprofloxacin") {
+		t.Errorf("unexpected regimen conflict for a single plan: %+v", p.Warnings)
+	}
+}
+
+func TestConsolidateKeepsRoutesAndPreReferralApart(t *testing.T) {
+	assessment := &domain.Assessment{ID: uuid.New(), AgeMonths: 24, WeightKg: 11}
+	severe := &domain.Classification{ID: uuid.New(), Disease: "VERY SEVERE FEBRILE DISEASE", TreatmentPriority: 1}
+	malaria := &domain.Classification{ID: uuid.New(), Disease: "MALARIA", TreatmentPriority: 2}
+	diarrhoea := &domain.Classification{ID: uuid.New(), Disease: "NO DEHYDRATION", TreatmentPriority: 3}
+
+	preReferral := plan(severe.ID, "Paracetamol", "Based on weight", "Stat", "Single dose", true, "Give one dose before referral")
+	rectal := plan(severe.ID, "Artesunate", "Based on weight", "Stat", "Single dose", true, "")
+	rectal.AdministrationRoute = "Rectal"
+	injected := plan(severe.ID, "Artesunate", "Based on weight", "Stat", "Single dose", true, "")
+	injected.AdministrationRoute = "IM"
+	zinc := plan(diarrhoea.ID, "Zinc", "Based on age", "Once daily", "10 days", false, "")
+	zinc.AdministrationRoute = "oral "
+	noRoute := plan(diarrhoea.ID, "Zinc", "Based on age", "once  daily", "10 days", false, "")
+	noRoute.AdministrationRoute = ""
+	plans := []*domain.TreatmentPlan{
+		plan(malaria.ID, "Paracetamol", "Based on weight", "Every 6 hours", "Until fever resolves", false, ""),
+		preReferral,
+		rectal,
+		injected,
+		plan(malaria.ID, "Zinc", "Based on age", "Once daily", "10 days", false, ""),
+		zinc,
+		noRoute,
+	}
+
+	p := Consolidate(DefaultDrugTable, assessment, plans, []*domain.Classification{severe, malaria, diarrhoea})
+
+	paracetamol := itemsOf(p, "paracetamol")
+	if len(paracetamol) != 2 || !paracetamol[0].IsPreReferral || paracetamol[1].IsPreReferral {
+		t.Fatalf("paracetamol = %+v, want the pre-referral dose and the course apart", paracetamol)
+	}
+	if paracetamol[0].Frequency != "Stat" || paracetamol[1].Duration != "Until fever resolves" {
+		t.Errorf("paracetamol = %+v %+v, want each plan unchanged", paracetamol[0], paracetamol[1])
+	}
+	if artesunate := itemsOf(p, "artesunate"); len(artesunate) != 2 {
+		t.Errorf("artesunate = %+v, want rectal and IM apart", artesunate)
+	}
+	// A plan without a route is oral.
+	if zinc := itemsOf(p, "zinc"); len(zinc) != 1 || len(zinc[0].TreatmentPlanIDs) != 3 {
+		t.Errorf("zinc = %+v, want one oral item", zinc)
+	}
+	if hasWarning(p, domain.WarningRegimenConflict, "zinc") {
+		t.Errorf("unexpected regimen conflict for the same zinc course: %+v", p.Warnings)
+	}
+}
+
+func TestConsolidateContraindications(t *testing.T) {
+	assessment := &domain.Assessment{ID: uuid.New(), AgeMonths: 4, WeightKg: 4.5}
+	malaria := &domain.Classification{ID: uuid.New(), Disease: "MALARIA", TreatmentPriority: 2}
+	sam := &domain.Classification{ID: uuid.New(), Disease: "UNCOMPLICATED SEVERE ACUTE MALNUTRITION", TreatmentPriority: 2}
+	plans := []*domain.TreatmentPlan{
+		plan(malaria.ID, "Artemisinin-Lumefantrine (AL)", "Based on weight", "Twice daily", "3 days", false, ""),
+		plan(malaria.ID, "Primaquine", "Based on weight", "Once daily", "Single dose", false, ""),
+		plan(sam.ID, "Iron supplement", "Based on weight", "Once daily", "14 days", false, ""),
+	}
+
+	p := Consolidate(DefaultDrugTable, assessment, plans, []*domain.Classification{malaria, sam})
+
+	for _, drug := range []string{"primaquine", "artemether_lumefantrine", "iron"} {
+		if !hasWarning(p, domain.WarningContraindication, drug) {
+			t.Errorf("warnings = %+v, want contraindication for %s", p.Warnings, drug)
+		}
+	}
+}
+
+// The durations are those written in the treatment plans of the trees.
+func TestDurationDays(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     int
+	}{
+		{"5 days", 5},
+		{"10-14 days", 14},
+		{"7-14 days", 14},
+		{"2 weeks", 14},
+		{"3-6 months", 180},
+		{"Single dose", 0},
+		{"Once", 0},
+		{"N/A", 0},
+		{"As per schedule", 0},
+		{"Until diarrhea stops", math.MaxInt32},
+		{"Until weight ≥2500g", math.MaxInt32},
+		{"Ongoing", math.MaxInt32},
+		{"Lifelong", math.MaxInt32},
+	}
+
+	for _, tt := range tests {
+		if got := durationDays(tt.duration); got != tt.want {
+			t.Errorf("durationDays(%q) = %d, want %d", tt.duration, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package surveillance implements the CDC Early Aberration Reporting System
+// (EARS) C1, C2 and C3 methods, which flag a day whose case count is
+// unusually high compared with a short moving baseline of the days before.
+package surveillance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	ErrUnknownMethod = errors.New("unknown surveillance method")
+	ErrShortSeries   = errors.New("not enough days for the baseline")
+)
+
+type Method string
+
+const (
+	// C1 compares the day with the mean of the 7 days before it.
+	C1 Method = "c1"
+	// C2 leaves a 2 day gap before the 7 day baseline, so a slowly growing
+	// outbreak does not raise its own baseline.
+	C2 Method = "c2"
+	// C3 sums how far the C2 statistic exceeded 1 over the last 3 days.
+	C3 Method = "c3"
+)
+
+const (
+	baselineDays = 7
+	c2Lag        = 2
+	c3Days       = 3
+	// MinStdDev keeps the statistic finite when the baseline is flat, which
+	// is common for rare classifications at small facilities.
+	MinStdDev = 0.5
+)
+
+// DefaultThreshold is the statistic at which each method raises an alert.
+func DefaultThreshold(method Method) float64 {
+	if method == C3 {
+		return 2
+	}
+	return 3
+}
+
+// ParseMethod accepts c1, c2 or c3 in any case.
+func ParseMethod(raw string) (Method, error) {
+	method := Method(strings.ToLower(strings.TrimSpace(raw)))
+	switch method {
+	case C1, C2, C3:
+		return method, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownMethod, raw)
+}
+
+// SeriesLength is how many daily counts, ending with the day under test,
+// method needs.
+func SeriesLength(method Method) int {
+	switch method {
+	case C1:
+		return baselineDays + 1
+	case C2:
+		return baselineDays + c2Lag + 1
+	case C3:
+		return baselineDays + c2Lag + c3Days
+	}
+	return 0
+}
+
+// Result is the outcome of testing the last day of a series.
+type Result struct {
+	Observed  int
+	Baseline  float64
+	StdDev    float64
+	Statistic float64
+	Threshold float64
+	Alert     bool
+}
+
+// Detect tests the last day of counts, oldest first, against its baseline.
+// A threshold of 0 uses DefaultThreshold.
+func Detect(method Method, counts []int, threshold float64) (Result, error) {
+	if threshold <= 0 {
+		threshold = DefaultThreshold(method)
+	}
+	need := SeriesLength(method)
+	if need == 0 {
+		return Result{}, fmt.Errorf("%w: %q", ErrUnknownMethod, method)
+	}
+	if len(counts) < need {
+		return Result{}, fmt.Errorf("%w: %s needs %d days, got %d", ErrShortSeries, method, need, len(counts))
+	}
+
+	var result Result
+	switch method {
+	case C1:
+		result = statistic(counts, len(counts)-1, 0)
+	case C2:
+		result = statistic(counts, len(counts)-1, c2Lag)
+	case C3:
+		result = statistic(counts, len(counts)-1, c2Lag)
+		result.Statistic = 0
+		for i := 0; i < c3Days; i++ {
+			day := statistic(counts, len(counts)-1-i, c2Lag)
+			result.Statistic += math.Max(0, day.Statistic-1)
+		}
+	}
+
+	result.Threshold = threshold
+	result.Alert = result.Statistic > threshold
+	return result, nil
+}
+
+// statistic standardises counts[day] against the baselineDays days ending
+// lag days before it.
+func statistic(counts []int, day, lag int) Result {
+	end := day - lag
+	baseline := counts[end-baselineDays : end]
+
+	var mean float64
+	for _, c := range baseline {
+		mean += float64(c)
+	}
+	mean /= float64(len(baseline))
+
+	var variance float64
+	for _, c := range baseline {
+		variance += (float64(c) - mean) * (float64(c) - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(baseline)-1))
+
+	return Result{
+		Observed:  counts[day],
+		Baseline:  mean,
+		StdDev:    stdDev,
+		Statistic: (float64(counts[day]) - mean) / math.Max(stdDev, MinStdDev),
+	}
+}
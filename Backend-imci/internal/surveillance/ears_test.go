@@ -0,0 +1,83 @@
+package surveillance
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDetectC1(t *testing.T) {
+	counts := []int{1, 0, 2, 1, 1, 0, 2, 6}
+	result, err := Detect(C1, counts, 0)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if result.Observed != 6 {
+		t.Errorf("observed = %d, want 6", result.Observed)
+	}
+	if math.Abs(result.Baseline-1) > 1e-9 {
+		t.Errorf("baseline = %v, want 1", result.Baseline)
+	}
+	if !result.Alert || result.Threshold != 3 {
+		t.Errorf("expected an alert at the default threshold, got %+v", result)
+	}
+
+	result, _ = Detect(C1, []int{1, 0, 2, 1, 1, 0, 2, 2}, 0)
+	if result.Alert {
+		t.Errorf("ordinary day raised an alert: %+v", result)
+	}
+}
+
+func TestDetectC2IgnoresRecentDays(t *testing.T) {
+	// The two days before the spike are already high; C1 would count them
+	// in the baseline, C2 does not.
+	counts := []int{0, 1, 0, 0, 1, 0, 0, 4, 5, 6}
+	c2, err := Detect(C2, counts, 0)
+	if err != nil {
+		t.Fatalf("Detect C2: %v", err)
+	}
+	c1, _ := Detect(C1, counts, 0)
+	if !c2.Alert || c1.Statistic >= c2.Statistic {
+		t.Errorf("C2 should be more sensitive here: c1 %+v, c2 %+v", c1, c2)
+	}
+}
+
+func TestDetectFlatBaseline(t *testing.T) {
+	result, err := Detect(C2, make([]int, 10), 0)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if math.IsInf(result.Statistic, 0) || math.IsNaN(result.Statistic) || result.Alert {
+		t.Errorf("flat series of zeros: %+v", result)
+	}
+
+	counts := make([]int, 10)
+	counts[9] = 2
+	result, _ = Detect(C2, counts, 0)
+	if result.Statistic != 2/MinStdDev || !result.Alert {
+		t.Errorf("statistic over a flat baseline = %v, want %v", result.Statistic, 2/MinStdDev)
+	}
+}
+
+func TestDetectC3(t *testing.T) {
+	counts := []int{1, 0, 1, 0, 1, 0, 1, 0, 1, 3, 3, 3}
+	result, err := Detect(C3, counts, 0)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if result.Threshold != 2 || !result.Alert {
+		t.Errorf("sustained rise should alert under C3: %+v", result)
+	}
+}
+
+func TestDetectErrors(t *testing.T) {
+	if _, err := Detect(C2, []int{1, 2, 3}, 0); !errors.Is(err, ErrShortSeries) {
+		t.Errorf("short series: got %v", err)
+	}
+	if _, err := Detect("c9", make([]int, 20), 0); !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("unknown method: got %v", err)
+	}
+	if _, err := ParseMethod(" C3 "); err != nil {
+		t.Errorf("ParseMethod: %v", err)
+	}
+}
@@ -30,12 +30,8 @@ func HashPassword(password string) (string, error) {
 // 	return err == nil
 // }
 func ComparePassword(hashedPassword, password string) error {
-    fmt.Println("Hashed Password:", hashedPassword)
-    fmt.Println("Input Password:", password)
-
     err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
     if err != nil {
-        fmt.Println("Error comparing passwords:", err)
         return fmt.Errorf("invalid password")
     }
     return nil
@@ -0,0 +1,268 @@
+// Package vitals checks the vital signs measured during an assessment
+// against the IMNCI danger thresholds and raises the alerts they call for.
+package vitals
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+// Threshold is one alerting rule. A value breaches it when it is below
+// Below or at least AtLeast, whichever is set. MinAgeMonths and MaxAgeMonths
+// bound the age it applies to, MaxAgeMonths exclusive and ignored when zero.
+type Threshold struct {
+	Code           string
+	Sign           domain.VitalSign
+	AssessmentType domain.AssessmentType
+	MinAgeMonths   int
+	MaxAgeMonths   int
+	Below          *float64
+	AtLeast        *float64
+	Severity       domain.VitalAlertSeverity
+	Message        string
+}
+
+func limit(v float64) *float64 {
+	return &v
+}
+
+// DefaultThresholds are the IMNCI danger thresholds. Rules for the same
+// sign are listed most severe first; only the first one breached is
+// reported.
+var DefaultThresholds = []Threshold{
+	{
+		Code:     "HYPOXAEMIA",
+		Sign:     domain.VitalOxygenSaturation,
+		Below:    limit(90),
+		Severity: domain.VitalEmergency,
+		Message:  "SpO2 below 90%: give oxygen and refer urgently",
+	},
+	{
+		Code:           "YOUNG_INFANT_HYPOTHERMIA",
+		Sign:           domain.VitalTemperature,
+		AssessmentType: domain.TypeYoungInfant,
+		Below:          limit(35.5),
+		Severity:       domain.VitalEmergency,
+		Message:        "Temperature below 35.5 °C in a young infant: warm the infant and refer urgently",
+	},
+	{
+		Code:           "YOUNG_INFANT_FEVER",
+		Sign:           domain.VitalTemperature,
+		AssessmentType: domain.TypeYoungInfant,
+		AtLeast:        limit(37.5),
+		Severity:       domain.VitalEmergency,
+		Message:        "Temperature 37.5 °C or above in a young infant: possible very severe disease, refer urgently",
+	},
+	{
+		Code:           "HIGH_FEVER",
+		Sign:           domain.VitalTemperature,
+		AssessmentType: domain.TypeChild,
+		AtLeast:        limit(39),
+		Severity:       domain.VitalWarning,
+		Message:        "Temperature 39 °C or above: give paracetamol and assess for very severe febrile disease",
+	},
+	{
+		Code:           "YOUNG_INFANT_FAST_BREATHING",
+		Sign:           domain.VitalRespiratoryRate,
+		AssessmentType: domain.TypeYoungInfant,
+		AtLeast:        limit(60),
+		Severity:       domain.VitalWarning,
+		Message:        "60 breaths per minute or more in a young infant",
+	},
+	{
+		Code:           "FAST_BREATHING",
+		Sign:           domain.VitalRespiratoryRate,
+		AssessmentType: domain.TypeChild,
+		MaxAgeMonths:   12,
+		AtLeast:        limit(50),
+		Severity:       domain.VitalWarning,
+		Message:        "50 breaths per minute or more in a child under 12 months",
+	},
+	{
+		Code:           "FAST_BREATHING",
+		Sign:           domain.VitalRespiratoryRate,
+		AssessmentType: domain.TypeChild,
+		MinAgeMonths:   12,
+		AtLeast:        limit(40),
+		Severity:       domain.VitalWarning,
+		Message:        "40 breaths per minute or more in a child 12 months or older",
+	},
+	{
+		Code:     "SEVERE_ANAEMIA",
+		Sign:     domain.VitalHemoglobin,
+		Below:    limit(7),
+		Severity: domain.VitalEmergency,
+		Message:  "Hb below 7 g/dL: severe anaemia, refer urgently",
+	},
+	{
+		Code:     "ANAEMIA",
+		Sign:     domain.VitalHemoglobin,
+		Below:    limit(11),
+		Severity: domain.VitalWarning,
+		Message:  "Hb below 11 g/dL: anaemia",
+	},
+}
+
+// Vitals are the measured values of an assessment; unmeasured signs are
+// absent.
+type Vitals map[domain.VitalSign]float64
+
+// FromAssessment collects the vitals recorded on an assessment.
+func FromAssessment(a *domain.Assessment) Vitals {
+	vitals := Vitals{}
+	if a.OxygenSaturation != nil {
+		vitals[domain.VitalOxygenSaturation] = float64(*a.OxygenSaturation)
+	}
+	if a.Temperature != nil {
+		vitals[domain.VitalTemperature] = *a.Temperature
+	}
+	if a.RespiratoryRate != nil {
+		vitals[domain.VitalRespiratoryRate] = float64(*a.RespiratoryRate)
+	}
+	if a.HbLevel != nil {
+		vitals[domain.VitalHemoglobin] = *a.HbLevel
+	}
+	return vitals
+}
+
+// AnswerNodes maps the tree questions that record a measured value to the
+// vital they measure. Yes/no questions such as "Is oxygen saturation
+// <90%?" are left to the trees.
+var AnswerNodes = map[string]domain.VitalSign{
+	"temperature_measurement": domain.VitalTemperature,
+	"breathing_rate":          domain.VitalRespiratoryRate,
+	"respiratory_rate":        domain.VitalRespiratoryRate,
+	"hb_value":                domain.VitalHemoglobin,
+}
+
+// FromAnswers collects the vitals measured in a flow's answers. Answers
+// that are not numbers are skipped.
+func FromAnswers(answers map[string]interface{}) Vitals {
+	vitals := Vitals{}
+	for nodeID, sign := range AnswerNodes {
+		switch v := answers[nodeID].(type) {
+		case float64:
+			vitals[sign] = v
+		case int:
+			vitals[sign] = float64(v)
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				vitals[sign] = f
+			}
+		}
+	}
+	return vitals
+}
+
+// SetOn records vitals on the assessment and reports whether any value
+// changed.
+func (vitals Vitals) SetOn(a *domain.Assessment) bool {
+	changed := false
+	for sign, value := range vitals {
+		switch sign {
+		case domain.VitalOxygenSaturation:
+			changed = setInt(&a.OxygenSaturation, value) || changed
+		case domain.VitalTemperature:
+			changed = setFloat(&a.Temperature, value) || changed
+		case domain.VitalRespiratoryRate:
+			changed = setInt(&a.RespiratoryRate, value) || changed
+		case domain.VitalHemoglobin:
+			changed = setFloat(&a.HbLevel, value) || changed
+		}
+	}
+	return changed
+}
+
+func setFloat(field **float64, value float64) bool {
+	if *field != nil && **field == value {
+		return false
+	}
+	*field = &value
+	return true
+}
+
+func setInt(field **int, value float64) bool {
+	n := int(value)
+	if *field != nil && **field == n {
+		return false
+	}
+	*field = &n
+	return true
+}
+
+func (t Threshold) appliesTo(assessmentType domain.AssessmentType, ageMonths int) bool {
+	if t.AssessmentType != "" && t.AssessmentType != assessmentType {
+		return false
+	}
+	if ageMonths < t.MinAgeMonths {
+		return false
+	}
+	return t.MaxAgeMonths == 0 || ageMonths < t.MaxAgeMonths
+}
+
+// breach returns the limit value crossed, if any.
+func (t Threshold) breach(value float64) (float64, bool) {
+	if t.Below != nil && value < *t.Below {
+		return *t.Below, true
+	}
+	if t.AtLeast != nil && value >= *t.AtLeast {
+		return *t.AtLeast, true
+	}
+	return 0, false
+}
+
+// Evaluate returns the alerts raised by vitals for an assessment of
+// assessmentType at ageMonths, at most one per sign.
+func Evaluate(rules []Threshold, assessmentType domain.AssessmentType, ageMonths int, vitals Vitals) []*domain.VitalAlert {
+	alerts := []*domain.VitalAlert{}
+	alerted := map[domain.VitalSign]bool{}
+	for _, rule := range rules {
+		value, measured := vitals[rule.Sign]
+		if !measured || alerted[rule.Sign] || !rule.appliesTo(assessmentType, ageMonths) {
+			continue
+		}
+		limit, breached := rule.breach(value)
+		if !breached {
+			continue
+		}
+		alerted[rule.Sign] = true
+		alerts = append(alerts, &domain.VitalAlert{
+			Code:      rule.Code,
+			Sign:      rule.Sign,
+			Value:     value,
+			Threshold: limit,
+			Severity:  rule.Severity,
+			Message:   rule.Message,
+		})
+	}
+	return alerts
+}
+
+// SetFlags marks the assessment critical and in need of urgent referral
+// while any alert is an emergency. Once no emergency remains the flags are
+// cleared, except those a classification of the assessment sets. It reports
+// whether a flag changed.
+func SetFlags(a *domain.Assessment, alerts []*domain.VitalAlert, classifications []*domain.Classification) bool {
+	critical, urgent := false, false
+	for _, alert := range alerts {
+		if alert.Severity == domain.VitalEmergency {
+			critical, urgent = true, true
+		}
+	}
+	for _, c := range classifications {
+		critical = critical || c.IsCriticalIllness
+		urgent = urgent || c.RequiresUrgentReferral
+	}
+
+	changed := a.IsCriticalIllness != critical || a.RequiresUrgentReferral != urgent
+	a.IsCriticalIllness = critical
+	a.RequiresUrgentReferral = urgent
+	return changed
+}
+
+// Text is the notification sent for an emergency alert.
+func Text(patientName string, alert *domain.VitalAlert) string {
+	return fmt.Sprintf("Emergency for %s: %s (%s %g).", patientName, alert.Message, alert.Sign, alert.Value)
+}
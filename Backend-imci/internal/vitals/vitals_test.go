@@ -0,0 +1,41 @@
+package vitals
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+func TestEvaluateVitals(t *testing.T) {
+	tests := []struct {
+		name           string
+		assessmentType domain.AssessmentType
+		ageMonths      int
+		vitals         Vitals
+		want           []string
+	}{
+		{"hypoxaemia", domain.TypeChild, 24, Vitals{domain.VitalOxygenSaturation: 88}, []string{"HYPOXAEMIA"}},
+		{"normal saturation", domain.TypeChild, 24, Vitals{domain.VitalOxygenSaturation: 95}, nil},
+		{"young infant hypothermia", domain.TypeYoungInfant, 0, Vitals{domain.VitalTemperature: 35.2}, []string{"YOUNG_INFANT_HYPOTHERMIA"}},
+		{"young infant fever", domain.TypeYoungInfant, 1, Vitals{domain.VitalTemperature: 37.5}, []string{"YOUNG_INFANT_FEVER"}},
+		{"cool child is not a young infant emergency", domain.TypeChild, 24, Vitals{domain.VitalTemperature: 35.2}, nil},
+		{"fast breathing under 12 months", domain.TypeChild, 8, Vitals{domain.VitalRespiratoryRate: 52}, []string{"FAST_BREATHING"}},
+		{"normal breathing over 12 months", domain.TypeChild, 24, Vitals{domain.VitalRespiratoryRate: 38}, nil},
+		{"severe anaemia reported once", domain.TypeChild, 24, Vitals{domain.VitalHemoglobin: 6.5}, []string{"SEVERE_ANAEMIA"}},
+		{"anaemia", domain.TypeChild, 24, Vitals{domain.VitalHemoglobin: 9}, []string{"ANAEMIA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := Evaluate(DefaultThresholds, tt.assessmentType, tt.ageMonths, tt.vitals)
+			var got []string
+			for _, alert := range alerts {
+				got = append(got, alert.Code)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("alerts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+-- WHO weight-for-length/height z-score computed from the assessment weight
+-- and the patient's length or height.
+ALTER TABLE clinical_findings
+    ADD COLUMN IF NOT EXISTS weight_for_height_z_score NUMERIC(4,2);
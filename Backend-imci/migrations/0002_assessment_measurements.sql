@@ -0,0 +1,7 @@
+-- Length/height and head circumference recorded at the assessment, with the
+-- position (lying or standing) used for the length/height measurement.
+ALTER TABLE assessments
+    ADD COLUMN IF NOT EXISTS length_cm NUMERIC(5,1),
+    ADD COLUMN IF NOT EXISTS head_circumference_cm NUMERIC(4,1),
+    ADD COLUMN IF NOT EXISTS measurement_position VARCHAR(10)
+        CHECK (measurement_position IN ('lying', 'standing'));
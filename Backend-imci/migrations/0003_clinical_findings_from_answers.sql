@@ -0,0 +1,52 @@
+-- Structured findings populated from assessment tree answers. Columns that
+-- already existed on clinical_findings are left untouched.
+ALTER TABLE clinical_findings
+    ADD COLUMN IF NOT EXISTS respiratory_rate INTEGER,
+    ADD COLUMN IF NOT EXISTS cough_present BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS cough_duration_days INTEGER,
+    ADD COLUMN IF NOT EXISTS diarrhea_present BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS sunken_eyes BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS skin_pinch_slow BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS skin_pinch_very_slow BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS restless_irritable BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS drinking_eagerly BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS drinking_poorly BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS fever_present BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS runny_nose BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS red_eyes BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS generalized_rash BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS ear_pain BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS ear_discharge BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS ear_discharge_duration_days INTEGER,
+    ADD COLUMN IF NOT EXISTS tender_swelling_behind_ear BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS visible_severe_wasting BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS severe_palmar_pallor BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS some_palmar_pallor BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS hb_level NUMERIC(4,1),
+    ADD COLUMN IF NOT EXISTS hct_level NUMERIC(4,1),
+    ADD COLUMN IF NOT EXISTS unable_to_feed BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS not_feeding_well BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS movement_only_when_stimulated BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS no_movement BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS umbilicus_red BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS umbilicus_draining_pus BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS skin_pustules BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS low_body_temperature BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS body_temperature NUMERIC(4,1),
+    ADD COLUMN IF NOT EXISTS hiv_exposed BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS hiv_status_known BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tb_cough_duration_days INTEGER,
+    ADD COLUMN IF NOT EXISTS tb_contact_history BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tb_weight_loss BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS night_sweats BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS suspected_developmental_delay BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS milestones_absent TEXT[],
+    ADD COLUMN IF NOT EXISTS risk_factors_present TEXT[],
+    ADD COLUMN IF NOT EXISTS breastfeeding BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS breastfeeding_frequency INTEGER,
+    ADD COLUMN IF NOT EXISTS complementary_foods BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS feeding_problem BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS underweight BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS other_findings TEXT NOT NULL DEFAULT '';
+
+UPDATE clinical_findings SET other_findings = '' WHERE other_findings IS NULL;
@@ -0,0 +1,14 @@
+-- Per-patient vaccine registry used by the EPI schedule engine.
+CREATE TABLE IF NOT EXISTS immunizations (
+    id UUID PRIMARY KEY,
+    patient_id UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    vaccine VARCHAR(20) NOT NULL,
+    dose_date DATE NOT NULL,
+    lot_number VARCHAR(50),
+    facility VARCHAR(255),
+    recorded_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (patient_id, vaccine)
+);
+
+CREATE INDEX IF NOT EXISTS idx_immunizations_patient_id ON immunizations(patient_id);
@@ -0,0 +1,13 @@
+-- Vitamin A and deworming doses given to a patient.
+CREATE TABLE IF NOT EXISTS supplement_doses (
+    id UUID PRIMARY KEY,
+    patient_id UUID NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    supplement VARCHAR(20) NOT NULL CHECK (supplement IN ('vitamin_a', 'deworming')),
+    dose VARCHAR(50) NOT NULL,
+    dose_date DATE NOT NULL,
+    facility VARCHAR(255),
+    recorded_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplement_doses_patient_id ON supplement_doses(patient_id, dose_date DESC);
@@ -0,0 +1,17 @@
+-- Server-side refresh sessions, one row per logged-in device. token_hash holds
+-- the SHA-256 of the only refresh token currently valid for the session.
+CREATE TABLE IF NOT EXISTS refresh_sessions (
+    id UUID PRIMARY KEY,
+    medical_professional_id UUID NOT NULL REFERENCES medical_professionals(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL,
+    device VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    revoked_reason VARCHAR(50)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_sessions_professional
+    ON refresh_sessions(medical_professional_id) WHERE revoked_at IS NULL;
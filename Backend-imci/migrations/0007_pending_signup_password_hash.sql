@@ -0,0 +1,8 @@
+-- Pending signups used to hold the plaintext password. Drop those rows (they
+-- live for five minutes anyway) and keep only a bcrypt hash from now on.
+DELETE FROM otp;
+
+ALTER TABLE otp RENAME COLUMN password TO password_hash;
+ALTER TABLE otp ADD COLUMN IF NOT EXISTS use_whatsapp BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_otp_expires_at ON otp(expires_at);
@@ -0,0 +1,13 @@
+-- Failed logins, lockouts and admin unlocks.
+CREATE TABLE IF NOT EXISTS login_audit (
+    id UUID PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    medical_professional_id UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    device VARCHAR(255) NOT NULL DEFAULT '',
+    event VARCHAR(30) NOT NULL,
+    performed_by UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_audit_phone ON login_audit(phone, created_at DESC);
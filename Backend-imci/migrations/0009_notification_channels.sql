@@ -0,0 +1,17 @@
+-- Per-user OTP channel preference and a log of every delivery attempt.
+ALTER TABLE medical_professionals ADD COLUMN IF NOT EXISTS notification_channel VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE otp ADD COLUMN IF NOT EXISTS notification_channel VARCHAR(20) NOT NULL DEFAULT '';
+
+UPDATE medical_professionals SET notification_channel = 'whatsapp' WHERE use_whatsapp AND notification_channel = '';
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id UUID PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_phone ON notification_deliveries(phone, created_at DESC);
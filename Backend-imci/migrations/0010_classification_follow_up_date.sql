@@ -0,0 +1,5 @@
+-- Follow-up day derived from the outcome's follow-up advice, used for the
+-- clinician's daily follow-up list.
+ALTER TABLE classifications ADD COLUMN IF NOT EXISTS follow_up_date DATE;
+
+CREATE INDEX IF NOT EXISTS idx_classifications_follow_up_date ON classifications(follow_up_date) WHERE follow_up_date IS NOT NULL;
@@ -0,0 +1,13 @@
+-- Delivery callbacks reported by the WhatsApp Cloud API webhook.
+CREATE TABLE IF NOT EXISTS whatsapp_message_statuses (
+    message_id VARCHAR(128) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    recipient_id VARCHAR(20) NOT NULL,
+    error_code INTEGER NOT NULL DEFAULT 0,
+    error_title TEXT NOT NULL DEFAULT '',
+    reported_at TIMESTAMP NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, status)
+);
+
+CREATE INDEX IF NOT EXISTS idx_whatsapp_message_statuses_recipient ON whatsapp_message_statuses(recipient_id, reported_at DESC);
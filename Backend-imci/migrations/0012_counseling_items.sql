@@ -0,0 +1,8 @@
+-- Structured counselling items per classification with the caregiver's
+-- confirmation.
+ALTER TABLE counselings ADD COLUMN IF NOT EXISTS classification_id UUID REFERENCES classifications(id) ON DELETE CASCADE;
+ALTER TABLE counselings ADD COLUMN IF NOT EXISTS understood_at TIMESTAMP;
+ALTER TABLE counselings ADD COLUMN IF NOT EXISTS counseled_by UUID REFERENCES medical_professionals(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_counselings_classification_advice
+    ON counselings(classification_id, advice_type) WHERE classification_id IS NOT NULL;
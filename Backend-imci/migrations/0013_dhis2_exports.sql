@@ -0,0 +1,20 @@
+-- Pushes of aggregate IMNCI data values to DHIS2.
+CREATE TABLE IF NOT EXISTS dhis2_exports (
+    id UUID PRIMARY KEY,
+    period VARCHAR(10) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    data_values INTEGER NOT NULL DEFAULT 0,
+    imported INTEGER NOT NULL DEFAULT 0,
+    updated INTEGER NOT NULL DEFAULT 0,
+    ignored INTEGER NOT NULL DEFAULT 0,
+    deleted INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    triggered_by UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dhis2_exports_period ON dhis2_exports(period, status);
+CREATE INDEX IF NOT EXISTS idx_dhis2_exports_created_at ON dhis2_exports(created_at DESC);
+
+-- Speeds up the period aggregation over assessments.
+CREATE INDEX IF NOT EXISTS idx_assessments_start_time ON assessments(start_time);
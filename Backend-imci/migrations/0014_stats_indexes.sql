@@ -0,0 +1,5 @@
+-- Indexes behind the facility dashboard aggregates.
+CREATE INDEX IF NOT EXISTS idx_classifications_assessment_id ON classifications(assessment_id);
+CREATE INDEX IF NOT EXISTS idx_assessments_patient_start_time ON assessments(patient_id, start_time);
+CREATE INDEX IF NOT EXISTS idx_medical_professionals_facility_name ON medical_professionals(facility_name);
+CREATE INDEX IF NOT EXISTS idx_treatment_plans_pre_referral ON treatment_plans(assessment_id) WHERE is_pre_referral;
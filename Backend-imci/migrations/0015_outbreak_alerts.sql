@@ -0,0 +1,21 @@
+-- Outbreak alerts raised by the EARS surveillance job.
+CREATE TABLE IF NOT EXISTS outbreak_alerts (
+    id UUID PRIMARY KEY,
+    facility_name VARCHAR(255) NOT NULL,
+    signal VARCHAR(100) NOT NULL,
+    alert_date DATE NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    observed INTEGER NOT NULL,
+    baseline DOUBLE PRECISION NOT NULL,
+    statistic DOUBLE PRECISION NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    notified INTEGER NOT NULL DEFAULT 0,
+    acknowledged_by UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    acknowledged_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbreak_alerts_day
+    ON outbreak_alerts(facility_name, signal, alert_date, method);
+CREATE INDEX IF NOT EXISTS idx_outbreak_alerts_status ON outbreak_alerts(status, alert_date DESC);
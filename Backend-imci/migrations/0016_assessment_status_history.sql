@@ -0,0 +1,22 @@
+-- Every assessment status transition, with who made it and why.
+CREATE TABLE IF NOT EXISTS assessment_status_history (
+    id UUID PRIMARY KEY,
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(20) NOT NULL,
+    changed_by UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_status_history_assessment
+    ON assessment_status_history(assessment_id, created_at);
+
+-- Trees finished per assessment, checked before an assessment is completed.
+CREATE TABLE IF NOT EXISTS assessment_trees (
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    tree_id VARCHAR(100) NOT NULL,
+    completed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (assessment_id, tree_id)
+);
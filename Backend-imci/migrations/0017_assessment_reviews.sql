@@ -0,0 +1,40 @@
+-- Clinicians whose classifications must be co-signed by a supervisor.
+ALTER TABLE medical_professionals ADD COLUMN IF NOT EXISTS is_trainee BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Classifications an offline device reached before the assessment synced,
+-- compared with the server's to find discordant results.
+CREATE TABLE IF NOT EXISTS offline_results (
+    assessment_id UUID PRIMARY KEY REFERENCES assessments(id) ON DELETE CASCADE,
+    classifications TEXT[] NOT NULL,
+    reported_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Assessments queued for supervisor review and why.
+CREATE TABLE IF NOT EXISTS assessment_reviews (
+    id UUID PRIMARY KEY,
+    assessment_id UUID NOT NULL UNIQUE REFERENCES assessments(id) ON DELETE CASCADE,
+    reasons TEXT[] NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_reviews_status
+    ON assessment_reviews(status, created_at);
+
+-- Approvals, amendments and comments on a review. Amendments keep the
+-- diff against the clinician's answers and classifications.
+CREATE TABLE IF NOT EXISTS review_actions (
+    id UUID PRIMARY KEY,
+    review_id UUID NOT NULL REFERENCES assessment_reviews(id) ON DELETE CASCADE,
+    reviewer_id UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    diff JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_actions_review
+    ON review_actions(review_id, created_at);
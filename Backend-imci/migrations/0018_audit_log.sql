@@ -0,0 +1,45 @@
+-- Patients and assessments are soft-deleted so their history is kept.
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE assessments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_assessments_not_deleted
+    ON assessments(patient_id) WHERE deleted_at IS NULL;
+
+-- Append-only, hash-chained record of who viewed, created, edited or
+-- deleted clinical data. before_data and after_data are JSON rather than
+-- JSONB so the text that was hashed is kept byte for byte.
+CREATE TABLE IF NOT EXISTS audit_log (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    actor_id UUID,
+    actor_role VARCHAR(30) NOT NULL DEFAULT '',
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id UUID NOT NULL,
+    before_data JSON,
+    after_data JSON,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    method VARCHAR(10) NOT NULL DEFAULT '',
+    path TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id, seq);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, seq);
+
+-- The hash chain shows tampering; the trigger stops the application from
+-- doing it in the first place.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,16 @@
+-- Danger-threshold breaches of an assessment's vitals. Each code is stored
+-- once per assessment so emergencies are notified only once.
+CREATE TABLE IF NOT EXISTS vital_alerts (
+    id UUID PRIMARY KEY,
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    sign VARCHAR(30) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    message TEXT NOT NULL,
+    notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (assessment_id, code)
+);
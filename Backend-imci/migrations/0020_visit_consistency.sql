@@ -0,0 +1,26 @@
+-- Answers of every completed tree of an assessment, kept for checks across
+-- the visit since medical_professional_answers only holds the latest tree.
+CREATE TABLE IF NOT EXISTS tree_answers (
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    tree_id VARCHAR(100) NOT NULL,
+    answers JSONB NOT NULL,
+    completed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (assessment_id, tree_id)
+);
+
+-- Contradictions and escalations found across the trees of a visit.
+CREATE TABLE IF NOT EXISTS consistency_findings (
+    id UUID PRIMARY KEY,
+    assessment_id UUID NOT NULL REFERENCES assessments(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    code VARCHAR(100) NOT NULL,
+    tree_ids TEXT[] NOT NULL,
+    detail TEXT NOT NULL,
+    classification_id UUID REFERENCES classifications(id) ON DELETE SET NULL,
+    from_disease VARCHAR(255),
+    to_disease VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_consistency_findings_code
+    ON consistency_findings(assessment_id, code, COALESCE(classification_id, '00000000-0000-0000-0000-000000000000'::uuid));
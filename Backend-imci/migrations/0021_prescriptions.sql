@@ -0,0 +1,28 @@
+-- The final prescription of a visit: its treatment plans merged per drug and
+-- ordered by urgency. It is regenerated, not edited, so items are replaced
+-- as a whole.
+CREATE TABLE IF NOT EXISTS prescriptions (
+    id UUID PRIMARY KEY,
+    assessment_id UUID NOT NULL UNIQUE REFERENCES assessments(id) ON DELETE CASCADE,
+    warnings JSONB NOT NULL DEFAULT '[]',
+    generated_by UUID REFERENCES medical_professionals(id) ON DELETE SET NULL,
+    generated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS prescription_items (
+    id UUID PRIMARY KEY,
+    prescription_id UUID NOT NULL REFERENCES prescriptions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    drug_key VARCHAR(100) NOT NULL DEFAULT '',
+    drug_name VARCHAR(255) NOT NULL,
+    dosage VARCHAR(255) NOT NULL DEFAULT '',
+    frequency VARCHAR(255) NOT NULL DEFAULT '',
+    duration VARCHAR(255) NOT NULL DEFAULT '',
+    administration_route VARCHAR(100) NOT NULL DEFAULT '',
+    is_pre_referral BOOLEAN NOT NULL DEFAULT FALSE,
+    instructions TEXT NOT NULL DEFAULT '',
+    treatment_priority INTEGER NOT NULL,
+    treatment_plan_ids UUID[] NOT NULL,
+    classification_ids UUID[] NOT NULL,
+    UNIQUE (prescription_id, position)
+);
@@ -0,0 +1,8 @@
+-- Incoming WhatsApp messages already handled. Meta redelivers a message when
+-- the webhook is slow to acknowledge it, so each ID is answered only once.
+CREATE TABLE IF NOT EXISTS whatsapp_inbound_messages (
+    message_id VARCHAR(128) PRIMARY KEY,
+    from_phone VARCHAR(20) NOT NULL,
+    sent_at TIMESTAMP NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
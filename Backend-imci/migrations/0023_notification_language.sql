@@ -0,0 +1,3 @@
+-- Per-user language for templated WhatsApp messages; empty means English.
+ALTER TABLE medical_professionals ADD COLUMN IF NOT EXISTS notification_language VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE otp ADD COLUMN IF NOT EXISTS notification_language VARCHAR(10) NOT NULL DEFAULT '';
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AssessmentLifecycleRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAssessmentLifecycleRepo(db *pgxpool.Pool) domain.AssessmentLifecycleRepository {
+	return &AssessmentLifecycleRepo{db: db}
+}
+
+func (r *AssessmentLifecycleRepo) GetAssessment(ctx context.Context, id uuid.UUID) (*domain.Assessment, error) {
+	query := `
+		SELECT id, medical_professional_id, patient_id, assessment_type, status, start_time, end_time
+		FROM assessments
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var assessment domain.Assessment
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&assessment.ID,
+		&assessment.MedicalProfessionalID,
+		&assessment.PatientID,
+		&assessment.AssessmentType,
+		&assessment.Status,
+		&assessment.StartTime,
+		&assessment.EndTime,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrAssessmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get assessment: %w", err)
+	}
+
+	return &assessment, nil
+}
+
+func (r *AssessmentLifecycleRepo) Transition(ctx context.Context, change *domain.AssessmentStatusChange, endTime *time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE assessments
+		SET status = $3, end_time = COALESCE($4, end_time), updated_at = $5
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+	`, change.AssessmentID, change.FromStatus, change.ToStatus, endTime, change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update assessment status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAssessmentStatusChanged
+	}
+
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO assessment_status_history (
+			id, assessment_id, from_status, to_status, actor, changed_by, reason, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		change.ID,
+		change.AssessmentID,
+		change.FromStatus,
+		change.ToStatus,
+		change.Actor,
+		change.ChangedBy,
+		change.Reason,
+		change.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit status change: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AssessmentLifecycleRepo) ListHistory(ctx context.Context, assessmentID uuid.UUID) ([]*domain.AssessmentStatusChange, error) {
+	query := `
+		SELECT id, assessment_id, from_status, to_status, actor, changed_by, reason, created_at
+		FROM assessment_status_history
+		WHERE assessment_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*domain.AssessmentStatusChange{}
+	for rows.Next() {
+		var change domain.AssessmentStatusChange
+		err := rows.Scan(
+			&change.ID,
+			&change.AssessmentID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Actor,
+			&change.ChangedBy,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		history = append(history, &change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status history: %w", err)
+	}
+
+	return history, nil
+}
+
+func (r *AssessmentLifecycleRepo) MarkTreeCompleted(ctx context.Context, assessmentID uuid.UUID, treeID string, at time.Time) error {
+	query := `
+		INSERT INTO assessment_trees (assessment_id, tree_id, completed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (assessment_id, tree_id) DO UPDATE SET completed_at = EXCLUDED.completed_at
+	`
+
+	if _, err := r.db.Exec(ctx, query, assessmentID, treeID, at); err != nil {
+		return fmt.Errorf("failed to record finished tree: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AssessmentLifecycleRepo) CompletedTrees(ctx context.Context, assessmentID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT tree_id FROM assessment_trees
+		WHERE assessment_id = $1
+		ORDER BY completed_at
+	`
+
+	rows, err := r.db.Query(ctx, query, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query finished trees: %w", err)
+	}
+	defer rows.Close()
+
+	trees := []string{}
+	for rows.Next() {
+		var treeID string
+		if err := rows.Scan(&treeID); err != nil {
+			return nil, fmt.Errorf("failed to scan finished tree: %w", err)
+		}
+		trees = append(trees, treeID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating finished trees: %w", err)
+	}
+
+	return trees, nil
+}
@@ -6,7 +6,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
@@ -30,8 +29,9 @@ func (r *AssessmentRepo) Create(ctx context.Context, assessment *domain.Assessme
 			weight_kg, temperature, main_symptoms, muac, respiratory_rate,
 			age_months, guideline_version, start_time, is_offline, created_at, updated_at,
 			oxygen_saturation, jaundice_signs, development_milestones, hb_level,
-			bilateral_edema, is_critical_illness, requires_urgent_referral
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+			bilateral_edema, is_critical_illness, requires_urgent_referral,
+			length_cm, head_circumference_cm, measurement_position
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26)
 	`
 
 	mainSymptomsJSON, err := json.Marshal(assessment.MainSymptoms)
@@ -77,6 +77,9 @@ func (r *AssessmentRepo) Create(ctx context.Context, assessment *domain.Assessme
 		assessment.BilateralEdema,
 		assessment.IsCriticalIllness,
 		assessment.RequiresUrgentReferral,
+		assessment.LengthCm,
+		assessment.HeadCircumferenceCm,
+		nullableString(string(assessment.MeasurementPosition)),
 	)
 
 	if err != nil {
@@ -94,24 +97,18 @@ func (r *AssessmentRepo) GetByID(ctx context.Context, id uuid.UUID, medicalProfe
 			age_months, guideline_version, start_time, end_time, summary,
 			is_offline, synced_at, created_at, updated_at,
 			oxygen_saturation, jaundice_signs, development_milestones, hb_level,
-			bilateral_edema, is_critical_illness, requires_urgent_referral
+			bilateral_edema, is_critical_illness, requires_urgent_referral,
+			length_cm, head_circumference_cm, measurement_position
 		FROM assessments 
-		WHERE id = $1 AND medical_professional_id = $2
+		WHERE id = $1 AND medical_professional_id = $2 AND deleted_at IS NULL
 	`
 
 	var assessment domain.Assessment
 	var mainSymptoms, jaundiceSigns, developmentMilestones []byte
-	var temperature, muac, hbLevel sql.NullFloat64
+	var temperature, muac, hbLevel, lengthCm, headCircumference sql.NullFloat64
 	var respiratoryRate, oxygenSaturation sql.NullInt32
 	var endTime, syncedAt sql.NullTime
-	var summary sql.NullString
-
-	// Add this before the Scan to see the exact query
-fmt.Printf("🔍 Executing query with params: assessmentID=%s, medicalProfessionalID=%s\n", id, medicalProfessionalID)
-
-// Count the number of ? placeholders in your SELECT vs Scan arguments
-selectCount := strings.Count(query, ",") + 1 // +1 for the first column
-fmt.Printf("🔍 SELECT has %d columns, Scan has 26 destinations\n", selectCount)
+	var summary, measurementPosition sql.NullString
 
 	err := r.db.QueryRow(ctx, query, id, medicalProfessionalID).Scan(
 		&assessment.ID,                       // 1
@@ -140,6 +137,9 @@ fmt.Printf("🔍 SELECT has %d columns, Scan has 26 destinations\n", selectCount
 		&assessment.BilateralEdema,           // 24
 		&assessment.IsCriticalIllness,        // 25
 		&assessment.RequiresUrgentReferral,   // 26
+		&lengthCm,                            // 27
+		&headCircumference,                   // 28
+		&measurementPosition,                 // 29
 	)
 
 	if err != nil {
@@ -170,6 +170,17 @@ fmt.Printf("🔍 SELECT has %d columns, Scan has 26 destinations\n", selectCount
 		hb := hbLevel.Float64
 		assessment.HbLevel = &hb
 	}
+	if lengthCm.Valid {
+		length := lengthCm.Float64
+		assessment.LengthCm = &length
+	}
+	if headCircumference.Valid {
+		hc := headCircumference.Float64
+		assessment.HeadCircumferenceCm = &hc
+	}
+	if measurementPosition.Valid {
+		assessment.MeasurementPosition = domain.MeasurementPosition(measurementPosition.String)
+	}
 	if endTime.Valid {
 		assessment.EndTime = &endTime.Time
 	}
@@ -194,15 +205,18 @@ fmt.Printf("🔍 SELECT has %d columns, Scan has 26 destinations\n", selectCount
 	return &assessment, nil
 }
 
+// Update saves the clinical fields of an assessment. Status and end time
+// only change through the lifecycle transitions.
 func (r *AssessmentRepo) Update(ctx context.Context, assessment *domain.Assessment) error {
 	query := `
 		UPDATE assessments 
-		SET status = $1, temperature = $2, main_symptoms = $3, muac = $4, 
-			respiratory_rate = $5, end_time = $6, summary = $7, synced_at = $8,
-			updated_at = $9, oxygen_saturation = $10, jaundice_signs = $11,
-			development_milestones = $12, hb_level = $13, bilateral_edema = $14,
-			is_critical_illness = $15, requires_urgent_referral = $16
-		WHERE id = $17 AND medical_professional_id = $18
+		SET temperature = $1, main_symptoms = $2, muac = $3, 
+			respiratory_rate = $4, summary = $5, synced_at = $6,
+			updated_at = $7, oxygen_saturation = $8, jaundice_signs = $9,
+			development_milestones = $10, hb_level = $11, bilateral_edema = $12,
+			is_critical_illness = $13, requires_urgent_referral = $14,
+			length_cm = $15, head_circumference_cm = $16, measurement_position = $17
+		WHERE id = $18 AND medical_professional_id = $19 AND deleted_at IS NULL
 	`
 
 	mainSymptomsJSON, err := json.Marshal(assessment.MainSymptoms)
@@ -223,12 +237,10 @@ func (r *AssessmentRepo) Update(ctx context.Context, assessment *domain.Assessme
 	assessment.UpdatedAt = time.Now()
 
 	result, err := r.db.Exec(ctx, query,
-		assessment.Status,
 		assessment.Temperature,
 		mainSymptomsJSON,
 		assessment.MUAC,
 		assessment.RespiratoryRate,
-		assessment.EndTime,
 		assessment.Summary,
 		assessment.SyncedAt,
 		assessment.UpdatedAt,
@@ -239,6 +251,9 @@ func (r *AssessmentRepo) Update(ctx context.Context, assessment *domain.Assessme
 		assessment.BilateralEdema,
 		assessment.IsCriticalIllness,
 		assessment.RequiresUrgentReferral,
+		assessment.LengthCm,
+		assessment.HeadCircumferenceCm,
+		nullableString(string(assessment.MeasurementPosition)),
 		assessment.ID,
 		assessment.MedicalProfessionalID,
 	)
@@ -260,7 +275,7 @@ func (r *AssessmentRepo) CalculateAgeInfo(ctx context.Context, patientID uuid.UU
 			EXTRACT(YEAR FROM AGE($1, date_of_birth)) * 12 + 
 			EXTRACT(MONTH FROM AGE($1, date_of_birth)) as age_months
 		FROM patients 
-		WHERE id = $2
+		WHERE id = $2 AND deleted_at IS NULL
 	`
 
 	var ageMonths int
@@ -293,9 +308,10 @@ func (r *AssessmentRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID
 		SELECT id, medical_professional_id, patient_id, assessment_type, status,
 			weight_kg, temperature, main_symptoms, muac, respiratory_rate,
 			age_months, guideline_version, start_time, end_time, summary,
-			is_offline, synced_at, created_at, updated_at
+			is_offline, synced_at, created_at, updated_at,
+			length_cm, head_circumference_cm, measurement_position
 		FROM assessments 
-		WHERE patient_id = $1 AND medical_professional_id = $2
+		WHERE patient_id = $1 AND medical_professional_id = $2 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -309,10 +325,10 @@ func (r *AssessmentRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID
 	for rows.Next() {
 		var assessment domain.Assessment
 		var mainSymptoms []byte
-		var temperature, muac sql.NullFloat64
+		var temperature, muac, lengthCm, headCircumference sql.NullFloat64
 		var respiratoryRate sql.NullInt32
 		var endTime, syncedAt sql.NullTime
-		var summary sql.NullString
+		var summary, measurementPosition sql.NullString
 
 		err := rows.Scan(
 			&assessment.ID,
@@ -330,11 +346,13 @@ func (r *AssessmentRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID
 			&assessment.StartTime,
 			&endTime,
 			&summary,
-			&assessment.Summary,
 			&assessment.IsOffline,
 			&syncedAt,
 			&assessment.CreatedAt,
 			&assessment.UpdatedAt,
+			&lengthCm,
+			&headCircumference,
+			&measurementPosition,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan assessment: %w", err)
@@ -362,6 +380,17 @@ func (r *AssessmentRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID
 		if summary.Valid { // ✅ Handle NULL summary
 			assessment.Summary = summary.String
 		}
+		if lengthCm.Valid {
+			length := lengthCm.Float64
+			assessment.LengthCm = &length
+		}
+		if headCircumference.Valid {
+			hc := headCircumference.Float64
+			assessment.HeadCircumferenceCm = &hc
+		}
+		if measurementPosition.Valid {
+			assessment.MeasurementPosition = domain.MeasurementPosition(measurementPosition.String)
+		}
 
 		// Unmarshal JSONB
 		if err := json.Unmarshal(mainSymptoms, &assessment.MainSymptoms); err != nil {
@@ -378,10 +407,15 @@ func (r *AssessmentRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID
 	return assessments, nil
 }
 
+// Delete soft-deletes the assessment; it stays in the database for the audit
+// trail but is hidden from every query.
 func (r *AssessmentRepo) Delete(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID) error {
-	query := `DELETE FROM assessments WHERE id = $1 AND medical_professional_id = $2`
+	query := `
+		UPDATE assessments SET deleted_at = $3
+		WHERE id = $1 AND medical_professional_id = $2 AND deleted_at IS NULL
+	`
 
-	result, err := r.db.Exec(ctx, query, id, medicalProfessionalID)
+	result, err := r.db.Exec(ctx, query, id, medicalProfessionalID, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to delete assessment: %w", err)
 	}
@@ -391,4 +425,12 @@ func (r *AssessmentRepo) Delete(ctx context.Context, id uuid.UUID, medicalProfes
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// nullableString stores empty enum-like values as NULL.
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AssessmentReviewRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAssessmentReviewRepo(db *pgxpool.Pool) domain.AssessmentReviewRepository {
+	return &AssessmentReviewRepo{db: db}
+}
+
+const reviewColumns = `
+	r.id, r.assessment_id, a.patient_id, a.medical_professional_id,
+	COALESCE(mp.full_name, ''), COALESCE(mp.facility_name, ''),
+	r.reasons, r.status, r.reviewed_by, r.reviewed_at, r.created_at, r.updated_at
+`
+
+func scanReview(row pgx.Row) (*domain.AssessmentReview, error) {
+	var review domain.AssessmentReview
+	var reasons []string
+	err := row.Scan(
+		&review.ID,
+		&review.AssessmentID,
+		&review.PatientID,
+		&review.MedicalProfessionalID,
+		&review.ClinicianName,
+		&review.Facility,
+		&reasons,
+		&review.Status,
+		&review.ReviewedBy,
+		&review.ReviewedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Reasons = make([]domain.ReviewReason, len(reasons))
+	for i, reason := range reasons {
+		review.Reasons[i] = domain.ReviewReason(reason)
+	}
+	return &review, nil
+}
+
+func (r *AssessmentReviewRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*domain.AssessmentReview, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM assessment_reviews r
+		JOIN assessments a ON a.id = r.assessment_id
+		LEFT JOIN medical_professionals mp ON mp.id = a.medical_professional_id
+		WHERE r.assessment_id = $1 AND a.deleted_at IS NULL
+	`
+
+	review, err := scanReview(r.db.QueryRow(ctx, query, assessmentID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get assessment review: %w", err)
+	}
+
+	return review, nil
+}
+
+func (r *AssessmentReviewRepo) Save(ctx context.Context, review *domain.AssessmentReview) error {
+	query := `
+		INSERT INTO assessment_reviews (id, assessment_id, reasons, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (assessment_id) DO UPDATE
+		SET reasons = EXCLUDED.reasons, status = EXCLUDED.status, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	if review.ID == uuid.Nil {
+		review.ID = uuid.New()
+	}
+
+	reasons := make([]string, len(review.Reasons))
+	for i, reason := range review.Reasons {
+		reasons[i] = string(reason)
+	}
+
+	err := r.db.QueryRow(ctx, query,
+		review.ID,
+		review.AssessmentID,
+		reasons,
+		review.Status,
+		review.CreatedAt,
+		review.UpdatedAt,
+	).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save assessment review: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AssessmentReviewRepo) ListQueue(ctx context.Context, status domain.ReviewStatus, facility string, limit int) ([]*domain.AssessmentReview, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM assessment_reviews r
+		JOIN assessments a ON a.id = r.assessment_id
+		LEFT JOIN medical_professionals mp ON mp.id = a.medical_professional_id
+		WHERE r.status = $1 AND a.deleted_at IS NULL
+		  AND ($2::text = '' OR mp.facility_name = $2::text)
+		ORDER BY r.created_at
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, status, facility, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review queue: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []*domain.AssessmentReview{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assessment review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review queue: %w", err)
+	}
+
+	return reviews, nil
+}
+
+func (r *AssessmentReviewRepo) RecordAction(ctx context.Context, action *domain.ReviewAction, status domain.ReviewStatus) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := recordReviewAction(ctx, tx, action, status); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit review action: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AssessmentReviewRepo) Amend(ctx context.Context, action *domain.ReviewAction, status domain.ReviewStatus, amendment *domain.ReviewAmendment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := recordReviewAction(ctx, tx, action, status); err != nil {
+		return err
+	}
+
+	now := action.CreatedAt
+	if amendment.Answers != nil {
+		answersJSON, err := json.Marshal(amendment.Answers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal answers: %w", err)
+		}
+		result, err := tx.Exec(ctx, `
+			UPDATE medical_professional_answers
+			SET answers = $2, updated_at = $3
+			WHERE assessment_id = $1
+		`, amendment.AssessmentID, answersJSON, now)
+		if err != nil {
+			return fmt.Errorf("failed to amend answers: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO medical_professional_answers (
+					id, assessment_id, answers, question_set_version, clinical_findings, created_at, updated_at
+				) VALUES ($1, $2, $3, '', '{}', $4, $4)
+			`, uuid.New(), amendment.AssessmentID, answersJSON, now)
+			if err != nil {
+				return fmt.Errorf("failed to create amended answers: %w", err)
+			}
+		}
+	}
+
+	if len(amendment.Removed) > 0 {
+		for _, query := range []string{
+			`DELETE FROM treatment_plans WHERE classification_id = ANY($1)`,
+			`DELETE FROM counselings WHERE classification_id = ANY($1)`,
+			`DELETE FROM classifications WHERE id = ANY($1)`,
+		} {
+			if _, err := tx.Exec(ctx, query, amendment.Removed); err != nil {
+				return fmt.Errorf("failed to remove amended classifications: %w", err)
+			}
+		}
+	}
+
+	for _, c := range amendment.Changed {
+		_, err := tx.Exec(ctx, `
+			UPDATE classifications
+			SET color = $2, details = $3, is_critical_illness = $4,
+				requires_urgent_referral = $5, treatment_priority = $6
+			WHERE id = $1
+		`, c.ID, c.Color, c.Details, c.IsCriticalIllness, c.RequiresUrgentReferral, c.TreatmentPriority)
+		if err != nil {
+			return fmt.Errorf("failed to amend classification: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM counselings WHERE classification_id = $1`, c.ID); err != nil {
+			return fmt.Errorf("failed to remove counseling of amended classification: %w", err)
+		}
+	}
+
+	for _, c := range amendment.Added {
+		c.CreatedAt = now
+		_, err := tx.Exec(ctx, `
+			INSERT INTO classifications (
+				id, assessment_id, disease, color, details, rule_version,
+				confidence_score, is_critical_illness, requires_urgent_referral,
+				treatment_priority, follow_up_date, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`,
+			c.ID,
+			c.AssessmentID,
+			c.Disease,
+			c.Color,
+			c.Details,
+			c.RuleVersion,
+			c.ConfidenceScore,
+			c.IsCriticalIllness,
+			c.RequiresUrgentReferral,
+			c.TreatmentPriority,
+			c.FollowUpDate,
+			c.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add amended classification: %w", err)
+		}
+	}
+
+	for classificationID, plans := range amendment.Plans {
+		if _, err := tx.Exec(ctx, `DELETE FROM treatment_plans WHERE classification_id = $1`, classificationID); err != nil {
+			return fmt.Errorf("failed to replace treatment plans: %w", err)
+		}
+		for _, plan := range plans {
+			plan.CreatedAt, plan.UpdatedAt = now, now
+			if err := createTreatmentPlan(ctx, tx, plan); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, counseling := range amendment.Counselings {
+		counseling.CreatedAt = now
+		if err := createCounseling(ctx, tx, counseling); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range amendment.Audit {
+		if err := appendAuditEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit review amendment: %w", err)
+	}
+
+	return nil
+}
+
+// recordReviewAction signs the review off with status, unless it is empty,
+// and stores action.
+func recordReviewAction(ctx context.Context, tx pgx.Tx, action *domain.ReviewAction, status domain.ReviewStatus) error {
+	if status != "" {
+		result, err := tx.Exec(ctx, `
+			UPDATE assessment_reviews
+			SET status = $2, reviewed_by = $3, reviewed_at = $4, updated_at = $4
+			WHERE id = $1 AND status = $5
+		`, action.ReviewID, status, action.ReviewerID, action.CreatedAt, domain.ReviewPending)
+		if err != nil {
+			return fmt.Errorf("failed to sign off assessment review: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return domain.ErrReviewClosed
+		}
+	}
+
+	if action.ID == uuid.Nil {
+		action.ID = uuid.New()
+	}
+
+	var diff []byte
+	var err error
+	if action.Diff != nil {
+		if diff, err = json.Marshal(action.Diff); err != nil {
+			return fmt.Errorf("failed to marshal review diff: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO review_actions (id, review_id, reviewer_id, action, comment, diff, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		action.ID,
+		action.ReviewID,
+		action.ReviewerID,
+		action.Action,
+		action.Comment,
+		diff,
+		action.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record review action: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AssessmentReviewRepo) ListActions(ctx context.Context, reviewID uuid.UUID) ([]*domain.ReviewAction, error) {
+	query := `
+		SELECT id, review_id, reviewer_id, action, comment, diff, created_at
+		FROM review_actions
+		WHERE review_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review actions: %w", err)
+	}
+	defer rows.Close()
+
+	actions := []*domain.ReviewAction{}
+	for rows.Next() {
+		var action domain.ReviewAction
+		var diff []byte
+		err := rows.Scan(
+			&action.ID,
+			&action.ReviewID,
+			&action.ReviewerID,
+			&action.Action,
+			&action.Comment,
+			&diff,
+			&action.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review action: %w", err)
+		}
+		if len(diff) > 0 {
+			action.Diff = &domain.ReviewDiff{}
+			if err := json.Unmarshal(diff, action.Diff); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal review diff: %w", err)
+			}
+		}
+		actions = append(actions, &action)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review actions: %w", err)
+	}
+
+	return actions, nil
+}
+
+func (r *AssessmentReviewRepo) SaveOfflineResult(ctx context.Context, assessmentID uuid.UUID, classifications []string, at time.Time) error {
+	query := `
+		INSERT INTO offline_results (assessment_id, classifications, reported_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (assessment_id) DO UPDATE
+		SET classifications = EXCLUDED.classifications, reported_at = EXCLUDED.reported_at
+	`
+
+	if _, err := r.db.Exec(ctx, query, assessmentID, classifications, at); err != nil {
+		return fmt.Errorf("failed to save offline result: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AssessmentReviewRepo) GetOfflineResult(ctx context.Context, assessmentID uuid.UUID) ([]string, error) {
+	query := `SELECT classifications FROM offline_results WHERE assessment_id = $1`
+
+	var classifications []string
+	if err := r.db.QueryRow(ctx, query, assessmentID).Scan(&classifications); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get offline result: %w", err)
+	}
+
+	return classifications, nil
+}
+
+func (r *AssessmentReviewRepo) SetTrainee(ctx context.Context, medicalProfessionalID uuid.UUID, isTrainee bool) error {
+	query := `UPDATE medical_professionals SET is_trainee = $2, updated_at = $3 WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, medicalProfessionalID, isTrainee, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update trainee flag: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepo(db *pgxpool.Pool) domain.AuditRepository {
+	return &AuditRepo{db: db}
+}
+
+// auditChainLock serialises appends so two entries never link to the same
+// predecessor.
+const auditChainLock = 7100301
+
+const auditColumns = `
+	seq, id, actor_id, actor_role, action, entity_type, entity_id,
+	before_data::text, after_data::text, ip, user_agent, method, path, request_id,
+	created_at, prev_hash, hash
+`
+
+func (r *AuditRepo) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := appendAuditEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// appendAuditEntry links entry to the head of the chain and inserts it in
+// tx, so a change and its audit entry are committed together.
+func appendAuditEntry(ctx context.Context, tx pgx.Tx, entry *domain.AuditEntry) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+
+	prevHash := domain.AuditGenesisHash
+	err := tx.QueryRow(ctx, `SELECT hash FROM audit_log ORDER BY seq DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	entry.PrevHash = prevHash
+	entry.Hash = domain.AuditHash(prevHash, entry)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO audit_log (
+			id, actor_id, actor_role, action, entity_type, entity_id, before_data, after_data,
+			ip, user_agent, method, path, request_id, created_at, prev_hash, hash
+		) VALUES ($1, $2, $3, $4, $5, $6, $7::json, $8::json, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING seq
+	`,
+		entry.ID,
+		entry.ActorID,
+		entry.ActorRole,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		nullableJSON(entry.Before),
+		nullableJSON(entry.After),
+		entry.IP,
+		entry.UserAgent,
+		entry.Method,
+		entry.Path,
+		entry.RequestID,
+		entry.CreatedAt,
+		entry.PrevHash,
+		entry.Hash,
+	).Scan(&entry.Seq)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepo) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE ($1::text = '' OR entity_type = $1::text)
+		  AND ($2::uuid IS NULL OR entity_id = $2)
+		  AND ($3::uuid IS NULL OR actor_id = $3)
+		  AND ($4::text = '' OR action = $4::text)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
+		  AND ($7::bigint = 0 OR seq < $7)
+		ORDER BY seq DESC
+		LIMIT $8
+	`
+
+	return r.query(ctx, query,
+		string(filter.EntityType),
+		filter.EntityID,
+		filter.ActorID,
+		string(filter.Action),
+		filter.From,
+		filter.To,
+		filter.BeforeSeq,
+		filter.Limit,
+	)
+}
+
+func (r *AuditRepo) Chain(ctx context.Context, afterSeq int64, limit int) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE seq > $1
+		ORDER BY seq
+		LIMIT $2
+	`
+
+	return r.query(ctx, query, afterSeq, limit)
+}
+
+func (r *AuditRepo) query(ctx context.Context, query string, args ...any) ([]*domain.AuditEntry, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.AuditEntry{}
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var before, after *string
+		err := rows.Scan(
+			&entry.Seq,
+			&entry.ID,
+			&entry.ActorID,
+			&entry.ActorRole,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&before,
+			&after,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.Method,
+			&entry.Path,
+			&entry.RequestID,
+			&entry.CreatedAt,
+			&entry.PrevHash,
+			&entry.Hash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if before != nil {
+			entry.Before = json.RawMessage(*before)
+		}
+		if after != nil {
+			entry.After = json.RawMessage(*after)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// nullableJSON stores an empty snapshot as NULL.
+func nullableJSON(raw json.RawMessage) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
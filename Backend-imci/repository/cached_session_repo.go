@@ -0,0 +1,110 @@
+// repository/cached_session_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// sessionStatusTTL bounds how long a session's status is remembered.
+// Revoking through CachedRefreshSessionRepo drops it at once.
+const sessionStatusTTL = 5 * time.Minute
+
+// CachedRefreshSessionRepo remembers in Redis whether each session is
+// active, so the auth middleware does not query the database on every
+// request. Without a Redis client every check reads the database.
+type CachedRefreshSessionRepo struct {
+	domain.RefreshSessionRepository
+	client *redis.Client
+}
+
+func NewCachedRefreshSessionRepo(sessions domain.RefreshSessionRepository, client *redis.Client) *CachedRefreshSessionRepo {
+	return &CachedRefreshSessionRepo{RefreshSessionRepository: sessions, client: client}
+}
+
+func sessionStatusKey(id uuid.UUID) string {
+	return "session:active:" + id.String()
+}
+
+func (r *CachedRefreshSessionRepo) IsActive(ctx context.Context, id uuid.UUID) (bool, error) {
+	if r.client != nil {
+		status, err := r.client.Get(ctx, sessionStatusKey(id)).Result()
+		if err == nil {
+			return status == "1", nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return false, fmt.Errorf("failed to read session status: %w", err)
+		}
+	}
+
+	active := false
+	ttl := sessionStatusTTL
+	session, err := r.RefreshSessionRepository.GetByID(ctx, id)
+	switch {
+	case errors.Is(err, domain.ErrSessionNotFound):
+	case err != nil:
+		return false, err
+	case session.RevokedAt == nil && time.Now().Before(session.ExpiresAt):
+		active = true
+		if remaining := time.Until(session.ExpiresAt); remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	if r.client != nil {
+		status := "0"
+		if active {
+			status = "1"
+		}
+		if err := r.client.Set(ctx, sessionStatusKey(id), status, ttl).Err(); err != nil {
+			return false, fmt.Errorf("failed to cache session status: %w", err)
+		}
+	}
+	return active, nil
+}
+
+func (r *CachedRefreshSessionRepo) Revoke(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID, reason string) error {
+	if err := r.RefreshSessionRepository.Revoke(ctx, id, medicalProfessionalID, reason); err != nil {
+		return err
+	}
+	return r.forget(ctx, id)
+}
+
+func (r *CachedRefreshSessionRepo) RevokeAll(ctx context.Context, medicalProfessionalID uuid.UUID, except *uuid.UUID, reason string) error {
+	sessions, err := r.RefreshSessionRepository.ListActive(ctx, medicalProfessionalID)
+	if err != nil {
+		return err
+	}
+	if err := r.RefreshSessionRepository.RevokeAll(ctx, medicalProfessionalID, except, reason); err != nil {
+		return err
+	}
+
+	ids := []uuid.UUID{}
+	for _, session := range sessions {
+		if except == nil || session.ID != *except {
+			ids = append(ids, session.ID)
+		}
+	}
+	return r.forget(ctx, ids...)
+}
+
+// forget drops the cached status of revoked sessions.
+func (r *CachedRefreshSessionRepo) forget(ctx context.Context, ids ...uuid.UUID) error {
+	if r.client == nil || len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = sessionStatusKey(id)
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to clear session status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// repository/chat_session_store.go
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisChatSessionStore struct {
+	client *redis.Client
+}
+
+func NewRedisChatSessionStore(client *redis.Client) domain.ChatSessionStore {
+	return &RedisChatSessionStore{client: client}
+}
+
+func chatSessionKey(conversationID string) string {
+	return "chat:session:" + conversationID
+}
+
+func (r *RedisChatSessionStore) Get(ctx context.Context, conversationID string) (*domain.ChatSession, error) {
+	raw, err := r.client.Get(ctx, chatSessionKey(conversationID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrChatSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat session: %w", err)
+	}
+
+	var session domain.ChatSession
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode chat session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *RedisChatSessionStore) Save(ctx context.Context, conversationID string, session *domain.ChatSession, ttl time.Duration) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode chat session: %w", err)
+	}
+	if err := r.client.Set(ctx, chatSessionKey(conversationID), raw, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save chat session: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisChatSessionStore) Delete(ctx context.Context, conversationID string) error {
+	if err := r.client.Del(ctx, chatSessionKey(conversationID)).Err(); err != nil {
+		return fmt.Errorf("failed to delete chat session: %w", err)
+	}
+	return nil
+}
+
+type memoryChatSession struct {
+	session   domain.ChatSession
+	expiresAt time.Time
+}
+
+type MemoryChatSessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]memoryChatSession
+}
+
+// NewMemoryChatSessionStore keeps sessions in process memory. It is used when
+// Redis is not configured; sessions are lost on restart.
+func NewMemoryChatSessionStore() domain.ChatSessionStore {
+	return &MemoryChatSessionStore{sessions: make(map[string]memoryChatSession)}
+}
+
+func (m *MemoryChatSessionStore) Get(ctx context.Context, conversationID string) (*domain.ChatSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.sessions[conversationID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(m.sessions, conversationID)
+		return nil, domain.ErrChatSessionNotFound
+	}
+	session := entry.session
+	session.Choices = append([]string(nil), entry.session.Choices...)
+	return &session, nil
+}
+
+func (m *MemoryChatSessionStore) Save(ctx context.Context, conversationID string, session *domain.ChatSession, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *session
+	stored.Choices = append([]string(nil), session.Choices...)
+	m.sessions[conversationID] = memoryChatSession{session: stored, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (m *MemoryChatSessionStore) Delete(ctx context.Context, conversationID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, conversationID)
+	return nil
+}
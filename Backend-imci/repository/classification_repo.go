@@ -25,8 +25,8 @@ func (r *ClassificationRepo) Create(ctx context.Context, classification *domain.
 		INSERT INTO classifications (
 			id, assessment_id, disease, color, details, rule_version,
 			confidence_score, is_critical_illness, requires_urgent_referral,
-			treatment_priority, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			treatment_priority, follow_up_date, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
 	classification.CreatedAt = time.Now()
@@ -42,6 +42,7 @@ func (r *ClassificationRepo) Create(ctx context.Context, classification *domain.
 		classification.IsCriticalIllness,
 		classification.RequiresUrgentReferral,
 		classification.TreatmentPriority,
+		classification.FollowUpDate,
 		classification.CreatedAt,
 	)
 
@@ -56,7 +57,7 @@ func (r *ClassificationRepo) GetByAssessmentID(ctx context.Context, assessmentID
 	query := `
 		SELECT id, assessment_id, disease, color, details, rule_version,
 			confidence_score, is_critical_illness, requires_urgent_referral,
-			treatment_priority, created_at
+			treatment_priority, follow_up_date, created_at
 		FROM classifications 
 		WHERE assessment_id = $1
 	`
@@ -73,6 +74,7 @@ func (r *ClassificationRepo) GetByAssessmentID(ctx context.Context, assessmentID
 		&classification.IsCriticalIllness,
 		&classification.RequiresUrgentReferral,
 		&classification.TreatmentPriority,
+		&classification.FollowUpDate,
 		&classification.CreatedAt,
 	)
 
@@ -86,6 +88,54 @@ func (r *ClassificationRepo) GetByAssessmentID(ctx context.Context, assessmentID
 	return &classification, nil
 }
 
+// ListByAssessmentID returns every classification of an assessment, most
+// urgent first.
+func (r *ClassificationRepo) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Classification, error) {
+	query := `
+		SELECT id, assessment_id, disease, color, details, rule_version,
+			confidence_score, is_critical_illness, requires_urgent_referral,
+			treatment_priority, follow_up_date, created_at
+		FROM classifications
+		WHERE assessment_id = $1
+		ORDER BY treatment_priority, created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query classifications: %w", err)
+	}
+	defer rows.Close()
+
+	classifications := []*domain.Classification{}
+	for rows.Next() {
+		var classification domain.Classification
+		err := rows.Scan(
+			&classification.ID,
+			&classification.AssessmentID,
+			&classification.Disease,
+			&classification.Color,
+			&classification.Details,
+			&classification.RuleVersion,
+			&classification.ConfidenceScore,
+			&classification.IsCriticalIllness,
+			&classification.RequiresUrgentReferral,
+			&classification.TreatmentPriority,
+			&classification.FollowUpDate,
+			&classification.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan classification: %w", err)
+		}
+		classifications = append(classifications, &classification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating classifications: %w", err)
+	}
+
+	return classifications, nil
+}
+
 // repository/classification_repo.go - Fix the Upsert method
 func (r *ClassificationRepo) Upsert(ctx context.Context, classification *domain.Classification) error {
     // First try to update if exists
@@ -94,8 +144,8 @@ func (r *ClassificationRepo) Upsert(ctx context.Context, classification *domain.
         SET disease = $1, color = $2, details = $3, rule_version = $4,
             confidence_score = $5, is_critical_illness = $6, 
             requires_urgent_referral = $7, treatment_priority = $8,
-            created_at = $9
-        WHERE assessment_id = $10
+            follow_up_date = $9, created_at = $10
+        WHERE assessment_id = $11
     `
 
     result, err := r.db.Exec(ctx, query,
@@ -107,6 +157,7 @@ func (r *ClassificationRepo) Upsert(ctx context.Context, classification *domain.
         classification.IsCriticalIllness,
         classification.RequiresUrgentReferral,
         classification.TreatmentPriority,
+        classification.FollowUpDate,
         classification.CreatedAt,
         classification.AssessmentID,
     )
@@ -128,8 +179,8 @@ func (r *ClassificationRepo) Update(ctx context.Context, classification *domain.
 		SET disease = $1, color = $2, details = $3, rule_version = $4,
 			confidence_score = $5, is_critical_illness = $6, 
 			requires_urgent_referral = $7, treatment_priority = $8,
-			created_at = $9
-		WHERE assessment_id = $10
+			follow_up_date = $9, created_at = $10
+		WHERE assessment_id = $11
 	`
 
 	_, err := r.db.Exec(ctx, query,
@@ -141,6 +192,7 @@ func (r *ClassificationRepo) Update(ctx context.Context, classification *domain.
 		classification.IsCriticalIllness,
 		classification.RequiresUrgentReferral,
 		classification.TreatmentPriority,
+		classification.FollowUpDate,
 		classification.CreatedAt,
 		classification.AssessmentID,
 	)
@@ -4,6 +4,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
@@ -20,51 +21,84 @@ func NewClinicalFindingsRepo(db *pgxpool.Pool) domain.ClinicalFindingsRepository
 	return &ClinicalFindingsRepo{db: db}
 }
 
+// clinicalFindingsColumns lists every persisted column after id and
+// assessment_id, in the same order as findingsFields.
+var clinicalFindingsColumns = []string{
+	"unable_to_drink", "vomits_everything", "had_convulsions", "lethargic_unconscious", "convulsing_now",
+	"fast_breathing", "chest_indrawing", "stridor", "wheezing", "oxygen_saturation",
+	"respiratory_rate", "cough_present", "cough_duration_days",
+	"diarrhea_present", "diarrhea_duration_days", "blood_in_stool",
+	"sunken_eyes", "skin_pinch_slow", "skin_pinch_very_slow", "restless_irritable",
+	"drinking_eagerly", "drinking_poorly",
+	"fever_present", "fever_duration_days", "stiff_neck", "bulging_fontanelle",
+	"runny_nose", "red_eyes", "generalized_rash",
+	"measles_now", "measles_last_3_months",
+	"ear_pain", "ear_discharge", "ear_discharge_duration_days", "tender_swelling_behind_ear",
+	"muac", "bilateral_edema", "visible_severe_wasting", "weight_for_height_z_score",
+	"severe_palmar_pallor", "some_palmar_pallor", "hb_level", "hct_level",
+	"palms_soles_yellow", "skin_eyes_yellow", "jaundice_age_hours",
+	"unable_to_feed", "not_feeding_well", "movement_only_when_stimulated", "no_movement",
+	"umbilicus_red", "umbilicus_draining_pus", "skin_pustules",
+	"low_body_temperature", "body_temperature",
+	"hiv_exposed", "hiv_status_known", "tb_cough_duration_days", "tb_contact_history",
+	"tb_weight_loss", "night_sweats",
+	"suspected_developmental_delay", "milestones_absent", "risk_factors_present",
+	"breastfeeding", "breastfeeding_frequency", "complementary_foods", "feeding_problem", "underweight",
+	"other_findings",
+}
+
+// findingsFields returns pointers to the ClinicalFindings fields matching
+// clinicalFindingsColumns, usable both as Exec arguments and Scan targets.
+func findingsFields(f *domain.ClinicalFindings) []interface{} {
+	return []interface{}{
+		&f.UnableToDrink, &f.VomitsEverything, &f.HadConvulsions, &f.LethargicUnconscious, &f.ConvulsingNow,
+		&f.FastBreathing, &f.ChestIndrawing, &f.Stridor, &f.Wheezing, &f.OxygenSaturation,
+		&f.RespiratoryRate, &f.CoughPresent, &f.CoughDurationDays,
+		&f.DiarrheaPresent, &f.DiarrheaDurationDays, &f.BloodInStool,
+		&f.SunkenEyes, &f.SkinPinchSlow, &f.SkinPinchVerySlow, &f.RestlessIrritable,
+		&f.DrinkingEagerly, &f.DrinkingPoorly,
+		&f.FeverPresent, &f.FeverDurationDays, &f.StiffNeck, &f.BulgingFontanelle,
+		&f.RunnyNose, &f.RedEyes, &f.GeneralizedRash,
+		&f.MeaslesNow, &f.MeaslesLast3Months,
+		&f.EarPain, &f.EarDischarge, &f.EarDischargeDurationDays, &f.TenderSwellingBehindEar,
+		&f.MUAC, &f.BilateralEdema, &f.VisibleSevereWasting, &f.WeightForHeightZScore,
+		&f.SeverePalmarPallor, &f.SomePalmarPallor, &f.HbLevel, &f.HctLevel,
+		&f.PalmsSolesYellow, &f.SkinEyesYellow, &f.JaundiceAgeHours,
+		&f.UnableToFeed, &f.NotFeedingWell, &f.MovementOnlyWhenStimulated, &f.NoMovement,
+		&f.UmbilicusRed, &f.UmbilicusDrainingPus, &f.SkinPustules,
+		&f.LowBodyTemperature, &f.BodyTemperature,
+		&f.HIVExposed, &f.HIVStatusKnown, &f.TBCoughDurationDays, &f.TBContactHistory,
+		&f.TBWeightLoss, &f.NightSweats,
+		&f.SuspectedDevelopmentalDelay, &f.MilestonesAbsent, &f.RiskFactorsPresent,
+		&f.Breastfeeding, &f.BreastfeedingFrequency, &f.ComplementaryFoods, &f.FeedingProblem, &f.Underweight,
+		&f.OtherFindings,
+	}
+}
+
 func (r *ClinicalFindingsRepo) Create(ctx context.Context, findings *domain.ClinicalFindings) error {
-	query := `
-		INSERT INTO clinical_findings (
-			id, assessment_id, unable_to_drink, vomits_everything, had_convulsions,
-			lethargic_unconscious, convulsing_now, fast_breathing, chest_indrawing,
-			stridor, wheezing, oxygen_saturation, palms_soles_yellow, skin_eyes_yellow,
-			jaundice_age_hours, diarrhea_duration_days, blood_in_stool, fever_duration_days,
-			measles_now, measles_last_3_months, stiff_neck, bulging_fontanelle, muac,
-			bilateral_edema, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26)
-	`
+	columns := append([]string{"id", "assessment_id"}, clinicalFindingsColumns...)
+	columns = append(columns, "created_at", "updated_at")
+
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO clinical_findings (%s) VALUES (%s)`,
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
 
 	now := time.Now()
 	findings.CreatedAt = now
 	findings.UpdatedAt = now
 
-	_, err := r.db.Exec(ctx, query,
-		findings.ID,
-		findings.AssessmentID,
-		findings.UnableToDrink,
-		findings.VomitsEverything,
-		findings.HadConvulsions,
-		findings.LethargicUnconscious,
-		findings.ConvulsingNow,
-		findings.FastBreathing,
-		findings.ChestIndrawing,
-		findings.Stridor,
-		findings.Wheezing,
-		findings.OxygenSaturation,
-		findings.PalmsSolesYellow,
-		findings.SkinEyesYellow,
-		findings.JaundiceAgeHours,
-		findings.DiarrheaDurationDays,
-		findings.BloodInStool,
-		findings.FeverDurationDays,
-		findings.MeaslesNow,
-		findings.MeaslesLast3Months,
-		findings.StiffNeck,
-		findings.BulgingFontanelle,
-		findings.MUAC,
-		findings.BilateralEdema,
-		findings.CreatedAt,
-		findings.UpdatedAt,
-	)
+	args := []interface{}{findings.ID, findings.AssessmentID}
+	args = append(args, findingsFields(findings)...)
+	args = append(args, findings.CreatedAt, findings.UpdatedAt)
 
+	_, err := r.db.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to create clinical findings: %w", err)
 	}
@@ -73,50 +107,22 @@ func (r *ClinicalFindingsRepo) Create(ctx context.Context, findings *domain.Clin
 }
 
 func (r *ClinicalFindingsRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*domain.ClinicalFindings, error) {
-	query := `
-		SELECT id, assessment_id, unable_to_drink, vomits_everything, had_convulsions,
-			lethargic_unconscious, convulsing_now, fast_breathing, chest_indrawing,
-			stridor, wheezing, oxygen_saturation, palms_soles_yellow, skin_eyes_yellow,
-			jaundice_age_hours, diarrhea_duration_days, blood_in_stool, fever_duration_days,
-			measles_now, measles_last_3_months, stiff_neck, bulging_fontanelle, muac,
-			bilateral_edema, created_at, updated_at
+	query := fmt.Sprintf(
+		`SELECT id, assessment_id, %s, created_at, updated_at
 		FROM clinical_findings 
-		WHERE assessment_id = $1
-	`
+		WHERE assessment_id = $1`,
+		strings.Join(clinicalFindingsColumns, ", "),
+	)
 
 	var findings domain.ClinicalFindings
-	err := r.db.QueryRow(ctx, query, assessmentID).Scan(
-		&findings.ID,
-		&findings.AssessmentID,
-		&findings.UnableToDrink,
-		&findings.VomitsEverything,
-		&findings.HadConvulsions,
-		&findings.LethargicUnconscious,
-		&findings.ConvulsingNow,
-		&findings.FastBreathing,
-		&findings.ChestIndrawing,
-		&findings.Stridor,
-		&findings.Wheezing,
-		&findings.OxygenSaturation,
-		&findings.PalmsSolesYellow,
-		&findings.SkinEyesYellow,
-		&findings.JaundiceAgeHours,
-		&findings.DiarrheaDurationDays,
-		&findings.BloodInStool,
-		&findings.FeverDurationDays,
-		&findings.MeaslesNow,
-		&findings.MeaslesLast3Months,
-		&findings.StiffNeck,
-		&findings.BulgingFontanelle,
-		&findings.MUAC,
-		&findings.BilateralEdema,
-		&findings.CreatedAt,
-		&findings.UpdatedAt,
-	)
+	dest := []interface{}{&findings.ID, &findings.AssessmentID}
+	dest = append(dest, findingsFields(&findings)...)
+	dest = append(dest, &findings.CreatedAt, &findings.UpdatedAt)
 
+	err := r.db.QueryRow(ctx, query, assessmentID).Scan(dest...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("clinical findings not found for assessment: %w", err)
+			return nil, fmt.Errorf("clinical findings not found for assessment: %w", domain.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get clinical findings: %w", err)
 	}
@@ -137,50 +143,28 @@ func (r *ClinicalFindingsRepo) Upsert(ctx context.Context, findings *domain.Clin
 }
 
 func (r *ClinicalFindingsRepo) Update(ctx context.Context, findings *domain.ClinicalFindings) error {
-	query := `
-		UPDATE clinical_findings 
-		SET unable_to_drink = $1, vomits_everything = $2, had_convulsions = $3,
-			lethargic_unconscious = $4, convulsing_now = $5, fast_breathing = $6,
-			chest_indrawing = $7, stridor = $8, wheezing = $9, oxygen_saturation = $10,
-			palms_soles_yellow = $11, skin_eyes_yellow = $12, jaundice_age_hours = $13,
-			diarrhea_duration_days = $14, blood_in_stool = $15, fever_duration_days = $16,
-			measles_now = $17, measles_last_3_months = $18, stiff_neck = $19,
-			bulging_fontanelle = $20, muac = $21, bilateral_edema = $22, updated_at = $23
-		WHERE id = $24
-	`
+	assignments := make([]string, len(clinicalFindingsColumns))
+	for i, column := range clinicalFindingsColumns {
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+1)
+	}
+	n := len(clinicalFindingsColumns)
+
+	query := fmt.Sprintf(
+		`UPDATE clinical_findings 
+		SET %s, updated_at = $%d
+		WHERE id = $%d`,
+		strings.Join(assignments, ", "), n+1, n+2,
+	)
 
 	findings.UpdatedAt = time.Now()
 
-	_, err := r.db.Exec(ctx, query,
-		findings.UnableToDrink,
-		findings.VomitsEverything,
-		findings.HadConvulsions,
-		findings.LethargicUnconscious,
-		findings.ConvulsingNow,
-		findings.FastBreathing,
-		findings.ChestIndrawing,
-		findings.Stridor,
-		findings.Wheezing,
-		findings.OxygenSaturation,
-		findings.PalmsSolesYellow,
-		findings.SkinEyesYellow,
-		findings.JaundiceAgeHours,
-		findings.DiarrheaDurationDays,
-		findings.BloodInStool,
-		findings.FeverDurationDays,
-		findings.MeaslesNow,
-		findings.MeaslesLast3Months,
-		findings.StiffNeck,
-		findings.BulgingFontanelle,
-		findings.MUAC,
-		findings.BilateralEdema,
-		findings.UpdatedAt,
-		findings.ID,
-	)
+	args := findingsFields(findings)
+	args = append(args, findings.UpdatedAt, findings.ID)
 
+	_, err := r.db.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update clinical findings: %w", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ConsistencyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewConsistencyRepo(db *pgxpool.Pool) domain.ConsistencyRepository {
+	return &ConsistencyRepo{db: db}
+}
+
+func (r *ConsistencyRepo) SaveTreeAnswers(ctx context.Context, snapshot *domain.TreeAnswers) error {
+	query := `
+		INSERT INTO tree_answers (assessment_id, tree_id, answers, completed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (assessment_id, tree_id) DO UPDATE
+		SET answers = EXCLUDED.answers, completed_at = EXCLUDED.completed_at
+	`
+
+	answersJSON, err := json.Marshal(snapshot.Answers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tree answers: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, query, snapshot.AssessmentID, snapshot.TreeID, answersJSON, snapshot.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save tree answers: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ConsistencyRepo) ListTreeAnswers(ctx context.Context, assessmentID uuid.UUID) ([]*domain.TreeAnswers, error) {
+	query := `
+		SELECT assessment_id, tree_id, answers, completed_at
+		FROM tree_answers
+		WHERE assessment_id = $1
+		ORDER BY completed_at
+	`
+
+	rows, err := r.db.Query(ctx, query, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tree answers: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []*domain.TreeAnswers{}
+	for rows.Next() {
+		var snapshot domain.TreeAnswers
+		var answersData []byte
+		if err := rows.Scan(&snapshot.AssessmentID, &snapshot.TreeID, &answersData, &snapshot.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tree answers: %w", err)
+		}
+		if err := json.Unmarshal(answersData, &snapshot.Answers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tree answers: %w", err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tree answers: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+func (r *ConsistencyRepo) SaveFinding(ctx context.Context, finding *domain.ConsistencyFinding) (bool, error) {
+	query := `
+		INSERT INTO consistency_findings (
+			id, assessment_id, kind, code, tree_ids, detail, classification_id,
+			from_disease, to_disease, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)
+		ON CONFLICT DO NOTHING
+	`
+
+	if finding.ID == uuid.Nil {
+		finding.ID = uuid.New()
+	}
+
+	result, err := r.db.Exec(ctx, query,
+		finding.ID,
+		finding.AssessmentID,
+		finding.Kind,
+		finding.Code,
+		finding.TreeIDs,
+		finding.Detail,
+		finding.ClassificationID,
+		finding.FromDisease,
+		finding.ToDisease,
+		finding.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to save consistency finding: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *ConsistencyRepo) ListFindings(ctx context.Context, assessmentID uuid.UUID) ([]*domain.ConsistencyFinding, error) {
+	query := `
+		SELECT id, assessment_id, kind, code, tree_ids, detail, classification_id,
+			COALESCE(from_disease, ''), COALESCE(to_disease, ''), created_at
+		FROM consistency_findings
+		WHERE assessment_id = $1
+		ORDER BY created_at, code
+	`
+
+	rows, err := r.db.Query(ctx, query, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consistency findings: %w", err)
+	}
+	defer rows.Close()
+
+	findings := []*domain.ConsistencyFinding{}
+	for rows.Next() {
+		var finding domain.ConsistencyFinding
+		err := rows.Scan(
+			&finding.ID,
+			&finding.AssessmentID,
+			&finding.Kind,
+			&finding.Code,
+			&finding.TreeIDs,
+			&finding.Detail,
+			&finding.ClassificationID,
+			&finding.FromDisease,
+			&finding.ToDisease,
+			&finding.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan consistency finding: %w", err)
+		}
+		findings = append(findings, &finding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating consistency findings: %w", err)
+	}
+
+	return findings, nil
+}
+
+func (r *ConsistencyRepo) ReplaceCare(ctx context.Context, classification *domain.Classification, plans []*domain.TreatmentPlan, counselings []*domain.Counseling, audit []*domain.AuditEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE classifications
+		SET disease = $1, color = $2, details = $3, rule_version = $4,
+			confidence_score = $5, is_critical_illness = $6,
+			requires_urgent_referral = $7, treatment_priority = $8,
+			follow_up_date = $9
+		WHERE id = $10 AND assessment_id = $11
+	`,
+		classification.Disease,
+		classification.Color,
+		classification.Details,
+		classification.RuleVersion,
+		classification.ConfidenceScore,
+		classification.IsCriticalIllness,
+		classification.RequiresUrgentReferral,
+		classification.TreatmentPriority,
+		classification.FollowUpDate,
+		classification.ID,
+		classification.AssessmentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update classification: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("failed to update classification: %w", pgx.ErrNoRows)
+	}
+
+	deleted, err := deleteTreatmentPlans(ctx, tx, classification.ID)
+	if err != nil {
+		return err
+	}
+	for _, plan := range deleted {
+		entry, err := domain.NewAuditEntry(ctx, domain.AuditDelete, domain.AuditTreatmentPlan, plan.ID, plan, nil)
+		if err != nil {
+			return err
+		}
+		audit = append(audit, entry)
+	}
+
+	deletedCounselings, err := deleteCounselings(ctx, tx, classification.ID)
+	if err != nil {
+		return err
+	}
+	for _, counseling := range deletedCounselings {
+		entry, err := domain.NewAuditEntry(ctx, domain.AuditDelete, domain.AuditCounseling, counseling.ID, counseling, nil)
+		if err != nil {
+			return err
+		}
+		audit = append(audit, entry)
+	}
+
+	now := time.Now()
+	for _, plan := range plans {
+		plan.CreatedAt, plan.UpdatedAt = now, now
+		if err := createTreatmentPlan(ctx, tx, plan); err != nil {
+			return err
+		}
+	}
+	for _, counseling := range counselings {
+		counseling.CreatedAt = now
+		if err := createCounseling(ctx, tx, counseling); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range audit {
+		if err := appendAuditEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func deleteTreatmentPlans(ctx context.Context, tx pgx.Tx, classificationID uuid.UUID) ([]*domain.TreatmentPlan, error) {
+	rows, err := tx.Query(ctx, `
+		DELETE FROM treatment_plans
+		WHERE classification_id = $1
+		RETURNING id, assessment_id, classification_id, drug_name, dosage, frequency,
+			duration, administration_route, is_pre_referral, instructions,
+			created_at, updated_at
+	`, classificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete treatment plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []*domain.TreatmentPlan{}
+	for rows.Next() {
+		var plan domain.TreatmentPlan
+		err := rows.Scan(
+			&plan.ID,
+			&plan.AssessmentID,
+			&plan.ClassificationID,
+			&plan.DrugName,
+			&plan.Dosage,
+			&plan.Frequency,
+			&plan.Duration,
+			&plan.AdministrationRoute,
+			&plan.IsPreReferral,
+			&plan.Instructions,
+			&plan.CreatedAt,
+			&plan.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deleted treatment plan: %w", err)
+		}
+		plans = append(plans, &plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to delete treatment plans: %w", err)
+	}
+	return plans, nil
+}
+
+func deleteCounselings(ctx context.Context, tx pgx.Tx, classificationID uuid.UUID) ([]*domain.Counseling, error) {
+	rows, err := tx.Query(ctx, `
+		DELETE FROM counselings
+		WHERE classification_id = $1
+		RETURNING `+counselingColumns, classificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete counselings: %w", err)
+	}
+	defer rows.Close()
+
+	counselings := []*domain.Counseling{}
+	for rows.Next() {
+		counseling, err := scanCounseling(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deleted counseling: %w", err)
+		}
+		counselings = append(counselings, counseling)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to delete counselings: %w", err)
+	}
+	return counselings, nil
+}
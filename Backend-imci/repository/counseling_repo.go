@@ -3,11 +3,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,38 +21,54 @@ func NewCounselingRepo(db *pgxpool.Pool) domain.CounselingRepository {
 	return &CounselingRepo{db: db}
 }
 
-func (r *CounselingRepo) Create(ctx context.Context, counseling *domain.Counseling) error {
-	query := `
-		INSERT INTO counselings (
-			id, assessment_id, advice_type, details, language, 
-			understood_by_caregiver, questions_asked, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
+const counselingColumns = `id, assessment_id, classification_id, advice_type, details, language, 
+			understood_by_caregiver, questions_asked, understood_at, counseled_by, created_at`
 
-	counseling.CreatedAt = time.Now()
+const insertCounselingQuery = `
+	INSERT INTO counselings (
+		` + counselingColumns + `
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (classification_id, advice_type) WHERE classification_id IS NOT NULL DO NOTHING
+`
 
-	_, err := r.db.Exec(ctx, query,
+func counselingArgs(counseling *domain.Counseling) []interface{} {
+	return []interface{}{
 		counseling.ID,
 		counseling.AssessmentID,
+		counseling.ClassificationID,
 		counseling.AdviceType,
 		counseling.Details,
 		counseling.Language,
 		counseling.UnderstoodByCaregiver,
 		counseling.QuestionsAsked,
+		counseling.UnderstoodAt,
+		counseling.CounseledBy,
 		counseling.CreatedAt,
-	)
+	}
+}
 
-	if err != nil {
+func (r *CounselingRepo) Create(ctx context.Context, counseling *domain.Counseling) error {
+	counseling.CreatedAt = time.Now()
+
+	if _, err := r.db.Exec(ctx, insertCounselingQuery, counselingArgs(counseling)...); err != nil {
 		return fmt.Errorf("failed to create counseling: %w", err)
 	}
 
 	return nil
 }
 
+// createCounseling writes counseling in tx, for changes that replace a
+// classification's advice along with the classification.
+func createCounseling(ctx context.Context, tx pgx.Tx, counseling *domain.Counseling) error {
+	if _, err := tx.Exec(ctx, insertCounselingQuery, counselingArgs(counseling)...); err != nil {
+		return fmt.Errorf("failed to create counseling: %w", err)
+	}
+	return nil
+}
+
 func (r *CounselingRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Counseling, error) {
 	query := `
-		SELECT id, assessment_id, advice_type, details, language, 
-			understood_by_caregiver, questions_asked, created_at
+		SELECT ` + counselingColumns + `
 		FROM counselings 
 		WHERE assessment_id = $1
 		ORDER BY created_at
@@ -64,37 +82,164 @@ func (r *CounselingRepo) GetByAssessmentID(ctx context.Context, assessmentID uui
 
 	var counselings []*domain.Counseling
 	for rows.Next() {
-		var counseling domain.Counseling
-		var understoodByCaregiver, questionsAsked *string
-		
-		err := rows.Scan(
-			&counseling.ID,
-			&counseling.AssessmentID,
-			&counseling.AdviceType,
-			&counseling.Details,
-			&counseling.Language,
-			&understoodByCaregiver,
-			&questionsAsked,
-			&counseling.CreatedAt,
-		)
+		counseling, err := scanCounseling(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan counseling: %w", err)
 		}
+		counselings = append(counselings, counseling)
+	}
 
-		// Handle nullable fields
-		if understoodByCaregiver != nil {
-			counseling.UnderstoodByCaregiver = understoodByCaregiver
-		}
-		if questionsAsked != nil {
-			counseling.QuestionsAsked = questionsAsked
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating counselings: %w", err)
+	}
+
+	return counselings, nil
+}
+
+func (r *CounselingRepo) GetByID(ctx context.Context, id uuid.UUID, assessmentID uuid.UUID) (*domain.Counseling, error) {
+	query := `
+		SELECT ` + counselingColumns + `
+		FROM counselings 
+		WHERE id = $1 AND assessment_id = $2
+	`
+
+	counseling, err := scanCounseling(r.db.QueryRow(ctx, query, id, assessmentID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrCounselingNotFound
 		}
+		return nil, fmt.Errorf("failed to get counseling: %w", err)
+	}
+
+	return counseling, nil
+}
+
+func (r *CounselingRepo) ConfirmUnderstanding(ctx context.Context, counseling *domain.Counseling) error {
+	query := `
+		UPDATE counselings
+		SET understood_by_caregiver = $3, understood_at = $4, counseled_by = $5
+		WHERE id = $1 AND assessment_id = $2
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		counseling.ID,
+		counseling.AssessmentID,
+		counseling.UnderstoodByCaregiver,
+		counseling.UnderstoodAt,
+		counseling.CounseledBy,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to confirm counseling: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrCounselingNotFound
+	}
+
+	return nil
+}
+
+// AddQuestion appends a caregiver question on its own line.
+func (r *CounselingRepo) AddQuestion(ctx context.Context, id uuid.UUID, assessmentID uuid.UUID, question string) error {
+	query := `
+		UPDATE counselings
+		SET questions_asked = CASE
+			WHEN questions_asked IS NULL OR questions_asked = '' THEN $3
+			ELSE questions_asked || E'\n' || $3
+		END
+		WHERE id = $1 AND assessment_id = $2
+	`
+
+	result, err := r.db.Exec(ctx, query, id, assessmentID, question)
+	if err != nil {
+		return fmt.Errorf("failed to record caregiver question: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrCounselingNotFound
+	}
+
+	return nil
+}
+
+func (r *CounselingRepo) CompletionReport(ctx context.Context, from, to time.Time, medicalProfessionalID *uuid.UUID) ([]*domain.CounselingCompletion, error) {
+	query := `
+		WITH per_assessment AS (
+			SELECT a.medical_professional_id,
+				a.id AS assessment_id,
+				COUNT(*) AS items,
+				COUNT(*) FILTER (WHERE c.understood_by_caregiver = 'yes') AS understood,
+				COUNT(*) FILTER (WHERE COALESCE(c.questions_asked, '') <> '') AS with_questions
+			FROM counselings c
+			JOIN assessments a ON a.id = c.assessment_id
+			WHERE c.advice_type IN ('home_fluids', 'feeding', 'when_to_return', 'referral')
+				AND a.created_at >= $1 AND a.created_at < $2
+				AND a.deleted_at IS NULL
+				AND ($3::uuid IS NULL OR a.medical_professional_id = $3)
+			GROUP BY a.medical_professional_id, a.id
+		)
+		SELECT p.medical_professional_id,
+			COALESCE(mp.full_name, ''),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE p.understood = p.items),
+			SUM(p.items),
+			SUM(p.understood),
+			SUM(p.with_questions)
+		FROM per_assessment p
+		LEFT JOIN medical_professionals mp ON mp.id = p.medical_professional_id
+		GROUP BY p.medical_professional_id, mp.full_name
+		ORDER BY mp.full_name
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to, medicalProfessionalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query counseling completion: %w", err)
+	}
+	defer rows.Close()
 
-		counselings = append(counselings, &counseling)
+	report := []*domain.CounselingCompletion{}
+	for rows.Next() {
+		var row domain.CounselingCompletion
+		err := rows.Scan(
+			&row.MedicalProfessionalID,
+			&row.FullName,
+			&row.Assessments,
+			&row.CompletedAssessments,
+			&row.Items,
+			&row.UnderstoodItems,
+			&row.ItemsWithQuestions,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan counseling completion: %w", err)
+		}
+		if row.Assessments > 0 {
+			row.CompletionRate = float64(row.CompletedAssessments) / float64(row.Assessments)
+		}
+		report = append(report, &row)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating counselings: %w", err)
+		return nil, fmt.Errorf("error iterating counseling completion: %w", err)
 	}
 
-	return counselings, nil
+	return report, nil
+}
+
+func scanCounseling(row pgx.Row) (*domain.Counseling, error) {
+	var counseling domain.Counseling
+	err := row.Scan(
+		&counseling.ID,
+		&counseling.AssessmentID,
+		&counseling.ClassificationID,
+		&counseling.AdviceType,
+		&counseling.Details,
+		&counseling.Language,
+		&counseling.UnderstoodByCaregiver,
+		&counseling.QuestionsAsked,
+		&counseling.UnderstoodAt,
+		&counseling.CounseledBy,
+		&counseling.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &counseling, nil
 }
\ No newline at end of file
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DHIS2Repo struct {
+	db *pgxpool.Pool
+}
+
+func NewDHIS2Repo(db *pgxpool.Pool) domain.DHIS2Repository {
+	return &DHIS2Repo{db: db}
+}
+
+func (r *DHIS2Repo) CountClassifications(ctx context.Context, from, to time.Time) ([]*domain.IndicatorCount, error) {
+	query := `
+		SELECT c.disease, a.assessment_type, p.gender, COALESCE(mp.facility_name, ''), COUNT(*)
+		FROM classifications c
+		JOIN assessments a ON a.id = c.assessment_id
+		JOIN patients p ON p.id = a.patient_id
+		JOIN medical_professionals mp ON mp.id = a.medical_professional_id
+		WHERE a.start_time >= $1 AND a.start_time < $2
+		  AND a.status <> $3
+		  AND a.deleted_at IS NULL AND p.deleted_at IS NULL
+		GROUP BY c.disease, a.assessment_type, p.gender, mp.facility_name
+		ORDER BY mp.facility_name, c.disease, a.assessment_type, p.gender
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to, domain.StatusCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count classifications: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*domain.IndicatorCount{}
+	for rows.Next() {
+		var count domain.IndicatorCount
+		err := rows.Scan(
+			&count.Classification,
+			&count.AgeGroup,
+			&count.Sex,
+			&count.Facility,
+			&count.Count,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan classification count: %w", err)
+		}
+		counts = append(counts, &count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating classification counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *DHIS2Repo) RecordExport(ctx context.Context, export *domain.DHIS2Export) error {
+	query := `
+		INSERT INTO dhis2_exports (
+			id, period, status, data_values, imported, updated, ignored, deleted, error, triggered_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	if export.ID == uuid.Nil {
+		export.ID = uuid.New()
+	}
+
+	_, err := r.db.Exec(ctx, query,
+		export.ID,
+		export.Period,
+		export.Status,
+		export.DataValues,
+		export.Imported,
+		export.Updated,
+		export.Ignored,
+		export.Deleted,
+		export.Error,
+		export.TriggeredBy,
+		export.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record DHIS2 export: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DHIS2Repo) ListExports(ctx context.Context, limit int) ([]*domain.DHIS2Export, error) {
+	query := `
+		SELECT id, period, status, data_values, imported, updated, ignored, deleted, error, triggered_by, created_at
+		FROM dhis2_exports
+		ORDER BY created_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query DHIS2 exports: %w", err)
+	}
+	defer rows.Close()
+
+	exports := []*domain.DHIS2Export{}
+	for rows.Next() {
+		var export domain.DHIS2Export
+		err := rows.Scan(
+			&export.ID,
+			&export.Period,
+			&export.Status,
+			&export.DataValues,
+			&export.Imported,
+			&export.Updated,
+			&export.Ignored,
+			&export.Deleted,
+			&export.Error,
+			&export.TriggeredBy,
+			&export.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan DHIS2 export: %w", err)
+		}
+		exports = append(exports, &export)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating DHIS2 exports: %w", err)
+	}
+
+	return exports, nil
+}
+
+func (r *DHIS2Repo) HasSuccessfulExport(ctx context.Context, period string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM dhis2_exports WHERE period = $1 AND status <> $2
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, period, domain.DHIS2ExportFailed).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check DHIS2 exports: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,62 @@
+// repository/followup_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FollowUpRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewFollowUpRepo(db *pgxpool.Pool) domain.FollowUpRepository {
+	return &FollowUpRepo{db: db}
+}
+
+func (r *FollowUpRepo) ListDue(ctx context.Context, medicalProfessionalID uuid.UUID, from, to time.Time) ([]*domain.FollowUp, error) {
+	query := `
+		SELECT c.assessment_id, a.patient_id, p.name, c.disease, c.color, c.follow_up_date
+		FROM classifications c
+		JOIN assessments a ON a.id = c.assessment_id
+		JOIN patients p ON p.id = a.patient_id
+		WHERE a.medical_professional_id = $1
+			AND a.deleted_at IS NULL AND p.deleted_at IS NULL
+			AND c.follow_up_date >= $2 AND c.follow_up_date < $3
+		ORDER BY c.follow_up_date, c.treatment_priority, p.name
+	`
+
+	rows, err := r.db.Query(ctx, query, medicalProfessionalID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query follow-ups: %w", err)
+	}
+	defer rows.Close()
+
+	followUps := []*domain.FollowUp{}
+	for rows.Next() {
+		var f domain.FollowUp
+		err := rows.Scan(
+			&f.AssessmentID,
+			&f.PatientID,
+			&f.PatientName,
+			&f.Disease,
+			&f.Color,
+			&f.FollowUpDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan follow-up: %w", err)
+		}
+		followUps = append(followUps, &f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating follow-ups: %w", err)
+	}
+
+	return followUps, nil
+}
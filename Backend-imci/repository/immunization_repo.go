@@ -0,0 +1,103 @@
+// repository/immunization_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImmunizationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewImmunizationRepo(db *pgxpool.Pool) domain.ImmunizationRepository {
+	return &ImmunizationRepo{db: db}
+}
+
+func (r *ImmunizationRepo) Create(ctx context.Context, record *domain.ImmunizationRecord) error {
+	query := `
+		INSERT INTO immunizations (
+			id, patient_id, vaccine, dose_date, lot_number, facility,
+			recorded_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	record.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		record.ID,
+		record.PatientID,
+		record.Vaccine,
+		record.DoseDate,
+		nullableString(record.LotNumber),
+		nullableString(record.Facility),
+		record.RecordedBy,
+		record.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create immunization record: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ImmunizationRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID) ([]*domain.ImmunizationRecord, error) {
+	query := `
+		SELECT id, patient_id, vaccine, dose_date, COALESCE(lot_number, ''),
+			COALESCE(facility, ''), recorded_by, created_at
+		FROM immunizations
+		WHERE patient_id = $1
+		ORDER BY dose_date, vaccine
+	`
+
+	rows, err := r.db.Query(ctx, query, patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query immunization records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*domain.ImmunizationRecord{}
+	for rows.Next() {
+		var record domain.ImmunizationRecord
+		err := rows.Scan(
+			&record.ID,
+			&record.PatientID,
+			&record.Vaccine,
+			&record.DoseDate,
+			&record.LotNumber,
+			&record.Facility,
+			&record.RecordedBy,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan immunization record: %w", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating immunization records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *ImmunizationRepo) Delete(ctx context.Context, id uuid.UUID, patientID uuid.UUID) error {
+	query := `DELETE FROM immunizations WHERE id = $1 AND patient_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, patientID)
+	if err != nil {
+		return fmt.Errorf("failed to delete immunization record: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrImmunizationNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// repository/login_audit_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoginAuditRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginAuditRepo(db *pgxpool.Pool) domain.LoginAuditRepository {
+	return &LoginAuditRepo{db: db}
+}
+
+func (r *LoginAuditRepo) Record(ctx context.Context, entry *domain.LoginAuditEntry) error {
+	query := `
+		INSERT INTO login_audit (
+			id, phone, medical_professional_id, ip_address, device, event, performed_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	err := r.db.QueryRow(ctx, query,
+		entry.ID,
+		entry.Phone,
+		entry.MedicalProfessionalID,
+		entry.IPAddress,
+		entry.Device,
+		entry.Event,
+		entry.PerformedBy,
+	).Scan(&entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record login audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the most recent audit entries, optionally for one phone.
+func (r *LoginAuditRepo) List(ctx context.Context, phone string, limit int) ([]*domain.LoginAuditEntry, error) {
+	query := `
+		SELECT id, phone, medical_professional_id, ip_address, device, event, performed_by, created_at
+		FROM login_audit
+		WHERE ($1 = '' OR phone = $1)
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, phone, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query login audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.LoginAuditEntry{}
+	for rows.Next() {
+		var entry domain.LoginAuditEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Phone,
+			&entry.MedicalProfessionalID,
+			&entry.IPAddress,
+			&entry.Device,
+			&entry.Event,
+			&entry.PerformedBy,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan login audit entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating login audit: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+// repository/notification_delivery_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificationDeliveryRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationDeliveryRepo(db *pgxpool.Pool) domain.NotificationDeliveryRepository {
+	return &NotificationDeliveryRepo{db: db}
+}
+
+func (r *NotificationDeliveryRepo) Record(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	query := `
+		INSERT INTO notification_deliveries (
+			id, phone, channel, purpose, status, error, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+
+	_, err := r.db.Exec(ctx, query,
+		delivery.ID,
+		delivery.Phone,
+		delivery.Channel,
+		delivery.Purpose,
+		delivery.Status,
+		delivery.Error,
+		delivery.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record notification delivery: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the most recent delivery attempts, optionally for one phone.
+func (r *NotificationDeliveryRepo) List(ctx context.Context, phone string, limit int) ([]*domain.NotificationDelivery, error) {
+	query := `
+		SELECT id, phone, channel, purpose, status, error, created_at
+		FROM notification_deliveries
+		WHERE ($1 = '' OR phone = $1)
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, phone, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.NotificationDelivery{}
+	for rows.Next() {
+		var delivery domain.NotificationDelivery
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.Phone,
+			&delivery.Channel,
+			&delivery.Purpose,
+			&delivery.Status,
+			&delivery.Error,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification delivery: %w", err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
@@ -22,7 +22,7 @@ func NewOtpRepository(db *pgxpool.Pool) *OtpRepository {
 func (o *OtpRepository) GetOtpByPhone(ctx context.Context, phone string) (*domain.OTP, error) {
 	otp := &domain.OTP{}
 	query := `
-        SELECT id, phone, code, role, facility_name, full_name, password, created_at, expires_at 
+        SELECT id, phone, code, role, facility_name, full_name, password_hash, use_whatsapp, notification_channel, notification_language, created_at, expires_at 
         FROM otp 
         WHERE phone = $1 AND expires_at > $2
         ORDER BY created_at DESC 
@@ -37,7 +37,10 @@ func (o *OtpRepository) GetOtpByPhone(ctx context.Context, phone string) (*domai
 		&otp.Role,
 		&otp.FacilityName,
 		&otp.FullName,
-		&otp.Password,
+		&otp.PasswordHash,
+		&otp.UseWhatsApp,
+		&otp.Channel,
+		&otp.Language,
 		&otp.CreatedAt,
 		&otp.ExpiresAt,
 	)
@@ -66,8 +69,8 @@ func (o *OtpRepository) SaveOTP(ctx context.Context, otp *domain.OTP) error {
 	}
 
 	query := `
-        INSERT INTO otp (phone, code, role, facility_name, full_name, password, expires_at) 
-        VALUES ($1, $2, $3, $4, $5, $6, $7) 
+        INSERT INTO otp (phone, code, role, facility_name, full_name, password_hash, use_whatsapp, notification_channel, notification_language, expires_at) 
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
         RETURNING id, created_at
     `
 	err = tx.QueryRow(
@@ -78,7 +81,10 @@ func (o *OtpRepository) SaveOTP(ctx context.Context, otp *domain.OTP) error {
 		otp.Role,
 		otp.FacilityName,
 		otp.FullName,
-		otp.Password,
+		otp.PasswordHash,
+		otp.UseWhatsApp,
+		otp.Channel,
+		otp.Language,
 		otp.ExpiresAt,
 	).Scan(&otp.ID, &otp.CreatedAt)
 
@@ -94,3 +100,13 @@ func (o *OtpRepository) DeleteOTP(ctx context.Context, phone string) error {
 	_, err := o.db.Exec(ctx, query, phone)
 	return err
 }
+
+// PurgeExpired deletes pending signups whose OTP has expired and returns how
+// many were removed.
+func (o *OtpRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	result, err := o.db.Exec(ctx, `DELETE FROM otp WHERE expires_at <= $1`, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired OTPs: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutbreakRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOutbreakRepo(db *pgxpool.Pool) domain.OutbreakRepository {
+	return &OutbreakRepo{db: db}
+}
+
+func (r *OutbreakRepo) DailyCounts(ctx context.Context, signals []string, from, to time.Time) ([]*domain.DailySignalCount, error) {
+	query := `
+		SELECT mp.facility_name, s.signal, a.start_time::date, COUNT(DISTINCT a.id)
+		FROM classifications c
+		JOIN assessments a ON a.id = c.assessment_id
+		JOIN medical_professionals mp ON mp.id = a.medical_professional_id
+		JOIN unnest($1::text[]) AS s(signal) ON UPPER(c.disease) LIKE '%' || UPPER(s.signal) || '%'
+		WHERE a.start_time >= $2 AND a.start_time < $3
+		  AND a.status <> $4
+		  AND a.deleted_at IS NULL
+		  AND COALESCE(mp.facility_name, '') <> ''
+		GROUP BY mp.facility_name, s.signal, a.start_time::date
+		ORDER BY mp.facility_name, s.signal, a.start_time::date
+	`
+
+	rows, err := r.db.Query(ctx, query, signals, from, to, domain.StatusCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count signal cases: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*domain.DailySignalCount{}
+	for rows.Next() {
+		var count domain.DailySignalCount
+		if err := rows.Scan(&count.Facility, &count.Signal, &count.Date, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan signal count: %w", err)
+		}
+		counts = append(counts, &count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating signal counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *OutbreakRepo) CreateAlert(ctx context.Context, alert *domain.OutbreakAlert) (bool, error) {
+	query := `
+		INSERT INTO outbreak_alerts (
+			id, facility_name, signal, alert_date, method, observed, baseline,
+			statistic, threshold, status, notified, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (facility_name, signal, alert_date, method) DO NOTHING
+	`
+
+	if alert.ID == uuid.Nil {
+		alert.ID = uuid.New()
+	}
+
+	result, err := r.db.Exec(ctx, query,
+		alert.ID,
+		alert.Facility,
+		alert.Signal,
+		alert.AlertDate,
+		alert.Method,
+		alert.Observed,
+		alert.Baseline,
+		alert.Statistic,
+		alert.Threshold,
+		alert.Status,
+		alert.Notified,
+		alert.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create outbreak alert: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *OutbreakRepo) SetNotified(ctx context.Context, id uuid.UUID, notified int) error {
+	query := `UPDATE outbreak_alerts SET notified = $2 WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, id, notified); err != nil {
+		return fmt.Errorf("failed to update outbreak alert: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OutbreakRepo) ListAlerts(ctx context.Context, status string, limit int) ([]*domain.OutbreakAlert, error) {
+	query := `
+		SELECT id, facility_name, signal, alert_date, method, observed, baseline,
+			statistic, threshold, status, notified, acknowledged_by, acknowledged_at, created_at
+		FROM outbreak_alerts
+		WHERE ($1::text = '' OR status = $1::text)
+		ORDER BY alert_date DESC, created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbreak alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []*domain.OutbreakAlert{}
+	for rows.Next() {
+		var alert domain.OutbreakAlert
+		err := rows.Scan(
+			&alert.ID,
+			&alert.Facility,
+			&alert.Signal,
+			&alert.AlertDate,
+			&alert.Method,
+			&alert.Observed,
+			&alert.Baseline,
+			&alert.Statistic,
+			&alert.Threshold,
+			&alert.Status,
+			&alert.Notified,
+			&alert.AcknowledgedBy,
+			&alert.AcknowledgedAt,
+			&alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbreak alert: %w", err)
+		}
+		alerts = append(alerts, &alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbreak alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+func (r *OutbreakRepo) Acknowledge(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE outbreak_alerts
+		SET status = $2, acknowledged_by = $3, acknowledged_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, id, domain.OutbreakAlertAcknowledged, medicalProfessionalID, at)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge outbreak alert: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrOutbreakAlertNotFound
+	}
+
+	return nil
+}
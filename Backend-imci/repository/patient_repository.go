@@ -8,6 +8,7 @@ import (
 
 	"github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -45,7 +46,7 @@ func (p *PatientRepo) Create(ctx context.Context, patient *domain.Patient) error
 func (p *PatientRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Patient, error) {
 	patient := &domain.Patient{}
 	query := `SELECT id, name, date_of_birth, gender, is_offline, created_at, updated_at 
-	          FROM patients WHERE id=$1`
+	          FROM patients WHERE id=$1 AND deleted_at IS NULL`
 	err := p.db.QueryRow(ctx, query, id).Scan(
 		&patient.ID,
 		&patient.Name,
@@ -56,7 +57,10 @@ func (p *PatientRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Patien
 		&patient.UpdatedAt,
 	)
 	if err != nil {
-		return nil, domain.ErrPatientNotFound
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrPatientNotFound
+		}
+		return nil, fmt.Errorf("failed to get patient: %w", err)
 	}
 	return patient, nil
 }
@@ -65,7 +69,7 @@ func (p *PatientRepo) GetAll(ctx context.Context, page, perPage int) ([]*domain.
 	offset := (page - 1) * perPage
 	
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM patients`
+	countQuery := `SELECT COUNT(*) FROM patients WHERE deleted_at IS NULL`
 	err := p.db.QueryRow(ctx, countQuery).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count patients: %w", err)
@@ -73,6 +77,7 @@ func (p *PatientRepo) GetAll(ctx context.Context, page, perPage int) ([]*domain.
 
 	query := `SELECT id, name, date_of_birth, gender, is_offline, created_at, updated_at 
 	          FROM patients 
+			  WHERE deleted_at IS NULL
 			  ORDER BY created_at DESC 
 			  LIMIT $1 OFFSET $2`
 	
@@ -106,11 +111,50 @@ func (p *PatientRepo) GetAll(ctx context.Context, page, perPage int) ([]*domain.
 	return patients, totalCount, nil
 }
 
+// SearchByName returns patients whose name contains the given text, ignoring
+// case.
+func (p *PatientRepo) SearchByName(ctx context.Context, name string, limit int) ([]*domain.Patient, error) {
+	query := `SELECT id, name, date_of_birth, gender, is_offline, created_at, updated_at
+	          FROM patients
+	          WHERE name ILIKE '%' || $1 || '%' AND deleted_at IS NULL
+	          ORDER BY name
+	          LIMIT $2`
+
+	rows, err := p.db.Query(ctx, query, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search patients: %w", err)
+	}
+	defer rows.Close()
+
+	var patients []*domain.Patient
+	for rows.Next() {
+		var patient domain.Patient
+		if err := rows.Scan(
+			&patient.ID,
+			&patient.Name,
+			&patient.DateOfBirth,
+			&patient.Gender,
+			&patient.IsOffline,
+			&patient.CreatedAt,
+			&patient.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan patient: %w", err)
+		}
+		patients = append(patients, &patient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating patients: %w", err)
+	}
+
+	return patients, nil
+}
+
 func (p *PatientRepo) Update(ctx context.Context, patient *domain.Patient) error {
 	query := `
 	UPDATE patients 
 	SET name=$1, date_of_birth=$2, gender=$3, is_offline=$4, updated_at=$5 
-	WHERE id=$6
+	WHERE id=$6 AND deleted_at IS NULL
 	`
 	result, err := p.db.Exec(ctx, query,
 		patient.Name,
@@ -130,9 +174,17 @@ func (p *PatientRepo) Update(ctx context.Context, patient *domain.Patient) error
 	return nil
 }
 
+// Delete soft-deletes the patient with their assessments so their history
+// stays on record.
 func (p *PatientRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM patients WHERE id=$1`
-	result, err := p.db.Exec(ctx, query, id)
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	result, err := tx.Exec(ctx, `UPDATE patients SET deleted_at=$2 WHERE id=$1 AND deleted_at IS NULL`, id, now)
 	if err != nil {
 		return fmt.Errorf("failed to delete patient: %w", err)
 	}
@@ -140,5 +192,13 @@ func (p *PatientRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	if result.RowsAffected() == 0 {
 		return domain.ErrPatientNotFound
 	}
+
+	if _, err := tx.Exec(ctx, `UPDATE assessments SET deleted_at=$2 WHERE patient_id=$1 AND deleted_at IS NULL`, id, now); err != nil {
+		return fmt.Errorf("failed to delete patient assessments: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit patient deletion: %w", err)
+	}
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PrescriptionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPrescriptionRepo(db *pgxpool.Pool) domain.PrescriptionRepository {
+	return &PrescriptionRepo{db: db}
+}
+
+func (r *PrescriptionRepo) Save(ctx context.Context, prescription *domain.Prescription) error {
+	warningsJSON, err := json.Marshal(prescription.Warnings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prescription warnings: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if prescription.ID == uuid.Nil {
+		prescription.ID = uuid.New()
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO prescriptions (id, assessment_id, warnings, generated_by, generated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (assessment_id) DO UPDATE
+		SET warnings = EXCLUDED.warnings, generated_by = EXCLUDED.generated_by,
+			generated_at = EXCLUDED.generated_at
+		RETURNING id
+	`,
+		prescription.ID,
+		prescription.AssessmentID,
+		warningsJSON,
+		prescription.GeneratedBy,
+		prescription.GeneratedAt,
+	).Scan(&prescription.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save prescription: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM prescription_items WHERE prescription_id = $1`, prescription.ID); err != nil {
+		return fmt.Errorf("failed to clear prescription items: %w", err)
+	}
+
+	for _, item := range prescription.Items {
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO prescription_items (
+				id, prescription_id, position, drug_key, drug_name, dosage, frequency, duration,
+				administration_route, is_pre_referral, instructions, treatment_priority,
+				treatment_plan_ids, classification_ids
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`,
+			item.ID,
+			prescription.ID,
+			item.Position,
+			item.DrugKey,
+			item.DrugName,
+			item.Dosage,
+			item.Frequency,
+			item.Duration,
+			item.AdministrationRoute,
+			item.IsPreReferral,
+			item.Instructions,
+			item.TreatmentPriority,
+			item.TreatmentPlanIDs,
+			item.ClassificationIDs,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save prescription item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit prescription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PrescriptionRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*domain.Prescription, error) {
+	var prescription domain.Prescription
+	var warningsData []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT id, assessment_id, warnings, generated_by, generated_at
+		FROM prescriptions
+		WHERE assessment_id = $1
+	`, assessmentID).Scan(
+		&prescription.ID,
+		&prescription.AssessmentID,
+		&warningsData,
+		&prescription.GeneratedBy,
+		&prescription.GeneratedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrPrescriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get prescription: %w", err)
+	}
+	if err := json.Unmarshal(warningsData, &prescription.Warnings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal prescription warnings: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, position, drug_key, drug_name, dosage, frequency, duration,
+			administration_route, is_pre_referral, instructions, treatment_priority,
+			treatment_plan_ids, classification_ids
+		FROM prescription_items
+		WHERE prescription_id = $1
+		ORDER BY position
+	`, prescription.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prescription items: %w", err)
+	}
+	defer rows.Close()
+
+	prescription.Items = []*domain.PrescriptionItem{}
+	for rows.Next() {
+		var item domain.PrescriptionItem
+		err := rows.Scan(
+			&item.ID,
+			&item.Position,
+			&item.DrugKey,
+			&item.DrugName,
+			&item.Dosage,
+			&item.Frequency,
+			&item.Duration,
+			&item.AdministrationRoute,
+			&item.IsPreReferral,
+			&item.Instructions,
+			&item.TreatmentPriority,
+			&item.TreatmentPlanIDs,
+			&item.ClassificationIDs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan prescription item: %w", err)
+		}
+		prescription.Items = append(prescription.Items, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating prescription items: %w", err)
+	}
+
+	return &prescription, nil
+}
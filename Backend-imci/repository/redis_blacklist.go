@@ -47,6 +47,12 @@ func (r *RedisTokenBlacklist) IsTokenBlacklisted(ctx context.Context, token stri
 	return result > 0, nil
 }
 
+// Client exposes the underlying connection so other Redis-backed stores can
+// share it.
+func (r *RedisTokenBlacklist) Client() *redis.Client {
+	return r.client
+}
+
 func (r *RedisTokenBlacklist) Close() error {
 	return r.client.Close()
 }
\ No newline at end of file
@@ -0,0 +1,71 @@
+// repository/redis_login_attempts.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+func NewRedisLoginAttemptStore(client *redis.Client) domain.LoginAttemptStore {
+	return &RedisLoginAttemptStore{client: client}
+}
+
+func failuresKey(scope, id string) string {
+	return "login:failures:" + scope + ":" + id
+}
+
+func blockKey(scope, id string) string {
+	return "login:block:" + scope + ":" + id
+}
+
+func (r *RedisLoginAttemptStore) IncrementFailures(ctx context.Context, scope, id string, window time.Duration) (int64, error) {
+	key := failuresKey(scope, id)
+
+	count, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count login failure: %w", err)
+	}
+
+	// The window starts at the first failure.
+	if count == 1 {
+		if err := r.client.Expire(ctx, key, window).Err(); err != nil {
+			return 0, fmt.Errorf("failed to set login failure window: %w", err)
+		}
+	}
+
+	return count, nil
+}
+
+func (r *RedisLoginAttemptStore) Block(ctx context.Context, scope, id string, duration time.Duration) error {
+	if err := r.client.Set(ctx, blockKey(scope, id), "1", duration).Err(); err != nil {
+		return fmt.Errorf("failed to block login: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisLoginAttemptStore) BlockedFor(ctx context.Context, scope, id string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, blockKey(scope, id)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check login block: %w", err)
+	}
+	// PTTL reports -2 for a missing key and -1 for a key without expiry.
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (r *RedisLoginAttemptStore) Reset(ctx context.Context, scope, id string) error {
+	if err := r.client.Del(ctx, failuresKey(scope, id), blockKey(scope, id)).Err(); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// repository/refresh_session_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RefreshSessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRefreshSessionRepo(db *pgxpool.Pool) domain.RefreshSessionRepository {
+	return &RefreshSessionRepo{db: db}
+}
+
+func (r *RefreshSessionRepo) Create(ctx context.Context, session *domain.RefreshSession) error {
+	query := `
+		INSERT INTO refresh_sessions (
+			id, medical_professional_id, token_hash, device, ip_address,
+			created_at, last_used_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastUsedAt = now
+
+	_, err := r.db.Exec(ctx, query,
+		session.ID,
+		session.MedicalProfessionalID,
+		session.TokenHash,
+		session.Device,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastUsedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RefreshSessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.RefreshSession, error) {
+	query := `
+		SELECT id, medical_professional_id, token_hash, device, ip_address,
+			created_at, last_used_at, expires_at, revoked_at, COALESCE(revoked_reason, '')
+		FROM refresh_sessions
+		WHERE id = $1
+	`
+
+	var session domain.RefreshSession
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&session.ID,
+		&session.MedicalProfessionalID,
+		&session.TokenHash,
+		&session.Device,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.RevokedReason,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get refresh session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *RefreshSessionRepo) Rotate(ctx context.Context, id uuid.UUID, oldHash, newHash string, meta domain.SessionMetadata, expiresAt time.Time) error {
+	query := `
+		UPDATE refresh_sessions
+		SET token_hash = $1, device = $2, ip_address = $3, last_used_at = $4, expires_at = $5
+		WHERE id = $6 AND token_hash = $7 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, newHash, meta.Device, meta.IPAddress, time.Now(), expiresAt, id, oldHash)
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	return nil
+}
+
+func (r *RefreshSessionRepo) ListActive(ctx context.Context, medicalProfessionalID uuid.UUID) ([]*domain.RefreshSession, error) {
+	query := `
+		SELECT id, medical_professional_id, token_hash, device, ip_address,
+			created_at, last_used_at, expires_at, revoked_at, COALESCE(revoked_reason, '')
+		FROM refresh_sessions
+		WHERE medical_professional_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, medicalProfessionalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query refresh sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*domain.RefreshSession{}
+	for rows.Next() {
+		var session domain.RefreshSession
+		err := rows.Scan(
+			&session.ID,
+			&session.MedicalProfessionalID,
+			&session.TokenHash,
+			&session.Device,
+			&session.IPAddress,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+			&session.RevokedAt,
+			&session.RevokedReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan refresh session: %w", err)
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating refresh sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *RefreshSessionRepo) Revoke(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID, reason string) error {
+	query := `
+		UPDATE refresh_sessions
+		SET revoked_at = $1, revoked_reason = $2
+		WHERE id = $3 AND medical_professional_id = $4 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, time.Now(), reason, id, medicalProfessionalID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *RefreshSessionRepo) RevokeAll(ctx context.Context, medicalProfessionalID uuid.UUID, except *uuid.UUID, reason string) error {
+	query := `
+		UPDATE refresh_sessions
+		SET revoked_at = $1, revoked_reason = $2
+		WHERE medical_professional_id = $3 AND revoked_at IS NULL
+			AND ($4::uuid IS NULL OR id <> $4)
+	`
+
+	_, err := r.db.Exec(ctx, query, time.Now(), reason, medicalProfessionalID, except)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh sessions: %w", err)
+	}
+
+	return nil
+}
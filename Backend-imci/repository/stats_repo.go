@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStatsRepo(db *pgxpool.Pool) domain.StatsRepository {
+	return &StatsRepo{db: db}
+}
+
+// scopedAssessments is the CTE every dashboard query starts from. It takes
+// $1 from, $2 to, $3 the cancelled status and $4 the facility name.
+const scopedAssessments = `
+	WITH scoped AS (
+		SELECT a.id, a.patient_id, a.assessment_type, a.start_time, a.end_time
+		FROM assessments a
+		JOIN medical_professionals mp ON mp.id = a.medical_professional_id
+		WHERE a.start_time >= $1 AND a.start_time < $2
+		  AND a.status <> $3
+		  AND a.deleted_at IS NULL
+		  AND ($4::text = '' OR mp.facility_name = $4::text)
+	)`
+
+func scopedArgs(filter domain.StatsFilter, extra ...any) []any {
+	return append([]any{filter.From, filter.To, domain.StatusCancelled, filter.Facility}, extra...)
+}
+
+func (r *StatsRepo) AssessmentsPerDay(ctx context.Context, filter domain.StatsFilter) ([]*domain.DailyCount, error) {
+	query := scopedAssessments + `
+		SELECT to_char(start_time::date, 'YYYY-MM-DD'), COUNT(*)
+		FROM scoped
+		GROUP BY start_time::date
+		ORDER BY start_time::date
+	`
+
+	rows, err := r.db.Query(ctx, query, scopedArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count assessments per day: %w", err)
+	}
+	defer rows.Close()
+
+	days := []*domain.DailyCount{}
+	for rows.Next() {
+		var day domain.DailyCount
+		if err := rows.Scan(&day.Date, &day.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan daily count: %w", err)
+		}
+		days = append(days, &day)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily counts: %w", err)
+	}
+
+	return days, nil
+}
+
+func (r *StatsRepo) ClassificationCounts(ctx context.Context, filter domain.StatsFilter) ([]*domain.ClassificationCount, error) {
+	query := scopedAssessments + `
+		SELECT c.disease, c.color, s.assessment_type, COUNT(*)
+		FROM scoped s
+		JOIN classifications c ON c.assessment_id = s.id
+		GROUP BY c.disease, c.color, s.assessment_type
+		ORDER BY COUNT(*) DESC, c.disease, s.assessment_type
+	`
+
+	rows, err := r.db.Query(ctx, query, scopedArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count classifications: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*domain.ClassificationCount{}
+	for rows.Next() {
+		var count domain.ClassificationCount
+		err := rows.Scan(
+			&count.Classification,
+			&count.Color,
+			&count.AgeGroup,
+			&count.Count,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan classification count: %w", err)
+		}
+		counts = append(counts, &count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating classification counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *StatsRepo) Referrals(ctx context.Context, filter domain.StatsFilter) (*domain.ReferralStats, error) {
+	query := scopedAssessments + `,
+	emergencies AS (
+		SELECT DISTINCT s.id
+		FROM scoped s
+		JOIN classifications c ON c.assessment_id = s.id
+		WHERE c.requires_urgent_referral
+	)
+	SELECT COUNT(*),
+		COUNT(*) FILTER (WHERE
+			EXISTS (SELECT 1 FROM treatment_plans t WHERE t.assessment_id = e.id AND t.is_pre_referral)
+			OR EXISTS (
+				SELECT 1 FROM counselings k
+				WHERE k.assessment_id = e.id AND k.advice_type = $5 AND k.understood_by_caregiver
+			))
+	FROM emergencies e
+	`
+
+	var stats domain.ReferralStats
+	err := r.db.QueryRow(ctx, query, scopedArgs(filter, domain.AdviceReferral)...).Scan(&stats.Emergencies, &stats.Referred)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count referrals: %w", err)
+	}
+
+	return &stats, nil
+}
+
+func (r *StatsRepo) FollowUpCompliance(ctx context.Context, filter domain.StatsFilter, now time.Time, graceDays int) (*domain.FollowUpCompliance, error) {
+	query := scopedAssessments + `,
+	due AS (
+		SELECT s.id, s.patient_id, MIN(c.follow_up_date) AS follow_up_date
+		FROM scoped s
+		JOIN classifications c ON c.assessment_id = s.id
+		WHERE c.follow_up_date IS NOT NULL
+		GROUP BY s.id, s.patient_id
+	)
+	SELECT COUNT(*),
+		COUNT(*) FILTER (WHERE EXISTS (
+			SELECT 1 FROM assessments v
+			WHERE v.patient_id = d.patient_id AND v.id <> d.id AND v.status <> $3
+			  AND v.deleted_at IS NULL
+			  AND v.start_time >= d.follow_up_date - 1
+			  AND v.start_time < d.follow_up_date + $6::int + 1
+		))
+	FROM due d
+	WHERE d.follow_up_date + $6::int + 1 <= $5::date
+	`
+
+	stats := domain.FollowUpCompliance{GraceDays: graceDays}
+	err := r.db.QueryRow(ctx, query, scopedArgs(filter, now, graceDays)...).Scan(&stats.Due, &stats.Attended)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure follow-up compliance: %w", err)
+	}
+
+	return &stats, nil
+}
+
+func (r *StatsRepo) AssessmentDuration(ctx context.Context, filter domain.StatsFilter) (*domain.AssessmentDuration, error) {
+	query := scopedAssessments + `
+		SELECT COUNT(*),
+			(AVG(EXTRACT(EPOCH FROM end_time - start_time)) / 60)::float8,
+			(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM end_time - start_time)) / 60)::float8
+		FROM scoped
+		WHERE end_time IS NOT NULL AND end_time > start_time
+	`
+
+	var duration domain.AssessmentDuration
+	err := r.db.QueryRow(ctx, query, scopedArgs(filter)...).Scan(
+		&duration.Assessments,
+		&duration.AverageMinutes,
+		&duration.MedianMinutes,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure assessment duration: %w", err)
+	}
+
+	return &duration, nil
+}
+
+func (r *StatsRepo) ListFacilities(ctx context.Context, from, to time.Time) ([]*domain.FacilitySummary, error) {
+	query := `
+		SELECT mp.facility_name, COUNT(DISTINCT mp.id), COUNT(a.id)
+		FROM medical_professionals mp
+		LEFT JOIN assessments a ON a.medical_professional_id = mp.id
+			AND a.start_time >= $1 AND a.start_time < $2
+			AND a.status <> $3
+			AND a.deleted_at IS NULL
+		WHERE COALESCE(mp.facility_name, '') <> ''
+		GROUP BY mp.facility_name
+		ORDER BY COUNT(a.id) DESC, mp.facility_name
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to, domain.StatusCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query facilities: %w", err)
+	}
+	defer rows.Close()
+
+	facilities := []*domain.FacilitySummary{}
+	for rows.Next() {
+		var facility domain.FacilitySummary
+		if err := rows.Scan(&facility.Facility, &facility.Clinicians, &facility.Assessments); err != nil {
+			return nil, fmt.Errorf("failed to scan facility: %w", err)
+		}
+		facilities = append(facilities, &facility)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating facilities: %w", err)
+	}
+
+	return facilities, nil
+}
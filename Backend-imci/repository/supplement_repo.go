@@ -0,0 +1,103 @@
+// repository/supplement_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SupplementRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSupplementRepo(db *pgxpool.Pool) domain.SupplementRepository {
+	return &SupplementRepo{db: db}
+}
+
+func (r *SupplementRepo) Create(ctx context.Context, record *domain.SupplementRecord) error {
+	query := `
+		INSERT INTO supplement_doses (
+			id, patient_id, supplement, dose, dose_date, facility,
+			recorded_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	record.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		record.ID,
+		record.PatientID,
+		record.Supplement,
+		record.Dose,
+		record.DoseDate,
+		nullableString(record.Facility),
+		record.RecordedBy,
+		record.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create supplement record: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SupplementRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID) ([]*domain.SupplementRecord, error) {
+	query := `
+		SELECT id, patient_id, supplement, dose, dose_date,
+			COALESCE(facility, ''), recorded_by, created_at
+		FROM supplement_doses
+		WHERE patient_id = $1
+		ORDER BY dose_date DESC, supplement
+	`
+
+	rows, err := r.db.Query(ctx, query, patientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supplement records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*domain.SupplementRecord{}
+	for rows.Next() {
+		var record domain.SupplementRecord
+		err := rows.Scan(
+			&record.ID,
+			&record.PatientID,
+			&record.Supplement,
+			&record.Dose,
+			&record.DoseDate,
+			&record.Facility,
+			&record.RecordedBy,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan supplement record: %w", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating supplement records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *SupplementRepo) Delete(ctx context.Context, id uuid.UUID, patientID uuid.UUID) error {
+	query := `DELETE FROM supplement_doses WHERE id = $1 AND patient_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, patientID)
+	if err != nil {
+		return fmt.Errorf("failed to delete supplement record: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSupplementNotFound
+	}
+
+	return nil
+}
@@ -58,6 +58,30 @@ func (t *telegramRepository) GetUsernameByPhone(ctx context.Context, phone strin
 	return username, nil
 }
 
+func (t *telegramRepository) GetPhoneByChatID(ctx context.Context, chatID int64) (string, error) {
+	var phone string
+	query := `SELECT phone_number FROM telegram_chat_ids WHERE chat_id = $1`
+
+	err := t.db.QueryRow(ctx, query, chatID).Scan(&phone)
+	if err != nil {
+		return "", fmt.Errorf("failed to get phone for chat ID %d: %w", chatID, err)
+	}
+
+	return phone, nil
+}
+
+func (t *telegramRepository) GetChatIDByPhone(ctx context.Context, phone string) (int64, error) {
+	var chatID int64
+	query := `SELECT chat_id FROM telegram_chat_ids WHERE phone_number = $1`
+
+	err := t.db.QueryRow(ctx, query, userutil.NormalizePhone(phone)).Scan(&chatID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chat ID for phone: %w", err)
+	}
+
+	return chatID, nil
+}
+
 func (t *telegramRepository) DeleteChatID(ctx context.Context, username string) error {
 	query := `DELETE FROM telegram_chat_ids WHERE telegram_username = $1`
 	_, err := t.db.Exec(ctx, query, username)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,20 +20,16 @@ func NewTreatmentPlanRepo(db *pgxpool.Pool) domain.TreatmentPlanRepository {
 	return &TreatmentPlanRepo{db: db}
 }
 
-func (r *TreatmentPlanRepo) Create(ctx context.Context, plan *domain.TreatmentPlan) error {
-	query := `
-		INSERT INTO treatment_plans (
-			id, assessment_id, classification_id, drug_name, dosage, frequency,
-			duration, administration_route, is_pre_referral, instructions,
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-	`
+const insertTreatmentPlanQuery = `
+	INSERT INTO treatment_plans (
+		id, assessment_id, classification_id, drug_name, dosage, frequency,
+		duration, administration_route, is_pre_referral, instructions,
+		created_at, updated_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+`
 
-	now := time.Now()
-	plan.CreatedAt = now
-	plan.UpdatedAt = now
-
-	_, err := r.db.Exec(ctx, query,
+func treatmentPlanArgs(plan *domain.TreatmentPlan) []interface{} {
+	return []interface{}{
 		plan.ID,
 		plan.AssessmentID,
 		plan.ClassificationID,
@@ -45,15 +42,30 @@ func (r *TreatmentPlanRepo) Create(ctx context.Context, plan *domain.TreatmentPl
 		plan.Instructions,
 		plan.CreatedAt,
 		plan.UpdatedAt,
-	)
+	}
+}
 
-	if err != nil {
+func (r *TreatmentPlanRepo) Create(ctx context.Context, plan *domain.TreatmentPlan) error {
+	now := time.Now()
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+
+	if _, err := r.db.Exec(ctx, insertTreatmentPlanQuery, treatmentPlanArgs(plan)...); err != nil {
 		return fmt.Errorf("failed to create treatment plan: %w", err)
 	}
 
 	return nil
 }
 
+// createTreatmentPlan writes plan in tx, for changes that replace a
+// classification's plans along with the classification.
+func createTreatmentPlan(ctx context.Context, tx pgx.Tx, plan *domain.TreatmentPlan) error {
+	if _, err := tx.Exec(ctx, insertTreatmentPlanQuery, treatmentPlanArgs(plan)...); err != nil {
+		return fmt.Errorf("failed to create treatment plan: %w", err)
+	}
+	return nil
+}
+
 func (r *TreatmentPlanRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.TreatmentPlan, error) {
 	query := `
 		SELECT id, assessment_id, classification_id, drug_name, dosage, frequency,
@@ -22,7 +22,7 @@ func NewMedicalProfessionalRepo(db *pgxpool.Pool) domain.MedicalProfessionalRepo
 func (m *MedicalProfessionalRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.MedicalProfessional, error) {
 	professional := &domain.MedicalProfessional{}
 	query := `
-		SELECT id, full_name, phone, password_hash, role, telegram_username, use_whatsapp, facility_name, created_at, updated_at 
+		SELECT id, full_name, phone, password_hash, role, telegram_username, use_whatsapp, notification_channel, notification_language, facility_name, is_trainee, created_at, updated_at 
 		FROM medical_professionals 
 		WHERE id=$1
 	`
@@ -34,7 +34,10 @@ func (m *MedicalProfessionalRepo) GetByID(ctx context.Context, id uuid.UUID) (*d
 		&professional.Role,
 		&professional.TelegramUsername,
 		&professional.UseWhatsApp,
+		&professional.NotificationChannel,
+		&professional.NotificationLanguage,
 		&professional.FacilityName,
+		&professional.IsTrainee,
 		&professional.CreatedAt,
 		&professional.UpdatedAt,
 	)
@@ -47,7 +50,7 @@ func (m *MedicalProfessionalRepo) GetByID(ctx context.Context, id uuid.UUID) (*d
 func (m *MedicalProfessionalRepo) GetByPhone(ctx context.Context, phone string) (*domain.MedicalProfessional, error) {
 	professional := &domain.MedicalProfessional{}
 	query := `
-		SELECT id, full_name, phone, password_hash, role, telegram_username, use_whatsapp, facility_name, created_at, updated_at 
+		SELECT id, full_name, phone, password_hash, role, telegram_username, use_whatsapp, notification_channel, notification_language, facility_name, is_trainee, created_at, updated_at 
 		FROM medical_professionals 
 		WHERE phone=$1
 	`
@@ -59,7 +62,10 @@ func (m *MedicalProfessionalRepo) GetByPhone(ctx context.Context, phone string)
 		&professional.Role,
 		&professional.TelegramUsername,
 		&professional.UseWhatsApp,
+		&professional.NotificationChannel,
+		&professional.NotificationLanguage,
 		&professional.FacilityName,
+		&professional.IsTrainee,
 		&professional.CreatedAt,
 		&professional.UpdatedAt,
 	)
@@ -72,8 +78,8 @@ func (m *MedicalProfessionalRepo) GetByPhone(ctx context.Context, phone string)
 func (m *MedicalProfessionalRepo) Create(ctx context.Context, professional *domain.MedicalProfessional) error {
 	query := `
 		INSERT INTO medical_professionals 
-		(full_name, phone, password_hash, role, telegram_username, use_whatsapp, facility_name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		(full_name, phone, password_hash, role, telegram_username, use_whatsapp, notification_channel, notification_language, facility_name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id
 	`
 
@@ -84,6 +90,8 @@ func (m *MedicalProfessionalRepo) Create(ctx context.Context, professional *doma
 		professional.Role,
 		professional.TelegramUsername,
 		professional.UseWhatsApp,
+		professional.NotificationChannel,
+		professional.NotificationLanguage,
 		professional.FacilityName,
 		time.Now(),
 		time.Now(),
@@ -99,8 +107,8 @@ func (m *MedicalProfessionalRepo) Update(ctx context.Context, professional *doma
 	query := `
 		UPDATE medical_professionals 
 		SET full_name=$1, phone=$2, password_hash=$3, role=$4, telegram_username=$5, 
-		    use_whatsapp=$6, facility_name=$7, updated_at=$8 
-		WHERE id=$9
+		    use_whatsapp=$6, notification_channel=$7, notification_language=$8, facility_name=$9, updated_at=$10 
+		WHERE id=$11
 	`
 	_, err := m.db.Exec(ctx, query,
 		professional.FullName,
@@ -109,6 +117,8 @@ func (m *MedicalProfessionalRepo) Update(ctx context.Context, professional *doma
 		professional.Role,
 		professional.TelegramUsername,
 		professional.UseWhatsApp,
+		professional.NotificationChannel,
+		professional.NotificationLanguage,
 		professional.FacilityName,
 		time.Now(),
 		professional.ID,
@@ -130,7 +140,7 @@ func (m *MedicalProfessionalRepo) Delete(ctx context.Context, id uuid.UUID) erro
 
 func (m *MedicalProfessionalRepo) GetAll(ctx context.Context) ([]*domain.MedicalProfessional, error) {
 	query := `
-		SELECT id, full_name, phone, password_hash, role, telegram_username, use_whatsapp, facility_name, created_at, updated_at 
+		SELECT id, full_name, phone, password_hash, role, telegram_username, use_whatsapp, notification_channel, notification_language, facility_name, is_trainee, created_at, updated_at 
 		FROM medical_professionals
 	`
 	rows, err := m.db.Query(ctx, query)
@@ -150,7 +160,52 @@ func (m *MedicalProfessionalRepo) GetAll(ctx context.Context) ([]*domain.Medical
 			&professional.Role,
 			&professional.TelegramUsername,
 			&professional.UseWhatsApp,
+			&professional.NotificationChannel,
+			&professional.NotificationLanguage,
 			&professional.FacilityName,
+			&professional.IsTrainee,
+			&professional.CreatedAt,
+			&professional.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan medical professional: %w", err)
+		}
+		professionals = append(professionals, &professional)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating medical professionals: %w", err)
+	}
+
+	return professionals, nil
+}
+
+func (m *MedicalProfessionalRepo) GetByFacilityAndRole(ctx context.Context, facility string, role string) ([]*domain.MedicalProfessional, error) {
+	query := `
+		SELECT id, full_name, phone, password_hash, role, telegram_username, use_whatsapp, notification_channel, notification_language, facility_name, is_trainee, created_at, updated_at 
+		FROM medical_professionals
+		WHERE facility_name = $1 AND role = $2
+	`
+	rows, err := m.db.Query(ctx, query, facility, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get medical professionals by facility and role: %w", err)
+	}
+	defer rows.Close()
+
+	var professionals []*domain.MedicalProfessional
+	for rows.Next() {
+		var professional domain.MedicalProfessional
+		if err := rows.Scan(
+			&professional.ID,
+			&professional.FullName,
+			&professional.Phone,
+			&professional.PasswordHash,
+			&professional.Role,
+			&professional.TelegramUsername,
+			&professional.UseWhatsApp,
+			&professional.NotificationChannel,
+			&professional.NotificationLanguage,
+			&professional.FacilityName,
+			&professional.IsTrainee,
 			&professional.CreatedAt,
 			&professional.UpdatedAt,
 		); err != nil {
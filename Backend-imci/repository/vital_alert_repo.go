@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type VitalAlertRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewVitalAlertRepo(db *pgxpool.Pool) domain.VitalAlertRepository {
+	return &VitalAlertRepo{db: db}
+}
+
+func (r *VitalAlertRepo) Save(ctx context.Context, alert *domain.VitalAlert) (bool, error) {
+	query := `
+		INSERT INTO vital_alerts (
+			id, assessment_id, code, sign, value, threshold, severity, message, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (assessment_id, code) DO UPDATE
+		SET value = EXCLUDED.value, threshold = EXCLUDED.threshold,
+			severity = EXCLUDED.severity, message = EXCLUDED.message, updated_at = EXCLUDED.updated_at
+		RETURNING id, notified_at, created_at, updated_at, (xmax = 0)
+	`
+
+	if alert.ID == uuid.Nil {
+		alert.ID = uuid.New()
+	}
+
+	var created bool
+	err := r.db.QueryRow(ctx, query,
+		alert.ID,
+		alert.AssessmentID,
+		alert.Code,
+		alert.Sign,
+		alert.Value,
+		alert.Threshold,
+		alert.Severity,
+		alert.Message,
+		time.Now(),
+	).Scan(&alert.ID, &alert.NotifiedAt, &alert.CreatedAt, &alert.UpdatedAt, &created)
+	if err != nil {
+		return false, fmt.Errorf("failed to save vital alert: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *VitalAlertRepo) MarkNotified(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE vital_alerts SET notified_at = $2 WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, id, at); err != nil {
+		return fmt.Errorf("failed to mark vital alert notified: %w", err)
+	}
+
+	return nil
+}
+
+func (r *VitalAlertRepo) DeleteExcept(ctx context.Context, assessmentID uuid.UUID, codes []string) error {
+	query := `DELETE FROM vital_alerts WHERE assessment_id = $1 AND NOT (code = ANY($2))`
+
+	if _, err := r.db.Exec(ctx, query, assessmentID, codes); err != nil {
+		return fmt.Errorf("failed to delete vital alerts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *VitalAlertRepo) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.VitalAlert, error) {
+	query := `
+		SELECT id, assessment_id, code, sign, value, threshold, severity, message,
+			notified_at, created_at, updated_at
+		FROM vital_alerts
+		WHERE assessment_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, assessmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vital alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []*domain.VitalAlert{}
+	for rows.Next() {
+		var alert domain.VitalAlert
+		err := rows.Scan(
+			&alert.ID,
+			&alert.AssessmentID,
+			&alert.Code,
+			&alert.Sign,
+			&alert.Value,
+			&alert.Threshold,
+			&alert.Severity,
+			&alert.Message,
+			&alert.NotifiedAt,
+			&alert.CreatedAt,
+			&alert.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vital alert: %w", err)
+		}
+		alerts = append(alerts, &alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vital alerts: %w", err)
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,56 @@
+// repository/whatsapp_status_repo.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WhatsAppStatusRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWhatsAppStatusRepo(db *pgxpool.Pool) domain.WhatsAppStatusRepository {
+	return &WhatsAppStatusRepo{db: db}
+}
+
+func (r *WhatsAppStatusRepo) Record(ctx context.Context, status *domain.WhatsAppStatusUpdate) error {
+	query := `
+		INSERT INTO whatsapp_message_statuses (
+			message_id, status, recipient_id, error_code, error_title, reported_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (message_id, status) DO NOTHING
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		status.MessageID,
+		status.Status,
+		status.RecipientID,
+		status.ErrorCode,
+		status.ErrorTitle,
+		status.Timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record whatsapp message status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WhatsAppStatusRepo) ClaimInbound(ctx context.Context, msg *domain.WhatsAppInboundMessage) (bool, error) {
+	query := `
+		INSERT INTO whatsapp_inbound_messages (message_id, from_phone, sent_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (message_id) DO NOTHING
+	`
+
+	tag, err := r.db.Exec(ctx, query, msg.ID, msg.From, msg.Timestamp)
+	if err != nil {
+		return false, fmt.Errorf("failed to record whatsapp message: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
 	childusecase "github.com/Afomiat/Digital-IMCI/ruleengine/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,11 +13,13 @@ import (
 
 type ChildRuleEngineController struct {
 	childRuleEngineUsecase *childusecase.ChildRuleEngineUsecase
+	translator             *locale.Translator
 }
 
-func NewChildRuleEngineController(childRuleEngineUsecase *childusecase.ChildRuleEngineUsecase) *ChildRuleEngineController {
+func NewChildRuleEngineController(childRuleEngineUsecase *childusecase.ChildRuleEngineUsecase, translator *locale.Translator) *ChildRuleEngineController {
 	return &ChildRuleEngineController{
 		childRuleEngineUsecase: childRuleEngineUsecase,
+		translator:             translator,
 	}
 }
 
@@ -28,7 +31,8 @@ type ErrorResponse struct {
 
 func (rc *ChildRuleEngineController) StartAssessmentFlow(c *gin.Context) {
 	var req struct {
-		TreeID string `json:"tree_id" binding:"required"`
+		TreeID   string `json:"tree_id" binding:"required"`
+		Language string `json:"language"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -65,6 +69,7 @@ func (rc *ChildRuleEngineController) StartAssessmentFlow(c *gin.Context) {
 	response, err := rc.childRuleEngineUsecase.StartAssessmentFlow(c.Request.Context(), domain.StartFlowRequest{
 		AssessmentID: assessmentID, 
 		TreeID:       req.TreeID,
+		Language:     RequestLanguage(c, rc.translator, req.Language),
 	}, mpID)
 
 	if err != nil {
@@ -85,7 +90,8 @@ func (rc *ChildRuleEngineController) StartAssessmentFlow(c *gin.Context) {
 func (rc *ChildRuleEngineController) SubmitAnswer(c *gin.Context) {
 	var req struct {
 		NodeID string      `json:"node_id" binding:"required"`
-		Answer interface{} `json:"answer" binding:"required"`
+		Answer   interface{} `json:"answer" binding:"required"`
+		Language string      `json:"language"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -123,6 +129,7 @@ func (rc *ChildRuleEngineController) SubmitAnswer(c *gin.Context) {
 		AssessmentID: assessmentID, 
 		NodeID:       req.NodeID,
 		Answer:       req.Answer,
+		Language:     RequestLanguage(c, rc.translator, req.Language),
 	}, mpID)
 
 	if err != nil {
@@ -149,6 +156,7 @@ func (ctrl *ChildRuleEngineController) ProcessBatchAssessment(c *gin.Context) {
         })
         return
     }
+    req.Language = RequestLanguage(c, ctrl.translator, req.Language)
 
     medicalProfessionalIDInterface, exists := c.Get("medical_professional_id")
     if !exists {
@@ -191,7 +199,8 @@ func (ctrl *ChildRuleEngineController) GetTreeQuestions(c *gin.Context) {
 		return
 	}
 
-	tree, err := ctrl.childRuleEngineUsecase.GetTreeQuestions(treeID)
+	language := RequestLanguage(c, ctrl.translator, "")
+	tree, err := ctrl.childRuleEngineUsecase.GetTreeQuestions(treeID, language)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get tree questions",
@@ -205,5 +214,6 @@ func (ctrl *ChildRuleEngineController) GetTreeQuestions(c *gin.Context) {
 		"title":        tree.Title,
 		"instructions": tree.Instructions,
 		"questions":    tree.QuestionsFlow,
+		"language":     language,
 	})
 }
\ No newline at end of file
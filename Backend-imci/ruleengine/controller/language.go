@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLanguage picks the language for a tree or flow response: the one
+// named in the request body if it is supported, otherwise the best match for
+// the Accept-Language header. The choice is echoed in Content-Language.
+func RequestLanguage(c *gin.Context, translator *locale.Translator, requested string) string {
+	language := translator.Negotiate(c.GetHeader("Accept-Language"))
+	if requested != "" && translator.Supports(requested) {
+		language = translator.Negotiate(requested)
+	}
+
+	c.Header("Content-Language", language)
+	return language
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
+	"github.com/gin-gonic/gin"
+)
+
+// TreeSource lists and returns the trees of one rule engine.
+type TreeSource interface {
+	GetAvailableTrees() []string
+	GetAssessmentTree(treeID string) (*domain.AssessmentTree, error)
+}
+
+type LocalizationController struct {
+	translator *locale.Translator
+	sources    map[domain.AgeGroup]TreeSource
+}
+
+// NewLocalizationController reports on the trees of each age group. Age
+// groups whose engine failed to start are simply left out.
+func NewLocalizationController(translator *locale.Translator, sources map[domain.AgeGroup]TreeSource) *LocalizationController {
+	return &LocalizationController{
+		translator: translator,
+		sources:    sources,
+	}
+}
+
+// LanguageCoverage sums the tree coverage of one language.
+type LanguageCoverage struct {
+	Language   string         `json:"language"`
+	Total      int            `json:"total"`
+	Translated int            `json:"translated"`
+	Percent    float64        `json:"percent"`
+	Trees      []TreeCoverage `json:"trees"`
+}
+
+// TreeCoverage is the coverage of one tree. Tree IDs are only unique within
+// an age group, e.g. developmental_assessment exists in both.
+type TreeCoverage struct {
+	AgeGroup domain.AgeGroup `json:"age_group"`
+	locale.TreeCoverage
+}
+
+func (lc *LocalizationController) ListLanguages(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"languages": lc.translator.Languages(),
+	})
+}
+
+// Coverage reports the untranslated strings of every tree, for one language
+// when ?lang= is given and otherwise for every non-English language.
+func (lc *LocalizationController) Coverage(c *gin.Context) {
+	var languages []string
+	if lang := c.Query("lang"); lang != "" {
+		if !lc.translator.HasCatalog(lang) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Unsupported language",
+				Message: "No translation catalogue for " + lang,
+				Code:    "validation_error",
+			})
+			return
+		}
+		languages = append(languages, lang)
+	} else {
+		for _, l := range lc.translator.Languages() {
+			if l.Code != locale.English {
+				languages = append(languages, l.Code)
+			}
+		}
+	}
+
+	trees := lc.trees()
+	report := make([]LanguageCoverage, 0, len(languages))
+	for _, lang := range languages {
+		summary := LanguageCoverage{Language: lang, Trees: make([]TreeCoverage, 0, len(trees))}
+		for _, tree := range trees {
+			coverage := lc.translator.Coverage(tree.tree, lang)
+			summary.Total += coverage.Total
+			summary.Translated += coverage.Translated
+			summary.Trees = append(summary.Trees, TreeCoverage{AgeGroup: tree.ageGroup, TreeCoverage: coverage})
+		}
+		if summary.Total > 0 {
+			summary.Percent = math.Round(float64(summary.Translated)/float64(summary.Total)*1000) / 10
+		}
+		report = append(report, summary)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"coverage": report,
+	})
+}
+
+type groupTree struct {
+	ageGroup domain.AgeGroup
+	tree     *domain.AssessmentTree
+}
+
+// trees returns every tree ordered by age group and ID.
+func (lc *LocalizationController) trees() []groupTree {
+	var trees []groupTree
+	for ageGroup, source := range lc.sources {
+		for _, id := range source.GetAvailableTrees() {
+			tree, err := source.GetAssessmentTree(id)
+			if err != nil {
+				continue
+			}
+			trees = append(trees, groupTree{ageGroup: ageGroup, tree: tree})
+		}
+	}
+
+	sort.Slice(trees, func(i, j int) bool {
+		if trees[i].ageGroup != trees[j].ageGroup {
+			return trees[i].ageGroup > trees[j].ageGroup
+		}
+		return trees[i].tree.AssessmentID < trees[j].tree.AssessmentID
+	})
+	return trees
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
 	younginfantusecase "github.com/Afomiat/Digital-IMCI/ruleengine/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,18 +13,21 @@ import (
 
 type YoungInfantRuleEngineController struct {
 	youngInfantRuleEngineUsecase *younginfantusecase.YoungInfantRuleEngineUsecase
+	translator                   *locale.Translator
 }
 
-func NewYoungInfantRuleEngineController(youngInfantRuleEngineUsecase *younginfantusecase.YoungInfantRuleEngineUsecase) *YoungInfantRuleEngineController {
+func NewYoungInfantRuleEngineController(youngInfantRuleEngineUsecase *younginfantusecase.YoungInfantRuleEngineUsecase, translator *locale.Translator) *YoungInfantRuleEngineController {
 	return &YoungInfantRuleEngineController{
 		youngInfantRuleEngineUsecase: youngInfantRuleEngineUsecase,
+		translator:                   translator,
 	}
 }
 
 
 func (rc *YoungInfantRuleEngineController) StartAssessmentFlow(c *gin.Context) {
 	var req struct {
-		TreeID string `json:"tree_id" binding:"required"`
+		TreeID   string `json:"tree_id" binding:"required"`
+		Language string `json:"language"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -60,6 +64,7 @@ func (rc *YoungInfantRuleEngineController) StartAssessmentFlow(c *gin.Context) {
 	response, err := rc.youngInfantRuleEngineUsecase.StartAssessmentFlow(c.Request.Context(), domain.StartFlowRequest{
 		AssessmentID: assessmentID, 
 		TreeID:       req.TreeID,
+		Language:     RequestLanguage(c, rc.translator, req.Language),
 	}, mpID)
 
 	if err != nil {
@@ -80,7 +85,8 @@ func (rc *YoungInfantRuleEngineController) StartAssessmentFlow(c *gin.Context) {
 func (rc *YoungInfantRuleEngineController) SubmitAnswer(c *gin.Context) {
 	var req struct {
 		NodeID string      `json:"node_id" binding:"required"`
-		Answer interface{} `json:"answer" binding:"required"`
+		Answer   interface{} `json:"answer" binding:"required"`
+		Language string      `json:"language"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -118,6 +124,7 @@ func (rc *YoungInfantRuleEngineController) SubmitAnswer(c *gin.Context) {
 		AssessmentID: assessmentID, 
 		NodeID:       req.NodeID,
 		Answer:       req.Answer,
+		Language:     RequestLanguage(c, rc.translator, req.Language),
 	}, mpID)
 
 	if err != nil {
@@ -145,6 +152,7 @@ func (ctrl *YoungInfantRuleEngineController) ProcessBatchAssessment(c *gin.Conte
         })
         return
     }
+    req.Language = RequestLanguage(c, ctrl.translator, req.Language)
 
     medicalProfessionalIDInterface, exists := c.Get("medical_professional_id")
     if !exists {
@@ -187,7 +195,8 @@ func (ctrl *YoungInfantRuleEngineController) GetTreeQuestions(c *gin.Context) {
 		return
 	}
 
-	tree, err := ctrl.youngInfantRuleEngineUsecase.GetTreeQuestions(treeID)
+	language := RequestLanguage(c, ctrl.translator, "")
+	tree, err := ctrl.youngInfantRuleEngineUsecase.GetTreeQuestions(treeID, language)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get tree questions",
@@ -201,5 +210,6 @@ func (ctrl *YoungInfantRuleEngineController) GetTreeQuestions(c *gin.Context) {
 		"title":        tree.Title,
 		"instructions": tree.Instructions,
 		"questions":    tree.QuestionsFlow,
+		"language":     language,
 	})
 }
\ No newline at end of file
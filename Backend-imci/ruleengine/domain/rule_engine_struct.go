@@ -4,6 +4,7 @@ package domain
 import (
 	"time"
 
+	coredomain "github.com/Afomiat/Digital-IMCI/domain"
 	"github.com/google/uuid"
 )
 
@@ -102,31 +103,45 @@ type BatchProcessRequest struct {
 	AssessmentID uuid.UUID            `json:"assessment_id" binding:"required"`
 	TreeID       string               `json:"tree_id" binding:"required"`
 	Answers      map[string]interface{} `json:"answers" binding:"required"`
+	// Language is the caregiver's language for the mother advice. The
+	// Accept-Language header is used when it is empty.
+	Language     string               `json:"language,omitempty"`
 }
 
 type BatchProcessResponse struct {
 	AssessmentID  uuid.UUID            `json:"assessment_id"`
 	Classification *ClassificationResult `json:"classification,omitempty"`
 	Status         FlowStatus          `json:"status"`
+	VitalAlerts    []*coredomain.VitalAlert `json:"vital_alerts,omitempty"`
+	// Consistency holds the contradictions and escalations found across the
+	// visit once the tree is finished.
+	Consistency    []*coredomain.ConsistencyFinding `json:"consistency,omitempty"`
 }
 
 // Sequential Processing
 type StartFlowRequest struct {
 	AssessmentID uuid.UUID
 	TreeID       string `json:"tree_id" binding:"required"`
+	Language     string `json:"language,omitempty"`
 }
 
 type StartFlowResponse struct {
 	SessionID   uuid.UUID `json:"session_id"`
 	Question    *Question `json:"question,omitempty"`
-	IsComplete  bool      `json:"is_complete"`
-	CurrentNode string    `json:"current_node"`
+	// Classification is set when recorded data answered every question, so
+	// the tree finished as it started.
+	Classification *ClassificationResult `json:"classification,omitempty"`
+	IsComplete  bool       `json:"is_complete"`
+	CurrentNode string     `json:"current_node"`
+	Status      FlowStatus `json:"status"`
+	Consistency []*coredomain.ConsistencyFinding `json:"consistency,omitempty"`
 }
 
 type SubmitAnswerRequest struct {
 	AssessmentID uuid.UUID
 	NodeID       string      `json:"node_id" binding:"required"`
 	Answer       interface{} `json:"answer" binding:"required"`
+	Language     string      `json:"language,omitempty"`
 }
 
 type SubmitAnswerResponse struct {
@@ -136,4 +151,6 @@ type SubmitAnswerResponse struct {
 	IsComplete     bool                 `json:"is_complete"`
 	CurrentNode    string               `json:"current_node"`
 	Status         FlowStatus           `json:"status"`
+	VitalAlerts    []*coredomain.VitalAlert `json:"vital_alerts,omitempty"`
+	Consistency    []*coredomain.ConsistencyFinding `json:"consistency,omitempty"`
 }
\ No newline at end of file
@@ -114,6 +114,8 @@ func (re *ChildRuleEngine) SubmitAnswer(flow *domain.AssessmentFlow, nodeID stri
 			finalClassification = re.classifyTBAssessment(flow.Answers)
 		case "developmental_assessment":
 			finalClassification = re.classifyDevelopmentalAssessment(flow.Answers)
+		case "immunization_vitamin_status":
+			finalClassification = re.classifyImmunizationVitamin(flow.Answers)
 		default:
 			finalClassification = "NO_DANGER_SIGNS"
 		}
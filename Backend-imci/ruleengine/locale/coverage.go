@@ -0,0 +1,104 @@
+package locale
+
+import (
+	"math"
+
+	"github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+)
+
+// TreeCoverage reports how much of a tree is translated into one language.
+// Missing lists catalogue keys such as "questions.fever_present.question";
+// Unknown lists catalogue entries for nodes or outcomes the tree no longer
+// has, usually left behind by a rename.
+type TreeCoverage struct {
+	TreeID     string   `json:"tree_id"`
+	Language   string   `json:"language"`
+	Total      int      `json:"total"`
+	Translated int      `json:"translated"`
+	Percent    float64  `json:"percent"`
+	Missing    []string `json:"missing"`
+	Unknown    []string `json:"unknown,omitempty"`
+}
+
+// Coverage counts the translatable strings of a tree, skipping those that
+// are empty in English. Catalogues pending review are included so reviewers
+// can see what is left.
+func (t *Translator) Coverage(tree *domain.AssessmentTree, lang string) TreeCoverage {
+	report := TreeCoverage{TreeID: tree.AssessmentID, Language: normalize(lang), Missing: []string{}}
+	c, tc, ok := t.tree(tree.AssessmentID, lang, true)
+	if !ok {
+		c = &Catalog{}
+	}
+
+	check := func(key, english, translated string) {
+		if english == "" {
+			return
+		}
+		report.Total++
+		if translated != "" {
+			report.Translated++
+			return
+		}
+		report.Missing = append(report.Missing, key)
+	}
+
+	check("title", tree.Title, tc.Title)
+	check("instructions", tree.Instructions, tc.Instructions)
+
+	for _, question := range tree.QuestionsFlow {
+		qc := tc.Questions[question.NodeID]
+		prefix := "questions." + question.NodeID
+		check(prefix+".question", question.Question, qc.Question)
+		check(prefix+".instructions", question.Instructions, qc.Instructions)
+		for _, option := range question.Options {
+			check(prefix+".options."+option.Value, option.DisplayText, optionText(c, qc, option.Value))
+		}
+	}
+
+	for _, id := range sortedKeys(tree.Outcomes) {
+		check("outcomes."+id+".mother_advice", tree.Outcomes[id].MotherAdvice, tc.Outcomes[id].MotherAdvice)
+	}
+
+	report.Unknown = unknownKeys(tree, tc)
+
+	if report.Total > 0 {
+		report.Percent = math.Round(float64(report.Translated)/float64(report.Total)*1000) / 10
+	}
+	return report
+}
+
+func unknownKeys(tree *domain.AssessmentTree, tc TreeCatalog) []string {
+	questions := make(map[string]*domain.Question, len(tree.QuestionsFlow))
+	for i := range tree.QuestionsFlow {
+		questions[tree.QuestionsFlow[i].NodeID] = &tree.QuestionsFlow[i]
+	}
+
+	var unknown []string
+	for _, nodeID := range sortedKeys(tc.Questions) {
+		question, ok := questions[nodeID]
+		if !ok {
+			unknown = append(unknown, "questions."+nodeID)
+			continue
+		}
+		for _, value := range sortedKeys(tc.Questions[nodeID].Options) {
+			if !hasOption(question, value) {
+				unknown = append(unknown, "questions."+nodeID+".options."+value)
+			}
+		}
+	}
+	for _, id := range sortedKeys(tc.Outcomes) {
+		if _, ok := tree.Outcomes[id]; !ok {
+			unknown = append(unknown, "outcomes."+id)
+		}
+	}
+	return unknown
+}
+
+func hasOption(question *domain.Question, value string) bool {
+	for _, option := range question.Options {
+		if option.Value == value {
+			return true
+		}
+	}
+	return false
+}
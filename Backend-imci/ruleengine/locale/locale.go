@@ -0,0 +1,263 @@
+// Package locale translates assessment trees into the languages spoken by
+// caregivers. Trees are written in English in Go; catalogues hold the
+// translations keyed by tree, node and outcome ID, and anything missing falls
+// back to English.
+//
+// The Amharic, Afaan Oromo and Tigrinya catalogues only cover the child
+// general danger signs tree and await clinical review; the coverage report
+// lists what is left and each catalogue's review record says who approved it.
+// Only approved catalogues are served to caregivers.
+package locale
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// English is the language the trees are written in and the fallback for
+// every missing translation.
+const English = "en"
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// Catalog holds the translations for one language.
+type Catalog struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+	// Review records the clinical review of the translations. Clinical
+	// wording must be checked by a clinician fluent in the language before
+	// the catalogue is approved.
+	Review CatalogReview `json:"review"`
+	// Options translates option display texts shared by many questions,
+	// keyed by option value, e.g. "yes".
+	Options map[string]string      `json:"options,omitempty"`
+	Trees   map[string]TreeCatalog `json:"trees"`
+	// Counseling translates the counselling card wordings, keyed by item
+	// key, e.g. "home_fluids.diarrhoea".
+	Counseling map[string]string `json:"counseling,omitempty"`
+}
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+)
+
+// CatalogReview says who approved a catalogue's clinical content and when.
+// ReviewedBy and ReviewedAt are required once Status is approved.
+type CatalogReview struct {
+	Status     string `json:"status"`
+	ReviewedBy string `json:"reviewed_by,omitempty"`
+	ReviewedAt string `json:"reviewed_at,omitempty"`
+	Notes      string `json:"notes,omitempty"`
+}
+
+type TreeCatalog struct {
+	Title        string                     `json:"title,omitempty"`
+	Instructions string                     `json:"instructions,omitempty"`
+	Questions    map[string]QuestionCatalog `json:"questions,omitempty"`
+	Outcomes     map[string]OutcomeCatalog  `json:"outcomes,omitempty"`
+}
+
+type QuestionCatalog struct {
+	Question     string `json:"question,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+	// Options overrides the shared option texts for this question.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+type OutcomeCatalog struct {
+	MotherAdvice string `json:"mother_advice,omitempty"`
+}
+
+// Language describes a supported language. Review is empty for English,
+// the language the trees are written in.
+type Language struct {
+	Code   string         `json:"code"`
+	Name   string         `json:"name"`
+	Review *CatalogReview `json:"review,omitempty"`
+}
+
+// Translator looks up translations in a set of catalogues. A nil Translator
+// is valid and leaves everything in English.
+type Translator struct {
+	catalogs map[string]*Catalog
+}
+
+func NewTranslator(catalogs ...*Catalog) *Translator {
+	t := &Translator{catalogs: make(map[string]*Catalog)}
+	for _, c := range catalogs {
+		t.catalogs[normalize(c.Language)] = c
+	}
+	return t
+}
+
+// LoadTranslator reads the catalogues embedded in the binary.
+func LoadTranslator() (*Translator, error) {
+	entries, err := catalogFiles.ReadDir("catalogs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translation catalogues: %w", err)
+	}
+
+	var catalogs []*Catalog
+	for _, entry := range entries {
+		data, err := catalogFiles.ReadFile(path.Join("catalogs", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		c, err := ParseCatalog(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid translation catalogue %s: %w", entry.Name(), err)
+		}
+		catalogs = append(catalogs, c)
+	}
+
+	return NewTranslator(catalogs...), nil
+}
+
+// ParseCatalog decodes a catalogue and checks its review record.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Language == "" {
+		return nil, fmt.Errorf("no language")
+	}
+	switch c.Review.Status {
+	case ReviewPending:
+	case ReviewApproved:
+		if c.Review.ReviewedBy == "" || c.Review.ReviewedAt == "" {
+			return nil, fmt.Errorf("approved without reviewed_by and reviewed_at")
+		}
+	default:
+		return nil, fmt.Errorf("unknown review status %q", c.Review.Status)
+	}
+	return &c, nil
+}
+
+// Languages lists English followed by the catalogue languages.
+func (t *Translator) Languages() []Language {
+	languages := []Language{{Code: English, Name: "English"}}
+	if t == nil {
+		return languages
+	}
+
+	codes := make([]string, 0, len(t.catalogs))
+	for code := range t.catalogs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		review := t.catalogs[code].Review
+		languages = append(languages, Language{Code: code, Name: t.catalogs[code].Name, Review: &review})
+	}
+	return languages
+}
+
+// Supports reports whether lang is English or has an approved catalogue.
+// Catalogues pending review are never negotiated.
+func (t *Translator) Supports(lang string) bool {
+	if normalize(lang) == English {
+		return true
+	}
+	_, ok := t.catalog(lang, false)
+	return ok
+}
+
+// HasCatalog reports whether lang has a catalogue, approved or not.
+func (t *Translator) HasCatalog(lang string) bool {
+	_, ok := t.catalog(lang, true)
+	return ok
+}
+
+// Negotiate picks the best supported language from an Accept-Language
+// header, falling back to English. Region subtags are ignored, so am-ET
+// selects am.
+func (t *Translator) Negotiate(acceptLanguage string) string {
+	best, bestQ := English, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := parseWeightedTag(part)
+		if tag == "" || q <= bestQ {
+			continue
+		}
+		if lang := normalize(tag); t.Supports(lang) {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+func parseWeightedTag(part string) (string, float64) {
+	fields := strings.Split(part, ";")
+	tag := strings.TrimSpace(fields[0])
+	if tag == "" || tag == "*" {
+		return "", 0
+	}
+
+	q := 1.0
+	for _, param := range fields[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+		if err != nil {
+			return "", 0
+		}
+		q = parsed
+	}
+	return tag, q
+}
+
+// normalize reduces a language tag to its lower-case primary subtag.
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// catalog returns the catalogue for lang, leaving out catalogues pending
+// review unless pending is set.
+func (t *Translator) catalog(lang string, pending bool) (*Catalog, bool) {
+	if t == nil {
+		return nil, false
+	}
+	c, ok := t.catalogs[normalize(lang)]
+	if !ok || (!pending && c.Review.Status != ReviewApproved) {
+		return nil, false
+	}
+	return c, true
+}
+
+func (t *Translator) tree(treeID, lang string, pending bool) (*Catalog, TreeCatalog, bool) {
+	c, ok := t.catalog(lang, pending)
+	if !ok {
+		return nil, TreeCatalog{}, false
+	}
+	return c, c.Trees[treeID], true
+}
+
+func pick(translated, fallback string) string {
+	if translated != "" {
+		return translated
+	}
+	return fallback
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
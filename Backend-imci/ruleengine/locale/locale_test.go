@@ -0,0 +1,220 @@
+package locale_test
+
+import (
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTranslator() *locale.Translator {
+	return locale.NewTranslator(&locale.Catalog{
+		Language: "am",
+		Name:     "አማርኛ",
+		Review:   locale.CatalogReview{Status: locale.ReviewApproved, ReviewedBy: "Dr. A", ReviewedAt: "2026-01-10"},
+		Options:  map[string]string{"positive": "ፖዘቲቭ"},
+		Counseling: map[string]string{
+			"home_fluids.diarrhoea": "ተጨማሪ ፈሳሽ ይስጡ",
+		},
+		Trees: map[string]locale.TreeCatalog{
+			"fever": {
+				Title: "ትኩሳት",
+				Questions: map[string]locale.QuestionCatalog{
+					"test": {Question: "ምርመራ?", Options: map[string]string{"negative": "ኔጌቲቭ"}},
+				},
+				Outcomes: map[string]locale.OutcomeCatalog{
+					"MALARIA": {MotherAdvice: "መድሃኒቱን ይስጡ"},
+				},
+			},
+		},
+	})
+}
+
+func testTree() *domain.AssessmentTree {
+	return &domain.AssessmentTree{
+		AssessmentID: "fever",
+		Title:        "Fever",
+		Instructions: "Ask about fever",
+		QuestionsFlow: []domain.Question{
+			{
+				NodeID:   "test",
+				Question: "Test result?",
+				Options: []domain.Option{
+					{Value: "positive", DisplayText: "Positive"},
+					{Value: "negative", DisplayText: "Negative"},
+					{Value: "unknown", DisplayText: "Unknown"},
+				},
+			},
+		},
+		Outcomes: map[string]domain.Outcome{
+			"MALARIA":    {Classification: "MALARIA", MotherAdvice: "Give the medicine"},
+			"NO_MALARIA": {Classification: "FEVER: NO MALARIA", MotherAdvice: "Return if fever persists"},
+		},
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	translator := testTranslator()
+
+	cases := map[string]string{
+		"":                          "en",
+		"am":                        "am",
+		"am-ET,en;q=0.5":            "am",
+		"en-US,am;q=0.8":            "en",
+		"fr,am;q=0.3":               "am",
+		"ti,om;q=0.9":               "en",
+		"de;q=0.9, AM_et;q=0.95, *": "am",
+		"am;q=0":                    "en",
+	}
+	for header, expected := range cases {
+		assert.Equal(t, expected, translator.Negotiate(header), "Accept-Language %q", header)
+	}
+
+	var nilTranslator *locale.Translator
+	assert.Equal(t, "en", nilTranslator.Negotiate("am"))
+}
+
+// Translations pending clinical review must not reach caregivers, but the
+// coverage report still covers them.
+func TestPendingCatalogIsNotServed(t *testing.T) {
+	translator := locale.NewTranslator(&locale.Catalog{
+		Language: "am",
+		Review:   locale.CatalogReview{Status: locale.ReviewPending},
+		Trees:    map[string]locale.TreeCatalog{"fever": {Title: "ትኩሳት"}},
+	})
+	tree := testTree()
+
+	assert.False(t, translator.Supports("am"))
+	assert.True(t, translator.HasCatalog("am"))
+	assert.Equal(t, "en", translator.Negotiate("am-ET,en;q=0.5"))
+	assert.Same(t, tree, translator.Tree(tree, "am"))
+	assert.Equal(t, 1, translator.Coverage(tree, "am").Translated)
+}
+
+func TestTreeTranslationFallsBackToEnglish(t *testing.T) {
+	translator := testTranslator()
+	tree := testTree()
+
+	translated := translator.Tree(tree, "am-ET")
+	assert.Equal(t, "ትኩሳት", translated.Title)
+	assert.Equal(t, "Ask about fever", translated.Instructions)
+	assert.Equal(t, "ምርመራ?", translated.QuestionsFlow[0].Question)
+	assert.Equal(t, "ፖዘቲቭ", translated.QuestionsFlow[0].Options[0].DisplayText)
+	assert.Equal(t, "ኔጌቲቭ", translated.QuestionsFlow[0].Options[1].DisplayText)
+	assert.Equal(t, "Unknown", translated.QuestionsFlow[0].Options[2].DisplayText)
+	assert.Equal(t, "መድሃኒቱን ይስጡ", translated.Outcomes["MALARIA"].MotherAdvice)
+	assert.Equal(t, "Return if fever persists", translated.Outcomes["NO_MALARIA"].MotherAdvice)
+
+	// The shared tree must stay in English.
+	assert.Equal(t, "Fever", tree.Title)
+	assert.Equal(t, "Positive", tree.QuestionsFlow[0].Options[0].DisplayText)
+	assert.Equal(t, "Give the medicine", tree.Outcomes["MALARIA"].MotherAdvice)
+
+	assert.Same(t, tree, translator.Tree(tree, "en"))
+}
+
+func TestClassificationReportsAdviceLanguage(t *testing.T) {
+	translator := testTranslator()
+	tree := testTree()
+
+	result, lang := translator.Classification(tree, &domain.ClassificationResult{Classification: "MALARIA", MotherAdvice: "Give the medicine"}, "am")
+	assert.Equal(t, "am", lang)
+	assert.Equal(t, "መድሃኒቱን ይስጡ", result.MotherAdvice)
+
+	result, lang = translator.Classification(tree, &domain.ClassificationResult{Classification: "FEVER: NO MALARIA", MotherAdvice: "Return if fever persists"}, "am")
+	assert.Equal(t, "en", lang)
+	assert.Equal(t, "Return if fever persists", result.MotherAdvice)
+}
+
+func TestCoverage(t *testing.T) {
+	translator := testTranslator()
+
+	coverage := translator.Coverage(testTree(), "am")
+	assert.Equal(t, 8, coverage.Total)
+	assert.Equal(t, 5, coverage.Translated)
+	assert.Equal(t, 62.5, coverage.Percent)
+	assert.Equal(t, []string{"instructions", "questions.test.options.unknown", "outcomes.NO_MALARIA.mother_advice"}, coverage.Missing)
+	assert.Empty(t, coverage.Unknown)
+
+	renamed := testTree()
+	renamed.QuestionsFlow[0].NodeID = "malaria_test"
+	coverage = translator.Coverage(renamed, "am")
+	assert.Equal(t, []string{"questions.test"}, coverage.Unknown)
+}
+
+// Every catalogue entry must point at a tree, question and outcome that
+// exist, otherwise a renamed node silently drops its translation.
+func TestEmbeddedCataloguesMatchTrees(t *testing.T) {
+	translator, err := locale.LoadTranslator()
+	require.NoError(t, err)
+
+	languages := translator.Languages()
+	codes := make([]string, len(languages))
+	for i, l := range languages {
+		codes[i] = l.Code
+	}
+	assert.Equal(t, []string{"en", "am", "om", "ti"}, codes)
+
+	youngInfant, err := engine.NewYoungInfantRuleEngine()
+	require.NoError(t, err)
+	child, err := engine.NewChildRuleEngine()
+	require.NoError(t, err)
+
+	for _, lang := range codes[1:] {
+		for _, source := range []interface {
+			GetAvailableTrees() []string
+			GetAssessmentTree(string) (*domain.AssessmentTree, error)
+		}{youngInfant, child} {
+			for _, id := range source.GetAvailableTrees() {
+				tree, err := source.GetAssessmentTree(id)
+				require.NoError(t, err)
+				coverage := translator.Coverage(tree, lang)
+				assert.Empty(t, coverage.Unknown, "%s catalogue has stale keys for %s", lang, id)
+			}
+		}
+	}
+
+	tree, err := child.GetAssessmentTree("child_general_danger_signs")
+	require.NoError(t, err)
+	for _, lang := range []string{"am", "om", "ti"} {
+		coverage := translator.Coverage(tree, lang)
+		assert.Empty(t, coverage.Missing, "%s translation of %s is incomplete", lang, tree.AssessmentID)
+	}
+}
+
+// A catalogue may only be marked approved with the name of the clinician who
+// reviewed it and the date.
+func TestParseCatalogReview(t *testing.T) {
+	_, err := locale.ParseCatalog([]byte(`{"language": "am", "review": {"status": "pending"}}`))
+	assert.NoError(t, err)
+
+	_, err = locale.ParseCatalog([]byte(`{"language": "am", "review": {"status": "approved"}}`))
+	assert.Error(t, err)
+
+	_, err = locale.ParseCatalog([]byte(`{"language": "am", "review": {"status": "approved", "reviewed_by": "Dr. A", "reviewed_at": "2026-01-10"}}`))
+	assert.NoError(t, err)
+
+	_, err = locale.ParseCatalog([]byte(`{"language": "am"}`))
+	assert.Error(t, err, "the review status is required")
+
+	translator, err := locale.LoadTranslator()
+	require.NoError(t, err)
+	for _, l := range translator.Languages()[1:] {
+		require.NotNil(t, l.Review, "%s has no review record", l.Code)
+	}
+}
+
+func TestCounselingText(t *testing.T) {
+	translator := testTranslator()
+
+	text, lang := translator.CounselingText("home_fluids.diarrhoea", "Give extra fluid", "am")
+	assert.Equal(t, "am", lang)
+	assert.Equal(t, "ተጨማሪ ፈሳሽ ይስጡ", text)
+
+	text, lang = translator.CounselingText("when_to_return.child", "Return immediately", "am")
+	assert.Equal(t, "en", lang)
+	assert.Equal(t, "Return immediately", text)
+}
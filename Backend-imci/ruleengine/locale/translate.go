@@ -0,0 +1,119 @@
+package locale
+
+import (
+	"github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+)
+
+// Tree returns a copy of tree with its title, instructions, questions and
+// mother advice translated. The tree itself is shared by every flow and is
+// never modified.
+func (t *Translator) Tree(tree *domain.AssessmentTree, lang string) *domain.AssessmentTree {
+	if tree == nil {
+		return nil
+	}
+	c, tc, ok := t.tree(tree.AssessmentID, lang, false)
+	if !ok {
+		return tree
+	}
+
+	out := *tree
+	out.Title = pick(tc.Title, tree.Title)
+	out.Instructions = pick(tc.Instructions, tree.Instructions)
+
+	out.QuestionsFlow = make([]domain.Question, len(tree.QuestionsFlow))
+	for i := range tree.QuestionsFlow {
+		out.QuestionsFlow[i] = *translateQuestion(c, tc, &tree.QuestionsFlow[i])
+	}
+
+	out.Outcomes = make(map[string]domain.Outcome, len(tree.Outcomes))
+	for id, outcome := range tree.Outcomes {
+		outcome.MotherAdvice = pick(tc.Outcomes[id].MotherAdvice, outcome.MotherAdvice)
+		out.Outcomes[id] = outcome
+	}
+
+	return &out
+}
+
+// Question returns a translated copy of a question from the given tree.
+func (t *Translator) Question(treeID string, question *domain.Question, lang string) *domain.Question {
+	if question == nil {
+		return nil
+	}
+	c, tc, ok := t.tree(treeID, lang, false)
+	if !ok {
+		return question
+	}
+	return translateQuestion(c, tc, question)
+}
+
+func translateQuestion(c *Catalog, tc TreeCatalog, question *domain.Question) *domain.Question {
+	qc := tc.Questions[question.NodeID]
+
+	out := *question
+	out.Question = pick(qc.Question, question.Question)
+	out.Instructions = pick(qc.Instructions, question.Instructions)
+
+	if len(question.Options) > 0 {
+		out.Options = make([]domain.Option, len(question.Options))
+		for i, option := range question.Options {
+			option.DisplayText = pick(optionText(c, qc, option.Value), option.DisplayText)
+			out.Options[i] = option
+		}
+	}
+
+	return &out
+}
+
+func optionText(c *Catalog, qc QuestionCatalog, value string) string {
+	if text := qc.Options[value]; text != "" {
+		return text
+	}
+	return c.Options[value]
+}
+
+// Classification returns a copy of result with the mother advice translated,
+// together with the language the advice ended up in. The outcome is matched
+// by classification name since results do not carry the outcome ID.
+func (t *Translator) Classification(tree *domain.AssessmentTree, result *domain.ClassificationResult, lang string) (*domain.ClassificationResult, string) {
+	if result == nil || tree == nil {
+		return result, English
+	}
+	_, tc, ok := t.tree(tree.AssessmentID, lang, false)
+	if !ok {
+		return result, English
+	}
+
+	advice := tc.Outcomes[outcomeID(tree, result)].MotherAdvice
+	if advice == "" {
+		return result, English
+	}
+
+	out := *result
+	out.MotherAdvice = advice
+	return &out, normalize(lang)
+}
+
+func outcomeID(tree *domain.AssessmentTree, result *domain.ClassificationResult) string {
+	var byName string
+	for id, outcome := range tree.Outcomes {
+		if outcome.Classification != result.Classification {
+			continue
+		}
+		if outcome.MotherAdvice == result.MotherAdvice {
+			return id
+		}
+		byName = id
+	}
+	return byName
+}
+
+// CounselingText returns the translation of a counselling card wording and
+// the language it is in, or the English text and English when the catalogue
+// has none.
+func (t *Translator) CounselingText(key, english, lang string) (string, string) {
+	c, ok := t.catalog(lang, false)
+	if !ok || c.Counseling[key] == "" {
+		return english, English
+	}
+	return c.Counseling[key], normalize(lang)
+}
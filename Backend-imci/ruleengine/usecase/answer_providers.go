@@ -0,0 +1,72 @@
+// ruleengine/usecase/answer_providers.go
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+)
+
+// collectProvidedAnswers asks every provider for the answers it can derive
+// for treeID. Earlier providers win when two answer the same node.
+func collectProvidedAnswers(ctx context.Context, providers []domain.TreeAnswerProvider, assessment *domain.Assessment, treeID string) (map[string]interface{}, error) {
+	provided := make(map[string]interface{})
+	for _, provider := range providers {
+		answers, err := provider.ProvideAnswers(ctx, assessment, treeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive answers for %s: %w", treeID, err)
+		}
+		for nodeID, answer := range answers {
+			if _, exists := provided[nodeID]; !exists {
+				provided[nodeID] = answer
+			}
+		}
+	}
+	return provided, nil
+}
+
+// autoAnswer submits provided answers for as long as the next question in the
+// flow is one the providers could answer, and returns the first question the
+// clinician still has to answer.
+func autoAnswer(re engine.RuleEngineInterface, flow *ruleenginedomain.AssessmentFlow, question *ruleenginedomain.Question, provided map[string]interface{}) (*ruleenginedomain.AssessmentFlow, *ruleenginedomain.Question, error) {
+	for question != nil && flow.Status == ruleenginedomain.FlowStatusInProgress {
+		answer, ok := provided[question.NodeID]
+		if !ok || !withinValidation(question, answer) {
+			break
+		}
+		if _, answered := flow.Answers[question.NodeID]; answered {
+			break
+		}
+
+		var err error
+		flow, question, err = re.SubmitAnswer(flow, question.NodeID, answer)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return flow, question, nil
+}
+
+// fillProvidedAnswers adds provided answers that the caller did not send in
+// a batch request.
+func fillProvidedAnswers(answers map[string]interface{}, provided map[string]interface{}) {
+	for nodeID, answer := range provided {
+		if _, exists := answers[nodeID]; !exists {
+			answers[nodeID] = answer
+		}
+	}
+}
+
+func withinValidation(question *ruleenginedomain.Question, answer interface{}) bool {
+	if question.Validation == nil {
+		return true
+	}
+	value, ok := answer.(float64)
+	if !ok {
+		return true
+	}
+	return value >= question.Validation.Min && value <= question.Validation.Max
+}
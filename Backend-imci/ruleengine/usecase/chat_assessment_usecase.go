@@ -0,0 +1,602 @@
+// ruleengine/usecase/chat_assessment_usecase.go
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/google/uuid"
+)
+
+// Conversation steps.
+const (
+	chatStepPatient  = "choose_patient"
+	chatStepWeight   = "weight"
+	chatStepTree     = "choose_tree"
+	chatStepQuestion = "question"
+	chatStepResult   = "result"
+)
+
+// Button ID prefixes and fixed IDs.
+const (
+	chatPatientPrefix = "patient:"
+	chatTreePrefix    = "tree:"
+	chatAnswerPrefix  = "ans:"
+	chatNext          = "next"
+	chatDone          = "done"
+)
+
+const (
+	chatSessionTTL   = 2 * time.Hour
+	chatPatientLimit = 5
+)
+
+// Trees in the order of the IMCI chart booklet.
+var (
+	youngInfantChatTrees = []string{
+		"birth_asphyxia_check",
+		"very_severe_disease_check",
+		"jaundice_check",
+		"diarrhea_check",
+		"feeding_problem_underweight_check",
+		"replacement_feeding_check",
+		"hiv_status_assessment",
+		"gestation_classification",
+		"developmental_assessment",
+	}
+	childChatTrees = []string{
+		"child_general_danger_signs",
+		"child_cough_difficult_breathing",
+		"child_diarrhea",
+		"child_fever",
+		"child_ear_problem",
+		"child_anemia_check",
+		"acute_malnutrition",
+		"feeding_assessment",
+		"hiv_assessment",
+		"tb_assessment",
+		"immunization_vitamin_status",
+		"developmental_assessment",
+	}
+)
+
+// FlowRunner is the part of a rule engine use case the chat adapter drives.
+// Both ChildRuleEngineUsecase and YoungInfantRuleEngineUsecase implement it.
+type FlowRunner interface {
+	StartAssessmentFlow(ctx context.Context, req ruleenginedomain.StartFlowRequest, medicalProfessionalID uuid.UUID) (*ruleenginedomain.StartFlowResponse, error)
+	SubmitAnswer(ctx context.Context, req ruleenginedomain.SubmitAnswerRequest, medicalProfessionalID uuid.UUID) (*ruleenginedomain.SubmitAnswerResponse, error)
+	GetAssessmentTree(treeID string) (*ruleenginedomain.AssessmentTree, error)
+}
+
+type ChatAssessmentUsecase struct {
+	assessmentUsecase domain.AssessmentUsecase
+	assessmentRepo    domain.AssessmentRepository
+	patientRepo       domain.PatientRepository
+	sessions          domain.ChatSessionStore
+	runners           map[domain.AssessmentType]FlowRunner
+	contextTimeout    time.Duration
+}
+
+// NewChatAssessmentUsecase builds the conversational adapter. Either runner
+// may be nil when its rule engine failed to load.
+func NewChatAssessmentUsecase(
+	assessmentUsecase domain.AssessmentUsecase,
+	assessmentRepo domain.AssessmentRepository,
+	patientRepo domain.PatientRepository,
+	sessions domain.ChatSessionStore,
+	youngInfantRunner FlowRunner,
+	childRunner FlowRunner,
+	timeout time.Duration,
+) domain.ChatAssessmentUsecase {
+	runners := make(map[domain.AssessmentType]FlowRunner)
+	if youngInfantRunner != nil {
+		runners[domain.TypeYoungInfant] = youngInfantRunner
+	}
+	if childRunner != nil {
+		runners[domain.TypeChild] = childRunner
+	}
+
+	return &ChatAssessmentUsecase{
+		assessmentUsecase: assessmentUsecase,
+		assessmentRepo:    assessmentRepo,
+		patientRepo:       patientRepo,
+		sessions:          sessions,
+		runners:           runners,
+		contextTimeout:    timeout,
+	}
+}
+
+func (uc *ChatAssessmentUsecase) HandleMessage(ctx context.Context, conversationID string, mp *domain.MedicalProfessional, text string) ([]*domain.ChatReply, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	text = strings.TrimSpace(text)
+	switch command, arg := parseChatCommand(text); command {
+	case "assess":
+		return uc.start(ctx, conversationID, mp, arg)
+	case "cancel":
+		if err := uc.sessions.Delete(ctx, conversationID); err != nil {
+			return nil, err
+		}
+		return replies("Assessment chat closed. Anything already classified has been saved."), nil
+	}
+
+	session, err := uc.sessions.Get(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	// A linked chat belongs to one clinician; refuse to continue someone
+	// else's session if the link changed mid-assessment.
+	if session.MedicalProfessionalID != mp.ID {
+		return nil, domain.ErrChatSessionNotFound
+	}
+
+	input := resolveChoice(session, text)
+	step, nodeID := session.Step, session.NodeID
+
+	var out []*domain.ChatReply
+	switch session.Step {
+	case chatStepPatient:
+		out, err = uc.choosePatient(ctx, session, input)
+	case chatStepWeight:
+		out, err = uc.recordWeight(ctx, session, mp, input)
+	case chatStepTree:
+		out, err = uc.chooseTree(ctx, session, mp, input)
+	case chatStepQuestion:
+		out, err = uc.answer(ctx, session, mp, input)
+	case chatStepResult:
+		out, err = uc.afterResult(ctx, conversationID, session, input)
+		if err == nil && session.Step == "" {
+			return out, nil
+		}
+	default:
+		return nil, domain.ErrChatSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A re-prompt without buttons keeps the earlier choices valid.
+	if session.Step != step || session.NodeID != nodeID {
+		session.Choices = nil
+	}
+	return out, uc.save(ctx, conversationID, session, out)
+}
+
+func (uc *ChatAssessmentUsecase) start(ctx context.Context, conversationID string, mp *domain.MedicalProfessional, arg string) ([]*domain.ChatReply, error) {
+	if len(uc.runners) == 0 {
+		return nil, domain.ErrChatUnavailable
+	}
+
+	session := &domain.ChatSession{MedicalProfessionalID: mp.ID}
+
+	var out []*domain.ChatReply
+	if assessmentID, err := uuid.Parse(arg); err == nil {
+		assessment, err := uc.assessmentUsecase.GetAssessment(ctx, assessmentID, mp.ID)
+		if err != nil {
+			return nil, err
+		}
+		session.AssessmentID = assessment.ID
+		session.PatientID = assessment.PatientID
+		session.AssessmentType = assessment.AssessmentType
+		session.Step = chatStepTree
+		out = uc.treeMenu(session, "Continuing the assessment.")
+	} else {
+		if arg == "" {
+			return replies("Send \"assess <patient name>\" to start, or \"assess <assessment id>\" to continue an assessment."), nil
+		}
+
+		patients, err := uc.ownPatients(ctx, mp.ID, arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(patients) == 0 {
+			return replies(fmt.Sprintf("None of your patients is named \"%s\". Start a new patient's first assessment in the app.", arg)), nil
+		}
+
+		reply := &domain.ChatReply{Text: "Which patient are you assessing?"}
+		for _, p := range patients {
+			reply.Buttons = append(reply.Buttons, domain.ChatButton{
+				ID:    chatPatientPrefix + p.ID.String(),
+				Title: fmt.Sprintf("%s (born %s)", p.Name, p.DateOfBirth.Format("2 Jan 2006")),
+			})
+		}
+		session.Step = chatStepPatient
+		out = []*domain.ChatReply{reply}
+	}
+
+	return out, uc.save(ctx, conversationID, session, out)
+}
+
+func (uc *ChatAssessmentUsecase) choosePatient(ctx context.Context, session *domain.ChatSession, input string) ([]*domain.ChatReply, error) {
+	patientID, err := uuid.Parse(strings.TrimPrefix(input, chatPatientPrefix))
+	if !strings.HasPrefix(input, chatPatientPrefix) || err != nil || !offered(session, input) {
+		return replies("Please pick one of the patients above, or send \"cancel\"."), nil
+	}
+
+	session.PatientID = patientID
+	session.Step = chatStepWeight
+	return replies("What is the child's weight in kg? (e.g. 7.5)"), nil
+}
+
+// ownPatients finds patients by name among those the clinician has assessed,
+// so the bot cannot be used to browse other facilities' records.
+func (uc *ChatAssessmentUsecase) ownPatients(ctx context.Context, medicalProfessionalID uuid.UUID, name string) ([]*domain.Patient, error) {
+	patients, err := uc.patientRepo.SearchByName(ctx, name, chatPatientLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	own := []*domain.Patient{}
+	for _, patient := range patients {
+		assessments, err := uc.assessmentRepo.GetByPatientID(ctx, patient.ID, medicalProfessionalID)
+		if err != nil {
+			return nil, err
+		}
+		if len(assessments) > 0 {
+			own = append(own, patient)
+		}
+	}
+	return own, nil
+}
+
+// offered reports whether id was one of the buttons last sent, so a typed
+// patient ID cannot pick a patient the search did not return.
+func offered(session *domain.ChatSession, id string) bool {
+	for _, choice := range session.Choices {
+		if choice == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *ChatAssessmentUsecase) recordWeight(ctx context.Context, session *domain.ChatSession, mp *domain.MedicalProfessional, input string) ([]*domain.ChatReply, error) {
+	weight, err := parseChatNumber(input)
+	if err != nil {
+		return replies("Please send the weight as a number of kg, e.g. 7.5"), nil
+	}
+
+	assessment, err := uc.assessmentUsecase.CreateAssessment(ctx, &domain.CreateAssessmentRequest{
+		PatientID: session.PatientID,
+		WeightKg:  weight,
+	}, mp.ID)
+	if errors.Is(err, domain.ErrInvalidWeight) {
+		return replies(fmt.Sprintf("%.1f kg is outside the plausible range for this age. Please check and send the weight again.", weight)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.AssessmentID = assessment.ID
+	session.AssessmentType = assessment.AssessmentType
+	session.Step = chatStepTree
+	return uc.treeMenu(session, "Assessment created."), nil
+}
+
+func (uc *ChatAssessmentUsecase) chooseTree(ctx context.Context, session *domain.ChatSession, mp *domain.MedicalProfessional, input string) ([]*domain.ChatReply, error) {
+	runner, ok := uc.runners[session.AssessmentType]
+	if !ok {
+		return nil, domain.ErrChatUnavailable
+	}
+
+	treeID := strings.TrimPrefix(input, chatTreePrefix)
+	if !strings.HasPrefix(input, chatTreePrefix) {
+		return uc.treeMenu(session, "Please pick what to assess."), nil
+	}
+
+	resp, err := runner.StartAssessmentFlow(ctx, ruleenginedomain.StartFlowRequest{
+		AssessmentID: session.AssessmentID,
+		TreeID:       treeID,
+	}, mp.ID)
+	if errors.Is(err, engine.ErrTreeNotFound) {
+		return uc.treeMenu(session, "Please pick what to assess."), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.TreeID = treeID
+	if resp.IsComplete || resp.Question == nil {
+		return uc.result(session, resp.Classification), nil
+	}
+	return uc.ask(session, resp.Question, ""), nil
+}
+
+func (uc *ChatAssessmentUsecase) answer(ctx context.Context, session *domain.ChatSession, mp *domain.MedicalProfessional, input string) ([]*domain.ChatReply, error) {
+	runner, ok := uc.runners[session.AssessmentType]
+	if !ok {
+		return nil, domain.ErrChatUnavailable
+	}
+
+	question, err := currentQuestion(runner, session)
+	if err != nil {
+		return nil, err
+	}
+
+	var answer interface{}
+	if isNumericQuestion(question) {
+		value, err := parseChatNumber(input)
+		if err != nil || !withinValidation(question, value) {
+			return uc.ask(session, question, "Please send a number"+validationHint(question)+"."), nil
+		}
+		answer = value
+	} else {
+		answer = strings.TrimPrefix(input, chatAnswerPrefix)
+	}
+
+	resp, err := runner.SubmitAnswer(ctx, ruleenginedomain.SubmitAnswerRequest{
+		AssessmentID: session.AssessmentID,
+		NodeID:       question.NodeID,
+		Answer:       answer,
+	}, mp.ID)
+	if errors.Is(err, engine.ErrInvalidAnswer) {
+		return uc.ask(session, question, "Please choose one of the options."), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsComplete || resp.Question == nil {
+		return uc.result(session, resp.Classification), nil
+	}
+	return uc.ask(session, resp.Question, ""), nil
+}
+
+func (uc *ChatAssessmentUsecase) afterResult(ctx context.Context, conversationID string, session *domain.ChatSession, input string) ([]*domain.ChatReply, error) {
+	switch input {
+	case chatNext:
+		session.Step = chatStepTree
+		return uc.treeMenu(session, ""), nil
+	case chatDone:
+		if err := uc.sessions.Delete(ctx, conversationID); err != nil {
+			return nil, err
+		}
+		session.Step = ""
+		return replies(fmt.Sprintf("Assessment saved. Assessment ID: %s", session.AssessmentID)), nil
+	default:
+		return []*domain.ChatReply{resultMenu("What next?")}, nil
+	}
+}
+
+// result reports the classification of the tree just finished.
+func (uc *ChatAssessmentUsecase) result(session *domain.ChatSession, classification *ruleenginedomain.ClassificationResult) []*domain.ChatReply {
+	session.Step = chatStepResult
+	session.NodeID = ""
+
+	if classification == nil {
+		return []*domain.ChatReply{resultMenu("This assessment finished without a classification.")}
+	}
+	return []*domain.ChatReply{
+		{Text: formatClassification(classification)},
+		resultMenu("What next?"),
+	}
+}
+
+func (uc *ChatAssessmentUsecase) treeMenu(session *domain.ChatSession, intro string) []*domain.ChatReply {
+	runner := uc.runners[session.AssessmentType]
+	order := childChatTrees
+	if session.AssessmentType == domain.TypeYoungInfant {
+		order = youngInfantChatTrees
+	}
+
+	text := "What do you want to assess?"
+	if intro != "" {
+		text = intro + "\n\n" + text
+	}
+	reply := &domain.ChatReply{Text: text}
+	if runner == nil {
+		reply.Text = "Decision support for this age group is not available right now."
+		return []*domain.ChatReply{reply}
+	}
+
+	for _, treeID := range order {
+		tree, err := runner.GetAssessmentTree(treeID)
+		if err != nil {
+			continue
+		}
+		reply.Buttons = append(reply.Buttons, domain.ChatButton{ID: chatTreePrefix + treeID, Title: tree.Title})
+	}
+	return []*domain.ChatReply{reply}
+}
+
+// ask renders a question. Choice questions get one button per answer;
+// numeric questions expect the value as the next message.
+func (uc *ChatAssessmentUsecase) ask(session *domain.ChatSession, question *ruleenginedomain.Question, prefix string) []*domain.ChatReply {
+	session.Step = chatStepQuestion
+	session.NodeID = question.NodeID
+
+	var b strings.Builder
+	if prefix != "" {
+		b.WriteString(prefix + "\n\n")
+	}
+	b.WriteString(question.Question)
+	if question.Instructions != "" {
+		b.WriteString("\n" + question.Instructions)
+	}
+
+	reply := &domain.ChatReply{}
+	if isNumericQuestion(question) {
+		b.WriteString("\n\nReply with a number" + validationHint(question) + ".")
+	} else {
+		reply.Buttons = questionButtons(question)
+	}
+	reply.Text = b.String()
+
+	return []*domain.ChatReply{reply}
+}
+
+func (uc *ChatAssessmentUsecase) save(ctx context.Context, conversationID string, session *domain.ChatSession, out []*domain.ChatReply) error {
+	if len(out) > 0 && len(out[len(out)-1].Buttons) > 0 {
+		session.Choices = nil
+		for _, button := range out[len(out)-1].Buttons {
+			session.Choices = append(session.Choices, button.ID)
+		}
+	}
+	session.UpdatedAt = time.Now()
+	return uc.sessions.Save(ctx, conversationID, session, chatSessionTTL)
+}
+
+func currentQuestion(runner FlowRunner, session *domain.ChatSession) (*ruleenginedomain.Question, error) {
+	tree, err := runner.GetAssessmentTree(session.TreeID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tree.QuestionsFlow {
+		if tree.QuestionsFlow[i].NodeID == session.NodeID {
+			return &tree.QuestionsFlow[i], nil
+		}
+	}
+	return nil, engine.ErrQuestionNotFound
+}
+
+func questionButtons(question *ruleenginedomain.Question) []domain.ChatButton {
+	var buttons []domain.ChatButton
+	if len(question.Options) > 0 {
+		for _, option := range question.Options {
+			buttons = append(buttons, domain.ChatButton{ID: chatAnswerPrefix + option.Value, Title: option.DisplayText})
+		}
+		return buttons
+	}
+
+	values := make([]string, 0, len(question.Answers))
+	for value := range question.Answers {
+		if value != "*" && value != "value_based" {
+			values = append(values, value)
+		}
+	}
+	// "yes" before "no", everything else alphabetically.
+	sort.Slice(values, func(i, j int) bool {
+		if values[i] == "yes" || values[j] == "yes" {
+			return values[i] == "yes"
+		}
+		return values[i] < values[j]
+	})
+
+	for _, value := range values {
+		buttons = append(buttons, domain.ChatButton{ID: chatAnswerPrefix + value, Title: answerTitle(value)})
+	}
+	return buttons
+}
+
+func answerTitle(value string) string {
+	title := strings.ReplaceAll(value, "_", " ")
+	if title == "" {
+		return value
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+func isNumericQuestion(question *ruleenginedomain.Question) bool {
+	return question.QuestionType == "number_input" || question.QuestionType == "number"
+}
+
+func validationHint(question *ruleenginedomain.Question) string {
+	if question.Validation == nil || question.Validation.Max == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" between %g and %g", question.Validation.Min, question.Validation.Max)
+}
+
+func formatClassification(c *ruleenginedomain.ClassificationResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", colorLabel(c.Color), c.Classification)
+	if c.Emergency {
+		b.WriteString("\nURGENT REFERRAL")
+	}
+	if c.TreatmentPlan != "" {
+		b.WriteString("\n\nTreatment: " + c.TreatmentPlan)
+	}
+	for _, action := range c.Actions {
+		b.WriteString("\n- " + action)
+	}
+	if c.MotherAdvice != "" {
+		b.WriteString("\n\nAdvise the mother: " + c.MotherAdvice)
+	}
+	if len(c.FollowUp) > 0 {
+		b.WriteString("\n\nFollow-up: " + strings.Join(c.FollowUp, "; "))
+	}
+	return b.String()
+}
+
+func colorLabel(color string) string {
+	switch strings.ToLower(color) {
+	case "pink", "red":
+		return "[PINK]"
+	case "yellow":
+		return "[YELLOW]"
+	case "green":
+		return "[GREEN]"
+	default:
+		return "[" + strings.ToUpper(color) + "]"
+	}
+}
+
+func resultMenu(text string) *domain.ChatReply {
+	return &domain.ChatReply{
+		Text: text,
+		Buttons: []domain.ChatButton{
+			{ID: chatNext, Title: "Assess another"},
+			{ID: chatDone, Title: "Finish"},
+		},
+	}
+}
+
+func replies(texts ...string) []*domain.ChatReply {
+	out := make([]*domain.ChatReply, len(texts))
+	for i, text := range texts {
+		out[i] = &domain.ChatReply{Text: text}
+	}
+	return out
+}
+
+// parseChatCommand recognises "assess ..." and "cancel", with or without a
+// leading slash, since WhatsApp has no bot commands.
+func parseChatCommand(text string) (string, string) {
+	fields := strings.SplitN(text, " ", 2)
+	command := strings.ToLower(strings.TrimPrefix(fields[0], "/"))
+	// Telegram appends the bot name in groups: /assess@imci_bot.
+	if i := strings.Index(command, "@"); i >= 0 {
+		command = command[:i]
+	}
+
+	switch command {
+	case "assess", "cancel":
+		if len(fields) == 2 {
+			return command, strings.TrimSpace(fields[1])
+		}
+		return command, ""
+	}
+	return "", ""
+}
+
+// resolveChoice maps a typed reply onto one of the buttons last offered: a
+// 1-based index or an exact button ID. Anything else is returned unchanged.
+func resolveChoice(session *domain.ChatSession, text string) string {
+	if len(session.Choices) == 0 {
+		return text
+	}
+	if i, err := strconv.Atoi(text); err == nil && i >= 1 && i <= len(session.Choices) {
+		return session.Choices[i-1]
+	}
+	for _, id := range session.Choices {
+		if strings.EqualFold(text, id) || strings.EqualFold(chatAnswerPrefix+text, id) {
+			return id
+		}
+	}
+	return text
+}
+
+// parseChatNumber accepts "7.5", "7,5" and "7.5 kg".
+func parseChatNumber(input string) (float64, error) {
+	input = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(input)), "kg")
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(input), ",", "."), 64)
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/repository"
+	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var chatTestTree = &ruleenginedomain.AssessmentTree{
+	AssessmentID: "child_fever",
+	Title:        "Fever",
+	StartNode:    "fever_present",
+	QuestionsFlow: []ruleenginedomain.Question{
+		{
+			NodeID:       "fever_present",
+			Question:     "Does the child have fever?",
+			QuestionType: "yes_no",
+			Answers: map[string]ruleenginedomain.Answer{
+				"yes": {NextNode: "fever_days"},
+				"no":  {Classification: "NO_FEVER"},
+			},
+		},
+		{
+			NodeID:       "fever_days",
+			Question:     "For how many days?",
+			QuestionType: "number_input",
+			Validation:   &ruleenginedomain.Validation{Min: 1, Max: 30},
+			Answers: map[string]ruleenginedomain.Answer{
+				"value_based": {Classification: "FEVER"},
+			},
+		},
+	},
+	Outcomes: map[string]ruleenginedomain.Outcome{
+		"FEVER":    {Classification: "FEVER", Color: "yellow", MotherAdvice: "Give fluids", FollowUp: []string{"Follow up in 3 days"}},
+		"NO_FEVER": {Classification: "NO FEVER", Color: "green"},
+	},
+}
+
+// fakeRunner walks chatTestTree without touching a database.
+type fakeRunner struct{}
+
+func (fakeRunner) GetAssessmentTree(treeID string) (*ruleenginedomain.AssessmentTree, error) {
+	if treeID != chatTestTree.AssessmentID {
+		return nil, engine.ErrTreeNotFound
+	}
+	return chatTestTree, nil
+}
+
+func (fakeRunner) StartAssessmentFlow(ctx context.Context, req ruleenginedomain.StartFlowRequest, mpID uuid.UUID) (*ruleenginedomain.StartFlowResponse, error) {
+	if req.TreeID != chatTestTree.AssessmentID {
+		return nil, engine.ErrTreeNotFound
+	}
+	return &ruleenginedomain.StartFlowResponse{Question: &chatTestTree.QuestionsFlow[0], CurrentNode: chatTestTree.StartNode}, nil
+}
+
+func (fakeRunner) SubmitAnswer(ctx context.Context, req ruleenginedomain.SubmitAnswerRequest, mpID uuid.UUID) (*ruleenginedomain.SubmitAnswerResponse, error) {
+	for i := range chatTestTree.QuestionsFlow {
+		question := &chatTestTree.QuestionsFlow[i]
+		if question.NodeID != req.NodeID {
+			continue
+		}
+		key, _ := req.Answer.(string)
+		if question.QuestionType == "number_input" {
+			key = "value_based"
+		}
+		answer, ok := question.Answers[key]
+		if !ok {
+			return nil, engine.ErrInvalidAnswer
+		}
+		if answer.NextNode != "" {
+			return &ruleenginedomain.SubmitAnswerResponse{Question: &chatTestTree.QuestionsFlow[1], CurrentNode: answer.NextNode}, nil
+		}
+		outcome := chatTestTree.Outcomes[answer.Classification]
+		return &ruleenginedomain.SubmitAnswerResponse{
+			IsComplete: true,
+			Classification: &ruleenginedomain.ClassificationResult{
+				Classification: outcome.Classification,
+				Color:          outcome.Color,
+				MotherAdvice:   outcome.MotherAdvice,
+				FollowUp:       outcome.FollowUp,
+			},
+		}, nil
+	}
+	return nil, engine.ErrQuestionNotFound
+}
+
+type fakeChatAssessments struct {
+	created *domain.CreateAssessmentRequest
+}
+
+func (f *fakeChatAssessments) CreateAssessment(ctx context.Context, req *domain.CreateAssessmentRequest, mpID uuid.UUID) (*domain.Assessment, error) {
+	if req.WeightKg > 30 {
+		return nil, domain.ErrInvalidWeight
+	}
+	f.created = req
+	return &domain.Assessment{ID: uuid.New(), PatientID: req.PatientID, AssessmentType: domain.TypeChild}, nil
+}
+
+func (f *fakeChatAssessments) GetAssessment(ctx context.Context, id uuid.UUID, mpID uuid.UUID) (*domain.Assessment, error) {
+	return nil, domain.ErrAssessmentNotFound
+}
+
+func (f *fakeChatAssessments) GetAssessmentsByPatient(ctx context.Context, patientID uuid.UUID, mpID uuid.UUID) ([]*domain.Assessment, error) {
+	return nil, nil
+}
+
+func (f *fakeChatAssessments) UpdateAssessment(ctx context.Context, assessment *domain.Assessment) error {
+	return nil
+}
+
+func (f *fakeChatAssessments) DeleteAssessment(ctx context.Context, id uuid.UUID, mpID uuid.UUID) error {
+	return nil
+}
+
+type fakeChatPatients struct {
+	domain.PatientRepository
+	patients []*domain.Patient
+}
+
+func (f *fakeChatPatients) SearchByName(ctx context.Context, name string, limit int) ([]*domain.Patient, error) {
+	return f.patients, nil
+}
+
+// fakeChatAssessmentRepo knows which patients the clinician has assessed.
+type fakeChatAssessmentRepo struct {
+	domain.AssessmentRepository
+	assessed map[uuid.UUID]bool
+}
+
+func (f *fakeChatAssessmentRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID, mpID uuid.UUID) ([]*domain.Assessment, error) {
+	if !f.assessed[patientID] {
+		return nil, nil
+	}
+	return []*domain.Assessment{{ID: uuid.New(), PatientID: patientID, MedicalProfessionalID: mpID}}, nil
+}
+
+func TestChatAssessmentConversation(t *testing.T) {
+	ctx := context.Background()
+	mp := &domain.MedicalProfessional{ID: uuid.New()}
+	patient := &domain.Patient{ID: uuid.New(), Name: "Abebe", DateOfBirth: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+	assessments := &fakeChatAssessments{}
+
+	chat := NewChatAssessmentUsecase(
+		assessments,
+		&fakeChatAssessmentRepo{assessed: map[uuid.UUID]bool{patient.ID: true}},
+		&fakeChatPatients{patients: []*domain.Patient{patient}},
+		repository.NewMemoryChatSessionStore(),
+		nil,
+		fakeRunner{},
+		time.Second,
+	)
+	send := func(text string) []*domain.ChatReply {
+		t.Helper()
+		out, err := chat.HandleMessage(ctx, "test:1", mp, text)
+		require.NoError(t, err)
+		require.NotEmpty(t, out)
+		return out
+	}
+
+	_, err := chat.HandleMessage(ctx, "test:1", mp, "hello")
+	assert.ErrorIs(t, err, domain.ErrChatSessionNotFound)
+
+	out := send("/assess Abebe")
+	require.Len(t, out[0].Buttons, 1)
+	assert.Equal(t, "patient:"+patient.ID.String(), out[0].Buttons[0].ID)
+
+	// A numeric reply picks the n-th button.
+	out = send("1")
+	assert.Contains(t, out[0].Text, "weight")
+
+	out = send("45")
+	assert.Contains(t, out[0].Text, "outside the plausible range")
+
+	out = send("7,5 kg")
+	require.NotNil(t, assessments.created)
+	assert.Equal(t, 7.5, assessments.created.WeightKg)
+	require.Len(t, out[0].Buttons, 1)
+	assert.Equal(t, "Fever", out[0].Buttons[0].Title)
+
+	out = send("tree:child_fever")
+	require.Len(t, out[0].Buttons, 2)
+	assert.Equal(t, "ans:yes", out[0].Buttons[0].ID)
+	assert.Equal(t, "Yes", out[0].Buttons[0].Title)
+
+	out = send("maybe")
+	assert.Contains(t, out[0].Text, "choose one of the options")
+
+	// Typed answers match the button values.
+	out = send("yes")
+	assert.Contains(t, out[0].Text, "between 1 and 30")
+	assert.Empty(t, out[0].Buttons)
+
+	out = send("90")
+	assert.Contains(t, out[0].Text, "Please send a number between 1 and 30")
+
+	out = send("4")
+	require.Len(t, out, 2)
+	assert.Contains(t, out[0].Text, "[YELLOW] FEVER")
+	assert.Contains(t, out[0].Text, "Give fluids")
+	assert.Contains(t, out[0].Text, "Follow up in 3 days")
+
+	out = send("done")
+	assert.Contains(t, out[0].Text, "Assessment saved")
+
+	_, err = chat.HandleMessage(ctx, "test:1", mp, "1")
+	assert.ErrorIs(t, err, domain.ErrChatSessionNotFound)
+}
+
+func TestChatAssessmentSessionIsPerClinician(t *testing.T) {
+	ctx := context.Background()
+	owner := &domain.MedicalProfessional{ID: uuid.New()}
+	other := &domain.MedicalProfessional{ID: uuid.New()}
+
+	almaz := &domain.Patient{ID: uuid.New(), Name: "Almaz"}
+	chat := NewChatAssessmentUsecase(
+		&fakeChatAssessments{},
+		&fakeChatAssessmentRepo{assessed: map[uuid.UUID]bool{almaz.ID: true}},
+		&fakeChatPatients{patients: []*domain.Patient{almaz}},
+		repository.NewMemoryChatSessionStore(),
+		nil,
+		fakeRunner{},
+		time.Second,
+	)
+
+	_, err := chat.HandleMessage(ctx, "test:2", owner, "assess Almaz")
+	require.NoError(t, err)
+
+	_, err = chat.HandleMessage(ctx, "test:2", other, "1")
+	assert.ErrorIs(t, err, domain.ErrChatSessionNotFound)
+}
+
+func TestChatAssessmentOnlyOffersOwnPatients(t *testing.T) {
+	ctx := context.Background()
+	mp := &domain.MedicalProfessional{ID: uuid.New()}
+	own := &domain.Patient{ID: uuid.New(), Name: "Sara"}
+	other := &domain.Patient{ID: uuid.New(), Name: "Sara"}
+
+	patients := &fakeChatPatients{patients: []*domain.Patient{own, other}}
+	chat := NewChatAssessmentUsecase(
+		&fakeChatAssessments{},
+		&fakeChatAssessmentRepo{assessed: map[uuid.UUID]bool{own.ID: true}},
+		patients,
+		repository.NewMemoryChatSessionStore(),
+		nil,
+		fakeRunner{},
+		time.Second,
+	)
+
+	out, err := chat.HandleMessage(ctx, "test:3", mp, "assess Sara")
+	require.NoError(t, err)
+	require.Len(t, out[0].Buttons, 1)
+	assert.Equal(t, "patient:"+own.ID.String(), out[0].Buttons[0].ID)
+
+	// A typed ID of a patient that was not offered is refused.
+	out, err = chat.HandleMessage(ctx, "test:3", mp, "patient:"+other.ID.String())
+	require.NoError(t, err)
+	assert.Contains(t, out[0].Text, "pick one of the patients")
+
+	patients.patients = []*domain.Patient{other}
+	out, err = chat.HandleMessage(ctx, "test:4", mp, "assess Sara")
+	require.NoError(t, err)
+	assert.Contains(t, out[0].Text, "None of your patients")
+}
@@ -8,40 +8,69 @@ import (
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/followup"
 	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
 	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
 	"github.com/google/uuid"
 )
 
 type ChildRuleEngineUsecase struct {
 	ruleEngine                    *engine.ChildRuleEngine
 	assessmentRepo                domain.AssessmentRepository
+	lifecycle                     domain.AssessmentLifecycleUsecase
+	vitalAlerts                   domain.VitalAlertUsecase
+	consistencyRepo               domain.ConsistencyRepository
 	medicalProfessionalAnswerRepo domain.MedicalProfessionalAnswerRepository
 	clinicalFindingsRepo          domain.ClinicalFindingsRepository
 	classificationRepo            domain.ClassificationRepository
 	treatmentPlanRepo             domain.TreatmentPlanRepository
 	counselingRepo                domain.CounselingRepository
+	answerProviders               []domain.TreeAnswerProvider
+	immunizationUsecase           domain.ImmunizationUsecase
+	supplementUsecase             domain.SupplementUsecase
+	classificationNotifier        domain.ClassificationNotifier
+	auditor                       domain.AuditRecorder
+	translator                    *locale.Translator
 	contextTimeout                time.Duration
 }
 
 func NewChildRuleEngineUsecase(
 	ruleEngine *engine.ChildRuleEngine,
 	assessmentRepo domain.AssessmentRepository,
+	lifecycle domain.AssessmentLifecycleUsecase,
+	vitalAlerts domain.VitalAlertUsecase,
+	consistencyRepo domain.ConsistencyRepository,
 	medicalProfessionalAnswerRepo domain.MedicalProfessionalAnswerRepository,
 	clinicalFindingsRepo domain.ClinicalFindingsRepository,
 	classificationRepo domain.ClassificationRepository,
 	treatmentPlanRepo domain.TreatmentPlanRepository,
 	counselingRepo domain.CounselingRepository,
+	answerProviders []domain.TreeAnswerProvider,
+	immunizationUsecase domain.ImmunizationUsecase,
+	supplementUsecase domain.SupplementUsecase,
+	classificationNotifier domain.ClassificationNotifier,
+	auditor domain.AuditRecorder,
+	translator *locale.Translator,
 	timeout time.Duration,
 ) *ChildRuleEngineUsecase {
 	return &ChildRuleEngineUsecase{
 		ruleEngine:                    ruleEngine,
 		assessmentRepo:                assessmentRepo,
+		lifecycle:                     lifecycle,
+		vitalAlerts:                   vitalAlerts,
+		consistencyRepo:               consistencyRepo,
 		medicalProfessionalAnswerRepo: medicalProfessionalAnswerRepo,
 		clinicalFindingsRepo:          clinicalFindingsRepo,
 		classificationRepo:            classificationRepo,
 		treatmentPlanRepo:             treatmentPlanRepo,
 		counselingRepo:                counselingRepo,
+		answerProviders:               answerProviders,
+		immunizationUsecase:           immunizationUsecase,
+		supplementUsecase:             supplementUsecase,
+		classificationNotifier:        classificationNotifier,
+		auditor:                       auditor,
+		translator:                    translator,
 		contextTimeout:                timeout,
 	}
 }
@@ -55,17 +84,41 @@ func (uc *ChildRuleEngineUsecase) StartAssessmentFlow(ctx context.Context, req r
 		return nil, err
 	}
 
+	if err := uc.lifecycle.FlowStarted(ctx, assessment); err != nil {
+		return nil, err
+	}
+
 	flow, err := uc.ruleEngine.StartAssessmentFlow(req.AssessmentID, req.TreeID)
 	if err != nil {
 		return nil, err
 	}
 
+	currentQuestion, err := uc.ruleEngine.GetCurrentQuestion(flow)
+	if err != nil {
+		return nil, err
+	}
+
+	provided, err := collectProvidedAnswers(ctx, uc.answerProviders, assessment, req.TreeID)
+	if err != nil {
+		return nil, err
+	}
+
+	flow, currentQuestion, err = autoAnswer(uc.ruleEngine, flow, currentQuestion, provided)
+	if err != nil {
+		return nil, err
+	}
+
+	clinicalFindings, err := saveClinicalFindings(ctx, uc.clinicalFindingsRepo, req.AssessmentID, req.TreeID, flow.Answers)
+	if err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer := &domain.MedicalProfessionalAnswer{
 		ID:                 uuid.New(),
 		AssessmentID:       req.AssessmentID,
 		Answers:            domain.JSONB(flow.Answers),
 		QuestionSetVersion: req.TreeID,
-		ClinicalFindings:   domain.JSONB{},
+		ClinicalFindings:   clinicalFindings,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -74,21 +127,29 @@ func (uc *ChildRuleEngineUsecase) StartAssessmentFlow(ctx context.Context, req r
 		return nil, fmt.Errorf("failed to save assessment flow: %w", err)
 	}
 
-	assessment.Status = domain.StatusInProgress
-	if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
-		return nil, fmt.Errorf("failed to update assessment status: %w", err)
-	}
+	classification, language := localizeClassification(uc.translator, uc.ruleEngine, req.TreeID, flow.Classification, req.Language)
 
-	currentQuestion, err := uc.ruleEngine.GetCurrentQuestion(flow)
-	if err != nil {
-		return nil, err
+	var consistency []*domain.ConsistencyFinding
+	if flow.Status != ruleenginedomain.FlowStatusInProgress {
+		if err := uc.saveClassificationResults(ctx, assessment, classification, language, req.Language); err != nil {
+			return nil, fmt.Errorf("failed to save classification results: %w", err)
+		}
+		if err := uc.lifecycle.FlowFinished(ctx, assessment, req.TreeID, classification != nil); err != nil {
+			return nil, fmt.Errorf("failed to update assessment status: %w", err)
+		}
+		if consistency, err = uc.reconcileVisit(ctx, assessment, req.TreeID, flow.Answers, req.Language); err != nil {
+			return nil, fmt.Errorf("failed to check visit consistency: %w", err)
+		}
 	}
 
 	return &ruleenginedomain.StartFlowResponse{
-		SessionID:   medicalProfessionalAnswer.ID,
-		Question:    currentQuestion,
-		IsComplete:  flow.Status == ruleenginedomain.FlowStatusCompleted,
-		CurrentNode: flow.CurrentNode,
+		SessionID:      medicalProfessionalAnswer.ID,
+		Question:       uc.translator.Question(req.TreeID, currentQuestion, req.Language),
+		Classification: classification,
+		IsComplete:     flow.Status != ruleenginedomain.FlowStatusInProgress,
+		CurrentNode:    flow.CurrentNode,
+		Status:         flow.Status,
+		Consistency:    consistency,
 	}, nil
 }
 
@@ -101,6 +162,10 @@ func (uc *ChildRuleEngineUsecase) SubmitAnswer(ctx context.Context, req ruleengi
 		return nil, err
 	}
 
+	if err := uc.lifecycle.FlowStarted(ctx, assessment); err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer, err := uc.medicalProfessionalAnswerRepo.GetByAssessmentID(ctx, req.AssessmentID)
 	if err != nil {
 		return nil, domain.ErrMedicalProfessionalAnswerNotFound
@@ -128,31 +193,61 @@ func (uc *ChildRuleEngineUsecase) SubmitAnswer(ctx context.Context, req ruleengi
 		return nil, err
 	}
 
+	provided, err := collectProvidedAnswers(ctx, uc.answerProviders, assessment, flow.TreeID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedFlow, nextQuestion, err = autoAnswer(uc.ruleEngine, updatedFlow, nextQuestion, provided)
+	if err != nil {
+		return nil, err
+	}
+
+	clinicalFindings, err := saveClinicalFindings(ctx, uc.clinicalFindingsRepo, req.AssessmentID, flow.TreeID, updatedFlow.Answers)
+	if err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer.Answers = domain.JSONB(updatedFlow.Answers)
+	medicalProfessionalAnswer.ClinicalFindings = clinicalFindings
 	medicalProfessionalAnswer.UpdatedAt = time.Now()
 
 	if err := uc.medicalProfessionalAnswerRepo.Upsert(ctx, medicalProfessionalAnswer); err != nil {
 		return nil, fmt.Errorf("failed to update assessment flow: %w", err)
 	}
 
+	vitalAlerts, err := uc.vitalAlerts.Evaluate(clinicianContext(ctx, assessment), assessment, updatedFlow.Answers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate vital alerts: %w", err)
+	}
+
+	classification, language := localizeClassification(uc.translator, uc.ruleEngine, flow.TreeID, updatedFlow.Classification, req.Language)
+
+	var findings []*domain.ConsistencyFinding
 	if updatedFlow.Status == ruleenginedomain.FlowStatusCompleted || updatedFlow.Status == ruleenginedomain.FlowStatusEmergency {
-		if err := uc.saveClassificationResults(ctx, assessment, updatedFlow.Classification); err != nil {
+		if err := uc.saveClassificationResults(ctx, assessment, classification, language, req.Language); err != nil {
 			return nil, fmt.Errorf("failed to save classification results: %w", err)
 		}
 
-		assessment.Status = domain.StatusCompleted
-		if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
+		if err := uc.lifecycle.FlowFinished(ctx, assessment, flow.TreeID, classification != nil); err != nil {
 			return nil, fmt.Errorf("failed to update assessment status: %w", err)
 		}
+
+		findings, err = uc.reconcileVisit(ctx, assessment, flow.TreeID, updatedFlow.Answers, req.Language)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check visit consistency: %w", err)
+		}
 	}
 
 	return &ruleenginedomain.SubmitAnswerResponse{
 		SessionID:      medicalProfessionalAnswer.ID,
-		Question:       nextQuestion,
-		Classification: updatedFlow.Classification,
+		Question:       uc.translator.Question(flow.TreeID, nextQuestion, req.Language),
+		Classification: classification,
 		IsComplete:     updatedFlow.Status != ruleenginedomain.FlowStatusInProgress,
 		CurrentNode:    updatedFlow.CurrentNode,
 		Status:         updatedFlow.Status,
+		VitalAlerts:    vitalAlerts,
+		Consistency:    findings,
 	}, nil
 }
 
@@ -165,17 +260,32 @@ func (uc *ChildRuleEngineUsecase) ProcessBatchAssessment(ctx context.Context, re
 		return nil, err
 	}
 
+	if err := uc.lifecycle.FlowStarted(ctx, assessment); err != nil {
+		return nil, err
+	}
+
+	provided, err := collectProvidedAnswers(ctx, uc.answerProviders, assessment, req.TreeID)
+	if err != nil {
+		return nil, err
+	}
+	fillProvidedAnswers(req.Answers, provided)
+
 	flow, err := uc.ruleEngine.ProcessBatchAssessment(req.AssessmentID, req.TreeID, req.Answers)
 	if err != nil {
 		return nil, err
 	}
 
+	clinicalFindings, err := saveClinicalFindings(ctx, uc.clinicalFindingsRepo, req.AssessmentID, req.TreeID, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer := &domain.MedicalProfessionalAnswer{
 		ID:                 uuid.New(),
 		AssessmentID:       req.AssessmentID,
 		Answers:            domain.JSONB(req.Answers),
 		QuestionSetVersion: req.TreeID,
-		ClinicalFindings:   domain.JSONB{},
+		ClinicalFindings:   clinicalFindings,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -184,30 +294,47 @@ func (uc *ChildRuleEngineUsecase) ProcessBatchAssessment(ctx context.Context, re
 		return nil, fmt.Errorf("failed to save assessment answers: %w", err)
 	}
 
-	if flow.Classification != nil {
-		if err := uc.saveClassificationResults(ctx, assessment, flow.Classification); err != nil {
+	vitalAlerts, err := uc.vitalAlerts.Evaluate(clinicianContext(ctx, assessment), assessment, req.Answers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate vital alerts: %w", err)
+	}
+
+	classification, language := localizeClassification(uc.translator, uc.ruleEngine, req.TreeID, flow.Classification, req.Language)
+
+	if classification != nil {
+		if err := uc.saveClassificationResults(ctx, assessment, classification, language, req.Language); err != nil {
 			return nil, fmt.Errorf("failed to save classification results: %w", err)
 		}
-
-		assessment.Status = domain.StatusCompleted
-		if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
+	}
+	var findings []*domain.ConsistencyFinding
+	if flow.Status != ruleenginedomain.FlowStatusInProgress {
+		if err := uc.lifecycle.FlowFinished(ctx, assessment, req.TreeID, classification != nil); err != nil {
 			return nil, fmt.Errorf("failed to update assessment status: %w", err)
 		}
+
+		findings, err = uc.reconcileVisit(ctx, assessment, req.TreeID, req.Answers, req.Language)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check visit consistency: %w", err)
+		}
 	}
 
 	return &ruleenginedomain.BatchProcessResponse{
 		AssessmentID:   req.AssessmentID,
-		Classification: flow.Classification,
+		Classification: classification,
 		Status:         flow.Status,
+		VitalAlerts:    vitalAlerts,
+		Consistency:    findings,
 	}, nil
 }
 
-func (uc *ChildRuleEngineUsecase) GetTreeQuestions(treeID string) (*ruleenginedomain.AssessmentTree, error) {
+// GetTreeQuestions returns the tree translated into language, falling back to
+// English for anything without a translation.
+func (uc *ChildRuleEngineUsecase) GetTreeQuestions(treeID, language string) (*ruleenginedomain.AssessmentTree, error) {
 	tree, err := uc.ruleEngine.GetAssessmentTree(treeID)
 	if err != nil {
 		return nil, err
 	}
-	return tree, nil
+	return uc.translator.Tree(tree, language), nil
 }
 
 func (uc *ChildRuleEngineUsecase) GetAssessmentTree(treeID string) (*ruleenginedomain.AssessmentTree, error) {
@@ -218,7 +345,11 @@ func (uc *ChildRuleEngineUsecase) GetAvailableTrees() []string {
 	return uc.ruleEngine.GetAvailableTrees()
 }
 
-func (uc *ChildRuleEngineUsecase) saveClassificationResults(ctx context.Context, assessment *domain.Assessment, classification *ruleenginedomain.ClassificationResult) error {
+// saveClassificationResults persists a finished flow: the classification, its
+// treatment plans and the counselling items. language is the language the
+// mother advice is in; requestedLanguage is the one negotiated for the
+// request, used for the counselling cards.
+func (uc *ChildRuleEngineUsecase) saveClassificationResults(ctx context.Context, assessment *domain.Assessment, classification *ruleenginedomain.ClassificationResult, language, requestedLanguage string) error {
 	if classification == nil {
 		return nil
 	}
@@ -233,6 +364,7 @@ func (uc *ChildRuleEngineUsecase) saveClassificationResults(ctx context.Context,
 		IsCriticalIllness:      classification.Emergency,
 		RequiresUrgentReferral: classification.Emergency,
 		TreatmentPriority:      uc.getTreatmentPriority(classification.Classification),
+		FollowUpDate:           followup.Date(time.Now(), classification.FollowUp),
 		CreatedAt:              time.Now(),
 	}
 
@@ -240,40 +372,71 @@ func (uc *ChildRuleEngineUsecase) saveClassificationResults(ctx context.Context,
 		return err
 	}
 
-	if err := uc.saveTreatmentPlans(ctx, class, classification); err != nil {
+	if err := uc.saveTreatmentPlans(ctx, assessment, class, classification); err != nil {
 		return err
 	}
 
-	counseling := &domain.Counseling{
-		ID:           uuid.New(),
-		AssessmentID: assessment.ID,
-		AdviceType:   "mother_advice",
-		Details:      classification.MotherAdvice,
-		Language:     "en",
-		CreatedAt:    time.Now(),
+	if err := uc.saveCounseling(ctx, assessment, class, classification, language, requestedLanguage); err != nil {
+		return err
 	}
 
-	if err := uc.counselingRepo.Create(ctx, counseling); err != nil {
+	if err := uc.auditor.Record(clinicianContext(ctx, assessment), domain.AuditCreate, domain.AuditClassification, class.ID, nil, class); err != nil {
 		return err
 	}
 
-	if len(classification.FollowUp) > 0 {
-		followUpCounseling := &domain.Counseling{
-			ID:           uuid.New(),
-			AssessmentID: assessment.ID,
-			AdviceType:   "follow_up_schedule",
-			Details:      fmt.Sprintf("Follow-up schedule: %v", strings.Join(classification.FollowUp, ", ")),
-			Language:     "en",
-			CreatedAt:    time.Now(),
-		}
-		if err := uc.counselingRepo.Create(ctx, followUpCounseling); err != nil {
+	if uc.classificationNotifier != nil {
+		uc.classificationNotifier.NotifyClassification(ctx, assessment, class)
+	}
+
+	return nil
+}
+
+// saveCounseling saves the mother advice, counselling cards and follow-up
+// schedule of a classification's outcome.
+func (uc *ChildRuleEngineUsecase) saveCounseling(ctx context.Context, assessment *domain.Assessment, class *domain.Classification, result *ruleenginedomain.ClassificationResult, language, requestedLanguage string) error {
+	given, err := givenAdvice(ctx, uc.counselingRepo, assessment.ID, class.ID)
+	if err != nil {
+		return err
+	}
+	for _, item := range uc.counselings(assessment, class, result, language, requestedLanguage, given) {
+		if err := uc.counselingRepo.Create(ctx, item); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
+// counselings builds the mother advice, in language, the counselling cards,
+// in requestedLanguage, and the follow-up schedule of a classification's
+// outcome. given holds the card types the rest of the visit already has.
+func (uc *ChildRuleEngineUsecase) counselings(assessment *domain.Assessment, class *domain.Classification, result *ruleenginedomain.ClassificationResult, language, requestedLanguage string, given map[string]bool) []*domain.Counseling {
+	classificationID := class.ID
+	items := []*domain.Counseling{{
+		ID:               uuid.New(),
+		AssessmentID:     assessment.ID,
+		ClassificationID: &classificationID,
+		AdviceType:       "mother_advice",
+		Details:          result.MotherAdvice,
+		Language:         language,
+		CreatedAt:        time.Now(),
+	}}
+	items = append(items, counselingItems(uc.translator, assessment, class, requestedLanguage, given)...)
+
+	if len(result.FollowUp) > 0 {
+		items = append(items, &domain.Counseling{
+			ID:               uuid.New(),
+			AssessmentID:     assessment.ID,
+			ClassificationID: &classificationID,
+			AdviceType:       "follow_up_schedule",
+			Details:          fmt.Sprintf("Follow-up schedule: %v", strings.Join(result.FollowUp, ", ")),
+			Language:         "en",
+			CreatedAt:        time.Now(),
+		})
+	}
+
+	return items
+}
+
 func (uc *ChildRuleEngineUsecase) getTreatmentPriority(classification string) int {
 	switch classification {
 	case "VERY SEVERE DISEASE", "SEVERE PNEUMONIA OR VERY SEVERE DISEASE", "SEVERE DEHYDRATION", "SEVERE PERSISTENT DIARRHOEA", "SEVERE MALNUTRITION", "VERY SEVERE FEBRILE DISEASE", "SEVERE COMPLICATED MEASLES", "MASTOIDITIS", "SEVERE ANEMIA", "COMPLICATED SEVERE ACUTE MALNUTRITION",
@@ -290,7 +453,21 @@ func (uc *ChildRuleEngineUsecase) getTreatmentPriority(classification string) in
 		return 3
 	}
 }
-func (uc *ChildRuleEngineUsecase) saveTreatmentPlans(ctx context.Context, classification *domain.Classification, result *ruleenginedomain.ClassificationResult) error {
+func (uc *ChildRuleEngineUsecase) saveTreatmentPlans(ctx context.Context, assessment *domain.Assessment, classification *domain.Classification, result *ruleenginedomain.ClassificationResult) error {
+	plans, err := uc.treatmentPlans(ctx, assessment, classification, result)
+	if err != nil {
+		return err
+	}
+	for _, plan := range plans {
+		if err := uc.treatmentPlanRepo.Create(ctx, plan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// treatmentPlans builds the treatment plans of a classification's outcome.
+func (uc *ChildRuleEngineUsecase) treatmentPlans(ctx context.Context, assessment *domain.Assessment, classification *domain.Classification, result *ruleenginedomain.ClassificationResult) ([]*domain.TreatmentPlan, error) {
 	var plans []*domain.TreatmentPlan
 
 	switch result.Classification {
@@ -309,6 +486,13 @@ func (uc *ChildRuleEngineUsecase) saveTreatmentPlans(ctx context.Context, classi
 				Instructions:        "Provide all due vaccines today and schedule next visit",
 			},
 		}
+		vaccinePlans, err := uc.catchUpVaccinePlans(ctx, assessment, classification)
+		if err != nil {
+			return nil, err
+		}
+		if len(vaccinePlans) > 0 {
+			plans = vaccinePlans
+		}
 	case "VITAMIN A DUE":
 		plans = []*domain.TreatmentPlan{
 			{
@@ -324,6 +508,13 @@ func (uc *ChildRuleEngineUsecase) saveTreatmentPlans(ctx context.Context, classi
 				Instructions:        "Give Vitamin A if child is 6 months or older",
 			},
 		}
+		supplementPlans, err := uc.dueSupplementPlans(ctx, assessment, classification, "vitamin_a")
+		if err != nil {
+			return nil, err
+		}
+		if len(supplementPlans) > 0 {
+			plans = supplementPlans
+		}
 	case "DEWORMING DUE":
 		plans = []*domain.TreatmentPlan{
 			{
@@ -339,6 +530,13 @@ func (uc *ChildRuleEngineUsecase) saveTreatmentPlans(ctx context.Context, classi
 				Instructions:        "Give deworming if child is 2 years or older",
 			},
 		}
+		supplementPlans, err := uc.dueSupplementPlans(ctx, assessment, classification, "deworming")
+		if err != nil {
+			return nil, err
+		}
+		if len(supplementPlans) > 0 {
+			plans = supplementPlans
+		}
 	case "MULTIPLE PREVENTIVE CARE DUE":
 		plans = []*domain.TreatmentPlan{
 			{
@@ -354,6 +552,16 @@ func (uc *ChildRuleEngineUsecase) saveTreatmentPlans(ctx context.Context, classi
 				Instructions:        "Provide missing vaccines and give Vitamin A/deworming as due",
 			},
 		}
+		vaccinePlans, err := uc.catchUpVaccinePlans(ctx, assessment, classification)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, vaccinePlans...)
+		supplementPlans, err := uc.dueSupplementPlans(ctx, assessment, classification, "vitamin_a", "deworming")
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, supplementPlans...)
 	case "IMMUNIZATION AND SUPPLEMENTS UP TO DATE":
 		plans = []*domain.TreatmentPlan{}
 	case "VERY SEVERE DISEASE":
@@ -1260,11 +1468,106 @@ func (uc *ChildRuleEngineUsecase) saveTreatmentPlans(ctx context.Context, classi
 		plans = []*domain.TreatmentPlan{}
 	}
 
-	for _, plan := range plans {
-		if err := uc.treatmentPlanRepo.Create(ctx, plan); err != nil {
-			return err
+	return plans, nil
+}
+
+// catchUpVaccinePlans returns one treatment plan per vaccine dose the EPI
+// schedule says is due or overdue on the day of the assessment.
+func (uc *ChildRuleEngineUsecase) catchUpVaccinePlans(ctx context.Context, assessment *domain.Assessment, classification *domain.Classification) ([]*domain.TreatmentPlan, error) {
+	if uc.immunizationUsecase == nil {
+		return nil, nil
+	}
+
+	asOf := assessment.StartTime
+	if asOf.IsZero() {
+		asOf = assessment.CreatedAt
+	}
+
+	schedule, err := uc.immunizationUsecase.GetSchedule(ctx, assessment.PatientID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute immunization schedule: %w", err)
+	}
+
+	var nextVisit string
+	if len(schedule.Next) > 0 {
+		nextVisit = schedule.Next[0].DueDate.Format("2006-01-02")
+	}
+
+	plans := make([]*domain.TreatmentPlan, 0, len(schedule.DueToday))
+	for _, dose := range schedule.DueToday {
+		instructions := fmt.Sprintf("Give %s (%s) in the %s; due since %s",
+			dose.Vaccine, dose.Antigen, strings.ToLower(dose.Site), dose.DueDate.Format("2006-01-02"))
+		if nextVisit != "" {
+			instructions += fmt.Sprintf(". Next immunization visit: %s", nextVisit)
 		}
+
+		plans = append(plans, &domain.TreatmentPlan{
+			ID:                  uuid.New(),
+			AssessmentID:        classification.AssessmentID,
+			ClassificationID:    classification.ID,
+			DrugName:            dose.Vaccine,
+			Dosage:              dose.Dosage,
+			Frequency:           "Today",
+			Duration:            "Single dose",
+			AdministrationRoute: dose.AdministrationRoute,
+			IsPreReferral:       false,
+			Instructions:        instructions,
+		})
 	}
 
-	return nil
+	return plans, nil
+}
+
+// dueSupplementPlans returns a treatment plan with the age-appropriate dose
+// for each of the given supplements that is due on the day of the
+// assessment.
+func (uc *ChildRuleEngineUsecase) dueSupplementPlans(ctx context.Context, assessment *domain.Assessment, classification *domain.Classification, supplements ...string) ([]*domain.TreatmentPlan, error) {
+	if uc.supplementUsecase == nil {
+		return nil, nil
+	}
+
+	asOf := assessment.StartTime
+	if asOf.IsZero() {
+		asOf = assessment.CreatedAt
+	}
+
+	status, err := uc.supplementUsecase.GetStatus(ctx, assessment.PatientID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute supplement status: %w", err)
+	}
+
+	var plans []*domain.TreatmentPlan
+	for _, s := range supplements {
+		var eligibility *domain.SupplementEligibility
+		switch s {
+		case "vitamin_a":
+			eligibility = status.VitaminA
+		case "deworming":
+			eligibility = status.Deworming
+		}
+		if eligibility == nil || !eligibility.Due {
+			continue
+		}
+
+		instructions := fmt.Sprintf("Give %s %s today", eligibility.Drug, eligibility.RecommendedDose)
+		if eligibility.LastDoseDate != nil {
+			instructions += fmt.Sprintf("; last dose %s", eligibility.LastDoseDate.Format("2006-01-02"))
+		}
+		instructions += ". Repeat every 6 months"
+
+		plans = append(plans, &domain.TreatmentPlan{
+			ID:                  uuid.New(),
+			AssessmentID:        classification.AssessmentID,
+			ClassificationID:    classification.ID,
+			DrugName:            eligibility.Drug,
+			Dosage:              eligibility.RecommendedDose,
+			Frequency:           "Single dose",
+			Duration:            "Once",
+			AdministrationRoute: eligibility.AdministrationRoute,
+			IsPreReferral:       false,
+			Instructions:        instructions,
+		})
+	}
+
+	return plans, nil
 }
@@ -0,0 +1,388 @@
+// ruleengine/usecase/clinical_findings_mapping.go
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+// findingMapper copies one node answer onto the structured findings.
+type findingMapper func(f *domain.ClinicalFindings, answer interface{})
+
+// nodeFinding maps the answer of one node.
+type nodeFinding struct {
+	nodeID string
+	mapper findingMapper
+}
+
+// treeFindingMappers declares, per assessment tree, which node answers feed
+// which ClinicalFindings fields. The mappers of a tree run in the order
+// declared, so a node that refines an earlier one is listed after it. When
+// two trees ask about the same sign the most recent answer wins, except for
+// danger signs, which once found stay found.
+var treeFindingMappers = map[string][]nodeFinding{
+	// Child (2 months - 5 years)
+	"child_general_danger_signs": {
+		{"unable_to_drink_breastfeed", dangerSign(func(f *domain.ClinicalFindings) *bool { return &f.UnableToDrink }, "no")},
+		{"vomits_everything", dangerSign(func(f *domain.ClinicalFindings) *bool { return &f.VomitsEverything }, "yes")},
+		{"convulsions_history", dangerSign(func(f *domain.ClinicalFindings) *bool { return &f.HadConvulsions }, "yes")},
+		{"lethargic_unconscious", dangerSign(func(f *domain.ClinicalFindings) *bool { return &f.LethargicUnconscious }, "yes")},
+		{"convulsing_now", dangerSign(func(f *domain.ClinicalFindings) *bool { return &f.ConvulsingNow }, "yes")},
+	},
+	"child_cough_difficult_breathing": {
+		{"cough_difficult_breathing", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.CoughPresent })},
+		{"how_long", intValue(func(f *domain.ClinicalFindings) **int { return &f.CoughDurationDays })},
+		{"stridor", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.Stridor })},
+		{"fast_breathing", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.FastBreathing })},
+		{"chest_indrawing", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.ChestIndrawing })},
+		{"hiv_exposed", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.HIVExposed })},
+		{"wheezing", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.Wheezing })},
+	},
+	"child_diarrhea": {
+		{"diarrhea_present", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.DiarrheaPresent })},
+		{"how_long_diarrhea", intValue(func(f *domain.ClinicalFindings) **int { return &f.DiarrheaDurationDays })},
+		{"blood_in_stool", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.BloodInStool })},
+		{"lethargic_unconscious", dangerSign(func(f *domain.ClinicalFindings) *bool { return &f.LethargicUnconscious }, "yes")},
+		{"restless_irritable", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.RestlessIrritable })},
+		{"sunken_eyes", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.SunkenEyes })},
+		{"drinking_ability", matches(func(f *domain.ClinicalFindings) *bool { return &f.DrinkingPoorly }, "no")},
+		{"drinking_eagerly", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.DrinkingEagerly })},
+		{"skin_pinch", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.SkinPinchVerySlow })},
+		{"skin_pinch_slow", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.SkinPinchSlow })},
+	},
+	"child_fever": {
+		{"fever_present", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.FeverPresent })},
+		{"fever_duration", intValue(func(f *domain.ClinicalFindings) **int { return &f.FeverDurationDays })},
+		{"measles_history", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.MeaslesLast3Months })},
+		{"current_measles", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.MeaslesNow })},
+		{"stiff_neck", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.StiffNeck })},
+		{"bulging_fontanelle", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.BulgingFontanelle })},
+		{"runny_nose", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.RunnyNose })},
+	},
+	"child_ear_problem": {
+		{"ear_pain", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.EarPain })},
+		{"pus_draining", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.EarDischarge })},
+		{"tender_swelling", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.TenderSwellingBehindEar })},
+	},
+	"child_anemia_check": {
+		{"palmar_pallor_severity", func(f *domain.ClinicalFindings, answer interface{}) {
+			f.SeverePalmarPallor = answerString(answer) == "severe_palmar_pallor"
+			f.SomePalmarPallor = answerString(answer) == "some_palmar_pallor"
+		}},
+		{"hb_value", floatValue(func(f *domain.ClinicalFindings) **float64 { return &f.HbLevel })},
+		{"hct_value", floatValue(func(f *domain.ClinicalFindings) **float64 { return &f.HctLevel })},
+	},
+	"acute_malnutrition": {
+		{"pitting_edema", func(f *domain.ClinicalFindings, answer interface{}) {
+			value := answerString(answer)
+			f.BilateralEdema = value != "" && value != "none"
+		}},
+		{"wfl_z_score", floatValue(func(f *domain.ClinicalFindings) **float64 { return &f.WeightForHeightZScore })},
+		{"muac_measurement", floatValue(func(f *domain.ClinicalFindings) **float64 { return &f.MUAC })},
+		{"severe_wasting_with_edema_check", severeWastingWithEdema},
+	},
+	"feeding_assessment": {
+		{"breastfeeding_check", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.Breastfeeding })},
+		{"breastfeeding_frequency", intValue(func(f *domain.ClinicalFindings) **int { return &f.BreastfeedingFrequency })},
+		{"other_food_check", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.ComplementaryFoods })},
+	},
+	"hiv_assessment": {
+		{"mother_hiv_status", matches(func(f *domain.ClinicalFindings) *bool { return &f.HIVExposed }, "positive")},
+		{"child_antibody_test", hivStatusKnown},
+		{"child_dna_pcr_test", hivStatusKnown},
+	},
+	"tb_assessment": {
+		{"tb_symptoms_check", func(f *domain.ClinicalFindings, answer interface{}) {
+			values := answerList(answer)
+			if contains(values, "cough_14_days") {
+				days := 14
+				f.TBCoughDurationDays = &days
+			}
+			f.NightSweats = contains(values, "fever_night_sweats_14_days")
+		}},
+		{"tb_contact_history", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.TBContactHistory })},
+		{"tb_signs_check", func(f *domain.ClinicalFindings, answer interface{}) {
+			f.TBWeightLoss = contains(answerList(answer), "weight_loss_failure_gain")
+		}},
+	},
+	// The child and young infant developmental trees share an ID: the child
+	// tree asks risk_factors, the young infant tree the other two.
+	"developmental_assessment": {
+		{"risk_factors", riskFactors},
+		{"medical_risk_factors", riskFactors},
+		{"environmental_risk_factors", riskFactors},
+		{"milestone_flexed_position", milestoneAbsent("flexed_position")},
+		{"milestone_grasp_reflex", milestoneAbsent("grasp_reflex")},
+		{"milestone_prefers_faces", milestoneAbsent("prefers_faces")},
+		{"milestone_suckle_reflex", milestoneAbsent("suckle_reflex")},
+		{"milestone_visual_tracking", milestoneAbsent("visual_tracking")},
+	},
+
+	// Young infant (0 - 2 months)
+	"very_severe_disease_check": {
+		{"feeding_ability_detail", func(f *domain.ClinicalFindings, answer interface{}) {
+			f.UnableToFeed = answerString(answer) == "unable_to_feed"
+			f.NotFeedingWell = answerString(answer) == "not_feeding_well"
+		}},
+		{"convulsions_history", dangerSign(func(f *domain.ClinicalFindings) *bool { return &f.HadConvulsions }, "yes")},
+		{"check_movements", infantMovement},
+		{"breathing_rate", func(f *domain.ClinicalFindings, answer interface{}) {
+			rate, ok := answerInt(answer)
+			if !ok {
+				return
+			}
+			f.RespiratoryRate = &rate
+			f.FastBreathing = rate >= 60
+		}},
+		{"chest_indrawing", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.ChestIndrawing })},
+		{"umbilicus_check", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.UmbilicusRed })},
+		{"skin_pustules", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.SkinPustules })},
+		{"temperature_measurement", func(f *domain.ClinicalFindings, answer interface{}) {
+			temp, ok := answerFloat(answer)
+			if !ok {
+				return
+			}
+			f.BodyTemperature = &temp
+			f.LowBodyTemperature = temp < 35.5
+		}},
+	},
+	"diarrhea_check": {
+		{"diarrhea_present", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.DiarrheaPresent })},
+		{"blood_in_stool", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.BloodInStool })},
+		{"movement_condition", infantMovement},
+		{"sunken_eyes", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.SunkenEyes })},
+		{"skin_pinch", func(f *domain.ClinicalFindings, answer interface{}) {
+			f.SkinPinchVerySlow = answerString(answer) == "very_slowly_more_than_2_seconds"
+			f.SkinPinchSlow = answerString(answer) == "slowly"
+		}},
+	},
+	"jaundice_check": {
+		{"skin_yellow", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.SkinEyesYellow })},
+		{"palms_soles_yellow", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.PalmsSolesYellow })},
+		{"infant_age", func(f *domain.ClinicalFindings, answer interface{}) {
+			if days, ok := answerInt(answer); ok {
+				hours := days * 24
+				f.JaundiceAgeHours = &hours
+			}
+		}},
+	},
+	"hiv_status_assessment": {
+		{"mother_hiv_status", matches(func(f *domain.ClinicalFindings) *bool { return &f.HIVExposed }, "positive")},
+		{"infant_antibody_status", hivStatusKnown},
+		{"infant_dna_pcr_status", hivStatusKnown},
+	},
+	"feeding_problem_underweight_check": {
+		{"feeding_difficulty", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.FeedingProblem })},
+		{"breastfeeding_status", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.Breastfeeding })},
+		{"breastfeeding_frequency", intValue(func(f *domain.ClinicalFindings) **int { return &f.BreastfeedingFrequency })},
+		{"other_foods_drinks", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.ComplementaryFoods })},
+		{"weight_age_assessment", underweight},
+	},
+	"replacement_feeding_check": {
+		{"feeding_difficulty_non_bf", yesNo(func(f *domain.ClinicalFindings) *bool { return &f.FeedingProblem })},
+		{"weight_age_assessment_non_bf", underweight},
+	},
+}
+
+// applyTreeFindings maps every answer given so far in treeID onto findings.
+func applyTreeFindings(findings *domain.ClinicalFindings, treeID string, answers map[string]interface{}) {
+	for _, node := range treeFindingMappers[treeID] {
+		if answer, answered := answers[node.nodeID]; answered {
+			node.mapper(findings, answer)
+		}
+	}
+}
+
+// saveClinicalFindings updates the assessment's typed findings row from the
+// tree answers and returns the findings as JSONB for the answer session.
+func saveClinicalFindings(ctx context.Context, repo domain.ClinicalFindingsRepository, assessmentID uuid.UUID, treeID string, answers map[string]interface{}) (domain.JSONB, error) {
+	findings, err := repo.GetByAssessmentID(ctx, assessmentID)
+	if errors.Is(err, domain.ErrNotFound) {
+		findings = &domain.ClinicalFindings{
+			ID:           uuid.New(),
+			AssessmentID: assessmentID,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	applyTreeFindings(findings, treeID, answers)
+
+	if err := repo.Upsert(ctx, findings); err != nil {
+		return nil, fmt.Errorf("failed to save clinical findings: %w", err)
+	}
+
+	return findingsJSONB(findings)
+}
+
+func findingsJSONB(findings *domain.ClinicalFindings) (domain.JSONB, error) {
+	data, err := json.Marshal(findings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal clinical findings: %w", err)
+	}
+	var out domain.JSONB
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal clinical findings: %w", err)
+	}
+	return out, nil
+}
+
+func yesNo(field func(*domain.ClinicalFindings) *bool) findingMapper {
+	return matches(field, "yes")
+}
+
+// dangerSign records a danger sign when the answer is value. Another tree's
+// answer never clears it.
+func dangerSign(field func(*domain.ClinicalFindings) *bool, value string) findingMapper {
+	return func(f *domain.ClinicalFindings, answer interface{}) {
+		if answerString(answer) == value {
+			*field(f) = true
+		}
+	}
+}
+
+func matches(field func(*domain.ClinicalFindings) *bool, value string) findingMapper {
+	return func(f *domain.ClinicalFindings, answer interface{}) {
+		*field(f) = answerString(answer) == value
+	}
+}
+
+func intValue(field func(*domain.ClinicalFindings) **int) findingMapper {
+	return func(f *domain.ClinicalFindings, answer interface{}) {
+		if v, ok := answerInt(answer); ok {
+			*field(f) = &v
+		}
+	}
+}
+
+func floatValue(field func(*domain.ClinicalFindings) **float64) findingMapper {
+	return func(f *domain.ClinicalFindings, answer interface{}) {
+		if v, ok := answerFloat(answer); ok {
+			*field(f) = &v
+		}
+	}
+}
+
+func hivStatusKnown(f *domain.ClinicalFindings, answer interface{}) {
+	value := answerString(answer)
+	if value == "positive" || value == "negative" {
+		f.HIVStatusKnown = true
+	}
+}
+
+// infantMovement records the movement danger signs, which two young infant
+// trees ask about. Like the other danger signs they are never cleared.
+func infantMovement(f *domain.ClinicalFindings, answer interface{}) {
+	value := answerString(answer)
+	switch {
+	case value == "moves_only_when_stimulated":
+		f.MovementOnlyWhenStimulated = true
+	case strings.HasPrefix(value, "no_movement"):
+		f.NoMovement = true
+	case value == "restless_irritable":
+		f.RestlessIrritable = true
+	}
+}
+
+func underweight(f *domain.ClinicalFindings, answer interface{}) {
+	if z, ok := answerFloat(answer); ok {
+		f.Underweight = z < -2
+	}
+}
+
+// severeWastingWithEdema records oedema, and the severe wasting it comes
+// with, when present. A "no" says nothing about oedema on its own, so the
+// pitting oedema answer is left as it is.
+func severeWastingWithEdema(f *domain.ClinicalFindings, answer interface{}) {
+	if answerString(answer) == "yes" {
+		f.BilateralEdema = true
+		f.VisibleSevereWasting = true
+	}
+}
+
+func riskFactors(f *domain.ClinicalFindings, answer interface{}) {
+	for _, value := range answerList(answer) {
+		if value != "none" && !contains(f.RiskFactorsPresent, value) {
+			f.RiskFactorsPresent = append(f.RiskFactorsPresent, value)
+		}
+	}
+}
+
+func milestoneAbsent(milestone string) findingMapper {
+	return func(f *domain.ClinicalFindings, answer interface{}) {
+		if answerString(answer) == "no" && !contains(f.MilestonesAbsent, milestone) {
+			f.MilestonesAbsent = append(f.MilestonesAbsent, milestone)
+		}
+	}
+}
+
+func answerString(answer interface{}) string {
+	if answer == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", answer)
+}
+
+func answerFloat(answer interface{}) (float64, bool) {
+	switch v := answer.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func answerInt(answer interface{}) (int, bool) {
+	f, ok := answerFloat(answer)
+	return int(f), ok
+}
+
+// answerList normalises multiple-choice answers, which clients send either
+// as a JSON array or as a comma separated string.
+func answerList(answer interface{}) []string {
+	switch v := answer.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return values
+	case string:
+		var values []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
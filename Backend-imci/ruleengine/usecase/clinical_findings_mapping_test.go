@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTreeFindings(t *testing.T) {
+	findings := &domain.ClinicalFindings{}
+
+	applyTreeFindings(findings, "child_diarrhea", map[string]interface{}{
+		"diarrhea_present":  "yes",
+		"how_long_diarrhea": float64(15),
+		"sunken_eyes":       "yes",
+		"drinking_ability":  "no",
+		"skin_pinch":        "no",
+		"skin_pinch_slow":   "yes",
+	})
+
+	assert.True(t, findings.DiarrheaPresent)
+	assert.Equal(t, 15, *findings.DiarrheaDurationDays)
+	assert.True(t, findings.SunkenEyes)
+	assert.True(t, findings.DrinkingPoorly)
+	assert.False(t, findings.SkinPinchVerySlow)
+	assert.True(t, findings.SkinPinchSlow)
+
+	applyTreeFindings(findings, "very_severe_disease_check", map[string]interface{}{
+		"breathing_rate":          "64",
+		"temperature_measurement": 35.1,
+		"check_movements":         "moves_only_when_stimulated",
+	})
+
+	assert.Equal(t, 64, *findings.RespiratoryRate)
+	assert.True(t, findings.FastBreathing)
+	assert.True(t, findings.LowBodyTemperature)
+	assert.True(t, findings.MovementOnlyWhenStimulated)
+	assert.False(t, findings.NoMovement)
+
+	applyTreeFindings(findings, "tb_assessment", map[string]interface{}{
+		"tb_symptoms_check": []interface{}{"cough_14_days", "fever_night_sweats_14_days"},
+	})
+
+	assert.Equal(t, 14, *findings.TBCoughDurationDays)
+	assert.True(t, findings.NightSweats)
+
+	applyTreeFindings(findings, "developmental_assessment", map[string]interface{}{
+		"risk_factors": []interface{}{"malnutrition", "poverty", "none"},
+	})
+
+	assert.Equal(t, []string{"malnutrition", "poverty"}, findings.RiskFactorsPresent)
+}
+
+func TestSevereWastingWithEdemaRecordsOedema(t *testing.T) {
+	findings := &domain.ClinicalFindings{}
+
+	applyTreeFindings(findings, "acute_malnutrition", map[string]interface{}{
+		"pitting_edema":                   "plus",
+		"severe_wasting_with_edema_check": "no",
+	})
+	assert.True(t, findings.BilateralEdema, "a no must not clear oedema found on the feet")
+	assert.False(t, findings.VisibleSevereWasting)
+
+	findings = &domain.ClinicalFindings{}
+	applyTreeFindings(findings, "acute_malnutrition", map[string]interface{}{
+		"severe_wasting_with_edema_check": "yes",
+	})
+	assert.True(t, findings.BilateralEdema)
+	assert.True(t, findings.VisibleSevereWasting)
+}
+
+// The danger sign tree asks whether the child is able to drink.
+func TestUnableToDrinkIsTheNoAnswer(t *testing.T) {
+	findings := &domain.ClinicalFindings{}
+	applyTreeFindings(findings, "child_general_danger_signs", map[string]interface{}{"unable_to_drink_breastfeed": "yes"})
+	assert.False(t, findings.UnableToDrink, "able to drink")
+
+	applyTreeFindings(findings, "child_general_danger_signs", map[string]interface{}{"unable_to_drink_breastfeed": "no"})
+	assert.True(t, findings.UnableToDrink, "not able to drink")
+}
+
+func TestDangerSignsAreNeverCleared(t *testing.T) {
+	findings := &domain.ClinicalFindings{}
+
+	applyTreeFindings(findings, "child_general_danger_signs", map[string]interface{}{
+		"unable_to_drink_breastfeed": "yes",
+		"lethargic_unconscious":      "yes",
+	})
+	applyTreeFindings(findings, "child_diarrhea", map[string]interface{}{
+		"lethargic_unconscious": "no",
+		"drinking_ability":      "yes",
+	})
+	assert.True(t, findings.LethargicUnconscious, "a no in another tree must not clear a danger sign")
+
+	applyTreeFindings(findings, "very_severe_disease_check", map[string]interface{}{"check_movements": "moves_only_when_stimulated"})
+	applyTreeFindings(findings, "diarrhea_check", map[string]interface{}{"movement_condition": "moves_on_own"})
+	assert.True(t, findings.MovementOnlyWhenStimulated)
+}
+
+// Mappers run in the order declared whatever the order of the answers, so
+// the severe wasting check always follows the pitting oedema grade.
+func TestApplyTreeFindingsIsOrderIndependent(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		findings := &domain.ClinicalFindings{}
+		applyTreeFindings(findings, "acute_malnutrition", map[string]interface{}{
+			"pitting_edema":                   "none",
+			"severe_wasting_with_edema_check": "yes",
+			"muac_measurement":                float64(10.5),
+		})
+		assert.True(t, findings.BilateralEdema)
+		assert.True(t, findings.VisibleSevereWasting)
+	}
+}
+
+// Every mapped node must exist in the tree it is declared for, otherwise the
+// mapping silently never fires.
+func TestTreeFindingMappersReferenceExistingNodes(t *testing.T) {
+	childEngine, err := engine.NewChildRuleEngine()
+	assert.NoError(t, err)
+	youngInfantEngine, err := engine.NewYoungInfantRuleEngine()
+	assert.NoError(t, err)
+
+	for treeID, mappers := range treeFindingMappers {
+		nodes := make(map[string]bool)
+		for _, re := range []engine.RuleEngineInterface{childEngine, youngInfantEngine} {
+			tree, err := re.GetAssessmentTree(treeID)
+			if err != nil {
+				continue
+			}
+			for _, question := range tree.QuestionsFlow {
+				nodes[question.NodeID] = true
+			}
+		}
+		assert.NotEmpty(t, nodes, "tree %s is not registered", treeID)
+
+		for _, node := range mappers {
+			assert.True(t, nodes[node.nodeID], "tree %s has no node %s", treeID, node.nodeID)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/consistency"
+	"github.com/Afomiat/Digital-IMCI/internal/followup"
+	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+)
+
+// escalateFunc applies an escalation finding to the classification it names.
+type escalateFunc func(rule *consistency.Escalation, class *domain.Classification) error
+
+// checkVisit runs once a tree is finished. It keeps the tree's answers,
+// checks the signs recorded across every tree of the visit against rules and
+// records what it found. Escalations go through escalate before they are
+// recorded.
+func checkVisit(ctx context.Context, consistencyRepo domain.ConsistencyRepository, classificationRepo domain.ClassificationRepository, rules consistency.Rules, assessment *domain.Assessment, treeID string, answers map[string]interface{}, escalate escalateFunc) ([]*domain.ConsistencyFinding, error) {
+	snapshot := &domain.TreeAnswers{
+		AssessmentID: assessment.ID,
+		TreeID:       treeID,
+		Answers:      domain.JSONB(answers),
+		CompletedAt:  time.Now(),
+	}
+	if err := consistencyRepo.SaveTreeAnswers(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := consistencyRepo.ListTreeAnswers(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+	visitAnswers := make(map[string]map[string]interface{}, len(snapshots))
+	for _, s := range snapshots {
+		visitAnswers[s.TreeID] = s.Answers
+	}
+
+	classifications, err := classificationRepo.ListByAssessmentID(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := consistency.Check(rules, visitAnswers, classifications)
+	for _, finding := range findings {
+		finding.AssessmentID = assessment.ID
+		finding.CreatedAt = time.Now()
+		if finding.Kind == domain.ConsistencyEscalation {
+			rule := rules.Escalation(finding.Code)
+			class := findClassification(classifications, finding)
+			if rule == nil || class == nil {
+				return nil, fmt.Errorf("classification not found for escalation %s", finding.Code)
+			}
+			if err := escalate(rule, class); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := consistencyRepo.SaveFinding(ctx, finding); err != nil {
+			return nil, err
+		}
+	}
+
+	return findings, nil
+}
+
+func findClassification(classifications []*domain.Classification, finding *domain.ConsistencyFinding) *domain.Classification {
+	if finding.ClassificationID == nil {
+		return nil
+	}
+	for _, c := range classifications {
+		if c.ID == *finding.ClassificationID {
+			return c
+		}
+	}
+	return nil
+}
+
+// reconcileVisit checks the child's visit with the general danger sign
+// rules and upgrades classifications a danger sign makes severe. Escalated
+// counselling is written in language.
+func (uc *ChildRuleEngineUsecase) reconcileVisit(ctx context.Context, assessment *domain.Assessment, treeID string, answers map[string]interface{}, language string) ([]*domain.ConsistencyFinding, error) {
+	return checkVisit(ctx, uc.consistencyRepo, uc.classificationRepo, consistency.Child, assessment, treeID, answers,
+		func(rule *consistency.Escalation, class *domain.Classification) error {
+			return uc.escalate(ctx, assessment, class, rule, language)
+		})
+}
+
+// escalate replaces the classification with the severe outcome of its tree.
+// The treatment plans, advice and follow-up saved for the earlier outcome
+// are deleted and those of the severe outcome saved in their place, all in
+// one transaction with the audit of the change.
+func (uc *ChildRuleEngineUsecase) escalate(ctx context.Context, assessment *domain.Assessment, class *domain.Classification, rule *consistency.Escalation, requestedLanguage string) error {
+	tree, err := uc.ruleEngine.GetAssessmentTree(rule.TreeID)
+	if err != nil {
+		return err
+	}
+	outcome, exists := tree.Outcomes[rule.Outcome]
+	if !exists {
+		return fmt.Errorf("outcome %s not found in tree %s", rule.Outcome, rule.TreeID)
+	}
+	result, language := localizeClassification(uc.translator, uc.ruleEngine, rule.TreeID, &ruleenginedomain.ClassificationResult{
+		Classification: outcome.Classification,
+		Color:          outcome.Color,
+		Emergency:      outcome.Emergency,
+		Actions:        outcome.Actions,
+		TreatmentPlan:  outcome.TreatmentPlan,
+		FollowUp:       outcome.FollowUp,
+		MotherAdvice:   outcome.MotherAdvice,
+	}, requestedLanguage)
+
+	before := *class
+	class.Disease = result.Classification
+	class.Color = result.Color
+	class.Details = result.TreatmentPlan
+	class.IsCriticalIllness = result.Emergency
+	class.RequiresUrgentReferral = result.Emergency
+	class.TreatmentPriority = uc.getTreatmentPriority(result.Classification)
+	class.FollowUpDate = followup.Date(time.Now(), result.FollowUp)
+
+	plans, err := uc.treatmentPlans(ctx, assessment, class, result)
+	if err != nil {
+		return err
+	}
+	given, err := givenAdvice(ctx, uc.counselingRepo, assessment.ID, class.ID)
+	if err != nil {
+		return err
+	}
+	counselings := uc.counselings(assessment, class, result, language, requestedLanguage, given)
+
+	auditCtx := clinicianContext(ctx, assessment)
+	entry, err := domain.NewAuditEntry(auditCtx, domain.AuditUpdate, domain.AuditClassification, class.ID, &before, class)
+	if err != nil {
+		return err
+	}
+	if err := uc.consistencyRepo.ReplaceCare(auditCtx, class, plans, counselings, []*domain.AuditEntry{entry}); err != nil {
+		return err
+	}
+
+	if uc.classificationNotifier != nil {
+		uc.classificationNotifier.NotifyClassification(ctx, assessment, class)
+	}
+
+	return nil
+}
+
+// reconcileVisit checks the young infant's visit for signs the trees
+// recorded differently. No young infant rule escalates a classification.
+func (uc *YoungInfantRuleEngineUsecase) reconcileVisit(ctx context.Context, assessment *domain.Assessment, treeID string, answers map[string]interface{}) ([]*domain.ConsistencyFinding, error) {
+	return checkVisit(ctx, uc.consistencyRepo, uc.classificationRepo, consistency.YoungInfant, assessment, treeID, answers,
+		func(rule *consistency.Escalation, class *domain.Classification) error {
+			return fmt.Errorf("young infant escalation %s is not supported", rule.Code)
+		})
+}
+
+// clinicianContext makes the clinician who owns the assessment the actor of
+// changes made without request metadata, such as flows run from the chat bots.
+func clinicianContext(ctx context.Context, assessment *domain.Assessment) context.Context {
+	if domain.RequestMetaFrom(ctx).ActorID == nil {
+		ctx = domain.WithRequestMeta(ctx, domain.RequestMeta{ActorID: &assessment.MedicalProfessionalID})
+	}
+	return ctx
+}
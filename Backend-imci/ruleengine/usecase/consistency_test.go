@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConsistencyRepo struct {
+	domain.ConsistencyRepository
+	trees    map[string]*domain.TreeAnswers
+	findings map[string]*domain.ConsistencyFinding
+	// replaced holds each escalated classification as saved.
+	replaced    []domain.Classification
+	plans       []*domain.TreatmentPlan
+	counselings []*domain.Counseling
+	audit       []*domain.AuditEntry
+}
+
+func (f *fakeConsistencyRepo) ReplaceCare(ctx context.Context, classification *domain.Classification, plans []*domain.TreatmentPlan, counselings []*domain.Counseling, audit []*domain.AuditEntry) error {
+	f.replaced = append(f.replaced, *classification)
+	f.plans = plans
+	f.counselings = counselings
+	f.audit = append(f.audit, audit...)
+	return nil
+}
+
+func (f *fakeConsistencyRepo) SaveTreeAnswers(ctx context.Context, snapshot *domain.TreeAnswers) error {
+	f.trees[snapshot.TreeID] = snapshot
+	return nil
+}
+
+func (f *fakeConsistencyRepo) ListTreeAnswers(ctx context.Context, assessmentID uuid.UUID) ([]*domain.TreeAnswers, error) {
+	snapshots := []*domain.TreeAnswers{}
+	for _, s := range f.trees {
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, nil
+}
+
+func (f *fakeConsistencyRepo) SaveFinding(ctx context.Context, finding *domain.ConsistencyFinding) (bool, error) {
+	key := finding.Code
+	if finding.ClassificationID != nil {
+		key += finding.ClassificationID.String()
+	}
+	if _, exists := f.findings[key]; exists {
+		return false, nil
+	}
+	f.findings[key] = finding
+	return true, nil
+}
+
+type fakeConsistencyClassifications struct {
+	domain.ClassificationRepository
+	classifications []*domain.Classification
+}
+
+func (f *fakeConsistencyClassifications) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Classification, error) {
+	return f.classifications, nil
+}
+
+type fakeConsistencyCounselings struct {
+	domain.CounselingRepository
+	counselings []*domain.Counseling
+}
+
+func (f *fakeConsistencyCounselings) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Counseling, error) {
+	return f.counselings, nil
+}
+
+type fakeConsistencyNotifier struct {
+	notified []*domain.Classification
+}
+
+func (f *fakeConsistencyNotifier) NotifyClassification(ctx context.Context, assessment *domain.Assessment, classification *domain.Classification) {
+	f.notified = append(f.notified, classification)
+}
+
+func findingCodes(findings []*domain.ConsistencyFinding) []string {
+	codes := []string{}
+	for _, f := range findings {
+		codes = append(codes, f.Code)
+	}
+	return codes
+}
+
+func TestReconcileVisitEscalatesAfterDangerSignTree(t *testing.T) {
+	ruleEngine, err := engine.NewChildRuleEngine()
+	require.NoError(t, err)
+
+	assessment := &domain.Assessment{ID: uuid.New(), MedicalProfessionalID: uuid.New()}
+	pneumonia := &domain.Classification{ID: uuid.New(), AssessmentID: assessment.ID, Disease: "PNEUMONIA", Color: "yellow", TreatmentPriority: 2}
+	consistency := &fakeConsistencyRepo{
+		trees: map[string]*domain.TreeAnswers{
+			"child_cough_difficult_breathing": {
+				AssessmentID: assessment.ID,
+				TreeID:       "child_cough_difficult_breathing",
+				Answers:      domain.JSONB{"general_danger_signs": "no", "fast_breathing": "yes"},
+			},
+		},
+		findings: map[string]*domain.ConsistencyFinding{},
+	}
+	classifications := &fakeConsistencyClassifications{classifications: []*domain.Classification{pneumonia}}
+	notifier := &fakeConsistencyNotifier{}
+	uc := &ChildRuleEngineUsecase{
+		ruleEngine:             ruleEngine,
+		consistencyRepo:        consistency,
+		classificationRepo:     classifications,
+		counselingRepo:         &fakeConsistencyCounselings{},
+		classificationNotifier: notifier,
+		translator:             locale.NewTranslator(),
+	}
+
+	findings, err := uc.reconcileVisit(context.Background(), assessment, "child_general_danger_signs", map[string]interface{}{
+		"unable_to_drink_breastfeed": "yes",
+		"vomits_everything":          "no",
+		"convulsions_history":        "no",
+		"lethargic_unconscious":      "yes",
+	}, locale.English)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"CONFLICTING_GENERAL_DANGER_SIGN", "DANGER_SIGN_SEVERE_PNEUMONIA"}, findingCodes(findings))
+	assert.Equal(t, "SEVERE PNEUMONIA OR VERY SEVERE DISEASE", pneumonia.Disease)
+	assert.Equal(t, "pink", pneumonia.Color)
+	assert.True(t, pneumonia.IsCriticalIllness)
+	assert.True(t, pneumonia.RequiresUrgentReferral)
+	assert.Equal(t, 1, pneumonia.TreatmentPriority)
+	// The pneumonia plans and advice are replaced by the severe outcome's
+	// together with the classification.
+	require.Len(t, consistency.replaced, 1)
+	assert.Equal(t, pneumonia.ID, consistency.replaced[0].ID)
+	require.NotEmpty(t, consistency.plans)
+	assert.Equal(t, pneumonia.ID, consistency.plans[0].ClassificationID)
+	require.NotEmpty(t, consistency.counselings)
+	assert.Equal(t, "mother_advice", consistency.counselings[0].AdviceType)
+	assert.Equal(t, pneumonia.ID, *consistency.counselings[0].ClassificationID)
+	// The change is audited as an update of the pneumonia classification by
+	// the clinician who owns the assessment.
+	require.Len(t, consistency.audit, 1)
+	entry := consistency.audit[0]
+	assert.Equal(t, domain.AuditUpdate, entry.Action)
+	assert.Equal(t, pneumonia.ID, entry.EntityID)
+	assert.Equal(t, assessment.MedicalProfessionalID, *entry.ActorID)
+	assert.Contains(t, string(entry.Before), `"disease":"PNEUMONIA"`)
+	assert.Len(t, notifier.notified, 1)
+	assert.Len(t, consistency.findings, 2)
+
+	// The escalated classification no longer matches, so a later tree only
+	// finds the contradiction again.
+	findings, err = uc.reconcileVisit(context.Background(), assessment, "child_fever", map[string]interface{}{"fever_present": "no"}, locale.English)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CONFLICTING_GENERAL_DANGER_SIGN"}, findingCodes(findings))
+	assert.Len(t, consistency.replaced, 1)
+	assert.Len(t, consistency.findings, 2)
+}
+
+func TestYoungInfantReconcileVisitOnlyReportsContradictions(t *testing.T) {
+	assessment := &domain.Assessment{ID: uuid.New(), AssessmentType: domain.TypeYoungInfant}
+	consistency := &fakeConsistencyRepo{
+		trees: map[string]*domain.TreeAnswers{
+			"very_severe_disease_check": {
+				AssessmentID: assessment.ID,
+				TreeID:       "very_severe_disease_check",
+				Answers:      domain.JSONB{"difficulty_feeding": "no", "check_movements": "moves_on_own"},
+			},
+		},
+		findings: map[string]*domain.ConsistencyFinding{},
+	}
+	classifications := &fakeConsistencyClassifications{classifications: []*domain.Classification{
+		{ID: uuid.New(), AssessmentID: assessment.ID, Disease: "SOME DEHYDRATION", Color: "yellow"},
+	}}
+	uc := &YoungInfantRuleEngineUsecase{
+		consistencyRepo:    consistency,
+		classificationRepo: classifications,
+	}
+
+	findings, err := uc.reconcileVisit(context.Background(), assessment, "diarrhea_check", map[string]interface{}{
+		"movement_condition": "moves_only_when_stimulated",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"CONFLICTING_MOVES_ONLY_WHEN_STIMULATED"}, findingCodes(findings))
+	assert.Equal(t, assessment.ID, findings[0].AssessmentID)
+	assert.Empty(t, consistency.replaced)
+	assert.Len(t, consistency.findings, 1)
+}
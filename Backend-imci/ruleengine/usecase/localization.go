@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/counseling"
+	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
+	"github.com/google/uuid"
+)
+
+type treeSource interface {
+	GetAssessmentTree(treeID string) (*ruleenginedomain.AssessmentTree, error)
+}
+
+// localizeClassification translates the mother advice of a finished flow.
+// It returns the result to show and persist, and the language its advice is
+// actually in, which is English whenever no translation exists.
+func localizeClassification(translator *locale.Translator, trees treeSource, treeID string, result *ruleenginedomain.ClassificationResult, language string) (*ruleenginedomain.ClassificationResult, string) {
+	if result == nil {
+		return nil, locale.English
+	}
+	tree, err := trees.GetAssessmentTree(treeID)
+	if err != nil {
+		return result, locale.English
+	}
+	return translator.Classification(tree, result, language)
+}
+
+// givenAdvice returns the counselling card types the visit already has,
+// leaving out the cards of replaced, a classification whose cards are about
+// to be replaced.
+func givenAdvice(ctx context.Context, repo domain.CounselingRepository, assessmentID uuid.UUID, replaced uuid.UUID) (map[string]bool, error) {
+	items, err := repo.GetByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	given := map[string]bool{}
+	for _, item := range items {
+		if item.IsStructured() && (item.ClassificationID == nil || *item.ClassificationID != replaced) {
+			given[item.AdviceType] = true
+		}
+	}
+	return given, nil
+}
+
+// counselingItems builds the counselling cards of a saved classification in
+// language, each stamped with the language its wording is actually in. The
+// general advice the visit has already given, per given, is left out.
+func counselingItems(translator *locale.Translator, assessment *domain.Assessment, class *domain.Classification, language string, given map[string]bool) []*domain.Counseling {
+	youngInfant := assessment.AssessmentType == domain.TypeYoungInfant
+	var items []*domain.Counseling
+	for _, card := range counseling.VisitCards(class.Disease, class.Color, youngInfant, given) {
+		text, lang := translator.CounselingText(card.Key, card.Text, language)
+		classificationID := class.ID
+		items = append(items, &domain.Counseling{
+			ID:               uuid.New(),
+			AssessmentID:     assessment.ID,
+			ClassificationID: &classificationID,
+			AdviceType:       card.Type,
+			Details:          text,
+			Language:         lang,
+			CreatedAt:        time.Now(),
+		})
+	}
+	return items
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
+	"github.com/google/uuid"
+)
+
+func TestCounselingItemsUseRequestedLanguage(t *testing.T) {
+	translator := locale.NewTranslator(&locale.Catalog{
+		Language:   "am",
+		Review:     locale.CatalogReview{Status: locale.ReviewApproved, ReviewedBy: "Dr. A", ReviewedAt: "2026-01-10"},
+		Counseling: map[string]string{"home_fluids.diarrhoea": "ተጨማሪ ፈሳሽ ይስጡ"},
+	})
+	assessment := &domain.Assessment{ID: uuid.New(), AssessmentType: domain.TypeChild}
+	class := &domain.Classification{ID: uuid.New(), Disease: "SOME DEHYDRATION", Color: "yellow"}
+
+	items := counselingItems(translator, assessment, class, "am", map[string]bool{})
+	if len(items) != 3 {
+		t.Fatalf("expected three items, got %d", len(items))
+	}
+	if items[0].Details != "ተጨማሪ ፈሳሽ ይስጡ" || items[0].Language != "am" {
+		t.Errorf("expected the translated fluids advice, got %q in %q", items[0].Details, items[0].Language)
+	}
+	for _, item := range items[1:] {
+		if item.Language != locale.English {
+			t.Errorf("expected untranslated %s in English, got %q", item.AdviceType, item.Language)
+		}
+	}
+	for _, item := range items {
+		if item.ClassificationID == nil || *item.ClassificationID != class.ID || item.AssessmentID != assessment.ID {
+			t.Errorf("item %s is not linked to the classification", item.AdviceType)
+		}
+	}
+}
@@ -64,6 +64,36 @@ func (_m *ClassificationRepository) GetByAssessmentID(ctx context.Context, asses
 	return r0, r1
 }
 
+// ListByAssessmentID provides a mock function with given fields: ctx, assessmentID
+func (_m *ClassificationRepository) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Classification, error) {
+	ret := _m.Called(ctx, assessmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByAssessmentID")
+	}
+
+	var r0 []*domain.Classification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.Classification, error)); ok {
+		return rf(ctx, assessmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.Classification); ok {
+		r0 = rf(ctx, assessmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Classification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, assessmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Upsert provides a mock function with given fields: ctx, classification
 func (_m *ClassificationRepository) Upsert(ctx context.Context, classification *domain.Classification) error {
 	ret := _m.Called(ctx, classification)
@@ -8,40 +8,63 @@ import (
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/followup"
 	ruleenginedomain "github.com/Afomiat/Digital-IMCI/ruleengine/domain"
 	"github.com/Afomiat/Digital-IMCI/ruleengine/engine"
+	"github.com/Afomiat/Digital-IMCI/ruleengine/locale"
 	"github.com/google/uuid"
 )
 
 type YoungInfantRuleEngineUsecase struct {
 	ruleEngine                      *engine.YoungInfantRuleEngine
 	assessmentRepo                  domain.AssessmentRepository
+	consistencyRepo                 domain.ConsistencyRepository
+	lifecycle                       domain.AssessmentLifecycleUsecase
+	vitalAlerts                     domain.VitalAlertUsecase
 	medicalProfessionalAnswerRepo   domain.MedicalProfessionalAnswerRepository
 	clinicalFindingsRepo            domain.ClinicalFindingsRepository
 	classificationRepo              domain.ClassificationRepository
 	treatmentPlanRepo               domain.TreatmentPlanRepository
 	counselingRepo                  domain.CounselingRepository
+	answerProviders                 []domain.TreeAnswerProvider
+	classificationNotifier          domain.ClassificationNotifier
+	auditor                         domain.AuditRecorder
+	translator                      *locale.Translator
 	contextTimeout                  time.Duration
 }
 
 func NewYoungInfantRuleEngineUsecase(
 	ruleEngine *engine.YoungInfantRuleEngine,
 	assessmentRepo domain.AssessmentRepository,
+	consistencyRepo domain.ConsistencyRepository,
+	lifecycle domain.AssessmentLifecycleUsecase,
+	vitalAlerts domain.VitalAlertUsecase,
 	medicalProfessionalAnswerRepo domain.MedicalProfessionalAnswerRepository,
 	clinicalFindingsRepo domain.ClinicalFindingsRepository,
 	classificationRepo domain.ClassificationRepository,
 	treatmentPlanRepo domain.TreatmentPlanRepository,
 	counselingRepo domain.CounselingRepository,
+	answerProviders []domain.TreeAnswerProvider,
+	classificationNotifier domain.ClassificationNotifier,
+	auditor domain.AuditRecorder,
+	translator *locale.Translator,
 	timeout time.Duration,
 ) *YoungInfantRuleEngineUsecase {
 	return &YoungInfantRuleEngineUsecase{
 		ruleEngine:                    ruleEngine,
 		assessmentRepo:                assessmentRepo,
+		consistencyRepo:               consistencyRepo,
+		lifecycle:                     lifecycle,
+		vitalAlerts:                   vitalAlerts,
 		medicalProfessionalAnswerRepo: medicalProfessionalAnswerRepo,
 		clinicalFindingsRepo:          clinicalFindingsRepo,
 		classificationRepo:            classificationRepo,
 		treatmentPlanRepo:             treatmentPlanRepo,
 		counselingRepo:                counselingRepo,
+		answerProviders:               answerProviders,
+		classificationNotifier:        classificationNotifier,
+		auditor:                       auditor,
+		translator:                    translator,
 		contextTimeout:                timeout,
 	}
 }
@@ -55,17 +78,41 @@ func (uc *YoungInfantRuleEngineUsecase) StartAssessmentFlow(ctx context.Context,
 		return nil, err
 	}
 
+	if err := uc.lifecycle.FlowStarted(ctx, assessment); err != nil {
+		return nil, err
+	}
+
 	flow, err := uc.ruleEngine.StartAssessmentFlow(req.AssessmentID, req.TreeID)
 	if err != nil {
 		return nil, err
 	}
 
+	currentQuestion, err := uc.ruleEngine.GetCurrentQuestion(flow)
+	if err != nil {
+		return nil, err
+	}
+
+	provided, err := collectProvidedAnswers(ctx, uc.answerProviders, assessment, req.TreeID)
+	if err != nil {
+		return nil, err
+	}
+
+	flow, currentQuestion, err = autoAnswer(uc.ruleEngine, flow, currentQuestion, provided)
+	if err != nil {
+		return nil, err
+	}
+
+	clinicalFindings, err := saveClinicalFindings(ctx, uc.clinicalFindingsRepo, req.AssessmentID, req.TreeID, flow.Answers)
+	if err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer := &domain.MedicalProfessionalAnswer{
 		ID:                 uuid.New(),
 		AssessmentID:       req.AssessmentID,
 		Answers:            domain.JSONB(flow.Answers),
 		QuestionSetVersion: req.TreeID,
-		ClinicalFindings:   domain.JSONB{},
+		ClinicalFindings:   clinicalFindings,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -74,21 +121,29 @@ func (uc *YoungInfantRuleEngineUsecase) StartAssessmentFlow(ctx context.Context,
 		return nil, fmt.Errorf("failed to save assessment flow: %w", err)
 	}
 
-	assessment.Status = domain.StatusInProgress
-	if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
-		return nil, fmt.Errorf("failed to update assessment status: %w", err)
-	}
+	classification, language := localizeClassification(uc.translator, uc.ruleEngine, req.TreeID, flow.Classification, req.Language)
 
-	currentQuestion, err := uc.ruleEngine.GetCurrentQuestion(flow)
-	if err != nil {
-		return nil, err
+	var consistency []*domain.ConsistencyFinding
+	if flow.Status != ruleenginedomain.FlowStatusInProgress {
+		if err := uc.saveClassificationResults(ctx, assessment, classification, language, req.Language); err != nil {
+			return nil, fmt.Errorf("failed to save classification results: %w", err)
+		}
+		if err := uc.lifecycle.FlowFinished(ctx, assessment, req.TreeID, classification != nil); err != nil {
+			return nil, fmt.Errorf("failed to update assessment status: %w", err)
+		}
+		if consistency, err = uc.reconcileVisit(ctx, assessment, req.TreeID, flow.Answers); err != nil {
+			return nil, fmt.Errorf("failed to check visit consistency: %w", err)
+		}
 	}
 
 	return &ruleenginedomain.StartFlowResponse{
-		SessionID:   medicalProfessionalAnswer.ID,
-		Question:    currentQuestion,
-		IsComplete:  flow.Status == ruleenginedomain.FlowStatusCompleted,
-		CurrentNode: flow.CurrentNode,
+		SessionID:      medicalProfessionalAnswer.ID,
+		Question:       uc.translator.Question(req.TreeID, currentQuestion, req.Language),
+		Classification: classification,
+		IsComplete:     flow.Status != ruleenginedomain.FlowStatusInProgress,
+		CurrentNode:    flow.CurrentNode,
+		Status:         flow.Status,
+		Consistency:    consistency,
 	}, nil
 }
 
@@ -101,6 +156,10 @@ func (uc *YoungInfantRuleEngineUsecase) SubmitAnswer(ctx context.Context, req ru
 		return nil, err
 	}
 
+	if err := uc.lifecycle.FlowStarted(ctx, assessment); err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer, err := uc.medicalProfessionalAnswerRepo.GetByAssessmentID(ctx, req.AssessmentID)
 	if err != nil {
 		return nil, domain.ErrMedicalProfessionalAnswerNotFound
@@ -128,31 +187,61 @@ func (uc *YoungInfantRuleEngineUsecase) SubmitAnswer(ctx context.Context, req ru
 		return nil, err
 	}
 
+	provided, err := collectProvidedAnswers(ctx, uc.answerProviders, assessment, flow.TreeID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedFlow, nextQuestion, err = autoAnswer(uc.ruleEngine, updatedFlow, nextQuestion, provided)
+	if err != nil {
+		return nil, err
+	}
+
+	clinicalFindings, err := saveClinicalFindings(ctx, uc.clinicalFindingsRepo, req.AssessmentID, flow.TreeID, updatedFlow.Answers)
+	if err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer.Answers = domain.JSONB(updatedFlow.Answers)
+	medicalProfessionalAnswer.ClinicalFindings = clinicalFindings
 	medicalProfessionalAnswer.UpdatedAt = time.Now()
 
 	if err := uc.medicalProfessionalAnswerRepo.Upsert(ctx, medicalProfessionalAnswer); err != nil {
 		return nil, fmt.Errorf("failed to update assessment flow: %w", err)
 	}
 
+	vitalAlerts, err := uc.vitalAlerts.Evaluate(clinicianContext(ctx, assessment), assessment, updatedFlow.Answers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate vital alerts: %w", err)
+	}
+
+	classification, language := localizeClassification(uc.translator, uc.ruleEngine, flow.TreeID, updatedFlow.Classification, req.Language)
+
+	var findings []*domain.ConsistencyFinding
 	if updatedFlow.Status == ruleenginedomain.FlowStatusCompleted || updatedFlow.Status == ruleenginedomain.FlowStatusEmergency {
-		if err := uc.saveClassificationResults(ctx, assessment, updatedFlow.Classification); err != nil {
+		if err := uc.saveClassificationResults(ctx, assessment, classification, language, req.Language); err != nil {
 			return nil, fmt.Errorf("failed to save classification results: %w", err)
 		}
 
-		assessment.Status = domain.StatusCompleted
-		if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
+		if err := uc.lifecycle.FlowFinished(ctx, assessment, flow.TreeID, classification != nil); err != nil {
 			return nil, fmt.Errorf("failed to update assessment status: %w", err)
 		}
+
+		findings, err = uc.reconcileVisit(ctx, assessment, flow.TreeID, updatedFlow.Answers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check visit consistency: %w", err)
+		}
 	}
 
 	return &ruleenginedomain.SubmitAnswerResponse{
 		SessionID:      medicalProfessionalAnswer.ID,
-		Question:       nextQuestion,
-		Classification: updatedFlow.Classification,
+		Question:       uc.translator.Question(flow.TreeID, nextQuestion, req.Language),
+		Classification: classification,
 		IsComplete:     updatedFlow.Status != ruleenginedomain.FlowStatusInProgress,
 		CurrentNode:    updatedFlow.CurrentNode,
 		Status:         updatedFlow.Status,
+		VitalAlerts:    vitalAlerts,
+		Consistency:    findings,
 	}, nil
 }
 
@@ -165,17 +254,32 @@ func (uc *YoungInfantRuleEngineUsecase) ProcessBatchAssessment(ctx context.Conte
 		return nil, err
 	}
 
+	if err := uc.lifecycle.FlowStarted(ctx, assessment); err != nil {
+		return nil, err
+	}
+
+	provided, err := collectProvidedAnswers(ctx, uc.answerProviders, assessment, req.TreeID)
+	if err != nil {
+		return nil, err
+	}
+	fillProvidedAnswers(req.Answers, provided)
+
 	flow, err := uc.ruleEngine.ProcessBatchAssessment(req.AssessmentID, req.TreeID, req.Answers)
 	if err != nil {
 		return nil, err
 	}
 
+	clinicalFindings, err := saveClinicalFindings(ctx, uc.clinicalFindingsRepo, req.AssessmentID, req.TreeID, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
 	medicalProfessionalAnswer := &domain.MedicalProfessionalAnswer{
 		ID:                 uuid.New(),
 		AssessmentID:       req.AssessmentID,
 		Answers:            domain.JSONB(req.Answers),
 		QuestionSetVersion: req.TreeID,
-		ClinicalFindings:   domain.JSONB{},
+		ClinicalFindings:   clinicalFindings,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -184,37 +288,57 @@ func (uc *YoungInfantRuleEngineUsecase) ProcessBatchAssessment(ctx context.Conte
 		return nil, fmt.Errorf("failed to save assessment answers: %w", err)
 	}
 
-	if flow.Classification != nil {
-		if err := uc.saveClassificationResults(ctx, assessment, flow.Classification); err != nil {
+	vitalAlerts, err := uc.vitalAlerts.Evaluate(clinicianContext(ctx, assessment), assessment, req.Answers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate vital alerts: %w", err)
+	}
+
+	classification, language := localizeClassification(uc.translator, uc.ruleEngine, req.TreeID, flow.Classification, req.Language)
+
+	if classification != nil {
+		if err := uc.saveClassificationResults(ctx, assessment, classification, language, req.Language); err != nil {
 			return nil, fmt.Errorf("failed to save classification results: %w", err)
 		}
-
-		assessment.Status = domain.StatusCompleted
-		if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
+	}
+	var findings []*domain.ConsistencyFinding
+	if flow.Status != ruleenginedomain.FlowStatusInProgress {
+		if err := uc.lifecycle.FlowFinished(ctx, assessment, req.TreeID, classification != nil); err != nil {
 			return nil, fmt.Errorf("failed to update assessment status: %w", err)
 		}
+		findings, err = uc.reconcileVisit(ctx, assessment, req.TreeID, req.Answers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check visit consistency: %w", err)
+		}
 	}
 
 	return &ruleenginedomain.BatchProcessResponse{
 		AssessmentID:   req.AssessmentID,
-		Classification: flow.Classification,
+		Classification: classification,
 		Status:         flow.Status,
+		VitalAlerts:    vitalAlerts,
+		Consistency:    findings,
 	}, nil
 }
 
-func (uc *YoungInfantRuleEngineUsecase) GetTreeQuestions(treeID string) (*ruleenginedomain.AssessmentTree, error) {
+// GetTreeQuestions returns the tree translated into language, falling back to
+// English for anything without a translation.
+func (uc *YoungInfantRuleEngineUsecase) GetTreeQuestions(treeID, language string) (*ruleenginedomain.AssessmentTree, error) {
 	tree, err := uc.ruleEngine.GetAssessmentTree(treeID)
 	if err != nil {
 		return nil, err
 	}
-	return tree, nil
+	return uc.translator.Tree(tree, language), nil
 }
 
 func (uc *YoungInfantRuleEngineUsecase) GetAssessmentTree(treeID string) (*ruleenginedomain.AssessmentTree, error) {
 	return uc.ruleEngine.GetAssessmentTree(treeID)
 }
 
-func (uc *YoungInfantRuleEngineUsecase) saveClassificationResults(ctx context.Context, assessment *domain.Assessment, classification *ruleenginedomain.ClassificationResult) error {
+// saveClassificationResults persists a finished flow: the classification, its
+// treatment plans and the counselling items. language is the language the
+// mother advice is in; requestedLanguage is the one negotiated for the
+// request, used for the counselling cards.
+func (uc *YoungInfantRuleEngineUsecase) saveClassificationResults(ctx context.Context, assessment *domain.Assessment, classification *ruleenginedomain.ClassificationResult, language, requestedLanguage string) error {
 	if classification == nil {
 		return nil
 	}
@@ -229,6 +353,7 @@ func (uc *YoungInfantRuleEngineUsecase) saveClassificationResults(ctx context.Co
 		IsCriticalIllness:     classification.Emergency,
 		RequiresUrgentReferral: classification.Emergency,
 		TreatmentPriority:     uc.getTreatmentPriority(classification.Classification),
+		FollowUpDate:          followup.Date(time.Now(), classification.FollowUp),
 		CreatedAt:             time.Now(),
 	}
 
@@ -241,32 +366,52 @@ func (uc *YoungInfantRuleEngineUsecase) saveClassificationResults(ctx context.Co
 	}
 
 	counseling := &domain.Counseling{
-		ID:           uuid.New(),
-		AssessmentID: assessment.ID,
-		AdviceType:   "mother_advice",
-		Details:      classification.MotherAdvice,
-		Language:     "en",
-		CreatedAt:    time.Now(),
+		ID:               uuid.New(),
+		AssessmentID:     assessment.ID,
+		ClassificationID: &class.ID,
+		AdviceType:       "mother_advice",
+		Details:          classification.MotherAdvice,
+		Language:         language,
+		CreatedAt:        time.Now(),
 	}
 
 	if err := uc.counselingRepo.Create(ctx, counseling); err != nil {
 		return err
 	}
 
+	given, err := givenAdvice(ctx, uc.counselingRepo, assessment.ID, class.ID)
+	if err != nil {
+		return err
+	}
+	for _, item := range counselingItems(uc.translator, assessment, class, requestedLanguage, given) {
+		if err := uc.counselingRepo.Create(ctx, item); err != nil {
+			return err
+		}
+	}
+
 	if len(classification.FollowUp) > 0 {
 		followUpCounseling := &domain.Counseling{
-			ID:           uuid.New(),
-			AssessmentID: assessment.ID,
-			AdviceType:   "follow_up_schedule",
-			Details:      fmt.Sprintf("Follow-up schedule: %v", strings.Join(classification.FollowUp, ", ")),
-			Language:     "en",
-			CreatedAt:    time.Now(),
+			ID:               uuid.New(),
+			AssessmentID:     assessment.ID,
+			ClassificationID: &class.ID,
+			AdviceType:       "follow_up_schedule",
+			Details:          fmt.Sprintf("Follow-up schedule: %v", strings.Join(classification.FollowUp, ", ")),
+			Language:         "en",
+			CreatedAt:        time.Now(),
 		}
 		if err := uc.counselingRepo.Create(ctx, followUpCounseling); err != nil {
 			return err
 		}
 	}
 
+	if err := uc.auditor.Record(clinicianContext(ctx, assessment), domain.AuditCreate, domain.AuditClassification, class.ID, nil, class); err != nil {
+		return err
+	}
+
+	if uc.classificationNotifier != nil {
+		uc.classificationNotifier.NotifyClassification(ctx, assessment, class)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+type dhis2Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewDHIS2Client posts to {baseURL}/api/dataValueSets with basic auth.
+// baseURL may point at a local mock server during development.
+func NewDHIS2Client(baseURL, username, password string) domain.DHIS2Client {
+	return &dhis2Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// dhis2ImportSummary covers both response shapes: DHIS2 2.36 and later wrap
+// the import summary in "response", older versions return it directly.
+type dhis2ImportSummary struct {
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	ImportCount struct {
+		Imported int `json:"imported"`
+		Updated  int `json:"updated"`
+		Ignored  int `json:"ignored"`
+		Deleted  int `json:"deleted"`
+	} `json:"importCount"`
+	Conflicts []struct {
+		Object string `json:"object"`
+		Value  string `json:"value"`
+	} `json:"conflicts"`
+	Response *dhis2ImportSummary `json:"response"`
+}
+
+func (c *dhis2Client) PushDataValueSet(ctx context.Context, set *domain.DHIS2DataValueSet) (*domain.DHIS2ImportSummary, error) {
+	payload, err := json.Marshal(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DHIS2 data values: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/dataValueSets", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DHIS2 request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach DHIS2: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	var parsed dhis2ImportSummary
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("%w: status %d", domain.ErrDHIS2PushFailed, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to decode DHIS2 response: %w", err)
+	}
+	if parsed.Response != nil {
+		parsed = *parsed.Response
+	}
+
+	summary := &domain.DHIS2ImportSummary{
+		Status:   strings.ToUpper(parsed.Status),
+		Imported: parsed.ImportCount.Imported,
+		Updated:  parsed.ImportCount.Updated,
+		Ignored:  parsed.ImportCount.Ignored,
+		Deleted:  parsed.ImportCount.Deleted,
+	}
+	for _, conflict := range parsed.Conflicts {
+		summary.Conflicts = append(summary.Conflicts, conflict.Object+": "+conflict.Value)
+	}
+
+	// DHIS2 answers 409 with an import summary when values conflict.
+	if resp.StatusCode >= 300 || summary.Status == "ERROR" {
+		reason := parsed.Description
+		if reason == "" && len(summary.Conflicts) > 0 {
+			reason = summary.Conflicts[0]
+		}
+		return summary, fmt.Errorf("%w: status %d: %s", domain.ErrDHIS2PushFailed, resp.StatusCode, reason)
+	}
+
+	return summary, nil
+}
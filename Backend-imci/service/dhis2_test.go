@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+func TestDHIS2ClientPush(t *testing.T) {
+	var received domain.DHIS2DataValueSet
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if r.URL.Path != "/api/dataValueSets" || !ok || user != "admin" || pass != "district" {
+			http.Error(w, "unexpected request", http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"httpStatus":"OK","status":"OK","response":{"status":"SUCCESS","importCount":{"imported":1,"updated":1,"ignored":0,"deleted":0}}}`))
+	}))
+	defer server.Close()
+
+	client := NewDHIS2Client(server.URL+"/", "admin", "district")
+	summary, err := client.PushDataValueSet(context.Background(), &domain.DHIS2DataValueSet{
+		DataValues: []domain.DHIS2DataValue{
+			{DataElement: "de1", Period: "202603", OrgUnit: "ou1", CategoryOptionCombo: "coc1", Value: "4"},
+			{DataElement: "de2", Period: "202603", OrgUnit: "ou1", CategoryOptionCombo: "coc1", Value: "2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PushDataValueSet: %v", err)
+	}
+	if summary.Status != "SUCCESS" || summary.Imported != 1 || summary.Updated != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if len(received.DataValues) != 2 || received.DataValues[0].Value != "4" {
+		t.Errorf("server received %+v", received)
+	}
+}
+
+func TestDHIS2ClientConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"httpStatus":"Conflict","status":"ERROR","response":{"status":"ERROR","description":"Data set not found","importCount":{"imported":0,"updated":0,"ignored":2,"deleted":0},"conflicts":[{"object":"de1","value":"Data element not found or not accessible"}]}}`))
+	}))
+	defer server.Close()
+
+	summary, err := NewDHIS2Client(server.URL, "", "").PushDataValueSet(context.Background(), &domain.DHIS2DataValueSet{})
+	if !errors.Is(err, domain.ErrDHIS2PushFailed) {
+		t.Fatalf("expected ErrDHIS2PushFailed, got %v", err)
+	}
+	if summary == nil || summary.Ignored != 2 || len(summary.Conflicts) != 1 {
+		t.Errorf("expected the import summary with conflicts, got %+v", summary)
+	}
+}
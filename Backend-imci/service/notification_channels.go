@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
+)
+
+type telegramChannel struct {
+	telegramService domain.TelegramService
+	telegramRepo    domain.TelegramRepository
+}
+
+// NewTelegramChannel delivers codes to phone numbers whose owner has linked a
+// Telegram account through the bot. It returns nil when Telegram is not
+// configured.
+func NewTelegramChannel(telegramService domain.TelegramService, telegramRepo domain.TelegramRepository) domain.NotificationChannel {
+	if telegramService == nil {
+		return nil
+	}
+	return &telegramChannel{
+		telegramService: telegramService,
+		telegramRepo:    telegramRepo,
+	}
+}
+
+func (t *telegramChannel) Name() string {
+	return domain.ChannelTelegram
+}
+
+func (t *telegramChannel) Send(ctx context.Context, n *domain.OTPNotification) error {
+	if n.Purpose.IsAlert() {
+		chatID, err := t.telegramRepo.GetChatIDByPhone(ctx, userutil.NormalizePhone(n.Phone))
+		if err != nil || chatID == 0 {
+			return domain.ErrRecipientUnreachable
+		}
+		return t.telegramService.SendMessage(ctx, chatID, n.Text)
+	}
+
+	username, err := t.telegramRepo.GetUsernameByPhone(ctx, userutil.NormalizePhone(n.Phone))
+	if err != nil || username == "" {
+		return domain.ErrRecipientUnreachable
+	}
+
+	if n.Purpose == domain.PurposePasswordResetOTP {
+		return t.telegramService.SendPasswordResetOTP(ctx, username, n.Code)
+	}
+	return t.telegramService.SendOTP(ctx, username, n.Code)
+}
+
+type whatsappChannel struct {
+	whatsappService domain.WhatsAppService
+}
+
+// NewWhatsAppChannel wraps the WhatsApp Business API. It returns nil when
+// WhatsApp is not configured.
+func NewWhatsAppChannel(whatsappService domain.WhatsAppService) domain.NotificationChannel {
+	if whatsappService == nil {
+		return nil
+	}
+	return &whatsappChannel{whatsappService: whatsappService}
+}
+
+func (w *whatsappChannel) Name() string {
+	return domain.ChannelWhatsApp
+}
+
+func (w *whatsappChannel) Send(ctx context.Context, n *domain.OTPNotification) error {
+	phoneE164 := userutil.FormatPhoneE164(n.Phone)
+	if phoneE164 == "" {
+		return fmt.Errorf("invalid phone number for WhatsApp")
+	}
+	if n.Purpose.IsAlert() {
+		return w.whatsappService.SendTemplate(ctx, phoneE164, &domain.WhatsAppTemplateMessage{
+			Kind:     domain.TemplateAlert,
+			Language: n.Language,
+			Params:   []string{n.Text},
+		})
+	}
+	return w.whatsappService.SendOTP(ctx, phoneE164, n.Code, n.Language)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/google/uuid"
+)
+
+// DefaultNotificationFallbackOrder is used when NOTIFICATION_FALLBACK_ORDER is
+// not set. SMS comes last because it is the only channel that costs money
+// per message.
+var DefaultNotificationFallbackOrder = []string{domain.ChannelWhatsApp, domain.ChannelTelegram, domain.ChannelSMS}
+
+type notificationRegistry struct {
+	channels     map[string]domain.NotificationChannel
+	order        []string
+	deliveryRepo domain.NotificationDeliveryRepository
+}
+
+// NewNotificationRegistry builds a dispatcher over the given channels. Nil
+// channels (transports that are not configured) are skipped, and channels
+// missing from the fallback order are tried after the ones listed.
+func NewNotificationRegistry(
+	deliveryRepo domain.NotificationDeliveryRepository,
+	fallbackOrder []string,
+	channels ...domain.NotificationChannel,
+) domain.NotificationDispatcher {
+	registry := &notificationRegistry{
+		channels:     make(map[string]domain.NotificationChannel),
+		deliveryRepo: deliveryRepo,
+	}
+
+	for _, ch := range channels {
+		if ch == nil {
+			continue
+		}
+		registry.channels[ch.Name()] = ch
+	}
+
+	if len(fallbackOrder) == 0 {
+		fallbackOrder = DefaultNotificationFallbackOrder
+	}
+	for _, name := range fallbackOrder {
+		if _, ok := registry.channels[name]; ok && !containsChannel(registry.order, name) {
+			registry.order = append(registry.order, name)
+		}
+	}
+	for _, ch := range channels {
+		if ch != nil && !containsChannel(registry.order, ch.Name()) {
+			registry.order = append(registry.order, ch.Name())
+		}
+	}
+
+	return registry
+}
+
+// ParseChannelOrder splits a comma separated channel list such as
+// "sms,whatsapp,telegram".
+func ParseChannelOrder(raw string) []string {
+	var order []string
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+func (r *notificationRegistry) Channels() []string {
+	return append([]string(nil), r.order...)
+}
+
+func (r *notificationRegistry) Has(channel string) bool {
+	_, ok := r.channels[channel]
+	return ok
+}
+
+func (r *notificationRegistry) Deliver(ctx context.Context, n *domain.OTPNotification, preferred string) (string, error) {
+	attempts := make([]string, 0, len(r.order)+1)
+	if r.Has(preferred) {
+		attempts = append(attempts, preferred)
+	}
+	for _, name := range r.order {
+		if name != preferred {
+			attempts = append(attempts, name)
+		}
+	}
+
+	var lastErr error
+	for _, name := range attempts {
+		err := r.channels[name].Send(ctx, n)
+		r.record(ctx, n, name, err)
+		if err == nil {
+			if name != preferred {
+				logger.Info("notification delivered on fallback channel", "phone", n.Phone, "preferred", preferred, "channel", name)
+			}
+			return name, nil
+		}
+		lastErr = err
+	}
+
+	if lastErr != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrNoNotificationChannel, lastErr)
+	}
+	return "", domain.ErrNoNotificationChannel
+}
+
+func (r *notificationRegistry) record(ctx context.Context, n *domain.OTPNotification, channel string, sendErr error) {
+	delivery := &domain.NotificationDelivery{
+		ID:        uuid.New(),
+		Phone:     n.Phone,
+		Channel:   channel,
+		Purpose:   n.Purpose,
+		Status:    domain.DeliverySent,
+		CreatedAt: time.Now(),
+	}
+
+	switch {
+	case errors.Is(sendErr, domain.ErrRecipientUnreachable):
+		delivery.Status = domain.DeliveryUnreachable
+	case sendErr != nil:
+		delivery.Status = domain.DeliveryFailed
+		delivery.Error = sendErr.Error()
+		logger.Warn("notification delivery failed", "phone", n.Phone, "channel", channel, "error", sendErr)
+	}
+
+	if r.deliveryRepo == nil {
+		return
+	}
+	if err := r.deliveryRepo.Record(ctx, delivery); err != nil {
+		logger.Error("failed to record notification delivery", "channel", channel, "error", err)
+	}
+}
+
+func containsChannel(channels []string, name string) bool {
+	for _, c := range channels {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+type fakeChannel struct {
+	name string
+	err  error
+	sent []*domain.OTPNotification
+}
+
+func (f *fakeChannel) Name() string { return f.name }
+
+func (f *fakeChannel) Send(ctx context.Context, n *domain.OTPNotification) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+type fakeDeliveryRepo struct {
+	deliveries []*domain.NotificationDelivery
+}
+
+func (f *fakeDeliveryRepo) Record(ctx context.Context, d *domain.NotificationDelivery) error {
+	f.deliveries = append(f.deliveries, d)
+	return nil
+}
+
+func (f *fakeDeliveryRepo) List(ctx context.Context, phone string, limit int) ([]*domain.NotificationDelivery, error) {
+	return f.deliveries, nil
+}
+
+func TestRegistryFallsBackInOrder(t *testing.T) {
+	telegram := &fakeChannel{name: domain.ChannelTelegram, err: domain.ErrRecipientUnreachable}
+	whatsapp := &fakeChannel{name: domain.ChannelWhatsApp, err: errors.New("template rejected")}
+	sms := &fakeChannel{name: domain.ChannelSMS}
+	log := &fakeDeliveryRepo{}
+
+	registry := NewNotificationRegistry(log, ParseChannelOrder("whatsapp, sms"), telegram, whatsapp, sms)
+
+	if got := strings.Join(registry.Channels(), ","); got != "whatsapp,sms,telegram" {
+		t.Fatalf("unexpected channel order %q", got)
+	}
+
+	n := &domain.OTPNotification{Phone: "251911000000", Code: "123456", Purpose: domain.PurposeSignupOTP}
+	channel, err := registry.Deliver(context.Background(), n, domain.ChannelTelegram)
+	if err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if channel != domain.ChannelSMS || len(sms.sent) != 1 {
+		t.Fatalf("expected delivery over SMS, got %q", channel)
+	}
+
+	want := []string{domain.DeliveryUnreachable, domain.DeliveryFailed, domain.DeliverySent}
+	if len(log.deliveries) != len(want) {
+		t.Fatalf("expected %d delivery records, got %d", len(want), len(log.deliveries))
+	}
+	for i, status := range want {
+		if log.deliveries[i].Status != status {
+			t.Errorf("delivery %d: status %q, want %q", i, log.deliveries[i].Status, status)
+		}
+		if strings.Contains(log.deliveries[i].Error, n.Code) {
+			t.Errorf("delivery %d: error leaks the code", i)
+		}
+	}
+}
+
+func TestRegistryReportsWhenNothingDelivers(t *testing.T) {
+	registry := NewNotificationRegistry(nil, nil,
+		&fakeChannel{name: domain.ChannelTelegram, err: domain.ErrRecipientUnreachable},
+		nil,
+	)
+
+	_, err := registry.Deliver(context.Background(), &domain.OTPNotification{Phone: "251911000000"}, domain.ChannelSMS)
+	if !errors.Is(err, domain.ErrNoNotificationChannel) {
+		t.Fatalf("expected ErrNoNotificationChannel, got %v", err)
+	}
+}
+
+func TestHTTPSMSChannel(t *testing.T) {
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sms := NewHTTPSMSChannel(server.URL, "key", "IMCI")
+	err := sms.Send(context.Background(), &domain.OTPNotification{
+		Phone:   "0911000000",
+		Code:    "654321",
+		Purpose: domain.PurposePasswordResetOTP,
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if body["to"] != "+251911000000" || body["from"] != "IMCI" || !strings.Contains(body["message"], "654321") {
+		t.Fatalf("unexpected gateway payload %v", body)
+	}
+
+	failing := NewHTTPSMSChannel(server.URL, "wrong", "IMCI")
+	if err := failing.Send(context.Background(), &domain.OTPNotification{Phone: "0911000000", Code: "1"}); err == nil {
+		t.Fatal("expected gateway error")
+	}
+}
+
+func TestStubSMSChannelWritesOutbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	sms := NewStubSMSChannel(path)
+
+	if err := sms.Send(context.Background(), &domain.OTPNotification{Phone: "251911000000", Code: "111222"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read outbox: %v", err)
+	}
+	if !strings.Contains(string(data), "to=251911000000") || !strings.Contains(string(data), "111222") {
+		t.Fatalf("unexpected outbox contents %q", data)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
+)
+
+// SMS providers selectable with SMS_PROVIDER.
+const (
+	SMSProviderHTTP = "http"
+	SMSProviderFile = "file"
+	SMSProviderLog  = "log"
+)
+
+// smsText renders the plain-text body for an OTP message or an alert.
+func smsText(n *domain.OTPNotification) string {
+	if n.Purpose.IsAlert() {
+		return "Digital IMCI: " + n.Text
+	}
+	if n.Purpose == domain.PurposePasswordResetOTP {
+		return fmt.Sprintf("Digital IMCI: your password reset code is %s.%s Do not share it.", n.Code, expiresIn(n.ExpiresAt, time.Now()))
+	}
+	return fmt.Sprintf("Digital IMCI: your verification code is %s.%s Do not share it.", n.Code, expiresIn(n.ExpiresAt, time.Now()))
+}
+
+// expiresIn tells the recipient how long the code stays valid, rounded up to
+// the minute. It is empty when the expiry is unknown.
+func expiresIn(expiresAt, now time.Time) string {
+	if expiresAt.IsZero() {
+		return ""
+	}
+	minutes := int(math.Ceil(expiresAt.Sub(now).Minutes()))
+	if minutes <= 1 {
+		return " It expires in 1 minute."
+	}
+	return fmt.Sprintf(" It expires in %d minutes.", minutes)
+}
+
+type httpSMSChannel struct {
+	gatewayURL string
+	apiKey     string
+	senderID   string
+	httpClient *http.Client
+}
+
+// NewHTTPSMSChannel sends SMS through a generic HTTP gateway. The gateway
+// receives a JSON body {"from", "to", "message"} with a bearer API key and
+// must answer with a 2xx status.
+func NewHTTPSMSChannel(gatewayURL, apiKey, senderID string) domain.NotificationChannel {
+	return &httpSMSChannel{
+		gatewayURL: gatewayURL,
+		apiKey:     apiKey,
+		senderID:   senderID,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (s *httpSMSChannel) Name() string {
+	return domain.ChannelSMS
+}
+
+func (s *httpSMSChannel) Send(ctx context.Context, n *domain.OTPNotification) error {
+	to := userutil.FormatPhoneE164(n.Phone)
+	if to == "" {
+		return fmt.Errorf("invalid phone number for SMS")
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"from":    s.senderID,
+		"to":      to,
+		"message": smsText(n),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal SMS payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.gatewayURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create SMS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("SMS gateway error: %s - %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	logger.Info("OTP sent via SMS", "phone", n.Phone)
+	return nil
+}
+
+type stubSMSChannel struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStubSMSChannel is a stand-in for local development: messages are
+// appended to the file at path, or written to the standard log when path is
+// empty. The codes are written in clear text, so never use it in production.
+func NewStubSMSChannel(path string) domain.NotificationChannel {
+	return &stubSMSChannel{path: path}
+}
+
+func (s *stubSMSChannel) Name() string {
+	return domain.ChannelSMS
+}
+
+func (s *stubSMSChannel) Send(ctx context.Context, n *domain.OTPNotification) error {
+	line := fmt.Sprintf("%s to=%s %s\n", time.Now().Format(time.RFC3339), n.Phone, smsText(n))
+
+	if s.path == "" {
+		logger.Info("SMS stub message", "phone", n.Phone, "text", smsText(n))
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open SMS outbox: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write SMS outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+func TestExpiresIn(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      string
+	}{
+		{"unknown expiry", time.Time{}, ""},
+		{"ten minutes", now.Add(10 * time.Minute), " It expires in 10 minutes."},
+		{"part minute rounds up", now.Add(4*time.Minute + 10*time.Second), " It expires in 5 minutes."},
+		{"under a minute", now.Add(20 * time.Second), " It expires in 1 minute."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expiresIn(tt.expiresAt, now); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSMSTextUsesExpiry(t *testing.T) {
+	text := smsText(&domain.OTPNotification{
+		Code:      "123456",
+		Purpose:   domain.PurposePasswordResetOTP,
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+	})
+	if !strings.Contains(text, "123456") || !strings.Contains(text, "15 minutes") {
+		t.Errorf("unexpected SMS text %q", text)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
 	"github.com/Afomiat/Digital-IMCI/internal/userutil"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -20,6 +21,8 @@ type telegramBotService struct {
 	botUsername  string
 	token        string
 	isRunning    bool
+	companion    domain.ClinicalCompanionUsecase
+	chat         domain.ChatAssessmentUsecase
 	mu           sync.RWMutex
 }
 
@@ -97,7 +100,33 @@ func (t *telegramBotService) GetStartLink() string {
 	return fmt.Sprintf("https://t.me/%s", t.botUsername)
 }
 
+func (t *telegramBotService) AttachCompanion(companion domain.ClinicalCompanionUsecase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.companion = companion
+}
+
+func (t *telegramBotService) getCompanion() domain.ClinicalCompanionUsecase {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.companion
+}
+
+func (t *telegramBotService) SendMessage(ctx context.Context, chatID int64, text string) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+	if _, err := t.bot.Send(msg); err != nil {
+		return fmt.Errorf("failed to send Telegram message: %w", err)
+	}
+	return nil
+}
+
 func (t *telegramBotService) handleUpdate(update tgbotapi.Update) {
+	if update.CallbackQuery != nil {
+		t.handleCallback(update.CallbackQuery)
+		return
+	}
+
 	if update.Message == nil {
 		return
 	}
@@ -119,6 +148,24 @@ func (t *telegramBotService) handleUpdate(update tgbotapi.Update) {
 		t.sendHelpMessage(update.Message.Chat.ID)
 		return
 	}
+
+	if update.Message.IsCommand() {
+		switch update.Message.Command() {
+		case "followups":
+			t.handleFollowUps(update.Message.Chat.ID)
+		case "patient":
+			t.handlePatientLookup(update.Message.Chat.ID, update.Message.CommandArguments())
+		case "summary":
+			t.handleSummary(update.Message.Chat.ID, update.Message.CommandArguments())
+		case "assess", "cancel":
+			t.handleChat(update.Message.Chat.ID, update.Message.Text, true)
+		}
+		return
+	}
+
+	if update.Message.Text != "" {
+		t.handleChat(update.Message.Chat.ID, update.Message.Text, false)
+	}
 }
 
 func (t *telegramBotService) handleStartCommand(update tgbotapi.Update) {
@@ -148,6 +195,13 @@ func (t *telegramBotService) handleContact(update tgbotapi.Update) {
 		return
 	}
 
+	// Only the sender's own contact, shared with the button, links an
+	// account; a forwarded contact would link someone else's phone.
+	if update.Message.From == nil || contact.UserID != update.Message.From.ID {
+		t.reply(chatID, "❌ Please share your own phone number using the button below.")
+		return
+	}
+
 	phone := contact.PhoneNumber
 	username := update.Message.From.UserName
 
@@ -196,7 +250,7 @@ func (t *telegramBotService) linkAccountAndSendOTP(username string, chatID int64
 	)
 
 	for _, candidate := range candidatePhones(phone) {
-		log.Printf("Attempting to match OTP for candidate phone: %s", candidate)
+		logger.Debug("matching pending signup", "phone", candidate)
 		otp, err = t.otpRepo.GetOtpByPhone(ctx, candidate)
 		if err == nil {
 			found = true
@@ -208,7 +262,7 @@ func (t *telegramBotService) linkAccountAndSendOTP(username string, chatID int64
 			continue
 		}
 
-		log.Printf("Failed to fetch OTP for phone %s: %v", candidate, err)
+		logger.Error("failed to fetch pending signup", "phone", candidate, "error", err)
 		t.reply(chatID, "❌ Error retrieving your OTP. Please try again.")
 		return
 	}
@@ -246,7 +300,7 @@ func (t *telegramBotService) linkAccountAndSendOTP(username string, chatID int64
 	msg2.ParseMode = "HTML"
 	t.bot.Send(msg2)
 
-	log.Printf("Successfully delivered existing OTP to @%s for phone %s", username, storage)
+	logger.Info("delivered signup OTP via Telegram", "telegram_username", username, "phone", storage)
 }
 
 func (t *telegramBotService) reply(chatID int64, message string) {
@@ -269,7 +323,13 @@ func (t *telegramBotService) sendHelpMessage(chatID int64) {
 			"1. Visit our app and start signup\n"+
 			"2. Click 'Link Telegram' button\n"+
 			"3. Use the provided link\n\n"+
-			"Your OTP will be sent automatically after linking!",
+			"Your OTP will be sent automatically after linking!\n\n"+
+			"Once linked:\n"+
+			"/followups - patients due for follow-up today\n"+
+			"/patient &lt;name&gt; - find a patient and their recent visits\n"+
+			"/summary &lt;assessment id&gt; - get a PDF visit summary\n"+
+			"/assess &lt;patient name&gt; - run an IMCI assessment in this chat\n"+
+			"/cancel - stop the assessment chat",
 	)
 	msg.ParseMode = "HTML"
 	t.bot.Send(msg)
@@ -297,7 +357,7 @@ func (t *telegramBotService) SendOTP(ctx context.Context, telegramUsername, code
 		return fmt.Errorf("failed to send Telegram message to @%s: %w", telegramUsername, err)
 	}
 
-	log.Printf("OTP %s sent successfully to @%s (Chat ID: %d)", code, telegramUsername, chatID)
+	logger.Info("OTP sent via Telegram", "telegram_username", telegramUsername, "chat_id", chatID)
 	return nil
 }
 
@@ -326,6 +386,6 @@ func (t *telegramBotService) SendPasswordResetOTP(ctx context.Context, telegramU
 		return fmt.Errorf("failed to send Telegram message to @%s: %w", telegramUsername, err)
 	}
 
-	log.Printf("Password reset OTP %s sent successfully to @%s (Chat ID: %d)", code, telegramUsername, chatID)
+	logger.Info("password reset OTP sent via Telegram", "telegram_username", telegramUsername, "chat_id", chatID)
 	return nil
 }
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Telegram rejects callback data longer than 64 bytes.
+const maxCallbackData = 64
+
+func (t *telegramBotService) AttachChatAssessment(chat domain.ChatAssessmentUsecase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.chat = chat
+}
+
+func (t *telegramBotService) getChat() domain.ChatAssessmentUsecase {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.chat
+}
+
+// SendChatReply sends a plain-text reply with its buttons as an inline
+// keyboard, one button per row. The recipient is the chat ID.
+func (t *telegramBotService) SendChatReply(ctx context.Context, recipient string, reply *domain.ChatReply) error {
+	chatID, err := strconv.ParseInt(recipient, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Telegram chat ID %q: %w", recipient, err)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, reply.Text)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, b := range reply.Buttons {
+		if len(b.ID) > maxCallbackData {
+			logger.Warn("skipping chat button with oversized callback data", "id", b.ID)
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(b.Title, b.ID)))
+	}
+	if len(rows) > 0 {
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	}
+
+	if _, err := t.bot.Send(msg); err != nil {
+		return fmt.Errorf("failed to send Telegram message: %w", err)
+	}
+	return nil
+}
+
+// handleChat passes a message to the assessment chat. Free text outside an
+// assessment is ignored; explicit is set for commands and button presses,
+// which always get an answer.
+func (t *telegramBotService) handleChat(chatID int64, text string, explicit bool) {
+	ctx := context.Background()
+
+	chat, companion := t.getChat(), t.getCompanion()
+	if chat == nil || companion == nil {
+		if explicit {
+			t.reply(chatID, "⚠️ This feature is not available right now.")
+		}
+		return
+	}
+
+	mp, err := companion.Authenticate(ctx, chatID)
+	if err != nil {
+		if explicit {
+			t.reply(chatID, "🔒 This chat is not linked to a Digital IMCI account. Send /start and share your phone number first.")
+		}
+		return
+	}
+
+	recipient := strconv.FormatInt(chatID, 10)
+	replies, err := chat.HandleMessage(ctx, "telegram:"+recipient, mp, text)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrChatSessionNotFound):
+		if explicit {
+			t.reply(chatID, "This assessment chat has ended. Send /assess &lt;patient name&gt; to start again.")
+		}
+		return
+	case errors.Is(err, domain.ErrChatUnavailable):
+		t.reply(chatID, "⚠️ Decision support is not available right now.")
+		return
+	case errors.Is(err, domain.ErrAssessmentNotFound):
+		t.reply(chatID, "⚠️ Assessment not found.")
+		return
+	default:
+		logger.Error("assessment chat failed", "chat_id", chatID, "error", err)
+		t.reply(chatID, "❌ Something went wrong. Please try again, or send /cancel.")
+		return
+	}
+
+	for _, r := range replies {
+		if err := t.SendChatReply(ctx, recipient, r); err != nil {
+			logger.Error("failed to send assessment chat reply", "chat_id", chatID, "error", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+)
+
+const summaryCallbackPrefix = "summary:"
+
+// companionFor resolves the clinician behind a chat, replying with the reason
+// when the command cannot be served.
+func (t *telegramBotService) companionFor(ctx context.Context, chatID int64) (domain.ClinicalCompanionUsecase, *domain.MedicalProfessional, bool) {
+	companion := t.getCompanion()
+	if companion == nil {
+		t.reply(chatID, "⚠️ This feature is not available right now.")
+		return nil, nil, false
+	}
+
+	mp, err := companion.Authenticate(ctx, chatID)
+	if err != nil {
+		t.reply(chatID, "🔒 This chat is not linked to a Digital IMCI account. Send /start and share your phone number first.")
+		return nil, nil, false
+	}
+
+	return companion, mp, true
+}
+
+func (t *telegramBotService) handleFollowUps(chatID int64) {
+	ctx := context.Background()
+	companion, mp, ok := t.companionFor(ctx, chatID)
+	if !ok {
+		return
+	}
+
+	due, err := companion.DueFollowUps(ctx, mp.ID, time.Now())
+	if err != nil {
+		logger.Error("failed to list follow-ups", "chat_id", chatID, "error", err)
+		t.reply(chatID, "❌ Could not load your follow-ups. Please try again.")
+		return
+	}
+
+	if len(due) == 0 {
+		t.reply(chatID, "✅ No follow-up visits are due today.")
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📅 <b>Follow-ups due today (%d)</b>\n", len(due))
+	for _, f := range due {
+		fmt.Fprintf(&b, "\n• <b>%s</b> - %s", html.EscapeString(f.PatientName), html.EscapeString(f.Disease))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = summaryKeyboard(followUpButtons(due))
+	t.bot.Send(msg)
+}
+
+func (t *telegramBotService) handlePatientLookup(chatID int64, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		t.reply(chatID, "Usage: /patient &lt;name&gt;")
+		return
+	}
+
+	ctx := context.Background()
+	companion, mp, ok := t.companionFor(ctx, chatID)
+	if !ok {
+		return
+	}
+
+	results, err := companion.FindPatients(ctx, mp.ID, name)
+	if err != nil {
+		logger.Error("failed to search patients", "chat_id", chatID, "error", err)
+		t.reply(chatID, "❌ Could not search patients. Please try again.")
+		return
+	}
+
+	if len(results) == 0 {
+		t.reply(chatID, fmt.Sprintf("No patients named \"%s\" among your assessments.", html.EscapeString(name)))
+		return
+	}
+
+	for _, r := range results {
+		var b strings.Builder
+		fmt.Fprintf(&b, "👶 <b>%s</b>\nBorn %s\n\nRecent visits:", html.EscapeString(r.Patient.Name), r.Patient.DateOfBirth.Format("2 Jan 2006"))
+
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for _, a := range r.Assessments {
+			fmt.Fprintf(&b, "\n• %s (%s)", a.StartTime.Format("2 Jan 2006"), a.Status)
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("📄 "+a.StartTime.Format("2 Jan 2006"), summaryCallbackPrefix+a.ID.String()),
+			))
+		}
+
+		msg := tgbotapi.NewMessage(chatID, b.String())
+		msg.ParseMode = "HTML"
+		msg.ReplyMarkup = summaryKeyboard(rows)
+		t.bot.Send(msg)
+	}
+}
+
+func (t *telegramBotService) handleSummary(chatID int64, arg string) {
+	assessmentID, err := uuid.Parse(strings.TrimSpace(arg))
+	if err != nil {
+		t.reply(chatID, "Usage: /summary &lt;assessment id&gt;")
+		return
+	}
+	t.sendVisitSummary(chatID, assessmentID)
+}
+
+func (t *telegramBotService) handleCallback(query *tgbotapi.CallbackQuery) {
+	t.bot.Request(tgbotapi.NewCallback(query.ID, ""))
+
+	if query.Message == nil {
+		return
+	}
+	if !strings.HasPrefix(query.Data, summaryCallbackPrefix) {
+		t.handleChat(query.Message.Chat.ID, query.Data, true)
+		return
+	}
+
+	assessmentID, err := uuid.Parse(strings.TrimPrefix(query.Data, summaryCallbackPrefix))
+	if err != nil {
+		return
+	}
+	t.sendVisitSummary(query.Message.Chat.ID, assessmentID)
+}
+
+func (t *telegramBotService) sendVisitSummary(chatID int64, assessmentID uuid.UUID) {
+	ctx := context.Background()
+	companion, mp, ok := t.companionFor(ctx, chatID)
+	if !ok {
+		return
+	}
+
+	pdf, err := companion.VisitSummaryPDF(ctx, mp.ID, assessmentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAssessmentNotFound) {
+			t.reply(chatID, "⚠️ Assessment not found.")
+			return
+		}
+		logger.Error("failed to render visit summary", "assessment_id", assessmentID, "error", err)
+		t.reply(chatID, "❌ Could not create the visit summary. Please try again.")
+		return
+	}
+
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("visit-summary-%s.pdf", assessmentID.String()[:8]),
+		Bytes: pdf,
+	})
+	if _, err := t.bot.Send(doc); err != nil {
+		logger.Error("failed to send visit summary", "assessment_id", assessmentID, "error", err)
+	}
+}
+
+func followUpButtons(due []*domain.FollowUp) [][]tgbotapi.InlineKeyboardButton {
+	seen := make(map[uuid.UUID]bool)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, f := range due {
+		if seen[f.AssessmentID] {
+			continue
+		}
+		seen[f.AssessmentID] = true
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📄 "+f.PatientName, summaryCallbackPrefix+f.AssessmentID.String()),
+		))
+	}
+	return rows
+}
+
+// summaryKeyboard returns nil for no rows, since Telegram rejects an empty
+// inline keyboard.
+func summaryKeyboard(rows [][]tgbotapi.InlineKeyboardButton) interface{} {
+	if len(rows) == 0 {
+		return nil
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
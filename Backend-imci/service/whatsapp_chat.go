@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+// WhatsApp interactive message limits.
+const (
+	waMaxReplyButtons   = 3
+	waMaxButtonTitle    = 20
+	waMaxListRows       = 10
+	waMaxRowTitle       = 24
+	waMaxRowDescription = 72
+	waMaxInteractive    = 1024
+)
+
+// SendChatReply renders a reply as reply buttons when it has at most three
+// short choices, as a list when it has up to ten, and as numbered plain text
+// otherwise; the chat adapter accepts the number as an answer.
+func (m *MetaWhatsAppClient) SendChatReply(ctx context.Context, recipient string, reply *domain.ChatReply) error {
+	to, err := m.formatPhoneNumber(recipient)
+	if err != nil {
+		return fmt.Errorf("invalid phone number: %w", err)
+	}
+	_, err = m.postMessage(ctx, whatsAppChatPayload(to, reply))
+	return err
+}
+
+func whatsAppChatPayload(to string, reply *domain.ChatReply) map[string]interface{} {
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+	}
+
+	buttons := reply.Buttons
+	switch {
+	case len(buttons) == 0:
+		payload["type"] = "text"
+		payload["text"] = map[string]interface{}{"body": reply.Text}
+
+	case len(buttons) <= waMaxReplyButtons && shortTitles(buttons, waMaxButtonTitle):
+		rows := make([]map[string]interface{}, len(buttons))
+		for i, b := range buttons {
+			rows[i] = map[string]interface{}{
+				"type":  "reply",
+				"reply": map[string]interface{}{"id": b.ID, "title": b.Title},
+			}
+		}
+		payload["type"] = "interactive"
+		payload["interactive"] = map[string]interface{}{
+			"type":   "button",
+			"body":   map[string]interface{}{"text": truncate(reply.Text, waMaxInteractive)},
+			"action": map[string]interface{}{"buttons": rows},
+		}
+
+	case len(buttons) <= waMaxListRows:
+		rows := make([]map[string]interface{}, len(buttons))
+		for i, b := range buttons {
+			row := map[string]interface{}{"id": b.ID, "title": truncate(b.Title, waMaxRowTitle)}
+			if utf8.RuneCountInString(b.Title) > waMaxRowTitle {
+				row["description"] = truncate(b.Title, waMaxRowDescription)
+			}
+			rows[i] = row
+		}
+		payload["type"] = "interactive"
+		payload["interactive"] = map[string]interface{}{
+			"type": "list",
+			"body": map[string]interface{}{"text": truncate(reply.Text, waMaxInteractive)},
+			"action": map[string]interface{}{
+				"button":   "Choose",
+				"sections": []map[string]interface{}{{"title": "Options", "rows": rows}},
+			},
+		}
+
+	default:
+		var b strings.Builder
+		b.WriteString(reply.Text)
+		b.WriteString("\n")
+		for i, button := range buttons {
+			b.WriteString("\n" + strconv.Itoa(i+1) + ". " + button.Title)
+		}
+		b.WriteString("\n\nReply with the number of your choice.")
+		payload["type"] = "text"
+		payload["text"] = map[string]interface{}{"body": b.String()}
+	}
+
+	return payload
+}
+
+func shortTitles(buttons []domain.ChatButton, max int) bool {
+	for _, b := range buttons {
+		if utf8.RuneCountInString(b.Title) > max {
+			return false
+		}
+	}
+	return true
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
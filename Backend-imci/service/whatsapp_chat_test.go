@@ -0,0 +1,45 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+func TestWhatsAppChatPayload(t *testing.T) {
+	buttons := func(titles ...string) []domain.ChatButton {
+		out := make([]domain.ChatButton, len(titles))
+		for i, title := range titles {
+			out[i] = domain.ChatButton{ID: "ans:" + title, Title: title}
+		}
+		return out
+	}
+	interactiveType := func(p map[string]interface{}) string {
+		if p["type"] != "interactive" {
+			return p["type"].(string)
+		}
+		return p["interactive"].(map[string]interface{})["type"].(string)
+	}
+
+	if got := interactiveType(whatsAppChatPayload("251911000000", &domain.ChatReply{Text: "hi"})); got != "text" {
+		t.Fatalf("plain reply rendered as %s", got)
+	}
+	if got := interactiveType(whatsAppChatPayload("251911000000", &domain.ChatReply{Text: "q", Buttons: buttons("yes", "no")})); got != "button" {
+		t.Fatalf("two short choices rendered as %s", got)
+	}
+	long := buttons("Moves only when stimulated", "No movement at all")
+	if got := interactiveType(whatsAppChatPayload("251911000000", &domain.ChatReply{Text: "q", Buttons: long})); got != "list" {
+		t.Fatalf("long titles rendered as %s", got)
+	}
+
+	many := buttons("a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k")
+	payload := whatsAppChatPayload("251911000000", &domain.ChatReply{Text: "q", Buttons: many})
+	if interactiveType(payload) != "text" {
+		t.Fatal("more than ten choices should fall back to a numbered list")
+	}
+	body := payload["text"].(map[string]interface{})["body"].(string)
+	if !strings.Contains(body, "11. k") {
+		t.Fatalf("numbered list missing entries: %q", body)
+	}
+}
@@ -14,88 +14,132 @@ import (
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
 )
 
+// DefaultGraphAPIURL is the Graph API version the client was written against.
+const DefaultGraphAPIURL = "https://graph.facebook.com/v22.0"
+
 type MetaWhatsAppClient struct {
 	accessToken   string
 	phoneNumberID string
+	graphURL      string
+	templates     *WhatsAppTemplateRegistry
 	httpClient    *http.Client
 }
 
-func NewMetaWhatsAppService(accessToken, phoneNumberID string) domain.WhatsAppService {
+// NewMetaWhatsAppService talks to the WhatsApp Cloud API. graphURL overrides
+// DefaultGraphAPIURL, e.g. to point at a local fake in tests, and templates
+// defaults to DefaultWhatsAppTemplates.
+func NewMetaWhatsAppService(accessToken, phoneNumberID, graphURL string, templates *WhatsAppTemplateRegistry) domain.WhatsAppService {
 	if accessToken == "" {
 		log.Fatal("WABA_ACCESS_TOKEN is required")
 	}
 	if phoneNumberID == "" {
 		log.Fatal("WABA_PHONE_NUMBER_ID is required")
 	}
+	if graphURL == "" {
+		graphURL = DefaultGraphAPIURL
+	}
+	if templates == nil {
+		templates = DefaultWhatsAppTemplates()
+	}
 	
 	log.Printf("WhatsApp Service initialized with Phone Number ID: %s", phoneNumberID)
-	log.Printf("Access token length: %d characters", len(accessToken))
 	
 	return &MetaWhatsAppClient{
 		accessToken:   accessToken,
 		phoneNumberID: phoneNumberID,
+		graphURL:      strings.TrimRight(graphURL, "/"),
+		templates:     templates,
 		httpClient:    &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
-func (m *MetaWhatsAppClient) SendOTP(ctx context.Context, phoneNumber, code string) error {
-	log.Printf("Attempting to send WhatsApp OTP to: %s", phoneNumber)
+func (m *MetaWhatsAppClient) SendOTP(ctx context.Context, phoneNumber, code, language string) error {
+	logger.Info("sending OTP via WhatsApp", "phone", phoneNumber, "language", language)
+
+	return m.SendTemplate(ctx, phoneNumber, &domain.WhatsAppTemplateMessage{
+		Kind:     domain.TemplateOTP,
+		Language: language,
+		Params:   []string{code},
+	})
+}
 
+func (m *MetaWhatsAppClient) SendTemplate(ctx context.Context, phoneNumber string, msg *domain.WhatsAppTemplateMessage) error {
 	cleanNumber, err := m.formatPhoneNumber(phoneNumber)
 	if err != nil {
 		return fmt.Errorf("invalid phone number: %w", err)
 	}
 
-	log.Printf("Formatted phone number: %s", cleanNumber)
+	template, err := m.templates.Resolve(msg.Kind, msg.Language)
+	if err != nil {
+		return err
+	}
+	if len(msg.Params) != template.BodyParams {
+		return fmt.Errorf("template %s expects %d parameters, got %d", template.Name, template.BodyParams, len(msg.Params))
+	}
+
+	params := make([]map[string]interface{}, len(msg.Params))
+	for i, p := range msg.Params {
+		params[i] = map[string]interface{}{"type": "text", "text": p}
+	}
+	components := []map[string]interface{}{
+		{
+			"type":       "body",
+			"parameters": params,
+		},
+	}
+	// Authentication templates carry the code again on their copy-code
+	// button.
+	if template.CodeButton && len(msg.Params) > 0 {
+		components = append(components, map[string]interface{}{
+			"type":     "button",
+			"sub_type": "url",
+			"index":    "0",
+			"parameters": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": msg.Params[0],
+				},
+			},
+		})
+	}
 
 	payload := map[string]interface{}{
 		"messaging_product": "whatsapp",
 		"to":                cleanNumber,
 		"type":              "template",
 		"template": map[string]interface{}{
-			"name": "digital_imci_otp", 
+			"name": template.Name,
 			"language": map[string]interface{}{
-				"code": "en",
-			},
-			"components": []map[string]interface{}{
-				{
-					"type": "body",
-					"parameters": []map[string]interface{}{
-						{
-							"type": "text",
-							"text": code,
-						},
-					},
-				},
-				{
-					"type": "button",
-					"sub_type": "url",
-					"index": "0",
-					"parameters": []map[string]interface{}{
-						{
-							"type": "text",
-							"text": code,
-						},
-					},
-				},
+				"code": template.Language,
 			},
+			"components": components,
 		},
 	}
 
+	messageID, err := m.postMessage(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("WhatsApp template sent", "phone", cleanNumber, "template", template.Name, "language", template.Language, "message_id", messageID)
+	return nil
+}
+
+// postMessage sends a message payload and returns the WhatsApp message ID,
+// which later status callbacks refer to.
+func (m *MetaWhatsAppClient) postMessage(ctx context.Context, payload map[string]interface{}) (string, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	url := fmt.Sprintf("https://graph.facebook.com/v22.0/%s/messages", m.phoneNumberID)
-	log.Printf("Sending request to: %s", url)
-	log.Printf("Request payload: %s", string(jsonData))
-	
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	url := fmt.Sprintf("%s/%s/messages", m.graphURL, m.phoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -103,43 +147,37 @@ func (m *MetaWhatsAppClient) SendOTP(ctx context.Context, phoneNumber, code stri
 
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send WhatsApp message: %w", err)
+		return "", fmt.Errorf("failed to send WhatsApp message: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
-	log.Printf("Meta API Response Status: %s", resp.Status)
-	log.Printf("Meta API Response Body: %s", string(body))
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse map[string]interface{}
-		if err := json.Unmarshal(body, &errorResponse); err != nil {
-			return fmt.Errorf("WhatsApp API error: %s - %s", resp.Status, string(body))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errorResponse struct {
+			Error struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
 		}
-		
-		if errorData, ok := errorResponse["error"].(map[string]interface{}); ok {
-			errorCode := "unknown"
-			if code, ok := errorData["code"].(float64); ok {
-				errorCode = fmt.Sprintf("%.0f", code)
-			}
-			
-			errorMessage := "unknown error"
-			if msg, ok := errorData["message"].(string); ok {
-				errorMessage = msg
-			}
-			
-			return fmt.Errorf("WhatsApp API error [%s]: %s", errorCode, errorMessage)
+		if err := json.Unmarshal(body, &errorResponse); err != nil || errorResponse.Error.Message == "" {
+			return "", fmt.Errorf("WhatsApp API error: %s - %s", resp.Status, string(body))
 		}
-		
-		return fmt.Errorf("WhatsApp API error: %s - %s", resp.Status, string(body))
+		return "", fmt.Errorf("WhatsApp API error [%d]: %s", errorResponse.Error.Code, errorResponse.Error.Message)
 	}
 
-	log.Printf("WhatsApp OTP %s sent successfully to %s", code, cleanNumber)
-	return nil
+	var sent struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &sent); err != nil || len(sent.Messages) == 0 {
+		return "", nil
+	}
+	return sent.Messages[0].ID, nil
 }
 
 func (m *MetaWhatsAppClient) GetStartLink() string {
@@ -174,4 +212,3 @@ func (m *MetaWhatsAppClient) formatPhoneNumber(phoneNumber string) (string, erro
 
 	return cleanNumber, nil
 }
-
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+// FallbackTemplateLanguage is used when a template has no translation in the
+// requested language.
+const FallbackTemplateLanguage = "en"
+
+// WhatsAppTemplate is a template approved in WhatsApp Manager for one
+// language.
+type WhatsAppTemplate struct {
+	Name string
+	// Language is the WhatsApp language code the template was approved in.
+	Language string
+	// BodyParams is the number of {{n}} placeholders in the body.
+	BodyParams int
+	// CodeButton marks authentication templates whose copy-code button
+	// repeats the first parameter.
+	CodeButton bool
+}
+
+type templateKey struct {
+	kind     domain.WhatsAppTemplateKind
+	language string
+}
+
+// WhatsAppTemplateRegistry maps a template kind and language onto an approved
+// template.
+type WhatsAppTemplateRegistry struct {
+	mu        sync.RWMutex
+	templates map[templateKey]WhatsAppTemplate
+}
+
+func NewWhatsAppTemplateRegistry() *WhatsAppTemplateRegistry {
+	return &WhatsAppTemplateRegistry{templates: make(map[templateKey]WhatsAppTemplate)}
+}
+
+// DefaultWhatsAppTemplates registers the Digital IMCI templates in English,
+// Amharic, Afaan Oromo and Tigrinya. Each translation has to be approved
+// under the same name before it can be sent.
+//
+//	otp:   {{1}} code
+//	alert: {{1}} alert text, for district focal persons and clinicians
+func DefaultWhatsAppTemplates() *WhatsAppTemplateRegistry {
+	r := NewWhatsAppTemplateRegistry()
+	for _, language := range []string{"en", "am", "om", "ti"} {
+		r.Register(domain.TemplateOTP, language, WhatsAppTemplate{Name: "digital_imci_otp", Language: language, BodyParams: 1, CodeButton: true})
+		r.Register(domain.TemplateAlert, language, WhatsAppTemplate{Name: "digital_imci_alert", Language: language, BodyParams: 1})
+	}
+	return r
+}
+
+func (r *WhatsAppTemplateRegistry) Register(kind domain.WhatsAppTemplateKind, language string, template WhatsAppTemplate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[templateKey{kind, normalizeLanguage(language)}] = template
+}
+
+// Resolve returns the template for kind in language, falling back to the
+// base language ("am" for "am-ET") and then to English.
+func (r *WhatsAppTemplateRegistry) Resolve(kind domain.WhatsAppTemplateKind, language string) (WhatsAppTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	language = normalizeLanguage(language)
+	candidates := []string{language}
+	if i := strings.IndexAny(language, "-_"); i > 0 {
+		candidates = append(candidates, language[:i])
+	}
+	candidates = append(candidates, FallbackTemplateLanguage)
+
+	for _, candidate := range candidates {
+		if t, ok := r.templates[templateKey{kind, candidate}]; ok {
+			return t, nil
+		}
+	}
+	return WhatsAppTemplate{}, fmt.Errorf("%w: %s", domain.ErrUnknownWhatsAppTemplate, kind)
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
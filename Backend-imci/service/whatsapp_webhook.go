@@ -0,0 +1,159 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+const whatsAppSignaturePrefix = "sha256="
+
+// VerifyWhatsAppSignature checks the X-Hub-Signature-256 header, an HMAC-SHA256
+// of the raw request body keyed with the app secret.
+func VerifyWhatsAppSignature(appSecret string, body []byte, header string) bool {
+	if appSecret == "" || !strings.HasPrefix(header, whatsAppSignaturePrefix) {
+		return false
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(header, whatsAppSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// Webhook payload as sent by the WhatsApp Cloud API. Only the fields we use
+// are declared.
+type waWebhookPayload struct {
+	Object string `json:"object"`
+	Entry  []struct {
+		Changes []struct {
+			Field string `json:"field"`
+			Value struct {
+				Contacts []struct {
+					WaID    string `json:"wa_id"`
+					Profile struct {
+						Name string `json:"name"`
+					} `json:"profile"`
+				} `json:"contacts"`
+				Messages []struct {
+					ID        string `json:"id"`
+					From      string `json:"from"`
+					Timestamp string `json:"timestamp"`
+					Type      string `json:"type"`
+					Text      struct {
+						Body string `json:"body"`
+					} `json:"text"`
+					Button struct {
+						Payload string `json:"payload"`
+						Text    string `json:"text"`
+					} `json:"button"`
+					Interactive struct {
+						Type        string     `json:"type"`
+						ButtonReply waReplyRef `json:"button_reply"`
+						ListReply   waReplyRef `json:"list_reply"`
+					} `json:"interactive"`
+				} `json:"messages"`
+				Statuses []struct {
+					ID          string `json:"id"`
+					Status      string `json:"status"`
+					Timestamp   string `json:"timestamp"`
+					RecipientID string `json:"recipient_id"`
+					Errors      []struct {
+						Code  int    `json:"code"`
+						Title string `json:"title"`
+					} `json:"errors"`
+				} `json:"statuses"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+type waReplyRef struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// ParseWhatsAppWebhook extracts the messages and status updates from a
+// webhook delivery. Unsupported message types are kept with an empty Text so
+// the caller can decide what to answer.
+func ParseWhatsAppWebhook(body []byte) (*domain.WhatsAppWebhookEvent, error) {
+	var payload waWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if payload.Object != "whatsapp_business_account" {
+		return nil, fmt.Errorf("unexpected webhook object %q", payload.Object)
+	}
+
+	event := &domain.WhatsAppWebhookEvent{}
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			if change.Field != "messages" {
+				continue
+			}
+
+			names := make(map[string]string)
+			for _, c := range change.Value.Contacts {
+				names[c.WaID] = c.Profile.Name
+			}
+
+			for _, m := range change.Value.Messages {
+				msg := &domain.WhatsAppInboundMessage{
+					ID:          m.ID,
+					From:        m.From,
+					ProfileName: names[m.From],
+					Type:        m.Type,
+					Timestamp:   unixTimestamp(m.Timestamp),
+				}
+				switch m.Type {
+				case "text":
+					msg.Text = m.Text.Body
+				case "button":
+					msg.Text, msg.ReplyID = m.Button.Text, m.Button.Payload
+				case "interactive":
+					reply := m.Interactive.ButtonReply
+					if m.Interactive.Type == "list_reply" {
+						reply = m.Interactive.ListReply
+					}
+					msg.Text, msg.ReplyID = reply.Title, reply.ID
+				}
+				event.Messages = append(event.Messages, msg)
+			}
+
+			for _, s := range change.Value.Statuses {
+				status := &domain.WhatsAppStatusUpdate{
+					MessageID:   s.ID,
+					RecipientID: s.RecipientID,
+					Status:      s.Status,
+					Timestamp:   unixTimestamp(s.Timestamp),
+				}
+				if len(s.Errors) > 0 {
+					status.ErrorCode = s.Errors[0].Code
+					status.ErrorTitle = s.Errors[0].Title
+				}
+				event.Statuses = append(event.Statuses, status)
+			}
+		}
+	}
+
+	return event, nil
+}
+
+func unixTimestamp(raw string) time.Time {
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.Unix(seconds, 0)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+const webhookSample = `{
+  "object": "whatsapp_business_account",
+  "entry": [{
+    "id": "1",
+    "changes": [{
+      "field": "messages",
+      "value": {
+        "contacts": [{"wa_id": "251911000000", "profile": {"name": "Sr. Hana"}}],
+        "messages": [
+          {"id": "wamid.1", "from": "251911000000", "timestamp": "1760000000", "type": "text", "text": {"body": "assess Abebe"}},
+          {"id": "wamid.2", "from": "251911000000", "timestamp": "1760000005", "type": "interactive",
+           "interactive": {"type": "list_reply", "list_reply": {"id": "tree:child_fever", "title": "Fever"}}},
+          {"id": "wamid.3", "from": "251911000000", "timestamp": "1760000009", "type": "image"}
+        ],
+        "statuses": [
+          {"id": "wamid.out", "status": "failed", "timestamp": "1760000010", "recipient_id": "251922000000",
+           "errors": [{"code": 131026, "title": "Message undeliverable"}]}
+        ]
+      }
+    }]
+  }]
+}`
+
+func TestVerifyWhatsAppSignature(t *testing.T) {
+	body := []byte(webhookSample)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	header := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !VerifyWhatsAppSignature("secret", body, header) {
+		t.Fatal("valid signature rejected")
+	}
+	if VerifyWhatsAppSignature("other", body, header) {
+		t.Fatal("signature with the wrong secret accepted")
+	}
+	if VerifyWhatsAppSignature("secret", append(body, ' '), header) {
+		t.Fatal("signature over a modified body accepted")
+	}
+	if VerifyWhatsAppSignature("secret", body, "") || VerifyWhatsAppSignature("", body, header) {
+		t.Fatal("missing header or secret accepted")
+	}
+}
+
+func TestParseWhatsAppWebhook(t *testing.T) {
+	event, err := ParseWhatsAppWebhook([]byte(webhookSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(event.Messages) != 3 || len(event.Statuses) != 1 {
+		t.Fatalf("got %d messages and %d statuses", len(event.Messages), len(event.Statuses))
+	}
+
+	text := event.Messages[0]
+	if text.Text != "assess Abebe" || text.ProfileName != "Sr. Hana" || text.Timestamp.Unix() != 1760000000 {
+		t.Fatalf("unexpected text message %+v", text)
+	}
+	if reply := event.Messages[1]; reply.ReplyID != "tree:child_fever" || reply.Text != "Fever" {
+		t.Fatalf("unexpected list reply %+v", reply)
+	}
+	if image := event.Messages[2]; image.Type != "image" || image.Text != "" {
+		t.Fatalf("unexpected image message %+v", image)
+	}
+
+	status := event.Statuses[0]
+	if status.Status != "failed" || status.ErrorCode != 131026 || status.RecipientID != "251922000000" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	if _, err := ParseWhatsAppWebhook([]byte(`{"object":"page"}`)); err == nil {
+		t.Fatal("non-WhatsApp payload accepted")
+	}
+}
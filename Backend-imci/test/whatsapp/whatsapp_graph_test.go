@@ -0,0 +1,154 @@
+package whatsapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGraphAPI records the messages posted to it and answers like the
+// Cloud API, or with fail when it is set.
+type fakeGraphAPI struct {
+	mu       sync.Mutex
+	requests []map[string]interface{}
+	fail     string
+}
+
+func (f *fakeGraphAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/PHONE_ID/messages" || r.Header.Get("Authorization") != "Bearer TOKEN" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, payload)
+	fail := f.fail
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if fail != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fail))
+		return
+	}
+	w.Write([]byte(`{"messaging_product":"whatsapp","messages":[{"id":"wamid.TEST"}]}`))
+}
+
+func (f *fakeGraphAPI) last(t *testing.T) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.NotEmpty(t, f.requests)
+	return f.requests[len(f.requests)-1]
+}
+
+func newGraphClient(t *testing.T) (domain.WhatsAppService, *fakeGraphAPI) {
+	fake := &fakeGraphAPI{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return service.NewMetaWhatsAppService("TOKEN", "PHONE_ID", server.URL, nil), fake
+}
+
+func templateOf(payload map[string]interface{}) map[string]interface{} {
+	return payload["template"].(map[string]interface{})
+}
+
+func TestGraphAPISendOTP(t *testing.T) {
+	client, fake := newGraphClient(t)
+
+	require.NoError(t, client.SendOTP(context.Background(), "0911000000", "123456", "am"))
+
+	payload := fake.last(t)
+	assert.Equal(t, "251911000000", payload["to"])
+	template := templateOf(payload)
+	assert.Equal(t, "digital_imci_otp", template["name"])
+	assert.Equal(t, "am", template["language"].(map[string]interface{})["code"])
+
+	components := template["components"].([]interface{})
+	require.Len(t, components, 2)
+	assert.Equal(t, "button", components[1].(map[string]interface{})["type"])
+}
+
+func TestGraphAPITemplateLanguages(t *testing.T) {
+	client, fake := newGraphClient(t)
+	ctx := context.Background()
+
+	languages := map[string]string{"am-ET": "am", "om": "om", "fr": "en"}
+	for requested, expected := range languages {
+		err := client.SendTemplate(ctx, "251911000000", &domain.WhatsAppTemplateMessage{
+			Kind:     domain.TemplateAlert,
+			Language: requested,
+			Params:   []string{"Cholera suspected in Adama"},
+		})
+		require.NoError(t, err)
+
+		language := templateOf(fake.last(t))["language"].(map[string]interface{})
+		assert.Equal(t, expected, language["code"], "requested %s", requested)
+	}
+
+	err := client.SendTemplate(ctx, "251911000000", &domain.WhatsAppTemplateMessage{
+		Kind:     domain.TemplateAlert,
+		Language: "en",
+		Params:   []string{"Cholera suspected", "Adama"},
+	})
+	assert.ErrorContains(t, err, "expects 1 parameters")
+
+	err = client.SendTemplate(ctx, "251911000000", &domain.WhatsAppTemplateMessage{Kind: "unknown", Language: "en"})
+	assert.ErrorIs(t, err, domain.ErrUnknownWhatsAppTemplate)
+}
+
+func TestWhatsAppChannelUsesPreferredLanguage(t *testing.T) {
+	client, fake := newGraphClient(t)
+	channel := service.NewWhatsAppChannel(client)
+	ctx := context.Background()
+
+	require.NoError(t, channel.Send(ctx, &domain.OTPNotification{
+		Phone: "0911000000", Code: "123456", Language: "om", Purpose: domain.PurposeSignupOTP,
+	}))
+	template := templateOf(fake.last(t))
+	assert.Equal(t, "digital_imci_otp", template["name"])
+	assert.Equal(t, "om", template["language"].(map[string]interface{})["code"])
+
+	require.NoError(t, channel.Send(ctx, &domain.OTPNotification{
+		Phone: "0911000000", Text: "Cholera suspected in Adama", Language: "ti", Purpose: domain.PurposeOutbreakAlert,
+	}))
+	template = templateOf(fake.last(t))
+	assert.Equal(t, "digital_imci_alert", template["name"])
+	assert.Equal(t, "ti", template["language"].(map[string]interface{})["code"])
+}
+
+func TestGraphAPIErrors(t *testing.T) {
+	client, fake := newGraphClient(t)
+	fake.fail = `{"error":{"message":"Template name does not exist in the translation","code":132001}}`
+
+	err := client.SendOTP(context.Background(), "251911000000", "123456", "")
+	assert.ErrorContains(t, err, "[132001]")
+}
+
+func TestGraphAPIChatReply(t *testing.T) {
+	client, fake := newGraphClient(t)
+
+	err := client.SendChatReply(context.Background(), "251911000000", &domain.ChatReply{
+		Text:    "Does the child have fever?",
+		Buttons: []domain.ChatButton{{ID: "ans:yes", Title: "Yes"}, {ID: "ans:no", Title: "No"}},
+	})
+	require.NoError(t, err)
+
+	payload := fake.last(t)
+	assert.Equal(t, "interactive", payload["type"])
+	interactive := payload["interactive"].(map[string]interface{})
+	assert.Equal(t, "button", interactive["type"])
+}
@@ -30,7 +30,7 @@ func NewTestableWhatsAppService(accessToken, phoneNumberID, baseURL string) *Tes
 // MockWhatsAppService for simple testing
 type MockWhatsAppService struct{}
 
-func (m *MockWhatsAppService) SendOTP(ctx context.Context, phoneNumber, code string) error {
+func (m *MockWhatsAppService) SendOTP(ctx context.Context, phoneNumber, code, language string) error {
 	// Simple validation
 	if phoneNumber == "" {
 		return fmt.Errorf("phone number is required")
@@ -56,6 +56,20 @@ func (m *MockWhatsAppService) SendPasswordResetOTP(ctx context.Context, phoneNum
 	return nil
 }
 
+func (m *MockWhatsAppService) SendTemplate(ctx context.Context, phoneNumber string, msg *domain.WhatsAppTemplateMessage) error {
+	if phoneNumber == "" {
+		return fmt.Errorf("phone number is required")
+	}
+	return nil
+}
+
+func (m *MockWhatsAppService) SendChatReply(ctx context.Context, recipient string, reply *domain.ChatReply) error {
+	if recipient == "" {
+		return fmt.Errorf("recipient is required")
+	}
+	return nil
+}
+
 func (m *MockWhatsAppService) GetStartLink() string {
 	return "WhatsApp verification ready. Use your phone number directly."
 }
@@ -97,15 +111,15 @@ func TestWhatsAppService_SimpleSendOTP(t *testing.T) {
 	service := &MockWhatsAppService{}
 
 	// Test successful OTP sending
-	err := service.SendOTP(ctx, "+251911223344", "123456")
+	err := service.SendOTP(ctx, "+251911223344", "123456", "")
 	assert.NoError(t, err)
 
 	// Test invalid phone number
-	err = service.SendOTP(ctx, "", "123456")
+	err = service.SendOTP(ctx, "", "123456", "")
 	assert.Error(t, err)
 
 	// Test invalid OTP code
-	err = service.SendOTP(ctx, "+251911223344", "123")
+	err = service.SendOTP(ctx, "+251911223344", "123", "")
 	assert.Error(t, err)
 }
 
@@ -127,4 +141,4 @@ func TestWhatsAppService_SimpleSendPasswordResetOTP(t *testing.T) {
 	// Test invalid OTP code
 	err = service.SendPasswordResetOTP(ctx, "+251911223344", "654")
 	assert.Error(t, err)
-}
\ No newline at end of file
+}
\ No newline at end of file
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type AssessmentLifecycleUsecase struct {
+	lifecycleRepo  domain.AssessmentLifecycleRepository
+	prescriptions  domain.PrescriptionFinalizer
+	contextTimeout time.Duration
+}
+
+// NewAssessmentLifecycleUsecase finalises the visit's prescription through
+// prescriptions on completion; it may be nil.
+func NewAssessmentLifecycleUsecase(lifecycleRepo domain.AssessmentLifecycleRepository, prescriptions domain.PrescriptionFinalizer, timeout time.Duration) domain.AssessmentLifecycleUsecase {
+	return &AssessmentLifecycleUsecase{
+		lifecycleRepo:  lifecycleRepo,
+		prescriptions:  prescriptions,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *AssessmentLifecycleUsecase) FlowStarted(ctx context.Context, assessment *domain.Assessment) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	switch assessment.Status {
+	case domain.StatusInProgress, domain.StatusClassified:
+		return nil
+	}
+	return uc.transition(ctx, assessment, domain.StatusInProgress, domain.ActorSystem, nil, "", nil)
+}
+
+func (uc *AssessmentLifecycleUsecase) FlowFinished(ctx context.Context, assessment *domain.Assessment, treeID string, classified bool) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if err := uc.lifecycleRepo.MarkTreeCompleted(ctx, assessment.ID, treeID, time.Now()); err != nil {
+		return err
+	}
+	if !classified || assessment.Status != domain.StatusInProgress {
+		return nil
+	}
+	return uc.transition(ctx, assessment, domain.StatusClassified, domain.ActorSystem, nil, "", nil)
+}
+
+func (uc *AssessmentLifecycleUsecase) Complete(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string) (*domain.AssessmentLifecycle, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, actor, err := uc.load(ctx, assessmentID, medicalProfessionalID, role)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.CheckTransition(assessment.Status, domain.StatusCompleted, actor); err != nil {
+		return nil, err
+	}
+
+	trees, err := uc.lifecycleRepo.CompletedTrees(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if missing := missingTrees(assessment.AssessmentType, trees); len(missing) > 0 {
+		return nil, &domain.IncompleteTreesError{Missing: missing}
+	}
+
+	// The prescription is stored before the transition so a failure leaves
+	// the assessment open.
+	if uc.prescriptions != nil {
+		if _, err := uc.prescriptions.Finalize(ctx, assessment, medicalProfessionalID); err != nil {
+			return nil, err
+		}
+	}
+
+	endTime := time.Now()
+	if err := uc.transition(ctx, assessment, domain.StatusCompleted, actor, &medicalProfessionalID, "", &endTime); err != nil {
+		return nil, err
+	}
+	return uc.lifecycle(ctx, assessment, actor)
+}
+
+func (uc *AssessmentLifecycleUsecase) Cancel(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string, reason string) (*domain.AssessmentLifecycle, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domain.ErrCancellationReasonRequired
+	}
+
+	assessment, actor, err := uc.load(ctx, assessmentID, medicalProfessionalID, role)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.transition(ctx, assessment, domain.StatusCancelled, actor, &medicalProfessionalID, reason, nil); err != nil {
+		return nil, err
+	}
+	return uc.lifecycle(ctx, assessment, actor)
+}
+
+func (uc *AssessmentLifecycleUsecase) GetLifecycle(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string) (*domain.AssessmentLifecycle, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, actor, err := uc.load(ctx, assessmentID, medicalProfessionalID, role)
+	if err != nil {
+		return nil, err
+	}
+	return uc.lifecycle(ctx, assessment, actor)
+}
+
+// load returns the assessment with the actor the caller acts as: clinician
+// on their own assessments, admin on anyone else's. Other clinicians get
+// ErrAssessmentNotFound, as elsewhere.
+func (uc *AssessmentLifecycleUsecase) load(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string) (*domain.Assessment, domain.TransitionActor, error) {
+	assessment, err := uc.lifecycleRepo.GetAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case assessment.MedicalProfessionalID == medicalProfessionalID:
+		return assessment, domain.ActorClinician, nil
+	case role == string(domain.AdminRole):
+		return assessment, domain.ActorAdmin, nil
+	}
+	return nil, "", domain.ErrAssessmentNotFound
+}
+
+func (uc *AssessmentLifecycleUsecase) transition(ctx context.Context, assessment *domain.Assessment, to domain.AssessmentStatus, actor domain.TransitionActor, changedBy *uuid.UUID, reason string, endTime *time.Time) error {
+	if err := domain.CheckTransition(assessment.Status, to, actor); err != nil {
+		return err
+	}
+
+	change := &domain.AssessmentStatusChange{
+		ID:           uuid.New(),
+		AssessmentID: assessment.ID,
+		FromStatus:   assessment.Status,
+		ToStatus:     to,
+		Actor:        actor,
+		ChangedBy:    changedBy,
+		Reason:       reason,
+		CreatedAt:    time.Now(),
+	}
+	if err := uc.lifecycleRepo.Transition(ctx, change, endTime); err != nil {
+		return err
+	}
+
+	assessment.Status = to
+	if endTime != nil {
+		assessment.EndTime = endTime
+	}
+	return nil
+}
+
+func (uc *AssessmentLifecycleUsecase) lifecycle(ctx context.Context, assessment *domain.Assessment, actor domain.TransitionActor) (*domain.AssessmentLifecycle, error) {
+	trees, err := uc.lifecycleRepo.CompletedTrees(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := uc.lifecycleRepo.ListHistory(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AssessmentLifecycle{
+		AssessmentID:       assessment.ID,
+		Status:             assessment.Status,
+		EndTime:            assessment.EndTime,
+		AllowedTransitions: domain.AllowedTransitions(assessment.Status, actor),
+		CompletedTrees:     trees,
+		MissingTrees:       missingTrees(assessment.AssessmentType, trees),
+		History:            history,
+	}, nil
+}
+
+func missingTrees(assessmentType domain.AssessmentType, completed []string) []string {
+	done := make(map[string]bool, len(completed))
+	for _, treeID := range completed {
+		done[treeID] = true
+	}
+
+	missing := []string{}
+	for _, treeID := range domain.MandatoryTrees[assessmentType] {
+		if !done[treeID] {
+			missing = append(missing, treeID)
+		}
+	}
+	return missing
+}
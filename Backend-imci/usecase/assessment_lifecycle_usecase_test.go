@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeLifecycleRepo struct {
+	assessment *domain.Assessment
+	history    []*domain.AssessmentStatusChange
+	trees      []string
+}
+
+func (f *fakeLifecycleRepo) GetAssessment(ctx context.Context, id uuid.UUID) (*domain.Assessment, error) {
+	if f.assessment == nil || f.assessment.ID != id {
+		return nil, domain.ErrAssessmentNotFound
+	}
+	copied := *f.assessment
+	return &copied, nil
+}
+
+func (f *fakeLifecycleRepo) Transition(ctx context.Context, change *domain.AssessmentStatusChange, endTime *time.Time) error {
+	if f.assessment.Status != change.FromStatus {
+		return domain.ErrAssessmentStatusChanged
+	}
+	f.assessment.Status = change.ToStatus
+	if endTime != nil {
+		f.assessment.EndTime = endTime
+	}
+	f.history = append(f.history, change)
+	return nil
+}
+
+func (f *fakeLifecycleRepo) ListHistory(ctx context.Context, assessmentID uuid.UUID) ([]*domain.AssessmentStatusChange, error) {
+	return f.history, nil
+}
+
+func (f *fakeLifecycleRepo) MarkTreeCompleted(ctx context.Context, assessmentID uuid.UUID, treeID string, at time.Time) error {
+	for _, done := range f.trees {
+		if done == treeID {
+			return nil
+		}
+	}
+	f.trees = append(f.trees, treeID)
+	return nil
+}
+
+func (f *fakeLifecycleRepo) CompletedTrees(ctx context.Context, assessmentID uuid.UUID) ([]string, error) {
+	return f.trees, nil
+}
+
+func newLifecycleFixture(status domain.AssessmentStatus) (*fakeLifecycleRepo, domain.AssessmentLifecycleUsecase) {
+	repo := &fakeLifecycleRepo{assessment: &domain.Assessment{
+		ID:                    uuid.New(),
+		MedicalProfessionalID: uuid.New(),
+		AssessmentType:        domain.TypeYoungInfant,
+		Status:                status,
+	}}
+	return repo, NewAssessmentLifecycleUsecase(repo, nil, time.Second)
+}
+
+func TestLifecycleFlowMovesDraftThroughClassified(t *testing.T) {
+	repo, uc := newLifecycleFixture(domain.StatusDraft)
+	ctx := context.Background()
+
+	assessment, _ := repo.GetAssessment(ctx, repo.assessment.ID)
+	if err := uc.FlowStarted(ctx, assessment); err != nil {
+		t.Fatalf("FlowStarted: %v", err)
+	}
+	if err := uc.FlowStarted(ctx, assessment); err != nil {
+		t.Fatalf("second FlowStarted: %v", err)
+	}
+	if err := uc.FlowFinished(ctx, assessment, "jaundice_check", false); err != nil {
+		t.Fatalf("FlowFinished without classification: %v", err)
+	}
+	if assessment.Status != domain.StatusInProgress {
+		t.Fatalf("status = %s, want in_progress", assessment.Status)
+	}
+	if err := uc.FlowFinished(ctx, assessment, "very_severe_disease_check", true); err != nil {
+		t.Fatalf("FlowFinished: %v", err)
+	}
+	if assessment.Status != domain.StatusClassified {
+		t.Fatalf("status = %s, want classified", assessment.Status)
+	}
+
+	if len(repo.history) != 2 {
+		t.Fatalf("history has %d entries, want 2", len(repo.history))
+	}
+	for _, change := range repo.history {
+		if change.Actor != domain.ActorSystem {
+			t.Errorf("transition %s -> %s by %s, want system", change.FromStatus, change.ToStatus, change.Actor)
+		}
+	}
+}
+
+func TestLifecycleFlowStartedRefusesClosedAssessment(t *testing.T) {
+	repo, uc := newLifecycleFixture(domain.StatusCancelled)
+
+	err := uc.FlowStarted(context.Background(), repo.assessment)
+	var transitionErr *domain.TransitionError
+	if !errors.As(err, &transitionErr) || !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Fatalf("err = %v, want illegal transition", err)
+	}
+	if len(repo.history) != 0 {
+		t.Errorf("history recorded for a refused transition")
+	}
+}
+
+func TestLifecycleCompleteRequiresMandatoryTrees(t *testing.T) {
+	repo, uc := newLifecycleFixture(domain.StatusClassified)
+	repo.trees = []string{"very_severe_disease_check", "diarrhea_check"}
+	owner := repo.assessment.MedicalProfessionalID
+
+	_, err := uc.Complete(context.Background(), repo.assessment.ID, owner, string(domain.NurseRole))
+	var incomplete *domain.IncompleteTreesError
+	if !errors.As(err, &incomplete) || !errors.Is(err, domain.ErrMandatoryTreesIncomplete) {
+		t.Fatalf("err = %v, want incomplete trees", err)
+	}
+	want := []string{"jaundice_check", "feeding_problem_underweight_check"}
+	if !reflect.DeepEqual(incomplete.Missing, want) {
+		t.Errorf("missing = %v, want %v", incomplete.Missing, want)
+	}
+	if repo.assessment.Status != domain.StatusClassified {
+		t.Errorf("status changed to %s", repo.assessment.Status)
+	}
+}
+
+func TestLifecycleCompleteSetsEndTime(t *testing.T) {
+	repo, uc := newLifecycleFixture(domain.StatusClassified)
+	repo.trees = append([]string{}, domain.MandatoryTrees[domain.TypeYoungInfant]...)
+	owner := repo.assessment.MedicalProfessionalID
+
+	lifecycle, err := uc.Complete(context.Background(), repo.assessment.ID, owner, string(domain.NurseRole))
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if lifecycle.Status != domain.StatusCompleted || lifecycle.EndTime == nil {
+		t.Fatalf("lifecycle = %+v, want completed with end time", lifecycle)
+	}
+	if len(lifecycle.AllowedTransitions) != 0 {
+		t.Errorf("completed assessment allows %v", lifecycle.AllowedTransitions)
+	}
+	last := repo.history[len(repo.history)-1]
+	if last.Actor != domain.ActorClinician || last.ChangedBy == nil || *last.ChangedBy != owner {
+		t.Errorf("history entry = %+v, want clinician %s", last, owner)
+	}
+
+	_, err = uc.Cancel(context.Background(), repo.assessment.ID, owner, string(domain.NurseRole), "duplicate")
+	if !errors.Is(err, domain.ErrIllegalTransition) {
+		t.Errorf("cancel after completion err = %v, want illegal transition", err)
+	}
+}
+
+func TestLifecycleCancel(t *testing.T) {
+	repo, uc := newLifecycleFixture(domain.StatusInProgress)
+	ctx := context.Background()
+	id := repo.assessment.ID
+
+	if _, err := uc.Cancel(ctx, id, repo.assessment.MedicalProfessionalID, string(domain.NurseRole), "  "); !errors.Is(err, domain.ErrCancellationReasonRequired) {
+		t.Fatalf("blank reason err = %v", err)
+	}
+	if _, err := uc.Cancel(ctx, id, uuid.New(), string(domain.NurseRole), "wrong patient"); !errors.Is(err, domain.ErrAssessmentNotFound) {
+		t.Fatalf("other clinician err = %v, want not found", err)
+	}
+
+	adminID := uuid.New()
+	lifecycle, err := uc.Cancel(ctx, id, adminID, string(domain.AdminRole), "wrong patient")
+	if err != nil {
+		t.Fatalf("admin Cancel: %v", err)
+	}
+	if lifecycle.Status != domain.StatusCancelled {
+		t.Fatalf("status = %s, want cancelled", lifecycle.Status)
+	}
+	last := repo.history[len(repo.history)-1]
+	if last.Actor != domain.ActorAdmin || last.Reason != "wrong patient" {
+		t.Errorf("history entry = %+v", last)
+	}
+}
+
+func TestLifecycleAdminCannotComplete(t *testing.T) {
+	repo, uc := newLifecycleFixture(domain.StatusClassified)
+	repo.trees = append([]string{}, domain.MandatoryTrees[domain.TypeYoungInfant]...)
+
+	_, err := uc.Complete(context.Background(), repo.assessment.ID, uuid.New(), string(domain.AdminRole))
+	if !errors.Is(err, domain.ErrTransitionNotPermitted) {
+		t.Fatalf("err = %v, want not permitted", err)
+	}
+}
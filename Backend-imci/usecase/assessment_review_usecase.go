@@ -0,0 +1,691 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/counseling"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/google/uuid"
+)
+
+type AssessmentReviewUsecase struct {
+	reviewRepo         domain.AssessmentReviewRepository
+	lifecycleRepo      domain.AssessmentLifecycleRepository
+	userRepo           domain.MedicalProfessionalRepository
+	answerRepo         domain.MedicalProfessionalAnswerRepository
+	classificationRepo domain.ClassificationRepository
+	treatmentPlanRepo  domain.TreatmentPlanRepository
+	counselingRepo     domain.CounselingRepository
+	prescriptions      domain.PrescriptionFinalizer
+	criteria           map[domain.ReviewReason]bool
+	contextTimeout     time.Duration
+}
+
+// NewAssessmentReviewUsecase queues assessments for the given criteria, or
+// for every criterion in domain.ReviewReasons when none are given. Amended
+// classifications are consolidated again through prescriptions; it may be
+// nil.
+func NewAssessmentReviewUsecase(
+	reviewRepo domain.AssessmentReviewRepository,
+	lifecycleRepo domain.AssessmentLifecycleRepository,
+	userRepo domain.MedicalProfessionalRepository,
+	answerRepo domain.MedicalProfessionalAnswerRepository,
+	classificationRepo domain.ClassificationRepository,
+	treatmentPlanRepo domain.TreatmentPlanRepository,
+	counselingRepo domain.CounselingRepository,
+	prescriptions domain.PrescriptionFinalizer,
+	criteria []domain.ReviewReason,
+	timeout time.Duration,
+) domain.AssessmentReviewUsecase {
+	if len(criteria) == 0 {
+		criteria = domain.ReviewReasons
+	}
+	enabled := make(map[domain.ReviewReason]bool, len(criteria))
+	for _, reason := range criteria {
+		enabled[reason] = true
+	}
+
+	return &AssessmentReviewUsecase{
+		reviewRepo:         reviewRepo,
+		lifecycleRepo:      lifecycleRepo,
+		userRepo:           userRepo,
+		answerRepo:         answerRepo,
+		classificationRepo: classificationRepo,
+		treatmentPlanRepo:  treatmentPlanRepo,
+		counselingRepo:     counselingRepo,
+		prescriptions:      prescriptions,
+		criteria:           enabled,
+		contextTimeout:     timeout,
+	}
+}
+
+func (uc *AssessmentReviewUsecase) NotifyClassification(ctx context.Context, assessment *domain.Assessment, _ *domain.Classification) {
+	if assessment == nil {
+		return
+	}
+	if _, err := uc.Evaluate(ctx, assessment); err != nil {
+		logger.Error("failed to evaluate review criteria", "assessment_id", assessment.ID, "error", err)
+	}
+}
+
+func (uc *AssessmentReviewUsecase) Evaluate(ctx context.Context, assessment *domain.Assessment) (*domain.AssessmentReview, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.evaluate(ctx, assessment, false)
+}
+
+// evaluate queues or re-opens the review of assessment. Offline results are
+// only compared both ways once the device reports them after syncing;
+// before that a classification the server reached and the device did not
+// is enough to call the results discordant.
+func (uc *AssessmentReviewUsecase) evaluate(ctx context.Context, assessment *domain.Assessment, offlineReported bool) (*domain.AssessmentReview, error) {
+	classifications, err := uc.classificationRepo.ListByAssessmentID(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := []domain.ReviewReason{}
+	if uc.criteria[domain.ReviewReasonEmergency] {
+		for _, c := range classifications {
+			if c.RequiresUrgentReferral {
+				reasons = append(reasons, domain.ReviewReasonEmergency)
+				break
+			}
+		}
+	}
+	if uc.criteria[domain.ReviewReasonDiscordantOffline] && assessment.IsOffline {
+		offline, err := uc.reviewRepo.GetOfflineResult(ctx, assessment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if offline != nil && discordant(offline, classifications, offlineReported) {
+			reasons = append(reasons, domain.ReviewReasonDiscordantOffline)
+		}
+	}
+	if uc.criteria[domain.ReviewReasonTrainee] {
+		clinician, err := uc.userRepo.GetByID(ctx, assessment.MedicalProfessionalID)
+		if err != nil {
+			return nil, err
+		}
+		if clinician.IsTrainee {
+			reasons = append(reasons, domain.ReviewReasonTrainee)
+		}
+	}
+
+	review, err := uc.reviewRepo.GetByAssessmentID(ctx, assessment.ID)
+	if err != nil && !errors.Is(err, domain.ErrReviewNotFound) {
+		return nil, err
+	}
+	if review == nil {
+		if len(reasons) == 0 {
+			return nil, nil
+		}
+		now := time.Now()
+		review = &domain.AssessmentReview{
+			AssessmentID:          assessment.ID,
+			PatientID:             assessment.PatientID,
+			MedicalProfessionalID: assessment.MedicalProfessionalID,
+			Status:                domain.ReviewPending,
+			CreatedAt:             now,
+			UpdatedAt:             now,
+		}
+	}
+
+	added := false
+	for _, reason := range reasons {
+		if !hasReviewReason(review.Reasons, reason) {
+			review.Reasons = append(review.Reasons, reason)
+			added = true
+		}
+	}
+	if !added && review.ID != uuid.Nil {
+		return review, nil
+	}
+
+	// A signed-off review goes back to the queue when a new reason appears,
+	// such as an emergency classification from a later tree.
+	if review.Status != domain.ReviewPending {
+		logger.Info("assessment review re-opened", "assessment_id", assessment.ID, "reasons", review.Reasons)
+		review.Status = domain.ReviewPending
+	}
+	review.UpdatedAt = time.Now()
+	if err := uc.reviewRepo.Save(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (uc *AssessmentReviewUsecase) ReportOfflineResult(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, classifications []string) (*domain.ReviewDetail, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, err := uc.lifecycleRepo.GetAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assessment.MedicalProfessionalID != medicalProfessionalID {
+		return nil, domain.ErrAssessmentNotFound
+	}
+
+	cleaned := []string{}
+	for _, name := range classifications {
+		if name = strings.TrimSpace(name); name != "" {
+			cleaned = append(cleaned, name)
+		}
+	}
+	if err := uc.reviewRepo.SaveOfflineResult(ctx, assessment.ID, cleaned, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// The result was reported, so the assessment is one taken offline
+	// whatever the create request said.
+	assessment.IsOffline = true
+	if _, err := uc.evaluate(ctx, assessment, true); err != nil {
+		return nil, err
+	}
+	return uc.detail(ctx, assessment)
+}
+
+func (uc *AssessmentReviewUsecase) GetReview(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID, role string) (*domain.ReviewDetail, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, err := uc.lifecycleRepo.GetAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assessment.MedicalProfessionalID != medicalProfessionalID {
+		if err := uc.checkReviewer(ctx, assessment, medicalProfessionalID, role); err != nil {
+			return nil, err
+		}
+	}
+	return uc.detail(ctx, assessment)
+}
+
+func (uc *AssessmentReviewUsecase) ListQueue(ctx context.Context, medicalProfessionalID uuid.UUID, role string, status domain.ReviewStatus, limit int) ([]*domain.AssessmentReview, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if status == "" {
+		status = domain.ReviewPending
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	facility := ""
+	if role != string(domain.AdminRole) {
+		reviewer, err := uc.userRepo.GetByID(ctx, medicalProfessionalID)
+		if err != nil {
+			return nil, err
+		}
+		if reviewer.FacilityName == "" {
+			return nil, domain.ErrNoFacility
+		}
+		facility = reviewer.FacilityName
+	}
+
+	return uc.reviewRepo.ListQueue(ctx, status, facility, limit)
+}
+
+func (uc *AssessmentReviewUsecase) Approve(ctx context.Context, assessmentID, reviewerID uuid.UUID, role string, comment string) (*domain.ReviewDetail, error) {
+	return uc.act(ctx, assessmentID, reviewerID, role, &domain.ReviewAction{
+		Action:  domain.ReviewActionApprove,
+		Comment: strings.TrimSpace(comment),
+	}, domain.ReviewApproved, nil)
+}
+
+func (uc *AssessmentReviewUsecase) Amend(ctx context.Context, assessmentID, reviewerID uuid.UUID, role string, req *domain.AmendReviewRequest) (*domain.ReviewDetail, error) {
+	if strings.TrimSpace(req.Comment) == "" {
+		return nil, domain.ErrReviewCommentRequired
+	}
+	return uc.act(ctx, assessmentID, reviewerID, role, &domain.ReviewAction{
+		Action:  domain.ReviewActionAmend,
+		Comment: strings.TrimSpace(req.Comment),
+	}, domain.ReviewAmended, req)
+}
+
+func (uc *AssessmentReviewUsecase) Comment(ctx context.Context, assessmentID, reviewerID uuid.UUID, role string, comment string) (*domain.ReviewDetail, error) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, domain.ErrReviewCommentRequired
+	}
+	return uc.act(ctx, assessmentID, reviewerID, role, &domain.ReviewAction{
+		Action:  domain.ReviewActionComment,
+		Comment: comment,
+	}, "", nil)
+}
+
+func (uc *AssessmentReviewUsecase) SetTrainee(ctx context.Context, medicalProfessionalID uuid.UUID, isTrainee bool) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.reviewRepo.SetTrainee(ctx, medicalProfessionalID, isTrainee)
+}
+
+// act records a supervisor's action on the review of an assessment. Status
+// signs the review off; amend carries the supervisor's changes, which are
+// applied to the record with the action.
+func (uc *AssessmentReviewUsecase) act(ctx context.Context, assessmentID, reviewerID uuid.UUID, role string, action *domain.ReviewAction, status domain.ReviewStatus, amend *domain.AmendReviewRequest) (*domain.ReviewDetail, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, err := uc.lifecycleRepo.GetAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assessment.MedicalProfessionalID == reviewerID {
+		return nil, domain.ErrSelfReview
+	}
+	if err := uc.checkReviewer(ctx, assessment, reviewerID, role); err != nil {
+		return nil, err
+	}
+
+	review, err := uc.reviewRepo.GetByAssessmentID(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" && review.Status != domain.ReviewPending {
+		return nil, domain.ErrReviewClosed
+	}
+
+	var amendment *domain.ReviewAmendment
+	if amend != nil {
+		answers, classifications, err := uc.record(ctx, assessment.ID)
+		if err != nil {
+			return nil, err
+		}
+		plans, err := uc.treatmentPlanRepo.GetByAssessmentID(ctx, assessment.ID)
+		if err != nil {
+			return nil, err
+		}
+		diff := reviewDiff(answers, classifications, plans, amend)
+		if len(diff.Answers) == 0 && len(diff.Classifications) == 0 {
+			return nil, domain.ErrEmptyAmendment
+		}
+		counselings, err := uc.counselingRepo.GetByAssessmentID(ctx, assessment.ID)
+		if err != nil {
+			return nil, err
+		}
+		action.Diff = diff
+		amendment = reviewAmendment(assessment, answers, classifications, counselings, amend, diff)
+		if amendment.Audit, err = amendmentAudit(ctx, classifications, amendment); err != nil {
+			return nil, err
+		}
+	}
+
+	action.ID = uuid.New()
+	action.ReviewID = review.ID
+	action.ReviewerID = &reviewerID
+	action.CreatedAt = time.Now()
+	if amendment != nil {
+		err = uc.reviewRepo.Amend(ctx, action, status, amendment)
+	} else {
+		err = uc.reviewRepo.RecordAction(ctx, action, status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The amendment stands when the prescription fails to consolidate; it
+	// is consolidated again when the assessment is completed.
+	if amendment != nil && len(action.Diff.Classifications) > 0 && uc.prescriptions != nil {
+		if _, err := uc.prescriptions.Finalize(ctx, assessment, reviewerID); err != nil {
+			logger.Error("failed to consolidate amended prescription", "assessment_id", assessment.ID, "error", err)
+		}
+	}
+
+	return uc.detail(ctx, assessment)
+}
+
+// checkReviewer lets admins review any assessment and supervisors those of
+// their own facility. Anyone else gets ErrAssessmentNotFound.
+func (uc *AssessmentReviewUsecase) checkReviewer(ctx context.Context, assessment *domain.Assessment, reviewerID uuid.UUID, role string) error {
+	switch role {
+	case string(domain.AdminRole):
+		return nil
+	case string(domain.SupervisorRole):
+	default:
+		return domain.ErrAssessmentNotFound
+	}
+
+	reviewer, err := uc.userRepo.GetByID(ctx, reviewerID)
+	if err != nil {
+		return err
+	}
+	clinician, err := uc.userRepo.GetByID(ctx, assessment.MedicalProfessionalID)
+	if err != nil {
+		return err
+	}
+	if reviewer.FacilityName == "" || reviewer.FacilityName != clinician.FacilityName {
+		return domain.ErrAssessmentNotFound
+	}
+	return nil
+}
+
+func (uc *AssessmentReviewUsecase) record(ctx context.Context, assessmentID uuid.UUID) (domain.JSONB, []*domain.Classification, error) {
+	answers := domain.JSONB{}
+	answer, err := uc.answerRepo.GetByAssessmentID(ctx, assessmentID)
+	switch {
+	case err == nil && answer.Answers != nil:
+		answers = answer.Answers
+	case err != nil && !errors.Is(err, domain.ErrMedicalProfessionalAnswerNotFound):
+		return nil, nil, err
+	}
+
+	classifications, err := uc.classificationRepo.ListByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return answers, classifications, nil
+}
+
+func (uc *AssessmentReviewUsecase) detail(ctx context.Context, assessment *domain.Assessment) (*domain.ReviewDetail, error) {
+	answers, classifications, err := uc.record(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &domain.ReviewDetail{
+		AssessmentID:    assessment.ID,
+		Status:          domain.ReviewNotRequired,
+		Answers:         answers,
+		Classifications: classifications,
+	}
+
+	review, err := uc.reviewRepo.GetByAssessmentID(ctx, assessment.ID)
+	if errors.Is(err, domain.ErrReviewNotFound) {
+		return detail, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if review.Actions, err = uc.reviewRepo.ListActions(ctx, review.ID); err != nil {
+		return nil, err
+	}
+
+	detail.Status = review.Status
+	detail.Review = review
+	return detail, nil
+}
+
+func hasReviewReason(reasons []domain.ReviewReason, reason domain.ReviewReason) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func classificationKey(disease string) string {
+	return strings.ToUpper(strings.TrimSpace(disease))
+}
+
+// discordant reports whether the server reached a classification the
+// offline device did not or, when both is set, the other way round.
+func discordant(offline []string, classifications []*domain.Classification, both bool) bool {
+	device := make(map[string]bool, len(offline))
+	for _, name := range offline {
+		device[classificationKey(name)] = true
+	}
+	server := make(map[string]bool, len(classifications))
+	for _, c := range classifications {
+		key := classificationKey(c.Disease)
+		server[key] = true
+		if !device[key] {
+			return true
+		}
+	}
+	if !both {
+		return false
+	}
+	for key := range device {
+		if !server[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewDiff compares an amendment with the clinician's answers,
+// classifications and treatment plans. Answers are overridden one by one;
+// classifications are replaced as a whole when the amendment lists any, and
+// a classification's treatments when the amendment lists them.
+func reviewDiff(answers domain.JSONB, classifications []*domain.Classification, plans []*domain.TreatmentPlan, amend *domain.AmendReviewRequest) *domain.ReviewDiff {
+	diff := &domain.ReviewDiff{
+		Answers:         []domain.AnswerChange{},
+		Classifications: []domain.ClassificationChange{},
+	}
+
+	nodeIDs := make([]string, 0, len(amend.Answers))
+	for nodeID := range amend.Answers {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		before, after := answers[nodeID], amend.Answers[nodeID]
+		if !reflect.DeepEqual(before, after) {
+			diff.Answers = append(diff.Answers, domain.AnswerChange{NodeID: nodeID, Before: before, After: after})
+		}
+	}
+
+	if amend.Classifications == nil {
+		return diff
+	}
+
+	treatments := make(map[uuid.UUID][]domain.ReviewedTreatment)
+	for _, p := range plans {
+		treatments[p.ClassificationID] = append(treatments[p.ClassificationID], domain.ReviewedTreatment{
+			DrugName:            p.DrugName,
+			Dosage:              p.Dosage,
+			Frequency:           p.Frequency,
+			Duration:            p.Duration,
+			AdministrationRoute: p.AdministrationRoute,
+			IsPreReferral:       p.IsPreReferral,
+			Instructions:        p.Instructions,
+		})
+	}
+	before := make(map[string]*domain.ReviewedClassification, len(classifications))
+	for _, c := range classifications {
+		before[classificationKey(c.Disease)] = &domain.ReviewedClassification{Disease: c.Disease, Color: c.Color, Details: c.Details, TreatmentPlans: treatments[c.ID]}
+	}
+	after := make(map[string]*domain.ReviewedClassification, len(amend.Classifications))
+	for i := range amend.Classifications {
+		after[classificationKey(amend.Classifications[i].Disease)] = &amend.Classifications[i]
+	}
+
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if before[key] == nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b, a := before[key], after[key]
+		switch {
+		case a == nil:
+			diff.Classifications = append(diff.Classifications, domain.ClassificationChange{Disease: b.Disease, Change: "removed", Before: b})
+		case b == nil:
+			diff.Classifications = append(diff.Classifications, domain.ClassificationChange{Disease: a.Disease, Change: "added", After: a})
+		case reclassified(b, a) || a.TreatmentPlans != nil && !sameTreatments(b.TreatmentPlans, a.TreatmentPlans):
+			diff.Classifications = append(diff.Classifications, domain.ClassificationChange{Disease: b.Disease, Change: "changed", Before: b, After: a})
+		}
+	}
+	return diff
+}
+
+func reclassified(before, after *domain.ReviewedClassification) bool {
+	return !strings.EqualFold(before.Color, after.Color) || before.Details != after.Details
+}
+
+func sameTreatments(a, b []domain.ReviewedTreatment) bool {
+	return len(a) == 0 && len(b) == 0 || reflect.DeepEqual(a, b)
+}
+
+// reviewRuleVersion marks the classifications a supervisor added.
+const reviewRuleVersion = "supervisor_review"
+
+// reviewAmendment turns diff into the writes that apply it to the record.
+// Amended classifications are prioritised by colour, pink ones needing
+// urgent referral, as the rule engines do. counselings are the visit's
+// current items, so general advice it keeps is not given again.
+func reviewAmendment(assessment *domain.Assessment, answers domain.JSONB, classifications []*domain.Classification, counselings []*domain.Counseling, amend *domain.AmendReviewRequest, diff *domain.ReviewDiff) *domain.ReviewAmendment {
+	amendment := &domain.ReviewAmendment{
+		AssessmentID: assessment.ID,
+		Plans:        map[uuid.UUID][]*domain.TreatmentPlan{},
+	}
+
+	if len(diff.Answers) > 0 {
+		amendment.Answers = make(domain.JSONB, len(answers)+len(amend.Answers))
+		for nodeID, value := range answers {
+			amendment.Answers[nodeID] = value
+		}
+		for nodeID, value := range amend.Answers {
+			amendment.Answers[nodeID] = value
+		}
+	}
+
+	existing := make(map[string]*domain.Classification, len(classifications))
+	for _, c := range classifications {
+		existing[classificationKey(c.Disease)] = c
+	}
+
+	for _, change := range diff.Classifications {
+		var c *domain.Classification
+		switch change.Change {
+		case "removed":
+			amendment.Removed = append(amendment.Removed, existing[classificationKey(change.Disease)].ID)
+			continue
+		case "added":
+			c = &domain.Classification{
+				ID:           uuid.New(),
+				AssessmentID: assessment.ID,
+				Disease:      strings.TrimSpace(change.After.Disease),
+				RuleVersion:  reviewRuleVersion,
+			}
+			amendment.Added = append(amendment.Added, c)
+		default:
+			copied := *existing[classificationKey(change.Disease)]
+			c = &copied
+			if reclassified(change.Before, change.After) {
+				amendment.Changed = append(amendment.Changed, c)
+			}
+		}
+
+		c.Color, c.Details = change.After.Color, change.After.Details
+		c.TreatmentPriority = colorPriority(c.Color)
+		c.IsCriticalIllness = c.TreatmentPriority == 1
+		c.RequiresUrgentReferral = c.IsCriticalIllness
+
+		if change.After.TreatmentPlans == nil {
+			continue
+		}
+		plans := []*domain.TreatmentPlan{}
+		for _, t := range change.After.TreatmentPlans {
+			plans = append(plans, &domain.TreatmentPlan{
+				ID:                  uuid.New(),
+				AssessmentID:        assessment.ID,
+				ClassificationID:    c.ID,
+				DrugName:            strings.TrimSpace(t.DrugName),
+				Dosage:              t.Dosage,
+				Frequency:           t.Frequency,
+				Duration:            t.Duration,
+				AdministrationRoute: t.AdministrationRoute,
+				IsPreReferral:       t.IsPreReferral,
+				Instructions:        t.Instructions,
+			})
+		}
+		amendment.Plans[c.ID] = plans
+	}
+
+	// The cards of removed and changed classifications are deleted with the
+	// amendment; the rest stay with the visit.
+	replaced := map[uuid.UUID]bool{}
+	for _, id := range amendment.Removed {
+		replaced[id] = true
+	}
+	for _, c := range amendment.Changed {
+		replaced[c.ID] = true
+	}
+	given := map[string]bool{}
+	for _, item := range counselings {
+		if item.IsStructured() && (item.ClassificationID == nil || !replaced[*item.ClassificationID]) {
+			given[item.AdviceType] = true
+		}
+	}
+
+	youngInfant := assessment.AssessmentType == domain.TypeYoungInfant
+	for _, c := range append(append([]*domain.Classification{}, amendment.Changed...), amendment.Added...) {
+		classificationID := c.ID
+		for _, card := range counseling.VisitCards(c.Disease, c.Color, youngInfant, given) {
+			amendment.Counselings = append(amendment.Counselings, &domain.Counseling{
+				ID:               uuid.New(),
+				AssessmentID:     assessment.ID,
+				ClassificationID: &classificationID,
+				AdviceType:       card.Type,
+				Details:          card.Text,
+				Language:         "en",
+			})
+		}
+	}
+	return amendment
+}
+
+// amendmentAudit records each classification the amendment removes, adds
+// or changes, with the reviewer as the actor.
+func amendmentAudit(ctx context.Context, classifications []*domain.Classification, amendment *domain.ReviewAmendment) ([]*domain.AuditEntry, error) {
+	byID := make(map[uuid.UUID]*domain.Classification, len(classifications))
+	for _, c := range classifications {
+		byID[c.ID] = c
+	}
+
+	entries := []*domain.AuditEntry{}
+	record := func(action domain.AuditAction, id uuid.UUID, before, after interface{}) error {
+		entry, err := domain.NewAuditEntry(ctx, action, domain.AuditClassification, id, before, after)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	}
+	for _, id := range amendment.Removed {
+		if err := record(domain.AuditDelete, id, byID[id], nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range amendment.Changed {
+		if err := record(domain.AuditUpdate, c.ID, byID[c.ID], c); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range amendment.Added {
+		if err := record(domain.AuditCreate, c.ID, nil, c); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+func colorPriority(color string) int {
+	switch strings.ToLower(color) {
+	case "pink":
+		return 1
+	case "yellow":
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,460 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeReviewRepo struct {
+	domain.AssessmentReviewRepository
+	reviews    map[uuid.UUID]*domain.AssessmentReview
+	actions    []*domain.ReviewAction
+	amendments []*domain.ReviewAmendment
+	offline    map[uuid.UUID][]string
+}
+
+func (f *fakeReviewRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*domain.AssessmentReview, error) {
+	review, ok := f.reviews[assessmentID]
+	if !ok {
+		return nil, domain.ErrReviewNotFound
+	}
+	copied := *review
+	copied.Reasons = append([]domain.ReviewReason{}, review.Reasons...)
+	return &copied, nil
+}
+
+func (f *fakeReviewRepo) Save(ctx context.Context, review *domain.AssessmentReview) error {
+	if review.ID == uuid.Nil {
+		review.ID = uuid.New()
+	}
+	copied := *review
+	f.reviews[review.AssessmentID] = &copied
+	return nil
+}
+
+func (f *fakeReviewRepo) RecordAction(ctx context.Context, action *domain.ReviewAction, status domain.ReviewStatus) error {
+	for _, review := range f.reviews {
+		if review.ID != action.ReviewID || status == "" {
+			continue
+		}
+		if review.Status != domain.ReviewPending {
+			return domain.ErrReviewClosed
+		}
+		review.Status = status
+		review.ReviewedBy = action.ReviewerID
+	}
+	f.actions = append(f.actions, action)
+	return nil
+}
+
+func (f *fakeReviewRepo) Amend(ctx context.Context, action *domain.ReviewAction, status domain.ReviewStatus, amendment *domain.ReviewAmendment) error {
+	if err := f.RecordAction(ctx, action, status); err != nil {
+		return err
+	}
+	f.amendments = append(f.amendments, amendment)
+	return nil
+}
+
+func (f *fakeReviewRepo) ListActions(ctx context.Context, reviewID uuid.UUID) ([]*domain.ReviewAction, error) {
+	return f.actions, nil
+}
+
+func (f *fakeReviewRepo) SaveOfflineResult(ctx context.Context, assessmentID uuid.UUID, classifications []string, at time.Time) error {
+	f.offline[assessmentID] = classifications
+	return nil
+}
+
+func (f *fakeReviewRepo) GetOfflineResult(ctx context.Context, assessmentID uuid.UUID) ([]string, error) {
+	return f.offline[assessmentID], nil
+}
+
+type fakeReviewUserRepo struct {
+	domain.MedicalProfessionalRepository
+	users map[uuid.UUID]*domain.MedicalProfessional
+}
+
+func (f *fakeReviewUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.MedicalProfessional, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+type fakeReviewAnswerRepo struct {
+	domain.MedicalProfessionalAnswerRepository
+	answers domain.JSONB
+}
+
+func (f *fakeReviewAnswerRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*domain.MedicalProfessionalAnswer, error) {
+	if f.answers == nil {
+		return nil, domain.ErrMedicalProfessionalAnswerNotFound
+	}
+	return &domain.MedicalProfessionalAnswer{AssessmentID: assessmentID, Answers: f.answers}, nil
+}
+
+type fakeReviewClassificationRepo struct {
+	domain.ClassificationRepository
+	classifications []*domain.Classification
+}
+
+func (f *fakeReviewClassificationRepo) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Classification, error) {
+	return f.classifications, nil
+}
+
+type fakeReviewPlanRepo struct {
+	domain.TreatmentPlanRepository
+	plans []*domain.TreatmentPlan
+}
+
+func (f *fakeReviewPlanRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.TreatmentPlan, error) {
+	return f.plans, nil
+}
+
+type fakeReviewCounselingRepo struct {
+	domain.CounselingRepository
+	counselings []*domain.Counseling
+}
+
+func (f *fakeReviewCounselingRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Counseling, error) {
+	return f.counselings, nil
+}
+
+type fakeReviewFinalizer struct {
+	finalized int
+}
+
+func (f *fakeReviewFinalizer) Finalize(ctx context.Context, assessment *domain.Assessment, generatedBy uuid.UUID) (*domain.Prescription, error) {
+	f.finalized++
+	return &domain.Prescription{AssessmentID: assessment.ID}, nil
+}
+
+type reviewFixture struct {
+	uc              domain.AssessmentReviewUsecase
+	reviews         *fakeReviewRepo
+	classifications *fakeReviewClassificationRepo
+	plans           *fakeReviewPlanRepo
+	counselings     *fakeReviewCounselingRepo
+	prescriptions   *fakeReviewFinalizer
+	assessment      *domain.Assessment
+	clinician       *domain.MedicalProfessional
+	supervisor      *domain.MedicalProfessional
+}
+
+func newReviewFixture(criteria ...domain.ReviewReason) *reviewFixture {
+	clinician := &domain.MedicalProfessional{ID: uuid.New(), FacilityName: "Adama HC", Role: string(domain.NurseRole)}
+	supervisor := &domain.MedicalProfessional{ID: uuid.New(), FacilityName: "Adama HC", Role: string(domain.SupervisorRole)}
+	assessment := &domain.Assessment{
+		ID:                    uuid.New(),
+		PatientID:             uuid.New(),
+		MedicalProfessionalID: clinician.ID,
+		AssessmentType:        domain.TypeChild,
+		Status:                domain.StatusClassified,
+	}
+
+	reviews := &fakeReviewRepo{
+		reviews: map[uuid.UUID]*domain.AssessmentReview{},
+		offline: map[uuid.UUID][]string{},
+	}
+	classifications := &fakeReviewClassificationRepo{}
+	users := &fakeReviewUserRepo{users: map[uuid.UUID]*domain.MedicalProfessional{
+		clinician.ID:  clinician,
+		supervisor.ID: supervisor,
+	}}
+	answers := &fakeReviewAnswerRepo{answers: domain.JSONB{"chest_indrawing": "no", "breaths_per_minute": float64(44)}}
+
+	plans := &fakeReviewPlanRepo{}
+	counselings := &fakeReviewCounselingRepo{}
+	prescriptions := &fakeReviewFinalizer{}
+
+	uc := NewAssessmentReviewUsecase(reviews, &fakeLifecycleRepo{assessment: assessment}, users, answers, classifications, plans, counselings, prescriptions, criteria, time.Second)
+	return &reviewFixture{
+		uc:              uc,
+		reviews:         reviews,
+		classifications: classifications,
+		plans:           plans,
+		counselings:     counselings,
+		prescriptions:   prescriptions,
+		assessment:      assessment,
+		clinician:       clinician,
+		supervisor:      supervisor,
+	}
+}
+
+func TestReviewEvaluateQueuesEmergencies(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+
+	f.classifications.classifications = []*domain.Classification{{Disease: "PNEUMONIA", Color: "yellow"}}
+	review, err := f.uc.Evaluate(ctx, f.assessment)
+	if err != nil || review != nil {
+		t.Fatalf("Evaluate = %v, %v; want no review", review, err)
+	}
+
+	f.classifications.classifications = append(f.classifications.classifications,
+		&domain.Classification{Disease: "SEVERE PNEUMONIA OR VERY SEVERE DISEASE", Color: "pink", RequiresUrgentReferral: true})
+	review, err = f.uc.Evaluate(ctx, f.assessment)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if review == nil || review.Status != domain.ReviewPending || len(review.Reasons) != 1 || review.Reasons[0] != domain.ReviewReasonEmergency {
+		t.Fatalf("review = %+v, want pending emergency review", review)
+	}
+}
+
+func TestReviewEvaluateRespectsCriteria(t *testing.T) {
+	f := newReviewFixture(domain.ReviewReasonTrainee)
+	f.classifications.classifications = []*domain.Classification{{Disease: "SEVERE DEHYDRATION", Color: "pink", RequiresUrgentReferral: true}}
+
+	review, err := f.uc.Evaluate(context.Background(), f.assessment)
+	if err != nil || review != nil {
+		t.Fatalf("Evaluate = %v, %v; want no review with only the trainee criterion", review, err)
+	}
+
+	f.clinician.IsTrainee = true
+	review, err = f.uc.Evaluate(context.Background(), f.assessment)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if review == nil || len(review.Reasons) != 1 || review.Reasons[0] != domain.ReviewReasonTrainee {
+		t.Fatalf("review = %+v, want trainee review", review)
+	}
+}
+
+func TestReviewOfflineDiscordance(t *testing.T) {
+	f := newReviewFixture(domain.ReviewReasonDiscordantOffline)
+	ctx := context.Background()
+	f.classifications.classifications = []*domain.Classification{{Disease: "Pneumonia", Color: "yellow"}}
+
+	detail, err := f.uc.ReportOfflineResult(ctx, f.assessment.ID, f.clinician.ID, []string{"PNEUMONIA "})
+	if err != nil {
+		t.Fatalf("ReportOfflineResult: %v", err)
+	}
+	if detail.Status != domain.ReviewNotRequired {
+		t.Fatalf("status = %s, want not required for matching results", detail.Status)
+	}
+
+	detail, err = f.uc.ReportOfflineResult(ctx, f.assessment.ID, f.clinician.ID, []string{"PNEUMONIA", "MALARIA"})
+	if err != nil {
+		t.Fatalf("ReportOfflineResult: %v", err)
+	}
+	if detail.Status != domain.ReviewPending || detail.Review.Reasons[0] != domain.ReviewReasonDiscordantOffline {
+		t.Fatalf("detail = %+v, want discordant review", detail)
+	}
+
+	if _, err := f.uc.ReportOfflineResult(ctx, f.assessment.ID, f.supervisor.ID, []string{"MALARIA"}); !errors.Is(err, domain.ErrAssessmentNotFound) {
+		t.Errorf("report by another clinician err = %v, want not found", err)
+	}
+}
+
+func TestReviewAmendRecordsDiff(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+	pneumonia := &domain.Classification{ID: uuid.New(), Disease: "PNEUMONIA", Color: "yellow"}
+	f.classifications.classifications = []*domain.Classification{
+		pneumonia,
+		{ID: uuid.New(), Disease: "NO ANAEMIA", Color: "green"},
+	}
+	f.plans.plans = []*domain.TreatmentPlan{{ClassificationID: pneumonia.ID, DrugName: "Amoxicillin"}}
+	f.clinician.IsTrainee = true
+	if _, err := f.uc.Evaluate(ctx, f.assessment); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	req := &domain.AmendReviewRequest{
+		Comment: "Chest indrawing was present",
+		Answers: domain.JSONB{"chest_indrawing": "yes", "breaths_per_minute": float64(44)},
+		Classifications: []domain.ReviewedClassification{
+			{
+				Disease:        "SEVERE PNEUMONIA OR VERY SEVERE DISEASE",
+				Color:          "pink",
+				TreatmentPlans: []domain.ReviewedTreatment{{DrugName: "Amoxicillin", IsPreReferral: true}},
+			},
+			{Disease: "NO ANAEMIA", Color: "green"},
+		},
+	}
+	detail, err := f.uc.Amend(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), req)
+	if err != nil {
+		t.Fatalf("Amend: %v", err)
+	}
+	if detail.Status != domain.ReviewAmended {
+		t.Fatalf("status = %s, want amended", detail.Status)
+	}
+
+	diff := f.reviews.actions[0].Diff
+	if len(diff.Answers) != 1 || diff.Answers[0].NodeID != "chest_indrawing" || diff.Answers[0].Before != "no" || diff.Answers[0].After != "yes" {
+		t.Errorf("answer diff = %+v", diff.Answers)
+	}
+	changes := map[string]string{}
+	for _, change := range diff.Classifications {
+		changes[change.Disease] = change.Change
+	}
+	if len(changes) != 2 || changes["PNEUMONIA"] != "removed" || changes["SEVERE PNEUMONIA OR VERY SEVERE DISEASE"] != "added" {
+		t.Errorf("classification diff = %+v", diff.Classifications)
+	}
+
+	// The amendment is applied to the record with the action.
+	amendment := f.reviews.amendments[0]
+	if amendment.Answers["chest_indrawing"] != "yes" || amendment.Answers["breaths_per_minute"] != float64(44) {
+		t.Errorf("amended answers = %v", amendment.Answers)
+	}
+	if len(amendment.Removed) != 1 || amendment.Removed[0] != pneumonia.ID {
+		t.Errorf("removed = %v, want pneumonia", amendment.Removed)
+	}
+	if len(amendment.Added) != 1 || !amendment.Added[0].RequiresUrgentReferral || amendment.Added[0].TreatmentPriority != 1 {
+		t.Fatalf("added = %+v, want one urgent classification", amendment.Added)
+	}
+	added := amendment.Plans[amendment.Added[0].ID]
+	if len(added) != 1 || added[0].DrugName != "Amoxicillin" || !added[0].IsPreReferral || added[0].ClassificationID != amendment.Added[0].ID {
+		t.Errorf("plans of the added classification = %+v", added)
+	}
+	// The severe classification gets the referral advice, and each change
+	// is audited with the amendment.
+	if len(amendment.Counselings) != 1 || amendment.Counselings[0].AdviceType != domain.AdviceReferral || *amendment.Counselings[0].ClassificationID != amendment.Added[0].ID {
+		t.Errorf("counselings = %+v, want the referral advice of the added classification", amendment.Counselings)
+	}
+	audited := map[domain.AuditAction]uuid.UUID{}
+	for _, entry := range amendment.Audit {
+		audited[entry.Action] = entry.EntityID
+	}
+	if len(amendment.Audit) != 2 || audited[domain.AuditDelete] != pneumonia.ID || audited[domain.AuditCreate] != amendment.Added[0].ID {
+		t.Errorf("audit = %+v, want the removal of pneumonia and the added classification", amendment.Audit)
+	}
+	if f.prescriptions.finalized != 1 {
+		t.Errorf("prescription consolidated %d times, want 1", f.prescriptions.finalized)
+	}
+
+	if _, err := f.uc.Approve(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), ""); !errors.Is(err, domain.ErrReviewClosed) {
+		t.Errorf("approve after amend err = %v, want closed", err)
+	}
+	if _, err := f.uc.Comment(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), "Discussed with the nurse"); err != nil {
+		t.Errorf("comment after amend: %v", err)
+	}
+}
+
+func TestReviewAmendReplacesTreatments(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+	pneumonia := &domain.Classification{ID: uuid.New(), Disease: "PNEUMONIA", Color: "yellow"}
+	f.classifications.classifications = []*domain.Classification{pneumonia}
+	f.plans.plans = []*domain.TreatmentPlan{{ClassificationID: pneumonia.ID, DrugName: "Cotrimoxazole"}}
+	f.clinician.IsTrainee = true
+	if _, err := f.uc.Evaluate(ctx, f.assessment); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	req := &domain.AmendReviewRequest{
+		Comment: "Amoxicillin is first line",
+		Classifications: []domain.ReviewedClassification{{
+			Disease:        "Pneumonia",
+			Color:          "Yellow",
+			TreatmentPlans: []domain.ReviewedTreatment{{DrugName: "Amoxicillin", Frequency: "twice daily", Duration: "5 days"}},
+		}},
+	}
+	if _, err := f.uc.Amend(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), req); err != nil {
+		t.Fatalf("Amend: %v", err)
+	}
+
+	diff := f.reviews.actions[0].Diff
+	if len(diff.Classifications) != 1 || diff.Classifications[0].Change != "changed" || diff.Classifications[0].Before.TreatmentPlans[0].DrugName != "Cotrimoxazole" {
+		t.Errorf("classification diff = %+v", diff.Classifications)
+	}
+	amendment := f.reviews.amendments[0]
+	if amendment.Answers != nil || len(amendment.Changed) != 0 || len(amendment.Counselings) != 0 || len(amendment.Audit) != 0 {
+		t.Errorf("only the treatments should change, got %+v", amendment)
+	}
+	if plans := amendment.Plans[pneumonia.ID]; len(plans) != 1 || plans[0].DrugName != "Amoxicillin" {
+		t.Errorf("plans = %+v, want amoxicillin", plans)
+	}
+	if f.prescriptions.finalized != 1 {
+		t.Errorf("prescription consolidated %d times, want 1", f.prescriptions.finalized)
+	}
+}
+
+func TestReviewAmendGivesGeneralAdviceOnce(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+	pneumonia := &domain.Classification{ID: uuid.New(), Disease: "PNEUMONIA", Color: "yellow"}
+	f.classifications.classifications = []*domain.Classification{pneumonia}
+	for _, adviceType := range []string{domain.AdviceHomeFluids, domain.AdviceFeeding, domain.AdviceWhenToReturn} {
+		f.counselings.counselings = append(f.counselings.counselings, &domain.Counseling{ClassificationID: &pneumonia.ID, AdviceType: adviceType})
+	}
+	f.clinician.IsTrainee = true
+	if _, err := f.uc.Evaluate(ctx, f.assessment); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	req := &domain.AmendReviewRequest{
+		Comment: "Fever was missed",
+		Classifications: []domain.ReviewedClassification{
+			{Disease: "PNEUMONIA", Color: "yellow"},
+			{Disease: "FEVER", Color: "yellow"},
+			{Disease: "NO ANEMIA", Color: "green"},
+		},
+	}
+	if _, err := f.uc.Amend(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), req); err != nil {
+		t.Fatalf("Amend: %v", err)
+	}
+
+	// Pneumonia keeps the visit's general advice, fever has nothing more
+	// and a negative classification gets no cards.
+	amendment := f.reviews.amendments[0]
+	if len(amendment.Added) != 2 || len(amendment.Counselings) != 0 {
+		t.Errorf("added %d classifications with counselings %+v, want none repeated", len(amendment.Added), amendment.Counselings)
+	}
+}
+
+func TestReviewRejectsEmptyAmendmentAndSelfReview(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+	f.clinician.IsTrainee = true
+	f.classifications.classifications = []*domain.Classification{{Disease: "PNEUMONIA", Color: "yellow"}}
+	if _, err := f.uc.Evaluate(ctx, f.assessment); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	req := &domain.AmendReviewRequest{Comment: "Checked", Answers: domain.JSONB{"chest_indrawing": "no"}}
+	if _, err := f.uc.Amend(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), req); !errors.Is(err, domain.ErrEmptyAmendment) {
+		t.Errorf("empty amendment err = %v", err)
+	}
+	if _, err := f.uc.Approve(ctx, f.assessment.ID, f.clinician.ID, string(domain.AdminRole), ""); !errors.Is(err, domain.ErrSelfReview) {
+		t.Errorf("self review err = %v", err)
+	}
+
+	f.supervisor.FacilityName = "Nazret Hospital"
+	if _, err := f.uc.Approve(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), ""); !errors.Is(err, domain.ErrAssessmentNotFound) {
+		t.Errorf("other facility err = %v, want not found", err)
+	}
+}
+
+func TestReviewReopensOnNewReason(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+	f.clinician.IsTrainee = true
+	f.classifications.classifications = []*domain.Classification{{Disease: "PNEUMONIA", Color: "yellow"}}
+	if _, err := f.uc.Evaluate(ctx, f.assessment); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if _, err := f.uc.Approve(ctx, f.assessment.ID, f.supervisor.ID, string(domain.SupervisorRole), "Agree"); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+
+	review, err := f.uc.Evaluate(ctx, f.assessment)
+	if err != nil || review.Status != domain.ReviewApproved {
+		t.Fatalf("re-evaluation = %+v, %v; want approved review left alone", review, err)
+	}
+
+	f.classifications.classifications = append(f.classifications.classifications,
+		&domain.Classification{Disease: "SEVERE DEHYDRATION", Color: "pink", RequiresUrgentReferral: true})
+	review, err = f.uc.Evaluate(ctx, f.assessment)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if review.Status != domain.ReviewPending || len(review.Reasons) != 2 {
+		t.Fatalf("review = %+v, want re-opened with both reasons", review)
+	}
+}
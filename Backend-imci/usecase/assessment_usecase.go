@@ -6,23 +6,30 @@ import (
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
 	"github.com/google/uuid"
 )
 
 type AssessmentUsecase struct {
 	assessmentRepo domain.AssessmentRepository
 	patientRepo    domain.PatientRepository
+	vitalAlerts    domain.VitalAlertUsecase
+	auditor        domain.AuditRecorder
 	contextTimeout time.Duration
 }
 
 func NewAssessmentUsecase(
 	assessmentRepo domain.AssessmentRepository,
 	patientRepo domain.PatientRepository,
+	vitalAlerts domain.VitalAlertUsecase,
+	auditor domain.AuditRecorder,
 	timeout time.Duration,
 ) domain.AssessmentUsecase {
 	return &AssessmentUsecase{
 		assessmentRepo: assessmentRepo,
 		patientRepo:    patientRepo,
+		vitalAlerts:    vitalAlerts,
+		auditor:        auditor,
 		contextTimeout: timeout,
 	}
 }
@@ -47,6 +54,11 @@ func (uc *AssessmentUsecase) CreateAssessment(ctx context.Context, req *domain.C
 		return nil, err
 	}
 
+	position, err := uc.validateMeasurements(req.LengthCm, req.HeadCircumferenceCm, req.MeasurementPosition, ageMonths)
+	if err != nil {
+		return nil, err
+	}
+
 	mainSymptoms := domain.JSONB{}
 	for _, symptom := range req.MainSymptoms {
 		mainSymptoms[symptom] = true
@@ -63,6 +75,11 @@ func (uc *AssessmentUsecase) CreateAssessment(ctx context.Context, req *domain.C
 		MainSymptoms:         mainSymptoms,
 		MUAC:                 req.MUAC,
 		RespiratoryRate:      req.RespiratoryRate,
+		OxygenSaturation:     req.OxygenSaturation,
+		HbLevel:              req.HbLevel,
+		LengthCm:             req.LengthCm,
+		HeadCircumferenceCm:  req.HeadCircumferenceCm,
+		MeasurementPosition:  position,
 		AgeMonths:            ageMonths,
 		GuidelineVersion:     "2014",
 		StartTime:            assessmentTime,
@@ -76,6 +93,11 @@ func (uc *AssessmentUsecase) CreateAssessment(ctx context.Context, req *domain.C
 		return nil, fmt.Errorf("failed to create assessment: %w", err)
 	}
 
+	uc.evaluateVitals(ctx, assessment)
+
+	if err := uc.auditor.Record(ctx, domain.AuditCreate, domain.AuditAssessment, assessment.ID, nil, assessment); err != nil {
+		return nil, err
+	}
 	return assessment, nil
 }
 
@@ -92,30 +114,130 @@ func (uc *AssessmentUsecase) validateWeight(weightKg float64, ageMonths int) err
 	return nil
 }
 
+// validateMeasurements checks length/height and head circumference against
+// plausible bands for the age group and returns the measurement position,
+// defaulting to lying under 24 months and standing from 24 months.
+func (uc *AssessmentUsecase) validateMeasurements(lengthCm, headCircumferenceCm *float64, position domain.MeasurementPosition, ageMonths int) (domain.MeasurementPosition, error) {
+	if lengthCm != nil {
+		if ageMonths < 2 {
+			if *lengthCm < 38.0 || *lengthCm > 70.0 {
+				return "", fmt.Errorf("length %.1f cm outside valid range for infants (38–70 cm): %w", *lengthCm, domain.ErrInvalidLength)
+			}
+		} else {
+			if *lengthCm < 45.0 || *lengthCm > 125.0 {
+				return "", fmt.Errorf("length/height %.1f cm outside valid range for children (45–125 cm): %w", *lengthCm, domain.ErrInvalidLength)
+			}
+		}
+	}
+
+	if headCircumferenceCm != nil {
+		if ageMonths < 2 {
+			if *headCircumferenceCm < 28.0 || *headCircumferenceCm > 45.0 {
+				return "", fmt.Errorf("head circumference %.1f cm outside valid range for infants (28–45 cm): %w", *headCircumferenceCm, domain.ErrInvalidHeadCircumference)
+			}
+		} else {
+			if *headCircumferenceCm < 35.0 || *headCircumferenceCm > 56.0 {
+				return "", fmt.Errorf("head circumference %.1f cm outside valid range for children (35–56 cm): %w", *headCircumferenceCm, domain.ErrInvalidHeadCircumference)
+			}
+		}
+	}
+
+	switch position {
+	case domain.PositionLying, domain.PositionStanding:
+		return position, nil
+	case "":
+		if lengthCm == nil {
+			return "", nil
+		}
+		if ageMonths < 24 {
+			return domain.PositionLying, nil
+		}
+		return domain.PositionStanding, nil
+	default:
+		return "", domain.ErrInvalidMeasurementPosition
+	}
+}
+
 func (uc *AssessmentUsecase) GetAssessment(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) (*domain.Assessment, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	return uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID)
+	assessment, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment.VitalAlerts = uc.vitalAlerts.Check(assessment)
+	if err := uc.auditor.Record(ctx, domain.AuditView, domain.AuditAssessment, assessment.ID, nil, nil); err != nil {
+		return nil, err
+	}
+	return assessment, nil
 }
 
 func (uc *AssessmentUsecase) GetAssessmentsByPatient(ctx context.Context, patientID uuid.UUID, medicalProfessionalID uuid.UUID) ([]*domain.Assessment, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	return uc.assessmentRepo.GetByPatientID(ctx, patientID, medicalProfessionalID)
+	assessments, err := uc.assessmentRepo.GetByPatientID(ctx, patientID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assessment := range assessments {
+		assessment.VitalAlerts = uc.vitalAlerts.Check(assessment)
+		if err := uc.auditor.Record(ctx, domain.AuditView, domain.AuditAssessment, assessment.ID, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	return assessments, nil
 }
 
 func (uc *AssessmentUsecase) UpdateAssessment(ctx context.Context, assessment *domain.Assessment) error {
 	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	return uc.assessmentRepo.Update(ctx, assessment)
+	position, err := uc.validateMeasurements(assessment.LengthCm, assessment.HeadCircumferenceCm, assessment.MeasurementPosition, assessment.AgeMonths)
+	if err != nil {
+		return err
+	}
+	assessment.MeasurementPosition = position
+
+	before, err := uc.assessmentRepo.GetByID(ctx, assessment.ID, assessment.MedicalProfessionalID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
+		return err
+	}
+
+	uc.evaluateVitals(ctx, assessment)
+
+	return uc.auditor.Record(ctx, domain.AuditUpdate, domain.AuditAssessment, assessment.ID, before, assessment)
 }
 
 func (uc *AssessmentUsecase) DeleteAssessment(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
 	defer cancel()
 
-	return uc.assessmentRepo.Delete(ctx, assessmentID, medicalProfessionalID)
+	before, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.assessmentRepo.Delete(ctx, assessmentID, medicalProfessionalID); err != nil {
+		return err
+	}
+
+	return uc.auditor.Record(ctx, domain.AuditDelete, domain.AuditAssessment, assessmentID, before, nil)
+}
+
+// evaluateVitals raises the alerts of a saved assessment. The assessment is
+// already stored, so a failure is logged rather than returned; the alerts
+// are still attached from the thresholds alone.
+func (uc *AssessmentUsecase) evaluateVitals(ctx context.Context, assessment *domain.Assessment) {
+	if _, err := uc.vitalAlerts.Evaluate(ctx, assessment, nil); err != nil {
+		logger.Error("failed to evaluate vital alerts", "assessment_id", assessment.ID, "error", err)
+		assessment.VitalAlerts = uc.vitalAlerts.Check(assessment)
+	}
 }
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+)
+
+func TestValidateMeasurements(t *testing.T) {
+	cm := func(v float64) *float64 { return &v }
+	uc := &AssessmentUsecase{}
+
+	tests := []struct {
+		name         string
+		lengthCm     *float64
+		headCm       *float64
+		position     domain.MeasurementPosition
+		ageMonths    int
+		wantPosition domain.MeasurementPosition
+		wantErr      error
+	}{
+		{"no measurements", nil, nil, "", 12, "", nil},
+		{"infant length at lower bound", cm(38), nil, "", 1, domain.PositionLying, nil},
+		{"infant length below range", cm(37.9), nil, "", 1, "", domain.ErrInvalidLength},
+		{"infant length above range", cm(70.1), nil, "", 1, "", domain.ErrInvalidLength},
+		{"child length at upper bound", cm(125), nil, "", 36, domain.PositionStanding, nil},
+		{"child length below range", cm(44.9), nil, "", 6, "", domain.ErrInvalidLength},
+		{"child height above range", cm(125.1), nil, "", 48, "", domain.ErrInvalidLength},
+		{"infant head circumference in range", nil, cm(34), "", 0, "", nil},
+		{"infant head circumference below range", nil, cm(27.9), "", 1, "", domain.ErrInvalidHeadCircumference},
+		{"infant head circumference above range", nil, cm(45.1), "", 1, "", domain.ErrInvalidHeadCircumference},
+		{"child head circumference below range", nil, cm(34.9), "", 12, "", domain.ErrInvalidHeadCircumference},
+		{"child head circumference above range", nil, cm(56.1), "", 12, "", domain.ErrInvalidHeadCircumference},
+		{"lying by default under 24 months", cm(75), nil, "", 23, domain.PositionLying, nil},
+		{"standing by default from 24 months", cm(85), nil, "", 24, domain.PositionStanding, nil},
+		{"explicit position kept", cm(85), nil, domain.PositionLying, 30, domain.PositionLying, nil},
+		{"unknown position", cm(85), nil, "sitting", 30, "", domain.ErrInvalidMeasurementPosition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position, err := uc.validateMeasurements(tt.lengthCm, tt.headCm, tt.position, tt.ageMonths)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if position != tt.wantPosition {
+				t.Errorf("expected position %q, got %q", tt.wantPosition, position)
+			}
+		})
+	}
+}
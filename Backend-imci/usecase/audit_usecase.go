@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/google/uuid"
+)
+
+// auditVerifyBatch is how many entries Verify reads at a time.
+const auditVerifyBatch = 1000
+
+type AuditUsecase struct {
+	auditRepo      domain.AuditRepository
+	contextTimeout time.Duration
+}
+
+func NewAuditUsecase(auditRepo domain.AuditRepository, timeout time.Duration) domain.AuditUsecase {
+	return &AuditUsecase{
+		auditRepo:      auditRepo,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *AuditUsecase) Record(ctx context.Context, action domain.AuditAction, entityType domain.AuditEntity, entityID uuid.UUID, before, after interface{}) error {
+	// The entry is written even when the request that caused it has timed
+	// out or been cancelled.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), uc.contextTimeout)
+	defer cancel()
+
+	entry, err := domain.NewAuditEntry(ctx, action, entityType, entityID, before, after)
+	if err == nil {
+		err = uc.auditRepo.Append(ctx, entry)
+	}
+	if err != nil {
+		logger.Error("failed to record audit entry", "action", action, "entity_type", entityType, "entity_id", entityID, "error", err)
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func (uc *AuditUsecase) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+	return uc.auditRepo.List(ctx, filter)
+}
+
+// Verify re-hashes the whole chain from the first entry. It runs without the
+// usual timeout because the log only grows.
+func (uc *AuditUsecase) Verify(ctx context.Context) (*domain.AuditVerification, error) {
+	result := &domain.AuditVerification{Valid: true, HeadHash: domain.AuditGenesisHash}
+
+	for {
+		entries, err := uc.auditRepo.Chain(ctx, result.HeadSeq, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			switch {
+			case entry.PrevHash != result.HeadHash:
+				result.Reason = fmt.Sprintf("entry %d does not link to the entry before it", entry.Seq)
+			case domain.AuditHash(entry.PrevHash, entry) != entry.Hash:
+				result.Reason = fmt.Sprintf("entry %d does not match its hash", entry.Seq)
+			}
+			if result.Reason != "" {
+				seq := entry.Seq
+				result.Valid = false
+				result.BrokenAt = &seq
+				return result, nil
+			}
+
+			result.Checked++
+			result.HeadSeq = entry.Seq
+			result.HeadHash = entry.Hash
+		}
+
+		if len(entries) < auditVerifyBatch {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeAuditRepo struct {
+	entries []*domain.AuditEntry
+}
+
+func (f *fakeAuditRepo) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.PrevHash = domain.AuditGenesisHash
+	if n := len(f.entries); n > 0 {
+		entry.PrevHash = f.entries[n-1].Hash
+	}
+	entry.Hash = domain.AuditHash(entry.PrevHash, entry)
+	entry.Seq = int64(len(f.entries) + 1)
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAuditRepo) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	return f.entries, nil
+}
+
+func (f *fakeAuditRepo) Chain(ctx context.Context, afterSeq int64, limit int) ([]*domain.AuditEntry, error) {
+	entries := []*domain.AuditEntry{}
+	for _, entry := range f.entries {
+		if entry.Seq > afterSeq && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func newAuditFixture(t *testing.T) (*fakeAuditRepo, domain.AuditUsecase) {
+	t.Helper()
+	repo := &fakeAuditRepo{}
+	uc := NewAuditUsecase(repo, time.Second)
+
+	actorID := uuid.New()
+	ctx := domain.WithRequestMeta(context.Background(), domain.RequestMeta{
+		ActorID:   &actorID,
+		ActorRole: "nurse",
+		IP:        "10.0.0.7",
+		Method:    "PUT",
+		Path:      "/api/v1/patients/1",
+		RequestID: "req-1",
+	})
+
+	patientID := uuid.New()
+	uc.Record(ctx, domain.AuditCreate, domain.AuditPatient, patientID, nil, map[string]string{"name": "Abebe"})
+	uc.Record(ctx, domain.AuditUpdate, domain.AuditPatient, patientID, map[string]string{"name": "Abebe"}, map[string]string{"name": "Abebe K"})
+	uc.Record(ctx, domain.AuditView, domain.AuditPatient, patientID, nil, nil)
+	return repo, uc
+}
+
+func TestAuditRecordCarriesRequestMeta(t *testing.T) {
+	repo, _ := newAuditFixture(t)
+
+	if len(repo.entries) != 3 {
+		t.Fatalf("entries = %d, want 3", len(repo.entries))
+	}
+	update := repo.entries[1]
+	if update.ActorID == nil || update.ActorRole != "nurse" || update.IP != "10.0.0.7" || update.RequestID != "req-1" {
+		t.Errorf("request metadata not recorded: %+v", update)
+	}
+	if string(update.Before) != `{"name":"Abebe"}` || string(update.After) != `{"name":"Abebe K"}` {
+		t.Errorf("before/after = %s / %s", update.Before, update.After)
+	}
+	if repo.entries[2].Before != nil || repo.entries[2].After != nil {
+		t.Error("view entries should carry no snapshot")
+	}
+}
+
+func TestAuditVerifyAcceptsIntactChain(t *testing.T) {
+	_, uc := newAuditFixture(t)
+
+	result, err := uc.Verify(context.Background())
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !result.Valid || result.Checked != 3 || result.HeadSeq != 3 {
+		t.Errorf("result = %+v, want valid chain of 3", result)
+	}
+}
+
+func TestAuditVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(repo *fakeAuditRepo)
+		broken int64
+	}{
+		{
+			name: "edited snapshot",
+			tamper: func(repo *fakeAuditRepo) {
+				repo.entries[1].After = json.RawMessage(`{"name":"Someone else"}`)
+			},
+			broken: 2,
+		},
+		{
+			name: "removed entry",
+			tamper: func(repo *fakeAuditRepo) {
+				repo.entries = append(repo.entries[:1], repo.entries[2:]...)
+			},
+			broken: 3,
+		},
+		{
+			name: "re-hashed entry",
+			tamper: func(repo *fakeAuditRepo) {
+				entry := repo.entries[0]
+				entry.ActorRole = "admin"
+				entry.Hash = domain.AuditHash(entry.PrevHash, entry)
+			},
+			broken: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, uc := newAuditFixture(t)
+			tt.tamper(repo)
+
+			result, err := uc.Verify(context.Background())
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if result.Valid || result.BrokenAt == nil || *result.BrokenAt != tt.broken {
+				t.Errorf("result = %+v, want broken at %d", result, tt.broken)
+			}
+		})
+	}
+}
+
+type failingAuditRepo struct {
+	fakeAuditRepo
+}
+
+func (f *failingAuditRepo) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	return errors.New("audit log unavailable")
+}
+
+func TestAuditRecordReturnsWriteFailure(t *testing.T) {
+	uc := NewAuditUsecase(&failingAuditRepo{}, time.Second)
+
+	err := uc.Record(context.Background(), domain.AuditCreate, domain.AuditPatient, uuid.New(), nil, map[string]string{"name": "Abebe"})
+	if err == nil || !strings.Contains(err.Error(), "audit log unavailable") {
+		t.Errorf("err = %v, want the write failure", err)
+	}
+}
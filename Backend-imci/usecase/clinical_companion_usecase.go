@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
+	"github.com/google/uuid"
+)
+
+const (
+	companionPatientLimit = 5
+	companionVisitLimit   = 3
+)
+
+type ClinicalCompanionUsecase struct {
+	telegramRepo    domain.TelegramRepository
+	telegramService domain.TelegramService
+	mpRepo          domain.MedicalProfessionalRepository
+	patientRepo     domain.PatientRepository
+	assessmentRepo  domain.AssessmentRepository
+	followUpRepo    domain.FollowUpRepository
+	visitSummary    domain.VisitSummaryUsecase
+	contextTimeout  time.Duration
+}
+
+// NewClinicalCompanionUsecase backs the bot commands for linked clinicians.
+// telegramService may be nil, in which case classification notifications are
+// dropped.
+func NewClinicalCompanionUsecase(
+	telegramRepo domain.TelegramRepository,
+	telegramService domain.TelegramService,
+	mpRepo domain.MedicalProfessionalRepository,
+	patientRepo domain.PatientRepository,
+	assessmentRepo domain.AssessmentRepository,
+	followUpRepo domain.FollowUpRepository,
+	visitSummary domain.VisitSummaryUsecase,
+	timeout time.Duration,
+) domain.ClinicalCompanionUsecase {
+	return &ClinicalCompanionUsecase{
+		telegramRepo:    telegramRepo,
+		telegramService: telegramService,
+		mpRepo:          mpRepo,
+		patientRepo:     patientRepo,
+		assessmentRepo:  assessmentRepo,
+		followUpRepo:    followUpRepo,
+		visitSummary:    visitSummary,
+		contextTimeout:  timeout,
+	}
+}
+
+func (uc *ClinicalCompanionUsecase) Authenticate(ctx context.Context, chatID int64) (*domain.MedicalProfessional, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	phone, err := uc.telegramRepo.GetPhoneByChatID(ctx, chatID)
+	if err != nil || phone == "" {
+		return nil, domain.ErrTelegramNotLinked
+	}
+
+	mp, err := uc.mpRepo.GetByPhone(ctx, userutil.NormalizePhone(phone))
+	if err != nil {
+		return nil, domain.ErrTelegramNotLinked
+	}
+
+	return mp, nil
+}
+
+func (uc *ClinicalCompanionUsecase) DueFollowUps(ctx context.Context, medicalProfessionalID uuid.UUID, day time.Time) ([]*domain.FollowUp, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return uc.followUpRepo.ListDue(ctx, medicalProfessionalID, from, from.AddDate(0, 0, 1))
+}
+
+func (uc *ClinicalCompanionUsecase) FindPatients(ctx context.Context, medicalProfessionalID uuid.UUID, name string) ([]*domain.PatientVisits, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.ErrNameRequired
+	}
+
+	patients, err := uc.patientRepo.SearchByName(ctx, name, companionPatientLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only patients this clinician has assessed are shown, so the bot cannot
+	// be used to browse other facilities' records.
+	results := []*domain.PatientVisits{}
+	for _, patient := range patients {
+		assessments, err := uc.assessmentRepo.GetByPatientID(ctx, patient.ID, medicalProfessionalID)
+		if err != nil {
+			return nil, err
+		}
+		if len(assessments) == 0 {
+			continue
+		}
+		if len(assessments) > companionVisitLimit {
+			assessments = assessments[:companionVisitLimit]
+		}
+		results = append(results, &domain.PatientVisits{Patient: patient, Assessments: assessments})
+	}
+
+	return results, nil
+}
+
+func (uc *ClinicalCompanionUsecase) VisitSummaryPDF(ctx context.Context, medicalProfessionalID uuid.UUID, assessmentID uuid.UUID) ([]byte, error) {
+	return uc.visitSummary.RenderPDF(ctx, assessmentID, medicalProfessionalID)
+}
+
+// NotifyClassification messages the assessing clinician in the background so
+// a slow or unavailable bot never holds up the assessment.
+func (uc *ClinicalCompanionUsecase) NotifyClassification(_ context.Context, assessment *domain.Assessment, classification *domain.Classification) {
+	if uc.telegramService == nil || assessment == nil || classification == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), uc.contextTimeout)
+		defer cancel()
+
+		if err := uc.notify(ctx, assessment, classification); err != nil {
+			logger.Debug("classification notification not sent", "assessment_id", assessment.ID, "error", err)
+		}
+	}()
+}
+
+func (uc *ClinicalCompanionUsecase) notify(ctx context.Context, assessment *domain.Assessment, classification *domain.Classification) error {
+	mp, err := uc.mpRepo.GetByID(ctx, assessment.MedicalProfessionalID)
+	if err != nil {
+		return err
+	}
+
+	chatID, err := uc.telegramRepo.GetChatIDByPhone(ctx, userutil.NormalizePhone(mp.Phone))
+	if err != nil {
+		return domain.ErrTelegramNotLinked
+	}
+
+	patientName := "patient"
+	if patient, err := uc.patientRepo.GetByID(ctx, assessment.PatientID); err == nil {
+		patientName = patient.Name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🩺 <b>%s</b>\n", html.EscapeString(patientName))
+	fmt.Fprintf(&b, "%s %s\n", colorMarker(classification.Color), html.EscapeString(classification.Disease))
+	if classification.RequiresUrgentReferral {
+		b.WriteString("🚑 <b>Urgent referral</b>\n")
+	}
+	if classification.FollowUpDate != nil {
+		fmt.Fprintf(&b, "📅 Follow-up: %s\n", classification.FollowUpDate.Format("Mon 2 Jan"))
+	}
+	fmt.Fprintf(&b, "\n/summary %s", assessment.ID)
+
+	return uc.telegramService.SendMessage(ctx, chatID, b.String())
+}
+
+func colorMarker(color string) string {
+	switch strings.ToLower(color) {
+	case "pink", "red":
+		return "🔴"
+	case "yellow":
+		return "🟡"
+	case "green":
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type ConsistencyUsecase struct {
+	consistencyRepo domain.ConsistencyRepository
+	assessmentRepo  domain.AssessmentRepository
+	contextTimeout  time.Duration
+}
+
+func NewConsistencyUsecase(
+	consistencyRepo domain.ConsistencyRepository,
+	assessmentRepo domain.AssessmentRepository,
+	timeout time.Duration,
+) domain.ConsistencyUsecase {
+	return &ConsistencyUsecase{
+		consistencyRepo: consistencyRepo,
+		assessmentRepo:  assessmentRepo,
+		contextTimeout:  timeout,
+	}
+}
+
+func (uc *ConsistencyUsecase) Findings(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) ([]*domain.ConsistencyFinding, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID); err != nil {
+		return nil, err
+	}
+	return uc.consistencyRepo.ListFindings(ctx, assessmentID)
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/counseling"
+	"github.com/google/uuid"
+)
+
+type CounselingUsecase struct {
+	assessmentRepo     domain.AssessmentRepository
+	classificationRepo domain.ClassificationRepository
+	counselingRepo     domain.CounselingRepository
+	contextTimeout     time.Duration
+}
+
+func NewCounselingUsecase(
+	assessmentRepo domain.AssessmentRepository,
+	classificationRepo domain.ClassificationRepository,
+	counselingRepo domain.CounselingRepository,
+	timeout time.Duration,
+) domain.CounselingUsecase {
+	return &CounselingUsecase{
+		assessmentRepo:     assessmentRepo,
+		classificationRepo: classificationRepo,
+		counselingRepo:     counselingRepo,
+		contextTimeout:     timeout,
+	}
+}
+
+func (uc *CounselingUsecase) GetSession(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) (*domain.CounselingSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID); err != nil {
+		return nil, err
+	}
+
+	classifications, err := uc.classificationRepo.ListByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.counselingRepo.GetByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCounselingSession(assessmentID, classifications, items), nil
+}
+
+func buildCounselingSession(assessmentID uuid.UUID, classifications []*domain.Classification, items []*domain.Counseling) *domain.CounselingSession {
+	session := &domain.CounselingSession{
+		AssessmentID: assessmentID,
+		Cards:        []*domain.CounselingCard{},
+		Notes:        []*domain.Counseling{},
+	}
+
+	byClassification := make(map[uuid.UUID][]*domain.Counseling)
+	for _, item := range items {
+		if item.ClassificationID == nil || !item.IsStructured() {
+			session.Notes = append(session.Notes, item)
+			continue
+		}
+		byClassification[*item.ClassificationID] = append(byClassification[*item.ClassificationID], item)
+	}
+
+	for _, class := range classifications {
+		card := &domain.CounselingCard{
+			ClassificationID: class.ID,
+			Disease:          class.Disease,
+			Color:            class.Color,
+			Items:            sortCounselingItems(byClassification[class.ID]),
+			Complete:         true,
+		}
+		for _, item := range card.Items {
+			session.Total++
+			if item.Understood() {
+				session.Understood++
+			} else {
+				card.Complete = false
+			}
+		}
+		session.Cards = append(session.Cards, card)
+	}
+
+	session.Complete = session.Total > 0 && session.Understood == session.Total
+	return session
+}
+
+// sortCounselingItems orders items the way the clinician goes through them.
+func sortCounselingItems(items []*domain.Counseling) []*domain.Counseling {
+	sorted := make([]*domain.Counseling, 0, len(items))
+	for _, adviceType := range counseling.Types {
+		for _, item := range items {
+			if item.AdviceType == adviceType {
+				sorted = append(sorted, item)
+			}
+		}
+	}
+	return sorted
+}
+
+func (uc *CounselingUsecase) ConfirmItem(ctx context.Context, assessmentID, itemID uuid.UUID, req *domain.ConfirmCounselingRequest, medicalProfessionalID uuid.UUID) (*domain.Counseling, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID); err != nil {
+		return nil, err
+	}
+
+	item, err := uc.counselingRepo.GetByID(ctx, itemID, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsStructured() {
+		return nil, domain.ErrCounselingNotConfirmable
+	}
+
+	understood := req.Understood
+	now := time.Now()
+	item.UnderstoodByCaregiver = &understood
+	item.UnderstoodAt = &now
+	item.CounseledBy = &medicalProfessionalID
+
+	if err := uc.counselingRepo.ConfirmUnderstanding(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (uc *CounselingUsecase) RecordQuestion(ctx context.Context, assessmentID, itemID uuid.UUID, req *domain.CaregiverQuestionRequest, medicalProfessionalID uuid.UUID) (*domain.Counseling, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID); err != nil {
+		return nil, err
+	}
+
+	// Questions are stored one per line.
+	question := strings.Join(strings.Fields(req.Question), " ")
+	if err := uc.counselingRepo.AddQuestion(ctx, itemID, assessmentID, question); err != nil {
+		return nil, err
+	}
+
+	return uc.counselingRepo.GetByID(ctx, itemID, assessmentID)
+}
+
+func (uc *CounselingUsecase) CompletionReport(ctx context.Context, from, to time.Time, medicalProfessionalID *uuid.UUID) ([]*domain.CounselingCompletion, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.counselingRepo.CompletionReport(ctx, from, to, medicalProfessionalID)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeCounselingAssessmentRepo struct {
+	domain.AssessmentRepository
+	assessment *domain.Assessment
+}
+
+func (f *fakeCounselingAssessmentRepo) GetByID(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID) (*domain.Assessment, error) {
+	if f.assessment == nil || f.assessment.ID != id {
+		return nil, domain.ErrAssessmentNotFound
+	}
+	return f.assessment, nil
+}
+
+type fakeCounselingClassificationRepo struct {
+	domain.ClassificationRepository
+	classifications []*domain.Classification
+}
+
+func (f *fakeCounselingClassificationRepo) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Classification, error) {
+	return f.classifications, nil
+}
+
+type fakeCounselingRepo struct {
+	domain.CounselingRepository
+	items []*domain.Counseling
+}
+
+func (f *fakeCounselingRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Counseling, error) {
+	return append([]*domain.Counseling(nil), f.items...), nil
+}
+
+func (f *fakeCounselingRepo) GetByID(ctx context.Context, id, assessmentID uuid.UUID) (*domain.Counseling, error) {
+	for _, item := range f.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, domain.ErrCounselingNotFound
+}
+
+func (f *fakeCounselingRepo) ConfirmUnderstanding(ctx context.Context, c *domain.Counseling) error {
+	return nil
+}
+
+// The fake repository has no Create, so the test also fails if reading the
+// session writes anything.
+func TestCounselingSession(t *testing.T) {
+	mpID := uuid.New()
+	assessment := &domain.Assessment{ID: uuid.New(), AssessmentType: domain.TypeChild}
+	diarrhoea := &domain.Classification{ID: uuid.New(), Disease: "SOME DEHYDRATION", Color: "yellow"}
+	item := func(adviceType string) *domain.Counseling {
+		return &domain.Counseling{ID: uuid.New(), AssessmentID: assessment.ID, ClassificationID: &diarrhoea.ID, AdviceType: adviceType, Language: "en"}
+	}
+	counselingRepo := &fakeCounselingRepo{items: []*domain.Counseling{
+		item(domain.AdviceMotherAdvice),
+		item(domain.AdviceWhenToReturn),
+		item(domain.AdviceFeeding),
+		item(domain.AdviceHomeFluids),
+	}}
+
+	uc := NewCounselingUsecase(
+		&fakeCounselingAssessmentRepo{assessment: assessment},
+		&fakeCounselingClassificationRepo{classifications: []*domain.Classification{diarrhoea}},
+		counselingRepo,
+		time.Second,
+	)
+
+	session, err := uc.GetSession(context.Background(), assessment.ID, mpID)
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if len(session.Cards) != 1 || len(session.Cards[0].Items) != 3 {
+		t.Fatalf("expected one card with three items, got %+v", session.Cards)
+	}
+	if got := session.Cards[0].Items[0].AdviceType; got != domain.AdviceHomeFluids {
+		t.Errorf("first item = %q, want %q", got, domain.AdviceHomeFluids)
+	}
+	if len(session.Notes) != 1 || session.Complete {
+		t.Errorf("expected the mother advice as a note and an incomplete session, got %+v", session)
+	}
+
+	for _, item := range session.Cards[0].Items {
+		if _, err := uc.ConfirmItem(context.Background(), assessment.ID, item.ID, &domain.ConfirmCounselingRequest{Understood: domain.UnderstoodYes}, mpID); err != nil {
+			t.Fatalf("ConfirmItem: %v", err)
+		}
+	}
+	session, _ = uc.GetSession(context.Background(), assessment.ID, mpID)
+	if !session.Complete || session.Understood != 3 {
+		t.Errorf("expected a complete session, got %d/%d understood", session.Understood, session.Total)
+	}
+
+	_, err = uc.ConfirmItem(context.Background(), assessment.ID, counselingRepo.items[0].ID, &domain.ConfirmCounselingRequest{Understood: domain.UnderstoodYes}, mpID)
+	if !errors.Is(err, domain.ErrCounselingNotConfirmable) {
+		t.Errorf("confirming mother advice: got %v, want ErrCounselingNotConfirmable", err)
+	}
+}
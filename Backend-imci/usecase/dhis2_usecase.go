@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/dhis2"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/google/uuid"
+)
+
+type DHIS2Usecase struct {
+	repo           domain.DHIS2Repository
+	client         domain.DHIS2Client
+	mapping        *dhis2.Mapping
+	pushDay        int
+	contextTimeout time.Duration
+}
+
+// NewDHIS2Usecase builds reports from mapping and pushes them with client.
+// Without a mapping nothing can be reported; without a client reports can
+// be previewed but not pushed. pushDay is the day of the month, in the
+// mapping's calendar, from which PushDue sends the previous month, 0 to
+// never push automatically.
+func NewDHIS2Usecase(repo domain.DHIS2Repository, client domain.DHIS2Client, mapping *dhis2.Mapping, pushDay int, timeout time.Duration) domain.DHIS2Usecase {
+	return &DHIS2Usecase{
+		repo:           repo,
+		client:         client,
+		mapping:        mapping,
+		pushDay:        pushDay,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *DHIS2Usecase) BuildReport(ctx context.Context, period string) (*domain.DHIS2Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.buildReport(ctx, period)
+}
+
+func (uc *DHIS2Usecase) buildReport(ctx context.Context, period string) (*domain.DHIS2Report, error) {
+	if uc.mapping == nil {
+		return nil, domain.ErrDHIS2NotConfigured
+	}
+
+	from, to, err := uc.mapping.Calendar.ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.repo.CountClassifications(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	set, unmapped := buildDataValueSet(uc.mapping, period, counts)
+	return &domain.DHIS2Report{
+		Period:       period,
+		From:         from,
+		To:           to,
+		Counts:       counts,
+		DataValueSet: set,
+		Unmapped:     unmapped,
+	}, nil
+}
+
+// buildDataValueSet maps counts onto data values, summing counts that land
+// on the same data element, disaggregation and organisation unit.
+func buildDataValueSet(mapping *dhis2.Mapping, period string, counts []*domain.IndicatorCount) (*domain.DHIS2DataValueSet, []*domain.UnmappedIndicator) {
+	type key struct{ dataElement, orgUnit, combo string }
+	totals := make(map[key]int)
+	unmapped := []*domain.UnmappedIndicator{}
+
+	for _, c := range counts {
+		dataElement, ok := mapping.DataElement(c.Classification)
+		if !ok {
+			unmapped = append(unmapped, &domain.UnmappedIndicator{IndicatorCount: *c, Reason: "no data element for classification"})
+			continue
+		}
+		combo, ok := mapping.CategoryOptionCombo(c.AgeGroup, c.Sex)
+		if !ok {
+			unmapped = append(unmapped, &domain.UnmappedIndicator{IndicatorCount: *c, Reason: "no category option combo for age group and sex"})
+			continue
+		}
+		orgUnit, ok := mapping.OrgUnit(c.Facility)
+		if !ok {
+			unmapped = append(unmapped, &domain.UnmappedIndicator{IndicatorCount: *c, Reason: "no organisation unit for facility"})
+			continue
+		}
+		totals[key{dataElement, orgUnit, combo}] += c.Count
+	}
+
+	set := &domain.DHIS2DataValueSet{DataSet: mapping.DataSet, DataValues: []domain.DHIS2DataValue{}}
+	for k, total := range totals {
+		set.DataValues = append(set.DataValues, domain.DHIS2DataValue{
+			DataElement:          k.dataElement,
+			Period:               period,
+			OrgUnit:              k.orgUnit,
+			CategoryOptionCombo:  k.combo,
+			AttributeOptionCombo: mapping.AttributeOptionCombo,
+			Value:                strconv.Itoa(total),
+		})
+	}
+	sort.Slice(set.DataValues, func(i, j int) bool {
+		a, b := set.DataValues[i], set.DataValues[j]
+		if a.OrgUnit != b.OrgUnit {
+			return a.OrgUnit < b.OrgUnit
+		}
+		if a.DataElement != b.DataElement {
+			return a.DataElement < b.DataElement
+		}
+		return a.CategoryOptionCombo < b.CategoryOptionCombo
+	})
+
+	return set, unmapped
+}
+
+func (uc *DHIS2Usecase) Push(ctx context.Context, period string, triggeredBy *uuid.UUID) (*domain.DHIS2Export, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.push(ctx, period, triggeredBy)
+}
+
+func (uc *DHIS2Usecase) push(ctx context.Context, period string, triggeredBy *uuid.UUID) (*domain.DHIS2Export, error) {
+	if uc.client == nil {
+		return nil, domain.ErrDHIS2NotConfigured
+	}
+
+	report, err := uc.buildReport(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.DHIS2Export{
+		Period:      period,
+		DataValues:  len(report.DataValueSet.DataValues),
+		TriggeredBy: triggeredBy,
+		CreatedAt:   time.Now(),
+	}
+
+	summary, pushErr := uc.client.PushDataValueSet(ctx, report.DataValueSet)
+	switch {
+	case pushErr != nil:
+		export.Status = domain.DHIS2ExportFailed
+		export.Error = pushErr.Error()
+	case summary.Status == "WARNING" || len(summary.Conflicts) > 0:
+		export.Status = domain.DHIS2ExportWarning
+	default:
+		export.Status = domain.DHIS2ExportSuccess
+	}
+	if summary != nil {
+		export.Imported, export.Updated = summary.Imported, summary.Updated
+		export.Ignored, export.Deleted = summary.Ignored, summary.Deleted
+	}
+
+	if err := uc.repo.RecordExport(ctx, export); err != nil {
+		logger.Error("failed to record DHIS2 export", "period", period, "error", err)
+	}
+
+	if pushErr != nil {
+		return export, pushErr
+	}
+	return export, nil
+}
+
+func (uc *DHIS2Usecase) PushDue(ctx context.Context, now time.Time) (*domain.DHIS2Export, error) {
+	if uc.pushDay <= 0 || uc.client == nil || uc.mapping == nil || uc.mapping.Calendar.Day(now) < uc.pushDay {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	period := uc.mapping.Calendar.PreviousMonth(now)
+	done, err := uc.repo.HasSuccessfulExport(ctx, period)
+	if err != nil || done {
+		return nil, err
+	}
+
+	return uc.push(ctx, period, nil)
+}
+
+func (uc *DHIS2Usecase) ListExports(ctx context.Context, limit int) ([]*domain.DHIS2Export, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return uc.repo.ListExports(ctx, limit)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/dhis2"
+)
+
+type fakeDHIS2Repo struct {
+	domain.DHIS2Repository
+	counts   []*domain.IndicatorCount
+	from, to time.Time
+	exports  []*domain.DHIS2Export
+}
+
+func (f *fakeDHIS2Repo) CountClassifications(ctx context.Context, from, to time.Time) ([]*domain.IndicatorCount, error) {
+	f.from, f.to = from, to
+	return f.counts, nil
+}
+
+func (f *fakeDHIS2Repo) RecordExport(ctx context.Context, export *domain.DHIS2Export) error {
+	f.exports = append(f.exports, export)
+	return nil
+}
+
+func (f *fakeDHIS2Repo) HasSuccessfulExport(ctx context.Context, period string) (bool, error) {
+	for _, e := range f.exports {
+		if e.Period == period && e.Status != domain.DHIS2ExportFailed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeDHIS2Client struct {
+	pushed []*domain.DHIS2DataValueSet
+}
+
+func (f *fakeDHIS2Client) PushDataValueSet(ctx context.Context, set *domain.DHIS2DataValueSet) (*domain.DHIS2ImportSummary, error) {
+	f.pushed = append(f.pushed, set)
+	return &domain.DHIS2ImportSummary{Status: "SUCCESS", Imported: len(set.DataValues)}, nil
+}
+
+func testDHIS2Mapping(t *testing.T) *dhis2.Mapping {
+	m, err := dhis2.ParseMapping([]byte(`{
+		"dataSet": "ds1",
+		"dataElements": {"COMPLICATED SEVERE ACUTE MALNUTRITION": "deSAM", "UNCOMPLICATED SEVERE ACUTE MALNUTRITION": "deSAM", "PNEUMONIA": "dePNA"},
+		"categoryOptionCombos": {"child.male": "cocM", "child.female": "cocF"},
+		"orgUnits": {"Adama HC": "ouAdama"}
+	}`))
+	if err != nil {
+		t.Fatalf("mapping: %v", err)
+	}
+	return m
+}
+
+func TestBuildDHIS2Report(t *testing.T) {
+	repo := &fakeDHIS2Repo{counts: []*domain.IndicatorCount{
+		{Classification: "COMPLICATED SEVERE ACUTE MALNUTRITION", AgeGroup: "child", Sex: "male", Facility: "Adama HC", Count: 2},
+		{Classification: "UNCOMPLICATED SEVERE ACUTE MALNUTRITION", AgeGroup: "child", Sex: "male", Facility: "Adama HC", Count: 3},
+		{Classification: "PNEUMONIA", AgeGroup: "child", Sex: "female", Facility: "Adama HC", Count: 4},
+		{Classification: "PNEUMONIA", AgeGroup: "young_infant", Sex: "female", Facility: "Adama HC", Count: 1},
+		{Classification: "MALARIA", AgeGroup: "child", Sex: "male", Facility: "Adama HC", Count: 7},
+		{Classification: "PNEUMONIA", AgeGroup: "child", Sex: "male", Facility: "Unknown HC", Count: 1},
+	}}
+	uc := NewDHIS2Usecase(repo, nil, testDHIS2Mapping(t), 0, time.Second)
+
+	report, err := uc.BuildReport(context.Background(), "202603")
+	if err != nil {
+		t.Fatalf("BuildReport: %v", err)
+	}
+	if got := repo.from.Format("2006-01-02") + "/" + repo.to.Format("2006-01-02"); got != "2026-03-01/2026-04-01" {
+		t.Errorf("aggregated over %s", got)
+	}
+
+	values := report.DataValueSet.DataValues
+	if len(values) != 2 || report.DataValueSet.DataSet != "ds1" {
+		t.Fatalf("expected two data values for ds1, got %+v", report.DataValueSet)
+	}
+	if v := values[0]; v.DataElement != "dePNA" || v.CategoryOptionCombo != "cocF" || v.Value != "4" || v.Period != "202603" {
+		t.Errorf("unexpected pneumonia value %+v", v)
+	}
+	if v := values[1]; v.DataElement != "deSAM" || v.CategoryOptionCombo != "cocM" || v.OrgUnit != "ouAdama" || v.Value != "5" {
+		t.Errorf("SAM counts should be summed, got %+v", v)
+	}
+	if len(report.Unmapped) != 3 {
+		t.Errorf("expected 3 unmapped counts, got %d", len(report.Unmapped))
+	}
+}
+
+func TestDHIS2PushDue(t *testing.T) {
+	repo := &fakeDHIS2Repo{counts: []*domain.IndicatorCount{
+		{Classification: "PNEUMONIA", AgeGroup: "child", Sex: "male", Facility: "Adama HC", Count: 4},
+	}}
+	client := &fakeDHIS2Client{}
+	uc := NewDHIS2Usecase(repo, client, testDHIS2Mapping(t), 5, time.Second)
+
+	if export, err := uc.PushDue(context.Background(), time.Date(2026, 4, 4, 9, 0, 0, 0, time.UTC)); export != nil || err != nil {
+		t.Fatalf("nothing should be pushed before the push day, got %+v, %v", export, err)
+	}
+
+	export, err := uc.PushDue(context.Background(), time.Date(2026, 4, 5, 9, 0, 0, 0, time.UTC))
+	if err != nil || export == nil {
+		t.Fatalf("expected a push, got %+v, %v", export, err)
+	}
+	if export.Period != "202603" || export.Status != domain.DHIS2ExportSuccess || export.Imported != 1 {
+		t.Errorf("unexpected export %+v", export)
+	}
+
+	if export, _ := uc.PushDue(context.Background(), time.Date(2026, 4, 6, 9, 0, 0, 0, time.UTC)); export != nil {
+		t.Errorf("the period should only be pushed once, got %+v", export)
+	}
+	if len(client.pushed) != 1 {
+		t.Errorf("expected one push, got %d", len(client.pushed))
+	}
+}
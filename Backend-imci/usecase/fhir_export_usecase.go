@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/fhir"
+	"github.com/google/uuid"
+)
+
+type FHIRExportUsecase struct {
+	assessmentRepo     domain.AssessmentRepository
+	patientRepo        domain.PatientRepository
+	classificationRepo domain.ClassificationRepository
+	treatmentPlanRepo  domain.TreatmentPlanRepository
+	contextTimeout     time.Duration
+}
+
+func NewFHIRExportUsecase(
+	assessmentRepo domain.AssessmentRepository,
+	patientRepo domain.PatientRepository,
+	classificationRepo domain.ClassificationRepository,
+	treatmentPlanRepo domain.TreatmentPlanRepository,
+	timeout time.Duration,
+) domain.FHIRExportUsecase {
+	return &FHIRExportUsecase{
+		assessmentRepo:     assessmentRepo,
+		patientRepo:        patientRepo,
+		classificationRepo: classificationRepo,
+		treatmentPlanRepo:  treatmentPlanRepo,
+		contextTimeout:     timeout,
+	}
+}
+
+func (uc *FHIRExportUsecase) PatientEverything(ctx context.Context, patientID uuid.UUID, medicalProfessionalID uuid.UUID, baseURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	patient, err := uc.patientRepo.GetByID(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	assessments, err := uc.assessmentRepo.GetByPatientID(ctx, patientID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+	// Only the clinician's own assessments are exported; without any the
+	// patient is not theirs to export.
+	if len(assessments) == 0 {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	return uc.export(ctx, baseURL, patient, assessments)
+}
+
+func (uc *FHIRExportUsecase) EncounterEverything(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID, baseURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	patient, err := uc.patientRepo.GetByID(ctx, assessment.PatientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.export(ctx, baseURL, patient, []*domain.Assessment{assessment})
+}
+
+func (uc *FHIRExportUsecase) export(ctx context.Context, baseURL string, patient *domain.Patient, assessments []*domain.Assessment) ([]byte, error) {
+	records := make([]*assessmentRecord, 0, len(assessments))
+	for _, a := range assessments {
+		classifications, err := uc.classificationRepo.ListByAssessmentID(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		plans, err := uc.treatmentPlanRepo.GetByAssessmentID(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &assessmentRecord{assessment: a, classifications: classifications, plans: plans})
+	}
+
+	bundle := buildBundle(baseURL, patient, records, time.Now())
+	// The exchange rejects the whole upload on one bad reference, so catch
+	// it here where the cause is visible.
+	if err := fhir.Validate(bundle); err != nil {
+		return nil, fmt.Errorf("failed to build FHIR bundle: %w", err)
+	}
+
+	return json.Marshal(bundle)
+}
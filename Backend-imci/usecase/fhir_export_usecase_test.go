@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeFHIRPatientRepo struct {
+	domain.PatientRepository
+	patient *domain.Patient
+	err     error
+}
+
+func (f *fakeFHIRPatientRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Patient, error) {
+	return f.patient, f.err
+}
+
+type fakeFHIRAssessmentRepo struct {
+	domain.AssessmentRepository
+	assessments []*domain.Assessment
+}
+
+func (f *fakeFHIRAssessmentRepo) GetByPatientID(ctx context.Context, patientID, medicalProfessionalID uuid.UUID) ([]*domain.Assessment, error) {
+	return f.assessments, nil
+}
+
+func TestPatientEverythingKeepsLookupErrors(t *testing.T) {
+	dbErr := errors.New("failed to get patient: connection refused")
+
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{"unknown patient", domain.ErrPatientNotFound, domain.ErrPatientNotFound},
+		{"database failure", dbErr, dbErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewFHIRExportUsecase(nil, &fakeFHIRPatientRepo{err: tt.repoErr}, nil, nil, time.Second)
+			_, err := uc.PatientEverything(context.Background(), uuid.New(), uuid.New(), "https://example.org/fhir")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPatientEverythingRequiresOwnAssessments(t *testing.T) {
+	patients := &fakeFHIRPatientRepo{patient: &domain.Patient{ID: uuid.New(), Name: "Abebe Kebede"}}
+	uc := NewFHIRExportUsecase(&fakeFHIRAssessmentRepo{}, patients, nil, nil, time.Second)
+
+	_, err := uc.PatientEverything(context.Background(), patients.patient.ID, uuid.New(), "https://example.org/fhir")
+	if !errors.Is(err, domain.ErrPatientNotFound) {
+		t.Errorf("expected %v for a patient without the clinician's assessments, got %v", domain.ErrPatientNotFound, err)
+	}
+}
@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/fhir"
+	"github.com/google/uuid"
+)
+
+// IMCI code systems and extensions. They are URNs until the exchange
+// publishes an implementation guide with canonical URLs.
+const (
+	imciPatientSystem        = "urn:digital-imci:patient"
+	imciAssessmentTypeSystem = "urn:digital-imci:assessment-type"
+	imciClassificationSystem = "urn:digital-imci:classification"
+	imciColorSystem          = "urn:digital-imci:classification-color"
+
+	extAssessmentStatus  = "urn:digital-imci:fhir:assessment-status"
+	extUrgentReferral    = "urn:digital-imci:fhir:urgent-referral"
+	extCriticalIllness   = "urn:digital-imci:fhir:critical-illness"
+	extTreatmentPriority = "urn:digital-imci:fhir:treatment-priority"
+	extRuleVersion       = "urn:digital-imci:fhir:rule-version"
+)
+
+// assessmentRecord is an assessment with what was decided during it.
+type assessmentRecord struct {
+	assessment      *domain.Assessment
+	classifications []*domain.Classification
+	plans           []*domain.TreatmentPlan
+}
+
+type vitalSign struct {
+	loinc   string
+	display string
+	unit    string
+	ucum    string
+	get     func(a *domain.Assessment) *float64
+	// set reads the value back; only the round-trip tests use it.
+	set func(a *domain.Assessment, v float64)
+}
+
+var vitalSigns = []vitalSign{
+	{
+		loinc: "29463-7", display: "Body weight", unit: "kg", ucum: "kg",
+		get: func(a *domain.Assessment) *float64 {
+			if a.WeightKg <= 0 {
+				return nil
+			}
+			return &a.WeightKg
+		},
+		set: func(a *domain.Assessment, v float64) { a.WeightKg = v },
+	},
+	{
+		loinc: "8310-5", display: "Body temperature", unit: "C", ucum: "Cel",
+		get: func(a *domain.Assessment) *float64 { return a.Temperature },
+		set: func(a *domain.Assessment, v float64) { a.Temperature = &v },
+	},
+	{
+		loinc: "9279-1", display: "Respiratory rate", unit: "breaths/min", ucum: "/min",
+		get: func(a *domain.Assessment) *float64 { return intToFloat(a.RespiratoryRate) },
+		set: func(a *domain.Assessment, v float64) { a.RespiratoryRate = floatToInt(v) },
+	},
+	{
+		loinc: "59408-5", display: "Oxygen saturation by pulse oximetry", unit: "%", ucum: "%",
+		get: func(a *domain.Assessment) *float64 { return intToFloat(a.OxygenSaturation) },
+		set: func(a *domain.Assessment, v float64) { a.OxygenSaturation = floatToInt(v) },
+	},
+	{
+		loinc: "56072-2", display: "Mid upper arm circumference", unit: "cm", ucum: "cm",
+		get: func(a *domain.Assessment) *float64 { return a.MUAC },
+		set: func(a *domain.Assessment, v float64) { a.MUAC = &v },
+	},
+}
+
+// SNOMED CT severities from the FHIR condition-severity value set.
+var colorSeverity = map[string]fhir.Coding{
+	"pink":   {System: fhir.SNOMED, Code: "24484000", Display: "Severe"},
+	"yellow": {System: fhir.SNOMED, Code: "6736007", Display: "Moderate"},
+	"green":  {System: fhir.SNOMED, Code: "255604002", Display: "Mild"},
+}
+
+var encounterStatus = map[domain.AssessmentStatus]string{
+	domain.StatusDraft:      "planned",
+	domain.StatusInProgress: "in-progress",
+	domain.StatusClassified: "in-progress",
+	domain.StatusCompleted:  "finished",
+	domain.StatusCancelled:  "cancelled",
+}
+
+var durationPattern = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s+(hours?|days?|weeks?|months?)$`)
+
+var durationUnits = map[string]string{"hour": "h", "day": "d", "week": "wk", "month": "mo"}
+
+// buildBundle maps a patient and their assessments to FHIR resources.
+func buildBundle(base string, patient *domain.Patient, records []*assessmentRecord, now time.Time) *fhir.Bundle {
+	bundle := fhir.NewSearchset(uuid.New().String(), now.UTC().Format(time.RFC3339))
+	bundle.Add(base, patientToFHIR(patient))
+
+	for _, r := range records {
+		bundle.Add(base, encounterToFHIR(r.assessment, r.classifications))
+		for _, o := range observationsToFHIR(r.assessment) {
+			bundle.Add(base, o)
+		}
+		for _, c := range r.classifications {
+			bundle.Add(base, conditionToFHIR(c, r.assessment))
+		}
+		for _, p := range r.plans {
+			bundle.Add(base, medicationRequestToFHIR(p, r.assessment))
+		}
+	}
+
+	return bundle
+}
+
+func patientToFHIR(p *domain.Patient) *fhir.Patient {
+	r := &fhir.Patient{
+		ResourceType: "Patient",
+		ID:           p.ID.String(),
+		Identifier:   []fhir.Identifier{{System: imciPatientSystem, Value: p.ID.String()}},
+		Gender:       string(p.Gender),
+	}
+	if !p.UpdatedAt.IsZero() {
+		r.Meta = &fhir.Meta{LastUpdated: formatInstant(p.UpdatedAt)}
+	}
+	if p.Name != "" {
+		r.Name = []fhir.HumanName{{Text: p.Name}}
+	}
+	if !p.DateOfBirth.IsZero() {
+		r.BirthDate = p.DateOfBirth.Format(fhir.DateLayout)
+	}
+	return r
+}
+
+func encounterToFHIR(a *domain.Assessment, classifications []*domain.Classification) *fhir.Encounter {
+	status, ok := encounterStatus[a.Status]
+	if !ok {
+		status = "unknown"
+	}
+
+	r := &fhir.Encounter{
+		ResourceType: "Encounter",
+		ID:           a.ID.String(),
+		Status:       status,
+		Class:        fhir.Coding{System: fhir.ActCode, Code: "AMB", Display: "ambulatory"},
+		Subject:      &fhir.Reference{Reference: "Patient/" + a.PatientID.String()},
+		Period:       &fhir.Period{Start: formatInstant(a.StartTime)},
+	}
+	if a.Status != "" {
+		r.Extension = []fhir.Extension{{URL: extAssessmentStatus, ValueCode: string(a.Status)}}
+	}
+	if !a.UpdatedAt.IsZero() {
+		r.Meta = &fhir.Meta{LastUpdated: formatInstant(a.UpdatedAt)}
+	}
+	if a.AssessmentType != "" {
+		r.Type = []fhir.CodeableConcept{{
+			Coding: []fhir.Coding{{System: imciAssessmentTypeSystem, Code: string(a.AssessmentType)}},
+		}}
+	}
+	if a.MedicalProfessionalID != uuid.Nil {
+		r.Participant = []fhir.EncounterParticipant{{
+			Individual: &fhir.Reference{Reference: "Practitioner/" + a.MedicalProfessionalID.String()},
+		}}
+	}
+	if a.EndTime != nil {
+		r.Period.End = formatInstant(*a.EndTime)
+	}
+	// Classifications come most urgent first, which is the diagnosis rank.
+	for i, c := range classifications {
+		r.Diagnosis = append(r.Diagnosis, fhir.EncounterDiagnosis{
+			Condition: fhir.Reference{Reference: "Condition/" + c.ID.String()},
+			Rank:      i + 1,
+		})
+	}
+	return r
+}
+
+func observationsToFHIR(a *domain.Assessment) []fhir.Resource {
+	var out []fhir.Resource
+	for _, v := range vitalSigns {
+		value := v.get(a)
+		if value == nil {
+			continue
+		}
+		out = append(out, &fhir.Observation{
+			ResourceType: "Observation",
+			// Derived from the assessment so re-exports keep the same id.
+			ID:     uuid.NewSHA1(a.ID, []byte(v.loinc)).String(),
+			Status: "final",
+			Category: []fhir.CodeableConcept{{
+				Coding: []fhir.Coding{{System: fhir.ObsCategory, Code: "vital-signs", Display: "Vital Signs"}},
+			}},
+			Code: fhir.CodeableConcept{
+				Coding: []fhir.Coding{{System: fhir.LOINC, Code: v.loinc, Display: v.display}},
+				Text:   v.display,
+			},
+			Subject:           &fhir.Reference{Reference: "Patient/" + a.PatientID.String()},
+			Encounter:         &fhir.Reference{Reference: "Encounter/" + a.ID.String()},
+			EffectiveDateTime: formatInstant(a.StartTime),
+			ValueQuantity:     &fhir.Quantity{Value: *value, Unit: v.unit, System: fhir.UCUM, Code: v.ucum},
+		})
+	}
+	return out
+}
+
+func conditionToFHIR(c *domain.Classification, a *domain.Assessment) *fhir.Condition {
+	urgent, critical, priority := c.RequiresUrgentReferral, c.IsCriticalIllness, c.TreatmentPriority
+
+	r := &fhir.Condition{
+		ResourceType: "Condition",
+		ID:           c.ID.String(),
+		Extension: []fhir.Extension{
+			{URL: extUrgentReferral, ValueBoolean: &urgent},
+			{URL: extCriticalIllness, ValueBoolean: &critical},
+			{URL: extTreatmentPriority, ValueInteger: &priority},
+		},
+		ClinicalStatus: &fhir.CodeableConcept{
+			Coding: []fhir.Coding{{System: fhir.CondClinical, Code: "active"}},
+		},
+		Category: []fhir.CodeableConcept{{
+			Coding: []fhir.Coding{{System: fhir.CondCategory, Code: "encounter-diagnosis", Display: "Encounter Diagnosis"}},
+		}},
+		Code: &fhir.CodeableConcept{
+			Coding: []fhir.Coding{{System: imciClassificationSystem, Code: c.Disease}},
+			Text:   c.Disease,
+		},
+		Subject:   fhir.Reference{Reference: "Patient/" + a.PatientID.String()},
+		Encounter: &fhir.Reference{Reference: "Encounter/" + a.ID.String()},
+	}
+	if c.RuleVersion != "" {
+		r.Extension = append(r.Extension, fhir.Extension{URL: extRuleVersion, ValueString: c.RuleVersion})
+	}
+	if c.Color != "" {
+		severity := &fhir.CodeableConcept{Coding: []fhir.Coding{{System: imciColorSystem, Code: c.Color}}}
+		if coding, ok := colorSeverity[strings.ToLower(c.Color)]; ok {
+			severity.Coding = append([]fhir.Coding{coding}, severity.Coding...)
+		}
+		r.Severity = severity
+	}
+	if !c.CreatedAt.IsZero() {
+		r.RecordedDate = formatInstant(c.CreatedAt)
+	}
+	if c.Details != "" {
+		r.Note = []fhir.Annotation{{Text: c.Details}}
+	}
+	return r
+}
+
+func medicationRequestToFHIR(p *domain.TreatmentPlan, a *domain.Assessment) *fhir.MedicationRequest {
+	priority := "routine"
+	if p.IsPreReferral {
+		// Pre-referral doses are given before the child leaves.
+		priority = "stat"
+	}
+
+	dosage := fhir.Dosage{Text: p.Dosage, PatientInstruction: p.Instructions}
+	if p.Frequency != "" || p.Duration != "" {
+		dosage.Timing = &fhir.Timing{}
+	}
+	if p.Frequency != "" {
+		dosage.Timing.Code = &fhir.CodeableConcept{Text: p.Frequency}
+	}
+	if p.Duration != "" {
+		if bounds := parseDuration(p.Duration); bounds != nil {
+			dosage.Timing.Repeat = &fhir.TimingRepeat{BoundsDuration: bounds}
+		} else {
+			dosage.AdditionalInstruction = []fhir.CodeableConcept{{Text: p.Duration}}
+		}
+	}
+	if p.AdministrationRoute != "" {
+		dosage.Route = &fhir.CodeableConcept{Text: p.AdministrationRoute}
+	}
+
+	r := &fhir.MedicationRequest{
+		ResourceType:              "MedicationRequest",
+		ID:                        p.ID.String(),
+		Status:                    "active",
+		Intent:                    "order",
+		Priority:                  priority,
+		MedicationCodeableConcept: &fhir.CodeableConcept{Text: p.DrugName},
+		Subject:                   fhir.Reference{Reference: "Patient/" + a.PatientID.String()},
+		Encounter:                 &fhir.Reference{Reference: "Encounter/" + a.ID.String()},
+		DosageInstruction:         []fhir.Dosage{dosage},
+	}
+	if p.ClassificationID != uuid.Nil {
+		r.ReasonReference = []fhir.Reference{{Reference: "Condition/" + p.ClassificationID.String()}}
+	}
+	if !p.CreatedAt.IsZero() {
+		r.AuthoredOn = formatInstant(p.CreatedAt)
+	}
+	return r
+}
+
+// parseDuration turns "5 days" into a UCUM duration, or nil for free text
+// such as "until the fever is gone".
+func parseDuration(text string) *fhir.Quantity {
+	m := durationPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return nil
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return nil
+	}
+	code := durationUnits[strings.TrimSuffix(strings.ToLower(m[2]), "s")]
+	return &fhir.Quantity{Value: value, Unit: m[2], System: fhir.UCUM, Code: code}
+}
+
+func formatInstant(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func intToFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func floatToInt(v float64) *int {
+	i := int(math.Round(v))
+	return &i
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/fhir"
+	"github.com/google/uuid"
+)
+
+func fhirFixture() (*domain.Patient, []*assessmentRecord) {
+	temperature, muac := 38.6, 12.4
+	rr, spo2 := 52, 93
+	start := time.Date(2026, 3, 2, 9, 15, 30, 123456000, time.UTC)
+	end := start.Add(25 * time.Minute)
+
+	patient := &domain.Patient{
+		ID:          uuid.New(),
+		Name:        "Abebe Kebede",
+		DateOfBirth: time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC),
+		Gender:      domain.GenderMale,
+		UpdatedAt:   start.Add(-time.Hour),
+	}
+	assessment := &domain.Assessment{
+		ID:                    uuid.New(),
+		MedicalProfessionalID: uuid.New(),
+		PatientID:             patient.ID,
+		AssessmentType:        domain.TypeChild,
+		Status:                domain.StatusClassified,
+		WeightKg:              9.8,
+		Temperature:           &temperature,
+		MUAC:                  &muac,
+		RespiratoryRate:       &rr,
+		OxygenSaturation:      &spo2,
+		StartTime:             start,
+		EndTime:               &end,
+		UpdatedAt:             end,
+	}
+	pneumonia := &domain.Classification{
+		ID:                uuid.New(),
+		AssessmentID:      assessment.ID,
+		Disease:           "PNEUMONIA",
+		Color:             "yellow",
+		Details:           "Fast breathing",
+		RuleVersion:       "2021",
+		TreatmentPriority: 2,
+		CreatedAt:         start.Add(10 * time.Minute),
+	}
+	severe := &domain.Classification{
+		ID:                     uuid.New(),
+		AssessmentID:           assessment.ID,
+		Disease:                "VERY SEVERE FEBRILE DISEASE",
+		Color:                  "pink",
+		IsCriticalIllness:      true,
+		RequiresUrgentReferral: true,
+		TreatmentPriority:      1,
+		CreatedAt:              start.Add(10 * time.Minute),
+	}
+	plans := []*domain.TreatmentPlan{
+		{
+			ID:                  uuid.New(),
+			AssessmentID:        assessment.ID,
+			ClassificationID:    pneumonia.ID,
+			DrugName:            "Amoxicillin",
+			Dosage:              "250 mg (1 tablet)",
+			Frequency:           "twice daily",
+			Duration:            "5 days",
+			AdministrationRoute: "oral",
+			Instructions:        "Give with food",
+			CreatedAt:           start.Add(12 * time.Minute),
+		},
+		{
+			ID:               uuid.New(),
+			AssessmentID:     assessment.ID,
+			ClassificationID: severe.ID,
+			DrugName:         "Artesunate",
+			Dosage:           "24 mg",
+			Duration:         "single dose before referral",
+			IsPreReferral:    true,
+			CreatedAt:        start.Add(12 * time.Minute),
+		},
+	}
+
+	return patient, []*assessmentRecord{{
+		assessment:      assessment,
+		classifications: []*domain.Classification{severe, pneumonia},
+		plans:           plans,
+	}}
+}
+
+func TestFHIRBundleRoundTrip(t *testing.T) {
+	patient, records := fhirFixture()
+
+	bundle := buildBundle("https://imci.example/api/v1/fhir", patient, records, time.Now())
+	if err := fhir.Validate(bundle); err != nil {
+		t.Fatalf("built bundle is invalid: %v", err)
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded fhir.Bundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := fhir.Validate(&decoded); err != nil {
+		t.Fatalf("decoded bundle is invalid: %v", err)
+	}
+
+	// 1 patient, 1 encounter, 5 vitals, 2 conditions, 2 medication requests.
+	if *decoded.Total != 11 || len(decoded.Entry) != 11 {
+		t.Fatalf("expected 11 entries, got %d", len(decoded.Entry))
+	}
+
+	gotPatient, gotRecords, err := parseBundle(&decoded)
+	if err != nil {
+		t.Fatalf("parseBundle: %v", err)
+	}
+	if !reflect.DeepEqual(gotPatient, patient) {
+		t.Errorf("patient changed in the round trip:\n got %+v\nwant %+v", gotPatient, patient)
+	}
+	if len(gotRecords) != 1 {
+		t.Fatalf("expected one assessment, got %d", len(gotRecords))
+	}
+	got, want := gotRecords[0], records[0]
+	if !reflect.DeepEqual(got.assessment, want.assessment) {
+		t.Errorf("assessment changed in the round trip:\n got %+v\nwant %+v", got.assessment, want.assessment)
+	}
+	if !reflect.DeepEqual(got.classifications, want.classifications) {
+		t.Errorf("classifications changed in the round trip:\n got %+v\nwant %+v", got.classifications, want.classifications)
+	}
+	if !reflect.DeepEqual(got.plans, want.plans) {
+		t.Errorf("treatment plans changed in the round trip:\n got %+v\nwant %+v", got.plans, want.plans)
+	}
+}
+
+func TestFHIRMappingCodes(t *testing.T) {
+	patient, records := fhirFixture()
+	bundle := buildBundle("", patient, records, time.Now())
+
+	encounter := fhir.Resources[*fhir.Encounter](bundle)[0]
+	if encounter.Status != "in-progress" || encounter.Class.Code != "AMB" {
+		t.Errorf("unexpected encounter status/class %q/%q", encounter.Status, encounter.Class.Code)
+	}
+	if len(encounter.Diagnosis) != 2 || encounter.Diagnosis[0].Rank != 1 {
+		t.Errorf("expected ranked diagnoses, got %+v", encounter.Diagnosis)
+	}
+
+	codes := make(map[string]fhir.Quantity)
+	for _, o := range fhir.Resources[*fhir.Observation](bundle) {
+		codes[o.Code.Code(fhir.LOINC)] = *o.ValueQuantity
+	}
+	if q := codes["8310-5"]; q.Value != 38.6 || q.Code != "Cel" {
+		t.Errorf("temperature observation = %+v", q)
+	}
+	if q := codes["59408-5"]; q.Value != 93 || q.Code != "%" {
+		t.Errorf("SpO2 observation = %+v", q)
+	}
+
+	conditions := fhir.Resources[*fhir.Condition](bundle)
+	if code := conditions[0].Severity.Code(fhir.SNOMED); code != "24484000" {
+		t.Errorf("pink classification severity = %q, want severe", code)
+	}
+
+	requests := fhir.Resources[*fhir.MedicationRequest](bundle)
+	bounds := requests[0].DosageInstruction[0].Timing.Repeat.BoundsDuration
+	if bounds == nil || bounds.Value != 5 || bounds.Code != "d" {
+		t.Errorf("duration = %+v, want 5 d", bounds)
+	}
+	if requests[1].Priority != "stat" {
+		t.Errorf("pre-referral dose priority = %q, want stat", requests[1].Priority)
+	}
+
+	for _, e := range bundle.Entry {
+		if !strings.HasPrefix(e.FullURL, e.Resource.FHIRType()+"/") {
+			t.Errorf("fullUrl %q does not match its resource", e.FullURL)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/fhir"
+	"github.com/google/uuid"
+)
+
+// parseBundle maps a bundle built by buildBundle back to the domain. It is
+// the reading side of the exchange mapping; the round-trip tests use it to
+// check that every field written can be read back.
+func parseBundle(bundle *fhir.Bundle) (*domain.Patient, []*assessmentRecord, error) {
+	patients := fhir.Resources[*fhir.Patient](bundle)
+	if len(patients) != 1 {
+		return nil, nil, fmt.Errorf("%w: expected one patient, found %d", fhir.ErrInvalidBundle, len(patients))
+	}
+	patient, err := patientFromFHIR(patients[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []*assessmentRecord
+	byEncounter := make(map[string]*assessmentRecord)
+	for _, e := range fhir.Resources[*fhir.Encounter](bundle) {
+		assessment, err := encounterFromFHIR(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		record := &assessmentRecord{assessment: assessment}
+		records = append(records, record)
+		byEncounter["Encounter/"+e.ID] = record
+	}
+
+	recordOf := func(ref *fhir.Reference) (*assessmentRecord, error) {
+		if ref == nil || byEncounter[ref.Reference] == nil {
+			return nil, fmt.Errorf("%w: resource without a known encounter", fhir.ErrInvalidBundle)
+		}
+		return byEncounter[ref.Reference], nil
+	}
+
+	for _, o := range fhir.Resources[*fhir.Observation](bundle) {
+		record, err := recordOf(o.Encounter)
+		if err != nil {
+			return nil, nil, err
+		}
+		observationFromFHIR(o, record.assessment)
+	}
+	for _, c := range fhir.Resources[*fhir.Condition](bundle) {
+		record, err := recordOf(c.Encounter)
+		if err != nil {
+			return nil, nil, err
+		}
+		classification, err := conditionFromFHIR(c, record.assessment.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		record.classifications = append(record.classifications, classification)
+	}
+	for _, m := range fhir.Resources[*fhir.MedicationRequest](bundle) {
+		record, err := recordOf(m.Encounter)
+		if err != nil {
+			return nil, nil, err
+		}
+		plan, err := medicationRequestFromFHIR(m, record.assessment.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		record.plans = append(record.plans, plan)
+	}
+
+	return patient, records, nil
+}
+
+func patientFromFHIR(r *fhir.Patient) (*domain.Patient, error) {
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patient id %q: %w", r.ID, err)
+	}
+
+	p := &domain.Patient{ID: id, Gender: domain.Gender(r.Gender)}
+	if len(r.Name) > 0 {
+		p.Name = r.Name[0].Text
+	}
+	if r.BirthDate != "" {
+		if p.DateOfBirth, err = time.Parse(fhir.DateLayout, r.BirthDate); err != nil {
+			return nil, fmt.Errorf("invalid birth date %q: %w", r.BirthDate, err)
+		}
+	}
+	if r.Meta != nil {
+		if p.UpdatedAt, err = parseInstant(r.Meta.LastUpdated); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func encounterFromFHIR(r *fhir.Encounter) (*domain.Assessment, error) {
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encounter id %q: %w", r.ID, err)
+	}
+
+	a := &domain.Assessment{ID: id}
+	if a.PatientID, err = referenceID(r.Subject, "Patient"); err != nil {
+		return nil, err
+	}
+	for _, p := range r.Participant {
+		if a.MedicalProfessionalID, err = referenceID(p.Individual, "Practitioner"); err != nil {
+			return nil, err
+		}
+	}
+	for _, ext := range r.Extension {
+		if ext.URL == extAssessmentStatus {
+			a.Status = domain.AssessmentStatus(ext.ValueCode)
+		}
+	}
+	for _, t := range r.Type {
+		if code := t.Code(imciAssessmentTypeSystem); code != "" {
+			a.AssessmentType = domain.AssessmentType(code)
+		}
+	}
+	if r.Period != nil {
+		if a.StartTime, err = parseInstant(r.Period.Start); err != nil {
+			return nil, err
+		}
+		if r.Period.End != "" {
+			end, err := parseInstant(r.Period.End)
+			if err != nil {
+				return nil, err
+			}
+			a.EndTime = &end
+		}
+	}
+	if r.Meta != nil {
+		if a.UpdatedAt, err = parseInstant(r.Meta.LastUpdated); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func observationFromFHIR(r *fhir.Observation, a *domain.Assessment) {
+	if r.ValueQuantity == nil {
+		return
+	}
+	code := r.Code.Code(fhir.LOINC)
+	for _, v := range vitalSigns {
+		if v.loinc == code {
+			v.set(a, r.ValueQuantity.Value)
+		}
+	}
+}
+
+func conditionFromFHIR(r *fhir.Condition, assessmentID uuid.UUID) (*domain.Classification, error) {
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition id %q: %w", r.ID, err)
+	}
+
+	c := &domain.Classification{
+		ID:           id,
+		AssessmentID: assessmentID,
+		Disease:      r.Code.Code(imciClassificationSystem),
+		Color:        r.Severity.Code(imciColorSystem),
+	}
+	if c.Disease == "" && r.Code != nil {
+		c.Disease = r.Code.Text
+	}
+	for _, ext := range r.Extension {
+		switch {
+		case ext.URL == extUrgentReferral && ext.ValueBoolean != nil:
+			c.RequiresUrgentReferral = *ext.ValueBoolean
+		case ext.URL == extCriticalIllness && ext.ValueBoolean != nil:
+			c.IsCriticalIllness = *ext.ValueBoolean
+		case ext.URL == extTreatmentPriority && ext.ValueInteger != nil:
+			c.TreatmentPriority = *ext.ValueInteger
+		case ext.URL == extRuleVersion:
+			c.RuleVersion = ext.ValueString
+		}
+	}
+	if len(r.Note) > 0 {
+		c.Details = r.Note[0].Text
+	}
+	if r.RecordedDate != "" {
+		if c.CreatedAt, err = parseInstant(r.RecordedDate); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func medicationRequestFromFHIR(r *fhir.MedicationRequest, assessmentID uuid.UUID) (*domain.TreatmentPlan, error) {
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid medication request id %q: %w", r.ID, err)
+	}
+
+	p := &domain.TreatmentPlan{
+		ID:            id,
+		AssessmentID:  assessmentID,
+		IsPreReferral: r.Priority == "stat",
+	}
+	if r.MedicationCodeableConcept != nil {
+		p.DrugName = r.MedicationCodeableConcept.Text
+	}
+	for _, reason := range r.ReasonReference {
+		if p.ClassificationID, err = referenceID(&reason, "Condition"); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.DosageInstruction) > 0 {
+		d := r.DosageInstruction[0]
+		p.Dosage, p.Instructions = d.Text, d.PatientInstruction
+		if d.Timing != nil && d.Timing.Code != nil {
+			p.Frequency = d.Timing.Code.Text
+		}
+		if d.Timing != nil && d.Timing.Repeat != nil && d.Timing.Repeat.BoundsDuration != nil {
+			bounds := d.Timing.Repeat.BoundsDuration
+			p.Duration = strconv.FormatFloat(bounds.Value, 'f', -1, 64) + " " + bounds.Unit
+		} else if len(d.AdditionalInstruction) > 0 {
+			p.Duration = d.AdditionalInstruction[0].Text
+		}
+		if d.Route != nil {
+			p.AdministrationRoute = d.Route.Text
+		}
+	}
+	if r.AuthoredOn != "" {
+		if p.CreatedAt, err = parseInstant(r.AuthoredOn); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func referenceID(ref *fhir.Reference, resourceType string) (uuid.UUID, error) {
+	if ref == nil {
+		return uuid.Nil, fmt.Errorf("%w: missing %s reference", fhir.ErrInvalidBundle, resourceType)
+	}
+	id, ok := strings.CutPrefix(ref.Reference, resourceType+"/")
+	if !ok {
+		return uuid.Nil, fmt.Errorf("%w: %q is not a %s reference", fhir.ErrInvalidBundle, ref.Reference, resourceType)
+	}
+	return uuid.Parse(id)
+}
+
+func parseInstant(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid FHIR dateTime %q: %w", s, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/growth"
+	"github.com/google/uuid"
+)
+
+type GrowthUsecase struct {
+	assessmentRepo domain.AssessmentRepository
+	patientRepo    domain.PatientRepository
+	reference      *growth.Reference
+	contextTimeout time.Duration
+}
+
+func NewGrowthUsecase(
+	assessmentRepo domain.AssessmentRepository,
+	patientRepo domain.PatientRepository,
+	reference *growth.Reference,
+	timeout time.Duration,
+) domain.GrowthUsecase {
+	return &GrowthUsecase{
+		assessmentRepo: assessmentRepo,
+		patientRepo:    patientRepo,
+		reference:      reference,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *GrowthUsecase) ComputeForAssessment(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) (*domain.GrowthPoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	patient, err := uc.patientRepo.GetByID(ctx, assessment.PatientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	point, err := uc.growthPoint(patient, assessment)
+	if err != nil {
+		return nil, err
+	}
+
+	return point, nil
+}
+
+func (uc *GrowthUsecase) GetPatientGrowthHistory(ctx context.Context, patientID uuid.UUID, medicalProfessionalID uuid.UUID) (*domain.GrowthHistory, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	patient, err := uc.patientRepo.GetByID(ctx, patientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	assessments, err := uc.assessmentRepo.GetByPatientID(ctx, patientID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &domain.GrowthHistory{
+		PatientID: patient.ID,
+		Gender:    patient.Gender,
+		Points:    []*domain.GrowthPoint{},
+	}
+
+	for _, assessment := range assessments {
+		point, err := uc.growthPoint(patient, assessment)
+		if err != nil {
+			continue
+		}
+		history.Points = append(history.Points, point)
+	}
+
+	sort.Slice(history.Points, func(i, j int) bool {
+		return history.Points[i].MeasuredAt.Before(history.Points[j].MeasuredAt)
+	})
+
+	return history, nil
+}
+
+// growthTreeNodes lists, per assessment tree, the nodes that can be answered
+// from the measurements recorded on the assessment.
+var growthTreeNodes = map[string][]string{
+	"acute_malnutrition":                {"wfl_z_score", "muac_measurement"},
+	"feeding_problem_underweight_check": {"weight_age_assessment"},
+	"replacement_feeding_check":         {"weight_age_assessment_non_bf"},
+}
+
+// ProvideAnswers fills in the z-score and MUAC questions of the nutrition
+// trees so the clinician does not have to read them off growth charts. It
+// only reads; the answers are stored with the tree's clinical findings when
+// the flow is saved.
+func (uc *GrowthUsecase) ProvideAnswers(ctx context.Context, assessment *domain.Assessment, treeID string) (map[string]interface{}, error) {
+	nodes, ok := growthTreeNodes[treeID]
+	if !ok {
+		return nil, nil
+	}
+
+	patient, err := uc.patientRepo.GetByID(ctx, assessment.PatientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	point, err := uc.growthPoint(patient, assessment)
+	if err != nil {
+		if errors.Is(err, domain.ErrGrowthNotComputable) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	answers := make(map[string]interface{})
+	for _, nodeID := range nodes {
+		var value *float64
+		switch nodeID {
+		case "wfl_z_score":
+			value = point.ZScores.WeightForLengthHeight
+		case "muac_measurement":
+			value = assessment.MUAC
+		case "weight_age_assessment", "weight_age_assessment_non_bf":
+			value = point.ZScores.WeightForAge
+		}
+		if value != nil {
+			answers[nodeID] = *value
+		}
+	}
+
+	return answers, nil
+}
+
+func (uc *GrowthUsecase) growthPoint(patient *domain.Patient, assessment *domain.Assessment) (*domain.GrowthPoint, error) {
+	measuredAt := assessment.StartTime
+	if measuredAt.IsZero() {
+		measuredAt = assessment.CreatedAt
+	}
+
+	ageDays := int(measuredAt.Sub(patient.DateOfBirth).Hours() / 24)
+	if ageDays < 0 {
+		return nil, domain.ErrGrowthNotComputable
+	}
+
+	point := &domain.GrowthPoint{
+		AssessmentID:        assessment.ID,
+		MeasuredAt:          measuredAt,
+		AgeDays:             ageDays,
+		AgeMonths:           growth.AgeInMonths(ageDays),
+		WeightKg:            assessment.WeightKg,
+		MUAC:                assessment.MUAC,
+		LengthCm:            assessment.LengthCm,
+		HeadCircumferenceCm: assessment.HeadCircumferenceCm,
+		MeasurementPosition: assessment.MeasurementPosition,
+	}
+
+	sex := growth.Sex(patient.Gender)
+	if sex != growth.Male && sex != growth.Female {
+		return point, nil
+	}
+
+	if z, err := uc.reference.WeightForAgeZ(sex, ageDays, assessment.WeightKg); err == nil {
+		point.ZScores.WeightForAge = &z
+	}
+	if assessment.MUAC != nil {
+		if z, err := uc.reference.MUACForAgeZ(sex, ageDays, *assessment.MUAC); err == nil {
+			point.ZScores.MUACForAge = &z
+		}
+	}
+	if assessment.LengthCm != nil {
+		position := growth.Position(assessment.MeasurementPosition)
+		if z, err := uc.reference.LengthHeightForAgeZ(sex, ageDays, *assessment.LengthCm, position); err == nil {
+			point.ZScores.LengthHeightForAge = &z
+		}
+		if z, indicator, err := uc.reference.WeightForLengthHeightZ(sex, ageDays, assessment.WeightKg, *assessment.LengthCm, position); err == nil {
+			point.ZScores.WeightForLengthHeight = &z
+			point.ZScores.WeightForLengthIndicator = string(indicator)
+		}
+	}
+
+	return point, nil
+}
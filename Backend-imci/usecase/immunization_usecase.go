@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/epi"
+	"github.com/Afomiat/Digital-IMCI/internal/growth"
+	"github.com/google/uuid"
+)
+
+type ImmunizationUsecase struct {
+	immunizationRepo domain.ImmunizationRepository
+	patientRepo      domain.PatientRepository
+	contextTimeout   time.Duration
+}
+
+func NewImmunizationUsecase(
+	immunizationRepo domain.ImmunizationRepository,
+	patientRepo domain.PatientRepository,
+	timeout time.Duration,
+) domain.ImmunizationUsecase {
+	return &ImmunizationUsecase{
+		immunizationRepo: immunizationRepo,
+		patientRepo:      patientRepo,
+		contextTimeout:   timeout,
+	}
+}
+
+func (uc *ImmunizationUsecase) RecordDose(ctx context.Context, patientID uuid.UUID, req *domain.RecordImmunizationRequest, medicalProfessionalID uuid.UUID) (*domain.ImmunizationRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := epi.Lookup(epi.Vaccine(req.Vaccine)); err != nil {
+		return nil, domain.ErrUnknownVaccine
+	}
+
+	patient, err := uc.patientRepo.GetByID(ctx, patientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	if req.DoseDate.Before(truncateToDay(patient.DateOfBirth)) || req.DoseDate.After(time.Now()) {
+		return nil, domain.ErrInvalidImmunizationDate
+	}
+
+	records, err := uc.immunizationRepo.GetByPatientID(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if r.Vaccine == req.Vaccine {
+			return nil, domain.ErrVaccineAlreadyRecorded
+		}
+	}
+
+	record := &domain.ImmunizationRecord{
+		ID:         uuid.New(),
+		PatientID:  patientID,
+		Vaccine:    req.Vaccine,
+		DoseDate:   req.DoseDate,
+		LotNumber:  req.LotNumber,
+		Facility:   req.Facility,
+		RecordedBy: &medicalProfessionalID,
+	}
+
+	if err := uc.immunizationRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (uc *ImmunizationUsecase) GetRecords(ctx context.Context, patientID uuid.UUID) ([]*domain.ImmunizationRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.patientRepo.GetByID(ctx, patientID); err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	return uc.immunizationRepo.GetByPatientID(ctx, patientID)
+}
+
+func (uc *ImmunizationUsecase) DeleteRecord(ctx context.Context, patientID uuid.UUID, recordID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.immunizationRepo.Delete(ctx, recordID, patientID)
+}
+
+func (uc *ImmunizationUsecase) GetSchedule(ctx context.Context, patientID uuid.UUID, asOf time.Time) (*domain.ImmunizationSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.schedule(ctx, patientID, asOf)
+}
+
+func (uc *ImmunizationUsecase) schedule(ctx context.Context, patientID uuid.UUID, asOf time.Time) (*domain.ImmunizationSchedule, error) {
+	patient, err := uc.patientRepo.GetByID(ctx, patientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	records, err := uc.immunizationRepo.GetByPatientID(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	given := make(map[epi.Vaccine]time.Time, len(records))
+	for _, r := range records {
+		given[epi.Vaccine(r.Vaccine)] = r.DoseDate
+	}
+
+	statuses := epi.Evaluate(patient.DateOfBirth, given, asOf)
+
+	return &domain.ImmunizationSchedule{
+		PatientID: patientID,
+		AsOf:      truncateToDay(asOf),
+		DueToday:  vaccinesDue(epi.DueToday(statuses)),
+		Next:      vaccinesDue(epi.NextDue(statuses)),
+		Doses:     vaccinesDue(statuses),
+	}, nil
+}
+
+// ProvideAnswers answers the age and missing-vaccine questions of the
+// immunization tree from the patient's date of birth and vaccine registry.
+// The missing-vaccine question is only answered once a dose is recorded.
+func (uc *ImmunizationUsecase) ProvideAnswers(ctx context.Context, assessment *domain.Assessment, treeID string) (map[string]interface{}, error) {
+	if treeID != "immunization_vitamin_status" {
+		return nil, nil
+	}
+
+	asOf := assessment.StartTime
+	if asOf.IsZero() {
+		asOf = assessment.CreatedAt
+	}
+
+	schedule, err := uc.schedule(ctx, assessment.PatientID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	patient, err := uc.patientRepo.GetByID(ctx, assessment.PatientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	answers := make(map[string]interface{})
+
+	ageDays := int(asOf.Sub(patient.DateOfBirth).Hours() / 24)
+	if ageDays >= 0 {
+		answers["child_age_months"] = math.Floor(growth.AgeInMonths(ageDays))
+	}
+
+	// With nothing in the registry the child's card may just not have been
+	// entered, so the clinician is asked instead.
+	if !anyDoseGiven(schedule.Doses) {
+		return answers, nil
+	}
+	if len(schedule.DueToday) > 0 {
+		answers["immunization_missing"] = "yes"
+	} else {
+		answers["immunization_missing"] = "no"
+	}
+
+	return answers, nil
+}
+
+func anyDoseGiven(doses []*domain.VaccineDue) bool {
+	for _, d := range doses {
+		if d.GivenOn != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func vaccinesDue(statuses []epi.DoseStatus) []*domain.VaccineDue {
+	due := make([]*domain.VaccineDue, 0, len(statuses))
+	for _, s := range statuses {
+		due = append(due, &domain.VaccineDue{
+			Vaccine:             string(s.Vaccine),
+			Antigen:             s.Antigen,
+			Status:              string(s.Status),
+			DueDate:             s.DueDate,
+			GivenOn:             s.GivenOn,
+			Dosage:              s.Dosage,
+			AdministrationRoute: s.Route,
+			Site:                s.Site,
+		})
+	}
+	return due
+}
+
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeImmunizationPatientRepo struct {
+	domain.PatientRepository
+	patient *domain.Patient
+}
+
+func (f *fakeImmunizationPatientRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Patient, error) {
+	return f.patient, nil
+}
+
+type fakeImmunizationRepo struct {
+	domain.ImmunizationRepository
+	records []*domain.ImmunizationRecord
+}
+
+func (f *fakeImmunizationRepo) GetByPatientID(ctx context.Context, patientID uuid.UUID) ([]*domain.ImmunizationRecord, error) {
+	return f.records, nil
+}
+
+func TestProvideAnswersLeavesMissingVaccinesToTheClinicianWithoutRecords(t *testing.T) {
+	born := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	patient := &domain.Patient{ID: uuid.New(), DateOfBirth: born}
+	assessment := &domain.Assessment{PatientID: patient.ID, StartTime: born.AddDate(0, 4, 0)}
+	immunizations := &fakeImmunizationRepo{}
+	uc := NewImmunizationUsecase(immunizations, &fakeImmunizationPatientRepo{patient: patient}, time.Second)
+
+	answers, err := uc.ProvideAnswers(context.Background(), assessment, "immunization_vitamin_status")
+	if err != nil {
+		t.Fatalf("ProvideAnswers: %v", err)
+	}
+	if answers["child_age_months"] != 3.0 {
+		t.Errorf("expected the age to be answered, got %v", answers["child_age_months"])
+	}
+	if _, ok := answers["immunization_missing"]; ok {
+		t.Errorf("expected immunization_missing to be asked when no dose is recorded, got %v", answers["immunization_missing"])
+	}
+
+	immunizations.records = []*domain.ImmunizationRecord{{PatientID: patient.ID, Vaccine: "BCG", DoseDate: born}}
+	answers, err = uc.ProvideAnswers(context.Background(), assessment, "immunization_vitamin_status")
+	if err != nil {
+		t.Fatalf("ProvideAnswers: %v", err)
+	}
+	if answers["immunization_missing"] != "yes" {
+		t.Errorf("expected the overdue doses to be reported, got %v", answers["immunization_missing"])
+	}
+}
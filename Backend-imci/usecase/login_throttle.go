@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
+	"github.com/google/uuid"
+)
+
+const (
+	// loginFailureWindow is how long failed attempts are remembered.
+	loginFailureWindow = 15 * time.Minute
+	// loginFreeAttempts failures are allowed before any delay applies.
+	loginFreeAttempts = 3
+	// loginLockoutAttempts failures lock the phone number out.
+	loginLockoutAttempts = 6
+	loginLockoutDuration = 15 * time.Minute
+	// ipLockoutAttempts failures from one IP, across all phone numbers,
+	// block the IP.
+	ipLockoutAttempts = 30
+
+	defaultLoginAuditLimit = 100
+)
+
+// loginBackoff returns how long a phone number must wait after its n-th
+// consecutive failure: nothing for the first few attempts, then a doubling
+// delay, then a lockout.
+func loginBackoff(failures int64) time.Duration {
+	switch {
+	case failures < loginFreeAttempts:
+		return 0
+	case failures >= loginLockoutAttempts:
+		return loginLockoutDuration
+	default:
+		return time.Duration(1<<(failures-loginFreeAttempts)) * 5 * time.Second
+	}
+}
+
+// checkLoginThrottle returns a LoginLockedError while the phone number or IP
+// is blocked. Throttling fails open if the attempt store is unavailable so a
+// Redis outage does not lock every user out.
+func (lu *LoginUsecase) checkLoginThrottle(ctx context.Context, phone string, meta domain.SessionMetadata) error {
+	if lu.attemptStore == nil {
+		return nil
+	}
+
+	wait, err := lu.attemptStore.BlockedFor(ctx, domain.LoginScopePhone, phone)
+	if err != nil {
+		logger.Error("login throttle check failed", "phone", phone, "error", err)
+		return nil
+	}
+
+	if meta.IPAddress != "" {
+		ipWait, err := lu.attemptStore.BlockedFor(ctx, domain.LoginScopeIP, meta.IPAddress)
+		if err != nil {
+			logger.Error("login throttle check failed", "ip_address", meta.IPAddress, "error", err)
+		} else if ipWait > wait {
+			wait = ipWait
+		}
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	lu.audit(ctx, &domain.LoginAuditEntry{
+		Phone:     phone,
+		IPAddress: meta.IPAddress,
+		Device:    meta.Device,
+		Event:     domain.LoginEventThrottled,
+	})
+
+	return &domain.LoginLockedError{RetryAfter: wait}
+}
+
+// recordLoginFailure audits a failed login and applies the backoff policy to
+// the phone number and the client IP.
+func (lu *LoginUsecase) recordLoginFailure(ctx context.Context, phone string, professionalID *uuid.UUID, meta domain.SessionMetadata, event string) {
+	lu.audit(ctx, &domain.LoginAuditEntry{
+		Phone:                 phone,
+		MedicalProfessionalID: professionalID,
+		IPAddress:             meta.IPAddress,
+		Device:                meta.Device,
+		Event:                 event,
+	})
+
+	if lu.attemptStore == nil {
+		return
+	}
+
+	failures, err := lu.attemptStore.IncrementFailures(ctx, domain.LoginScopePhone, phone, loginFailureWindow)
+	if err != nil {
+		logger.Error("failed to count login failure", "phone", phone, "error", err)
+		return
+	}
+
+	if delay := loginBackoff(failures); delay > 0 {
+		if err := lu.attemptStore.Block(ctx, domain.LoginScopePhone, phone, delay); err != nil {
+			logger.Error("failed to throttle login", "phone", phone, "error", err)
+		}
+		if delay == loginLockoutDuration {
+			logger.Warn("login locked out", "phone", phone, "failures", failures)
+			lu.audit(ctx, &domain.LoginAuditEntry{
+				Phone:                 phone,
+				MedicalProfessionalID: professionalID,
+				IPAddress:             meta.IPAddress,
+				Device:                meta.Device,
+				Event:                 domain.LoginEventLockedOut,
+			})
+		}
+	}
+
+	if meta.IPAddress == "" {
+		return
+	}
+
+	ipFailures, err := lu.attemptStore.IncrementFailures(ctx, domain.LoginScopeIP, meta.IPAddress, loginFailureWindow)
+	if err != nil {
+		logger.Error("failed to count login failure", "ip_address", meta.IPAddress, "error", err)
+		return
+	}
+	if ipFailures >= ipLockoutAttempts {
+		if err := lu.attemptStore.Block(ctx, domain.LoginScopeIP, meta.IPAddress, loginLockoutDuration); err != nil {
+			logger.Error("failed to block IP", "ip_address", meta.IPAddress, "error", err)
+		}
+	}
+}
+
+func (lu *LoginUsecase) clearLoginFailures(ctx context.Context, phone string) {
+	if lu.attemptStore == nil {
+		return
+	}
+	if err := lu.attemptStore.Reset(ctx, domain.LoginScopePhone, phone); err != nil {
+		logger.Error("failed to reset login failures", "phone", phone, "error", err)
+	}
+}
+
+func (lu *LoginUsecase) audit(ctx context.Context, entry *domain.LoginAuditEntry) {
+	if lu.auditRepo == nil {
+		return
+	}
+	if err := lu.auditRepo.Record(ctx, entry); err != nil {
+		logger.Error("failed to record login audit entry", "event", entry.Event, "error", err)
+	}
+}
+
+// UnlockLogin clears the failure count and any lockout for a phone number.
+func (lu *LoginUsecase) UnlockLogin(ctx context.Context, phone string, adminID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	normalized := userutil.NormalizePhone(phone)
+	if normalized == "" {
+		return errors.New("invalid phone number")
+	}
+
+	if lu.attemptStore == nil {
+		return errors.New("login throttling is not configured")
+	}
+
+	if err := lu.attemptStore.Reset(ctx, domain.LoginScopePhone, normalized); err != nil {
+		return err
+	}
+
+	lu.audit(ctx, &domain.LoginAuditEntry{
+		Phone:       normalized,
+		Event:       domain.LoginEventUnlocked,
+		PerformedBy: &adminID,
+	})
+
+	return nil
+}
+
+func (lu *LoginUsecase) ListLoginAudit(ctx context.Context, phone string, limit int) ([]*domain.LoginAuditEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	if phone != "" {
+		phone = userutil.NormalizePhone(phone)
+		if phone == "" {
+			return nil, errors.New("invalid phone number")
+		}
+	}
+
+	if limit <= 0 || limit > 1000 {
+		limit = defaultLoginAuditLimit
+	}
+
+	if lu.auditRepo == nil {
+		return []*domain.LoginAuditEntry{}, nil
+	}
+
+	return lu.auditRepo.List(ctx, phone, limit)
+}
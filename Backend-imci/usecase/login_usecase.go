@@ -3,12 +3,15 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
 	"github.com/Afomiat/Digital-IMCI/internal/userutil"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -16,23 +19,32 @@ import (
 
 type LoginUsecase struct {
 	medicalProfessionalRepo domain.MedicalProfessionalRepository
+	sessionRepo             domain.RefreshSessionRepository
+	attemptStore            domain.LoginAttemptStore
+	auditRepo               domain.LoginAuditRepository
 	contextTimeout          time.Duration
 	env                     *config.Env
 }
 
 func NewLoginUsecase(
 	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	sessionRepo domain.RefreshSessionRepository,
+	attemptStore domain.LoginAttemptStore,
+	auditRepo domain.LoginAuditRepository,
 	timeout time.Duration,
 	env *config.Env,
 ) domain.LoginUsecase {
 	return &LoginUsecase{
 		medicalProfessionalRepo: medicalProfessionalRepo,
+		sessionRepo:             sessionRepo,
+		attemptStore:            attemptStore,
+		auditRepo:               auditRepo,
 		contextTimeout:          timeout,
 		env:                     env,
 	}
 }
 
-func (lu *LoginUsecase) Login(ctx context.Context, request *domain.LoginRequest) (*domain.LoginResponse, error) {
+func (lu *LoginUsecase) Login(ctx context.Context, request *domain.LoginRequest, meta domain.SessionMetadata) (*domain.LoginResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
 	defer cancel()
 
@@ -43,35 +55,54 @@ func (lu *LoginUsecase) Login(ctx context.Context, request *domain.LoginRequest)
 
 	request.Phone = normalizedPhone
 
+	// Refuse early while the phone or IP is throttled
+	if err := lu.checkLoginThrottle(ctx, normalizedPhone, meta); err != nil {
+		return nil, err
+	}
+
 	// Find medical professional by phone
 	professional, err := lu.medicalProfessionalRepo.GetByPhone(ctx, normalizedPhone)
 	if err != nil {
-		fmt.Printf("User not found for phone: %s, error: %v\n", request.Phone, err)
+		logger.Warn("login failed: unknown phone", "phone", request.Phone, "error", err)
+		lu.recordLoginFailure(ctx, normalizedPhone, nil, meta, domain.LoginEventUnknownPhone)
 		return nil, errors.New("invalid phone or password")
 	}
 
-	// Debug: Print what's stored in the database
-	fmt.Printf("Stored password hash: %s\n", professional.PasswordHash)
-	fmt.Printf("Input password: %s\n", request.Password)
-
 	// Verify password - Use the same pattern as working code
 	err = userutil.ComparePassword(professional.PasswordHash, request.Password)
 	if err != nil {
-		fmt.Printf("Password comparison failed: %v\n", err)
+		logger.Warn("login failed: wrong password", "phone", request.Phone, "medical_professional_id", professional.ID)
+		lu.recordLoginFailure(ctx, normalizedPhone, &professional.ID, meta, domain.LoginEventWrongPassword)
 		return nil, errors.New("invalid credentials")
 	}
 
-	// Generate JWT tokens
-	accessToken, err := lu.generateAccessToken(professional)
+	lu.clearLoginFailures(ctx, normalizedPhone)
+
+	// Open a refresh session for this device
+	sessionID := uuid.New()
+
+	accessToken, err := lu.generateAccessToken(professional, sessionID)
 	if err != nil {
 		return nil, errors.New("failed to generate token")
 	}
 
-	refreshToken, err := lu.generateRefreshToken(professional)
+	refreshToken, err := lu.generateRefreshToken(professional, sessionID)
 	if err != nil {
 		return nil, errors.New("failed to generate refresh token")
 	}
 
+	session := &domain.RefreshSession{
+		ID:                    sessionID,
+		MedicalProfessionalID: professional.ID,
+		TokenHash:             hashToken(refreshToken),
+		Device:                meta.Device,
+		IPAddress:             meta.IPAddress,
+		ExpiresAt:             lu.refreshExpiry(),
+	}
+	if err := lu.sessionRepo.Create(ctx, session); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
 	return &domain.LoginResponse{
 		ID:           professional.ID,
 		FullName:     professional.FullName,
@@ -84,11 +115,12 @@ func (lu *LoginUsecase) Login(ctx context.Context, request *domain.LoginRequest)
 
 // usecase/login_usecase.go
 // usecase/login_usecase.go
-func (lu *LoginUsecase) generateAccessToken(professional *domain.MedicalProfessional) (string, error) {
+func (lu *LoginUsecase) generateAccessToken(professional *domain.MedicalProfessional, sessionID uuid.UUID) (string, error) {
 	claims := jwt.MapClaims{
 		"id":    professional.ID.String(),
 		"phone": professional.Phone,
 		"role":  professional.Role,
+		"sid":   sessionID.String(),
 		"type":  "access", // Add token type
 		"exp":   time.Now().Add(time.Minute * time.Duration(lu.env.AccessTokenExpiryMinute)).Unix(),
 	}
@@ -97,19 +129,32 @@ func (lu *LoginUsecase) generateAccessToken(professional *domain.MedicalProfessi
 	return token.SignedString([]byte(lu.env.AccessTokenSecret))
 }
 
-func (lu *LoginUsecase) generateRefreshToken(professional *domain.MedicalProfessional) (string, error) {
+// generateRefreshToken issues a refresh token bound to a session. The jti
+// makes every rotated token distinct even within the same second.
+func (lu *LoginUsecase) generateRefreshToken(professional *domain.MedicalProfessional, sessionID uuid.UUID) (string, error) {
 	claims := jwt.MapClaims{
 		"id":   professional.ID.String(),
+		"sid":  sessionID.String(),
+		"jti":  uuid.New().String(),
 		"type": "refresh", // Add token type
-		"exp":  time.Now().Add(time.Hour * 24 * time.Duration(lu.env.RefreshTokenExpiryDay)).Unix(),
+		"exp":  lu.refreshExpiry().Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(lu.env.RefreshTokenSecret))
 }
 
+func (lu *LoginUsecase) refreshExpiry() time.Time {
+	return time.Now().Add(time.Hour * 24 * time.Duration(lu.env.RefreshTokenExpiryDay))
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // usecase/login_usecase.go
-func (lu *LoginUsecase) RefreshToken(ctx context.Context, refreshToken string) (*domain.LoginResponse, error) {
+func (lu *LoginUsecase) RefreshToken(ctx context.Context, refreshToken string, meta domain.SessionMetadata) (*domain.LoginResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
 	defer cancel()
 
@@ -123,7 +168,7 @@ func (lu *LoginUsecase) RefreshToken(ctx context.Context, refreshToken string) (
 	})
 
 	if err != nil || !token.Valid {
-		return nil, errors.New("invalid or expired refresh token")
+		return nil, domain.ErrInvalidRefreshToken
 	}
 
 	// 2. Extract claims
@@ -143,29 +188,62 @@ func (lu *LoginUsecase) RefreshToken(ctx context.Context, refreshToken string) (
 		return nil, errors.New("invalid user ID format")
 	}
 
-	// 4. Get user from database
+	if tokenType, _ := claims["type"].(string); tokenType != "refresh" {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	sessionIDStr, _ := claims["sid"].(string)
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	// 4. Check the token is the one currently held by its session
+	session, err := lu.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if session.MedicalProfessionalID != userID || session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	oldHash := hashToken(refreshToken)
+	if oldHash != session.TokenHash {
+		// An already rotated token was replayed: someone else holds a copy.
+		if err := lu.sessionRepo.Revoke(ctx, session.ID, userID, "token_reuse"); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	// 5. Get user from database
 	professional, err := lu.medicalProfessionalRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
 
-	// 5. TODO: Check if refresh token is revoked (optional enhancement)
-	// if lu.isRefreshTokenRevoked(ctx, refreshToken) {
-	//     return nil, errors.New("refresh token has been revoked")
-	// }
-
 	// 6. Generate new access token
-	newAccessToken, err := lu.generateAccessToken(professional)
+	newAccessToken, err := lu.generateAccessToken(professional, session.ID)
 	if err != nil {
 		return nil, errors.New("failed to generate new access token")
 	}
 
-	// 7. Optionally generate new refresh token (rotation)
-	newRefreshToken, err := lu.generateRefreshToken(professional)
+	// 7. Rotate the refresh token; the old one stops working
+	newRefreshToken, err := lu.generateRefreshToken(professional, session.ID)
 	if err != nil {
 		return nil, errors.New("failed to generate new refresh token")
 	}
 
+	if err := lu.sessionRepo.Rotate(ctx, session.ID, oldHash, hashToken(newRefreshToken), meta, lu.refreshExpiry()); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			// Lost a race with a concurrent refresh using the same token.
+			if revokeErr := lu.sessionRepo.Revoke(ctx, session.ID, userID, "token_reuse"); revokeErr != nil && !errors.Is(revokeErr, domain.ErrSessionNotFound) {
+				return nil, revokeErr
+			}
+		}
+		return nil, err
+	}
+
 	return &domain.LoginResponse{
 		ID:           professional.ID,
 		FullName:     professional.FullName,
@@ -175,3 +253,40 @@ func (lu *LoginUsecase) RefreshToken(ctx context.Context, refreshToken string) (
 		RefreshToken: newRefreshToken, // Return new refresh token (rotation)
 	}, nil
 }
+
+func (lu *LoginUsecase) ListSessions(ctx context.Context, medicalProfessionalID uuid.UUID, currentSessionID uuid.UUID) ([]*domain.RefreshSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	sessions, err := lu.sessionRepo.ListActive(ctx, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range sessions {
+		s.Current = s.ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+func (lu *LoginUsecase) RevokeSession(ctx context.Context, medicalProfessionalID uuid.UUID, sessionID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	return lu.sessionRepo.Revoke(ctx, sessionID, medicalProfessionalID, "revoked_by_user")
+}
+
+// RevokeOtherSessions signs the user out everywhere except the session the
+// request came from. A zero currentSessionID revokes every session.
+func (lu *LoginUsecase) RevokeOtherSessions(ctx context.Context, medicalProfessionalID uuid.UUID, currentSessionID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, lu.contextTimeout)
+	defer cancel()
+
+	var except *uuid.UUID
+	if currentSessionID != uuid.Nil {
+		except = &currentSessionID
+	}
+
+	return lu.sessionRepo.RevokeAll(ctx, medicalProfessionalID, except, "revoked_by_user")
+}
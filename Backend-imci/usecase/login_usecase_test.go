@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/config"
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
+	"github.com/google/uuid"
+)
+
+type fakeProfessionalRepo struct {
+	domain.MedicalProfessionalRepository
+	professional *domain.MedicalProfessional
+}
+
+func (f *fakeProfessionalRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.MedicalProfessional, error) {
+	if id != f.professional.ID {
+		return nil, errors.New("not found")
+	}
+	return f.professional, nil
+}
+
+func (f *fakeProfessionalRepo) GetByPhone(ctx context.Context, phone string) (*domain.MedicalProfessional, error) {
+	if phone != f.professional.Phone {
+		return nil, errors.New("not found")
+	}
+	return f.professional, nil
+}
+
+type fakeAttemptStore struct {
+	failures map[string]int64
+	blocked  map[string]time.Duration
+}
+
+func newFakeAttemptStore() *fakeAttemptStore {
+	return &fakeAttemptStore{failures: map[string]int64{}, blocked: map[string]time.Duration{}}
+}
+
+func (f *fakeAttemptStore) IncrementFailures(ctx context.Context, scope, id string, window time.Duration) (int64, error) {
+	f.failures[scope+":"+id]++
+	return f.failures[scope+":"+id], nil
+}
+
+func (f *fakeAttemptStore) Block(ctx context.Context, scope, id string, duration time.Duration) error {
+	f.blocked[scope+":"+id] = duration
+	return nil
+}
+
+func (f *fakeAttemptStore) BlockedFor(ctx context.Context, scope, id string) (time.Duration, error) {
+	return f.blocked[scope+":"+id], nil
+}
+
+func (f *fakeAttemptStore) Reset(ctx context.Context, scope, id string) error {
+	delete(f.failures, scope+":"+id)
+	delete(f.blocked, scope+":"+id)
+	return nil
+}
+
+// expire lifts every block, as if the backoff delays had elapsed.
+func (f *fakeAttemptStore) expire() {
+	f.blocked = map[string]time.Duration{}
+}
+
+type fakeSessionRepo struct {
+	sessions map[uuid.UUID]*domain.RefreshSession
+}
+
+func (f *fakeSessionRepo) Create(ctx context.Context, s *domain.RefreshSession) error {
+	s.CreatedAt = time.Now()
+	s.LastUsedAt = s.CreatedAt
+	f.sessions[s.ID] = s
+	return nil
+}
+
+func (f *fakeSessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.RefreshSession, error) {
+	s, ok := f.sessions[id]
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (f *fakeSessionRepo) Rotate(ctx context.Context, id uuid.UUID, oldHash, newHash string, meta domain.SessionMetadata, expiresAt time.Time) error {
+	s, ok := f.sessions[id]
+	if !ok || s.TokenHash != oldHash || s.RevokedAt != nil {
+		return domain.ErrRefreshTokenReused
+	}
+	s.TokenHash = newHash
+	s.ExpiresAt = expiresAt
+	return nil
+}
+
+func (f *fakeSessionRepo) ListActive(ctx context.Context, mpID uuid.UUID) ([]*domain.RefreshSession, error) {
+	var active []*domain.RefreshSession
+	for _, s := range f.sessions {
+		if s.MedicalProfessionalID == mpID && s.RevokedAt == nil {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeSessionRepo) Revoke(ctx context.Context, id uuid.UUID, mpID uuid.UUID, reason string) error {
+	s, ok := f.sessions[id]
+	if !ok || s.MedicalProfessionalID != mpID || s.RevokedAt != nil {
+		return domain.ErrSessionNotFound
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	s.RevokedReason = reason
+	return nil
+}
+
+func (f *fakeSessionRepo) RevokeAll(ctx context.Context, mpID uuid.UUID, except *uuid.UUID, reason string) error {
+	for id := range f.sessions {
+		if except != nil && id == *except {
+			continue
+		}
+		_ = f.Revoke(ctx, id, mpID, reason)
+	}
+	return nil
+}
+
+func newTestLoginUsecase(t *testing.T, attempts domain.LoginAttemptStore) (domain.LoginUsecase, *fakeSessionRepo) {
+	t.Helper()
+
+	hash, err := userutil.HashPassword("secret123")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+
+	professionals := &fakeProfessionalRepo{professional: &domain.MedicalProfessional{
+		ID:           uuid.New(),
+		FullName:     "Test Nurse",
+		Phone:        "251911000000",
+		PasswordHash: hash,
+		Role:         "nurse",
+	}}
+	sessions := &fakeSessionRepo{sessions: map[uuid.UUID]*domain.RefreshSession{}}
+	env := &config.Env{
+		AccessTokenSecret:       "access-secret",
+		RefreshTokenSecret:      "refresh-secret",
+		AccessTokenExpiryMinute: 15,
+		RefreshTokenExpiryDay:   7,
+	}
+
+	return NewLoginUsecase(professionals, sessions, attempts, nil, time.Second, env), sessions
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	lu, sessions := newTestLoginUsecase(t, nil)
+	ctx := context.Background()
+	meta := domain.SessionMetadata{Device: "test", IPAddress: "127.0.0.1"}
+
+	login, err := lu.Login(ctx, &domain.LoginRequest{Phone: "0911000000", Password: "secret123"}, meta)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	refreshed, err := lu.RefreshToken(ctx, login.RefreshToken, meta)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if refreshed.RefreshToken == login.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+
+	// Replaying the first token must revoke the session for everyone.
+	if _, err := lu.RefreshToken(ctx, login.RefreshToken, meta); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, err := lu.RefreshToken(ctx, refreshed.RefreshToken, meta); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Fatalf("expected rotated token to be revoked with its session, got %v", err)
+	}
+
+	active, _ := sessions.ListActive(ctx, login.ID)
+	if len(active) != 0 {
+		t.Fatalf("expected no active sessions, got %d", len(active))
+	}
+}
+
+func TestRevokeSessionStopsRefresh(t *testing.T) {
+	lu, _ := newTestLoginUsecase(t, nil)
+	ctx := context.Background()
+	meta := domain.SessionMetadata{Device: "test"}
+
+	first, err := lu.Login(ctx, &domain.LoginRequest{Phone: "0911000000", Password: "secret123"}, meta)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	second, err := lu.Login(ctx, &domain.LoginRequest{Phone: "0911000000", Password: "secret123"}, meta)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	list, err := lu.ListSessions(ctx, first.ID, uuid.Nil)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("expected 2 sessions, got %d (%v)", len(list), err)
+	}
+
+	if err := lu.RevokeOtherSessions(ctx, first.ID, uuid.Nil); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+
+	for _, token := range []string{first.RefreshToken, second.RefreshToken} {
+		if _, err := lu.RefreshToken(ctx, token, meta); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Fatalf("expected revoked session to reject refresh, got %v", err)
+		}
+	}
+}
+
+func TestLoginBackoff(t *testing.T) {
+	cases := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{1, 0},
+		{2, 0},
+		{3, 5 * time.Second},
+		{4, 10 * time.Second},
+		{5, 20 * time.Second},
+		{6, loginLockoutDuration},
+		{9, loginLockoutDuration},
+	}
+	for _, tc := range cases {
+		if got := loginBackoff(tc.failures); got != tc.want {
+			t.Errorf("loginBackoff(%d) = %v, want %v", tc.failures, got, tc.want)
+		}
+	}
+}
+
+func TestLoginLockoutAndUnlock(t *testing.T) {
+	attempts := newFakeAttemptStore()
+	lu, _ := newTestLoginUsecase(t, attempts)
+	ctx := context.Background()
+	meta := domain.SessionMetadata{Device: "test", IPAddress: "10.0.0.1"}
+	wrong := &domain.LoginRequest{Phone: "0911000000", Password: "wrong-password"}
+	right := &domain.LoginRequest{Phone: "0911000000", Password: "secret123"}
+
+	for i := 0; i < loginLockoutAttempts; i++ {
+		attempts.expire()
+		if _, err := lu.Login(ctx, wrong, meta); err == nil || errors.Is(err, domain.ErrLoginLocked) {
+			t.Fatalf("attempt %d: expected invalid credentials, got %v", i+1, err)
+		}
+	}
+
+	_, err := lu.Login(ctx, right, meta)
+	var locked *domain.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("expected lockout after %d failures, got %v", loginLockoutAttempts, err)
+	}
+	if locked.RetryAfter != loginLockoutDuration {
+		t.Fatalf("expected retry after %v, got %v", loginLockoutDuration, locked.RetryAfter)
+	}
+
+	if err := lu.UnlockLogin(ctx, "0911000000", uuid.New()); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if _, err := lu.Login(ctx, right, meta); err != nil {
+		t.Fatalf("expected login after unlock, got %v", err)
+	}
+}
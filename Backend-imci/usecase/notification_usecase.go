@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
+	"github.com/google/uuid"
+)
+
+const defaultDeliveryLogLimit = 100
+
+type NotificationUsecase struct {
+	medicalProfessionalRepo domain.MedicalProfessionalRepository
+	deliveryRepo            domain.NotificationDeliveryRepository
+	notifier                domain.NotificationDispatcher
+	contextTimeout          time.Duration
+}
+
+func NewNotificationUsecase(
+	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	deliveryRepo domain.NotificationDeliveryRepository,
+	notifier domain.NotificationDispatcher,
+	timeout time.Duration,
+) domain.NotificationUsecase {
+	return &NotificationUsecase{
+		medicalProfessionalRepo: medicalProfessionalRepo,
+		deliveryRepo:            deliveryRepo,
+		notifier:                notifier,
+		contextTimeout:          timeout,
+	}
+}
+
+// AvailableChannels lists the configured channels in fallback order.
+func (nu *NotificationUsecase) AvailableChannels() []string {
+	return nu.notifier.Channels()
+}
+
+// SetPreferredChannel sets the channel notifications go to first and, when
+// language is not empty, the language templated messages are sent in.
+func (nu *NotificationUsecase) SetPreferredChannel(ctx context.Context, medicalProfessionalID uuid.UUID, channel, language string) error {
+	ctx, cancel := context.WithTimeout(ctx, nu.contextTimeout)
+	defer cancel()
+
+	if !nu.notifier.Has(channel) {
+		return domain.ErrUnknownNotificationChannel
+	}
+
+	professional, err := nu.medicalProfessionalRepo.GetByID(ctx, medicalProfessionalID)
+	if err != nil {
+		return err
+	}
+
+	professional.NotificationChannel = channel
+	professional.UseWhatsApp = channel == domain.ChannelWhatsApp
+	if language != "" {
+		professional.NotificationLanguage = language
+	}
+	return nu.medicalProfessionalRepo.Update(ctx, professional)
+}
+
+func (nu *NotificationUsecase) ListDeliveries(ctx context.Context, phone string, limit int) ([]*domain.NotificationDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, nu.contextTimeout)
+	defer cancel()
+
+	if phone != "" {
+		phone = userutil.NormalizePhone(phone)
+		if phone == "" {
+			return nil, errors.New("invalid phone number")
+		}
+	}
+
+	if limit <= 0 || limit > 1000 {
+		limit = defaultDeliveryLogLimit
+	}
+
+	return nu.deliveryRepo.List(ctx, phone, limit)
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/surveillance"
+	"github.com/google/uuid"
+)
+
+// DefaultOutbreakSignals are watched when no signals are configured.
+var DefaultOutbreakSignals = []string{"DYSENTERY", "MEASLES", "SEVERE DEHYDRATION"}
+
+// DefaultOutbreakMinCases keeps single cases at quiet facilities from
+// raising alerts.
+const DefaultOutbreakMinCases = 3
+
+// OutbreakSettings configures the surveillance run.
+type OutbreakSettings struct {
+	// Signals are matched against classification names by containment.
+	Signals []string
+	Method  surveillance.Method
+	// Threshold of 0 uses the method's default.
+	Threshold float64
+	// MinCases is the fewest cases on a day that may alert.
+	MinCases int
+	// FocalPhones are the district focal persons who receive alerts.
+	FocalPhones []string
+}
+
+type OutbreakUsecase struct {
+	outbreakRepo   domain.OutbreakRepository
+	userRepo       domain.MedicalProfessionalRepository
+	notifier       domain.NotificationDispatcher
+	settings       OutbreakSettings
+	contextTimeout time.Duration
+}
+
+// NewOutbreakUsecase fills in DefaultOutbreakSignals, EARS C2 and
+// DefaultOutbreakMinCases where settings leave them empty. Without a
+// notifier alerts are only stored.
+func NewOutbreakUsecase(outbreakRepo domain.OutbreakRepository, userRepo domain.MedicalProfessionalRepository, notifier domain.NotificationDispatcher, settings OutbreakSettings, timeout time.Duration) domain.OutbreakUsecase {
+	if len(settings.Signals) == 0 {
+		settings.Signals = DefaultOutbreakSignals
+	}
+	if settings.Method == "" {
+		settings.Method = surveillance.C2
+	}
+	if settings.MinCases <= 0 {
+		settings.MinCases = DefaultOutbreakMinCases
+	}
+	return &OutbreakUsecase{
+		outbreakRepo:   outbreakRepo,
+		userRepo:       userRepo,
+		notifier:       notifier,
+		settings:       settings,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *OutbreakUsecase) Detect(ctx context.Context, day time.Time) ([]*domain.OutbreakAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	days := surveillance.SeriesLength(uc.settings.Method)
+	from := day.AddDate(0, 0, -(days - 1))
+
+	counts, err := uc.outbreakRepo.DailyCounts(ctx, uc.settings.Signals, from, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ facility, signal string }
+	series := make(map[key][]int)
+	for _, c := range counts {
+		k := key{c.Facility, c.Signal}
+		if series[k] == nil {
+			series[k] = make([]int, days)
+		}
+		index := int(c.Date.Sub(from).Hours() / 24)
+		if index >= 0 && index < days {
+			series[k][index] += c.Count
+		}
+	}
+
+	keys := make([]key, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].facility != keys[j].facility {
+			return keys[i].facility < keys[j].facility
+		}
+		return keys[i].signal < keys[j].signal
+	})
+
+	alerts := []*domain.OutbreakAlert{}
+	for _, k := range keys {
+		if series[k][days-1] < uc.settings.MinCases {
+			continue
+		}
+		result, err := surveillance.Detect(uc.settings.Method, series[k], uc.settings.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Alert {
+			continue
+		}
+
+		alert := &domain.OutbreakAlert{
+			Facility:  k.facility,
+			Signal:    k.signal,
+			AlertDate: day,
+			Method:    string(uc.settings.Method),
+			Observed:  result.Observed,
+			Baseline:  result.Baseline,
+			Statistic: result.Statistic,
+			Threshold: result.Threshold,
+			Status:    domain.OutbreakAlertOpen,
+			CreatedAt: time.Now(),
+		}
+		created, err := uc.outbreakRepo.CreateAlert(ctx, alert)
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			continue
+		}
+
+		logger.Warn("outbreak alert raised", "facility", alert.Facility, "signal", alert.Signal, "observed", alert.Observed, "baseline", alert.Baseline)
+		alert.Notified = uc.notify(ctx, alert)
+		if alert.Notified > 0 {
+			if err := uc.outbreakRepo.SetNotified(ctx, alert.ID, alert.Notified); err != nil {
+				logger.Error("failed to record outbreak alert notifications", "alert_id", alert.ID, "error", err)
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// notify sends alert to every focal person on their preferred channel and
+// returns how many were reached.
+func (uc *OutbreakUsecase) notify(ctx context.Context, alert *domain.OutbreakAlert) int {
+	if uc.notifier == nil {
+		return 0
+	}
+
+	text := outbreakAlertText(alert)
+	notified := 0
+	for _, phone := range uc.settings.FocalPhones {
+		preferred, language := "", ""
+		if professional, err := uc.userRepo.GetByPhone(ctx, phone); err == nil {
+			preferred = domain.ResolveChannel(professional.NotificationChannel, professional.UseWhatsApp)
+			language = professional.NotificationLanguage
+		}
+
+		_, err := uc.notifier.Deliver(ctx, &domain.OTPNotification{
+			Phone:    phone,
+			Text:     text,
+			Language: language,
+			Purpose:  domain.PurposeOutbreakAlert,
+		}, preferred)
+		if err != nil {
+			logger.Error("failed to send outbreak alert", "alert_id", alert.ID, "phone", phone, "error", err)
+			continue
+		}
+		notified++
+	}
+	return notified
+}
+
+func outbreakAlertText(alert *domain.OutbreakAlert) string {
+	return fmt.Sprintf(
+		"Possible %s outbreak at %s: %d cases on %s against a baseline of %.1f a day (EARS %s).",
+		alert.Signal,
+		alert.Facility,
+		alert.Observed,
+		alert.AlertDate.Format("2006-01-02"),
+		alert.Baseline,
+		strings.ToUpper(alert.Method),
+	)
+}
+
+func (uc *OutbreakUsecase) ListAlerts(ctx context.Context, status string, limit int) ([]*domain.OutbreakAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return uc.outbreakRepo.ListAlerts(ctx, status, limit)
+}
+
+func (uc *OutbreakUsecase) Acknowledge(ctx context.Context, id uuid.UUID, medicalProfessionalID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.outbreakRepo.Acknowledge(ctx, id, medicalProfessionalID, time.Now())
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/surveillance"
+	"github.com/google/uuid"
+)
+
+type fakeOutbreakRepo struct {
+	domain.OutbreakRepository
+	counts  []*domain.DailySignalCount
+	from    time.Time
+	signals []string
+	alerts  map[string]*domain.OutbreakAlert
+}
+
+func (f *fakeOutbreakRepo) DailyCounts(ctx context.Context, signals []string, from, to time.Time) ([]*domain.DailySignalCount, error) {
+	f.signals, f.from = signals, from
+	return f.counts, nil
+}
+
+func (f *fakeOutbreakRepo) CreateAlert(ctx context.Context, alert *domain.OutbreakAlert) (bool, error) {
+	key := alert.Facility + "|" + alert.Signal + "|" + alert.AlertDate.Format("2006-01-02")
+	if _, ok := f.alerts[key]; ok {
+		return false, nil
+	}
+	alert.ID = uuid.New()
+	f.alerts[key] = alert
+	return true, nil
+}
+
+func (f *fakeOutbreakRepo) SetNotified(ctx context.Context, id uuid.UUID, notified int) error {
+	return nil
+}
+
+type fakeOutbreakUserRepo struct {
+	domain.MedicalProfessionalRepository
+}
+
+func (f *fakeOutbreakUserRepo) GetByPhone(ctx context.Context, phone string) (*domain.MedicalProfessional, error) {
+	if phone == "0911000001" {
+		return &domain.MedicalProfessional{NotificationChannel: domain.ChannelSMS}, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+type fakeOutbreakNotifier struct {
+	domain.NotificationDispatcher
+	sent      []*domain.OTPNotification
+	preferred []string
+}
+
+func (f *fakeOutbreakNotifier) Deliver(ctx context.Context, n *domain.OTPNotification, preferred string) (string, error) {
+	f.sent = append(f.sent, n)
+	f.preferred = append(f.preferred, preferred)
+	return domain.ChannelSMS, nil
+}
+
+func TestOutbreakDetect(t *testing.T) {
+	day := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	repo := &fakeOutbreakRepo{alerts: map[string]*domain.OutbreakAlert{}}
+	// A quiet week of dysentery at Dangila, then 6 cases today; measles at
+	// Bahir Dar stays at its usual level.
+	for i, n := range []int{1, 0, 1, 0, 1, 0, 1, 0, 1, 6} {
+		if n > 0 {
+			repo.counts = append(repo.counts, &domain.DailySignalCount{Facility: "Dangila HC", Signal: "DYSENTERY", Date: day.AddDate(0, 0, i-9), Count: n})
+		}
+	}
+	for i := 0; i < 10; i++ {
+		repo.counts = append(repo.counts, &domain.DailySignalCount{Facility: "Bahir Dar HC", Signal: "MEASLES", Date: day.AddDate(0, 0, i-9), Count: 3 + i%2})
+	}
+
+	notifier := &fakeOutbreakNotifier{}
+	uc := NewOutbreakUsecase(repo, &fakeOutbreakUserRepo{}, notifier, OutbreakSettings{
+		FocalPhones: []string{"0911000001", "0911000002"},
+	}, time.Second)
+
+	alerts, err := uc.Detect(context.Background(), day.Add(15*time.Hour))
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if !repo.from.Equal(day.AddDate(0, 0, -(surveillance.SeriesLength(surveillance.C2) - 1))) {
+		t.Errorf("series starts %v", repo.from)
+	}
+	if len(repo.signals) != len(DefaultOutbreakSignals) {
+		t.Errorf("signals = %v, want the defaults", repo.signals)
+	}
+
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1: %+v", len(alerts), alerts)
+	}
+	alert := alerts[0]
+	if alert.Facility != "Dangila HC" || alert.Signal != "DYSENTERY" || alert.Observed != 6 || alert.Method != "c2" {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+	if alert.Notified != 2 || len(notifier.sent) != 2 {
+		t.Fatalf("alert should reach both focal persons, notified %d", alert.Notified)
+	}
+	if notifier.sent[0].Purpose != domain.PurposeOutbreakAlert || !strings.Contains(notifier.sent[0].Text, "DYSENTERY outbreak at Dangila HC") {
+		t.Errorf("unexpected notification: %+v", notifier.sent[0])
+	}
+	if notifier.preferred[0] != domain.ChannelSMS || notifier.preferred[1] != "" {
+		t.Errorf("preferred channels = %v", notifier.preferred)
+	}
+
+	alerts, err = uc.Detect(context.Background(), day)
+	if err != nil {
+		t.Fatalf("second Detect: %v", err)
+	}
+	if len(alerts) != 0 || len(notifier.sent) != 2 {
+		t.Errorf("alert was raised twice for the same day")
+	}
+}
+
+func TestOutbreakDetectMinCases(t *testing.T) {
+	day := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	repo := &fakeOutbreakRepo{
+		alerts: map[string]*domain.OutbreakAlert{},
+		counts: []*domain.DailySignalCount{{Facility: "Dangila HC", Signal: "MEASLES", Date: day, Count: 2}},
+	}
+	uc := NewOutbreakUsecase(repo, &fakeOutbreakUserRepo{}, nil, OutbreakSettings{}, time.Second)
+
+	alerts, err := uc.Detect(context.Background(), day)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("2 cases over a flat baseline should stay below the minimum, got %+v", alerts)
+	}
+}
@@ -5,11 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
 	"github.com/Afomiat/Digital-IMCI/internal/userutil"
 	"github.com/google/uuid"
 )
@@ -17,9 +17,7 @@ import (
 type passwordResetUsecase struct {
 	medicalProfessionalRepo domain.MedicalProfessionalRepository
 	passwordResetRepo       domain.PasswordResetRepository
-	telegramRepo            domain.TelegramRepository
-	telegramService         domain.TelegramService
-	whatsappService         domain.WhatsAppService
+	notifier                domain.NotificationDispatcher
 	contextTimeout          time.Duration
 	env                     *config.Env
 }
@@ -27,38 +25,41 @@ type passwordResetUsecase struct {
 func NewPasswordResetUsecase(
 	medicalProfessionalRepo domain.MedicalProfessionalRepository,
 	passwordResetRepo domain.PasswordResetRepository,
-	telegramRepo domain.TelegramRepository,
-	telegramService domain.TelegramService,
-	whatsappService domain.WhatsAppService,
+	notifier domain.NotificationDispatcher,
 	timeout time.Duration,
 	env *config.Env,
 ) domain.PasswordResetUsecase {
 	return &passwordResetUsecase{
 		medicalProfessionalRepo: medicalProfessionalRepo,
 		passwordResetRepo:       passwordResetRepo,
-		telegramRepo:           telegramRepo,
-		telegramService:         telegramService,
-		whatsappService:         whatsappService,
+		notifier:                notifier,
 		contextTimeout:          timeout,
 		env:                     env,
 	}
 }
 
-func (u *passwordResetUsecase) InitiatePasswordReset(ctx context.Context, phone string, useWhatsApp bool) error {
+// InitiatePasswordReset sends a reset code on the requested channel, or the
+// account's preferred channel when none is given, falling back to the other
+// channels. It returns the channel used.
+func (u *passwordResetUsecase) InitiatePasswordReset(ctx context.Context, phone string, channel string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
-	_, err := u.medicalProfessionalRepo.GetByPhone(ctx, phone)
+	professional, err := u.medicalProfessionalRepo.GetByPhone(ctx, phone)
 	if err != nil {
-		log.Printf("Password reset requested for non-existent phone: %s", phone)
-		return nil 
+		logger.Info("password reset requested for unknown phone", "phone", phone)
+		return domain.ResolveChannel(channel, false), nil
+	}
+
+	if channel == "" {
+		channel = domain.ResolveChannel(professional.NotificationChannel, professional.UseWhatsApp)
 	}
 
 	existingRequest, err := u.passwordResetRepo.GetPasswordResetOTP(ctx, phone)
 	if err == nil && existingRequest != nil {
 		if time.Now().Before(existingRequest.ExpiresAt) {
 			if time.Since(existingRequest.CreatedAt) < time.Minute {
-				return errors.New("please wait before requesting another reset")
+				return "", errors.New("please wait before requesting another reset")
 			}
 		} else {
 			u.passwordResetRepo.DeletePasswordResetOTP(ctx, phone)
@@ -74,31 +75,28 @@ func (u *passwordResetUsecase) InitiatePasswordReset(ctx context.Context, phone
 		CreatedAt:   time.Now(),
 		IsVerified:  false,
 		Attempts:    0,
-		UseWhatsApp: useWhatsApp,
+		UseWhatsApp: channel == domain.ChannelWhatsApp,
 	}
 
 	if err := u.passwordResetRepo.SavePasswordResetOTP(ctx, resetRequest); err != nil {
-		return fmt.Errorf("failed to save reset request: %w", err)
+		return "", fmt.Errorf("failed to save reset request: %w", err)
 	}
 
-	if useWhatsApp {
-		log.Printf("Sending password reset OTP via WhatsApp to %s", phone)
-			
-		if err := u.whatsappService.SendOTP(ctx, phone, otpCode); err != nil {
-			return fmt.Errorf("failed to send WhatsApp OTP: %w", err)
-		}
-	} else {
-		telegramUsername, err := u.telegramRepo.GetUsernameByPhone(ctx, phone)
-		if err != nil || telegramUsername == "" {
-			return fmt.Errorf("Telegram account not linked. Please link your Telegram account first or use WhatsApp")
-		}
-		
-		log.Printf("Sending password reset OTP via Telegram to @%s", telegramUsername)
-		if err := u.telegramService.SendPasswordResetOTP(ctx, telegramUsername, otpCode); err != nil {
-			return fmt.Errorf("failed to send Telegram OTP: %w", err)
-		}
+	if u.notifier == nil {
+		return "", domain.ErrNoNotificationChannel
 	}
-	return nil
+
+	used, err := u.notifier.Deliver(ctx, &domain.OTPNotification{
+		Phone:     phone,
+		Code:      otpCode,
+		Language:  professional.NotificationLanguage,
+		Purpose:   domain.PurposePasswordResetOTP,
+		ExpiresAt: resetRequest.ExpiresAt,
+	}, channel)
+	if err != nil {
+		return "", fmt.Errorf("failed to send password reset OTP: %w", err)
+	}
+	return used, nil
 }
 func (u *passwordResetUsecase) VerifyPasswordResetOTP(ctx context.Context, phone, otpCode string) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
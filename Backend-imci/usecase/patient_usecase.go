@@ -12,15 +12,18 @@ import (
 
 type PatientUsecase struct {
 	patientRepo    domain.PatientRepository
+	auditor        domain.AuditRecorder
 	contextTimeout time.Duration
 }
 
 func NewPatientUsecase(
 	patientRepo domain.PatientRepository,
+	auditor domain.AuditRecorder,
 	timeout time.Duration,
 ) domain.PatientUsecase {
 	return &PatientUsecase{
 		patientRepo:    patientRepo,
+		auditor:        auditor,
 		contextTimeout: timeout,
 	}
 }
@@ -34,14 +37,26 @@ func (pu *PatientUsecase) CreatePatient(ctx context.Context, patient *domain.Pat
 		return err
 	}
 
-	return pu.patientRepo.Create(ctx, patient)
+	if err := pu.patientRepo.Create(ctx, patient); err != nil {
+		return err
+	}
+
+	return pu.auditor.Record(ctx, domain.AuditCreate, domain.AuditPatient, patient.ID, nil, patient)
 }
 
 func (pu *PatientUsecase) GetPatient(ctx context.Context, id uuid.UUID) (*domain.Patient, error) {
 	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
 	defer cancel()
 
-	return pu.patientRepo.GetByID(ctx, id)
+	patient, err := pu.patientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pu.auditor.Record(ctx, domain.AuditView, domain.AuditPatient, patient.ID, nil, nil); err != nil {
+		return nil, err
+	}
+	return patient, nil
 }
 
 func (pu *PatientUsecase) GetAllPatients(ctx context.Context, page, perPage int) ([]*domain.Patient, int, error) {
@@ -66,14 +81,32 @@ func (pu *PatientUsecase) UpdatePatient(ctx context.Context, patient *domain.Pat
 		return err
 	}
 
-	return pu.patientRepo.Update(ctx, patient)
+	before, err := pu.patientRepo.GetByID(ctx, patient.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := pu.patientRepo.Update(ctx, patient); err != nil {
+		return err
+	}
+
+	return pu.auditor.Record(ctx, domain.AuditUpdate, domain.AuditPatient, patient.ID, before, patient)
 }
 
 func (pu *PatientUsecase) DeletePatient(ctx context.Context, id uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(ctx, pu.contextTimeout)
 	defer cancel()
 
-	return pu.patientRepo.Delete(ctx, id)
+	before, err := pu.patientRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := pu.patientRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return pu.auditor.Record(ctx, domain.AuditDelete, domain.AuditPatient, id, before, nil)
 }
 
 func (pu *PatientUsecase) validatePatient(patient *domain.Patient) error {
@@ -96,4 +129,4 @@ func (pu *PatientUsecase) validatePatient(patient *domain.Patient) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/prescribing"
+	"github.com/google/uuid"
+)
+
+type PrescriptionUsecase struct {
+	prescriptionRepo   domain.PrescriptionRepository
+	assessmentRepo     domain.AssessmentRepository
+	classificationRepo domain.ClassificationRepository
+	treatmentPlanRepo  domain.TreatmentPlanRepository
+	auditor            domain.AuditRecorder
+	drugs              prescribing.DrugTable
+	contextTimeout     time.Duration
+}
+
+func NewPrescriptionUsecase(
+	prescriptionRepo domain.PrescriptionRepository,
+	assessmentRepo domain.AssessmentRepository,
+	classificationRepo domain.ClassificationRepository,
+	treatmentPlanRepo domain.TreatmentPlanRepository,
+	auditor domain.AuditRecorder,
+	drugs prescribing.DrugTable,
+	timeout time.Duration,
+) domain.PrescriptionUsecase {
+	return &PrescriptionUsecase{
+		prescriptionRepo:   prescriptionRepo,
+		assessmentRepo:     assessmentRepo,
+		classificationRepo: classificationRepo,
+		treatmentPlanRepo:  treatmentPlanRepo,
+		auditor:            auditor,
+		drugs:              drugs,
+		contextTimeout:     timeout,
+	}
+}
+
+// Finalize consolidates the treatment plans of every classification of the
+// assessment and stores the result, replacing an earlier prescription.
+func (uc *PrescriptionUsecase) Finalize(ctx context.Context, assessment *domain.Assessment, generatedBy uuid.UUID) (*domain.Prescription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	plans, err := uc.treatmentPlanRepo.GetByAssessmentID(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+	classifications, err := uc.classificationRepo.ListByAssessmentID(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := uc.prescriptionRepo.GetByAssessmentID(ctx, assessment.ID)
+	if err != nil && !errors.Is(err, domain.ErrPrescriptionNotFound) {
+		return nil, err
+	}
+
+	prescription := prescribing.Consolidate(uc.drugs, assessment, plans, classifications)
+	prescription.GeneratedBy = &generatedBy
+	prescription.GeneratedAt = time.Now()
+	if err := uc.prescriptionRepo.Save(ctx, prescription); err != nil {
+		return nil, err
+	}
+
+	if before == nil {
+		err = uc.auditor.Record(ctx, domain.AuditCreate, domain.AuditPrescription, prescription.ID, nil, prescription)
+	} else {
+		err = uc.auditor.Record(ctx, domain.AuditUpdate, domain.AuditPrescription, prescription.ID, before, prescription)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return prescription, nil
+}
+
+func (uc *PrescriptionUsecase) Consolidate(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) (*domain.Prescription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.Finalize(ctx, assessment, medicalProfessionalID)
+}
+
+func (uc *PrescriptionUsecase) Get(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) (*domain.Prescription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID); err != nil {
+		return nil, err
+	}
+
+	prescription, err := uc.prescriptionRepo.GetByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.auditor.Record(ctx, domain.AuditView, domain.AuditPrescription, prescription.ID, nil, nil); err != nil {
+		return nil, err
+	}
+	return prescription, nil
+}
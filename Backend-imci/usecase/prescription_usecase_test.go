@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/prescribing"
+	"github.com/google/uuid"
+)
+
+type fakePrescriptionRepo struct {
+	domain.PrescriptionRepository
+	saved *domain.Prescription
+}
+
+func (f *fakePrescriptionRepo) Save(ctx context.Context, prescription *domain.Prescription) error {
+	if f.saved != nil {
+		prescription.ID = f.saved.ID
+	} else {
+		prescription.ID = uuid.New()
+	}
+	f.saved = prescription
+	return nil
+}
+
+func (f *fakePrescriptionRepo) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) (*domain.Prescription, error) {
+	if f.saved == nil {
+		return nil, domain.ErrPrescriptionNotFound
+	}
+	return f.saved, nil
+}
+
+type fakePrescriptionPlans struct {
+	domain.TreatmentPlanRepository
+	plans []*domain.TreatmentPlan
+}
+
+func (f *fakePrescriptionPlans) GetByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.TreatmentPlan, error) {
+	return f.plans, nil
+}
+
+type fakePrescriptionClassifications struct {
+	domain.ClassificationRepository
+	classifications []*domain.Classification
+}
+
+func (f *fakePrescriptionClassifications) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Classification, error) {
+	return f.classifications, nil
+}
+
+type fakePrescriptionAuditor struct {
+	actions []domain.AuditAction
+}
+
+func (f *fakePrescriptionAuditor) Record(ctx context.Context, action domain.AuditAction, entityType domain.AuditEntity, entityID uuid.UUID, before, after interface{}) error {
+	f.actions = append(f.actions, action)
+	return nil
+}
+
+func prescriptionPlan(classificationID uuid.UUID, drug, dosage, frequency, duration string, preReferral bool, instructions string) *domain.TreatmentPlan {
+	return &domain.TreatmentPlan{
+		ID:                  uuid.New(),
+		ClassificationID:    classificationID,
+		DrugName:            drug,
+		Dosage:              dosage,
+		Frequency:           frequency,
+		Duration:            duration,
+		AdministrationRoute: "Oral",
+		IsPreReferral:       preReferral,
+		Instructions:        instructions,
+	}
+}
+
+func TestPrescriptionFinalizeStoresAndAudits(t *testing.T) {
+	assessment := &domain.Assessment{ID: uuid.New(), AgeMonths: 24}
+	pneumonia := &domain.Classification{ID: uuid.New(), Disease: "PNEUMONIA", TreatmentPriority: 2}
+	repo := &fakePrescriptionRepo{}
+	auditor := &fakePrescriptionAuditor{}
+	uc := NewPrescriptionUsecase(
+		repo,
+		nil,
+		&fakePrescriptionClassifications{classifications: []*domain.Classification{pneumonia}},
+		&fakePrescriptionPlans{plans: []*domain.TreatmentPlan{
+			prescriptionPlan(pneumonia.ID, "Amoxicillin", "Based on weight", "Twice daily", "5 days", false, ""),
+		}},
+		auditor,
+		prescribing.DefaultDrugTable,
+		time.Second,
+	)
+	clinician := uuid.New()
+
+	first, err := uc.Finalize(context.Background(), assessment, clinician)
+	if err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if repo.saved != first || len(first.Items) != 1 || *first.GeneratedBy != clinician {
+		t.Fatalf("saved = %+v, want the consolidated prescription", repo.saved)
+	}
+
+	second, err := uc.Finalize(context.Background(), assessment, clinician)
+	if err != nil {
+		t.Fatalf("second Finalize: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("prescription id changed from %s to %s", first.ID, second.ID)
+	}
+	want := []domain.AuditAction{domain.AuditCreate, domain.AuditUpdate}
+	if len(auditor.actions) != 2 || auditor.actions[0] != want[0] || auditor.actions[1] != want[1] {
+		t.Errorf("audited %v, want %v", auditor.actions, want)
+	}
+}
+
+type fakeFinalizer struct {
+	err   error
+	calls int
+}
+
+func (f *fakeFinalizer) Finalize(ctx context.Context, assessment *domain.Assessment, generatedBy uuid.UUID) (*domain.Prescription, error) {
+	f.calls++
+	return &domain.Prescription{AssessmentID: assessment.ID}, f.err
+}
+
+func TestLifecycleCompleteFinalizesPrescription(t *testing.T) {
+	repo, _ := newLifecycleFixture(domain.StatusClassified)
+	repo.trees = append([]string{}, domain.MandatoryTrees[domain.TypeYoungInfant]...)
+	owner := repo.assessment.MedicalProfessionalID
+
+	failing := &fakeFinalizer{err: errors.New("database down")}
+	uc := NewAssessmentLifecycleUsecase(repo, failing, time.Second)
+	if _, err := uc.Complete(context.Background(), repo.assessment.ID, owner, string(domain.NurseRole)); err == nil {
+		t.Fatal("Complete succeeded although the prescription failed")
+	}
+	if repo.assessment.Status != domain.StatusClassified {
+		t.Errorf("status = %s, want classified after a failed prescription", repo.assessment.Status)
+	}
+
+	finalizer := &fakeFinalizer{}
+	uc = NewAssessmentLifecycleUsecase(repo, finalizer, time.Second)
+	if _, err := uc.Complete(context.Background(), repo.assessment.ID, owner, string(domain.NurseRole)); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if finalizer.calls != 1 || repo.assessment.Status != domain.StatusCompleted {
+		t.Errorf("calls = %d, status = %s", finalizer.calls, repo.assessment.Status)
+	}
+}
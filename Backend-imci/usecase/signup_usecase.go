@@ -3,22 +3,25 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/Afomiat/Digital-IMCI/config"
 	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
 	"github.com/Afomiat/Digital-IMCI/internal/userutil"
 	"github.com/google/uuid"
 )
 
+// signupOTPLifetime is how long a pending signup waits for verification.
+const signupOTPLifetime = 5 * time.Minute
+
 type SignupUsecase struct {
 	medicalProfessionalRepo domain.MedicalProfessionalRepository
 	otpRepo                 domain.OtpRepository
-	telegramService         domain.TelegramService
-	whatsappService         domain.WhatsAppService
+	notifier                domain.NotificationDispatcher
 	contextTimeout          time.Duration
 	env                     *config.Env
 }
@@ -26,19 +29,16 @@ type SignupUsecase struct {
 func NewSignupUsecase(
 	medicalProfessionalRepo domain.MedicalProfessionalRepository,
 	otpRepo domain.OtpRepository,
-	telegramService domain.TelegramService,
-	whatsappService domain.WhatsAppService,
+	notifier domain.NotificationDispatcher,
 	timeout time.Duration,
 	env *config.Env,
 ) domain.SignupUsecase {
 	return &SignupUsecase{
 		medicalProfessionalRepo: medicalProfessionalRepo,
 		otpRepo:                 otpRepo,
-		telegramService:         telegramService,
-		whatsappService:         whatsappService,
-
-		contextTimeout: timeout,
-		env:            env,
+		notifier:                notifier,
+		contextTimeout:          timeout,
+		env:                     env,
 	}
 }
 func (su *SignupUsecase) GetMedicalProfessionalByPhone(ctx context.Context, phone string) (*domain.MedicalProfessional, error) {
@@ -77,6 +77,9 @@ func (su *SignupUsecase) RegisterMedicalProfessional(ctx context.Context, form *
 		FacilityName: form.FacilityName,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
+
+		NotificationChannel:  domain.ResolveChannel(form.Channel, form.UseWhatsApp),
+		NotificationLanguage: form.Language,
 	}
 
 	err = su.medicalProfessionalRepo.Create(ctx, &professional)
@@ -104,12 +107,15 @@ func (su *SignupUsecase) PrepareSignupOTP(ctx context.Context, form *domain.Sign
 	return otp, nil
 }
 
-func (su *SignupUsecase) SendWhatsAppOTP(ctx context.Context, form *domain.SignupForm) (*domain.OTP, error) {
+// SendSignupOTP stores the pending signup and pushes the code through the
+// notification channels, starting with the one the user asked for. The
+// returned OTP's Channel is the channel that delivered it.
+func (su *SignupUsecase) SendSignupOTP(ctx context.Context, form *domain.SignupForm) (*domain.OTP, error) {
 	ctx, cancel := context.WithTimeout(ctx, su.contextTimeout)
 	defer cancel()
 
-	if su.whatsappService == nil {
-		return nil, errors.New("whatsapp service not configured")
+	if su.notifier == nil {
+		return nil, domain.ErrNoNotificationChannel
 	}
 
 	form.Phone = userutil.NormalizePhone(form.Phone)
@@ -122,18 +128,20 @@ func (su *SignupUsecase) SendWhatsAppOTP(ctx context.Context, form *domain.Signu
 		return nil, err
 	}
 
-	log.Printf("Sending WhatsApp OTP to %s", form.Phone)
-	phoneE164 := userutil.FormatPhoneE164(form.Phone)
-	if phoneE164 == "" {
-		return nil, errors.New("invalid phone number for WhatsApp")
-	}
-
-	if err := su.whatsappService.SendOTP(ctx, phoneE164, otp.Code); err != nil {
-		log.Printf("Failed to send WhatsApp OTP: %v", err)
-		return nil, fmt.Errorf("failed to send WhatsApp OTP: %w", err)
+	channel, err := su.notifier.Deliver(ctx, &domain.OTPNotification{
+		Phone:     otp.Phone,
+		Code:      otp.Code,
+		Language:  otp.Language,
+		Purpose:   domain.PurposeSignupOTP,
+		ExpiresAt: otp.ExpiresAt,
+	}, otp.Channel)
+	if err != nil {
+		logger.Error("failed to send signup OTP", "phone", form.Phone, "error", err)
+		return nil, fmt.Errorf("failed to send signup OTP: %w", err)
 	}
 
-	log.Printf("WhatsApp OTP sent successfully to %s", form.Phone)
+	logger.Info("signup OTP sent", "phone", form.Phone, "channel", channel)
+	otp.Channel = channel
 	return otp, nil
 }
 func (su *SignupUsecase) GetOtpByPhone(ctx context.Context, phone string) (*domain.OTP, error) {
@@ -159,8 +167,7 @@ func (su *SignupUsecase) VerifyOtp(ctx context.Context, otp *domain.VerifyOtp) (
 		}
 		return nil, err
 	}
-	fmt.Printf("in signup_usecase   Stored OTP********************************8: %+v\n", storedOTP)
-	if storedOTP.Code != otp.Code {
+	if subtle.ConstantTimeCompare([]byte(storedOTP.Code), []byte(otp.Code)) != 1 {
 		return nil, errors.New("invalid OTP")
 	}
 
@@ -186,23 +193,70 @@ func (su *SignupUsecase) generateAndStoreOTP(ctx context.Context, form *domain.S
 		return nil, errors.New("invalid phone number")
 	}
 
+	passwordHash, err := userutil.HashPassword(form.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	otp := domain.OTP{
 		FullName:     form.FullName,
 		Phone:        form.Phone,
 		Role:         form.Role,
 		FacilityName: form.FacilityName,
 		Code:         userutil.GenerateOTP(),
-		Password:     form.Password,
+		PasswordHash: passwordHash,
+		UseWhatsApp:  form.UseWhatsApp,
+		Channel:      domain.ResolveChannel(form.Channel, form.UseWhatsApp),
+		Language:     form.Language,
 		CreatedAt:    time.Now(),
-		ExpiresAt:    time.Now().Add(5 * time.Minute),
+		ExpiresAt:    time.Now().Add(signupOTPLifetime),
 	}
 
-	log.Printf("Generated signup OTP for phone %s", otp.Phone)
+	logger.Info("generated signup OTP", "phone", otp.Phone)
 
 	if err := su.otpRepo.SaveOTP(ctx, &otp); err != nil {
-		log.Printf("Error saving OTP: %v", err)
+		logger.Error("failed to save signup OTP", "phone", otp.Phone, "error", err)
 		return nil, err
 	}
 
 	return &otp, nil
 }
+
+func (su *SignupUsecase) CompleteSignup(ctx context.Context, otp *domain.VerifyOtp) (uuid.UUID, error) {
+	pending, err := su.VerifyOtp(ctx, otp)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, su.contextTimeout)
+	defer cancel()
+
+	professional := domain.MedicalProfessional{
+		FullName:     pending.FullName,
+		Phone:        pending.Phone,
+		PasswordHash: pending.PasswordHash,
+		Role:         pending.Role,
+		UseWhatsApp:  pending.UseWhatsApp,
+		FacilityName: pending.FacilityName,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+
+		NotificationChannel:  domain.ResolveChannel(pending.Channel, pending.UseWhatsApp),
+		NotificationLanguage: pending.Language,
+	}
+
+	if err := su.medicalProfessionalRepo.Create(ctx, &professional); err != nil {
+		return uuid.Nil, err
+	}
+
+	logger.Info("signup completed", "phone", professional.Phone, "medical_professional_id", professional.ID)
+	return professional.ID, nil
+}
+
+// PurgeExpiredSignups removes pending signups that were never verified.
+func (su *SignupUsecase) PurgeExpiredSignups(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, su.contextTimeout)
+	defer cancel()
+
+	return su.otpRepo.PurgeExpired(ctx)
+}
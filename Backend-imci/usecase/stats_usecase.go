@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxStatsRange bounds a dashboard query to a year of assessments.
+	maxStatsRange = 366 * 24 * time.Hour
+	// followUpGraceDays is how late a follow-up visit may be and still count.
+	followUpGraceDays = 3
+	// topConditionsPerAgeGroup is how many conditions each age group lists.
+	topConditionsPerAgeGroup = 5
+)
+
+// statsColorOrder lists the classification colours by severity.
+var statsColorOrder = []string{"pink", "yellow", "green"}
+
+type StatsUsecase struct {
+	statsRepo      domain.StatsRepository
+	userRepo       domain.MedicalProfessionalRepository
+	contextTimeout time.Duration
+}
+
+func NewStatsUsecase(statsRepo domain.StatsRepository, userRepo domain.MedicalProfessionalRepository, timeout time.Duration) domain.StatsUsecase {
+	return &StatsUsecase{
+		statsRepo:      statsRepo,
+		userRepo:       userRepo,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *StatsUsecase) GetFacilityStats(ctx context.Context, medicalProfessionalID uuid.UUID, isAdmin bool, facility string, from, to time.Time) (*domain.FacilityStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if err := validateStatsRange(from, to); err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		professional, err := uc.userRepo.GetByID(ctx, medicalProfessionalID)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(professional.FacilityName) == "" {
+			return nil, domain.ErrNoFacility
+		}
+		facility = professional.FacilityName
+	}
+
+	filter := domain.StatsFilter{Facility: strings.TrimSpace(facility), From: from, To: to}
+
+	days, err := uc.statsRepo.AssessmentsPerDay(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := uc.statsRepo.ClassificationCounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	referrals, err := uc.statsRepo.Referrals(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	followUp, err := uc.statsRepo.FollowUpCompliance(ctx, filter, time.Now(), followUpGraceDays)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := uc.statsRepo.AssessmentDuration(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	referrals.Rate = statsRate(referrals.Referred, referrals.Emergencies)
+	followUp.Rate = statsRate(followUp.Attended, followUp.Due)
+
+	stats := &domain.FacilityStats{
+		Facility:                   filter.Facility,
+		From:                       from,
+		To:                         to,
+		AssessmentsPerDay:          fillStatsDays(days, from, to),
+		ClassificationsByColor:     countByColor(counts),
+		ClassificationDistribution: countByClassification(counts),
+		Referrals:                  referrals,
+		FollowUp:                   followUp,
+		Duration:                   duration,
+		TopConditions:              topConditionsByAgeGroup(counts, topConditionsPerAgeGroup),
+	}
+	for _, day := range stats.AssessmentsPerDay {
+		stats.TotalAssessments += day.Count
+	}
+
+	return stats, nil
+}
+
+func (uc *StatsUsecase) ListFacilities(ctx context.Context, from, to time.Time) ([]*domain.FacilitySummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if err := validateStatsRange(from, to); err != nil {
+		return nil, err
+	}
+	return uc.statsRepo.ListFacilities(ctx, from, to)
+}
+
+func validateStatsRange(from, to time.Time) error {
+	if !to.After(from) || to.Sub(from) > maxStatsRange {
+		return domain.ErrInvalidStatsRange
+	}
+	return nil
+}
+
+func statsRate(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
+
+// fillStatsDays returns one entry per day in [from, to), with zero for days
+// without assessments, so the dashboard can plot the series directly.
+func fillStatsDays(days []*domain.DailyCount, from, to time.Time) []*domain.DailyCount {
+	byDate := make(map[string]int, len(days))
+	for _, day := range days {
+		byDate[day.Date] = day.Count
+	}
+
+	filled := []*domain.DailyCount{}
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		filled = append(filled, &domain.DailyCount{Date: date, Count: byDate[date]})
+	}
+	return filled
+}
+
+func countByColor(counts []*domain.ClassificationCount) []*domain.ColorCount {
+	totals := make(map[string]int)
+	for _, c := range counts {
+		totals[strings.ToLower(c.Color)] += c.Count
+	}
+
+	colors := []*domain.ColorCount{}
+	for _, color := range statsColorOrder {
+		colors = append(colors, &domain.ColorCount{Color: color, Count: totals[color]})
+		delete(totals, color)
+	}
+
+	others := make([]string, 0, len(totals))
+	for color := range totals {
+		others = append(others, color)
+	}
+	sort.Strings(others)
+	for _, color := range others {
+		colors = append(colors, &domain.ColorCount{Color: color, Count: totals[color]})
+	}
+	return colors
+}
+
+// countByClassification merges the age groups of each classification,
+// most frequent first.
+func countByClassification(counts []*domain.ClassificationCount) []*domain.ClassificationCount {
+	type key struct{ classification, color string }
+	totals := make(map[key]*domain.ClassificationCount)
+	merged := []*domain.ClassificationCount{}
+
+	for _, c := range counts {
+		k := key{c.Classification, c.Color}
+		total, ok := totals[k]
+		if !ok {
+			total = &domain.ClassificationCount{Classification: c.Classification, Color: c.Color}
+			totals[k] = total
+			merged = append(merged, total)
+		}
+		total.Count += c.Count
+	}
+
+	sortClassificationCounts(merged)
+	return merged
+}
+
+func topConditionsByAgeGroup(counts []*domain.ClassificationCount, limit int) []*domain.AgeGroupConditions {
+	byGroup := make(map[string][]*domain.ClassificationCount)
+	for _, c := range counts {
+		byGroup[c.AgeGroup] = append(byGroup[c.AgeGroup], c)
+	}
+
+	groups := make([]string, 0, len(byGroup))
+	for group := range byGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	top := []*domain.AgeGroupConditions{}
+	for _, group := range groups {
+		conditions := byGroup[group]
+		sortClassificationCounts(conditions)
+		if len(conditions) > limit {
+			conditions = conditions[:limit]
+		}
+		top = append(top, &domain.AgeGroupConditions{AgeGroup: group, Conditions: conditions})
+	}
+	return top
+}
+
+func sortClassificationCounts(counts []*domain.ClassificationCount) {
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Classification < counts[j].Classification
+	})
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeStatsRepo struct {
+	domain.StatsRepository
+	filter domain.StatsFilter
+	days   []*domain.DailyCount
+	counts []*domain.ClassificationCount
+}
+
+func (f *fakeStatsRepo) AssessmentsPerDay(ctx context.Context, filter domain.StatsFilter) ([]*domain.DailyCount, error) {
+	f.filter = filter
+	return f.days, nil
+}
+
+func (f *fakeStatsRepo) ClassificationCounts(ctx context.Context, filter domain.StatsFilter) ([]*domain.ClassificationCount, error) {
+	return f.counts, nil
+}
+
+func (f *fakeStatsRepo) Referrals(ctx context.Context, filter domain.StatsFilter) (*domain.ReferralStats, error) {
+	return &domain.ReferralStats{Emergencies: 4, Referred: 3}, nil
+}
+
+func (f *fakeStatsRepo) FollowUpCompliance(ctx context.Context, filter domain.StatsFilter, now time.Time, graceDays int) (*domain.FollowUpCompliance, error) {
+	return &domain.FollowUpCompliance{Due: 0, GraceDays: graceDays}, nil
+}
+
+func (f *fakeStatsRepo) AssessmentDuration(ctx context.Context, filter domain.StatsFilter) (*domain.AssessmentDuration, error) {
+	return &domain.AssessmentDuration{}, nil
+}
+
+type fakeStatsUserRepo struct {
+	domain.MedicalProfessionalRepository
+	professional *domain.MedicalProfessional
+}
+
+func (f *fakeStatsUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.MedicalProfessional, error) {
+	return f.professional, nil
+}
+
+func TestGetFacilityStats(t *testing.T) {
+	repo := &fakeStatsRepo{
+		days: []*domain.DailyCount{{Date: "2025-03-02", Count: 5}},
+		counts: []*domain.ClassificationCount{
+			{Classification: "PNEUMONIA", Color: "yellow", AgeGroup: "child", Count: 6},
+			{Classification: "NO PNEUMONIA: COUGH OR COLD", Color: "green", AgeGroup: "child", Count: 4},
+			{Classification: "PNEUMONIA", Color: "yellow", AgeGroup: "young_infant", Count: 1},
+			{Classification: "VERY SEVERE DISEASE", Color: "Pink", AgeGroup: "young_infant", Count: 2},
+		},
+	}
+	users := &fakeStatsUserRepo{professional: &domain.MedicalProfessional{FacilityName: "Bahir Dar HC"}}
+	uc := NewStatsUsecase(repo, users, time.Second)
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+	stats, err := uc.GetFacilityStats(context.Background(), uuid.New(), false, "Other HC", from, to)
+	if err != nil {
+		t.Fatalf("GetFacilityStats: %v", err)
+	}
+
+	if repo.filter.Facility != "Bahir Dar HC" {
+		t.Errorf("clinician should only see their own facility, got %q", repo.filter.Facility)
+	}
+	if len(stats.AssessmentsPerDay) != 3 || stats.AssessmentsPerDay[0].Count != 0 || stats.AssessmentsPerDay[1].Count != 5 {
+		t.Errorf("unexpected daily series: %+v", stats.AssessmentsPerDay)
+	}
+	if stats.TotalAssessments != 5 {
+		t.Errorf("total = %d, want 5", stats.TotalAssessments)
+	}
+
+	colors := map[string]int{}
+	for _, c := range stats.ClassificationsByColor {
+		colors[c.Color] = c.Count
+	}
+	if colors["pink"] != 2 || colors["yellow"] != 7 || colors["green"] != 4 {
+		t.Errorf("unexpected colour counts: %v", colors)
+	}
+	if first := stats.ClassificationDistribution[0]; first.Classification != "PNEUMONIA" || first.Count != 7 {
+		t.Errorf("most frequent classification = %+v", first)
+	}
+	if stats.Referrals.Rate != 0.75 {
+		t.Errorf("referral rate = %v, want 0.75", stats.Referrals.Rate)
+	}
+	if stats.FollowUp.Rate != 0 {
+		t.Errorf("follow-up rate without due follow-ups = %v, want 0", stats.FollowUp.Rate)
+	}
+
+	if len(stats.TopConditions) != 2 || stats.TopConditions[1].AgeGroup != "young_infant" {
+		t.Fatalf("unexpected age groups: %+v", stats.TopConditions)
+	}
+	if top := stats.TopConditions[1].Conditions[0]; top.Classification != "VERY SEVERE DISEASE" {
+		t.Errorf("top young infant condition = %q", top.Classification)
+	}
+}
+
+func TestGetFacilityStatsScope(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := &fakeStatsRepo{}
+	uc := NewStatsUsecase(repo, &fakeStatsUserRepo{professional: &domain.MedicalProfessional{}}, time.Second)
+
+	if _, err := uc.GetFacilityStats(context.Background(), uuid.New(), false, "", from, from.AddDate(0, 0, 7)); !errors.Is(err, domain.ErrNoFacility) {
+		t.Errorf("clinician without facility: got %v, want ErrNoFacility", err)
+	}
+	if _, err := uc.GetFacilityStats(context.Background(), uuid.New(), true, "", from, from.AddDate(2, 0, 0)); !errors.Is(err, domain.ErrInvalidStatsRange) {
+		t.Errorf("two-year range: got %v, want ErrInvalidStatsRange", err)
+	}
+	if _, err := uc.GetFacilityStats(context.Background(), uuid.New(), true, " Gondar HC ", from, from.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("admin stats: %v", err)
+	}
+	if repo.filter.Facility != "Gondar HC" {
+		t.Errorf("admin facility = %q, want Gondar HC", repo.filter.Facility)
+	}
+}
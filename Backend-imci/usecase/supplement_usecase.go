@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/epi"
+	"github.com/google/uuid"
+)
+
+type SupplementUsecase struct {
+	supplementRepo domain.SupplementRepository
+	patientRepo    domain.PatientRepository
+	contextTimeout time.Duration
+}
+
+func NewSupplementUsecase(
+	supplementRepo domain.SupplementRepository,
+	patientRepo domain.PatientRepository,
+	timeout time.Duration,
+) domain.SupplementUsecase {
+	return &SupplementUsecase{
+		supplementRepo: supplementRepo,
+		patientRepo:    patientRepo,
+		contextTimeout: timeout,
+	}
+}
+
+func (uc *SupplementUsecase) RecordDose(ctx context.Context, patientID uuid.UUID, req *domain.RecordSupplementRequest, medicalProfessionalID uuid.UUID) (*domain.SupplementRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	patient, err := uc.patientRepo.GetByID(ctx, patientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	if req.DoseDate.Before(truncateToDay(patient.DateOfBirth)) || req.DoseDate.After(time.Now()) {
+		return nil, domain.ErrInvalidSupplementDate
+	}
+
+	dose := req.Dose
+	if dose == "" {
+		eligibility, err := epi.EvaluateSupplement(epi.Supplement(req.Supplement), patient.DateOfBirth, nil, req.DoseDate)
+		if err != nil {
+			return nil, domain.ErrUnknownSupplement
+		}
+		dose = eligibility.Dosage
+	}
+
+	record := &domain.SupplementRecord{
+		ID:         uuid.New(),
+		PatientID:  patientID,
+		Supplement: req.Supplement,
+		Dose:       dose,
+		DoseDate:   req.DoseDate,
+		Facility:   req.Facility,
+		RecordedBy: &medicalProfessionalID,
+	}
+
+	if err := uc.supplementRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (uc *SupplementUsecase) GetRecords(ctx context.Context, patientID uuid.UUID) ([]*domain.SupplementRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.patientRepo.GetByID(ctx, patientID); err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	return uc.supplementRepo.GetByPatientID(ctx, patientID)
+}
+
+func (uc *SupplementUsecase) DeleteRecord(ctx context.Context, patientID uuid.UUID, recordID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.supplementRepo.Delete(ctx, recordID, patientID)
+}
+
+func (uc *SupplementUsecase) GetStatus(ctx context.Context, patientID uuid.UUID, asOf time.Time) (*domain.SupplementStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	return uc.status(ctx, patientID, asOf)
+}
+
+func (uc *SupplementUsecase) status(ctx context.Context, patientID uuid.UUID, asOf time.Time) (*domain.SupplementStatus, error) {
+	patient, err := uc.patientRepo.GetByID(ctx, patientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	records, err := uc.supplementRepo.GetByPatientID(ctx, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastDoses := make(map[epi.Supplement]*time.Time)
+	for _, r := range records {
+		if r.DoseDate.After(asOf) {
+			continue
+		}
+		s := epi.Supplement(r.Supplement)
+		if last, ok := lastDoses[s]; !ok || r.DoseDate.After(*last) {
+			doseDate := r.DoseDate
+			lastDoses[s] = &doseDate
+		}
+	}
+
+	status := &domain.SupplementStatus{
+		PatientID: patientID,
+		AsOf:      truncateToDay(asOf),
+	}
+
+	for _, s := range []epi.Supplement{epi.VitaminA, epi.Deworming} {
+		e, err := epi.EvaluateSupplement(s, patient.DateOfBirth, lastDoses[s], asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		eligibility := &domain.SupplementEligibility{
+			Supplement:          string(e.Supplement),
+			Eligible:            e.Eligible,
+			Due:                 e.Due,
+			LastDoseDate:        e.LastDose,
+			NextDueDate:         e.NextDueDate,
+			Drug:                e.Drug,
+			RecommendedDose:     e.Dosage,
+			AdministrationRoute: e.Route,
+		}
+
+		switch s {
+		case epi.VitaminA:
+			status.VitaminA = eligibility
+		case epi.Deworming:
+			status.Deworming = eligibility
+		}
+	}
+
+	return status, nil
+}
+
+// supplementTreeNodes maps the immunization tree's supplement questions to
+// the supplement they ask about.
+var supplementTreeNodes = map[string]func(*domain.SupplementStatus) *domain.SupplementEligibility{
+	"vitamin_a_last_6months": func(s *domain.SupplementStatus) *domain.SupplementEligibility { return s.VitaminA },
+	"deworming_last_6months": func(s *domain.SupplementStatus) *domain.SupplementEligibility { return s.Deworming },
+}
+
+// ProvideAnswers answers the Vitamin A and deworming questions of the
+// immunization tree from the recorded dose history. A child with no recorded
+// dose in the last six months is treated as not having received one.
+func (uc *SupplementUsecase) ProvideAnswers(ctx context.Context, assessment *domain.Assessment, treeID string) (map[string]interface{}, error) {
+	if treeID != "immunization_vitamin_status" {
+		return nil, nil
+	}
+
+	asOf := assessment.StartTime
+	if asOf.IsZero() {
+		asOf = assessment.CreatedAt
+	}
+
+	status, err := uc.status(ctx, assessment.PatientID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make(map[string]interface{})
+	for nodeID, pick := range supplementTreeNodes {
+		eligibility := pick(status)
+		switch {
+		case !eligibility.Eligible:
+			answers[nodeID] = "not_applicable"
+		case eligibility.Due:
+			answers[nodeID] = "not_received"
+		default:
+			answers[nodeID] = "received"
+		}
+	}
+
+	return answers, nil
+}
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/counseling"
+	"github.com/Afomiat/Digital-IMCI/internal/dosing"
+	"github.com/Afomiat/Digital-IMCI/internal/pdf"
+	"github.com/Afomiat/Digital-IMCI/internal/prescribing"
+	"github.com/google/uuid"
+)
+
+type VisitSummaryUsecase struct {
+	assessmentRepo     domain.AssessmentRepository
+	patientRepo        domain.PatientRepository
+	mpRepo             domain.MedicalProfessionalRepository
+	classificationRepo domain.ClassificationRepository
+	treatmentPlanRepo  domain.TreatmentPlanRepository
+	counselingRepo     domain.CounselingRepository
+	font               *pdf.Font
+	contextTimeout     time.Duration
+}
+
+func NewVisitSummaryUsecase(
+	assessmentRepo domain.AssessmentRepository,
+	patientRepo domain.PatientRepository,
+	mpRepo domain.MedicalProfessionalRepository,
+	classificationRepo domain.ClassificationRepository,
+	treatmentPlanRepo domain.TreatmentPlanRepository,
+	counselingRepo domain.CounselingRepository,
+	font *pdf.Font,
+	timeout time.Duration,
+) domain.VisitSummaryUsecase {
+	return &VisitSummaryUsecase{
+		assessmentRepo:     assessmentRepo,
+		patientRepo:        patientRepo,
+		mpRepo:             mpRepo,
+		classificationRepo: classificationRepo,
+		treatmentPlanRepo:  treatmentPlanRepo,
+		counselingRepo:     counselingRepo,
+		font:               font,
+		contextTimeout:     timeout,
+	}
+}
+
+func (uc *VisitSummaryUsecase) GetVisitSummary(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) (*domain.VisitSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	assessment, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	patient, err := uc.patientRepo.GetByID(ctx, assessment.PatientID)
+	if err != nil {
+		return nil, domain.ErrPatientNotFound
+	}
+
+	classifications, err := uc.classificationRepo.ListByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := uc.treatmentPlanRepo.GetByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	counseling, err := uc.counselingRepo.GetByAssessmentID(ctx, assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.VisitSummary{
+		Assessment:      assessment,
+		Patient:         patient,
+		Classifications: classifications,
+		TreatmentPlans:  plans,
+		Counseling:      counseling,
+		GeneratedAt:     time.Now(),
+	}
+
+	// The clinician is only shown on the printout, so a lookup failure is
+	// not worth failing the summary for.
+	if mp, err := uc.mpRepo.GetByID(ctx, medicalProfessionalID); err == nil {
+		summary.Clinician = mp.FullName
+		summary.Facility = mp.FacilityName
+	}
+
+	summary.Language = "en"
+	for _, c := range counseling {
+		if c.AdviceType == domain.AdviceMotherAdvice && c.Language != "" {
+			summary.Language = c.Language
+			break
+		}
+	}
+
+	for _, c := range classifications {
+		if c.FollowUpDate != nil && (summary.FollowUpDate == nil || c.FollowUpDate.Before(*summary.FollowUpDate)) {
+			summary.FollowUpDate = c.FollowUpDate
+		}
+	}
+
+	return summary, nil
+}
+
+func (uc *VisitSummaryUsecase) RenderPDF(ctx context.Context, assessmentID uuid.UUID, medicalProfessionalID uuid.UUID) ([]byte, error) {
+	summary, err := uc.GetVisitSummary(ctx, assessmentID, medicalProfessionalID)
+	if err != nil {
+		return nil, err
+	}
+	return renderVisitSummary(summary, uc.font), nil
+}
+
+// Classification colours on the printout, light enough for black text.
+var classificationColors = map[string]pdf.Color{
+	"pink":   {R: 0.97, G: 0.73, B: 0.82},
+	"yellow": {R: 1.00, G: 0.92, B: 0.50},
+	"green":  {R: 0.72, G: 0.89, B: 0.70},
+}
+
+var unknownClassificationColor = pdf.Color{R: 0.85, G: 0.85, B: 0.85}
+
+// renderVisitSummary prints the summary, setting text outside Latin-1 in
+// font when one is configured.
+func renderVisitSummary(s *domain.VisitSummary, font *pdf.Font) []byte {
+	doc := pdf.NewWithFont(font)
+	doc.Title("IMCI visit summary")
+	doc.Field("Facility", s.Facility)
+	doc.Field("Clinician", s.Clinician)
+	doc.Field("Visit date", s.Assessment.StartTime.Format("2 Jan 2006"))
+
+	doc.Heading("Patient")
+	doc.Field("Name", s.Patient.Name)
+	doc.Field("Date of birth", s.Patient.DateOfBirth.Format("2 Jan 2006"))
+	doc.Field("Sex", string(s.Patient.Gender))
+
+	doc.Heading("Vitals")
+	renderVitals(doc, s.Assessment)
+
+	plans := make(map[uuid.UUID][]*domain.TreatmentPlan)
+	for _, p := range s.TreatmentPlans {
+		plans[p.ClassificationID] = append(plans[p.ClassificationID], p)
+	}
+	advice := make(map[uuid.UUID][]*domain.Counseling)
+	var otherAdvice []*domain.Counseling
+	// given keeps the general home-care advice to once per visit.
+	given := map[string]bool{}
+	for _, c := range s.Counseling {
+		if c.IsStructured() {
+			given[c.AdviceType] = true
+		}
+		if c.ClassificationID == nil {
+			otherAdvice = append(otherAdvice, c)
+			continue
+		}
+		advice[*c.ClassificationID] = append(advice[*c.ClassificationID], c)
+	}
+
+	// Advice in a script the fonts cannot show is left out rather than
+	// printed as question marks; the English cards still cover it.
+	unprintable := false
+	youngInfant := s.Assessment.AssessmentType == domain.TypeYoungInfant
+
+	doc.Heading("Classifications")
+	if len(s.Classifications) == 0 {
+		doc.Text("No classification recorded.")
+	}
+	for _, c := range s.Classifications {
+		title := c.Disease
+		if c.RequiresUrgentReferral {
+			title += " - URGENT REFERRAL"
+		}
+		color, ok := classificationColors[strings.ToLower(c.Color)]
+		if !ok {
+			color = unknownClassificationColor
+		}
+		doc.Banner(title, color)
+
+		if len(plans[c.ID]) > 0 {
+			doc.Bold("Treatment")
+			for _, p := range plans[c.ID] {
+				doc.Bullet(treatmentLine(p, s.Assessment))
+			}
+		}
+
+		var homeCare []string
+		structured := false
+		for _, a := range advice[c.ID] {
+			if a.IsStructured() {
+				structured = true
+			}
+			if !doc.Supports(a.Details) {
+				unprintable = true
+				continue
+			}
+			homeCare = append(homeCare, a.Details)
+		}
+		// Visits classified before cards were saved with the classification
+		// have none; print them anyway so the caregiver leaves with the
+		// home-care advice.
+		if !structured {
+			for _, item := range counseling.VisitCards(c.Disease, c.Color, youngInfant, given) {
+				homeCare = append(homeCare, item.Text)
+			}
+		}
+		if len(homeCare) > 0 {
+			doc.Bold("Home care")
+			for _, line := range homeCare {
+				doc.Bullet(line)
+			}
+		}
+	}
+
+	// Treatments and advice from before they were linked to a classification.
+	var unlinked []*domain.TreatmentPlan
+	for _, p := range s.TreatmentPlans {
+		if !hasClassification(s.Classifications, p.ClassificationID) {
+			unlinked = append(unlinked, p)
+		}
+	}
+	if len(unlinked) > 0 {
+		doc.Heading("Treatment")
+		for _, p := range unlinked {
+			doc.Bullet(treatmentLine(p, s.Assessment))
+		}
+	}
+	if len(otherAdvice) > 0 {
+		doc.Heading("Advice for the caregiver")
+		for _, c := range otherAdvice {
+			if !doc.Supports(c.Details) {
+				unprintable = true
+				continue
+			}
+			doc.Bullet(c.Details)
+		}
+	}
+	if unprintable {
+		doc.Space(4)
+		doc.Text("Some advice was given in a script this printout cannot show. Ask the health worker to read it to you from the app.")
+	}
+
+	if s.FollowUpDate != nil {
+		doc.Heading("Follow-up")
+		doc.Bold("Return on " + s.FollowUpDate.Format("Monday 2 January 2006"))
+	}
+
+	return doc.Bytes()
+}
+
+func renderVitals(doc *pdf.Document, a *domain.Assessment) {
+	doc.Field("Weight", fmt.Sprintf("%.1f kg", a.WeightKg))
+	if a.Temperature != nil {
+		doc.Field("Temperature", fmt.Sprintf("%.1f C", *a.Temperature))
+	}
+	if a.RespiratoryRate != nil {
+		doc.Field("Respiratory rate", fmt.Sprintf("%d breaths/min", *a.RespiratoryRate))
+	}
+	if a.OxygenSaturation != nil {
+		doc.Field("Oxygen saturation", fmt.Sprintf("%d%%", *a.OxygenSaturation))
+	}
+	if a.MUAC != nil {
+		doc.Field("MUAC", fmt.Sprintf("%.1f cm", *a.MUAC))
+	}
+	if a.LengthCm != nil {
+		doc.Field("Length/height", fmt.Sprintf("%.1f cm", *a.LengthCm))
+	}
+	if a.HeadCircumferenceCm != nil {
+		doc.Field("Head circumference", fmt.Sprintf("%.1f cm", *a.HeadCircumferenceCm))
+	}
+	if a.HbLevel != nil {
+		doc.Field("Haemoglobin", fmt.Sprintf("%.1f g/dL", *a.HbLevel))
+	}
+	if a.BilateralEdema {
+		doc.Field("Oedema of both feet", "yes")
+	}
+}
+
+// treatmentLine describes a treatment. Drugs in the IMCI dosing table are
+// given the dose for the child's weight and age; others keep the plan's
+// dosage.
+func treatmentLine(p *domain.TreatmentPlan, a *domain.Assessment) string {
+	dosage := p.Dosage
+	if drug, ok := prescribing.DefaultDrugTable.MatchDrug(p.DrugName); ok {
+		if dose, ok := dosing.For(drug.Key, a.WeightKg, a.AgeMonths); ok {
+			dosage = dose
+		}
+	}
+	line := strings.Join(nonEmpty(p.DrugName, dosage, p.AdministrationRoute, p.Frequency, p.Duration), ", ")
+	if p.IsPreReferral {
+		line += " (before referral)"
+	}
+	if p.Instructions != "" {
+		line += ". " + p.Instructions
+	}
+	return line
+}
+
+func hasClassification(classifications []*domain.Classification, id uuid.UUID) bool {
+	for _, c := range classifications {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func nonEmpty(values ...string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+func TestRenderVisitSummary(t *testing.T) {
+	pneumonia := &domain.Classification{ID: uuid.New(), Disease: "PNEUMONIA", Color: "Yellow"}
+	severe := &domain.Classification{ID: uuid.New(), Disease: "VERY SEVERE DISEASE", Color: "pink", RequiresUrgentReferral: true}
+	followUp := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	out := renderVisitSummary(&domain.VisitSummary{
+		Assessment:      &domain.Assessment{AssessmentType: domain.TypeChild, WeightKg: 11.2, AgeMonths: 20},
+		Patient:         &domain.Patient{Name: "Abebe"},
+		Classifications: []*domain.Classification{severe, pneumonia},
+		TreatmentPlans: []*domain.TreatmentPlan{{
+			ClassificationID: pneumonia.ID,
+			DrugName:         "Amoxicillin",
+			Dosage:           "Based on weight",
+			Frequency:        "twice daily",
+			Duration:         "5 days",
+		}},
+		Counseling: []*domain.Counseling{{
+			ClassificationID: &pneumonia.ID,
+			AdviceType:       domain.AdviceMotherAdvice,
+			Details:          "ፈሳሽ ይስጡ",
+			Language:         "am",
+		}},
+		FollowUpDate: &followUp,
+		Language:     "am",
+	}, nil)
+
+	for _, want := range []string{
+		"0.970 0.730 0.820 rg",
+		"1.000 0.920 0.500 rg",
+		"(VERY SEVERE DISEASE - URGENT REFERRAL) Tj",
+		"(- Amoxicillin, 3 tablets \\(750 mg\\), twice daily, 5 days) Tj",
+		"(Return on Thursday 5 March 2026) Tj",
+		"cannot show",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("summary is missing %q", want)
+		}
+	}
+	if bytes.Contains(out, []byte("???")) {
+		t.Error("Ge'ez advice was printed as question marks")
+	}
+	// Without generated counselling items the English cards are printed.
+	if !bytes.Contains(out, []byte("(Home care) Tj")) {
+		t.Error("home care advice missing")
+	}
+}
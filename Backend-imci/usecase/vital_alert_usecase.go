@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/vitals"
+	"github.com/google/uuid"
+)
+
+type VitalAlertUsecase struct {
+	vitalAlertRepo     domain.VitalAlertRepository
+	assessmentRepo     domain.AssessmentRepository
+	classificationRepo domain.ClassificationRepository
+	patientRepo        domain.PatientRepository
+	userRepo           domain.MedicalProfessionalRepository
+	notifier           domain.NotificationDispatcher
+	auditor            domain.AuditRecorder
+	rules              []vitals.Threshold
+	contextTimeout     time.Duration
+
+	// dispatch runs a notification outside the request; notifying holds the
+	// alerts being sent so a repeat evaluation does not send them twice.
+	dispatch  func(func())
+	notifying sync.Map
+}
+
+func NewVitalAlertUsecase(
+	vitalAlertRepo domain.VitalAlertRepository,
+	assessmentRepo domain.AssessmentRepository,
+	classificationRepo domain.ClassificationRepository,
+	patientRepo domain.PatientRepository,
+	userRepo domain.MedicalProfessionalRepository,
+	notifier domain.NotificationDispatcher,
+	auditor domain.AuditRecorder,
+	rules []vitals.Threshold,
+	timeout time.Duration,
+) domain.VitalAlertUsecase {
+	return &VitalAlertUsecase{
+		vitalAlertRepo:     vitalAlertRepo,
+		assessmentRepo:     assessmentRepo,
+		classificationRepo: classificationRepo,
+		patientRepo:        patientRepo,
+		userRepo:           userRepo,
+		notifier:           notifier,
+		auditor:            auditor,
+		rules:              rules,
+		contextTimeout:     timeout,
+		dispatch:           func(f func()) { go f() },
+	}
+}
+
+func (uc *VitalAlertUsecase) Check(assessment *domain.Assessment) []*domain.VitalAlert {
+	alerts := vitals.Evaluate(uc.rules, assessment.AssessmentType, assessment.AgeMonths, vitals.FromAssessment(assessment))
+	for _, alert := range alerts {
+		alert.AssessmentID = assessment.ID
+	}
+	return alerts
+}
+
+func (uc *VitalAlertUsecase) Evaluate(ctx context.Context, assessment *domain.Assessment, answers map[string]interface{}) ([]*domain.VitalAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	classifications, err := uc.classificationRepo.ListByAssessmentID(ctx, assessment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *assessment
+	changed := vitals.FromAnswers(answers).SetOn(assessment)
+	alerts := uc.Check(assessment)
+	if vitals.SetFlags(assessment, alerts, classifications) {
+		changed = true
+	}
+	if changed {
+		if err := uc.assessmentRepo.Update(ctx, assessment); err != nil {
+			return nil, fmt.Errorf("failed to save assessment vitals: %w", err)
+		}
+		if err := uc.auditor.Record(ctx, domain.AuditUpdate, domain.AuditAssessment, assessment.ID, &before, assessment); err != nil {
+			return nil, err
+		}
+	}
+
+	codes := make([]string, len(alerts))
+	for i, alert := range alerts {
+		codes[i] = alert.Code
+	}
+	if err := uc.vitalAlertRepo.DeleteExcept(ctx, assessment.ID, codes); err != nil {
+		return nil, err
+	}
+
+	for _, alert := range alerts {
+		if _, err := uc.vitalAlertRepo.Save(ctx, alert); err != nil {
+			return nil, err
+		}
+		if alert.Severity == domain.VitalEmergency && alert.NotifiedAt == nil {
+			uc.notifyInBackground(ctx, assessment, alert)
+		}
+	}
+
+	assessment.VitalAlerts = alerts
+	return alerts, nil
+}
+
+// notifyInBackground notifies an emergency without holding up the request.
+// It works on copies, as the caller keeps using the assessment and alert.
+func (uc *VitalAlertUsecase) notifyInBackground(ctx context.Context, assessment *domain.Assessment, alert *domain.VitalAlert) {
+	if uc.notifier == nil {
+		return
+	}
+	if _, sending := uc.notifying.LoadOrStore(alert.ID, true); sending {
+		return
+	}
+
+	clinicianID, patientID, copied := assessment.MedicalProfessionalID, assessment.PatientID, *alert
+	ctx = context.WithoutCancel(ctx)
+	uc.dispatch(func() {
+		defer uc.notifying.Delete(copied.ID)
+
+		ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+		defer cancel()
+		uc.notify(ctx, clinicianID, patientID, &copied)
+	})
+}
+
+// notify sends an emergency to the assessing clinician and the supervisors
+// of their facility. The alert is marked notified once anyone is reached, so
+// a failed push is retried on the next evaluation.
+func (uc *VitalAlertUsecase) notify(ctx context.Context, clinicianID, patientID uuid.UUID, alert *domain.VitalAlert) {
+	recipients, err := uc.recipients(ctx, clinicianID)
+	if err != nil {
+		logger.Error("failed to resolve vital alert recipients", "alert_id", alert.ID, "error", err)
+		return
+	}
+
+	patientName := "patient"
+	if patient, err := uc.patientRepo.GetByID(ctx, patientID); err == nil {
+		patientName = patient.Name
+	}
+	text := vitals.Text(patientName, alert)
+
+	notified := false
+	for _, recipient := range recipients {
+		_, err := uc.notifier.Deliver(ctx, &domain.OTPNotification{
+			Phone:    recipient.Phone,
+			Text:     text,
+			Language: recipient.NotificationLanguage,
+			Purpose:  domain.PurposeClinicalAlert,
+		}, domain.ResolveChannel(recipient.NotificationChannel, recipient.UseWhatsApp))
+		if err != nil {
+			logger.Error("failed to send vital alert", "alert_id", alert.ID, "phone", recipient.Phone, "error", err)
+			continue
+		}
+		notified = true
+	}
+	if !notified {
+		return
+	}
+
+	if err := uc.vitalAlertRepo.MarkNotified(ctx, alert.ID, time.Now()); err != nil {
+		logger.Error("failed to mark vital alert notified", "alert_id", alert.ID, "error", err)
+	}
+}
+
+func (uc *VitalAlertUsecase) recipients(ctx context.Context, clinicianID uuid.UUID) ([]*domain.MedicalProfessional, error) {
+	clinician, err := uc.userRepo.GetByID(ctx, clinicianID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := []*domain.MedicalProfessional{clinician}
+	if clinician.FacilityName == "" {
+		return recipients, nil
+	}
+
+	supervisors, err := uc.userRepo.GetByFacilityAndRole(ctx, clinician.FacilityName, string(domain.SupervisorRole))
+	if err != nil {
+		return nil, err
+	}
+	for _, supervisor := range supervisors {
+		if supervisor.ID != clinician.ID {
+			recipients = append(recipients, supervisor)
+		}
+	}
+	return recipients, nil
+}
+
+func (uc *VitalAlertUsecase) History(ctx context.Context, assessmentID, medicalProfessionalID uuid.UUID) ([]*domain.VitalAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if _, err := uc.assessmentRepo.GetByID(ctx, assessmentID, medicalProfessionalID); err != nil {
+		return nil, err
+	}
+	return uc.vitalAlertRepo.ListByAssessmentID(ctx, assessmentID)
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/vitals"
+	"github.com/google/uuid"
+)
+
+type fakeVitalAlertRepo struct {
+	domain.VitalAlertRepository
+	alerts map[string]*domain.VitalAlert
+}
+
+func (f *fakeVitalAlertRepo) Save(ctx context.Context, alert *domain.VitalAlert) (bool, error) {
+	if stored, ok := f.alerts[alert.Code]; ok {
+		alert.ID, alert.NotifiedAt = stored.ID, stored.NotifiedAt
+		return false, nil
+	}
+	alert.ID = uuid.New()
+	copied := *alert
+	f.alerts[alert.Code] = &copied
+	return true, nil
+}
+
+func (f *fakeVitalAlertRepo) DeleteExcept(ctx context.Context, assessmentID uuid.UUID, codes []string) error {
+	for code := range f.alerts {
+		kept := false
+		for _, c := range codes {
+			kept = kept || c == code
+		}
+		if !kept {
+			delete(f.alerts, code)
+		}
+	}
+	return nil
+}
+
+func (f *fakeVitalAlertRepo) MarkNotified(ctx context.Context, id uuid.UUID, at time.Time) error {
+	for _, alert := range f.alerts {
+		if alert.ID == id {
+			alert.NotifiedAt = &at
+		}
+	}
+	return nil
+}
+
+type fakeVitalAssessmentRepo struct {
+	domain.AssessmentRepository
+	updates int
+}
+
+func (f *fakeVitalAssessmentRepo) Update(ctx context.Context, assessment *domain.Assessment) error {
+	f.updates++
+	return nil
+}
+
+type fakeVitalClassificationRepo struct {
+	domain.ClassificationRepository
+	classifications []*domain.Classification
+}
+
+func (f *fakeVitalClassificationRepo) ListByAssessmentID(ctx context.Context, assessmentID uuid.UUID) ([]*domain.Classification, error) {
+	return f.classifications, nil
+}
+
+type fakeVitalPatientRepo struct {
+	domain.PatientRepository
+}
+
+func (f *fakeVitalPatientRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Patient, error) {
+	return &domain.Patient{ID: id, Name: "Baby Almaz"}, nil
+}
+
+type fakeVitalUserRepo struct {
+	domain.MedicalProfessionalRepository
+	professionals []*domain.MedicalProfessional
+	queries       []string
+}
+
+func (f *fakeVitalUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.MedicalProfessional, error) {
+	for _, professional := range f.professionals {
+		if professional.ID == id {
+			return professional, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (f *fakeVitalUserRepo) GetByFacilityAndRole(ctx context.Context, facility string, role string) ([]*domain.MedicalProfessional, error) {
+	f.queries = append(f.queries, facility+"/"+role)
+	var matching []*domain.MedicalProfessional
+	for _, professional := range f.professionals {
+		if professional.FacilityName == facility && professional.Role == role {
+			matching = append(matching, professional)
+		}
+	}
+	return matching, nil
+}
+
+// syncDispatch sends notifications before Evaluate returns so tests can
+// check them.
+func syncDispatch(uc domain.VitalAlertUsecase) {
+	uc.(*VitalAlertUsecase).dispatch = func(f func()) { f() }
+}
+
+func TestVitalAlertEvaluateEmergency(t *testing.T) {
+	clinician := &domain.MedicalProfessional{ID: uuid.New(), Phone: "0911000001", Role: string(domain.NurseRole), FacilityName: "Dangila HC"}
+	supervisor := &domain.MedicalProfessional{ID: uuid.New(), Phone: "0911000002", Role: string(domain.SupervisorRole), FacilityName: "Dangila HC"}
+	elsewhere := &domain.MedicalProfessional{ID: uuid.New(), Phone: "0911000003", Role: string(domain.SupervisorRole), FacilityName: "Bahir Dar HC"}
+
+	repo := &fakeVitalAlertRepo{alerts: map[string]*domain.VitalAlert{}}
+	assessmentRepo := &fakeVitalAssessmentRepo{}
+	notifier := &fakeOutbreakNotifier{}
+	users := &fakeVitalUserRepo{professionals: []*domain.MedicalProfessional{clinician, supervisor, elsewhere}}
+	uc := NewVitalAlertUsecase(repo, assessmentRepo, &fakeVitalClassificationRepo{}, &fakeVitalPatientRepo{}, users,
+		notifier, &fakePrescriptionAuditor{}, vitals.DefaultThresholds, time.Second)
+	syncDispatch(uc)
+
+	assessment := &domain.Assessment{
+		ID:                    uuid.New(),
+		PatientID:             uuid.New(),
+		MedicalProfessionalID: clinician.ID,
+		AssessmentType:        domain.TypeYoungInfant,
+	}
+	answers := map[string]interface{}{"temperature_measurement": "35.0"}
+
+	alerts, err := uc.Evaluate(context.Background(), assessment, answers)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Code != "YOUNG_INFANT_HYPOTHERMIA" {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+	if assessment.Temperature == nil || *assessment.Temperature != 35.0 {
+		t.Errorf("measured temperature not recorded on the assessment")
+	}
+	if !assessment.IsCriticalIllness || !assessment.RequiresUrgentReferral || assessmentRepo.updates != 1 {
+		t.Errorf("emergency should flag and save the assessment, updates = %d", assessmentRepo.updates)
+	}
+	if len(notifier.sent) != 2 || notifier.sent[0].Phone != clinician.Phone || notifier.sent[1].Phone != supervisor.Phone {
+		t.Fatalf("notifications = %+v, want the clinician and their supervisor", notifier.sent)
+	}
+	if len(users.queries) != 1 || users.queries[0] != "Dangila HC/supervisor" {
+		t.Errorf("recipient queries = %v, want the supervisors of Dangila HC", users.queries)
+	}
+	if notifier.sent[0].Purpose != domain.PurposeClinicalAlert || !strings.Contains(notifier.sent[0].Text, "Baby Almaz") {
+		t.Errorf("unexpected notification: %+v", notifier.sent[0])
+	}
+
+	if _, err := uc.Evaluate(context.Background(), assessment, answers); err != nil {
+		t.Fatalf("second Evaluate: %v", err)
+	}
+	if len(notifier.sent) != 2 || assessmentRepo.updates != 1 {
+		t.Errorf("repeat evaluation notified again or saved an unchanged assessment")
+	}
+}
+
+func TestVitalAlertEvaluateWarning(t *testing.T) {
+	repo := &fakeVitalAlertRepo{alerts: map[string]*domain.VitalAlert{}}
+	assessmentRepo := &fakeVitalAssessmentRepo{}
+	notifier := &fakeOutbreakNotifier{}
+	uc := NewVitalAlertUsecase(repo, assessmentRepo, &fakeVitalClassificationRepo{}, &fakeVitalPatientRepo{}, &fakeVitalUserRepo{},
+		notifier, &fakePrescriptionAuditor{}, vitals.DefaultThresholds, time.Second)
+
+	hb := 9.0
+	assessment := &domain.Assessment{ID: uuid.New(), AssessmentType: domain.TypeChild, AgeMonths: 30, HbLevel: &hb}
+
+	alerts, err := uc.Evaluate(context.Background(), assessment, nil)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Severity != domain.VitalWarning {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+	if assessment.IsCriticalIllness || assessmentRepo.updates != 0 || len(notifier.sent) != 0 {
+		t.Errorf("a warning should neither flag the assessment nor notify")
+	}
+	if len(repo.alerts) != 1 {
+		t.Errorf("warning should still be stored")
+	}
+}
+
+func TestVitalAlertNotifiesOutsideTheRequest(t *testing.T) {
+	clinician := &domain.MedicalProfessional{ID: uuid.New(), Phone: "0911000001", Role: string(domain.NurseRole)}
+	repo := &fakeVitalAlertRepo{alerts: map[string]*domain.VitalAlert{}}
+	notifier := &fakeOutbreakNotifier{}
+	uc := NewVitalAlertUsecase(repo, &fakeVitalAssessmentRepo{}, &fakeVitalClassificationRepo{}, &fakeVitalPatientRepo{},
+		&fakeVitalUserRepo{professionals: []*domain.MedicalProfessional{clinician}},
+		notifier, &fakePrescriptionAuditor{}, vitals.DefaultThresholds, time.Second)
+	var pending []func()
+	uc.(*VitalAlertUsecase).dispatch = func(f func()) { pending = append(pending, f) }
+
+	spo2 := 85
+	assessment := &domain.Assessment{ID: uuid.New(), MedicalProfessionalID: clinician.ID, AssessmentType: domain.TypeChild, AgeMonths: 20, OxygenSaturation: &spo2}
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := uc.Evaluate(ctx, assessment, nil); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if _, err := uc.Evaluate(ctx, assessment, nil); err != nil {
+		t.Fatalf("second Evaluate: %v", err)
+	}
+	cancel()
+	if len(notifier.sent) != 0 || len(pending) != 1 {
+		t.Fatalf("expected one notification queued and none sent, got %d queued and %d sent", len(pending), len(notifier.sent))
+	}
+
+	// The request is over by the time the notification runs.
+	pending[0]()
+	if len(notifier.sent) != 1 || repo.alerts["HYPOXAEMIA"].NotifiedAt == nil {
+		t.Errorf("queued notification not sent after the request ended")
+	}
+}
+
+func TestVitalAlertCorrectedReadingClearsEmergency(t *testing.T) {
+	tests := []struct {
+		name            string
+		classifications []*domain.Classification
+		wantFlags       bool
+	}{
+		{"no severe classification", nil, false},
+		{"severe classification", []*domain.Classification{{Disease: "SEVERE PNEUMONIA OR VERY SEVERE DISEASE", IsCriticalIllness: true, RequiresUrgentReferral: true}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeVitalAlertRepo{alerts: map[string]*domain.VitalAlert{}}
+			assessmentRepo := &fakeVitalAssessmentRepo{}
+			auditor := &fakePrescriptionAuditor{}
+			uc := NewVitalAlertUsecase(repo, assessmentRepo, &fakeVitalClassificationRepo{classifications: tt.classifications},
+				&fakeVitalPatientRepo{}, &fakeVitalUserRepo{}, nil, auditor, vitals.DefaultThresholds, time.Second)
+
+			spo2 := 85
+			assessment := &domain.Assessment{ID: uuid.New(), AssessmentType: domain.TypeChild, AgeMonths: 20, OxygenSaturation: &spo2}
+			if _, err := uc.Evaluate(context.Background(), assessment, nil); err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if !assessment.IsCriticalIllness || repo.alerts["HYPOXAEMIA"] == nil {
+				t.Fatalf("hypoxaemia should flag the assessment and be stored")
+			}
+
+			spo2 = 96
+			alerts, err := uc.Evaluate(context.Background(), assessment, nil)
+			if err != nil {
+				t.Fatalf("second Evaluate: %v", err)
+			}
+			if len(alerts) != 0 || len(repo.alerts) != 0 {
+				t.Errorf("alerts = %+v, stored = %+v, want the hypoxaemia alert gone", alerts, repo.alerts)
+			}
+			if assessment.IsCriticalIllness != tt.wantFlags || assessment.RequiresUrgentReferral != tt.wantFlags {
+				t.Errorf("flags = %v/%v, want %v", assessment.IsCriticalIllness, assessment.RequiresUrgentReferral, tt.wantFlags)
+			}
+			// Each flag change is saved and audited.
+			wantUpdates := 2
+			if tt.wantFlags {
+				wantUpdates = 1
+			}
+			if assessmentRepo.updates != wantUpdates || len(auditor.actions) != wantUpdates {
+				t.Errorf("updates = %d, audited = %v, want %d", assessmentRepo.updates, auditor.actions, wantUpdates)
+			}
+		})
+	}
+}
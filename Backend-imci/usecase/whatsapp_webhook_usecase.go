@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/Afomiat/Digital-IMCI/internal/logger"
+	"github.com/Afomiat/Digital-IMCI/internal/userutil"
+)
+
+const (
+	whatsAppNotRegisteredText = "This number is not registered with Digital IMCI. Please sign up in the app first."
+	whatsAppHelpText          = "Send \"assess <patient name>\" to start an IMCI assessment, or \"cancel\" to stop one."
+	whatsAppUnavailableText   = "Decision support is not available right now. Please try again later."
+	whatsAppErrorText         = "Something went wrong. Please try again, or send \"cancel\"."
+)
+
+type WhatsAppWebhookUsecase struct {
+	statusRepo              domain.WhatsAppStatusRepository
+	medicalProfessionalRepo domain.MedicalProfessionalRepository
+	chat                    domain.ChatAssessmentUsecase
+	messenger               domain.ChatMessenger
+	senders                 *senderLocks
+	contextTimeout          time.Duration
+}
+
+// NewWhatsAppWebhookUsecase handles webhook deliveries. chat may be nil, in
+// which case incoming messages only get a notice.
+func NewWhatsAppWebhookUsecase(
+	statusRepo domain.WhatsAppStatusRepository,
+	medicalProfessionalRepo domain.MedicalProfessionalRepository,
+	chat domain.ChatAssessmentUsecase,
+	messenger domain.ChatMessenger,
+	timeout time.Duration,
+) domain.WhatsAppWebhookUsecase {
+	return &WhatsAppWebhookUsecase{
+		statusRepo:              statusRepo,
+		medicalProfessionalRepo: medicalProfessionalRepo,
+		chat:                    chat,
+		messenger:               messenger,
+		senders:                 &senderLocks{locks: map[string]*senderLock{}},
+		contextTimeout:          timeout,
+	}
+}
+
+// HandleEvent records status callbacks and answers incoming messages. A
+// message already handled is skipped, and messages from one sender are
+// handled one at a time so their chat steps cannot interleave. A failure on
+// one item does not stop the others; the first error is returned.
+func (uc *WhatsAppWebhookUsecase) HandleEvent(ctx context.Context, event *domain.WhatsAppWebhookEvent) error {
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for _, status := range event.Statuses {
+		keep(uc.recordStatus(ctx, status))
+	}
+	for _, msg := range event.Messages {
+		keep(uc.handleMessage(ctx, msg))
+	}
+
+	return firstErr
+}
+
+func (uc *WhatsAppWebhookUsecase) recordStatus(ctx context.Context, status *domain.WhatsAppStatusUpdate) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if status.Status == "failed" {
+		logger.Warn("whatsapp message delivery failed",
+			"message_id", status.MessageID,
+			"recipient", status.RecipientID,
+			"error_code", status.ErrorCode,
+			"error", status.ErrorTitle,
+		)
+	}
+
+	return uc.statusRepo.Record(ctx, status)
+}
+
+func (uc *WhatsAppWebhookUsecase) handleMessage(ctx context.Context, msg *domain.WhatsAppInboundMessage) error {
+	unlock := uc.senders.lock(msg.From)
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, uc.contextTimeout)
+	defer cancel()
+
+	if msg.ID != "" {
+		fresh, err := uc.statusRepo.ClaimInbound(ctx, msg)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return nil
+		}
+	}
+
+	text := msg.ReplyID
+	if text == "" {
+		text = msg.Text
+	}
+	if text == "" {
+		return uc.notify(ctx, msg.From, whatsAppHelpText)
+	}
+
+	mp, err := uc.medicalProfessionalRepo.GetByPhone(ctx, userutil.NormalizePhone(msg.From))
+	if err != nil || mp == nil {
+		return uc.notify(ctx, msg.From, whatsAppNotRegisteredText)
+	}
+
+	if uc.chat == nil {
+		return uc.notify(ctx, msg.From, whatsAppUnavailableText)
+	}
+
+	replies, err := uc.chat.HandleMessage(ctx, "whatsapp:"+msg.From, mp, text)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrChatSessionNotFound):
+		return uc.notify(ctx, msg.From, whatsAppHelpText)
+	case errors.Is(err, domain.ErrChatUnavailable):
+		return uc.notify(ctx, msg.From, whatsAppUnavailableText)
+	default:
+		logger.Error("whatsapp assessment chat failed", "from", msg.From, "error", err)
+		if notifyErr := uc.notify(ctx, msg.From, whatsAppErrorText); notifyErr != nil {
+			return notifyErr
+		}
+		return err
+	}
+
+	for _, reply := range replies {
+		if err := uc.messenger.SendChatReply(ctx, msg.From, reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *WhatsAppWebhookUsecase) notify(ctx context.Context, recipient, text string) error {
+	return uc.messenger.SendChatReply(ctx, recipient, &domain.ChatReply{Text: text})
+}
+
+// senderLocks serialises the handling of each sender's messages within this
+// process. A lock is dropped once nobody holds or waits for it.
+type senderLocks struct {
+	mu    sync.Mutex
+	locks map[string]*senderLock
+}
+
+type senderLock struct {
+	sync.Mutex
+	users int
+}
+
+func (s *senderLocks) lock(sender string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[sender]
+	if !ok {
+		l = &senderLock{}
+		s.locks[sender] = l
+	}
+	l.users++
+	s.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.mu.Lock()
+		l.users--
+		if l.users == 0 {
+			delete(s.locks, sender)
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Afomiat/Digital-IMCI/domain"
+	"github.com/google/uuid"
+)
+
+type fakeWhatsAppStatusRepo struct {
+	mu      sync.Mutex
+	claimed map[string]bool
+}
+
+func (f *fakeWhatsAppStatusRepo) Record(ctx context.Context, status *domain.WhatsAppStatusUpdate) error {
+	return nil
+}
+
+func (f *fakeWhatsAppStatusRepo) ClaimInbound(ctx context.Context, msg *domain.WhatsAppInboundMessage) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.claimed[msg.ID] {
+		return false, nil
+	}
+	f.claimed[msg.ID] = true
+	return true, nil
+}
+
+type fakeWhatsAppProfessionals struct {
+	domain.MedicalProfessionalRepository
+}
+
+func (fakeWhatsAppProfessionals) GetByPhone(ctx context.Context, phone string) (*domain.MedicalProfessional, error) {
+	return &domain.MedicalProfessional{ID: uuid.New(), Phone: phone}, nil
+}
+
+// fakeWhatsAppChat records how many messages of one conversation are being
+// handled at once.
+type fakeWhatsAppChat struct {
+	active  int32
+	overlap int32
+	handled int32
+}
+
+func (f *fakeWhatsAppChat) HandleMessage(ctx context.Context, conversationID string, mp *domain.MedicalProfessional, text string) ([]*domain.ChatReply, error) {
+	if atomic.AddInt32(&f.active, 1) > 1 {
+		atomic.StoreInt32(&f.overlap, 1)
+	}
+	time.Sleep(5 * time.Millisecond)
+	atomic.AddInt32(&f.active, -1)
+	atomic.AddInt32(&f.handled, 1)
+	return []*domain.ChatReply{{Text: "ok"}}, nil
+}
+
+type fakeWhatsAppMessenger struct {
+	sent int32
+}
+
+func (f *fakeWhatsAppMessenger) SendChatReply(ctx context.Context, recipient string, reply *domain.ChatReply) error {
+	atomic.AddInt32(&f.sent, 1)
+	return nil
+}
+
+func TestWhatsAppWebhookSkipsRedeliveredMessages(t *testing.T) {
+	chat := &fakeWhatsAppChat{}
+	messenger := &fakeWhatsAppMessenger{}
+	uc := NewWhatsAppWebhookUsecase(&fakeWhatsAppStatusRepo{claimed: map[string]bool{}}, fakeWhatsAppProfessionals{}, chat, messenger, time.Second)
+
+	event := &domain.WhatsAppWebhookEvent{Messages: []*domain.WhatsAppInboundMessage{
+		{ID: "wamid.1", From: "251911000000", Text: "assess Almaz"},
+	}}
+	for i := 0; i < 2; i++ {
+		if err := uc.HandleEvent(context.Background(), event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if chat.handled != 1 || messenger.sent != 1 {
+		t.Errorf("expected the message to be answered once, handled %d and sent %d", chat.handled, messenger.sent)
+	}
+}
+
+func TestWhatsAppWebhookSerialisesEachSender(t *testing.T) {
+	chat := &fakeWhatsAppChat{}
+	uc := NewWhatsAppWebhookUsecase(&fakeWhatsAppStatusRepo{claimed: map[string]bool{}}, fakeWhatsAppProfessionals{}, chat, &fakeWhatsAppMessenger{}, time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			event := &domain.WhatsAppWebhookEvent{Messages: []*domain.WhatsAppInboundMessage{
+				{ID: uuid.NewString(), From: "251911000000", Text: "1"},
+			}}
+			if err := uc.HandleEvent(context.Background(), event); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if chat.handled != 5 {
+		t.Errorf("expected 5 messages handled, got %d", chat.handled)
+	}
+	if chat.overlap != 0 {
+		t.Error("expected one sender's messages to be handled one at a time")
+	}
+}